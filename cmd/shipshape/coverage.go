@@ -0,0 +1,938 @@
+// Ship Shape - Coverage Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/chambridge/ship-shape/internal/coverage"
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/gitdiff"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/internal/runner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	coverageLocateJSON         bool
+	coverageMergeOutput        string
+	coverageMergeFormat        string
+	coverageMergeMode          string
+	coverageMergeDir           string
+	coverageDiffBase           string
+	coverageDiffDir            string
+	coverageDiffJSON           bool
+	coverageDiffFailUnder      float64
+	coverageCriticalDir        string
+	coverageCriticalJSON       bool
+	coverageCheckDir           string
+	coverageCheckJSON          bool
+	coverageCheckRatchet       bool
+	coverageCheckRatchetFile   string
+	coverageCheckUpdateRatchet bool
+	coverageRunWorkDir         string
+	coverageRunTimeout         time.Duration
+	coverageRunWorkspaces      []string
+	coverageRunOutput          string
+	coverageRunFormat          string
+	coverageRunJSON            bool
+)
+
+// coverageCmd groups commands that work with coverage reports
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Work with coverage reports",
+	Long: `Commands for finding, merging and converting the coverage reports of a
+repository, for measuring the coverage of a change and of critical code,
+and for checking coverage against targets.
+
+Supported formats: Go coverage profiles, Cobertura XML, LCOV,
+Istanbul JSON (coverage-final.json) and JaCoCo XML.`,
+}
+
+// coverageLocateCmd represents the coverage locate command
+var coverageLocateCmd = &cobra.Command{
+	Use:   "locate [directory]",
+	Short: "Find the coverage reports of a repository",
+	Long: `Finds coverage reports and detects their format from their content.
+
+Reports are the files matching coverage.paths in .shipshape.yml or, when
+it is empty, every report-like file of the repository, including the
+coverage/, htmlcov/, target/ and build/ directories. Each report is
+associated with the workspace containing it and marked stale when a source
+file it lists changed after the report was written.
+
+Example:
+  shipshape coverage locate
+  shipshape coverage locate /path/to/repo --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCoverageLocate,
+}
+
+// coverageMergeCmd represents the coverage merge command
+var coverageMergeCmd = &cobra.Command{
+	Use:   "merge [report...]",
+	Short: "Merge coverage reports and convert them to another format",
+	Long: `Merges coverage reports of any supported format into one report.
+
+Reports covering the same file are combined line by line. With --mode sum
+(the default, or coverage.merge-mode in .shipshape.yml) hits add up, as for
+test shards or separate unit and integration runs; with --mode max the
+highest count wins, as for reruns of the same tests. Without arguments, the
+reports found by "shipshape coverage locate" in --dir are merged.
+
+The merged report is written as JSON, or in the format given by --format
+or implied by the --output extension: lcov (.info, .lcov) or cobertura-xml
+(.xml). Passing a single report converts it.
+
+Example:
+  shipshape coverage merge -o merged.json
+  shipshape coverage merge shard-*/coverage.xml --mode max -o merged.json
+  shipshape coverage merge coverage.out -o lcov.info`,
+	RunE: runCoverageMerge,
+}
+
+// coverageDiffCmd represents the coverage diff command
+var coverageDiffCmd = &cobra.Command{
+	Use:   "diff [report...]",
+	Short: "Report the coverage of the lines a change adds or modifies",
+	Long: `Intersects coverage with the lines added or modified since the working
+tree diverged from a base revision, for reviewing pull requests.
+
+The change covers every commit since the merge base of --base and HEAD plus
+staged and unstaged changes to tracked files. Every line of untracked files
+that git does not ignore counts as added. The given reports, or every report
+found by "shipshape coverage locate", are merged and each changed line the
+reports list as executable counts towards the patch coverage.
+Uncovered lines are listed per file, and changed source files missing from
+every report are named.
+
+--fail-under (or coverage.patch-threshold in .shipshape.yml) exits with an
+error when the patch coverage is below the given percentage.
+
+Example:
+  shipshape coverage diff --base origin/main
+  shipshape coverage diff coverage.out --base main --fail-under 80
+  shipshape coverage diff --dir services/api --json`,
+	RunE: runCoverageDiff,
+}
+
+// coverageCriticalCmd represents the coverage critical command
+var coverageCriticalCmd = &cobra.Command{
+	Use:   "critical [report...]",
+	Short: "Report uncovered critical code",
+	Long: `Finds the critical code of a repository and reports the critical code
+that tests do not cover, apart from the overall coverage percentage.
+
+Code is critical when it matches coverage.critical-paths in .shipshape.yml:
+files matching a path glob, functions matching a name pattern, or functions
+following a "// shipshape:critical" comment. Heuristics also mark as
+critical error-handling blocks, the exported API and the code of
+authentication, security and payment packages.
+
+Each critical function, file or error-handling block with uncovered lines
+is a finding. With coverage.critical-paths-required (the default) findings
+are high severity and the command fails when there are any.
+
+Example:
+  shipshape coverage critical
+  shipshape coverage critical coverage.out --json`,
+	RunE: runCoverageCritical,
+}
+
+// coverageCheckCmd represents the coverage check command
+var coverageCheckCmd = &cobra.Command{
+	Use:   "check [report...]",
+	Short: "Check coverage against thresholds per workspace, package and file",
+	Long: `Compares the coverage of every workspace and package with the thresholds
+under coverage.thresholds in .shipshape.yml and names the ones below target
+and by how much.
+
+Thresholds:
+  • line, branch, function: targets of every workspace and package
+  • overrides: targets of the files matching a path glob, or of a workspace
+    given by name or path. Packages whose files all match a path override
+    take its targets, and matching files are also checked on their own.
+
+Ratchet: with --ratchet (or coverage.ratchet.enabled) packages may not fall
+below the coverage recorded in the ratchet file, less coverage.ratchet.tolerance
+points. --update-ratchet raises the recorded coverage of the packages that
+improved; run it where coverage is authoritative, such as the main branch.
+
+The command fails when a target is not met.
+
+Example:
+  shipshape coverage check
+  shipshape coverage check coverage.out --json
+  shipshape coverage check --ratchet --update-ratchet`,
+	RunE: runCoverageCheck,
+}
+
+// coverageRunCmd represents the coverage run command
+var coverageRunCmd = &cobra.Command{
+	Use:   "run [directory]",
+	Short: "Run the tests of each workspace with coverage",
+	Long: `Runs the tests of every workspace with coverage enabled, using the test
+framework detected in it, and merges the reports the runs write.
+
+Commands:
+  • Go: go test -coverprofile -json ./...
+  • Python: pytest --cov --junitxml (requires pytest-cov)
+  • JavaScript and TypeScript: npx jest --coverage --json
+  • Java (Maven): mvn test with the JaCoCo plugin's prepare-agent and report goals
+
+Each command runs in its workspace, bounded by --timeout (default:
+analysis.analyzer-timeout). Coverage reports, test results and console
+output are written to a directory per workspace of --work-dir (default: a
+new temporary directory). Workspaces without a supported framework are
+listed as skipped. The command fails when tests fail, time out or write no
+coverage report, after writing the merged report.
+
+Example:
+  shipshape coverage run
+  shipshape coverage run /path/to/repo --workspace web -o coverage.json
+  shipshape coverage run --timeout 10m --work-dir .shipshape-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runCoverageRun,
+}
+
+func init() {
+	rootCmd.AddCommand(coverageCmd)
+	coverageCmd.AddCommand(coverageLocateCmd)
+	coverageCmd.AddCommand(coverageMergeCmd)
+	coverageCmd.AddCommand(coverageDiffCmd)
+	coverageCmd.AddCommand(coverageCriticalCmd)
+	coverageCmd.AddCommand(coverageCheckCmd)
+	coverageCmd.AddCommand(coverageRunCmd)
+
+	coverageLocateCmd.Flags().BoolVar(&coverageLocateJSON, "json", false, "output in JSON format")
+
+	coverageMergeCmd.Flags().StringVarP(&coverageMergeOutput, "output", "o", "", "file to write the merged report to (default: stdout)")
+	coverageMergeCmd.Flags().StringVar(&coverageMergeFormat, "format", "",
+		"output format: json, lcov or cobertura-xml (default: from the output extension, else json)")
+	coverageMergeCmd.Flags().StringVar(&coverageMergeMode, "mode", "",
+		"how hits of the same line combine: sum or max (default: coverage.merge-mode)")
+	coverageMergeCmd.Flags().StringVar(&coverageMergeDir, "dir", ".", "repository the reports cover")
+
+	coverageDiffCmd.Flags().StringVar(&coverageDiffBase, "base", "origin/main", "revision the change is compared against")
+	coverageDiffCmd.Flags().StringVar(&coverageDiffDir, "dir", ".", "directory of the repository the reports cover")
+	coverageDiffCmd.Flags().BoolVar(&coverageDiffJSON, "json", false, "output in JSON format")
+	coverageDiffCmd.Flags().Float64Var(&coverageDiffFailUnder, "fail-under", 0,
+		"fail when the patch coverage percentage is below this value (default: coverage.patch-threshold)")
+
+	coverageCriticalCmd.Flags().StringVar(&coverageCriticalDir, "dir", ".", "repository the reports cover")
+	coverageCriticalCmd.Flags().BoolVar(&coverageCriticalJSON, "json", false, "output in JSON format")
+
+	coverageCheckCmd.Flags().StringVar(&coverageCheckDir, "dir", ".", "repository the reports cover")
+	coverageCheckCmd.Flags().BoolVar(&coverageCheckJSON, "json", false, "output in JSON format")
+	coverageCheckCmd.Flags().BoolVar(&coverageCheckRatchet, "ratchet", false,
+		"hold packages to their recorded coverage (default: coverage.ratchet.enabled)")
+	coverageCheckCmd.Flags().StringVar(&coverageCheckRatchetFile, "ratchet-file", "",
+		"file recording the coverage of packages (default: coverage.ratchet.file)")
+	coverageCheckCmd.Flags().BoolVar(&coverageCheckUpdateRatchet, "update-ratchet", false,
+		"raise the recorded coverage of packages that improved")
+
+	coverageRunCmd.Flags().StringVar(&coverageRunWorkDir, "work-dir", "", "directory to write outputs to (default: a new temporary directory)")
+	coverageRunCmd.Flags().DurationVar(&coverageRunTimeout, "timeout", 0, "time limit of each workspace (default: analysis.analyzer-timeout)")
+	coverageRunCmd.Flags().StringSliceVar(&coverageRunWorkspaces, "workspace", nil, "only test the workspaces with these names or paths")
+	coverageRunCmd.Flags().StringVarP(&coverageRunOutput, "output", "o", "", "file to write the merged report to")
+	coverageRunCmd.Flags().StringVar(&coverageRunFormat, "format", "",
+		"format of the merged report: json, lcov or cobertura-xml (default: from the output extension, else json)")
+	coverageRunCmd.Flags().BoolVar(&coverageRunJSON, "json", false, "output in JSON format")
+}
+
+func runCoverageLocate(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	locator, err := newCoverageLocator(dir)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Locating coverage reports", "directory", dir)
+
+	reports, err := locator.Locate()
+	if err != nil {
+		return fmt.Errorf("failed to locate coverage reports: %w", err)
+	}
+
+	logger.Debug("Coverage reports located", "reports", len(reports))
+
+	if coverageLocateJSON {
+		if reports == nil {
+			reports = []coverage.Located{}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(reports); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	writeCoverageLocateText(os.Stdout, reports)
+
+	return nil
+}
+
+func runCoverageMerge(_ *cobra.Command, args []string) error {
+	if _, err := os.Stat(coverageMergeDir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", coverageMergeDir)
+	}
+
+	format, err := coverageOutputFormat(coverageMergeFormat, coverageMergeOutput)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := coverage.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load coverage configuration: %w", err)
+	}
+
+	mode := cfg.MergeMode
+	if coverageMergeMode != "" {
+		if mode, err = coverage.ParseMergeMode(coverageMergeMode); err != nil {
+			return fmt.Errorf("invalid --mode: %w", err)
+		}
+	}
+
+	locator, err := newCoverageLocator(coverageMergeDir)
+	if err != nil {
+		return err
+	}
+
+	reports, err := parseCoverageReports(locator, args, coverageMergeDir)
+	if err != nil {
+		return err
+	}
+
+	merged := coverage.Merge(reports, mode)
+
+	logger.Info("Coverage reports merged", "reports", len(reports), "files", len(merged.Files), "mode", mode)
+
+	if coverageMergeOutput == "" {
+		return coverage.Write(os.Stdout, merged, format)
+	}
+
+	if err := writeCoverageReport(coverageMergeOutput, merged, format); err != nil {
+		return err
+	}
+
+	fmt.Printf("Merged %d reports covering %d files into %s (%s, %.1f%% lines)\n",
+		len(reports), len(merged.Files), coverageMergeOutput, format, merged.Metrics.Lines.Percent())
+
+	return nil
+}
+
+// coverageDiffResult is the JSON output of the coverage diff command.
+type coverageDiffResult struct {
+	*coverage.Patch
+
+	// Base is the revision the change is compared against
+	Base string `json:"base"`
+
+	// Threshold is the lowest acceptable patch coverage, or 0
+	Threshold float64 `json:"threshold"`
+
+	// Passed reports whether the patch coverage meets the threshold
+	Passed bool `json:"passed"`
+}
+
+func runCoverageDiff(cmd *cobra.Command, args []string) error {
+	if _, err := os.Stat(coverageDiffDir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", coverageDiffDir)
+	}
+
+	if _, ok := repositoryRoot(coverageDiffDir); !ok {
+		return fmt.Errorf("not a git repository: %s", coverageDiffDir)
+	}
+
+	cfg, err := coverage.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load coverage configuration: %w", err)
+	}
+
+	threshold := cfg.PatchThreshold
+	if cmd.Flags().Changed("fail-under") {
+		if coverageDiffFailUnder < 0 || coverageDiffFailUnder > 100 {
+			return fmt.Errorf("invalid --fail-under: %v is not between 0 and 100", coverageDiffFailUnder)
+		}
+
+		threshold = coverageDiffFailUnder
+	}
+
+	logger.Info("Computing patch coverage", "directory", coverageDiffDir, "base", coverageDiffBase)
+
+	changes, err := gitdiff.Diff(coverageDiffDir, coverageDiffBase)
+	if err != nil {
+		return err
+	}
+
+	logger.Debug("Changes read", "files", len(changes), "lines", changes.Lines())
+
+	locator, err := newCoverageLocator(coverageDiffDir)
+	if err != nil {
+		return err
+	}
+
+	reports, err := parseCoverageReports(locator, args, coverageDiffDir)
+	if err != nil {
+		return err
+	}
+
+	patch := coverage.NewPatch(coverage.Merge(reports, cfg.MergeMode), changes)
+	passed := threshold == 0 || patch.Percent >= threshold
+
+	if coverageDiffJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		result := coverageDiffResult{Patch: patch, Base: coverageDiffBase, Threshold: threshold, Passed: passed}
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		writeCoverageDiffText(os.Stdout, patch, coverageDiffBase)
+	}
+
+	if !passed {
+		return fmt.Errorf("patch coverage %.1f%% is below the threshold of %.1f%%", patch.Percent, threshold)
+	}
+
+	return nil
+}
+
+func writeCoverageDiffText(w io.Writer, patch *coverage.Patch, base string) {
+	if patch.Lines.Total == 0 {
+		fmt.Fprintf(w, "No executable lines changed since %s\n", base)
+	} else {
+		fmt.Fprintf(w, "Patch coverage: %.1f%% (%d of %d changed lines covered) since %s\n",
+			patch.Percent, patch.Lines.Covered, patch.Lines.Total, base)
+	}
+
+	if len(patch.Files) > 0 {
+		fmt.Fprintln(w)
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "FILE\tCOVERED\tUNCOVERED LINES")
+
+		for _, f := range patch.Files {
+			uncovered := "-"
+			if len(f.Uncovered) > 0 {
+				uncovered = coverage.LineRanges(f.Uncovered)
+			}
+
+			fmt.Fprintf(tw, "%s\t%d/%d\t%s\n", f.Path, f.Lines.Covered, f.Lines.Total, uncovered)
+		}
+
+		_ = tw.Flush()
+	}
+
+	if len(patch.Unreported) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Changed source files without coverage data:")
+
+		for _, f := range patch.Unreported {
+			fmt.Fprintf(w, "  %s\n", f)
+		}
+	}
+}
+
+func runCoverageCritical(_ *cobra.Command, args []string) error {
+	if _, err := os.Stat(coverageCriticalDir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", coverageCriticalDir)
+	}
+
+	cfg, err := coverage.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load coverage configuration: %w", err)
+	}
+
+	logger.Info("Finding uncovered critical code", "directory", coverageCriticalDir)
+
+	locator, err := newCoverageLocator(coverageCriticalDir)
+	if err != nil {
+		return err
+	}
+
+	reports, err := parseCoverageReports(locator, args, coverageCriticalDir)
+	if err != nil {
+		return err
+	}
+
+	critical := coverage.NewCriticalAnalyzer(coverageCriticalDir, cfg).Analyze(coverage.Merge(reports, cfg.MergeMode))
+
+	logger.Debug("Critical code analysis complete", "regions", len(critical.Regions), "findings", len(critical.Findings))
+
+	if coverageCriticalJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(critical); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		writeCoverageCriticalText(os.Stdout, critical)
+	}
+
+	if cfg.CriticalPathsRequired && len(critical.Findings) > 0 {
+		return fmt.Errorf("%d critical regions are not fully covered", len(critical.Findings))
+	}
+
+	return nil
+}
+
+func writeCoverageCriticalText(w io.Writer, critical *coverage.CriticalReport) {
+	if len(critical.Regions) == 0 {
+		fmt.Fprintln(w, "No critical code found")
+		return
+	}
+
+	fmt.Fprintf(w, "Critical code coverage: %.1f%% (%d of %d lines in %d critical regions)\n",
+		critical.Percent, critical.Lines.Covered, critical.Lines.Total, len(critical.Regions))
+
+	if len(critical.Findings) == 0 {
+		fmt.Fprintln(w, "\nNo uncovered critical code found")
+		return
+	}
+
+	fmt.Fprintln(w, "\nFindings:")
+
+	for _, f := range critical.Findings {
+		fmt.Fprintf(w, "%s [%s] %s\n", f.Location, f.Severity, f.Title)
+		fmt.Fprintf(w, "  %s\n", f.Description)
+	}
+}
+
+func runCoverageCheck(_ *cobra.Command, args []string) error {
+	if _, err := os.Stat(coverageCheckDir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", coverageCheckDir)
+	}
+
+	cfg, err := coverage.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load coverage configuration: %w", err)
+	}
+
+	ratchetPath := coverageCheckRatchetFile
+	if ratchetPath == "" {
+		ratchetPath = cfg.Ratchet.File
+	}
+
+	logger.Info("Checking coverage thresholds", "directory", coverageCheckDir)
+
+	locator, err := newCoverageLocator(coverageCheckDir)
+	if err != nil {
+		return err
+	}
+
+	reports, err := parseCoverageReports(locator, args, coverageCheckDir)
+	if err != nil {
+		return err
+	}
+
+	merged := coverage.Merge(reports, cfg.MergeMode)
+
+	var ratchet *coverage.Ratchet
+
+	if cfg.Ratchet.Enabled || coverageCheckRatchet || coverageCheckUpdateRatchet {
+		ratchet, err = coverage.LoadRatchet(ratchetPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			logger.Info("No coverage recorded yet", "ratchet", ratchetPath)
+
+			ratchet, err = coverage.NewRatchet(), nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	result := coverage.NewThresholdEvaluator(cfg, locator.Workspaces(), ratchet).Evaluate(merged)
+
+	logger.Debug("Threshold check complete", "results", len(result.Results), "findings", len(result.Findings))
+
+	if coverageCheckJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		writeCoverageCheckText(os.Stdout, result)
+	}
+
+	if coverageCheckUpdateRatchet {
+		changed := ratchet.Update(merged)
+		if err := ratchet.Save(ratchetPath); err != nil {
+			return err
+		}
+
+		logger.Info("Coverage ratchet updated", "file", ratchetPath, "packages", changed)
+
+		if !coverageCheckJSON {
+			fmt.Printf("Recorded the coverage of %d packages in %s\n", changed, ratchetPath)
+		}
+	}
+
+	if failed := result.Failed(); failed > 0 {
+		return fmt.Errorf("%d coverage targets not met", failed)
+	}
+
+	return nil
+}
+
+func writeCoverageCheckText(w io.Writer, result *coverage.ThresholdReport) {
+	if len(result.Results) == 0 {
+		fmt.Fprintln(w, "No coverage targets to check")
+		return
+	}
+
+	if len(result.Findings) == 0 {
+		fmt.Fprintf(w, "All %d coverage targets met\n", len(result.Results))
+		return
+	}
+
+	fmt.Fprintf(w, "%d of %d coverage targets not met\n", len(result.Findings), len(result.Results))
+	fmt.Fprintln(w, "\nFindings:")
+
+	for _, f := range result.Findings {
+		fmt.Fprintf(w, "%s [%s] %s\n", f.Location, f.Severity, f.Title)
+		fmt.Fprintf(w, "  %s\n", f.Description)
+	}
+}
+
+// coverageRunResult is the JSON output of the coverage run command.
+type coverageRunResult struct {
+	// WorkDir is the directory of the outputs
+	WorkDir string `json:"work_dir"`
+
+	// Runs are the outcomes of the test commands
+	Runs []runner.Result `json:"runs"`
+
+	// Skipped are the workspaces without a supported test framework
+	Skipped []runner.Skipped `json:"skipped"`
+
+	// Metrics are the counters of the merged report
+	Metrics coverage.Metrics `json:"metrics"`
+}
+
+//nolint:gocognit,gocyclo // Sequential steps of a run: plan, run, parse, merge and report
+func runCoverageRun(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	format, err := coverageOutputFormat(coverageRunFormat, coverageRunOutput)
+	if err != nil {
+		return err
+	}
+
+	coverageCfg, err := coverage.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load coverage configuration: %w", err)
+	}
+
+	cfg, err := runner.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load analysis configuration: %w", err)
+	}
+
+	if cmd.Flags().Changed("timeout") {
+		if coverageRunTimeout <= 0 {
+			return fmt.Errorf("invalid --timeout: %s is not positive", coverageRunTimeout)
+		}
+
+		cfg.Timeout = coverageRunTimeout
+	}
+
+	workspaces, err := discovery.NewWorkspaceDetector(dir, discovery.NewWalker(dir)).Detect()
+	if err != nil {
+		return fmt.Errorf("failed to detect workspaces: %w", err)
+	}
+
+	selected := workspaces
+	if len(coverageRunWorkspaces) > 0 {
+		selected = nil
+
+		for _, ws := range workspaces {
+			if slices.Contains(coverageRunWorkspaces, ws.Name) || slices.Contains(coverageRunWorkspaces, ws.Path) {
+				selected = append(selected, ws)
+			}
+		}
+	}
+
+	workDir := coverageRunWorkDir
+	if workDir == "" {
+		if workDir, err = os.MkdirTemp("", "shipshape-run-"); err != nil {
+			return fmt.Errorf("failed to create work directory: %w", err)
+		}
+	}
+
+	testRunner, err := runner.NewRunner(dir, workDir, cfg)
+	if err != nil {
+		return err
+	}
+
+	commands, skipped, err := testRunner.Plan(selected)
+	if err != nil {
+		return err
+	}
+
+	if len(commands) == 0 {
+		if coverageRunWorkDir == "" {
+			_ = os.Remove(workDir)
+		}
+
+		return fmt.Errorf("no workspace with a supported test framework in %s", dir)
+	}
+
+	result := coverageRunResult{WorkDir: workDir, Runs: make([]runner.Result, 0, len(commands)), Skipped: skipped}
+	reports := make([]*coverage.Report, 0, len(commands))
+	failed := 0
+
+	for _, c := range commands {
+		run := testRunner.Run(cmd.Context(), c)
+
+		if run.Reported {
+			report, err := testRunner.Parse(run, workspaces)
+			if err != nil {
+				logger.Warn("Skipping coverage report", "path", run.Coverage, "error", err)
+				run.Error = err.Error()
+			} else {
+				reports = append(reports, report)
+			}
+		}
+
+		if !run.Passed() {
+			failed++
+		}
+
+		result.Runs = append(result.Runs, run)
+	}
+
+	merged := coverage.Merge(reports, coverageCfg.MergeMode)
+	result.Metrics = merged.Metrics
+
+	if coverageRunOutput != "" && len(reports) > 0 {
+		if err := writeCoverageReport(coverageRunOutput, merged, format); err != nil {
+			return err
+		}
+	}
+
+	if coverageRunJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		writeCoverageRunText(os.Stdout, result, merged)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("tests failed or wrote no coverage report in %d of %d workspaces", failed, len(commands))
+	}
+
+	return nil
+}
+
+func writeCoverageRunText(w io.Writer, result coverageRunResult, merged *coverage.Report) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "WORKSPACE\tFRAMEWORK\tRESULT\tDURATION")
+
+	for _, run := range result.Runs {
+		status := "passed"
+
+		switch {
+		case run.TimedOut:
+			status = "timed out"
+		case run.ExitCode > 0:
+			status = fmt.Sprintf("failed (exit %d)", run.ExitCode)
+		case run.Error != "":
+			status = "error: " + run.Error
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", run.Workspace.Path, run.Framework, status, run.Duration.Round(time.Millisecond))
+	}
+
+	_ = tw.Flush()
+
+	if len(result.Skipped) > 0 {
+		fmt.Fprintln(w, "\nSkipped workspaces:")
+
+		for _, s := range result.Skipped {
+			fmt.Fprintf(w, "  %s: %s\n", s.Workspace.Path, s.Reason)
+		}
+	}
+
+	fmt.Fprintf(w, "\nCoverage: %.1f%% lines (%d of %d) in %d files\n",
+		merged.Metrics.Lines.Percent(), merged.Metrics.Lines.Covered, merged.Metrics.Lines.Total, len(merged.Files))
+
+	if coverageRunOutput != "" && len(merged.Files) > 0 {
+		fmt.Fprintf(w, "Merged report written to %s\n", coverageRunOutput)
+	}
+
+	fmt.Fprintf(w, "Outputs written to %s\n", result.WorkDir)
+}
+
+// writeCoverageReport writes a report to a file in the given format.
+func writeCoverageReport(path string, r *coverage.Report, format coverage.Format) error {
+	var buf bytes.Buffer
+	if err := coverage.Write(&buf, r, format); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("failed to write merged report: %w", err)
+	}
+
+	return nil
+}
+
+// parseCoverageReports parses the reports at the given paths or, when there
+// are none, every report located in dir.
+func parseCoverageReports(locator *coverage.Locator, paths []string, dir string) ([]*coverage.Report, error) {
+	var (
+		located []coverage.Located
+		err     error
+	)
+
+	if len(paths) == 0 {
+		if located, err = locator.Locate(); err != nil {
+			return nil, fmt.Errorf("failed to locate coverage reports: %w", err)
+		}
+	}
+
+	for _, p := range paths {
+		r, err := locator.Describe(p)
+		if err != nil {
+			return nil, err
+		}
+
+		located = append(located, r)
+	}
+
+	if len(located) == 0 {
+		return nil, fmt.Errorf("no coverage reports found in %s", dir)
+	}
+
+	reports := make([]*coverage.Report, 0, len(located))
+
+	for _, r := range located {
+		logger.Debug("Parsing coverage report", "path", r.Path, "format", r.Format)
+
+		report, err := locator.Parse(r)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// coverageOutputFormat returns the format a merged report is written in:
+// the named format, or the one implied by the output file extension.
+func coverageOutputFormat(name, output string) (coverage.Format, error) {
+	switch strings.ToLower(name) {
+	case "":
+	case "json":
+		return coverage.FormatShipShape, nil
+	default:
+		for _, f := range coverage.OutputFormats {
+			if coverage.Format(strings.ToLower(name)) == f {
+				return f, nil
+			}
+		}
+
+		return "", fmt.Errorf("unsupported output format %q (expected json, lcov or cobertura-xml)", name)
+	}
+
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".info", ".lcov":
+		return coverage.FormatLCOV, nil
+	case ".xml":
+		return coverage.FormatCobertura, nil
+	default:
+		return coverage.FormatShipShape, nil
+	}
+}
+
+// newCoverageLocator creates a locator for a repository from the coverage
+// configuration and its workspaces.
+func newCoverageLocator(dir string) (*coverage.Locator, error) {
+	cfg, err := coverage.LoadConfig(viper.GetViper())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load coverage configuration: %w", err)
+	}
+
+	workspaces, err := discovery.NewWorkspaceDetector(dir, discovery.NewWalker(dir)).Detect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect workspaces: %w", err)
+	}
+
+	return coverage.NewLocator(dir, cfg, workspaces), nil
+}
+
+func writeCoverageLocateText(w io.Writer, reports []coverage.Located) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No coverage reports found")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "REPORT\tFORMAT\tWORKSPACE\tMODIFIED\tSTATUS")
+
+	for _, r := range reports {
+		workspace := r.WorkspacePath
+		if workspace == "" {
+			workspace = "-"
+		}
+
+		status := "✓"
+		if r.Stale {
+			status = "stale (" + r.NewestSource + " is newer)"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.Path, r.Format, workspace, r.ModTime.Format("2006-01-02 15:04"), status)
+	}
+
+	_ = tw.Flush()
+}
@@ -0,0 +1,709 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chambridge/ship-shape/internal/coverage"
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func TestCoverageLocateCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "locate [directory]",
+			Args: cobra.MaximumNArgs(1),
+			RunE: runCoverageLocate,
+		}
+		cmd.Flags().BoolVar(&coverageLocateJSON, "json", false, "output in JSON format")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	// writeRepo creates a Go module whose profile predates its source.
+	writeRepo := func(t *testing.T) string {
+		t.Helper()
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+		testutil.WriteFile(t, dir, "calc.go", "package app\n\nfunc One() int {\n\treturn 1\n}\n")
+		testutil.WriteFile(t, dir, "coverage.out", "mode: count\nexample.com/app/calc.go:3.16,5.2 1 2\n")
+
+		past := time.Now().Add(-time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, "coverage.out"), past, past); err != nil {
+			t.Fatal(err)
+		}
+
+		return dir
+	}
+
+	t.Run("lists reports as text", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{writeRepo(t)})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage locate failed: %v", err)
+			}
+		})
+
+		for _, want := range []string{"REPORT", "coverage.out", "go-cover", "stale (calc.go is newer)"} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("lists reports as JSON", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{writeRepo(t), "--json"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage locate failed: %v", err)
+			}
+		})
+
+		var reports []coverage.Located
+		if err := json.Unmarshal([]byte(stdout), &reports); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+
+		if len(reports) != 1 || reports[0].Format != coverage.FormatGoCover || !reports[0].Stale {
+			t.Errorf("reports = %+v, want one stale go-cover report", reports)
+		}
+	})
+
+	t.Run("reports when nothing is found", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "main.go", "package main\n")
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{dir})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage locate failed: %v", err)
+			}
+		})
+
+		if !contains(stdout, "No coverage reports found") {
+			t.Errorf("output = %q, want empty message", stdout)
+		}
+	})
+
+	t.Run("fails on missing directory", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"/nonexistent/path"})
+
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected error for missing directory")
+		}
+	})
+}
+
+func TestCoverageMergeCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "merge [report...]",
+			RunE: runCoverageMerge,
+		}
+		cmd.Flags().StringVarP(&coverageMergeOutput, "output", "o", "", "output file")
+		cmd.Flags().StringVar(&coverageMergeFormat, "format", "", "output format")
+		cmd.Flags().StringVar(&coverageMergeMode, "mode", "", "merge mode")
+		cmd.Flags().StringVar(&coverageMergeDir, "dir", ".", "repository")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	// writeShards creates a repository with the LCOV tracefiles of two test
+	// shards covering the same file.
+	writeShards := func(t *testing.T) string {
+		t.Helper()
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "package.json", `{"name": "shop"}`)
+		testutil.WriteFile(t, dir, "src/cart.js", "module.exports = 1;\n")
+		testutil.WriteFile(t, dir, "shard-1/lcov.info", "SF:src/cart.js\nDA:1,1\nDA:2,0\nend_of_record\n")
+		testutil.WriteFile(t, dir, "shard-2/lcov.info", "SF:src/cart.js\nDA:1,2\nDA:2,3\nend_of_record\n")
+
+		return dir
+	}
+
+	t.Run("merges located reports into JSON", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeShards(t)
+		output := filepath.Join(dir, "merged.json")
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", dir, "-o", output})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage merge failed: %v", err)
+			}
+		})
+
+		if !contains(stdout, "Merged 2 reports") {
+			t.Errorf("output = %q, want summary", stdout)
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var report coverage.Report
+		if err := json.Unmarshal(data, &report); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		if len(report.Files) != 1 || report.Files[0].Lines[0].Hits != 3 || report.Metrics.Lines.Covered != 2 {
+			t.Errorf("report = %+v, want summed hits of src/cart.js", report)
+		}
+	})
+
+	t.Run("converts to LCOV with max mode", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeShards(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{
+			"--dir", dir, "--mode", "max", "--format", "lcov",
+			filepath.Join(dir, "shard-1", "lcov.info"), filepath.Join(dir, "shard-2", "lcov.info"),
+		})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage merge failed: %v", err)
+			}
+		})
+
+		for _, want := range []string{"SF:src/cart.js", "DA:1,2", "DA:2,3", "LH:2"} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("infers the format from the output extension", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeShards(t)
+		output := filepath.Join(dir, "merged.xml")
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", dir, "-o", output})
+
+		testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage merge failed: %v", err)
+			}
+		})
+
+		data, err := os.ReadFile(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !contains(string(data), `<coverage line-rate="1"`) {
+			t.Errorf("output is not a Cobertura report:\n%s", data)
+		}
+	})
+
+	t.Run("rejects unknown modes, formats and reports", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--mode", "average"},
+			{"--format", "clover"},
+			{"missing.info"},
+		} {
+			resetRootCmd(t)
+
+			cmd := newCmd()
+			cmd.SetArgs(append([]string{"--dir", writeShards(t)}, args...))
+
+			if err := cmd.Execute(); err == nil {
+				t.Errorf("coverage merge %v succeeded, want error", args)
+			}
+		}
+	})
+
+	t.Run("fails without reports", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "main.go", "package main\n")
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", dir})
+
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected error without reports")
+		}
+	})
+}
+
+func TestCoverageDiffCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "diff [report...]",
+			RunE: runCoverageDiff,
+		}
+		cmd.Flags().StringVar(&coverageDiffBase, "base", "origin/main", "base revision")
+		cmd.Flags().StringVar(&coverageDiffDir, "dir", ".", "repository")
+		cmd.Flags().BoolVar(&coverageDiffJSON, "json", false, "output in JSON format")
+		cmd.Flags().Float64Var(&coverageDiffFailUnder, "fail-under", 0, "threshold")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	// writeChange creates a repository whose feature branch adds a function
+	// that its tests cover in part, and an untested file.
+	writeChange := func(t *testing.T) string {
+		t.Helper()
+
+		dir := testutil.TempDir(t)
+
+		git := func(args ...string) {
+			t.Helper()
+
+			cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v\n%s", args, err, out)
+			}
+		}
+
+		testutil.WriteFile(t, dir, "go.mod", "module example.com/shop\n\ngo 1.21\n")
+		testutil.WriteFile(t, dir, "cart.go", "package shop\n\nfunc Total() int {\n\treturn 1\n}\n")
+		git("init", "-q", "-b", "main")
+		git("add", "-A")
+		git("commit", "-qm", "init")
+		git("checkout", "-qb", "feature")
+
+		testutil.WriteFile(t, dir, "cart.go", `package shop
+
+func Total() int {
+	return 1
+}
+
+func Abs(x int) int {
+	if x > 0 {
+		return x
+	}
+
+	return -x
+}
+`)
+		testutil.WriteFile(t, dir, "tax.go", "package shop\n\nfunc Tax() int { return 0 }\n")
+		git("add", "-A")
+		git("commit", "-qm", "add Abs")
+
+		testutil.WriteFile(t, dir, "coverage.out", `mode: set
+example.com/shop/cart.go:3.18,5.2 1 1
+example.com/shop/cart.go:7.21,8.11 1 1
+example.com/shop/cart.go:8.11,10.3 1 1
+example.com/shop/cart.go:12.2,12.11 1 0
+`)
+
+		return dir
+	}
+
+	t.Run("reports patch coverage as text", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", writeChange(t), "--base", "main"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage diff failed: %v", err)
+			}
+		})
+
+		for _, want := range []string{"Patch coverage: 80.0% (4 of 5 changed lines covered) since main", "cart.go", "4/5", "12", "tax.go"} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("fails under the threshold with JSON output", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", writeChange(t), "--base", "main", "--json", "--fail-under", "90"})
+
+		var err error
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			err = cmd.Execute()
+		})
+
+		if err == nil || !contains(err.Error(), "below the threshold of 90.0%") {
+			t.Errorf("error = %v, want threshold failure", err)
+		}
+
+		var result struct {
+			Percent    float64              `json:"percent"`
+			Files      []coverage.PatchFile `json:"files"`
+			Unreported []string             `json:"unreported"`
+			Base       string               `json:"base"`
+			Passed     bool                 `json:"passed"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+
+		if result.Percent != 80 || result.Base != "main" || result.Passed || len(result.Files) != 1 ||
+			len(result.Unreported) != 1 || result.Files[0].Uncovered[0] != 12 {
+			t.Errorf("result = %+v", result)
+		}
+	})
+
+	t.Run("fails outside a git repository", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", testutil.TempDir(t)})
+
+		if err := cmd.Execute(); err == nil || !contains(err.Error(), "not a git repository") {
+			t.Errorf("error = %v, want not a git repository", err)
+		}
+	})
+}
+
+func TestCoverageCriticalCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "critical [report...]",
+			RunE: runCoverageCritical,
+		}
+		cmd.Flags().StringVar(&coverageCriticalDir, "dir", ".", "repository")
+		cmd.Flags().BoolVar(&coverageCriticalJSON, "json", false, "output in JSON format")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	// writeRepo creates a Go module with an exported function and a billing
+	// package, and a profile covering the billing package or not.
+	writeRepo := func(t *testing.T, chargeHits int) string {
+		t.Helper()
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+		testutil.WriteFile(t, dir, "calc.go", "package app\n\nfunc One() int {\n\treturn 1\n}\n")
+		testutil.WriteFile(t, dir, "internal/billing/charge.go", "package billing\n\nfunc charge(amount int) error {\n\treturn nil\n}\n")
+		testutil.WriteFile(t, dir, "coverage.out", fmt.Sprintf("mode: set\n"+
+			"example.com/app/calc.go:3.16,5.2 1 1\n"+
+			"example.com/app/internal/billing/charge.go:3.32,5.2 1 %d\n", chargeHits))
+
+		return dir
+	}
+
+	t.Run("reports uncovered critical code", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeRepo(t, 0)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", dir})
+
+		var err error
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			err = cmd.Execute()
+		})
+
+		if err == nil || !contains(err.Error(), "1 critical regions are not fully covered") {
+			t.Errorf("error = %v, want uncovered critical code error", err)
+		}
+
+		for _, want := range []string{
+			"Critical code coverage: 50.0% (3 of 6 lines in 2 critical regions)",
+			"internal/billing/charge.go:3-5 [high] Uncovered Critical Code",
+			"Critical function charge (sensitive package billing) never runs in tests",
+		} {
+			if !contains(stdout, want) {
+				t.Errorf("output = %q, want %q", stdout, want)
+			}
+		}
+	})
+
+	t.Run("outputs JSON", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeRepo(t, 1)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", dir, "--json", filepath.Join(dir, "coverage.out")})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage critical failed: %v", err)
+			}
+		})
+
+		var report coverage.CriticalReport
+		if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		if len(report.Regions) != 2 || report.Percent != 100 || len(report.Findings) != 0 {
+			t.Errorf("report = %+v, want two covered critical regions", report)
+		}
+	})
+
+	t.Run("nonexistent directory", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"--dir", "/nonexistent/path"})
+
+		if err := cmd.Execute(); err == nil || !contains(err.Error(), "directory does not exist") {
+			t.Errorf("error = %v, want directory error", err)
+		}
+	})
+}
+
+func TestCoverageCheckCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "check [report...]",
+			RunE: runCoverageCheck,
+		}
+		cmd.Flags().StringVar(&coverageCheckDir, "dir", ".", "repository")
+		cmd.Flags().BoolVar(&coverageCheckJSON, "json", false, "output in JSON format")
+		cmd.Flags().BoolVar(&coverageCheckRatchet, "ratchet", false, "use the ratchet")
+		cmd.Flags().StringVar(&coverageCheckRatchetFile, "ratchet-file", "", "ratchet file")
+		cmd.Flags().BoolVar(&coverageCheckUpdateRatchet, "update-ratchet", false, "update the ratchet")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	// writeRepo creates a Go module with a covered package and a package
+	// with the given hits of its two statements.
+	writeRepo := func(t *testing.T, first, second int) string {
+		t.Helper()
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+		testutil.WriteFile(t, dir, "calc.go", "package app\n\nfunc One() int {\n\treturn 1\n}\n")
+		testutil.WriteFile(t, dir, "tax/tax.go", "package tax\n\nfunc Rate() int {\n\treturn 1\n}\n\nfunc Zero() int {\n\treturn 0\n}\n")
+		testutil.WriteFile(t, dir, "coverage.out", fmt.Sprintf("mode: set\n"+
+			"example.com/app/calc.go:3.16,5.2 1 1\n"+
+			"example.com/app/tax/tax.go:3.17,5.2 1 %d\n"+
+			"example.com/app/tax/tax.go:7.17,9.2 1 %d\n", first, second))
+
+		return dir
+	}
+
+	run := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+
+		cmd := newCmd()
+		cmd.SetArgs(args)
+
+		var err error
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			err = cmd.Execute()
+		})
+
+		return stdout, err
+	}
+
+	t.Run("reports packages below the thresholds", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeRepo(t, 1, 0)
+
+		stdout, err := run(t, "--dir", dir)
+		if err == nil || !contains(err.Error(), "coverage targets not met") {
+			t.Errorf("error = %v, want targets not met", err)
+		}
+
+		for _, want := range []string{
+			"Findings:",
+			"[medium] Coverage Below Threshold",
+			"Package example.com/app/tax has 50.0% line coverage (3 of 6 lines), 30.0 points below the target of 80.0%",
+		} {
+			if !contains(stdout, want) {
+				t.Errorf("output = %q, want %q", stdout, want)
+			}
+		}
+	})
+
+	t.Run("ratchet holds recorded coverage", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeRepo(t, 1, 1)
+		ratchetFile := filepath.Join(dir, "ratchet.json")
+
+		stdout, err := run(t, "--dir", dir, "--ratchet-file", ratchetFile, "--update-ratchet")
+		if err != nil {
+			t.Fatalf("coverage check failed: %v", err)
+		}
+
+		if !contains(stdout, "coverage targets met") || !contains(stdout, "Recorded the coverage of 2 packages") {
+			t.Errorf("output = %q, want all targets met and the ratchet recorded", stdout)
+		}
+
+		testutil.WriteFile(t, dir, "coverage.out", "mode: set\n"+
+			"example.com/app/calc.go:3.16,5.2 1 1\n"+
+			"example.com/app/tax/tax.go:3.17,5.2 1 1\n"+
+			"example.com/app/tax/tax.go:7.17,9.2 1 0\n")
+
+		resetRootCmd(t)
+
+		stdout, err = run(t, "--dir", dir, "--ratchet-file", ratchetFile, "--ratchet", "--json")
+		if err == nil {
+			t.Fatal("coverage check succeeded, want the ratchet to fail")
+		}
+
+		var result coverage.ThresholdReport
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("invalid JSON: %v", err)
+		}
+
+		var ratchet []string
+		for _, f := range result.Findings {
+			if f.CheckID == coverage.CheckCoverageRatchet {
+				ratchet = append(ratchet, f.Description)
+			}
+		}
+
+		if len(ratchet) != 2 || !contains(ratchet[0], "Package example.com/app/tax line coverage fell to 50.00% from its recorded 100.00%") {
+			t.Errorf("ratchet findings = %q, want the tax lines and functions", ratchet)
+		}
+	})
+
+	t.Run("nonexistent directory", func(t *testing.T) {
+		resetRootCmd(t)
+
+		if _, err := run(t, "--dir", "/nonexistent/path"); err == nil || !contains(err.Error(), "directory does not exist") {
+			t.Errorf("error = %v, want directory error", err)
+		}
+	})
+}
+
+func TestCoverageRunCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "run [directory]",
+			Args: cobra.MaximumNArgs(1),
+			RunE: runCoverageRun,
+		}
+		cmd.Flags().StringVar(&coverageRunWorkDir, "work-dir", "", "work directory")
+		cmd.Flags().DurationVar(&coverageRunTimeout, "timeout", 0, "timeout")
+		cmd.Flags().StringSliceVar(&coverageRunWorkspaces, "workspace", nil, "workspaces")
+		cmd.Flags().StringVarP(&coverageRunOutput, "output", "o", "", "output file")
+		cmd.Flags().StringVar(&coverageRunFormat, "format", "", "output format")
+		cmd.Flags().BoolVar(&coverageRunJSON, "json", false, "output in JSON format")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	t.Run("runs go tests and merges coverage", func(t *testing.T) {
+		if _, err := exec.LookPath("go"); err != nil {
+			t.Skip("go is not installed")
+		}
+
+		resetRootCmd(t)
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+		testutil.WriteFile(t, dir, "calc.go", "package app\n\nfunc One() int {\n\treturn 1\n}\n")
+		testutil.WriteFile(t, dir, "calc_test.go", "package app\n\nimport \"testing\"\n\nfunc TestOne(t *testing.T) {\n\tOne()\n}\n")
+		testutil.WriteFile(t, dir, "web/package.json", `{"name": "web"}`)
+
+		workDir := filepath.Join(dir, "out")
+		output := filepath.Join(dir, "lcov.info")
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{dir, "--work-dir", workDir, "-o", output})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("coverage run failed: %v", err)
+			}
+		})
+
+		for _, want := range []string{"go test", "passed", "web: Jest not detected", "Coverage: 100.0% lines (2 of 2) in 1 files"} {
+			if !contains(stdout, want) {
+				t.Errorf("output = %q, want %q", stdout, want)
+			}
+		}
+
+		data, err := os.ReadFile(output)
+		if err != nil || !contains(string(data), "SF:calc.go") {
+			t.Errorf("merged report = %q, %v, want LCOV of calc.go", data, err)
+		}
+
+		if _, err := os.Stat(filepath.Join(workDir, "root", "test.json")); err != nil {
+			t.Errorf("test output not captured: %v", err)
+		}
+	})
+
+	t.Run("no supported workspace", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "web/package.json", `{"name": "web"}`)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{dir})
+
+		if err := cmd.Execute(); err == nil || !contains(err.Error(), "no workspace with a supported test framework") {
+			t.Errorf("error = %v, want no supported workspace", err)
+		}
+	})
+
+	t.Run("nonexistent directory", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{"/nonexistent/path"})
+
+		if err := cmd.Execute(); err == nil || !contains(err.Error(), "directory does not exist") {
+			t.Errorf("error = %v, want directory error", err)
+		}
+	})
+}
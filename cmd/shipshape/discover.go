@@ -94,13 +94,25 @@ func runDiscover(_ *cobra.Command, args []string) error {
 
 	logger.Debug("Frameworks detected", "count", len(frameworks))
 
+	// Analyze test organization
+	logger.Debug("Analyzing test organization...")
+	organizationAnalyzer := discovery.NewOrganizationAnalyzer(walker)
+
+	organization, err := organizationAnalyzer.Analyze()
+	if err != nil {
+		return fmt.Errorf("failed to analyze test organization: %w", err)
+	}
+
+	logger.Debug("Test organization analyzed", "test_files", organization.TestFiles, "ratio", organization.Ratio)
+
 	// Build repository context
 	repo := types.Repository{
-		Path:          dir,
-		Languages:     languages,
-		Frameworks:    frameworks,
-		TotalFiles:    totalFiles,
-		ExcludedPaths: walker.ExcludePatterns,
+		Path:             dir,
+		Languages:        languages,
+		Frameworks:       frameworks,
+		TotalFiles:       totalFiles,
+		ExcludedPaths:    walker.ExcludePatterns,
+		TestOrganization: organization,
 	}
 
 	// Output results
@@ -210,5 +222,41 @@ func outputText(repo *types.Repository) error {
 		fmt.Println()
 	}
 
+	// Test organization section
+	if org := repo.TestOrganization; org != nil {
+		outputOrganizationText(org)
+	}
+
 	return nil
 }
+
+func outputOrganizationText(org *types.TestOrganization) {
+	fmt.Println("Test Organization:")
+	fmt.Printf("  Test Files: %d (%d lines)\n", org.TestFiles, org.TestLines)
+	fmt.Printf("  Source Files: %d (%d lines)\n", org.SourceFiles, org.SourceLines)
+
+	status := "✓"
+	if !org.MeetsRatio() {
+		status = "✗"
+	}
+
+	fmt.Printf("  Test-to-Code Ratio: %.2f (target ≥ %.2f) %s\n", org.Ratio, types.MinTestToCodeRatio, status)
+
+	for _, lang := range org.Languages {
+		if lang.TestFiles == 0 {
+			continue
+		}
+
+		fmt.Printf("    • %s: %d test files, ratio %.2f, %s\n",
+			lang.Language, lang.TestFiles, lang.Ratio, lang.DominantLayout())
+	}
+
+	if len(org.Warnings) > 0 {
+		fmt.Println("  Warnings:")
+		for _, warning := range org.Warnings {
+			fmt.Printf("    ⚠ %s\n", warning)
+		}
+	}
+
+	fmt.Println()
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/chambridge/ship-shape/internal/groundtruth"
 	"github.com/chambridge/ship-shape/internal/logger"
 	"github.com/chambridge/ship-shape/internal/testutil"
 	"github.com/chambridge/ship-shape/pkg/types"
@@ -23,6 +24,54 @@ func resetRootCmd(t *testing.T) {
 	// Reset discover command flags
 	discoverJSON = false
 
+	// Reset tests list command flags
+	testsListFormat = "text"
+	testsListLanguages = nil
+	testsListWorkspaces = nil
+	testsListPaths = nil
+	testsListFrameworks = nil
+	testsMapJSON = false
+	testsMapLinks = false
+	testsSmellsJSON = false
+	testsSmellsMinSeverity = "info"
+	testsSmellsBaseline = ""
+	testsSmellsUpdateBaseline = false
+	testsSmellsFailOn = ""
+	testsPatternsJSON = false
+	testsAssertionsJSON = false
+	testsAssertionsTests = false
+	testsFlakinessJSON = false
+	testsFlakinessTop = 10
+	testsFixturesJSON = false
+	testsFixturesTests = false
+	validateDetectorsJSON = false
+	validateDetectorsSchema = ""
+	validateDetectorsMinPrecision = groundtruth.DefaultTarget
+	validateDetectorsMinRecall = groundtruth.DefaultTarget
+	validateDetectorsMismatches = false
+	coverageLocateJSON = false
+	coverageMergeOutput = ""
+	coverageMergeFormat = ""
+	coverageMergeMode = ""
+	coverageMergeDir = "."
+	coverageDiffBase = "origin/main"
+	coverageDiffDir = "."
+	coverageDiffJSON = false
+	coverageDiffFailUnder = 0
+	coverageCriticalDir = "."
+	coverageCriticalJSON = false
+	coverageCheckDir = "."
+	coverageCheckJSON = false
+	coverageCheckRatchet = false
+	coverageCheckRatchetFile = ""
+	coverageCheckUpdateRatchet = false
+	coverageRunWorkDir = ""
+	coverageRunTimeout = 0
+	coverageRunWorkspaces = nil
+	coverageRunOutput = ""
+	coverageRunFormat = ""
+	coverageRunJSON = false
+
 	// Create a minimal logger that doesn't write anywhere during tests
 	// This prevents race conditions from logger writing to redirected stderr
 	cfg := logger.Config{
@@ -129,6 +178,11 @@ func TestDiscoverCommand(t *testing.T) {
 			t.Error("Output should mention jest framework")
 		}
 
+		// Should report test organization
+		if !contains(stdout, "Test-to-Code Ratio:") {
+			t.Error("Output should include the test-to-code ratio")
+		}
+
 		// Should detect eslint
 		if !contains(stdout, "eslint") {
 			t.Error("Output should mention eslint")
@@ -189,6 +243,11 @@ func TestDiscoverCommand(t *testing.T) {
 		if !hasGo {
 			t.Error("Go language not detected in JSON output")
 		}
+
+		// Should include test organization metrics
+		if repo.TestOrganization == nil || repo.TestOrganization.TestFiles != 1 || repo.TestOrganization.SourceFiles != 1 {
+			t.Errorf("TestOrganization = %+v, want 1 test file and 1 source file", repo.TestOrganization)
+		}
 	})
 
 	t.Run("handles non-existent directory", func(t *testing.T) {
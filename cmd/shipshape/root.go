@@ -5,8 +5,8 @@
 package main
 
 import (
-	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/chambridge/ship-shape/internal/logger"
 	"github.com/spf13/cobra"
@@ -107,21 +107,34 @@ func findRepositoryRoot() string {
 		return "."
 	}
 
+	if root, ok := repositoryRoot(dir); ok {
+		return root
+	}
+
+	return "."
+}
+
+// repositoryRoot returns the closest directory at or above dir that contains
+// a .git directory or file (worktrees and submodules use a file).
+func repositoryRoot(dir string) (string, bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
 	for {
-		if _, err := os.Stat(fmt.Sprintf("%s/.git", dir)); err == nil {
-			return dir
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return dir, true
 		}
 
-		parent := fmt.Sprintf("%s/..", dir)
+		parent := filepath.Dir(dir)
 		if parent == dir {
 			// Reached filesystem root
-			break
+			return "", false
 		}
 
 		dir = parent
 	}
-
-	return "."
 }
 
 // initLogger initializes the logger based on CLI flags.
@@ -0,0 +1,274 @@
+// Ship Shape - Tests Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testsListFormat     string
+	testsListLanguages  []string
+	testsListWorkspaces []string
+	testsListPaths      []string
+	testsListFrameworks []string
+)
+
+// testsCmd groups commands that inspect the test suites of a repository
+var testsCmd = &cobra.Command{
+	Use:   "tests",
+	Short: "Inspect the test suites of a repository",
+	Long: `Commands for inspecting the tests declared in a repository.
+
+Tests are discovered statically from source files for Go, Python,
+JavaScript/TypeScript (Jest, Vitest, Mocha) and Java (JUnit, TestNG).`,
+}
+
+// testsListCmd represents the tests list command
+var testsListCmd = &cobra.Command{
+	Use:   "list [directory]",
+	Short: "List all tests in a repository",
+	Long: `Enumerates every test across all supported languages.
+
+Each test is printed with its ID, location, kind, framework, skipped/focused
+state and the number of statically known parameter cases.
+
+Example:
+  shipshape tests list
+  shipshape tests list --language go --path 'internal/**'
+  shipshape tests list --framework jest --format csv > tests.csv
+  shipshape tests list --workspace web --format json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTestsList,
+}
+
+func init() {
+	rootCmd.AddCommand(testsCmd)
+	testsCmd.AddCommand(testsListCmd)
+
+	testsListCmd.Flags().StringVarP(&testsListFormat, "format", "f", "text", "output format: text, json, csv")
+	testsListCmd.Flags().StringSliceVar(&testsListLanguages, "language", nil, "only list tests in these languages")
+	testsListCmd.Flags().StringSliceVar(&testsListWorkspaces, "workspace", nil, "only list tests in these workspaces (name or path)")
+	testsListCmd.Flags().StringSliceVar(&testsListPaths, "path", nil, "only list tests in files matching these globs")
+	testsListCmd.Flags().StringSliceVar(&testsListFrameworks, "framework", nil, "only list tests for these frameworks")
+}
+
+// testListEntry is a single row of the tests list output.
+type testListEntry struct {
+	ID             string         `json:"id"`
+	File           string         `json:"file"`
+	Line           int            `json:"line"`
+	Kind           types.TestKind `json:"kind"`
+	Language       types.Language `json:"language"`
+	Framework      string         `json:"framework"`
+	Workspace      string         `json:"workspace,omitempty"`
+	Skipped        bool           `json:"skipped"`
+	Focused        bool           `json:"focused"`
+	Todo           bool           `json:"todo"`
+	ParameterCases int            `json:"parameter_cases"`
+}
+
+func runTestsList(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	switch testsListFormat {
+	case "text", "json", "csv":
+	default:
+		return fmt.Errorf("unsupported format %q (want text, json or csv)", testsListFormat)
+	}
+
+	logger.Info("Collecting tests", "directory", dir)
+
+	walker := discovery.NewWalker(dir)
+
+	workspaces, err := discovery.NewWorkspaceDetector(dir, walker).Detect()
+	if err != nil {
+		return fmt.Errorf("failed to detect workspaces: %w", err)
+	}
+
+	inv, err := inventory.NewCollector(walker).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to collect tests: %w", err)
+	}
+
+	entries := filterTestEntries(inv, workspaces)
+
+	logger.Debug("Tests collected", "files", len(inv.Files), "tests", len(entries))
+
+	switch testsListFormat {
+	case "json":
+		return writeTestsJSON(os.Stdout, entries)
+	case "csv":
+		return writeTestsCSV(os.Stdout, entries)
+	default:
+		return writeTestsText(os.Stdout, entries)
+	}
+}
+
+// filterTestEntries flattens the inventory and applies the command filters.
+func filterTestEntries(inv *types.TestInventory, workspaces []types.Workspace) []testListEntry {
+	entries := []testListEntry{}
+
+	for i := range inv.Files {
+		file := &inv.Files[i]
+
+		if !matchesFilter(testsListLanguages, string(file.Language)) ||
+			!matchesFilter(testsListFrameworks, file.Framework) {
+			continue
+		}
+
+		if len(testsListPaths) > 0 && !discovery.MatchAnyGlob(testsListPaths, file.Path) {
+			continue
+		}
+
+		workspace := ""
+		if ws := discovery.WorkspaceFor(workspaces, file.Path); ws != nil {
+			workspace = ws.Name
+
+			if len(testsListWorkspaces) > 0 && !matchesFilter(testsListWorkspaces, ws.Name) &&
+				!matchesFilter(testsListWorkspaces, ws.Path) {
+				continue
+			}
+		} else if len(testsListWorkspaces) > 0 {
+			continue
+		}
+
+		for _, tc := range file.EffectiveLeaves() {
+			entries = append(entries, testListEntry{
+				ID:             tc.ID,
+				File:           tc.File,
+				Line:           tc.Line,
+				Kind:           tc.Kind,
+				Language:       file.Language,
+				Framework:      file.Framework,
+				Workspace:      workspace,
+				Skipped:        tc.Skipped,
+				Focused:        tc.Focused,
+				Todo:           tc.Todo,
+				ParameterCases: tc.ParameterCases,
+			})
+		}
+	}
+
+	return entries
+}
+
+// matchesFilter reports whether value matches one of the filter values
+// (case-insensitively). An empty filter matches everything.
+func matchesFilter(filter []string, value string) bool {
+	if len(filter) == 0 {
+		return true
+	}
+
+	for _, f := range filter {
+		if strings.EqualFold(strings.TrimSpace(f), value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func writeTestsJSON(w io.Writer, entries []testListEntry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(entries); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
+
+func writeTestsCSV(w io.Writer, entries []testListEntry) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"id", "file", "line", "kind", "language", "framework", "workspace",
+		"skipped", "focused", "todo", "parameter_cases"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	for _, e := range entries {
+		record := []string{
+			e.ID, e.File, strconv.Itoa(e.Line), string(e.Kind), string(e.Language), e.Framework, e.Workspace,
+			strconv.FormatBool(e.Skipped), strconv.FormatBool(e.Focused), strconv.FormatBool(e.Todo),
+			strconv.Itoa(e.ParameterCases),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func writeTestsText(w io.Writer, entries []testListEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "LOCATION\tKIND\tFRAMEWORK\tFLAGS\tCASES\tID")
+
+	skipped, focused := 0, 0
+
+	for _, e := range entries {
+		var flags []string
+
+		if e.Skipped {
+			flags = append(flags, "skipped")
+			skipped++
+		}
+
+		if e.Focused {
+			flags = append(flags, "focused")
+			focused++
+		}
+
+		if e.Todo {
+			flags = append(flags, "todo")
+		}
+
+		flagText := "-"
+		if len(flags) > 0 {
+			flagText = strings.Join(flags, ",")
+		}
+
+		cases := "-"
+		if e.ParameterCases > 0 {
+			cases = strconv.Itoa(e.ParameterCases)
+		}
+
+		fmt.Fprintf(tw, "%s:%d\t%s\t%s\t%s\t%s\t%s\n", e.File, e.Line, e.Kind, e.Framework, flagText, cases, e.ID)
+	}
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	fmt.Fprintf(w, "\n%d tests (%d skipped, %d focused)\n", len(entries), skipped, focused)
+
+	return nil
+}
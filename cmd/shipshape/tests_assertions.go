@@ -0,0 +1,152 @@
+// Ship Shape - Tests Assertions Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/chambridge/ship-shape/internal/assertions"
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testsAssertionsJSON  bool
+	testsAssertionsTests bool
+)
+
+// testsAssertionsCmd represents the tests assertions command
+var testsAssertionsCmd = &cobra.Command{
+	Use:   "assertions [directory]",
+	Short: "Measure assertion quality",
+	Long: `Counts the assertions of every test and reports weak assertions and tests
+without assertions, aggregated per package (directory) and per framework.
+
+Weak assertions hide the compared values when they fail:
+  • Truthiness checks: assert x, expect(x).toBeTruthy(), assert.ok(x)
+  • Comparisons checked as booleans: assertTrue(a == b),
+    assert.True(t, a == b), expect(a === b).toBe(true)
+  • Failures without a message: t.Fail(), t.Error()
+
+Each weak assertion comes with the specific assertion to use instead, such
+as testify's assert.Equal, unittest's assertEqual or AssertJ's
+assertThat(actual).isEqualTo(expected).
+
+Example:
+  shipshape tests assertions
+  shipshape tests assertions --tests
+  shipshape tests assertions /path/to/repo --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTestsAssertions,
+}
+
+func init() {
+	testsCmd.AddCommand(testsAssertionsCmd)
+
+	testsAssertionsCmd.Flags().BoolVar(&testsAssertionsJSON, "json", false, "output in JSON format")
+	testsAssertionsCmd.Flags().BoolVar(&testsAssertionsTests, "tests", false, "list the assertion count of every test")
+}
+
+func runTestsAssertions(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	logger.Info("Measuring assertion quality", "directory", dir)
+
+	inv, err := inventory.NewCollector(discovery.NewWalker(dir)).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to collect test inventory: %w", err)
+	}
+
+	report := assertions.NewAnalyzer().Analyze(inv)
+
+	logger.Debug("Assertion analysis complete", "tests", report.Summary.Tests, "findings", len(report.Findings))
+
+	if testsAssertionsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	writeAssertionsText(os.Stdout, report, testsAssertionsTests)
+
+	return nil
+}
+
+func writeAssertionsText(w io.Writer, report *assertions.Report, showTests bool) {
+	s := report.Summary
+	if s.Tests == 0 {
+		fmt.Fprintln(w, "No tests found for assertion analysis")
+		return
+	}
+
+	fmt.Fprintf(w, "Assertions: %d in %d tests (%.1f per test, %.1f%% specific)\n",
+		s.Assertions, s.Tests, s.AssertionsPerTest, s.SpecificPercentage)
+	fmt.Fprintf(w, "Tests Without Assertions: %d\n", s.WithoutAssertions)
+	fmt.Fprintf(w, "Weak Assertions: %d\n\n", s.WeakAssertions)
+
+	writeAssertionStats(w, "PACKAGE", report.Packages)
+	fmt.Fprintln(w)
+	writeAssertionStats(w, "FRAMEWORK", report.Frameworks)
+
+	if showTests {
+		fmt.Fprintln(w, "\nTests:")
+
+		for _, t := range report.Tests {
+			fmt.Fprintf(w, "  %s (%d assertions, %d weak)\n", t.Test, t.Assertions, len(t.WeakAssertions))
+		}
+	}
+
+	if len(report.Recommendations) > 0 {
+		fmt.Fprintln(w, "\nRecommendations:")
+
+		for _, rec := range report.Recommendations {
+			fmt.Fprintf(w, "  • %s (%d weak assertions): %s\n", rec.Framework, rec.WeakAssertions, rec.Summary)
+
+			for _, r := range rec.Replacements {
+				fmt.Fprintf(w, "      %s → %s (%d)\n", r.Assertion, r.Suggestion, r.Count)
+			}
+		}
+	}
+
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(w, "\nFindings:")
+
+		for _, f := range report.Findings {
+			fmt.Fprintf(w, "%s [%s] %s\n", f.Location, f.Severity, f.Title)
+			fmt.Fprintf(w, "  %s\n", f.Description)
+		}
+	}
+}
+
+func writeAssertionStats(w io.Writer, header string, stats []assertions.Stats) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "%s\tTESTS\tASSERTIONS\tPER TEST\tWITHOUT\tWEAK\tSPECIFIC\n", header)
+
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.1f\t%d\t%d\t%.1f%%\n",
+			s.Name, s.Tests, s.Assertions, s.AssertionsPerTest, s.WithoutAssertions, s.WeakAssertions, s.SpecificPercentage)
+	}
+
+	_ = tw.Flush()
+}
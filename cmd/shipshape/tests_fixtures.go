@@ -0,0 +1,158 @@
+// Ship Shape - Tests Fixtures Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/fixtures"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testsFixturesJSON  bool
+	testsFixturesTests bool
+)
+
+// testsFixturesCmd represents the tests fixtures command
+var testsFixturesCmd = &cobra.Command{
+	Use:   "fixtures [directory]",
+	Short: "Analyze test fixtures and global state",
+	Long: `Finds the fixtures tests depend on and reports shared state that leaks
+between tests.
+
+Fixtures:
+  • Go: package variables, TestMain, testify suite setup, helpers taking *testing.T
+  • Python: pytest fixtures (including conftest.py), module-level mutable values, setUp hooks
+  • JavaScript/TypeScript: beforeEach/beforeAll hooks, jest.mock/vi.mock, module-level let and var
+
+Checks:
+  • global-state-mutation: a test changes package, module or process state without restoring it
+  • leaky-test-main: TestMain creates resources it never releases
+  • mutable-shared-fixture: a test mutates a class, module, package or session-scoped pytest fixture
+  • mock-without-reset: several tests assert calls on module-level mocks that are never cleared
+  • missing-cleanup: setup creates resources without t.Cleanup, yield teardown or afterEach
+
+Example:
+  shipshape tests fixtures
+  shipshape tests fixtures /path/to/repo --tests
+  shipshape tests fixtures --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTestsFixtures,
+}
+
+func init() {
+	testsCmd.AddCommand(testsFixturesCmd)
+
+	testsFixturesCmd.Flags().BoolVar(&testsFixturesJSON, "json", false, "output in JSON format")
+	testsFixturesCmd.Flags().BoolVar(&testsFixturesTests, "tests", false, "list the fixtures of every test")
+}
+
+func runTestsFixtures(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	logger.Info("Analyzing test fixtures", "directory", dir)
+
+	report, err := fixtures.NewAnalyzer(discovery.NewWalker(dir)).Analyze()
+	if err != nil {
+		return fmt.Errorf("failed to analyze fixtures: %w", err)
+	}
+
+	logger.Debug("Fixture analysis complete", "fixtures", report.Summary.Fixtures, "findings", report.Summary.Findings)
+
+	if testsFixturesJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	writeFixturesText(os.Stdout, report, testsFixturesTests)
+
+	return nil
+}
+
+func writeFixturesText(w io.Writer, report *fixtures.Report, showTests bool) {
+	s := report.Summary
+
+	if s.Tests == 0 && s.Fixtures == 0 {
+		fmt.Fprintln(w, "No tests found for fixture analysis")
+		return
+	}
+
+	fmt.Fprintf(w, "Tests: %d (with fixtures: %d, with global state: %d)\n", s.Tests, s.TestsWithFixtures, s.TestsWithGlobalState)
+	fmt.Fprintf(w, "Fixtures: %d (shared: %d)\n", s.Fixtures, s.SharedFixtures)
+
+	if len(report.Fixtures) > 0 {
+		fmt.Fprintln(w, "\nFixtures:")
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		for _, f := range report.Fixtures {
+			var flags string
+			if f.Mutable {
+				flags += " mutable"
+			}
+
+			if f.Teardown {
+				flags += " teardown"
+			}
+
+			if f.Autouse {
+				flags += " autouse"
+			}
+
+			fmt.Fprintf(tw, "  • %s\t%s\t%s%s\n", f.ID, f.Kind, f.Scope, flags)
+		}
+
+		_ = tw.Flush()
+	}
+
+	if showTests {
+		fmt.Fprintln(w, "\nTests:")
+
+		for _, t := range report.Tests {
+			state := ""
+			if t.GlobalState {
+				state = " [global state]"
+			}
+
+			fmt.Fprintf(w, "  %s%s\n", t.Test, state)
+
+			for _, id := range t.Fixtures {
+				fmt.Fprintf(w, "    • %s\n", id)
+			}
+		}
+	}
+
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(w, "\nNo fixture problems found")
+		return
+	}
+
+	fmt.Fprintln(w, "\nFindings:")
+
+	for _, f := range report.Findings {
+		fmt.Fprintf(w, "%s [%s] %s\n", f.Location, f.Severity, f.Title)
+		fmt.Fprintf(w, "  %s\n", f.Description)
+	}
+}
@@ -0,0 +1,134 @@
+// Ship Shape - Tests Flakiness Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/flakiness"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testsFlakinessJSON bool
+	testsFlakinessTop  int
+)
+
+// testsFlakinessCmd represents the tests flakiness command
+var testsFlakinessCmd = &cobra.Command{
+	Use:   "flakiness [directory]",
+	Short: "Score the flakiness risk of individual tests",
+	Long: `Assigns every test a flakiness risk score between 0 and 100 from static
+signals of non-determinism and lists the riskiest tests.
+
+Signals (weight):
+  • sleep (30): time.Sleep, time.sleep, setTimeout without fake timers
+  • concurrency (30): goroutines and threads the test does not wait for
+  • parallel-shared-state (30): t.Parallel or test.concurrent with shared state
+  • network (25): real HTTP clients, fixed ports
+  • wall-clock (20): time.Now, datetime.now, Date.now
+  • randomness (20): unseeded global random sources
+  • global-state (20): package variables, environment, working directory
+  • map-iteration (15): results built while ranging over maps or sets
+  • temp-path (15): temporary paths without cleanup, hard-coded /tmp paths
+
+The score of a test is the sum of the weights of its distinct signals.
+Scores of 60 and above are high risk, 30 and above medium risk.
+
+Example:
+  shipshape tests flakiness
+  shipshape tests flakiness /path/to/repo --top 25
+  shipshape tests flakiness --top 0 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTestsFlakiness,
+}
+
+func init() {
+	testsCmd.AddCommand(testsFlakinessCmd)
+
+	testsFlakinessCmd.Flags().BoolVar(&testsFlakinessJSON, "json", false, "output in JSON format")
+	testsFlakinessCmd.Flags().IntVar(&testsFlakinessTop, "top", 10, "number of riskiest tests to list (0 for all)")
+}
+
+func runTestsFlakiness(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	logger.Info("Scoring flakiness risk", "directory", dir)
+
+	report, err := flakiness.NewAnalyzer(discovery.NewWalker(dir)).Analyze()
+	if err != nil {
+		return fmt.Errorf("failed to analyze flakiness: %w", err)
+	}
+
+	logger.Debug("Flakiness analysis complete", "tests", report.Summary.Tests, "at_risk", report.Summary.AtRisk)
+
+	report.Tests = report.Top(testsFlakinessTop)
+
+	if testsFlakinessJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	writeFlakinessText(os.Stdout, report)
+
+	return nil
+}
+
+func writeFlakinessText(w io.Writer, report *flakiness.Report) {
+	s := report.Summary
+
+	if s.Tests == 0 {
+		fmt.Fprintln(w, "No tests found for flakiness analysis")
+		return
+	}
+
+	fmt.Fprintf(w, "Tests: %d (at risk: %d, high: %d, medium: %d, low: %d)\n", s.Tests, s.AtRisk, s.High, s.Medium, s.Low)
+
+	if s.AtRisk == 0 {
+		fmt.Fprintln(w, "\nNo flakiness signals found")
+		return
+	}
+
+	fmt.Fprintln(w, "\nSignals:")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, signal := range flakiness.All() {
+		if n := s.Signals[signal]; n > 0 {
+			fmt.Fprintf(tw, "  • %s\t%d\n", signal, n)
+		}
+	}
+
+	_ = tw.Flush()
+
+	fmt.Fprintf(w, "\nRiskiest tests (%d of %d):\n", len(report.Tests), s.AtRisk)
+
+	for _, r := range report.Tests {
+		fmt.Fprintf(w, "\n[%d %s] %s\n", r.Score, r.Level, r.Test)
+
+		for _, e := range r.Signals {
+			fmt.Fprintf(w, "  • %s:%d %s: %s\n", r.File, e.Line, e.Signal, e.Detail)
+		}
+	}
+}
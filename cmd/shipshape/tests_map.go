@@ -0,0 +1,135 @@
+// Ship Shape - Tests Map Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/internal/mapping"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	testsMapJSON  bool
+	testsMapLinks bool
+)
+
+// testsMapCmd represents the tests map command
+var testsMapCmd = &cobra.Command{
+	Use:   "map [directory]",
+	Short: "Map tests to the source files they exercise",
+	Long: `Links test files to production source files using language conventions
+and import analysis, then reports untested source files and orphan tests.
+
+Conventions:
+  • Go: foo_test.go covers foo.go, otherwise its whole package
+  • Python: tests/test_foo.py and foo_test.py cover foo.py
+  • JavaScript/TypeScript: colocated foo.test.ts, foo.spec.ts and __tests__/foo.ts cover foo.ts
+  • Java: src/test/java/.../FooTest.java covers src/main/java/.../Foo.java
+
+Example:
+  shipshape tests map
+  shipshape tests map --links
+  shipshape tests map /path/to/repo --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTestsMap,
+}
+
+func init() {
+	testsCmd.AddCommand(testsMapCmd)
+
+	testsMapCmd.Flags().BoolVar(&testsMapJSON, "json", false, "output in JSON format")
+	testsMapCmd.Flags().BoolVar(&testsMapLinks, "links", false, "list the sources linked to each test file")
+}
+
+func runTestsMap(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	logger.Info("Mapping tests to sources", "directory", dir)
+
+	result, err := mapping.NewMapper(dir, discovery.NewWalker(dir)).Map()
+	if err != nil {
+		return fmt.Errorf("failed to map tests: %w", err)
+	}
+
+	logger.Debug("Mapping complete",
+		"links", len(result.Links),
+		"untested", len(result.Untested),
+		"orphans", len(result.Orphans),
+	)
+
+	if testsMapJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(result); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	writeTestMapText(os.Stdout, result, testsMapLinks)
+
+	return nil
+}
+
+func writeTestMapText(w io.Writer, result *types.TestMap, showLinks bool) {
+	fmt.Fprintf(w, "Source Files: %d (%.1f%% with tests)\n", result.SourceFiles, result.TestedPercentage())
+	fmt.Fprintf(w, "Test Files: %d\n\n", result.TestFiles)
+
+	if showLinks && len(result.Links) > 0 {
+		fmt.Fprintln(w, "Links:")
+
+		current := ""
+		for _, link := range result.Links {
+			if link.Test != current {
+				current = link.Test
+				fmt.Fprintf(w, "  %s\n", link.Test)
+			}
+
+			fmt.Fprintf(w, "    → %s (%s)\n", link.Source, link.Reason)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	if len(result.Untested) > 0 {
+		fmt.Fprintf(w, "Untested Source Files (%d):\n", len(result.Untested))
+		for _, src := range result.Untested {
+			fmt.Fprintf(w, "  • %s\n", src)
+		}
+
+		fmt.Fprintln(w)
+	} else {
+		fmt.Fprintln(w, "Untested Source Files: None")
+		fmt.Fprintln(w)
+	}
+
+	if len(result.Orphans) > 0 {
+		fmt.Fprintf(w, "Orphan Tests (%d):\n", len(result.Orphans))
+		for _, orphan := range result.Orphans {
+			fmt.Fprintf(w, "  • %s (expected %s)\n", orphan.Path, orphan.Subject)
+		}
+
+		fmt.Fprintln(w)
+	} else {
+		fmt.Fprintln(w, "Orphan Tests: None")
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,132 @@
+// Ship Shape - Tests Patterns Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/internal/patterns"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var testsPatternsJSON bool
+
+// testsPatternsCmd represents the tests patterns command
+var testsPatternsCmd = &cobra.Command{
+	Use:   "patterns [directory]",
+	Short: "Measure the adoption of testing best practices",
+	Long: `Measures how many tests follow the best practices of each language and
+compares the percentages with the thresholds under quality.patterns in
+.shipshape.yml. Patterns below their threshold are reported as findings.
+
+Patterns:
+  • Go: table-driven tests, t.Parallel() (over Test functions)
+  • Python: @pytest.mark.parametrize, fixture usage (over tests)
+  • JavaScript/TypeScript: describe/it structure (over tests),
+    async/await (over asynchronous tests)
+
+A threshold of 0 disables the check of a pattern.
+
+Example:
+  shipshape tests patterns
+  shipshape tests patterns /path/to/repo --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTestsPatterns,
+}
+
+func init() {
+	testsCmd.AddCommand(testsPatternsCmd)
+
+	testsPatternsCmd.Flags().BoolVar(&testsPatternsJSON, "json", false, "output in JSON format")
+}
+
+func runTestsPatterns(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	cfg, err := patterns.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load pattern configuration: %w", err)
+	}
+
+	logger.Info("Measuring test patterns", "directory", dir)
+
+	inv, err := inventory.NewCollector(discovery.NewWalker(dir)).Collect()
+	if err != nil {
+		return fmt.Errorf("failed to collect test inventory: %w", err)
+	}
+
+	report := patterns.NewAnalyzer(cfg).Analyze(inv)
+
+	logger.Debug("Pattern analysis complete", "metrics", len(report.Metrics), "findings", len(report.Findings))
+
+	if testsPatternsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+
+		return nil
+	}
+
+	writePatternsText(os.Stdout, report)
+
+	return nil
+}
+
+func writePatternsText(w io.Writer, report *patterns.Report) {
+	if len(report.Metrics) == 0 {
+		fmt.Fprintln(w, "No tests found for pattern analysis")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "LANGUAGE\tPATTERN\tADOPTED\tPERCENTAGE\tTHRESHOLD\tSTATUS")
+
+	for _, m := range report.Metrics {
+		status := "✓"
+		if !m.Met {
+			status = "✗"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%d/%d\t%.1f%%\t%.0f%%\t%s\n",
+			m.Language, m.Pattern, m.Adopted, m.Eligible, m.Percentage, m.Threshold, status)
+	}
+
+	_ = tw.Flush()
+
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(w, "\nAll patterns meet their thresholds")
+		return
+	}
+
+	fmt.Fprintln(w)
+
+	for _, f := range report.Findings {
+		fmt.Fprintf(w, "[%s] %s\n", f.Severity, f.Title)
+		fmt.Fprintf(w, "  %s\n", f.Description)
+
+		if f.Remediation != nil {
+			fmt.Fprintf(w, "  • Fix: %s\n", f.Remediation.Summary)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+// Ship Shape - Tests Smells Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/internal/smells"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	testsSmellsJSON           bool
+	testsSmellsMinSeverity    string
+	testsSmellsBaseline       string
+	testsSmellsUpdateBaseline bool
+	testsSmellsFailOn         string
+)
+
+// testsSmellsCmd represents the tests smells command
+var testsSmellsCmd = &cobra.Command{
+	Use:   "smells [directory]",
+	Short: "Detect test smells",
+	Long: `Analyzes test files for the smells of the Ship Shape catalog:
+mystery-guest, eager-test, lazy-test, assertion-roulette, conditional-logic,
+general-fixture, obscure-test, sensitive-equality, resource-optimism,
+code-duplication and flakiness, plus focused-test, skipped-test, empty-test,
+snapshot-only and missing-await for JavaScript/TypeScript.
+
+Supported languages:
+  • Go (testing, testify)
+  • Python (pytest, unittest), including fixtures in conftest.py
+  • JavaScript/TypeScript (Jest, Vitest, Mocha)
+
+code-duplication reports token-based clones across all test files of a
+language: identical copies (Type 1) and copies with renamed identifiers or
+literals (Type 2) of at least quality.smells.duplication.min-tokens tokens.
+
+Smells can be disabled with quality.smells.detect and their severity changed
+with quality.smells.severity-overrides in .shipshape.yml.
+
+Suppressing findings:
+  • Inline: a "// shipshape:ignore eager-test,lazy-test reason" comment
+    (or "#" in Python) on the line before a finding or inside its range
+  • Per path: quality.smells.suppress entries with a path glob, optional
+    smells and a reason
+  • Baseline: --update-baseline records the current findings; with
+    --baseline (or quality.smells.baseline) only new findings are reported
+
+Findings are matched against the baseline by fingerprints that do not
+depend on line numbers. --fail-on (or gates.fail-on) exits with an error
+when new findings reach the given severity.
+
+Example:
+  shipshape tests smells
+  shipshape tests smells --min-severity medium
+  shipshape tests smells /path/to/repo --json
+  shipshape tests smells --update-baseline --baseline .shipshape-smells-baseline.json
+  shipshape tests smells --baseline .shipshape-smells-baseline.json --fail-on low`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTestsSmells,
+}
+
+func init() {
+	testsCmd.AddCommand(testsSmellsCmd)
+
+	testsSmellsCmd.Flags().BoolVar(&testsSmellsJSON, "json", false, "output in JSON format")
+	testsSmellsCmd.Flags().StringVar(&testsSmellsMinSeverity, "min-severity", "info",
+		"only report smells at or above this severity: info, low, medium, high, critical")
+	testsSmellsCmd.Flags().StringVar(&testsSmellsBaseline, "baseline", "",
+		"baseline file of accepted smells (default: quality.smells.baseline)")
+	testsSmellsCmd.Flags().BoolVar(&testsSmellsUpdateBaseline, "update-baseline", false,
+		"write the current smells to the baseline file instead of reporting them")
+	testsSmellsCmd.Flags().StringVar(&testsSmellsFailOn, "fail-on", "",
+		"fail when new smells reach this severity, or none (default: gates.fail-on)")
+}
+
+//nolint:gocognit,gocyclo // Baseline, filtering, output and gate handling
+func runTestsSmells(_ *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	minSeverity, err := types.ParseSeverity(testsSmellsMinSeverity)
+	if err != nil {
+		return err
+	}
+
+	failOn := testsSmellsFailOn
+	if failOn == "" {
+		failOn = viper.GetString("gates.fail-on")
+	}
+
+	var failSeverity types.Severity
+	if failOn != "" && failOn != "none" {
+		if failSeverity, err = types.ParseSeverity(failOn); err != nil {
+			return fmt.Errorf("invalid --fail-on: %w", err)
+		}
+	}
+
+	baselinePath := testsSmellsBaseline
+	if baselinePath == "" {
+		baselinePath = viper.GetString("quality.smells.baseline")
+	}
+
+	cfg, err := smells.LoadConfig(viper.GetViper())
+	if err != nil {
+		return fmt.Errorf("failed to load smell configuration: %w", err)
+	}
+
+	logger.Info("Detecting test smells", "directory", dir)
+
+	findings, err := smells.NewEngine(discovery.NewWalker(dir), cfg).Analyze()
+	if err != nil {
+		return fmt.Errorf("failed to detect smells: %w", err)
+	}
+
+	if testsSmellsUpdateBaseline {
+		if baselinePath == "" {
+			baselinePath = smells.DefaultBaselineFile
+		}
+
+		if err := smells.NewBaseline(findings).Save(baselinePath); err != nil {
+			return err
+		}
+
+		fmt.Printf("Wrote baseline of %d smells to %s\n", len(findings), baselinePath)
+
+		return nil
+	}
+
+	known := 0
+
+	if baselinePath != "" {
+		baseline, err := smells.LoadBaseline(baselinePath)
+		if err != nil {
+			return err
+		}
+
+		findings, known = baseline.Filter(findings)
+	}
+
+	filtered := make([]types.Finding, 0, len(findings))
+	for _, f := range findings {
+		if f.Severity.AtLeast(minSeverity) {
+			filtered = append(filtered, f)
+		}
+	}
+
+	logger.Debug("Smell detection complete", "findings", len(findings), "reported", len(filtered), "baseline", known)
+
+	if testsSmellsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(filtered); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		writeFindingsText(os.Stdout, filtered)
+
+		if known > 0 {
+			fmt.Printf("%d known smells matched the baseline %s\n", known, baselinePath)
+		}
+	}
+
+	if failSeverity != "" {
+		failing := 0
+
+		for _, f := range findings {
+			if f.Severity.AtLeast(failSeverity) {
+				failing++
+			}
+		}
+
+		if failing > 0 {
+			return fmt.Errorf("%d new smells at or above %s severity", failing, failSeverity)
+		}
+	}
+
+	return nil
+}
+
+func writeFindingsText(w io.Writer, findings []types.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No test smells found")
+		return
+	}
+
+	counts := make(map[types.Severity]int)
+
+	for _, f := range findings {
+		counts[f.Severity]++
+
+		fmt.Fprintf(w, "%s [%s] %s\n", f.Location, f.Severity, f.Title)
+		fmt.Fprintf(w, "  %s\n", f.Description)
+
+		if f.Remediation != nil {
+			fmt.Fprintf(w, "  • Fix: %s\n", f.Remediation.Summary)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "%d smells (%d critical, %d high, %d medium, %d low, %d info)\n", len(findings),
+		counts[types.SeverityCritical], counts[types.SeverityHigh], counts[types.SeverityMedium],
+		counts[types.SeverityLow], counts[types.SeverityInfo])
+}
@@ -0,0 +1,767 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/assertions"
+	"github.com/chambridge/ship-shape/internal/fixtures"
+	"github.com/chambridge/ship-shape/internal/flakiness"
+	"github.com/chambridge/ship-shape/internal/patterns"
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// newTestsListCmd creates a fresh tests list command to avoid initialization hooks
+func newTestsListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  "list [directory]",
+		Args: cobra.MaximumNArgs(1),
+		RunE: runTestsList,
+	}
+	cmd.Flags().StringVarP(&testsListFormat, "format", "f", "text", "output format")
+	cmd.Flags().StringSliceVar(&testsListLanguages, "language", nil, "language filter")
+	cmd.Flags().StringSliceVar(&testsListWorkspaces, "workspace", nil, "workspace filter")
+	cmd.Flags().StringSliceVar(&testsListPaths, "path", nil, "path filter")
+	cmd.Flags().StringSliceVar(&testsListFrameworks, "framework", nil, "framework filter")
+
+	return cmd
+}
+
+// writePolyglotRepo creates a monorepo with Go, Python and TypeScript tests
+func writePolyglotRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := testutil.TempDir(t)
+
+	testutil.WriteFile(t, dir, "go.mod", "module example.com/mono\n\ngo 1.24")
+	testutil.WriteFile(t, dir, "internal/calc/calc_test.go", `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {}
+
+func TestSub(t *testing.T) {
+	t.Skip("todo")
+}
+`)
+	testutil.WriteFile(t, dir, "ml/pyproject.toml", "[project]\nname = \"acme-ml\"\n")
+	testutil.WriteFile(t, dir, "ml/tests/test_model.py", `import pytest
+
+@pytest.mark.parametrize("x", [1, 2, 3])
+def test_fit(x):
+    assert x
+`)
+	testutil.WriteFile(t, dir, "web/package.json", `{"name": "web", "devDependencies": {"jest": "^29.0.0"}}`)
+	testutil.WriteFile(t, dir, "web/src/app.test.ts", `describe("app", () => {
+  it.only("renders", () => { expect(1).toBe(1); });
+});
+`)
+
+	return dir
+}
+
+//nolint:gocognit // Table-driven tests can be complex but are still readable
+func TestTestsListCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	t.Run("lists tests as JSON", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writePolyglotRepo(t)
+
+		cmd := newTestsListCmd()
+		cmd.SetArgs([]string{dir, "--format", "json"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests list failed: %v", err)
+			}
+		})
+
+		var entries []testListEntry
+		if err := json.Unmarshal([]byte(stdout), &entries); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+		}
+
+		byID := map[string]testListEntry{}
+		for _, e := range entries {
+			byID[e.ID] = e
+		}
+
+		if len(byID) != 4 {
+			t.Fatalf("entries = %+v, want 4", entries)
+		}
+
+		if e := byID["internal/calc/calc_test.go::TestSub"]; !e.Skipped || e.Workspace != "example.com/mono" {
+			t.Errorf("TestSub = %+v", e)
+		}
+
+		if e := byID["ml/tests/test_model.py::test_fit"]; e.ParameterCases != 3 || e.Workspace != "acme-ml" {
+			t.Errorf("test_fit = %+v", e)
+		}
+
+		if e := byID["web/src/app.test.ts::app::renders"]; !e.Focused || e.Framework != "jest" || e.Line != 2 {
+			t.Errorf("renders = %+v", e)
+		}
+	})
+
+	t.Run("filters by language and path", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writePolyglotRepo(t)
+
+		cmd := newTestsListCmd()
+		cmd.SetArgs([]string{dir, "--format", "csv", "--language", "go", "--path", "internal/**"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests list failed: %v", err)
+			}
+		})
+
+		records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV output: %v", err)
+		}
+
+		if len(records) != 3 || records[0][0] != "id" {
+			t.Fatalf("records = %v, want header plus 2 rows", records)
+		}
+
+		if records[2][0] != "internal/calc/calc_test.go::TestSub" || records[2][7] != "true" {
+			t.Errorf("row = %v", records[2])
+		}
+	})
+
+	t.Run("filters by workspace and framework", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writePolyglotRepo(t)
+
+		cmd := newTestsListCmd()
+		cmd.SetArgs([]string{dir, "--workspace", "web", "--framework", "jest"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests list failed: %v", err)
+			}
+		})
+
+		if !contains(stdout, "web/src/app.test.ts:2") || !contains(stdout, "focused") {
+			t.Errorf("text output missing focused jest test:\n%s", stdout)
+		}
+
+		if contains(stdout, "calc_test.go") {
+			t.Errorf("text output should not include Go tests:\n%s", stdout)
+		}
+
+		if !contains(stdout, "1 tests (0 skipped, 1 focused)") {
+			t.Errorf("text output missing summary:\n%s", stdout)
+		}
+	})
+
+	t.Run("rejects unknown format", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newTestsListCmd()
+		cmd.SetArgs([]string{testutil.TempDir(t), "--format", "xml"})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected error for unsupported format")
+		}
+	})
+
+	t.Run("rejects missing directory", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newTestsListCmd()
+		cmd.SetArgs([]string{"/nonexistent/path/to/repo"})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected error for missing directory")
+		}
+	})
+}
+
+func TestTestsMapCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "map [directory]",
+			Args: cobra.MaximumNArgs(1),
+			RunE: runTestsMap,
+		}
+		cmd.Flags().BoolVar(&testsMapJSON, "json", false, "output in JSON format")
+		cmd.Flags().BoolVar(&testsMapLinks, "links", false, "list links")
+
+		return cmd
+	}
+
+	t.Run("reports untested sources and orphans", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writePolyglotRepo(t)
+		testutil.WriteFile(t, dir, "internal/calc/calc.go", "package calc")
+		testutil.WriteFile(t, dir, "internal/calc/round.go", "package calc")
+		testutil.WriteFile(t, dir, "web/src/app.ts", "")
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{dir, "--links"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests map failed: %v", err)
+			}
+		})
+
+		for _, want := range []string{
+			"Source Files: 3 (66.7% with tests)",
+			"→ internal/calc/calc.go (convention)",
+			"Untested Source Files (1):",
+			"• internal/calc/round.go",
+			"• ml/tests/test_model.py (expected model.py)",
+		} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("outputs JSON", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writePolyglotRepo(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{dir, "--json"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests map failed: %v", err)
+			}
+		})
+
+		var result types.TestMap
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+		}
+
+		if result.TestFiles != 3 || len(result.Orphans) != 3 {
+			t.Errorf("result = %+v, want 3 test files, all orphans", result)
+		}
+	})
+}
+
+//nolint:gocognit // Table-driven tests can be complex but are still readable
+func TestTestsSmellsCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "smells [directory]",
+			Args: cobra.MaximumNArgs(1),
+			RunE: runTestsSmells,
+		}
+		cmd.Flags().BoolVar(&testsSmellsJSON, "json", false, "output in JSON format")
+		cmd.Flags().StringVar(&testsSmellsMinSeverity, "min-severity", "info", "minimum severity")
+		cmd.Flags().StringVar(&testsSmellsBaseline, "baseline", "", "baseline file")
+		cmd.Flags().BoolVar(&testsSmellsUpdateBaseline, "update-baseline", false, "write baseline")
+		cmd.Flags().StringVar(&testsSmellsFailOn, "fail-on", "", "fail severity")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	writeSmellyRepo := func(t *testing.T) string {
+		t.Helper()
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21")
+		testutil.WriteFile(t, dir, "app/app_test.go", `package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFoo(t *testing.T) {
+	time.Sleep(time.Second)
+}
+`)
+
+		return dir
+	}
+
+	t.Run("reports smells as text", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{writeSmellyRepo(t)})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests smells failed: %v", err)
+			}
+		})
+
+		for _, want := range []string{
+			"app/app_test.go:9 [high] Flakiness",
+			"TestFoo waits on the clock instead of synchronizing (time.Sleep(time.Second))",
+			"app/app_test.go:8 [low] Obscure Test",
+			"• Fix: Make the test deterministic",
+			"2 smells (0 critical, 1 high, 0 medium, 1 low, 0 info)",
+		} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("filters by severity and outputs JSON", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{writeSmellyRepo(t), "--json", "--min-severity", "medium"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests smells failed: %v", err)
+			}
+		})
+
+		var findings []types.Finding
+		if err := json.Unmarshal([]byte(stdout), &findings); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+		}
+
+		if len(findings) != 1 || findings[0].CheckID != "flakiness" {
+			t.Errorf("findings = %+v, want only flakiness", findings)
+		}
+	})
+
+	t.Run("reports only findings missing from the baseline", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := writeSmellyRepo(t)
+		baseline := filepath.Join(dir, "baseline.json")
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{dir, "--update-baseline", "--baseline", baseline})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests smells --update-baseline failed: %v", err)
+			}
+		})
+
+		if !contains(stdout, "Wrote baseline of 2 smells") {
+			t.Errorf("unexpected output:\n%s", stdout)
+		}
+
+		// Shift the existing test down and add a new smelly test
+		testutil.WriteFile(t, dir, "app/app_test.go", `package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBar(t *testing.T) {
+	time.Sleep(2 * time.Second)
+}
+
+func TestFoo(t *testing.T) {
+	time.Sleep(time.Second)
+}
+`)
+
+		resetRootCmd(t)
+
+		cmd = newCmd()
+		cmd.SetArgs([]string{dir, "--baseline", baseline, "--fail-on", "high"})
+
+		var runErr error
+
+		stdout, _ = testutil.CaptureOutput(t, func() {
+			runErr = cmd.Execute()
+		})
+
+		if runErr == nil || !contains(runErr.Error(), "1 new smells at or above high severity") {
+			t.Errorf("error = %v, want fail-on error for the new smell", runErr)
+		}
+
+		for _, want := range []string{"TestBar waits on the clock", "2 known smells matched the baseline"} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+
+		if contains(stdout, "TestFoo waits on the clock") {
+			t.Errorf("baselined finding reported:\n%s", stdout)
+		}
+	})
+
+	t.Run("honors inline suppressions", func(t *testing.T) {
+		resetRootCmd(t)
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "app/app_test.go", `package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetriesAfterBackoff(t *testing.T) {
+	// shipshape:ignore flakiness waits for the real backoff on purpose
+	time.Sleep(time.Second)
+}
+`)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{dir, "--fail-on", "low"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("tests smells failed: %v", err)
+			}
+		})
+
+		if !contains(stdout, "No test smells found") {
+			t.Errorf("unexpected output:\n%s", stdout)
+		}
+	})
+
+	t.Run("rejects invalid severity", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{writeSmellyRepo(t), "--min-severity", "urgent"})
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		if err := cmd.Execute(); err == nil {
+			t.Error("expected error for invalid severity")
+		}
+	})
+}
+
+// analysisCommandTest describes a tests subcommand that analyzes the tests
+// of a Go module and reports as text or JSON.
+type analysisCommandTest struct {
+	name  string
+	run   func(cmd *cobra.Command, args []string) error
+	json  *bool
+	flags func(cmd *cobra.Command)
+
+	// files are written to the module besides its go.mod
+	files map[string]string
+
+	textArgs   []string
+	wantText   []string
+	absentText []string
+	jsonArgs   []string
+
+	// checkJSON validates the JSON output
+	checkJSON func(t *testing.T, stdout string)
+}
+
+// newAnalysisCmd creates a fresh command for a test case, avoiding the
+// initialization hooks of the real command.
+func newAnalysisCmd(tt analysisCommandTest) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  tt.name + " [directory]",
+		Args: cobra.MaximumNArgs(1),
+		RunE: tt.run,
+	}
+	cmd.Flags().BoolVar(tt.json, "json", false, "output in JSON format")
+
+	if tt.flags != nil {
+		tt.flags(cmd)
+	}
+
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	return cmd
+}
+
+// writeModule writes a Go module with the given files.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21")
+
+	for name, content := range files {
+		testutil.WriteFile(t, dir, name, content)
+	}
+
+	return dir
+}
+
+// executeCmd runs a command and returns its standard output, failing the
+// test if the command fails.
+func executeCmd(t *testing.T, cmd *cobra.Command, args ...string) string {
+	t.Helper()
+
+	cmd.SetArgs(args)
+
+	stdout, _ := testutil.CaptureOutput(t, func() {
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s failed: %v", cmd.Name(), err)
+		}
+	})
+
+	return stdout
+}
+
+// decodeJSON decodes command output into v, failing the test if it is not
+// valid JSON.
+func decodeJSON(t *testing.T, stdout string, v any) {
+	t.Helper()
+
+	if err := json.Unmarshal([]byte(stdout), v); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+}
+
+// analysisCommandTests returns the cases of the analysis commands.
+func analysisCommandTests() []analysisCommandTest {
+	return []analysisCommandTest{
+		{
+			name: "patterns",
+			run:  runTestsPatterns,
+			json: &testsPatternsJSON,
+			files: map[string]string{
+				"app/app_test.go": `package app
+
+import "testing"
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct{ name string }{{"a"}, {"b"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {})
+	}
+}
+
+func TestPlain(t *testing.T) {}
+
+func TestMore(t *testing.T) {}
+`,
+			},
+			wantText: []string{
+				"Go        parallel      1/3      33.3%       30%        ✓",
+				"Go        table-driven  1/3      33.3%       50%        ✗",
+				"[low] Low Table-Driven Tests Adoption",
+				"1 of 3 Go test functions (33.3%) follow the pattern, below the 50% target",
+			},
+			checkJSON: func(t *testing.T, stdout string) {
+				var report patterns.Report
+				decodeJSON(t, stdout, &report)
+
+				if len(report.Metrics) != 2 || len(report.Findings) != 1 || report.Findings[0].CheckID != "table-driven-adoption" {
+					t.Errorf("report = %+v", report)
+				}
+			},
+		},
+		{
+			name: "assertions",
+			run:  runTestsAssertions,
+			json: &testsAssertionsJSON,
+			flags: func(cmd *cobra.Command) {
+				cmd.Flags().BoolVar(&testsAssertionsTests, "tests", false, "list tests")
+			},
+			files: map[string]string{
+				"app/app_test.go": `package app
+
+import "testing"
+
+func TestSum(t *testing.T) {
+	if Sum(1, 2) != 3 {
+		t.Fail()
+	}
+}
+
+func TestRun(t *testing.T) {
+	Run()
+}
+`,
+			},
+			textArgs: []string{"--tests"},
+			wantText: []string{
+				"Assertions: 1 in 2 tests (0.5 per test, 0.0% specific)",
+				"Tests Without Assertions: 1",
+				"app      2      1           0.5       1        1     0.0%",
+				"testing    2      1           0.5       1        1     0.0%",
+				"app/app_test.go::TestSum (1 assertions, 1 weak)",
+				"• testing (1 weak assertions): Report failures with t.Errorf",
+				"t.Fail → t.Errorf(\"got %v, want %v\", got, want) (1)",
+				"app/app_test.go:7 [low] Weak Assertion",
+				"app/app_test.go:11-13 [medium] Test Without Assertions",
+			},
+			checkJSON: func(t *testing.T, stdout string) {
+				var report assertions.Report
+				decodeJSON(t, stdout, &report)
+
+				if report.Summary.Tests != 2 || len(report.Tests) != 2 || len(report.Findings) != 2 {
+					t.Errorf("report = %+v", report)
+				}
+			},
+		},
+		{
+			name: "flakiness",
+			run:  runTestsFlakiness,
+			json: &testsFlakinessJSON,
+			flags: func(cmd *cobra.Command) {
+				cmd.Flags().IntVar(&testsFlakinessTop, "top", 10, "number of riskiest tests")
+			},
+			files: map[string]string{
+				"app/app_test.go": `package app
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestExpiry(t *testing.T) {
+	go Expire()
+	time.Sleep(time.Second)
+}
+
+func TestMode(t *testing.T) {
+	os.Setenv("MODE", "test")
+}
+
+func TestSum(t *testing.T) {
+	if Sum(1, 2) != 3 {
+		t.Error("wrong sum")
+	}
+}
+`,
+			},
+			textArgs: []string{"--top", "1"},
+			wantText: []string{
+				"Tests: 3 (at risk: 2, high: 1, medium: 0, low: 1)",
+				"• concurrency   1",
+				"• global-state  1",
+				"Riskiest tests (1 of 2):",
+				"[60 high] app/app_test.go::TestExpiry",
+				"• app/app_test.go:10 concurrency: goroutine started without waiting for it",
+				"• app/app_test.go:11 sleep: time.Sleep(time.Second)",
+			},
+			absentText: []string{"TestMode"},
+			jsonArgs:   []string{"--top", "0"},
+			checkJSON: func(t *testing.T, stdout string) {
+				var report flakiness.Report
+				decodeJSON(t, stdout, &report)
+
+				if report.Summary.Tests != 3 || len(report.Tests) != 2 || report.Tests[1].Test != "app/app_test.go::TestMode" {
+					t.Errorf("report = %+v", report)
+				}
+			},
+		},
+		{
+			name: "fixtures",
+			run:  runTestsFixtures,
+			json: &testsFixturesJSON,
+			flags: func(cmd *cobra.Command) {
+				cmd.Flags().BoolVar(&testsFixturesTests, "tests", false, "list the fixtures of every test")
+			},
+			files: map[string]string{
+				"app/app_test.go": `package app
+
+import "testing"
+
+var calls []string
+
+func TestRecord(t *testing.T) {
+	calls = append(calls, "record")
+}
+
+func TestSum(t *testing.T) {
+	if Sum(1, 2) != 3 {
+		t.Error("wrong sum")
+	}
+}
+`,
+				"tests/conftest.py":   "import pytest\n\n@pytest.fixture(scope=\"module\")\ndef items():\n    return []\n",
+				"tests/test_items.py": "def test_adds(items):\n    items.append(1)\n",
+			},
+			textArgs: []string{"--tests"},
+			wantText: []string{
+				"Tests: 3 (with fixtures: 2, with global state: 1)",
+				"Fixtures: 2 (shared: 2)",
+				"app/app_test.go::calls",
+				"pytest-fixture    module mutable",
+				"app/app_test.go::TestRecord [global state]",
+				"    • tests/conftest.py::items",
+				"[medium] Global State Mutation",
+				"TestRecord changes calls without restoring it",
+				"[high] Mutable Shared Fixture",
+			},
+			checkJSON: func(t *testing.T, stdout string) {
+				var report fixtures.Report
+				decodeJSON(t, stdout, &report)
+
+				if report.Summary.Tests != 3 || len(report.Findings) != 2 || report.Findings[0].CheckID != fixtures.CheckGlobalStateMutation {
+					t.Errorf("report = %+v", report)
+				}
+			},
+		},
+	}
+}
+
+func TestTestsAnalysisCommands(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	for _, tt := range analysisCommandTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Run("reports as text", func(t *testing.T) {
+				resetRootCmd(t)
+
+				stdout := executeCmd(t, newAnalysisCmd(tt), append([]string{writeModule(t, tt.files)}, tt.textArgs...)...)
+
+				for _, want := range tt.wantText {
+					if !contains(stdout, want) {
+						t.Errorf("output missing %q:\n%s", want, stdout)
+					}
+				}
+
+				for _, absent := range tt.absentText {
+					if contains(stdout, absent) {
+						t.Errorf("output contains %q:\n%s", absent, stdout)
+					}
+				}
+			})
+
+			t.Run("outputs JSON", func(t *testing.T) {
+				resetRootCmd(t)
+
+				args := append([]string{writeModule(t, tt.files), "--json"}, tt.jsonArgs...)
+				tt.checkJSON(t, executeCmd(t, newAnalysisCmd(tt), args...))
+			})
+
+			t.Run("missing directory", func(t *testing.T) {
+				resetRootCmd(t)
+
+				cmd := newAnalysisCmd(tt)
+				cmd.SetArgs([]string{"/nonexistent/path"})
+
+				if err := cmd.Execute(); err == nil || !contains(err.Error(), "directory does not exist") {
+					t.Errorf("error = %v", err)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Ship Shape - Validate Detectors Command
+// Copyright (c) 2026 Ship Shape Contributors
+// Licensed under Apache License 2.0
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/chambridge/ship-shape/internal/groundtruth"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// defaultGroundTruthDir is the ground-truth tree of the Ship Shape repository.
+var defaultGroundTruthDir = filepath.Join("testdata", "ground-truth")
+
+var (
+	validateDetectorsJSON         bool
+	validateDetectorsSchema       string
+	validateDetectorsMinPrecision float64
+	validateDetectorsMinRecall    float64
+	validateDetectorsMismatches   bool
+)
+
+// validateDetectorsCmd represents the validate-detectors command
+var validateDetectorsCmd = &cobra.Command{
+	Use:   "validate-detectors [ground-truth-dir]",
+	Short: "Measure detector accuracy against ground-truth examples",
+	Long: `Walks the ground-truth tree (default: testdata/ground-truth), validates
+each metadata.yml against metadata.schema.yml, runs the matching detector
+over the example and compares its findings with the expected detections.
+
+Precision, recall and F1 are reported per smell, pattern and coverage
+format. The command fails when an example is invalid or a score is below
+the precision or recall target.
+
+Only the smells an example is annotated for are scored: its smell_type and
+the smells of its expected_detections. Findings listed under
+false_positives_expected are not counted as false positives.
+
+Example:
+  shipshape validate-detectors
+  shipshape validate-detectors testdata/ground-truth --mismatches
+  shipshape validate-detectors --min-precision 0.95 --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runValidateDetectors,
+}
+
+func init() {
+	rootCmd.AddCommand(validateDetectorsCmd)
+
+	validateDetectorsCmd.Flags().BoolVar(&validateDetectorsJSON, "json", false, "output in JSON format")
+	validateDetectorsCmd.Flags().StringVar(&validateDetectorsSchema, "schema", "",
+		"metadata schema (default: metadata.schema.yml in the ground-truth directory)")
+	validateDetectorsCmd.Flags().Float64Var(&validateDetectorsMinPrecision, "min-precision", groundtruth.DefaultTarget,
+		"minimum precision of each smell, pattern and coverage format")
+	validateDetectorsCmd.Flags().Float64Var(&validateDetectorsMinRecall, "min-recall", groundtruth.DefaultTarget,
+		"minimum recall of each smell, pattern and coverage format")
+	validateDetectorsCmd.Flags().BoolVar(&validateDetectorsMismatches, "mismatches", false,
+		"list every false positive and false negative")
+}
+
+func runValidateDetectors(_ *cobra.Command, args []string) error {
+	dir := defaultGroundTruthDir
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("directory does not exist: %s", dir)
+	}
+
+	schemaPath := validateDetectorsSchema
+	if schemaPath == "" {
+		schemaPath = filepath.Join(dir, groundtruth.SchemaFile)
+	}
+
+	schema, err := groundtruth.LoadSchema(schemaPath)
+	if err != nil {
+		return err
+	}
+
+	targets := groundtruth.Targets{Precision: validateDetectorsMinPrecision, Recall: validateDetectorsMinRecall}
+
+	logger.Info("Validating detectors", "directory", dir)
+
+	report, err := groundtruth.NewHarness(dir, schema, targets).Run()
+	if err != nil {
+		return err
+	}
+
+	if validateDetectorsJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode JSON: %w", err)
+		}
+	} else {
+		writeValidationText(os.Stdout, report, validateDetectorsMismatches)
+	}
+
+	if !report.Passed() {
+		failing := 0
+
+		for _, s := range report.Scores {
+			if !s.Passed {
+				failing++
+			}
+		}
+
+		return fmt.Errorf("detector validation failed: %d invalid examples, %d scores below target",
+			len(report.Problems), failing)
+	}
+
+	return nil
+}
+
+func writeValidationText(w io.Writer, report *groundtruth.Report, mismatches bool) {
+	fmt.Fprintf(w, "Ground truth: %s (%d examples, targets: precision ≥ %.0f%%, recall ≥ %.0f%%)\n\n",
+		report.Root, report.Examples, report.Targets.Precision*100, report.Targets.Recall*100)
+
+	if len(report.Scores) > 0 {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+		fmt.Fprintln(tw, "CATEGORY\tKEY\tEXAMPLES\tTP\tFP\tFN\tPRECISION\tRECALL\tF1\tSTATUS")
+
+		for _, s := range report.Scores {
+			status := "✓"
+			if !s.Passed {
+				status = "✗"
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%.1f%%\t%.1f%%\t%.1f%%\t%s\n",
+				s.Category, s.Key, s.Examples, s.TruePositives, s.FalsePositives, s.FalseNegatives,
+				s.Precision*100, s.Recall*100, s.F1*100, status)
+		}
+
+		_ = tw.Flush()
+	}
+
+	if len(report.Skipped) > 0 {
+		fmt.Fprintf(w, "\nSkipped %d examples without an evaluator:\n", len(report.Skipped))
+
+		for _, s := range report.Skipped {
+			fmt.Fprintf(w, "  • %s\n", s)
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		fmt.Fprintf(w, "\nInvalid examples:\n")
+
+		for _, p := range report.Problems {
+			fmt.Fprintf(w, "  • %s\n", p)
+		}
+	}
+
+	if mismatches && len(report.Mismatches) > 0 {
+		fmt.Fprintf(w, "\nMismatches:\n")
+
+		for _, m := range report.Mismatches {
+			fmt.Fprintf(w, "  • %s\n", m)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/groundtruth"
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/spf13/cobra"
+)
+
+func TestValidateDetectorsCommand(t *testing.T) {
+	// DO NOT run subtests in parallel - they share global command flag state
+	newCmd := func() *cobra.Command {
+		cmd := &cobra.Command{
+			Use:  "validate-detectors [ground-truth-dir]",
+			Args: cobra.MaximumNArgs(1),
+			RunE: runValidateDetectors,
+		}
+		cmd.Flags().BoolVar(&validateDetectorsJSON, "json", false, "output in JSON format")
+		cmd.Flags().StringVar(&validateDetectorsSchema, "schema", "", "metadata schema")
+		cmd.Flags().Float64Var(&validateDetectorsMinPrecision, "min-precision", groundtruth.DefaultTarget, "precision")
+		cmd.Flags().Float64Var(&validateDetectorsMinRecall, "min-recall", groundtruth.DefaultTarget, "recall")
+		cmd.Flags().BoolVar(&validateDetectorsMismatches, "mismatches", false, "list mismatches")
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		return cmd
+	}
+
+	repoGroundTruth := filepath.Join("..", "..", "testdata", "ground-truth")
+
+	// writeGroundTruth creates a tree whose only example expects a smell in
+	// a test that does not exist.
+	writeGroundTruth := func(t *testing.T) string {
+		t.Helper()
+
+		dir := testutil.TempDir(t)
+		testutil.WriteFile(t, dir, "sleepy/app_test.go", `package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaits(t *testing.T) {
+	time.Sleep(time.Second)
+}
+`)
+		testutil.WriteFile(t, dir, "sleepy/metadata.yml", `version: "1.0.0"
+language: go
+category: test-smell
+smell_type: flakiness
+description: "Sleeps"
+verified_by: ["a@example.com", "b@example.com"]
+verified_date: "2026-10-18"
+expected_detections:
+  - type: test-smell
+    smell: flakiness
+    file: "app_test.go"
+    function: "TestOther"
+    reason: "wrong test"
+`)
+
+		return dir
+	}
+
+	t.Run("repository ground truth meets the targets", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{repoGroundTruth})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("validate-detectors failed: %v", err)
+			}
+		})
+
+		for _, want := range []string{"targets: precision ≥ 90%, recall ≥ 90%", "table-driven", "eager-test", "go-cover", "✓"} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("fails below target and lists mismatches", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{writeGroundTruth(t), "--schema", filepath.Join(repoGroundTruth, groundtruth.SchemaFile), "--mismatches"})
+
+		var err error
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			err = cmd.Execute()
+		})
+
+		if err == nil || !contains(err.Error(), "0 invalid examples, 1 scores below target") {
+			t.Errorf("error = %v", err)
+		}
+
+		for _, want := range []string{"✗", "missed flakiness in app_test.go (TestOther)", "unexpected flakiness at app_test.go:9"} {
+			if !contains(stdout, want) {
+				t.Errorf("output missing %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("outputs JSON", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{writeGroundTruth(t), "--json", "--schema", filepath.Join(repoGroundTruth, groundtruth.SchemaFile),
+			"--min-precision", "0", "--min-recall", "0"})
+
+		stdout, _ := testutil.CaptureOutput(t, func() {
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("validate-detectors failed: %v", err)
+			}
+		})
+
+		var report groundtruth.Report
+		if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+		}
+
+		if report.Examples != 1 || len(report.Scores) != 1 || report.Scores[0].FalseNegatives != 1 {
+			t.Errorf("report = %+v", report)
+		}
+	})
+
+	t.Run("missing schema", func(t *testing.T) {
+		resetRootCmd(t)
+
+		cmd := newCmd()
+		cmd.SetArgs([]string{testutil.TempDir(t)})
+
+		if err := cmd.Execute(); err == nil || !contains(err.Error(), "failed to read schema") {
+			t.Errorf("error = %v", err)
+		}
+	})
+}
@@ -0,0 +1,360 @@
+// Package assertions measures the quality of test assertions.
+//
+// Assertion counts and weak assertions are read from the test inventory (see
+// package inventory) and aggregated per package and per framework. Weak
+// assertions, such as assert x or assertTrue(a == b), and tests without
+// assertions are reported as findings, with recommendations for the specific
+// assertions of each framework.
+package assertions
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Check IDs of the findings reported by the analyzer.
+const (
+	CheckWeakAssertion    = "weak-assertion"
+	CheckMissingAssertion = "missing-assertion"
+)
+
+// Stats aggregates the assertions of a group of tests.
+type Stats struct {
+	// Name is the package directory or framework of the group
+	Name string `json:"name"`
+
+	// Language is the language of a framework group
+	Language types.Language `json:"language,omitempty"`
+
+	// Tests is the number of runnable tests
+	Tests int `json:"tests"`
+
+	// Assertions is the number of assertions of the tests
+	Assertions int `json:"assertions"`
+
+	// AssertionsPerTest is Assertions / Tests
+	AssertionsPerTest float64 `json:"assertions_per_test"`
+
+	// WithoutAssertions is the number of tests without any assertion
+	WithoutAssertions int `json:"without_assertions"`
+
+	// WeakAssertions is the number of weak assertions
+	WeakAssertions int `json:"weak_assertions"`
+
+	// SpecificPercentage is the percentage of assertions that are not weak
+	SpecificPercentage float64 `json:"specific_percentage"`
+}
+
+// TestAssertions is the assertion count of a single test.
+type TestAssertions struct {
+	// Test is the ID of the test
+	Test string `json:"test"`
+
+	// File is the test file, relative to the repository root
+	File string `json:"file"`
+
+	// Line is the line where the test is declared
+	Line int `json:"line"`
+
+	// Framework is the test framework of the file
+	Framework string `json:"framework"`
+
+	// Assertions is the number of assertions in the test body
+	Assertions int `json:"assertions"`
+
+	// WeakAssertions are the weak assertions of the test
+	WeakAssertions []types.WeakAssertion `json:"weak_assertions,omitempty"`
+}
+
+// Replacement is a weak assertion and the specific assertion to use instead.
+type Replacement struct {
+	// Assertion is the weak assertion as written
+	Assertion string `json:"assertion"`
+
+	// Suggestion is the specific assertion to use instead
+	Suggestion string `json:"suggestion"`
+
+	// Count is the number of occurrences
+	Count int `json:"count"`
+}
+
+// Recommendation suggests the specific assertions of a framework.
+type Recommendation struct {
+	// Framework is the test framework the recommendation applies to
+	Framework string `json:"framework"`
+
+	// WeakAssertions is the number of weak assertions written with the framework
+	WeakAssertions int `json:"weak_assertions"`
+
+	// Summary describes the assertions to prefer
+	Summary string `json:"summary"`
+
+	// Replacements are the weak assertions found, most frequent first
+	Replacements []Replacement `json:"replacements"`
+}
+
+// Report is the result of an assertion analysis.
+type Report struct {
+	// Summary aggregates every test
+	Summary Stats `json:"summary"`
+
+	// Packages aggregate the tests of each directory, sorted by name
+	Packages []Stats `json:"packages"`
+
+	// Frameworks aggregate the tests of each framework, sorted by name
+	Frameworks []Stats `json:"frameworks"`
+
+	// Tests are the assertion counts of every test, in inventory order
+	Tests []TestAssertions `json:"tests"`
+
+	// Recommendations suggest specific assertions for the frameworks with
+	// weak assertions, sorted by framework
+	Recommendations []Recommendation `json:"recommendations"`
+
+	// Findings report weak assertions and tests without assertions
+	Findings []types.Finding `json:"findings"`
+}
+
+// frameworkAdvice is the assertion style recommended for each framework.
+var frameworkAdvice = map[string]string{
+	"testing":  "Report failures with t.Errorf and the got and want values, or use testify's assert.Equal",
+	"testify":  "Use testify's specific assertions, such as Equal, Nil, Contains and ErrorIs, instead of True and False",
+	"pytest":   "Compare values in assert statements (assert actual == expected) so pytest shows both sides",
+	"unittest": "Use specific unittest assertions such as assertEqual, assertIn and assertIsNone",
+	"jest":     "Use specific matchers such as toBe, toEqual, toContain and toBeNull instead of toBeTruthy",
+	"vitest":   "Use specific matchers such as toBe, toEqual, toContain and toBeNull instead of toBeTruthy",
+	"mocha":    "Use specific Chai assertions such as expect(actual).to.equal(expected) instead of ok and true",
+	"junit5":   "Use AssertJ's assertThat(actual) with specific matchers, or assertEquals, instead of assertTrue",
+	"junit4":   "Use AssertJ's assertThat(actual) with specific matchers, or assertEquals, instead of assertTrue",
+	"junit3":   "Use assertEquals and assertNull, or AssertJ's assertThat(actual), instead of assertTrue",
+	"testng":   "Use AssertJ's assertThat(actual) with specific matchers, or assertEquals, instead of assertTrue",
+}
+
+// defaultAdvice is recommended for frameworks without specific advice.
+const defaultAdvice = "Use the specific assertions of the framework instead of checking booleans"
+
+// weakDescriptions explain each kind of weak assertion; the arguments are
+// the assertion and the suggestion.
+var weakDescriptions = map[types.WeakAssertionKind]string{
+	types.WeakTruthiness:        "%s only checks that the value is truthy; %s shows the expected and actual values",
+	types.WeakBooleanComparison: "%s checks a comparison as a boolean and hides both values; %s shows them",
+	types.WeakMissingMessage:    "%s fails without saying what went wrong; %s explains the failure",
+}
+
+// Analyzer measures assertion quality in a test inventory.
+type Analyzer struct{}
+
+// NewAnalyzer creates an assertion analyzer.
+func NewAnalyzer() *Analyzer {
+	return &Analyzer{}
+}
+
+// Analyze aggregates the assertions of every runnable test of the inventory.
+// Skipped and todo tests, benchmarks, fuzz targets and examples are not
+// measured. Assertions of a test with subtests count towards the totals,
+// but only tests without subtests are counted as tests.
+func (a *Analyzer) Analyze(inv *types.TestInventory) *Report {
+	report := &Report{
+		Summary:         Stats{Name: "total"},
+		Packages:        []Stats{},
+		Frameworks:      []Stats{},
+		Tests:           []TestAssertions{},
+		Recommendations: []Recommendation{},
+		Findings:        []types.Finding{},
+	}
+
+	packages := make(map[string]*Stats)
+	frameworks := make(map[string]*Stats)
+	replacements := make(map[string]map[Replacement]int)
+
+	for i := range inv.Files {
+		file := &inv.Files[i]
+		dir := path.Dir(file.Path)
+
+		if packages[dir] == nil {
+			packages[dir] = &Stats{Name: dir}
+		}
+
+		if frameworks[file.Framework] == nil {
+			frameworks[file.Framework] = &Stats{Name: file.Framework, Language: file.Language}
+		}
+
+		groups := []*Stats{&report.Summary, packages[dir], frameworks[file.Framework]}
+
+		for _, tc := range file.EffectiveLeaves() {
+			if !isMeasured(&tc) {
+				continue
+			}
+
+			counted := len(tc.Children) == 0
+
+			for _, s := range groups {
+				s.add(&tc, counted)
+			}
+
+			if counted {
+				report.Tests = append(report.Tests, TestAssertions{
+					Test:           tc.ID,
+					File:           file.Path,
+					Line:           tc.Line,
+					Framework:      file.Framework,
+					Assertions:     tc.Assertions,
+					WeakAssertions: tc.WeakAssertions,
+				})
+
+				if tc.Assertions == 0 {
+					report.Findings = append(report.Findings, missingAssertion(file.Path, &tc))
+				}
+			}
+
+			for _, wa := range tc.WeakAssertions {
+				if replacements[file.Framework] == nil {
+					replacements[file.Framework] = make(map[Replacement]int)
+				}
+
+				replacements[file.Framework][Replacement{Assertion: wa.Assertion, Suggestion: wa.Suggestion}]++
+				report.Findings = append(report.Findings, weakAssertion(file.Path, &tc, wa))
+			}
+		}
+	}
+
+	report.Summary.finish()
+	report.Packages = sortedStats(packages)
+	report.Frameworks = sortedStats(frameworks)
+
+	for _, s := range report.Frameworks {
+		if s.WeakAssertions > 0 {
+			report.Recommendations = append(report.Recommendations, recommend(s, replacements[s.Name]))
+		}
+	}
+
+	sort.SliceStable(report.Findings, func(i, j int) bool {
+		if report.Findings[i].Location.File != report.Findings[j].Location.File {
+			return report.Findings[i].Location.File < report.Findings[j].Location.File
+		}
+
+		return report.Findings[i].Location.StartLine < report.Findings[j].Location.StartLine
+	})
+
+	return report
+}
+
+// isMeasured reports whether a test is expected to assert.
+func isMeasured(tc *types.TestCase) bool {
+	return !tc.Skipped && !tc.Todo && (tc.Kind == types.TestKindTest || tc.Kind == types.TestKindParameterized)
+}
+
+func (s *Stats) add(tc *types.TestCase, counted bool) {
+	s.Assertions += tc.Assertions
+	s.WeakAssertions += len(tc.WeakAssertions)
+
+	if counted {
+		s.Tests++
+
+		if tc.Assertions == 0 {
+			s.WithoutAssertions++
+		}
+	}
+}
+
+// finish computes the ratios of the group.
+func (s *Stats) finish() {
+	if s.Tests > 0 {
+		s.AssertionsPerTest = float64(s.Assertions) / float64(s.Tests)
+	}
+
+	if s.Assertions > 0 {
+		s.SpecificPercentage = float64(s.Assertions-s.WeakAssertions) * 100 / float64(s.Assertions)
+	}
+}
+
+// sortedStats returns the groups with tests, sorted by name.
+func sortedStats(groups map[string]*Stats) []Stats {
+	stats := make([]Stats, 0, len(groups))
+
+	for _, s := range groups {
+		if s.Tests == 0 && s.Assertions == 0 {
+			continue
+		}
+
+		s.finish()
+		stats = append(stats, *s)
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+
+	return stats
+}
+
+// recommend builds the recommendation of a framework with weak assertions.
+func recommend(s Stats, counts map[Replacement]int) Recommendation {
+	advice, ok := frameworkAdvice[s.Name]
+	if !ok {
+		advice = defaultAdvice
+	}
+
+	rec := Recommendation{Framework: s.Name, WeakAssertions: s.WeakAssertions, Summary: advice}
+
+	for r, n := range counts {
+		r.Count = n
+		rec.Replacements = append(rec.Replacements, r)
+	}
+
+	sort.Slice(rec.Replacements, func(i, j int) bool {
+		a, b := rec.Replacements[i], rec.Replacements[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+
+		if a.Assertion != b.Assertion {
+			return a.Assertion < b.Assertion
+		}
+
+		return a.Suggestion < b.Suggestion
+	})
+
+	return rec
+}
+
+// weakAssertion reports a weak assertion of a test.
+func weakAssertion(file string, tc *types.TestCase, wa types.WeakAssertion) types.Finding {
+	return types.Finding{
+		CheckID:     CheckWeakAssertion,
+		Type:        types.FindingTypeQuality,
+		Severity:    types.SeverityLow,
+		Title:       "Weak Assertion",
+		Description: fmt.Sprintf(weakDescriptions[wa.Kind], wa.Assertion, wa.Suggestion),
+		Rationale:   "Specific assertions report the expected and actual values, so a failure can be diagnosed without rerunning the test.",
+		Location:    types.Location{File: file, StartLine: wa.Line, EndLine: wa.Line},
+		Test:        tc.ID,
+		Remediation: &types.Remediation{
+			Summary: "Replace " + wa.Assertion + " with " + wa.Suggestion,
+			Effort:  types.EffortMinimal,
+		},
+	}
+}
+
+// missingAssertion reports a test without assertions.
+func missingAssertion(file string, tc *types.TestCase) types.Finding {
+	return types.Finding{
+		CheckID:     CheckMissingAssertion,
+		Type:        types.FindingTypeQuality,
+		Severity:    types.SeverityMedium,
+		Title:       "Test Without Assertions",
+		Description: fmt.Sprintf("%s has no assertions, so it only fails if the code under test panics or throws", tc.Name),
+		Rationale:   "A test without assertions does not check the behavior it exercises and keeps passing when that behavior breaks.",
+		Location:    types.Location{File: file, StartLine: tc.Line, EndLine: tc.EndLine},
+		Test:        tc.ID,
+		Remediation: &types.Remediation{
+			Summary: "Assert on the result or side effects of the code under test",
+			Steps: []string{
+				"Check the values returned or the state changed by the code under test",
+				"When the test only checks that no error occurs, assert it explicitly (require.NoError, expect(fn).not.toThrow())",
+			},
+			Effort: types.EffortLow,
+		},
+	}
+}
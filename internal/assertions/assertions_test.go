@@ -0,0 +1,168 @@
+package assertions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const goTests = `package orders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTotal(t *testing.T) {
+	assert.Equal(t, 3, Total())
+	assert.True(t, Total() == 3)
+}
+
+func TestPrices(t *testing.T) {
+	for _, tt := range []struct{ name string }{{"a"}, {"b"}} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NotEmpty(t, Price(tt.name))
+		})
+	}
+}
+
+func TestPlace(t *testing.T) {
+	Place()
+}
+
+func TestSkipped(t *testing.T) {
+	t.Skip("later")
+}
+
+func BenchmarkTotal(b *testing.B) {}
+`
+
+const pythonTests = `def test_load():
+    assert load()
+    assert load() == 1
+
+
+def test_save():
+    assert save() is True
+`
+
+// parse builds an inventory from test sources keyed by path.
+func parse(t *testing.T, files map[string]string) *types.TestInventory {
+	t.Helper()
+
+	parsers := map[string]inventory.Parser{
+		".go": inventory.NewGoParser(),
+		".py": inventory.NewPythonParser(),
+	}
+
+	inv := &types.TestInventory{}
+
+	for _, p := range []string{"orders/orders_test.go", "tests/test_store.py"} {
+		file, err := parsers[p[strings.LastIndex(p, "."):]].Parse(p, []byte(files[p]))
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", p, err)
+		}
+
+		inv.Files = append(inv.Files, *file)
+	}
+
+	return inv
+}
+
+func TestAnalyze(t *testing.T) {
+	report := NewAnalyzer().Analyze(parse(t, map[string]string{
+		"orders/orders_test.go": goTests,
+		"tests/test_store.py":   pythonTests,
+	}))
+
+	if s := report.Summary; s.Tests != 5 || s.Assertions != 6 || s.WithoutAssertions != 1 || s.WeakAssertions != 2 {
+		t.Errorf("summary = %+v", s)
+	}
+
+	if got := report.Summary.SpecificPercentage; got < 66.6 || got > 66.7 {
+		t.Errorf("specific percentage = %v, want 66.7", got)
+	}
+
+	wantPackages := []Stats{
+		{Name: "orders", Tests: 3, Assertions: 3, AssertionsPerTest: 1, WithoutAssertions: 1, WeakAssertions: 1},
+		{Name: "tests", Tests: 2, Assertions: 3, AssertionsPerTest: 1.5, WeakAssertions: 1},
+	}
+
+	if len(report.Packages) != len(wantPackages) {
+		t.Fatalf("packages = %+v", report.Packages)
+	}
+
+	for i, want := range wantPackages {
+		got := report.Packages[i]
+		got.SpecificPercentage = 0
+
+		if got != want {
+			t.Errorf("package %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	if len(report.Frameworks) != 2 || report.Frameworks[0].Name != "pytest" || report.Frameworks[0].Language != types.LanguagePython ||
+		report.Frameworks[1].Name != "testify" || report.Frameworks[1].WeakAssertions != 1 {
+		t.Errorf("frameworks = %+v", report.Frameworks)
+	}
+
+	var tests []string
+	for _, ta := range report.Tests {
+		tests = append(tests, ta.Test)
+	}
+
+	want := "orders/orders_test.go::TestTotal, orders/orders_test.go::TestPrices::tt.name, orders/orders_test.go::TestPlace, " +
+		"tests/test_store.py::test_load, tests/test_store.py::test_save"
+	if strings.Join(tests, ", ") != want {
+		t.Errorf("tests = %s, want %s", strings.Join(tests, ", "), want)
+	}
+
+	if len(report.Recommendations) != 2 {
+		t.Fatalf("recommendations = %+v", report.Recommendations)
+	}
+
+	rec := report.Recommendations[1]
+	if rec.Framework != "testify" || !strings.Contains(rec.Summary, "Equal") || len(rec.Replacements) != 1 ||
+		rec.Replacements[0] != (Replacement{Assertion: "assert.True", Suggestion: "assert.Equal", Count: 1}) {
+		t.Errorf("recommendation = %+v", rec)
+	}
+}
+
+func TestAnalyzeFindings(t *testing.T) {
+	report := NewAnalyzer().Analyze(parse(t, map[string]string{
+		"orders/orders_test.go": goTests,
+		"tests/test_store.py":   pythonTests,
+	}))
+
+	var got []string
+	for _, f := range report.Findings {
+		got = append(got, f.CheckID+" "+f.Location.String()+" "+f.Test)
+	}
+
+	want := []string{
+		"weak-assertion orders/orders_test.go:11 orders/orders_test.go::TestTotal",
+		"missing-assertion orders/orders_test.go:22-24 orders/orders_test.go::TestPlace",
+		"weak-assertion tests/test_store.py:2 tests/test_store.py::test_load",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	f := report.Findings[0]
+	if f.Description != "assert.True checks a comparison as a boolean and hides both values; assert.Equal shows them" ||
+		f.Remediation == nil || f.Remediation.Summary != "Replace assert.True with assert.Equal" {
+		t.Errorf("finding = %+v", f)
+	}
+}
+
+func TestAnalyzeEmptyInventory(t *testing.T) {
+	report := NewAnalyzer().Analyze(&types.TestInventory{})
+
+	if report.Summary.Tests != 0 || len(report.Packages) != 0 || len(report.Findings) != 0 {
+		t.Errorf("report = %+v", report)
+	}
+}
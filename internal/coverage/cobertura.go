@@ -0,0 +1,386 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// coberturaFile accumulates the classes of a Cobertura report that belong to
+// one source file.
+type coberturaFile struct {
+	name      string
+	pkg       string
+	hits      map[int]int64
+	branches  map[int][]Branch
+	functions []Function
+}
+
+// line records the hits of a line; lines repeated by several classes keep
+// their highest count.
+func (f *coberturaFile) line(number int, hits int64) {
+	if h, ok := f.hits[number]; !ok || hits > h {
+		f.hits[number] = hits
+	}
+}
+
+// branch records the outcomes of a line, keeping the most detailed or most
+// covered outcomes of lines repeated by several classes.
+func (f *coberturaFile) branch(number int, outcomes []Branch) {
+	existing := f.branches[number]
+
+	switch {
+	case len(outcomes) > len(existing):
+		f.branches[number] = outcomes
+	case len(outcomes) == len(existing):
+		for i := range existing {
+			existing[i].Hits = max(existing[i].Hits, outcomes[i].Hits)
+		}
+	}
+}
+
+// coberturaLine is the <line> element being decoded.
+type coberturaLine struct {
+	number     int
+	hits       int64
+	covered    int
+	total      int
+	conditions []coberturaCondition
+}
+
+// coberturaCondition is a <condition> element of a line.
+type coberturaCondition struct {
+	number  int
+	kind    string
+	percent float64
+}
+
+// CoberturaParser parses Cobertura XML reports, as written by coverage.py,
+// Cobertura, coverlet and most CI converters. The report is decoded as a
+// token stream, so only the per-file line data is held in memory however
+// large the document is. Classes of the same file are merged, and file
+// names are resolved against the <sources> of the report and the
+// repository root.
+type CoberturaParser struct {
+	paths resolver
+	dir   string
+}
+
+// NewCoberturaParser creates a parser resolving file names relative to the
+// repository root. Relative sources, and file names when the report has no
+// sources, are taken relative to dir, the directory the tests ran in
+// relative to the root; an empty dir means the root.
+func NewCoberturaParser(root, dir string) *CoberturaParser {
+	return &CoberturaParser{paths: resolver{root: root}, dir: dir}
+}
+
+// Format returns FormatCobertura.
+func (p *CoberturaParser) Format() Format {
+	return FormatCobertura
+}
+
+// Parse reads a Cobertura report. Line hits come from the <line> elements
+// of each class; the lines of its methods only describe the methods.
+// Branch outcomes come from the condition-coverage attribute of a line and
+// are split into one block per <condition> when the conditions account for
+// every outcome. Cobertura records how many outcomes were taken but not how
+// often, so taken outcomes have one hit.
+//
+//nolint:gocognit,gocyclo // Streaming decoder over the nested report elements
+func (p *CoberturaParser) Parse(r io.Reader) (*Report, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		seen    bool
+		sources []string
+		source  *strings.Builder
+		pkg     string
+		files   = make(map[string]*coberturaFile)
+		file    *coberturaFile
+		class   string
+		method  *Function
+		line    *coberturaLine
+	)
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Cobertura report: %w", err)
+		}
+
+		pos, _ := dec.InputPos()
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "coverage":
+				seen = true
+			case "source":
+				source = &strings.Builder{}
+			case "package":
+				pkg = xmlAttr(t, "name")
+			case "class":
+				name := xmlAttr(t, "filename")
+				if name == "" {
+					return nil, fmt.Errorf("line %d: class %q has no filename", pos, xmlAttr(t, "name"))
+				}
+
+				file = files[name]
+				if file == nil {
+					file = &coberturaFile{name: name, pkg: pkg, hits: make(map[int]int64), branches: make(map[int][]Branch)}
+					files[name] = file
+				}
+
+				class = xmlAttr(t, "name")
+			case "method":
+				if file != nil {
+					method = &Function{Name: coberturaMethodName(class, xmlAttr(t, "name"))}
+				}
+			case "line":
+				if file == nil {
+					continue
+				}
+
+				l, err := parseCoberturaLine(t)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", pos, err)
+				}
+
+				line = &l
+			case "condition":
+				if line != nil {
+					line.conditions = append(line.conditions, parseCoberturaCondition(t))
+				}
+			}
+		case xml.CharData:
+			if source != nil {
+				source.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "source":
+				if s := strings.TrimSpace(source.String()); s != "" {
+					sources = append(sources, s)
+				}
+
+				source = nil
+			case "package":
+				pkg = ""
+			case "class":
+				file, class = nil, ""
+			case "method":
+				if method != nil && method.Line > 0 {
+					file.functions = append(file.functions, *method)
+				}
+
+				method = nil
+			case "line":
+				if line == nil {
+					continue
+				}
+
+				if method != nil {
+					if method.Line == 0 || line.number < method.Line {
+						method.Line = line.number
+					}
+
+					method.EndLine = max(method.EndLine, line.number)
+					method.Hits = max(method.Hits, line.hits)
+				} else {
+					file.line(line.number, line.hits)
+
+					if line.total > 0 {
+						file.branch(line.number, line.outcomes())
+					}
+				}
+
+				line = nil
+			}
+		}
+	}
+
+	if !seen {
+		return nil, fmt.Errorf("missing coverage element")
+	}
+
+	report := make([]*File, 0, len(files))
+	for _, f := range files {
+		report = append(report, p.file(f, sources))
+	}
+
+	return NewReport(FormatCobertura, report), nil
+}
+
+// parseCoberturaLine parses the attributes of a <line> element.
+func parseCoberturaLine(t xml.StartElement) (coberturaLine, error) {
+	var l coberturaLine
+
+	number, err := strconv.Atoi(xmlAttr(t, "number"))
+	if err != nil || number <= 0 {
+		return l, fmt.Errorf("invalid line number %q", xmlAttr(t, "number"))
+	}
+
+	hits, err := strconv.ParseInt(xmlAttr(t, "hits"), 10, 64)
+	if err != nil || hits < 0 {
+		return l, fmt.Errorf("invalid hits %q on line %d", xmlAttr(t, "hits"), number)
+	}
+
+	l.number, l.hits = number, hits
+
+	if cc := xmlAttr(t, "condition-coverage"); cc != "" && xmlAttr(t, "branch") != "false" {
+		// The attribute reads "50% (1/2)".
+		i := strings.IndexByte(cc, '(')
+		if i < 0 {
+			return l, fmt.Errorf("invalid condition coverage %q on line %d", cc, number)
+		}
+
+		if _, err := fmt.Sscanf(cc[i:], "(%d/%d)", &l.covered, &l.total); err != nil || l.covered > l.total {
+			return l, fmt.Errorf("invalid condition coverage %q on line %d", cc, number)
+		}
+	}
+
+	return l, nil
+}
+
+// parseCoberturaCondition parses the attributes of a <condition> element.
+// Unreadable attributes leave the condition unusable for splitting the
+// outcomes of its line.
+func parseCoberturaCondition(t xml.StartElement) coberturaCondition {
+	c := coberturaCondition{number: -1, kind: xmlAttr(t, "type"), percent: -1}
+
+	if n, err := strconv.Atoi(xmlAttr(t, "number")); err == nil {
+		c.number = n
+	}
+
+	if pct, err := strconv.ParseFloat(strings.TrimSuffix(xmlAttr(t, "coverage"), "%"), 64); err == nil {
+		c.percent = pct
+	}
+
+	return c
+}
+
+// outcomes returns the branch outcomes of a line. Jump conditions have two
+// outcomes each; when the jump conditions of the line account for all of
+// its outcomes, every condition becomes a block of its own.
+func (l *coberturaLine) outcomes() []Branch {
+	if blocks := l.conditionOutcomes(); blocks != nil {
+		return blocks
+	}
+
+	outcomes := make([]Branch, l.total)
+	for i := range outcomes {
+		outcomes[i] = Branch{Line: l.number, Branch: i}
+		if i < l.covered {
+			outcomes[i].Hits = 1
+		}
+	}
+
+	return outcomes
+}
+
+// conditionOutcomes splits the outcomes of a line by condition, or returns
+// nil when the conditions do not describe them.
+func (l *coberturaLine) conditionOutcomes() []Branch {
+	if len(l.conditions) == 0 || 2*len(l.conditions) != l.total {
+		return nil
+	}
+
+	var (
+		outcomes []Branch
+		covered  int
+	)
+
+	for _, c := range l.conditions {
+		if c.kind != "jump" || c.number < 0 || c.percent < 0 {
+			return nil
+		}
+
+		taken := int(math.Round(c.percent * 2 / 100))
+		covered += taken
+
+		for i := range 2 {
+			b := Branch{Line: l.number, Block: c.number, Branch: i}
+			if i < taken {
+				b.Hits = 1
+			}
+
+			outcomes = append(outcomes, b)
+		}
+	}
+
+	if covered != l.covered {
+		return nil
+	}
+
+	return outcomes
+}
+
+// file builds the coverage of a file from its merged classes.
+func (p *CoberturaParser) file(cf *coberturaFile, sources []string) *File {
+	f := &File{Path: p.paths.resolve(cf.name, p.bases(sources)), Package: cf.pkg, Functions: cf.functions}
+	if f.Package == "." {
+		f.Package = ""
+	}
+
+	for number, hits := range cf.hits {
+		f.Lines = append(f.Lines, Line{Number: number, Hits: hits})
+	}
+
+	for _, outcomes := range cf.branches {
+		f.Branches = append(f.Branches, outcomes...)
+	}
+
+	return f
+}
+
+// bases returns the base directories of file names: the sources of the
+// report, relative ones taken under the test directory, then the test
+// directory itself.
+func (p *CoberturaParser) bases(sources []string) []string {
+	dirs := baseDirs(p.dir)
+
+	var bases []string
+
+	for _, source := range sources {
+		if len(dirs) > 0 && !isAbsSlash(strings.ReplaceAll(source, `\`, "/")) {
+			source = path.Join(dirs[0], source)
+		}
+
+		bases = append(bases, source)
+	}
+
+	return append(bases, dirs...)
+}
+
+// coberturaMethodName qualifies a method by the simple name of its class.
+func coberturaMethodName(class, method string) string {
+	if class == "" {
+		return method
+	}
+
+	if i := strings.LastIndexByte(class, '.'); i >= 0 {
+		class = class[i+1:]
+	}
+
+	return class + "." + method
+}
+
+// xmlAttr returns the value of an attribute of an element, or "".
+func xmlAttr(t xml.StartElement, name string) string {
+	for _, a := range t.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
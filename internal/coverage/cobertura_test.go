@@ -0,0 +1,230 @@
+package coverage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+func TestCoberturaParserCoveragePy(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "src/shop/cart.py", "")
+	testutil.WriteFile(t, dir, "src/shop/__init__.py", "")
+
+	report := `<?xml version="1.0" ?>
+<coverage version="7.4.0" timestamp="1700000000000" lines-valid="6" lines-covered="4" line-rate="0.6667" branches-covered="1" branches-valid="2" branch-rate="0.5" complexity="0">
+	<!-- Generated by coverage.py -->
+	<sources>
+		<source>/home/runner/work/shop/shop/src</source>
+	</sources>
+	<packages>
+		<package name="shop" line-rate="0.6667" branch-rate="0.5" complexity="0">
+			<classes>
+				<class name="__init__.py" filename="shop/__init__.py" complexity="0" line-rate="1" branch-rate="1">
+					<methods/>
+					<lines>
+						<line number="1" hits="1"/>
+					</lines>
+				</class>
+				<class name="cart.py" filename="shop/cart.py" complexity="0" line-rate="0.6" branch-rate="0.5">
+					<methods/>
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="3" hits="1"/>
+						<line number="4" hits="2" branch="true" condition-coverage="50% (1/2)" missing-branches="6"/>
+						<line number="5" hits="0"/>
+						<line number="6" hits="0"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+
+	got, err := NewCoberturaParser(dir, "").Parse(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(got.Files) != 2 || got.Files[0].Path != "src/shop/__init__.py" || got.Files[1].Path != "src/shop/cart.py" {
+		t.Fatalf("Files = %+v, want the files resolved by suffix under the root", got.Files)
+	}
+
+	want := Metrics{Lines: Counter{Covered: 4, Total: 6}, Branches: Counter{Covered: 1, Total: 2}}
+	if got.Metrics != want {
+		t.Errorf("Metrics = %+v, want %+v", got.Metrics, want)
+	}
+
+	if cart := got.Files[1]; cart.Package != "shop" || len(cart.Branches) != 2 || cart.Branches[0] != (Branch{Line: 4, Branch: 0, Hits: 1}) {
+		t.Errorf("cart.py = %+v", cart)
+	}
+}
+
+func TestCoberturaParserRelativeSource(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "services/api/app/x.py", "")
+
+	report := `<?xml version="1.0" ?>
+<coverage version="7.4.0">
+	<sources>
+		<source>.</source>
+	</sources>
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="x.py" filename="app/x.py">
+					<lines>
+						<line number="1" hits="1"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+
+	got, err := NewCoberturaParser(dir, "services/api").Parse(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(got.Files) != 1 || got.Files[0].Path != "services/api/app/x.py" {
+		t.Errorf("Files = %+v, want the relative source taken under the test directory", got.Files)
+	}
+}
+
+func TestCoberturaParserJava(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "shop/src/main/java/com/example/Cart.java", "")
+
+	report := `<?xml version="1.0"?>
+<!DOCTYPE coverage SYSTEM "http://cobertura.sourceforge.net/xml/coverage-04.dtd">
+<coverage line-rate="0.5" branch-rate="0.25" version="2.1.1">
+	<sources>
+		<source>` + filepath.ToSlash(filepath.Join(dir, "shop", "src", "main", "java")) + `</source>
+		<source>--source</source>
+	</sources>
+	<packages>
+		<package name="com.example">
+			<classes>
+				<class name="com.example.Cart" filename="com/example/Cart.java">
+					<methods>
+						<method name="add" signature="(I)V" line-rate="1.0" branch-rate="0.5">
+							<lines>
+								<line number="10" hits="3" branch="false"/>
+								<line number="11" hits="3" branch="true" condition-coverage="25% (1/4)"/>
+							</lines>
+						</method>
+						<method name="clear" signature="()V" line-rate="0.0">
+							<lines>
+								<line number="20" hits="0" branch="false"/>
+							</lines>
+						</method>
+					</methods>
+					<lines>
+						<line number="10" hits="3" branch="false"/>
+						<line number="11" hits="3" branch="true" condition-coverage="25% (1/4)">
+							<conditions>
+								<condition number="0" type="jump" coverage="50%"/>
+								<condition number="1" type="jump" coverage="0%"/>
+							</conditions>
+						</line>
+						<line number="20" hits="0" branch="false"/>
+					</lines>
+				</class>
+				<class name="com.example.Cart$Item" filename="com/example/Cart.java">
+					<methods/>
+					<lines>
+						<line number="11" hits="5" branch="false"/>
+						<line number="30" hits="0" branch="false"/>
+					</lines>
+				</class>
+				<class name="com.example.Gone" filename="com/example/Gone.java">
+					<lines>
+						<line number="1" hits="1"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>
+`
+
+	got, err := NewCoberturaParser(dir, "").Parse(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	cart := got.File("shop/src/main/java/com/example/Cart.java")
+	if cart == nil {
+		t.Fatalf("Files = %+v, want Cart.java resolved through the absolute source", got.Files)
+	}
+
+	wantLines := []Line{{Number: 10, Hits: 3}, {Number: 11, Hits: 5}, {Number: 20, Hits: 0}, {Number: 30, Hits: 0}}
+	if len(cart.Lines) != len(wantLines) {
+		t.Fatalf("Lines = %+v, want %+v", cart.Lines, wantLines)
+	}
+
+	for i, l := range wantLines {
+		if cart.Lines[i] != l {
+			t.Errorf("Lines[%d] = %+v, want %+v", i, cart.Lines[i], l)
+		}
+	}
+
+	wantBranches := []Branch{
+		{Line: 11, Block: 0, Branch: 0, Hits: 1},
+		{Line: 11, Block: 0, Branch: 1},
+		{Line: 11, Block: 1, Branch: 0},
+		{Line: 11, Block: 1, Branch: 1},
+	}
+	if len(cart.Branches) != len(wantBranches) {
+		t.Fatalf("Branches = %+v, want %+v", cart.Branches, wantBranches)
+	}
+
+	for i, b := range wantBranches {
+		if cart.Branches[i] != b {
+			t.Errorf("Branches[%d] = %+v, want %+v", i, cart.Branches[i], b)
+		}
+	}
+
+	wantFunctions := []Function{{Name: "Cart.add", Line: 10, EndLine: 11, Hits: 3}, {Name: "Cart.clear", Line: 20, EndLine: 20}}
+	if len(cart.Functions) != 2 || cart.Functions[0] != wantFunctions[0] || cart.Functions[1] != wantFunctions[1] {
+		t.Errorf("Functions = %+v, want %+v", cart.Functions, wantFunctions)
+	}
+
+	if cart.Metrics.Lines != (Counter{Covered: 2, Total: 4}) || cart.Metrics.Functions != (Counter{Covered: 1, Total: 2}) {
+		t.Errorf("Cart.java metrics = %+v", cart.Metrics)
+	}
+
+	if gone := got.File("shop/src/main/java/com/example/Gone.java"); gone == nil || gone.Package != "com.example" {
+		t.Errorf("missing file = %+v, want it resolved through the source under the root", gone)
+	}
+}
+
+func TestCoberturaParserErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		want   string
+	}{
+		{name: "empty", report: "", want: "missing coverage element"},
+		{name: "not cobertura", report: "<report name=\"jacoco\"/>", want: "missing coverage element"},
+		{name: "malformed", report: "<coverage><packages>", want: "failed to read Cobertura report"},
+		{name: "class without file", report: `<coverage><packages><package><classes><class name="A"/></classes></package></packages></coverage>`, want: `class "A" has no filename`},
+		{name: "bad line number", report: `<coverage><class filename="a.py"><lines><line number="x" hits="1"/></lines></class></coverage>`, want: `invalid line number "x"`},
+		{name: "bad hits", report: `<coverage><class filename="a.py"><lines><line number="1" hits="-1"/></lines></class></coverage>`, want: `invalid hits "-1" on line 1`},
+		{name: "bad conditions", report: `<coverage><class filename="a.py"><lines><line number="1" hits="1" branch="true" condition-coverage="50%"/></lines></class></coverage>`, want: "invalid condition coverage"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCoberturaParser(".", "").Parse(strings.NewReader(tt.report))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
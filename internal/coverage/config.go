@@ -0,0 +1,273 @@
+package coverage
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds the coverage settings of .shipshape.yml.
+type Config struct {
+	// Paths are glob patterns of the reports to analyze, relative to the
+	// repository root; empty means auto-detection
+	Paths []string
+
+	// MergeMode is how merged reports combine hits
+	MergeMode MergeMode
+
+	// PatchThreshold is the lowest acceptable coverage percentage of the
+	// changed lines; 0 disables the check
+	PatchThreshold float64
+
+	// CriticalPathsRequired reports uncovered critical code as high
+	// severity findings rather than medium
+	CriticalPathsRequired bool
+
+	// Critical declares the critical code of the repository
+	Critical CriticalConfig
+
+	// Thresholds are the target percentages of every workspace and package
+	Thresholds Thresholds
+
+	// Overrides replace the thresholds of files matching a glob or of a
+	// workspace, in order
+	Overrides []ThresholdOverride
+
+	// Ratchet keeps the coverage of packages from falling below its last
+	// recorded value
+	Ratchet RatchetConfig
+}
+
+// Thresholds are target coverage percentages; 0 disables a target.
+type Thresholds struct {
+	// Line is the target line coverage
+	Line float64
+
+	// Branch is the target branch coverage
+	Branch float64
+
+	// Function is the target function coverage
+	Function float64
+}
+
+// ThresholdOverride replaces some thresholds for the files matching a glob
+// or for a workspace, configured under coverage.thresholds.overrides.
+type ThresholdOverride struct {
+	// Path is a glob of the files the thresholds apply to, such as
+	// "internal/legacy/**"
+	Path string `mapstructure:"path"`
+
+	// Workspace is the name or path of the workspace the thresholds apply to
+	Workspace string `mapstructure:"workspace"`
+
+	// Line replaces the line threshold when set
+	Line *float64 `mapstructure:"line"`
+
+	// Branch replaces the branch threshold when set
+	Branch *float64 `mapstructure:"branch"`
+
+	// Function replaces the function threshold when set
+	Function *float64 `mapstructure:"function"`
+}
+
+// apply returns thresholds with the values the override sets replaced.
+func (o ThresholdOverride) apply(t Thresholds) Thresholds {
+	if o.Line != nil {
+		t.Line = *o.Line
+	}
+
+	if o.Branch != nil {
+		t.Branch = *o.Branch
+	}
+
+	if o.Function != nil {
+		t.Function = *o.Function
+	}
+
+	return t
+}
+
+// RatchetConfig configures the coverage ratchet, under coverage.ratchet.
+type RatchetConfig struct {
+	// Enabled reports whether package coverage is compared with the ratchet
+	// file
+	Enabled bool
+
+	// File is the path of the ratchet file
+	File string
+
+	// Tolerance is the drop in percentage points accepted as noise
+	Tolerance float64
+}
+
+// CriticalConfig declares critical code, configured under
+// coverage.critical-paths.
+type CriticalConfig struct {
+	// Paths are globs of critical files, such as "internal/billing/**"
+	Paths []string
+
+	// Functions are glob patterns of critical function names, matched
+	// against qualified and unqualified names, such as "Authorize*"
+	Functions []string
+
+	// Heuristics are the enabled heuristics
+	Heuristics []Heuristic
+}
+
+// DefaultConfig auto-detects coverage reports, merges them by adding their
+// hits, requires critical code found by every heuristic to be covered and
+// targets 80% line, 70% branch and 90% function coverage.
+func DefaultConfig() *Config {
+	return &Config{
+		MergeMode:             MergeSum,
+		CriticalPathsRequired: true,
+		Critical:              CriticalConfig{Heuristics: slices.Clone(Heuristics)},
+		Thresholds:            Thresholds{Line: 80, Branch: 70, Function: 90},
+		Ratchet:               RatchetConfig{File: DefaultRatchetFile},
+	}
+}
+
+// LoadConfig reads coverage.paths, coverage.merge-mode,
+// coverage.patch-threshold, coverage.critical-paths-required,
+// coverage.critical-paths, coverage.thresholds and coverage.ratchet.
+// Patterns must be relative to the repository root and percentages between
+// 0 and 100.
+func LoadConfig(v *viper.Viper) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if v.IsSet("coverage.critical-paths-required") {
+		cfg.CriticalPathsRequired = v.GetBool("coverage.critical-paths-required")
+	}
+
+	if err := loadCriticalConfig(v, &cfg.Critical); err != nil {
+		return nil, err
+	}
+
+	if err := loadThresholds(v, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := loadRatchetConfig(v, &cfg.Ratchet); err != nil {
+		return nil, err
+	}
+
+	if v.IsSet("coverage.merge-mode") {
+		mode, err := ParseMergeMode(v.GetString("coverage.merge-mode"))
+		if err != nil {
+			return nil, fmt.Errorf("coverage.merge-mode: %w", err)
+		}
+
+		cfg.MergeMode = mode
+	}
+
+	cfg.PatchThreshold = v.GetFloat64("coverage.patch-threshold")
+	if cfg.PatchThreshold < 0 || cfg.PatchThreshold > 100 {
+		return nil, fmt.Errorf("coverage.patch-threshold must be between 0 and 100, got %v", cfg.PatchThreshold)
+	}
+
+	for _, p := range v.GetStringSlice("coverage.paths") {
+		if p == "" || path.IsAbs(p) {
+			return nil, fmt.Errorf("coverage.paths must be relative to the repository root, got %q", p)
+		}
+
+		cfg.Paths = append(cfg.Paths, p)
+	}
+
+	return cfg, nil
+}
+
+// loadCriticalConfig reads coverage.critical-paths, keeping every heuristic
+// unless heuristics are listed.
+func loadCriticalConfig(v *viper.Viper, cfg *CriticalConfig) error {
+	cfg.Paths = v.GetStringSlice("coverage.critical-paths.paths")
+	cfg.Functions = v.GetStringSlice("coverage.critical-paths.functions")
+
+	for _, p := range cfg.Paths {
+		if p == "" || path.IsAbs(p) {
+			return fmt.Errorf("coverage.critical-paths.paths must be relative to the repository root, got %q", p)
+		}
+	}
+
+	for _, pattern := range cfg.Functions {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q in coverage.critical-paths.functions: %w", pattern, err)
+		}
+	}
+
+	if !v.IsSet("coverage.critical-paths.heuristics") {
+		return nil
+	}
+
+	cfg.Heuristics = nil
+
+	for _, name := range v.GetStringSlice("coverage.critical-paths.heuristics") {
+		h := Heuristic(name)
+		if !slices.Contains(Heuristics, h) {
+			return fmt.Errorf("unknown heuristic %q in coverage.critical-paths.heuristics", name)
+		}
+
+		cfg.Heuristics = append(cfg.Heuristics, h)
+	}
+
+	return nil
+}
+
+// loadThresholds reads coverage.thresholds and its overrides.
+//
+//nolint:gocognit // One check per threshold setting
+func loadThresholds(v *viper.Viper, cfg *Config) error {
+	for key, threshold := range map[string]*float64{
+		"coverage.thresholds.line":     &cfg.Thresholds.Line,
+		"coverage.thresholds.branch":   &cfg.Thresholds.Branch,
+		"coverage.thresholds.function": &cfg.Thresholds.Function,
+	} {
+		if !v.IsSet(key) {
+			continue
+		}
+
+		*threshold = v.GetFloat64(key)
+		if *threshold < 0 || *threshold > 100 {
+			return fmt.Errorf("%s must be between 0 and 100, got %v", key, *threshold)
+		}
+	}
+
+	if err := v.UnmarshalKey("coverage.thresholds.overrides", &cfg.Overrides); err != nil {
+		return fmt.Errorf("invalid coverage.thresholds.overrides: %w", err)
+	}
+
+	for i, o := range cfg.Overrides {
+		if (o.Path == "") == (o.Workspace == "") {
+			return fmt.Errorf("coverage.thresholds.overrides[%d] must have either a path or a workspace", i)
+		}
+
+		if path.IsAbs(o.Path) {
+			return fmt.Errorf("coverage.thresholds.overrides[%d] path must be relative to the repository root, got %q", i, o.Path)
+		}
+
+		for _, threshold := range []*float64{o.Line, o.Branch, o.Function} {
+			if threshold != nil && (*threshold < 0 || *threshold > 100) {
+				return fmt.Errorf("coverage.thresholds.overrides[%d] thresholds must be between 0 and 100, got %v", i, *threshold)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadRatchetConfig reads coverage.ratchet.
+func loadRatchetConfig(v *viper.Viper, cfg *RatchetConfig) error {
+	cfg.Enabled = v.GetBool("coverage.ratchet.enabled")
+
+	if file := v.GetString("coverage.ratchet.file"); file != "" {
+		cfg.File = file
+	}
+
+	cfg.Tolerance = v.GetFloat64("coverage.ratchet.tolerance")
+	if cfg.Tolerance < 0 || cfg.Tolerance > 100 {
+		return fmt.Errorf("coverage.ratchet.tolerance must be between 0 and 100, got %v", cfg.Tolerance)
+	}
+
+	return nil
+}
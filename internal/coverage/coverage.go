@@ -0,0 +1,460 @@
+// Package coverage parses coverage reports into a language-neutral model.
+//
+// Every parser produces a Report: the files of the report with their line,
+// branch and function data, and covered/total counters aggregated per file,
+// per package and for the whole report.
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Format identifies a coverage report format.
+type Format string
+
+// Format constants, matching the coverage_format values of the ground-truth
+// schema.
+const (
+	FormatGoCover   Format = "go-cover"
+	FormatCobertura Format = "cobertura-xml"
+	FormatLCOV      Format = "lcov"
+	FormatIstanbul  Format = "istanbul-json"
+	FormatJaCoCo    Format = "jacoco-xml"
+)
+
+// FormatShipShape is the JSON encoding of a Report, written when reports are
+// merged or converted.
+const FormatShipShape Format = "shipshape-json"
+
+// Parser parses one coverage report format.
+type Parser interface {
+	// Format returns the format the parser reads.
+	Format() Format
+
+	// Parse reads a report.
+	Parse(r io.Reader) (*Report, error)
+}
+
+// NewParser creates the parser of a format. Reports name their files
+// relative to the repository root or to dir, the directory the tests ran in
+// relative to the root; Go profiles name them by import path, resolved
+// through the Go workspaces.
+func NewParser(format Format, root, dir string, workspaces []types.Workspace) (Parser, error) {
+	switch format {
+	case FormatGoCover:
+		return NewGoProfileParser(root, workspaces), nil
+	case FormatCobertura:
+		return NewCoberturaParser(root, dir), nil
+	case FormatLCOV:
+		return NewLCOVParser(root, dir), nil
+	case FormatIstanbul:
+		return NewIstanbulParser(root, dir), nil
+	case FormatJaCoCo:
+		return NewJaCoCoParser(root, dir), nil
+	case FormatShipShape:
+		return NewJSONParser(), nil
+	default:
+		return nil, fmt.Errorf("unsupported coverage format %q", format)
+	}
+}
+
+// Counter counts covered items out of a total.
+type Counter struct {
+	// Covered is the number of covered items
+	Covered int `json:"covered"`
+
+	// Total is the number of items
+	Total int `json:"total"`
+}
+
+// Add adds the items of other to c.
+func (c *Counter) Add(other Counter) {
+	c.Covered += other.Covered
+	c.Total += other.Total
+}
+
+// Count adds one item to c.
+func (c *Counter) Count(covered bool) {
+	c.Total++
+
+	if covered {
+		c.Covered++
+	}
+}
+
+// Percent returns the covered percentage, or 0 when there is nothing to
+// cover.
+func (c Counter) Percent() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+
+	return float64(c.Covered) * 100 / float64(c.Total)
+}
+
+// Metrics are the counters of a file, package or report. Counters a format
+// does not provide are zero.
+type Metrics struct {
+	// Lines counts executable lines
+	Lines Counter `json:"lines"`
+
+	// Statements counts statements
+	Statements Counter `json:"statements"`
+
+	// Branches counts branch outcomes
+	Branches Counter `json:"branches"`
+
+	// Functions counts functions and methods
+	Functions Counter `json:"functions"`
+
+	// Instructions counts bytecode instructions
+	Instructions Counter `json:"instructions"`
+
+	// Complexity counts the cyclomatic complexity paths
+	Complexity Counter `json:"complexity"`
+
+	// Classes counts classes
+	Classes Counter `json:"classes"`
+}
+
+// Add adds the counters of other to m.
+func (m *Metrics) Add(other Metrics) {
+	m.Lines.Add(other.Lines)
+	m.Statements.Add(other.Statements)
+	m.Branches.Add(other.Branches)
+	m.Functions.Add(other.Functions)
+	m.Instructions.Add(other.Instructions)
+	m.Complexity.Add(other.Complexity)
+	m.Classes.Add(other.Classes)
+}
+
+// Line is the execution count of an executable line.
+type Line struct {
+	// Number is the 1-based line number
+	Number int `json:"line"`
+
+	// Hits is the number of times the line ran
+	Hits int64 `json:"hits"`
+}
+
+// Branch is the execution count of one outcome of a branch.
+type Branch struct {
+	// Line is the line of the branch
+	Line int `json:"line"`
+
+	// Block identifies the branch among the branches of the line
+	Block int `json:"block"`
+
+	// Branch identifies the outcome within the block
+	Branch int `json:"branch"`
+
+	// Hits is the number of times the outcome was taken
+	Hits int64 `json:"hits"`
+}
+
+// Function is the coverage of a function or method.
+type Function struct {
+	// Name is the function name; methods are qualified by their type
+	Name string `json:"name"`
+
+	// Line is the line where the function starts
+	Line int `json:"line"`
+
+	// EndLine is the line where the function ends, or 0 when unknown
+	EndLine int `json:"end_line,omitempty"`
+
+	// Hits is the number of times the function ran
+	Hits int64 `json:"hits"`
+
+	// Statements counts the statements of the function, when known
+	Statements Counter `json:"statements"`
+}
+
+// File is the coverage of a source file.
+type File struct {
+	// Path is the file path, relative to the repository root when it can
+	// be resolved
+	Path string `json:"path"`
+
+	// Package is the package, module or directory of the file
+	Package string `json:"package,omitempty"`
+
+	// Lines are the executable lines, sorted by number
+	Lines []Line `json:"lines,omitempty"`
+
+	// Branches are the branch outcomes, sorted by line
+	Branches []Branch `json:"branches,omitempty"`
+
+	// Functions are the functions, sorted by line
+	Functions []Function `json:"functions,omitempty"`
+
+	// Metrics are the counters of the file
+	Metrics Metrics `json:"metrics"`
+
+	// blocks are the statement blocks of a Go profile, kept so that merged
+	// profiles combine statements block by block
+	blocks []goBlock
+}
+
+// Package is the coverage of the files of a package.
+type Package struct {
+	// Name is the package name
+	Name string `json:"name"`
+
+	// Files is the number of files of the package
+	Files int `json:"files"`
+
+	// Metrics are the counters of the package
+	Metrics Metrics `json:"metrics"`
+}
+
+// Report is a parsed coverage report.
+type Report struct {
+	// Format is the format the report was parsed from
+	Format Format `json:"format"`
+
+	// Files are the covered files, sorted by path
+	Files []*File `json:"files"`
+
+	// Metrics are the counters of the whole report
+	Metrics Metrics `json:"metrics"`
+}
+
+// NewReport sorts the files of a report and computes its counters.
+func NewReport(format Format, files []*File) *Report {
+	if files == nil {
+		files = []*File{}
+	}
+
+	r := &Report{Format: format, Files: files}
+	r.Summarize()
+
+	return r
+}
+
+// Summarize sorts the files and their data and recomputes the counters of
+// the report from the counters of its files. Files whose line and function
+// counters are empty get them from their lines and functions.
+func (r *Report) Summarize() {
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+
+	r.Metrics = Metrics{}
+
+	for _, f := range r.Files {
+		f.sort()
+
+		if f.Metrics.Lines.Total == 0 {
+			for _, l := range f.Lines {
+				f.Metrics.Lines.Count(l.Hits > 0)
+			}
+		}
+
+		if f.Metrics.Branches.Total == 0 {
+			for _, b := range f.Branches {
+				f.Metrics.Branches.Count(b.Hits > 0)
+			}
+		}
+
+		if f.Metrics.Functions.Total == 0 {
+			for _, fn := range f.Functions {
+				f.Metrics.Functions.Count(fn.Hits > 0)
+			}
+		}
+
+		r.Metrics.Add(f.Metrics)
+	}
+}
+
+// File returns the file with the given path, or nil.
+func (r *Report) File(p string) *File {
+	i := sort.Search(len(r.Files), func(i int) bool { return r.Files[i].Path >= p })
+	if i < len(r.Files) && r.Files[i].Path == p {
+		return r.Files[i]
+	}
+
+	return nil
+}
+
+// Packages aggregates the files of the report per package, sorted by name.
+// Files without a package are grouped by directory.
+func (r *Report) Packages() []Package {
+	byName := make(map[string]*Package)
+
+	for _, f := range r.Files {
+		name := f.Package
+		if name == "" {
+			name = path.Dir(f.Path)
+		}
+
+		p, ok := byName[name]
+		if !ok {
+			p = &Package{Name: name}
+			byName[name] = p
+		}
+
+		p.Files++
+		p.Metrics.Add(f.Metrics)
+	}
+
+	packages := make([]Package, 0, len(byName))
+	for _, p := range byName {
+		packages = append(packages, *p)
+	}
+
+	sort.Slice(packages, func(i, j int) bool { return packages[i].Name < packages[j].Name })
+
+	return packages
+}
+
+// sort orders the lines, branches and functions of the file.
+func (f *File) sort() {
+	sort.Slice(f.Lines, func(i, j int) bool { return f.Lines[i].Number < f.Lines[j].Number })
+	sort.Slice(f.Functions, func(i, j int) bool {
+		a, b := f.Functions[i], f.Functions[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Name < b.Name
+	})
+	sort.SliceStable(f.Branches, func(i, j int) bool {
+		a, b := f.Branches[i], f.Branches[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		if a.Block != b.Block {
+			return a.Block < b.Block
+		}
+
+		return a.Branch < b.Branch
+	})
+}
+
+// span is a source range, from a start line and column to an end line and
+// column.
+type span struct {
+	startLine, startCol, endLine, endCol int
+}
+
+// before reports whether p starts before other.
+func (p span) before(other span) bool {
+	if p.startLine != other.startLine {
+		return p.startLine < other.startLine
+	}
+
+	return p.startCol < other.startCol
+}
+
+// contains reports whether other lies within p.
+func (p span) contains(other span) bool {
+	startsAfter := other.startLine > p.startLine || other.startLine == p.startLine && other.startCol >= p.startCol
+	endsBefore := other.endLine < p.endLine || other.endLine == p.endLine && other.endCol <= p.endCol
+
+	return startsAfter && endsBefore
+}
+
+// branchKey identifies a branch outcome of a file.
+type branchKey struct {
+	line, block, branch int
+}
+
+// record accumulates the coverage of a file that a report lists in several
+// records, adding the hits of repeated lines, statements, branches and
+// functions.
+type record struct {
+	lines      map[int]int64
+	statements map[span]int64
+	branches   map[branchKey]int64
+	functions  map[string]*Function
+}
+
+// newRecord creates an empty record.
+func newRecord() *record {
+	return &record{
+		lines:      make(map[int]int64),
+		statements: make(map[span]int64),
+		branches:   make(map[branchKey]int64),
+		functions:  make(map[string]*Function),
+	}
+}
+
+// line adds the hits of a line.
+func (r *record) line(number int, hits int64) {
+	r.lines[number] += hits
+}
+
+// statement adds the hits of a statement.
+func (r *record) statement(s span, hits int64) {
+	r.statements[s] += hits
+}
+
+// branch adds the hits of a branch outcome.
+func (r *record) branch(key branchKey, hits int64) {
+	r.branches[key] += hits
+}
+
+// function returns the function with the given name, declaring it at a line
+// unless it is already known. Functions sharing a name but declared at
+// different lines (constructors of several classes, overloads) are kept
+// apart; a line of 0 refers to the first declaration of the name.
+func (r *record) function(name string, line, endLine int) *Function {
+	key := name
+	if fn, ok := r.functions[key]; ok && line > 0 && fn.Line > 0 && fn.Line != line {
+		key = name + "@" + strconv.Itoa(line)
+	}
+
+	fn, ok := r.functions[key]
+	if !ok {
+		fn = &Function{Name: name}
+		r.functions[key] = fn
+	}
+
+	if fn.Line == 0 {
+		fn.Line, fn.EndLine = line, endLine
+	}
+
+	return fn
+}
+
+// file builds the coverage of the file. Files reported by statements only
+// get the hits of a line from the statements that start on it, keeping the
+// highest count.
+func (r *record) file(p string) *File {
+	f := &File{Path: p}
+
+	lines := r.lines
+	if len(lines) == 0 && len(r.statements) > 0 {
+		lines = make(map[int]int64)
+
+		for s, hits := range r.statements {
+			if h, ok := lines[s.startLine]; !ok || hits > h {
+				lines[s.startLine] = hits
+			}
+		}
+	}
+
+	for number, hits := range lines {
+		f.Lines = append(f.Lines, Line{Number: number, Hits: hits})
+	}
+
+	for _, hits := range r.statements {
+		f.Metrics.Statements.Count(hits > 0)
+	}
+
+	for key, hits := range r.branches {
+		f.Branches = append(f.Branches, Branch{Line: key.line, Block: key.block, Branch: key.branch, Hits: hits})
+	}
+
+	for _, fn := range r.functions {
+		f.Functions = append(f.Functions, *fn)
+	}
+
+	return f
+}
@@ -0,0 +1,66 @@
+package coverage
+
+import (
+	"testing"
+)
+
+func TestCounterPercent(t *testing.T) {
+	tests := []struct {
+		counter Counter
+		want    float64
+	}{
+		{counter: Counter{Covered: 0, Total: 0}, want: 0},
+		{counter: Counter{Covered: 3, Total: 4}, want: 75},
+		{counter: Counter{Covered: 2, Total: 2}, want: 100},
+	}
+
+	for _, tt := range tests {
+		if got := tt.counter.Percent(); got != tt.want {
+			t.Errorf("%+v.Percent() = %v, want %v", tt.counter, got, tt.want)
+		}
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	report := NewReport(FormatLCOV, []*File{
+		{
+			Path:      "src/b.js",
+			Lines:     []Line{{Number: 3, Hits: 0}, {Number: 1, Hits: 2}},
+			Branches:  []Branch{{Line: 1, Branch: 1, Hits: 0}, {Line: 1, Branch: 0, Hits: 1}},
+			Functions: []Function{{Name: "b", Line: 1, Hits: 2}},
+		},
+		{
+			Path:    "lib/a.go",
+			Package: "example.com/lib",
+			Metrics: Metrics{Lines: Counter{Covered: 1, Total: 4}, Statements: Counter{Covered: 2, Total: 5}},
+		},
+	})
+
+	if report.Files[0].Path != "lib/a.go" || report.Files[1].Lines[0].Number != 1 || report.Files[1].Branches[0].Branch != 0 {
+		t.Errorf("files are not sorted: %+v", report.Files)
+	}
+
+	want := Metrics{
+		Lines:      Counter{Covered: 2, Total: 6},
+		Statements: Counter{Covered: 2, Total: 5},
+		Branches:   Counter{Covered: 1, Total: 2},
+		Functions:  Counter{Covered: 1, Total: 1},
+	}
+
+	if report.Metrics != want {
+		t.Errorf("Metrics = %+v, want %+v", report.Metrics, want)
+	}
+
+	if f := report.File("src/b.js"); f == nil || f.Metrics.Lines.Total != 2 {
+		t.Errorf("File(src/b.js) = %+v", f)
+	}
+
+	if f := report.File("src/missing.js"); f != nil {
+		t.Errorf("File(src/missing.js) = %+v, want nil", f)
+	}
+
+	packages := report.Packages()
+	if len(packages) != 2 || packages[0].Name != "example.com/lib" || packages[1].Name != "src" || packages[1].Files != 1 {
+		t.Errorf("Packages() = %+v", packages)
+	}
+}
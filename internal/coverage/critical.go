@@ -0,0 +1,641 @@
+package coverage
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Heuristic is a built-in rule that marks code as critical.
+type Heuristic string
+
+// Heuristics that mark code as critical without configuration.
+const (
+	// HeuristicErrorHandling marks error-handling blocks: the bodies of Go
+	// "if err != nil" checks, Python except clauses and catch blocks
+	HeuristicErrorHandling Heuristic = "error-handling"
+
+	// HeuristicExportedAPI marks exported Go functions outside internal
+	// directories and main packages, public Java methods and exported
+	// JavaScript and TypeScript functions
+	HeuristicExportedAPI Heuristic = "exported-api"
+
+	// HeuristicSensitivePackages marks the files of directories named after
+	// authentication, security or payment concerns
+	HeuristicSensitivePackages Heuristic = "sensitive-packages"
+)
+
+// Heuristics lists every heuristic.
+var Heuristics = []Heuristic{HeuristicErrorHandling, HeuristicExportedAPI, HeuristicSensitivePackages}
+
+// Check IDs of critical-path findings.
+const (
+	// CheckUncoveredCriticalCode reports critical functions and files with
+	// uncovered lines
+	CheckUncoveredCriticalCode = "uncovered-critical-code"
+
+	// CheckUncoveredErrorHandling reports error-handling blocks that tests
+	// never enter
+	CheckUncoveredErrorHandling = "uncovered-error-handling"
+)
+
+// criticalDirective is the comment marker of a critical function.
+const criticalDirective = "shipshape:critical"
+
+// sensitiveWords are the directory name parts of sensitive packages.
+var sensitiveWords = map[string]bool{
+	"auth": true, "authn": true, "authz": true, "authentication": true, "authorization": true,
+	"oauth": true, "login": true, "session": true, "sessions": true, "security": true, "crypto": true,
+	"payment": true, "payments": true, "billing": true, "checkout": true, "invoice": true,
+	"invoices": true, "wallet": true,
+}
+
+var (
+	// goErrorCheck matches a Go line opening the block of an error check.
+	goErrorCheck = regexp.MustCompile(`\berr\w*\s*!=\s*nil\s*\{\s*(//.*)?$`)
+
+	// catchClause matches a line opening a catch block.
+	catchClause = regexp.MustCompile(`\bcatch\s*(\([^)]*\))?\s*\{\s*(//.*)?$`)
+
+	// exceptClause matches a Python except clause.
+	exceptClause = regexp.MustCompile(`^\s*except\b.*:\s*(#.*)?$`)
+
+	// javaPublic matches a public Java declaration.
+	javaPublic = regexp.MustCompile(`\bpublic\b`)
+)
+
+// CriticalRegion is a critical function, the remainder of a critical file
+// outside its functions, or an error-handling block.
+type CriticalRegion struct {
+	// File is the file path, relative to the repository root
+	File string `json:"file"`
+
+	// Function is the function the region is or lies in, if known
+	Function string `json:"function,omitempty"`
+
+	// StartLine is the first line of the region
+	StartLine int `json:"start_line"`
+
+	// EndLine is the last line of the region
+	EndLine int `json:"end_line"`
+
+	// Reasons explain why the region is critical
+	Reasons []string `json:"reasons"`
+
+	// ErrorHandling reports whether the region is an error-handling block
+	ErrorHandling bool `json:"error_handling,omitempty"`
+
+	// Lines counts the executable lines of the region
+	Lines Counter `json:"lines"`
+
+	// Uncovered are the executable lines of the region that never ran
+	Uncovered []int `json:"uncovered_lines,omitempty"`
+}
+
+// CriticalReport is the coverage of the critical code of a report, kept
+// apart from its overall coverage.
+type CriticalReport struct {
+	// Lines counts the executable lines of every critical region
+	Lines Counter `json:"lines"`
+
+	// Percent is the covered percentage of the critical lines, 100 when
+	// there are none
+	Percent float64 `json:"percent"`
+
+	// Regions are the critical regions, sorted by file and line
+	Regions []CriticalRegion `json:"regions"`
+
+	// Findings report the regions with uncovered lines
+	Findings []types.Finding `json:"findings"`
+}
+
+// CriticalAnalyzer finds the critical code of the files of a coverage
+// report and reports the critical code that tests do not cover.
+//
+// Code is critical when its file matches a configured path, its function
+// matches a configured name pattern or follows a shipshape:critical
+// comment, or a heuristic marks it:
+//
+//	// shipshape:critical
+//	func Charge(card Card, amount int) error {
+type CriticalAnalyzer struct {
+	root     string
+	config   CriticalConfig
+	severity types.Severity
+}
+
+// NewCriticalAnalyzer creates an analyzer reading the sources of the
+// repository at root. Findings are high severity when critical paths are
+// required, medium otherwise.
+func NewCriticalAnalyzer(root string, cfg *Config) *CriticalAnalyzer {
+	severity := types.SeverityMedium
+	if cfg.CriticalPathsRequired {
+		severity = types.SeverityHigh
+	}
+
+	return &CriticalAnalyzer{root: root, config: cfg.Critical, severity: severity}
+}
+
+// Analyze finds the critical regions of the files of a report. Test files
+// and files outside the repository are skipped. Function ranges come from
+// the report; functions without an end line are taken to end where the
+// next one starts.
+func (a *CriticalAnalyzer) Analyze(r *Report) *CriticalReport {
+	report := &CriticalReport{Regions: []CriticalRegion{}, Findings: []types.Finding{}}
+
+	for _, f := range r.Files {
+		if isAbsSlash(f.Path) || discovery.IsTestFile(f.Path) {
+			continue
+		}
+
+		for _, region := range a.regions(f) {
+			report.Regions = append(report.Regions, region)
+			report.Lines.Add(region.Lines)
+
+			if len(region.Uncovered) > 0 {
+				report.Findings = append(report.Findings, a.finding(region))
+			}
+		}
+	}
+
+	report.Percent = 100
+	if report.Lines.Total > 0 {
+		report.Percent = report.Lines.Percent()
+	}
+
+	return report
+}
+
+// criticalFunction is a function of a file with its resolved range.
+type criticalFunction struct {
+	name       string
+	start, end int
+}
+
+// regions returns the critical regions of a file, sorted by line. Nested
+// functions and error-handling blocks within a critical function are part
+// of that function.
+//
+//nolint:gocognit,gocyclo // Each source of criticality adds regions
+func (a *CriticalAnalyzer) regions(f *File) []CriticalRegion {
+	var lines []string
+
+	src, err := os.ReadFile(filepath.Join(a.root, filepath.FromSlash(f.Path))) //nolint:gosec // Reading covered sources from repository
+	if err != nil {
+		logger.Debug("Covered source not found", "path", f.Path, "error", err)
+	} else {
+		lines = strings.Split(strings.TrimSuffix(string(src), "\n"), "\n")
+	}
+
+	lang := discovery.LanguageOf(path.Base(f.Path))
+	hits := make(map[int]int64, len(f.Lines))
+
+	for _, l := range f.Lines {
+		hits[l.Number] = l.Hits
+	}
+
+	fileReasons := a.fileReasons(f.Path)
+	functions := functionRanges(f, len(lines))
+	annotations := criticalAnnotations(lines)
+
+	var regions []CriticalRegion
+
+	within := func(start, end int) bool {
+		for _, r := range regions {
+			if start >= r.StartLine && end <= r.EndLine {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	previousEnd := 0
+
+	for _, fn := range functions {
+		annotated := slices.ContainsFunc(annotations, func(line int) bool { return line > previousEnd && line <= fn.end })
+		previousEnd = max(previousEnd, fn.end)
+
+		if within(fn.start, fn.end) {
+			continue
+		}
+
+		reasons := slices.Clone(fileReasons)
+		reasons = append(reasons, a.functionReasons(fn.name)...)
+
+		if annotated {
+			reasons = append(reasons, criticalDirective+" comment")
+		}
+
+		if a.enabled(HeuristicExportedAPI) && exported(lang, f.Path, fn, lines) {
+			reasons = append(reasons, "exported API")
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		if region := newRegion(f.Path, fn.name, fn.start, fn.end, reasons, hits); region.Lines.Total > 0 {
+			regions = append(regions, region)
+		}
+	}
+
+	if len(fileReasons) > 0 {
+		if region := remainder(f.Path, functions, fileReasons, hits); region.Lines.Total > 0 {
+			regions = append(regions, region)
+		}
+	}
+
+	if a.enabled(HeuristicErrorHandling) {
+		for _, block := range errorBlocks(lang, lines) {
+			if within(block.start, block.end) {
+				continue
+			}
+
+			region := newRegion(f.Path, enclosing(functions, block.start), block.start, block.end, []string{"error handling"}, hits)
+			region.ErrorHandling = true
+
+			if region.Lines.Total > 0 {
+				regions = append(regions, region)
+			}
+		}
+	}
+
+	sort.SliceStable(regions, func(i, j int) bool { return regions[i].StartLine < regions[j].StartLine })
+
+	return regions
+}
+
+// enabled reports whether a heuristic is enabled.
+func (a *CriticalAnalyzer) enabled(h Heuristic) bool {
+	return slices.Contains(a.config.Heuristics, h)
+}
+
+// fileReasons returns why every function of a file is critical.
+func (a *CriticalAnalyzer) fileReasons(relPath string) []string {
+	var reasons []string
+
+	for _, glob := range a.config.Paths {
+		if discovery.MatchGlob(glob, relPath) {
+			reasons = append(reasons, "path "+glob)
+		}
+	}
+
+	if a.enabled(HeuristicSensitivePackages) {
+		if dir := sensitiveDir(relPath); dir != "" {
+			reasons = append(reasons, "sensitive package "+dir)
+		}
+	}
+
+	return reasons
+}
+
+// functionReasons returns the configured name patterns a function matches.
+func (a *CriticalAnalyzer) functionReasons(name string) []string {
+	short := name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		short = name[i+1:]
+	}
+
+	var reasons []string
+
+	for _, pattern := range a.config.Functions {
+		if ok, _ := path.Match(pattern, name); ok {
+			reasons = append(reasons, "function "+pattern)
+		} else if ok, _ := path.Match(pattern, short); ok {
+			reasons = append(reasons, "function "+pattern)
+		}
+	}
+
+	return reasons
+}
+
+// finding reports a critical region with uncovered lines.
+func (a *CriticalAnalyzer) finding(r CriticalRegion) types.Finding {
+	f := types.Finding{
+		CheckID:   CheckUncoveredCriticalCode,
+		Type:      types.FindingTypeCoverage,
+		Severity:  a.severity,
+		Title:     "Uncovered Critical Code",
+		Rationale: "Defects in critical code such as payments, authentication and public APIs cost the most, so it should not depend on overall coverage to be tested.",
+		Location:  types.Location{File: r.File, StartLine: r.StartLine, EndLine: r.EndLine},
+		Remediation: &types.Remediation{
+			Summary: "Add tests that run the uncovered lines",
+			Steps: []string{
+				"Write tests for the inputs that reach the uncovered lines",
+				"Regenerate the coverage report and check the lines are covered",
+			},
+			Effort: types.EffortLow,
+		},
+	}
+
+	subject := "Critical code"
+	if r.Function != "" {
+		subject = "Critical function " + r.Function
+	}
+
+	if r.ErrorHandling {
+		f.CheckID = CheckUncoveredErrorHandling
+		f.Title = "Untested Error Handling"
+		f.Rationale = "Error paths run rarely in production and are the first thing to break when they do; untested error handling often loses or hides the error."
+		f.Remediation.Summary = "Add tests that make the guarded call fail"
+		f.Remediation.Steps = []string{
+			"Inject a failing dependency, fake or input that makes the call return an error",
+			"Assert on the error returned or the recovery performed",
+		}
+
+		subject = "Error handling"
+		if r.Function != "" {
+			subject += " in " + r.Function
+		}
+	}
+
+	if !r.ErrorHandling {
+		subject += " (" + strings.Join(r.Reasons, ", ") + ")"
+	}
+
+	f.Description = fmt.Sprintf("%s has %d of %d executable lines uncovered: %s",
+		subject, len(r.Uncovered), r.Lines.Total, LineRanges(r.Uncovered))
+
+	if r.Lines.Covered == 0 {
+		f.Description = subject + " never runs in tests"
+	}
+
+	return f
+}
+
+// newRegion counts the executable lines of a region.
+func newRegion(file, function string, start, end int, reasons []string, hits map[int]int64) CriticalRegion {
+	r := CriticalRegion{File: file, Function: function, StartLine: start, EndLine: end, Reasons: reasons}
+
+	for line := start; line <= end; line++ {
+		h, ok := hits[line]
+		if !ok {
+			continue
+		}
+
+		r.Lines.Count(h > 0)
+
+		if h == 0 {
+			r.Uncovered = append(r.Uncovered, line)
+		}
+	}
+
+	return r
+}
+
+// remainder returns the region of the executable lines of a critical file
+// outside its functions.
+func remainder(file string, functions []criticalFunction, reasons []string, hits map[int]int64) CriticalRegion {
+	r := CriticalRegion{File: file, Reasons: reasons}
+
+	numbers := make([]int, 0, len(hits))
+
+	for line := range hits {
+		if enclosing(functions, line) == "" {
+			numbers = append(numbers, line)
+		}
+	}
+
+	sort.Ints(numbers)
+
+	for _, line := range numbers {
+		if r.StartLine == 0 {
+			r.StartLine = line
+		}
+
+		r.EndLine = line
+		r.Lines.Count(hits[line] > 0)
+
+		if hits[line] == 0 {
+			r.Uncovered = append(r.Uncovered, line)
+		}
+	}
+
+	return r
+}
+
+// functionRanges returns the functions of a file sorted by line, ending at
+// their end line or, when unknown, before the next function or at the last
+// line of the file.
+func functionRanges(f *File, lastLine int) []criticalFunction {
+	functions := make([]criticalFunction, 0, len(f.Functions))
+
+	for _, fn := range f.Functions {
+		if fn.Line > 0 {
+			functions = append(functions, criticalFunction{name: fn.Name, start: fn.Line, end: fn.EndLine})
+		}
+	}
+
+	sort.SliceStable(functions, func(i, j int) bool { return functions[i].start < functions[j].start })
+
+	for _, l := range f.Lines {
+		lastLine = max(lastLine, l.Number)
+	}
+
+	for i := range functions {
+		if functions[i].end >= functions[i].start {
+			continue
+		}
+
+		functions[i].end = lastLine
+
+		for _, next := range functions[i+1:] {
+			if next.start > functions[i].start {
+				functions[i].end = next.start - 1
+				break
+			}
+		}
+	}
+
+	return functions
+}
+
+// enclosing returns the name of the innermost function containing a line,
+// or "".
+func enclosing(functions []criticalFunction, line int) string {
+	name, size := "", 0
+
+	for _, fn := range functions {
+		if line >= fn.start && line <= fn.end && (name == "" || fn.end-fn.start < size) {
+			name, size = fn.name, fn.end-fn.start
+		}
+	}
+
+	return name
+}
+
+// criticalAnnotations returns the lines of shipshape:critical comments.
+func criticalAnnotations(lines []string) []int {
+	var annotations []int
+
+	for i, text := range lines {
+		idx := strings.Index(text, criticalDirective)
+		if idx < 0 || !strings.ContainsAny(text[:idx], "/#*") {
+			continue
+		}
+
+		if rest := text[idx+len(criticalDirective):]; rest != "" && rest[0] != ' ' && rest[0] != '\t' && !strings.HasPrefix(rest, "*/") {
+			continue // Some other word, such as shipshape:critical-path
+		}
+
+		annotations = append(annotations, i+1)
+	}
+
+	return annotations
+}
+
+// sensitiveDir returns the first directory of a path named after a
+// sensitive concern, or "". Directory names are split on "-", "_" and "."
+// so that "payments-api" is sensitive.
+func sensitiveDir(relPath string) string {
+	for _, dir := range strings.Split(path.Dir(relPath), "/") {
+		for _, part := range strings.FieldsFunc(strings.ToLower(dir), func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			if sensitiveWords[part] {
+				return dir
+			}
+		}
+	}
+
+	return ""
+}
+
+// exported reports whether a function belongs to the public API of its
+// language. Python has no export marker and is never matched.
+func exported(lang types.Language, relPath string, fn criticalFunction, lines []string) bool {
+	switch lang {
+	case types.LanguageGo:
+		if slices.Contains(strings.Split(relPath, "/"), "internal") || goMainPackage(lines) {
+			return false
+		}
+
+		for _, part := range strings.Split(fn.name, ".") {
+			if r, _ := utf8.DecodeRuneInString(part); !unicode.IsUpper(r) {
+				return false
+			}
+		}
+
+		return true
+	case types.LanguageJava:
+		// Annotations may sit between the declaration and the line a
+		// report gives for a method.
+		for line := max(fn.start-2, 1); line <= fn.start && line <= len(lines); line++ {
+			if javaPublic.MatchString(lines[line-1]) {
+				return true
+			}
+		}
+	case types.LanguageJavaScript, types.LanguageTypeScript:
+		if fn.start <= len(lines) {
+			return strings.HasPrefix(strings.TrimSpace(lines[fn.start-1]), "export ")
+		}
+	}
+
+	return false
+}
+
+// goMainPackage reports whether Go source lines declare package main.
+func goMainPackage(lines []string) bool {
+	for _, line := range lines {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "package "); ok {
+			return strings.TrimSpace(name) == "main"
+		}
+	}
+
+	return false
+}
+
+// block is a range of lines.
+type block struct {
+	start, end int
+}
+
+// errorBlocks returns the bodies of the error-handling blocks of a source,
+// from the line after the clause to its last line. Brace blocks end at the
+// matching closing brace, counted without regard to strings and comments;
+// Python clauses end before the next line indented no deeper than the
+// clause.
+func errorBlocks(lang types.Language, lines []string) []block {
+	var clause *regexp.Regexp
+
+	switch lang {
+	case types.LanguageGo:
+		clause = goErrorCheck
+	case types.LanguageJavaScript, types.LanguageTypeScript, types.LanguageJava, types.LanguageCSharp:
+		clause = catchClause
+	case types.LanguagePython:
+		clause = exceptClause
+	default:
+		return nil
+	}
+
+	var blocks []block
+
+	for i, text := range lines {
+		if !clause.MatchString(text) {
+			continue
+		}
+
+		end := braceBlockEnd(lines, i)
+		if lang == types.LanguagePython {
+			end = indentBlockEnd(lines, i)
+		}
+
+		if end > i {
+			blocks = append(blocks, block{start: i + 2, end: end + 1})
+		}
+	}
+
+	return blocks
+}
+
+// braceBlockEnd returns the index of the line closing the brace that ends
+// line i, or i when it is not closed.
+func braceBlockEnd(lines []string, i int) int {
+	depth := 1
+
+	for j := i + 1; j < len(lines); j++ {
+		depth += strings.Count(lines[j], "{") - strings.Count(lines[j], "}")
+		if depth <= 0 {
+			return j
+		}
+	}
+
+	return i
+}
+
+// indentBlockEnd returns the index of the last line of the Python block
+// opened by line i.
+func indentBlockEnd(lines []string, i int) int {
+	indent := indentation(lines[i])
+	end := i
+
+	for j := i + 1; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) == "" {
+			continue
+		}
+
+		if indentation(lines[j]) <= indent {
+			break
+		}
+
+		end = j
+	}
+
+	return end
+}
+
+// indentation returns the width of the leading whitespace of a line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
@@ -0,0 +1,274 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+const cartSource = `package api
+
+import "errors"
+
+// Total sums prices.
+func Total(prices []int) (int, error) {
+	sum := 0
+	for _, p := range prices {
+		if p < 0 {
+			return 0, errors.New("negative")
+		}
+		sum += p
+	}
+	return sum, nil
+}
+
+// shipshape:critical
+func discount(total int) int {
+	return total / 10
+}
+
+func load(name string) (string, error) {
+	data, err := read(name)
+	if err != nil {
+		return "", err
+	}
+	return data, nil
+}
+
+func read(name string) (string, error) {
+	return name, nil
+}
+`
+
+// writeCriticalRepo writes the sources of a repository and a report
+// covering them.
+func writeCriticalRepo(t *testing.T) (string, *Report) {
+	t.Helper()
+
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "api/cart.go", cartSource)
+	testutil.WriteFile(t, dir, "api/cart_test.go", "package api\n\nfunc TestTotal(t *testing.T) {\n\tt.Fail()\n}\n")
+	testutil.WriteFile(t, dir, "internal/payments/refund.go", "package payments\n\nfunc Refund(id string) error {\n\treturn nil\n}\n")
+
+	lines := func(hits map[int]int64) []Line {
+		var l []Line
+		for number, h := range hits {
+			l = append(l, Line{Number: number, Hits: h})
+		}
+
+		return l
+	}
+
+	report := NewReport(FormatGoCover, []*File{
+		{
+			Path:  "api/cart.go",
+			Lines: lines(map[int]int64{7: 1, 8: 1, 9: 1, 10: 0, 12: 1, 14: 1, 19: 0, 23: 1, 24: 1, 25: 0, 27: 1, 31: 1}),
+			Functions: []Function{
+				{Name: "Total", Line: 6, EndLine: 15},
+				{Name: "discount", Line: 18, EndLine: 20},
+				{Name: "load", Line: 22, EndLine: 28},
+				{Name: "read", Line: 30, EndLine: 32},
+			},
+		},
+		{
+			Path:      "api/cart_test.go",
+			Lines:     lines(map[int]int64{4: 0}),
+			Functions: []Function{{Name: "TestTotal", Line: 3, EndLine: 5}},
+		},
+		{
+			Path:      "internal/payments/refund.go",
+			Lines:     lines(map[int]int64{4: 0}),
+			Functions: []Function{{Name: "Refund", Line: 3}},
+		},
+	})
+
+	return dir, report
+}
+
+func TestCriticalAnalyzer(t *testing.T) {
+	dir, report := writeCriticalRepo(t)
+
+	cfg := DefaultConfig()
+	cfg.Critical.Functions = []string{"Refund*"}
+
+	critical := NewCriticalAnalyzer(dir, cfg).Analyze(report)
+
+	want := []CriticalRegion{
+		{File: "api/cart.go", Function: "Total", StartLine: 6, EndLine: 15, Reasons: []string{"exported API"},
+			Lines: Counter{Covered: 5, Total: 6}, Uncovered: []int{10}},
+		{File: "api/cart.go", Function: "discount", StartLine: 18, EndLine: 20, Reasons: []string{"shipshape:critical comment"},
+			Lines: Counter{Total: 1}, Uncovered: []int{19}},
+		{File: "api/cart.go", Function: "load", StartLine: 25, EndLine: 26, Reasons: []string{"error handling"}, ErrorHandling: true,
+			Lines: Counter{Total: 1}, Uncovered: []int{25}},
+		{File: "internal/payments/refund.go", Function: "Refund", StartLine: 3, EndLine: 5,
+			Reasons: []string{"sensitive package payments", "function Refund*"}, Lines: Counter{Total: 1}, Uncovered: []int{4}},
+	}
+	if !reflect.DeepEqual(critical.Regions, want) {
+		t.Errorf("Regions = %+v, want %+v", critical.Regions, want)
+	}
+
+	if critical.Lines != (Counter{Covered: 5, Total: 9}) {
+		t.Errorf("Lines = %+v, want 5/9", critical.Lines)
+	}
+
+	if len(critical.Findings) != 4 {
+		t.Fatalf("got %d findings, want 4", len(critical.Findings))
+	}
+
+	total := critical.Findings[0]
+	if total.CheckID != CheckUncoveredCriticalCode || total.Severity != types.SeverityHigh ||
+		total.Description != "Critical function Total (exported API) has 1 of 6 executable lines uncovered: 10" {
+		t.Errorf("Total finding = %+v", total)
+	}
+
+	if d := critical.Findings[1].Description; d != "Critical function discount (shipshape:critical comment) never runs in tests" {
+		t.Errorf("discount finding description = %q", d)
+	}
+
+	if f := critical.Findings[2]; f.CheckID != CheckUncoveredErrorHandling || f.Location.StartLine != 25 || f.Description != "Error handling in load never runs in tests" {
+		t.Errorf("error handling finding = %+v", f)
+	}
+}
+
+func TestCriticalAnalyzerConfiguredOnly(t *testing.T) {
+	dir, report := writeCriticalRepo(t)
+
+	cfg := DefaultConfig()
+	cfg.CriticalPathsRequired = false
+	cfg.Critical = CriticalConfig{Paths: []string{"internal/**"}, Functions: []string{"load"}}
+
+	critical := NewCriticalAnalyzer(dir, cfg).Analyze(report)
+
+	var got []string
+	for _, r := range critical.Regions {
+		got = append(got, r.Function+" "+strings.Join(r.Reasons, ", "))
+	}
+
+	want := []string{"discount shipshape:critical comment", "load function load", "Refund path internal/**"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("regions = %q, want %q", got, want)
+	}
+
+	for _, f := range critical.Findings {
+		if f.Severity != types.SeverityMedium {
+			t.Errorf("finding %q severity = %s, want medium when critical paths are not required", f.Description, f.Severity)
+		}
+	}
+}
+
+func TestCriticalAnalyzerWithoutCriticalCode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Critical.Heuristics = nil
+
+	critical := NewCriticalAnalyzer(testutil.TempDir(t), cfg).Analyze(NewReport(FormatLCOV, []*File{
+		{Path: "src/a.js", Lines: []Line{{Number: 1, Hits: 0}}},
+	}))
+
+	if critical.Percent != 100 || len(critical.Regions) != 0 || len(critical.Findings) != 0 {
+		t.Errorf("Analyze() = %+v, want no critical code", critical)
+	}
+}
+
+func TestErrorBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   types.Language
+		source string
+		want   []block
+	}{
+		{
+			name:   "go",
+			lang:   types.LanguageGo,
+			source: "if err := f(); err != nil { // wrap\n\treturn fmt.Errorf(\"f: %w\", err)\n}\nif errs != nil {}\n",
+			want:   []block{{start: 2, end: 3}},
+		},
+		{
+			name:   "javascript",
+			lang:   types.LanguageJavaScript,
+			source: "try {\n  run();\n} catch (e) {\n  if (e) {\n    log(e);\n  }\n}\n",
+			want:   []block{{start: 4, end: 7}},
+		},
+		{
+			name:   "python",
+			lang:   types.LanguagePython,
+			source: "try:\n    run()\nexcept ValueError as e:  # bad input\n    log(e)\n\n    raise\nprint()\n",
+			want:   []block{{start: 4, end: 6}},
+		},
+		{
+			name:   "unsupported",
+			lang:   types.LanguageRust,
+			source: "match f() {\n  Err(e) => {}\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorBlocks(tt.lang, strings.Split(tt.source, "\n")); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errorBlocks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCriticalAnnotations(t *testing.T) {
+	lines := []string{
+		"// shipshape:critical",
+		"# shipshape:critical handles refunds",
+		"/* shipshape:critical */",
+		"// shipshape:critical-path",
+		`name := "shipshape:critical"`,
+	}
+
+	if got := criticalAnnotations(lines); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("criticalAnnotations() = %v, want [1 2 3]", got)
+	}
+}
+
+func TestSensitiveDir(t *testing.T) {
+	tests := map[string]string{
+		"services/payments-api/handler.go": "payments-api",
+		"src/auth/login.ts":                "auth",
+		"app/models/user.py":               "",
+		"authors.go":                       "",
+	}
+
+	for relPath, want := range tests {
+		if got := sensitiveDir(relPath); got != want {
+			t.Errorf("sensitiveDir(%q) = %q, want %q", relPath, got, want)
+		}
+	}
+}
+
+func TestLoadCriticalConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	config := "coverage:\n  critical-paths-required: false\n  critical-paths:\n    paths: [\"internal/billing/**\"]\n    functions: [\"Authorize*\"]\n    heuristics: [error-handling]\n"
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := CriticalConfig{Paths: []string{"internal/billing/**"}, Functions: []string{"Authorize*"}, Heuristics: []Heuristic{HeuristicErrorHandling}}
+	if cfg.CriticalPathsRequired || !reflect.DeepEqual(cfg.Critical, want) {
+		t.Errorf("LoadConfig() = %+v, want %+v", cfg, want)
+	}
+
+	if !reflect.DeepEqual(DefaultConfig().Critical.Heuristics, Heuristics) {
+		t.Errorf("default heuristics = %v, want all", DefaultConfig().Critical.Heuristics)
+	}
+
+	v.Set("coverage.critical-paths.heuristics", []string{"magic"})
+
+	if _, err := LoadConfig(v); err == nil || !strings.Contains(err.Error(), `unknown heuristic "magic"`) {
+		t.Errorf("LoadConfig() error = %v, want unknown heuristic error", err)
+	}
+}
@@ -0,0 +1,318 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Go coverage modes.
+const (
+	goModeSet    = "set"
+	goModeCount  = "count"
+	goModeAtomic = "atomic"
+)
+
+// goBlock is a coverage block of a Go profile.
+type goBlock struct {
+	span
+	stmts int
+	count int64
+}
+
+// GoProfileParser parses the profiles written by go test -coverprofile in
+// set, count and atomic mode. File names are import paths; they are
+// resolved to repository paths through the Go workspaces, and the sources
+// found under the root are parsed to attribute blocks to functions.
+type GoProfileParser struct {
+	root    string
+	modules []types.Workspace
+}
+
+// NewGoProfileParser creates a parser resolving the files of Go modules of
+// workspaces relative to the repository root.
+func NewGoProfileParser(root string, workspaces []types.Workspace) *GoProfileParser {
+	var modules []types.Workspace
+
+	for _, ws := range workspaces {
+		if ws.Type == types.WorkspaceTypeGo && ws.Name != "" {
+			modules = append(modules, ws)
+		}
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return len(modules[i].Name) > len(modules[j].Name) })
+
+	return &GoProfileParser{root: root, modules: modules}
+}
+
+// Format returns FormatGoCover.
+func (p *GoProfileParser) Format() Format {
+	return FormatGoCover
+}
+
+// Parse reads a coverage profile. Profiles concatenated from several runs
+// may repeat the mode line and the blocks of packages covered by several
+// test binaries: repeated blocks are merged by taking the maximum in set
+// mode and the sum in count and atomic mode.
+func (p *GoProfileParser) Parse(r io.Reader) (*Report, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		mode   string
+		number int
+		files  = make(map[string]map[span]*goBlock)
+	)
+
+	for scanner.Scan() {
+		number++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if m, ok := strings.CutPrefix(line, "mode:"); ok {
+			m = strings.TrimSpace(m)
+
+			switch {
+			case m != goModeSet && m != goModeCount && m != goModeAtomic:
+				return nil, fmt.Errorf("line %d: unknown coverage mode %q", number, m)
+			case mode != "" && m != mode:
+				return nil, fmt.Errorf("line %d: coverage mode %s differs from %s", number, m, mode)
+			}
+
+			mode = m
+
+			continue
+		}
+
+		if mode == "" {
+			return nil, fmt.Errorf("line %d: missing mode line", number)
+		}
+
+		name, block, err := parseGoBlock(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", number, err)
+		}
+
+		blocks, ok := files[name]
+		if !ok {
+			blocks = make(map[span]*goBlock)
+			files[name] = blocks
+		}
+
+		existing, ok := blocks[block.span]
+		switch {
+		case !ok:
+			blocks[block.span] = &block
+		case existing.stmts != block.stmts:
+			return nil, fmt.Errorf("line %d: block %s:%d.%d has %d statements, previously %d",
+				number, name, block.startLine, block.startCol, block.stmts, existing.stmts)
+		case mode == goModeSet:
+			existing.count = max(existing.count, block.count)
+		default:
+			existing.count += block.count
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read coverage profile: %w", err)
+	}
+
+	if mode == "" {
+		return nil, fmt.Errorf("missing mode line")
+	}
+
+	report := make([]*File, 0, len(files))
+
+	for name, byPos := range files {
+		blocks := make([]*goBlock, 0, len(byPos))
+		for _, b := range byPos {
+			blocks = append(blocks, b)
+		}
+
+		sort.Slice(blocks, func(i, j int) bool { return blocks[i].before(blocks[j].span) })
+
+		report = append(report, p.file(name, blocks))
+	}
+
+	return NewReport(FormatGoCover, report), nil
+}
+
+// parseGoBlock parses a profile line of the form
+// "file.go:startLine.startCol,endLine.endCol numStmt count".
+func parseGoBlock(line string) (string, goBlock, error) {
+	var b goBlock
+
+	i := strings.LastIndex(line, ":")
+	if i <= 0 {
+		return "", b, fmt.Errorf("invalid block %q", line)
+	}
+
+	fields := strings.Fields(line[i+1:])
+	if len(fields) != 3 {
+		return "", b, fmt.Errorf("invalid block %q", line)
+	}
+
+	if _, err := fmt.Sscanf(fields[0], "%d.%d,%d.%d", &b.startLine, &b.startCol, &b.endLine, &b.endCol); err != nil {
+		return "", b, fmt.Errorf("invalid block position %q", fields[0])
+	}
+
+	stmts, err := strconv.Atoi(fields[1])
+	if err != nil || stmts < 0 {
+		return "", b, fmt.Errorf("invalid statement count %q", fields[1])
+	}
+
+	count, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || count < 0 {
+		return "", b, fmt.Errorf("invalid execution count %q", fields[2])
+	}
+
+	b.stmts, b.count = stmts, count
+
+	return line[:i], b, nil
+}
+
+// file builds the coverage of a file from its sorted blocks.
+func (p *GoProfileParser) file(name string, blocks []*goBlock) *File {
+	f := &File{Path: p.resolve(name), Package: path.Dir(name)}
+	hits := make(map[int]int64)
+
+	for _, b := range blocks {
+		f.blocks = append(f.blocks, *b)
+		f.Metrics.Statements.Total += b.stmts
+
+		if b.count > 0 {
+			f.Metrics.Statements.Covered += b.stmts
+		}
+
+		if b.stmts == 0 {
+			continue
+		}
+
+		for line := b.startLine; line <= b.endLine; line++ {
+			if h, ok := hits[line]; !ok || b.count > h {
+				hits[line] = b.count
+			}
+		}
+	}
+
+	for line, h := range hits {
+		f.Lines = append(f.Lines, Line{Number: line, Hits: h})
+	}
+
+	if f.Path != name {
+		f.Functions = p.functions(f.Path, blocks)
+	}
+
+	return f
+}
+
+// resolve maps an import path onto a repository path through the module
+// paths of the Go workspaces. Absolute paths under the root are made
+// relative; other names are returned unchanged.
+func (p *GoProfileParser) resolve(name string) string {
+	if filepath.IsAbs(name) {
+		if rel, err := filepath.Rel(p.root, name); err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+
+		return name
+	}
+
+	for _, ws := range p.modules {
+		if rest, ok := strings.CutPrefix(name, ws.Name+"/"); ok {
+			return path.Join(ws.Path, rest)
+		}
+	}
+
+	return name
+}
+
+// functions attributes the blocks of a file to the functions declared in
+// its source. Functions without statements are omitted.
+func (p *GoProfileParser) functions(relPath string, blocks []*goBlock) []Function {
+	src, err := os.ReadFile(filepath.Join(p.root, filepath.FromSlash(relPath))) //nolint:gosec // Reading covered sources from repository
+	if err != nil {
+		logger.Debug("Covered source not found", "path", relPath, "error", err)
+		return nil
+	}
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, relPath, src, 0)
+	if err != nil {
+		logger.Warn("Failed to parse covered source", "path", relPath, "error", err)
+		return nil
+	}
+
+	var functions []Function
+
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		start, end := fset.Position(fn.Pos()), fset.Position(fn.End())
+		span := span{startLine: start.Line, startCol: start.Column, endLine: end.Line, endCol: end.Column}
+		f := Function{Name: goFuncName(fn), Line: start.Line, EndLine: end.Line}
+
+		for _, b := range blocks {
+			if span.contains(b.span) {
+				f.Statements.Total += b.stmts
+
+				if b.count > 0 {
+					f.Statements.Covered += b.stmts
+				}
+
+				f.Hits = max(f.Hits, b.count)
+			}
+		}
+
+		if f.Statements.Total > 0 {
+			functions = append(functions, f)
+		}
+	}
+
+	return functions
+}
+
+// goFuncName returns the name of a function, qualified by the receiver type
+// for methods (e.g., "Server.Start").
+func goFuncName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	switch x := expr.(type) {
+	case *ast.IndexExpr:
+		expr = x.X
+	case *ast.IndexListExpr:
+		expr = x.X
+	}
+
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name + "." + fn.Name.Name
+	}
+
+	return fn.Name.Name
+}
@@ -0,0 +1,180 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const goCoverSource = `package calc
+
+// Add adds two numbers.
+func Add(a, b int) int {
+	return a + b
+}
+
+// Div divides a by b.
+func Div(a, b int) int {
+	if b == 0 {
+		return 0
+	}
+
+	return a / b
+}
+
+type Acc struct{ n int }
+
+func (a *Acc) Inc() {
+	a.n++
+}
+`
+
+func TestGoProfileParser(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "calc/calc.go", goCoverSource)
+
+	workspaces := []types.Workspace{{Name: "example.com/calc", Path: ".", Type: types.WorkspaceTypeGo}}
+
+	tests := []struct {
+		name       string
+		profile    string
+		statements Counter
+		lines      Counter
+		functions  Counter
+		divHits    int64
+	}{
+		{
+			name: "set mode merges duplicates with max",
+			profile: `mode: set
+example.com/calc/calc/calc.go:4.24,6.2 1 1
+example.com/calc/calc/calc.go:9.24,10.12 1 1
+example.com/calc/calc/calc.go:10.12,12.3 1 0
+example.com/calc/calc/calc.go:14.2,14.14 1 0
+example.com/calc/calc/calc.go:19.21,21.2 1 0
+mode: set
+example.com/calc/calc/calc.go:9.24,10.12 1 0
+example.com/calc/calc/calc.go:14.2,14.14 1 1
+`,
+			statements: Counter{Covered: 3, Total: 5},
+			lines:      Counter{Covered: 6, Total: 11},
+			functions:  Counter{Covered: 2, Total: 3},
+			divHits:    1,
+		},
+		{
+			name: "count mode sums duplicates",
+			profile: `mode: count
+example.com/calc/calc/calc.go:4.24,6.2 1 3
+example.com/calc/calc/calc.go:9.24,10.12 1 2
+example.com/calc/calc/calc.go:10.12,12.3 1 1
+example.com/calc/calc/calc.go:14.2,14.14 1 1
+example.com/calc/calc/calc.go:19.21,21.2 1 0
+example.com/calc/calc/calc.go:9.24,10.12 1 4
+`,
+			statements: Counter{Covered: 4, Total: 5},
+			lines:      Counter{Covered: 8, Total: 11},
+			functions:  Counter{Covered: 2, Total: 3},
+			divHits:    6,
+		},
+		{
+			name:       "atomic mode",
+			profile:    "mode: atomic\nexample.com/calc/calc/calc.go:19.21,21.2 1 7\n",
+			statements: Counter{Covered: 1, Total: 1},
+			lines:      Counter{Covered: 3, Total: 3},
+			functions:  Counter{Covered: 1, Total: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := NewGoProfileParser(dir, workspaces).Parse(strings.NewReader(tt.profile))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if len(report.Files) != 1 || report.Files[0].Path != "calc/calc.go" || report.Files[0].Package != "example.com/calc/calc" {
+				t.Fatalf("Files = %+v, want calc/calc.go", report.Files)
+			}
+
+			m := report.Metrics
+			if m.Statements != tt.statements || m.Lines != tt.lines || m.Functions != tt.functions {
+				t.Errorf("Metrics = %+v, want statements %+v lines %+v functions %+v",
+					m, tt.statements, tt.lines, tt.functions)
+			}
+
+			for _, fn := range report.Files[0].Functions {
+				if fn.Name == "Div" && fn.Hits != tt.divHits {
+					t.Errorf("Div hits = %d, want %d", fn.Hits, tt.divHits)
+				}
+			}
+		})
+	}
+}
+
+func TestGoProfileParserFunctions(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "calc/calc.go", goCoverSource)
+
+	profile := `mode: set
+example.com/calc/calc/calc.go:4.24,6.2 1 1
+example.com/calc/calc/calc.go:9.24,10.12 1 1
+example.com/calc/calc/calc.go:10.12,12.3 1 0
+example.com/calc/calc/calc.go:14.2,14.14 1 1
+example.com/calc/calc/calc.go:19.21,21.2 1 0
+example.com/other/x.go:1.1,2.2 1 1
+`
+
+	workspaces := []types.Workspace{{Name: "example.com/calc", Path: ".", Type: types.WorkspaceTypeGo}}
+
+	report, err := NewGoProfileParser(dir, workspaces).Parse(strings.NewReader(profile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Function{
+		{Name: "Add", Line: 4, EndLine: 6, Hits: 1, Statements: Counter{Covered: 1, Total: 1}},
+		{Name: "Div", Line: 9, EndLine: 15, Hits: 1, Statements: Counter{Covered: 2, Total: 3}},
+		{Name: "Acc.Inc", Line: 19, EndLine: 21, Hits: 0, Statements: Counter{Covered: 0, Total: 1}},
+	}
+
+	calc := report.File("calc/calc.go")
+	if calc == nil || len(calc.Functions) != len(want) {
+		t.Fatalf("calc/calc.go = %+v", calc)
+	}
+
+	for i, fn := range want {
+		if calc.Functions[i] != fn {
+			t.Errorf("Functions[%d] = %+v, want %+v", i, calc.Functions[i], fn)
+		}
+	}
+
+	if other := report.File("example.com/other/x.go"); other == nil || other.Functions != nil {
+		t.Errorf("unresolved file = %+v, want it kept by import path without functions", other)
+	}
+}
+
+func TestGoProfileParserErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		want    string
+	}{
+		{name: "empty", profile: "", want: "missing mode line"},
+		{name: "no mode", profile: "a.go:1.1,2.2 1 1\n", want: "line 1: missing mode line"},
+		{name: "unknown mode", profile: "mode: sometimes\n", want: "unknown coverage mode"},
+		{name: "mixed modes", profile: "mode: set\nmode: count\n", want: "differs from set"},
+		{name: "bad block", profile: "mode: set\na.go:1.1,2.2 1\n", want: "line 2: invalid block"},
+		{name: "bad count", profile: "mode: set\na.go:1.1,2.2 1 x\n", want: "invalid execution count"},
+		{name: "inconsistent block", profile: "mode: set\na.go:1.1,2.2 1 1\na.go:1.1,2.2 2 1\n", want: "has 2 statements, previously 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewGoProfileParser(".", nil).Parse(strings.NewReader(tt.profile))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
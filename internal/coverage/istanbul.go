@@ -0,0 +1,220 @@
+package coverage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// istanbulPosition is a position of an Istanbul location.
+type istanbulPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// istanbulLocation is a source range of an Istanbul report.
+type istanbulLocation struct {
+	Start istanbulPosition `json:"start"`
+	End   istanbulPosition `json:"end"`
+}
+
+// span returns the range of the location.
+func (l istanbulLocation) span() span {
+	return span{startLine: l.Start.Line, startCol: l.Start.Column, endLine: l.End.Line, endCol: l.End.Column}
+}
+
+// istanbulFunction is an entry of the fnMap of a file.
+type istanbulFunction struct {
+	Name string           `json:"name"`
+	Decl istanbulLocation `json:"decl"`
+	Loc  istanbulLocation `json:"loc"`
+	Line int              `json:"line"`
+}
+
+// istanbulBranch is an entry of the branchMap of a file.
+type istanbulBranch struct {
+	Loc       istanbulLocation   `json:"loc"`
+	Type      string             `json:"type"`
+	Locations []istanbulLocation `json:"locations"`
+	Line      int                `json:"line"`
+}
+
+// istanbulFile is the coverage of a file in coverage-final.json.
+type istanbulFile struct {
+	Path         string                      `json:"path"`
+	StatementMap map[string]istanbulLocation `json:"statementMap"`
+	FnMap        map[string]istanbulFunction `json:"fnMap"`
+	BranchMap    map[string]istanbulBranch   `json:"branchMap"`
+	S            map[string]int64            `json:"s"`
+	F            map[string]int64            `json:"f"`
+	B            map[string][]int64          `json:"b"`
+
+	// Data holds the coverage when a tool serialized the FileCoverage
+	// object itself rather than its data
+	Data *istanbulFile `json:"data"`
+}
+
+// IstanbulParser parses the coverage-final.json reports of Istanbul, as
+// written by nyc, c8, Jest and Vitest. The report maps file paths to their
+// statement, function and branch maps and counts; files are decoded one at
+// a time, and entries of the same file are merged by adding their hits.
+type IstanbulParser struct {
+	paths resolver
+	bases []string
+}
+
+// NewIstanbulParser creates a parser resolving file names relative to the
+// repository root. Relative names are first tried under dir, the directory
+// the tests ran in relative to the root; an empty dir means the root.
+func NewIstanbulParser(root, dir string) *IstanbulParser {
+	return &IstanbulParser{paths: resolver{root: root}, bases: baseDirs(dir)}
+}
+
+// Format returns FormatIstanbul.
+func (p *IstanbulParser) Format() Format {
+	return FormatIstanbul
+}
+
+// Parse reads a report. Statements and their counts give the statement
+// counter, and the hits of a line are the highest count of the statements
+// starting on it, as Istanbul's own LCOV output computes them. Every
+// location of a branch is an outcome, in a block numbered by the branch id.
+func (p *IstanbulParser) Parse(r io.Reader) (*Report, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Istanbul report: %w", err)
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("istanbul report is not an object")
+	}
+
+	var (
+		names   []string
+		records = make(map[string]*record)
+	)
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Istanbul report: %w", err)
+		}
+
+		key, _ := tok.(string)
+
+		var data istanbulFile
+		if err := dec.Decode(&data); err != nil {
+			return nil, fmt.Errorf("failed to decode coverage of %s: %w", key, err)
+		}
+
+		if data.Data != nil {
+			data = *data.Data
+		}
+
+		if data.Path == "" {
+			data.Path = key
+		}
+
+		name := p.paths.resolve(data.Path, p.bases)
+
+		rec := records[name]
+		if rec == nil {
+			rec = newRecord()
+			records[name] = rec
+			names = append(names, name)
+		}
+
+		if err := data.addTo(rec); err != nil {
+			return nil, fmt.Errorf("invalid coverage of %s: %w", key, err)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read Istanbul report: %w", err)
+	}
+
+	files := make([]*File, 0, len(names))
+	for _, name := range names {
+		files = append(files, records[name].file(name))
+	}
+
+	return NewReport(FormatIstanbul, files), nil
+}
+
+// addTo adds the statements, functions and branches of the file to a
+// record. Counts without an entry in their map are errors.
+func (f *istanbulFile) addTo(rec *record) error {
+	for id, hits := range f.S {
+		loc, ok := f.StatementMap[id]
+		if !ok {
+			return fmt.Errorf("statement %s has no location", id)
+		}
+
+		rec.statement(loc.span(), hits)
+	}
+
+	// Functions are declared in id order, so that same-named functions keep
+	// a stable name.
+	for _, id := range sortedIDs(f.F) {
+		fn, ok := f.FnMap[id]
+		if !ok {
+			return fmt.Errorf("function %s has no declaration", id)
+		}
+
+		line := fn.Decl.Start.Line
+		if line == 0 {
+			line = fn.Line
+		}
+
+		rec.function(fn.Name, line, fn.Loc.End.Line).Hits += f.F[id]
+	}
+
+	for id, counts := range f.B {
+		b, ok := f.BranchMap[id]
+		if !ok {
+			return fmt.Errorf("branch %s has no location", id)
+		}
+
+		block, err := strconv.Atoi(id)
+		if err != nil {
+			return fmt.Errorf("invalid branch id %q", id)
+		}
+
+		line := b.Line
+		if line == 0 {
+			line = b.Loc.Start.Line
+		}
+
+		for i, hits := range counts {
+			rec.branch(branchKey{line: line, block: block, branch: i}, hits)
+		}
+	}
+
+	return nil
+}
+
+// sortedIDs returns the ids of an Istanbul count map in numeric order.
+func sortedIDs(counts map[string]int64) []string {
+	ids := make([]string, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+
+		if errA != nil || errB != nil {
+			return ids[i] < ids[j]
+		}
+
+		return a < b
+	})
+
+	return ids
+}
@@ -0,0 +1,111 @@
+package coverage
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+func TestIstanbulParser(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "src/cart.ts", "")
+
+	cart := filepath.ToSlash(filepath.Join(dir, "src", "cart.ts"))
+
+	report := `{
+  "` + cart + `": {
+    "path": "` + cart + `",
+    "statementMap": {
+      "0": {"start": {"line": 2, "column": 2}, "end": {"line": 2, "column": 20}},
+      "1": {"start": {"line": 3, "column": 2}, "end": {"line": 3, "column": 9}},
+      "2": {"start": {"line": 3, "column": 10}, "end": {"line": 3, "column": 30}},
+      "3": {"start": {"line": 7, "column": 4}, "end": {"line": 7, "column": 16}}
+    },
+    "fnMap": {
+      "0": {"name": "constructor", "decl": {"start": {"line": 1, "column": 2}, "end": {"line": 1, "column": 13}}, "loc": {"start": {"line": 1, "column": 16}, "end": {"line": 4, "column": 3}}, "line": 1},
+      "1": {"name": "constructor", "decl": {"start": {"line": 6, "column": 2}, "end": {"line": 6, "column": 13}}, "loc": {"start": {"line": 6, "column": 16}, "end": {"line": 8, "column": 3}}, "line": 6}
+    },
+    "branchMap": {
+      "0": {"loc": {"start": {"line": 3, "column": 2}, "end": {"line": 3, "column": 30}}, "type": "if", "locations": [{"start": {"line": 3, "column": 2}, "end": {"line": 3, "column": 30}}, {"start": {}, "end": {}}], "line": 3}
+    },
+    "s": {"0": 1, "1": 1, "2": 0, "3": 0},
+    "f": {"0": 1, "1": 0},
+    "b": {"0": [1, 0]}
+  },
+  "src/cart.ts": {
+    "data": {
+      "path": "src/cart.ts",
+      "statementMap": {"0": {"start": {"line": 7, "column": 4}, "end": {"line": 7, "column": 16}}},
+      "fnMap": {"0": {"name": "constructor", "decl": {"start": {"line": 6, "column": 2}, "end": {"line": 6, "column": 13}}, "loc": {"start": {"line": 6, "column": 16}, "end": {"line": 8, "column": 3}}}},
+      "branchMap": {},
+      "s": {"0": 2},
+      "f": {"0": 2},
+      "b": {}
+    }
+  }
+}`
+
+	got, err := NewIstanbulParser(dir, "").Parse(strings.NewReader(report))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(got.Files) != 1 || got.Files[0].Path != "src/cart.ts" {
+		t.Fatalf("Files = %+v, want both entries merged into src/cart.ts", got.Files)
+	}
+
+	f := got.Files[0]
+
+	wantLines := []Line{{Number: 2, Hits: 1}, {Number: 3, Hits: 1}, {Number: 7, Hits: 2}}
+	if len(f.Lines) != len(wantLines) {
+		t.Fatalf("Lines = %+v, want %+v", f.Lines, wantLines)
+	}
+
+	for i, l := range wantLines {
+		if f.Lines[i] != l {
+			t.Errorf("Lines[%d] = %+v, want %+v", i, f.Lines[i], l)
+		}
+	}
+
+	wantFunctions := []Function{{Name: "constructor", Line: 1, EndLine: 4, Hits: 1}, {Name: "constructor", Line: 6, EndLine: 8, Hits: 2}}
+	if len(f.Functions) != 2 || f.Functions[0] != wantFunctions[0] || f.Functions[1] != wantFunctions[1] {
+		t.Errorf("Functions = %+v, want %+v", f.Functions, wantFunctions)
+	}
+
+	want := Metrics{
+		Lines:      Counter{Covered: 3, Total: 3},
+		Statements: Counter{Covered: 3, Total: 4},
+		Branches:   Counter{Covered: 1, Total: 2},
+		Functions:  Counter{Covered: 2, Total: 2},
+	}
+	if got.Metrics != want {
+		t.Errorf("Metrics = %+v, want %+v", got.Metrics, want)
+	}
+}
+
+func TestIstanbulParserErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		want   string
+	}{
+		{name: "empty", report: "", want: "failed to read Istanbul report"},
+		{name: "array", report: "[]", want: "not an object"},
+		{name: "bad file", report: `{"a.js": {"s": {"0": "x"}}}`, want: "failed to decode coverage of a.js"},
+		{name: "missing statement", report: `{"a.js": {"s": {"0": 1}}}`, want: "invalid coverage of a.js: statement 0 has no location"},
+		{name: "missing function", report: `{"a.js": {"f": {"0": 1}}}`, want: "function 0 has no declaration"},
+		{name: "missing branch", report: `{"a.js": {"b": {"0": [1]}}}`, want: "branch 0 has no location"},
+		{name: "truncated", report: `{"a.js": {}`, want: "failed to read Istanbul report"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewIstanbulParser(".", "").Parse(strings.NewReader(tt.report))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+)
+
+// jacocoSourceExts are the extensions of the JVM sources JaCoCo reports on.
+var jacocoSourceExts = map[string]bool{
+	".java":   true,
+	".kt":     true,
+	".groovy": true,
+	".scala":  true,
+}
+
+// JaCoCoParser parses JaCoCo XML reports, as written by the JaCoCo Maven and
+// Gradle plugins for single modules and aggregated across modules. Source
+// files are named by package and file name only, so they are located under
+// the repository root by that suffix, preferring the module of the report
+// and the module named by the enclosing group of aggregate reports.
+type JaCoCoParser struct {
+	root    string
+	dir     string
+	sources map[string][]string
+}
+
+// NewJaCoCoParser creates a parser locating sources under the repository
+// root. dir is the module the report belongs to, relative to the root; an
+// empty dir means the root.
+func NewJaCoCoParser(root, dir string) *JaCoCoParser {
+	return &JaCoCoParser{root: root, dir: path.Clean(filepath.ToSlash(dir))}
+}
+
+// Format returns FormatJaCoCo.
+func (p *JaCoCoParser) Format() Format {
+	return FormatJaCoCo
+}
+
+// Parse reads a JaCoCo report. The counters of each source file give its
+// instruction, line, branch, complexity, method and class coverage. JaCoCo
+// records whether instructions ran but not how often, so covered lines,
+// branches and methods have one hit; the outcomes of a line are its missed
+// and covered branches.
+//
+//nolint:gocognit,gocyclo // Streaming decoder over the nested report elements
+func (p *JaCoCoParser) Parse(r io.Reader) (*Report, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		seen    bool
+		groups  []string
+		pkg     string
+		methods map[string][]Function
+		class   string
+		source  string
+		method  *Function
+		file    *File
+		files   = make(map[string]*File)
+	)
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JaCoCo report: %w", err)
+		}
+
+		pos, _ := dec.InputPos()
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "report":
+				seen = true
+			case "group":
+				groups = append(groups, xmlAttr(t, "name"))
+			case "package":
+				pkg = xmlAttr(t, "name")
+				methods = make(map[string][]Function)
+			case "class":
+				class, source = xmlAttr(t, "name"), xmlAttr(t, "sourcefilename")
+			case "method":
+				if source == "" {
+					continue
+				}
+
+				line, _ := strconv.Atoi(xmlAttr(t, "line"))
+				method = &Function{Name: jacocoMethodName(class, xmlAttr(t, "name")), Line: line}
+			case "sourcefile":
+				name := xmlAttr(t, "name")
+				if name == "" {
+					return nil, fmt.Errorf("line %d: sourcefile in package %q has no name", pos, pkg)
+				}
+
+				file = &File{
+					Path:      p.resolve(path.Join(pkg, name), groups),
+					Package:   strings.ReplaceAll(pkg, "/", "."),
+					Functions: methods[name],
+				}
+			case "line":
+				if file == nil {
+					continue
+				}
+
+				l, branches, err := parseJaCoCoLine(t)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", pos, err)
+				}
+
+				file.Lines = append(file.Lines, l)
+				file.Branches = append(file.Branches, branches...)
+			case "counter":
+				kind, c, err := parseJaCoCoCounter(t)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", pos, err)
+				}
+
+				switch {
+				case method != nil:
+					if kind == "METHOD" && c.Covered > 0 {
+						method.Hits = 1
+					}
+				case file != nil:
+					setJaCoCoCounter(&file.Metrics, kind, c)
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "group":
+				groups = groups[:len(groups)-1]
+			case "package":
+				pkg, methods = "", nil
+			case "class":
+				class, source = "", ""
+			case "method":
+				if method != nil && methods != nil {
+					methods[source] = append(methods[source], *method)
+				}
+
+				method = nil
+			case "sourcefile":
+				if existing, ok := files[file.Path]; ok {
+					logger.Warn("Source file reported twice in JaCoCo report, keeping the first",
+						"path", file.Path, "package", existing.Package)
+				} else {
+					files[file.Path] = file
+				}
+
+				file = nil
+			}
+		}
+	}
+
+	if !seen {
+		return nil, fmt.Errorf("missing report element")
+	}
+
+	report := make([]*File, 0, len(files))
+	for _, f := range files {
+		report = append(report, f)
+	}
+
+	return NewReport(FormatJaCoCo, report), nil
+}
+
+// parseJaCoCoLine parses a <line> element of a source file and the branch
+// outcomes it records.
+func parseJaCoCoLine(t xml.StartElement) (Line, []Branch, error) {
+	var counts [4]int
+
+	number, err := strconv.Atoi(xmlAttr(t, "nr"))
+	if err != nil || number <= 0 {
+		return Line{}, nil, fmt.Errorf("invalid line number %q", xmlAttr(t, "nr"))
+	}
+
+	for i, attr := range []string{"mi", "ci", "mb", "cb"} {
+		v := xmlAttr(t, attr)
+		if v == "" {
+			continue
+		}
+
+		if counts[i], err = strconv.Atoi(v); err != nil || counts[i] < 0 {
+			return Line{}, nil, fmt.Errorf("invalid %s %q on line %d", attr, v, number)
+		}
+	}
+
+	l := Line{Number: number}
+	if counts[1] > 0 {
+		l.Hits = 1
+	}
+
+	missed, covered := counts[2], counts[3]
+	branches := make([]Branch, 0, missed+covered)
+
+	for i := range missed + covered {
+		b := Branch{Line: number, Branch: i}
+		if i < covered {
+			b.Hits = 1
+		}
+
+		branches = append(branches, b)
+	}
+
+	return l, branches, nil
+}
+
+// parseJaCoCoCounter parses a <counter> element.
+func parseJaCoCoCounter(t xml.StartElement) (string, Counter, error) {
+	kind := xmlAttr(t, "type")
+
+	missed, err := strconv.Atoi(xmlAttr(t, "missed"))
+	if err != nil || missed < 0 {
+		return kind, Counter{}, fmt.Errorf("invalid missed count %q of %s counter", xmlAttr(t, "missed"), kind)
+	}
+
+	covered, err := strconv.Atoi(xmlAttr(t, "covered"))
+	if err != nil || covered < 0 {
+		return kind, Counter{}, fmt.Errorf("invalid covered count %q of %s counter", xmlAttr(t, "covered"), kind)
+	}
+
+	return kind, Counter{Covered: covered, Total: missed + covered}, nil
+}
+
+// setJaCoCoCounter stores a counter of a source file in its metrics.
+func setJaCoCoCounter(m *Metrics, kind string, c Counter) {
+	switch kind {
+	case "INSTRUCTION":
+		m.Instructions = c
+	case "LINE":
+		m.Lines = c
+	case "BRANCH":
+		m.Branches = c
+	case "COMPLEXITY":
+		m.Complexity = c
+	case "METHOD":
+		m.Functions = c
+	case "CLASS":
+		m.Classes = c
+	}
+}
+
+// jacocoMethodName qualifies a method by the simple name of its class, which
+// JaCoCo records in VM form (e.g., "com/example/Cart$Item").
+func jacocoMethodName(class, method string) string {
+	if i := strings.LastIndexByte(class, '/'); i >= 0 {
+		class = class[i+1:]
+	}
+
+	if class == "" {
+		return method
+	}
+
+	return class + "." + method
+}
+
+// resolve locates a source file, given as package path and file name, under
+// the root. Among several matches it prefers one in the module of the
+// report, then one in a module named by an enclosing group, then the
+// shortest path. Files that are not found keep their package path.
+func (p *JaCoCoParser) resolve(name string, groups []string) string {
+	candidates := p.sourceIndex()[path.Base(name)]
+
+	var matches []string
+
+	for _, c := range candidates {
+		if c == name || strings.HasSuffix(c, "/"+name) {
+			matches = append(matches, c)
+		}
+	}
+
+	if len(matches) == 0 {
+		return name
+	}
+
+	rank := func(c string) int {
+		if p.dir != "." && strings.HasPrefix(c, p.dir+"/") {
+			return 0
+		}
+
+		for _, g := range groups {
+			if g != "" && (strings.HasPrefix(c, g+"/") || strings.Contains(c, "/"+g+"/")) {
+				return 1
+			}
+		}
+
+		return 2
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		ri, rj := rank(matches[i]), rank(matches[j])
+		if ri != rj {
+			return ri < rj
+		}
+
+		return len(matches[i]) < len(matches[j])
+	})
+
+	if len(matches) > 1 && rank(matches[0]) == rank(matches[1]) {
+		logger.Debug("Ambiguous JaCoCo source file", "name", name, "matches", matches)
+	}
+
+	return matches[0]
+}
+
+// sourceIndex lists the JVM sources under the root by file name. The root
+// is walked once, on first use.
+func (p *JaCoCoParser) sourceIndex() map[string][]string {
+	if p.sources != nil {
+		return p.sources
+	}
+
+	p.sources = make(map[string][]string)
+
+	_, err := discovery.NewWalker(p.root).Walk(func(info discovery.FileInfo) error {
+		if jacocoSourceExts[info.Ext] {
+			p.sources[info.Name] = append(p.sources[info.Name], filepath.ToSlash(info.RelPath))
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Warn("Failed to index sources for JaCoCo report", "root", p.root, "error", err)
+	}
+
+	return p.sources
+}
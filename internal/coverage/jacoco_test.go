@@ -0,0 +1,188 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+// jacocoAggregate is an aggregate report of two Maven modules that both
+// declare com.example.util.Strings.
+const jacocoAggregate = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">
+<report name="shop">
+	<sessioninfo id="ci-1" start="1760745600000" dump="1760745610000"/>
+	<group name="cart">
+		<package name="com/example/cart">
+			<class name="com/example/cart/Cart" sourcefilename="Cart.java">
+				<method name="&lt;init&gt;" desc="()V" line="5">
+					<counter type="INSTRUCTION" missed="0" covered="3"/>
+					<counter type="METHOD" missed="0" covered="1"/>
+				</method>
+				<method name="total" desc="()I" line="9">
+					<counter type="INSTRUCTION" missed="4" covered="0"/>
+					<counter type="METHOD" missed="1" covered="0"/>
+				</method>
+				<counter type="CLASS" missed="0" covered="1"/>
+			</class>
+			<class name="com/example/cart/Cart$Item" sourcefilename="Cart.java">
+				<method name="price" desc="()I" line="14">
+					<counter type="METHOD" missed="0" covered="1"/>
+				</method>
+			</class>
+			<sourcefile name="Cart.java">
+				<line nr="5" mi="0" ci="3" mb="0" cb="0"/>
+				<line nr="9" mi="2" ci="0" mb="2" cb="0"/>
+				<line nr="10" mi="2" ci="0" mb="0" cb="0"/>
+				<line nr="14" mi="0" ci="2" mb="1" cb="1"/>
+				<counter type="INSTRUCTION" missed="4" covered="5"/>
+				<counter type="BRANCH" missed="3" covered="1"/>
+				<counter type="LINE" missed="2" covered="2"/>
+				<counter type="COMPLEXITY" missed="3" covered="2"/>
+				<counter type="METHOD" missed="1" covered="2"/>
+				<counter type="CLASS" missed="0" covered="2"/>
+			</sourcefile>
+			<counter type="LINE" missed="2" covered="2"/>
+		</package>
+		<package name="com/example/util">
+			<sourcefile name="Strings.java">
+				<line nr="3" mi="0" ci="1"/>
+				<counter type="LINE" missed="0" covered="1"/>
+			</sourcefile>
+		</package>
+	</group>
+	<group name="pricing">
+		<package name="com/example/util">
+			<sourcefile name="Strings.java">
+				<line nr="3" mi="1" ci="0"/>
+				<counter type="LINE" missed="1" covered="0"/>
+			</sourcefile>
+		</package>
+		<package name="com/example/gone">
+			<sourcefile name="Gone.kt">
+				<line nr="1" mi="1" ci="0"/>
+			</sourcefile>
+		</package>
+	</group>
+	<counter type="LINE" missed="4" covered="3"/>
+</report>
+`
+
+func TestJaCoCoParser(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "cart/src/main/java/com/example/cart/Cart.java", "")
+	testutil.WriteFile(t, dir, "cart/src/main/java/com/example/util/Strings.java", "")
+	testutil.WriteFile(t, dir, "pricing/src/main/java/com/example/util/Strings.java", "")
+	testutil.WriteFile(t, dir, "cart/target/generated-sources/com/example/cart/Cart.java", "")
+
+	report, err := NewJaCoCoParser(dir, "").Parse(strings.NewReader(jacocoAggregate))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var paths []string
+	for _, f := range report.Files {
+		paths = append(paths, f.Path)
+	}
+
+	wantPaths := []string{
+		"cart/src/main/java/com/example/cart/Cart.java",
+		"cart/src/main/java/com/example/util/Strings.java",
+		"com/example/gone/Gone.kt",
+		"pricing/src/main/java/com/example/util/Strings.java",
+	}
+	if strings.Join(paths, " ") != strings.Join(wantPaths, " ") {
+		t.Fatalf("paths = %v, want %v", paths, wantPaths)
+	}
+
+	cart := report.Files[0]
+
+	wantMetrics := Metrics{
+		Lines:        Counter{Covered: 2, Total: 4},
+		Branches:     Counter{Covered: 1, Total: 4},
+		Functions:    Counter{Covered: 2, Total: 3},
+		Instructions: Counter{Covered: 5, Total: 9},
+		Complexity:   Counter{Covered: 2, Total: 5},
+		Classes:      Counter{Covered: 2, Total: 2},
+	}
+	if cart.Metrics != wantMetrics || cart.Package != "com.example.cart" {
+		t.Errorf("Cart.java = %+v, want metrics %+v", cart, wantMetrics)
+	}
+
+	wantFunctions := []Function{
+		{Name: "Cart.<init>", Line: 5, Hits: 1},
+		{Name: "Cart.total", Line: 9},
+		{Name: "Cart$Item.price", Line: 14, Hits: 1},
+	}
+	if len(cart.Functions) != len(wantFunctions) {
+		t.Fatalf("Functions = %+v, want %+v", cart.Functions, wantFunctions)
+	}
+
+	for i, fn := range wantFunctions {
+		if cart.Functions[i] != fn {
+			t.Errorf("Functions[%d] = %+v, want %+v", i, cart.Functions[i], fn)
+		}
+	}
+
+	wantBranches := []Branch{{Line: 9}, {Line: 9, Branch: 1}, {Line: 14, Hits: 1}, {Line: 14, Branch: 1}}
+	if len(cart.Branches) != len(wantBranches) {
+		t.Fatalf("Branches = %+v, want %+v", cart.Branches, wantBranches)
+	}
+
+	for i, b := range wantBranches {
+		if cart.Branches[i] != b {
+			t.Errorf("Branches[%d] = %+v, want %+v", i, cart.Branches[i], b)
+		}
+	}
+
+	if gone := report.File("com/example/gone/Gone.kt"); gone.Metrics.Lines != (Counter{Total: 1}) {
+		t.Errorf("Gone.kt lines = %+v, want them counted from its lines", gone.Metrics.Lines)
+	}
+
+	if report.Metrics.Lines != (Counter{Covered: 3, Total: 7}) {
+		t.Errorf("report lines = %+v, want the sum of the files", report.Metrics.Lines)
+	}
+}
+
+func TestJaCoCoParserModule(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "cart/src/main/java/com/example/util/Strings.java", "")
+	testutil.WriteFile(t, dir, "pricing/src/main/java/com/example/util/Strings.java", "")
+
+	module := `<report name="pricing"><package name="com/example/util"><sourcefile name="Strings.java"><line nr="3" mi="0" ci="1"/></sourcefile></package></report>`
+
+	report, err := NewJaCoCoParser(dir, "pricing").Parse(strings.NewReader(module))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(report.Files) != 1 || report.Files[0].Path != "pricing/src/main/java/com/example/util/Strings.java" {
+		t.Errorf("Files = %+v, want the source of the report module", report.Files)
+	}
+}
+
+func TestJaCoCoParserErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		want   string
+	}{
+		{name: "empty", report: "", want: "missing report element"},
+		{name: "cobertura", report: "<coverage/>", want: "missing report element"},
+		{name: "malformed", report: "<report><package>", want: "failed to read JaCoCo report"},
+		{name: "unnamed source", report: `<report><package name="a"><sourcefile/></package></report>`, want: `sourcefile in package "a" has no name`},
+		{name: "bad line", report: `<report><package name="a"><sourcefile name="A.java"><line nr="0"/></sourcefile></package></report>`, want: `invalid line number "0"`},
+		{name: "bad branch count", report: `<report><package name="a"><sourcefile name="A.java"><line nr="1" mb="x"/></sourcefile></package></report>`, want: `invalid mb "x" on line 1`},
+		{name: "bad counter", report: `<report><counter type="LINE" missed="1" covered="-1"/></report>`, want: `invalid covered count "-1" of LINE counter`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJaCoCoParser(testutil.TempDir(t), "").Parse(strings.NewReader(tt.report))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
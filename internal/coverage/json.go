@@ -0,0 +1,42 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONParser reads reports written in FormatShipShape, such as merged
+// reports. File paths are kept as written.
+type JSONParser struct{}
+
+// NewJSONParser creates a parser of encoded reports.
+func NewJSONParser() *JSONParser {
+	return &JSONParser{}
+}
+
+// Format returns FormatShipShape.
+func (p *JSONParser) Format() Format {
+	return FormatShipShape
+}
+
+// Parse reads an encoded report. The report keeps the format it was
+// originally parsed from, and its counters are recomputed from its files.
+func (p *JSONParser) Parse(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to decode coverage report: %w", err)
+	}
+
+	for i, f := range report.Files {
+		if f == nil || f.Path == "" {
+			return nil, fmt.Errorf("file %d has no path", i)
+		}
+	}
+
+	if report.Format == "" {
+		report.Format = FormatShipShape
+	}
+
+	return NewReport(report.Format, report.Files), nil
+}
@@ -0,0 +1,260 @@
+package coverage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LCOVParser parses LCOV tracefiles, as written by nyc, c8, Jest, Vitest,
+// lcov and grcov. A tracefile holds one record per source file and test
+// name; records of the same file are merged by adding their hits.
+type LCOVParser struct {
+	paths resolver
+	bases []string
+}
+
+// NewLCOVParser creates a parser resolving file names relative to the
+// repository root. Relative names are first tried under dir, the directory
+// the tests ran in relative to the root; an empty dir means the root.
+func NewLCOVParser(root, dir string) *LCOVParser {
+	return &LCOVParser{paths: resolver{root: root}, bases: baseDirs(dir)}
+}
+
+// Format returns FormatLCOV.
+func (p *LCOVParser) Format() Format {
+	return FormatLCOV
+}
+
+// Parse reads a tracefile. Line hits come from DA records, functions from
+// FN and FNDA records and branch outcomes from BRDA records; a branch whose
+// block never ran ("-") has no hits. Summary records (LF, LH, FNF, FNH,
+// BRF, BRH) are recomputed from the data and ignored.
+//
+//nolint:gocognit,gocyclo // One case per LCOV record type
+func (p *LCOVParser) Parse(r io.Reader) (*Report, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		number  int
+		names   []string
+		records = make(map[string]*record)
+		current *record
+		indexes map[branchKey]int
+	)
+
+	for scanner.Scan() {
+		number++
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if line == "end_of_record" {
+			current = nil
+			continue
+		}
+
+		kind, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid record %q", number, line)
+		}
+
+		if kind == "SF" {
+			name := p.paths.resolve(value, p.bases)
+
+			current = records[name]
+			if current == nil {
+				current = newRecord()
+				records[name] = current
+				names = append(names, name)
+			}
+
+			indexes = make(map[branchKey]int)
+
+			continue
+		}
+
+		switch kind {
+		case "DA", "FN", "FNDA", "BRDA":
+			if current == nil {
+				return nil, fmt.Errorf("line %d: %s record outside a source file", number, kind)
+			}
+		default:
+			continue
+		}
+
+		var err error
+
+		switch kind {
+		case "DA":
+			err = parseLCOVLine(current, value)
+		case "FN":
+			err = parseLCOVFunction(current, value)
+		case "FNDA":
+			err = parseLCOVFunctionHits(current, value)
+		case "BRDA":
+			err = parseLCOVBranch(current, value, indexes)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid %s record %q", number, kind, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read LCOV tracefile: %w", err)
+	}
+
+	files := make([]*File, 0, len(names))
+	for _, name := range names {
+		files = append(files, records[name].file(name))
+	}
+
+	return NewReport(FormatLCOV, files), nil
+}
+
+// parseLCOVLine parses "DA:<line>,<hits>[,<checksum>]".
+func parseLCOVLine(rec *record, value string) error {
+	fields := strings.Split(value, ",")
+	if len(fields) < 2 {
+		return fmt.Errorf("missing hits")
+	}
+
+	line, err := lcovLineNumber(fields[0])
+	if err != nil {
+		return err
+	}
+
+	hits, err := lcovHits(fields[1])
+	if err != nil {
+		return err
+	}
+
+	rec.line(line, hits)
+
+	return nil
+}
+
+// parseLCOVFunction parses "FN:<line>,<name>" and the
+// "FN:<line>,<end line>,<name>" form of lcov 2.
+func parseLCOVFunction(rec *record, value string) error {
+	first, name, ok := strings.Cut(value, ",")
+	if !ok || name == "" {
+		return fmt.Errorf("missing name")
+	}
+
+	line, err := lcovLineNumber(first)
+	if err != nil {
+		return err
+	}
+
+	var endLine int
+
+	if second, rest, ok := strings.Cut(name, ","); ok && rest != "" {
+		if end, err := strconv.Atoi(second); err == nil && end >= line {
+			endLine, name = end, rest
+		}
+	}
+
+	rec.function(name, line, endLine)
+
+	return nil
+}
+
+// parseLCOVFunctionHits parses "FNDA:<hits>,<name>".
+func parseLCOVFunctionHits(rec *record, value string) error {
+	first, name, ok := strings.Cut(value, ",")
+	if !ok || name == "" {
+		return fmt.Errorf("missing name")
+	}
+
+	hits, err := lcovHits(first)
+	if err != nil {
+		return err
+	}
+
+	rec.function(name, 0, 0).Hits += hits
+
+	return nil
+}
+
+// parseLCOVBranch parses "BRDA:<line>,<block>,<branch>,<taken>". Exception
+// blocks are prefixed with "e", and lcov 2 may describe the branch by an
+// expression rather than a number; such branches are numbered in order of
+// appearance within their block.
+func parseLCOVBranch(rec *record, value string, indexes map[branchKey]int) error {
+	fields := strings.Split(value, ",")
+	if len(fields) < 4 {
+		return fmt.Errorf("missing fields")
+	}
+
+	line, err := lcovLineNumber(fields[0])
+	if err != nil {
+		return err
+	}
+
+	block, err := strconv.Atoi(strings.TrimPrefix(fields[1], "e"))
+	if err != nil || block < 0 {
+		return fmt.Errorf("invalid block %q", fields[1])
+	}
+
+	taken := fields[len(fields)-1]
+
+	branch, err := strconv.Atoi(strings.Join(fields[2:len(fields)-1], ","))
+	if err != nil {
+		key := branchKey{line: line, block: block}
+		branch = indexes[key]
+		indexes[key]++
+	}
+
+	var hits int64
+
+	if taken != "-" {
+		if hits, err = lcovHits(taken); err != nil {
+			return err
+		}
+	}
+
+	rec.branch(branchKey{line: line, block: block, branch: branch}, hits)
+
+	return nil
+}
+
+// lcovLineNumber parses a 1-based line number.
+func lcovLineNumber(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid line number %q", s)
+	}
+
+	return n, nil
+}
+
+// lcovHits parses an execution count. Some tools write counts as floats.
+func lcovHits(s string) (int64, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n >= 0 {
+		return n, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid hits %q", s)
+	}
+
+	return int64(f), nil
+}
+
+// baseDirs returns the base directories of relative file names for a test
+// directory, or none for the root.
+func baseDirs(dir string) []string {
+	if dir == "" || dir == "." {
+		return nil
+	}
+
+	return []string{dir}
+}
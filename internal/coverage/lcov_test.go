@@ -0,0 +1,127 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+func TestLCOVParser(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "packages/web/src/cart.js", "")
+	testutil.WriteFile(t, dir, "packages/web/src/price.js", "")
+
+	tracefile := `TN:unit
+SF:src/cart.js
+FN:1,5,add
+FN:7,remove
+FNDA:2,add
+FNDA:0,remove
+FNF:2
+FNH:1
+BRDA:2,0,0,2
+BRDA:2,0,1,0
+BRDA:8,e1,0,-
+DA:1,1
+DA:2,2
+DA:3,0
+DA:7,1
+DA:8,0,abc123
+LF:5
+LH:3
+end_of_record
+TN:integration
+SF:/ci/build/packages/web/src/cart.js
+FNDA:1,remove
+BRDA:2,0,1,3
+DA:3,1
+DA:8,1
+end_of_record
+SF:src/price.js
+BRDA:4,0,(a > 0) == True,1
+BRDA:4,0,(a > 0) == False,0
+DA:4,1.0
+end_of_record
+`
+
+	report, err := NewLCOVParser(dir, "packages/web").Parse(strings.NewReader(tracefile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(report.Files) != 2 {
+		t.Fatalf("Files = %+v, want both SF records of cart.js merged", report.Files)
+	}
+
+	cart := report.File("packages/web/src/cart.js")
+	if cart == nil {
+		t.Fatalf("Files = %+v, want cart.js resolved under the test directory", report.Files)
+	}
+
+	wantLines := []Line{{Number: 1, Hits: 1}, {Number: 2, Hits: 2}, {Number: 3, Hits: 1}, {Number: 7, Hits: 1}, {Number: 8, Hits: 1}}
+	if len(cart.Lines) != len(wantLines) {
+		t.Fatalf("Lines = %+v, want %+v", cart.Lines, wantLines)
+	}
+
+	for i, l := range wantLines {
+		if cart.Lines[i] != l {
+			t.Errorf("Lines[%d] = %+v, want %+v", i, cart.Lines[i], l)
+		}
+	}
+
+	wantFunctions := []Function{{Name: "add", Line: 1, EndLine: 5, Hits: 2}, {Name: "remove", Line: 7, Hits: 1}}
+	if len(cart.Functions) != 2 || cart.Functions[0] != wantFunctions[0] || cart.Functions[1] != wantFunctions[1] {
+		t.Errorf("Functions = %+v, want %+v", cart.Functions, wantFunctions)
+	}
+
+	wantBranches := []Branch{{Line: 2, Hits: 2}, {Line: 2, Branch: 1, Hits: 3}, {Line: 8, Block: 1}}
+	if len(cart.Branches) != len(wantBranches) {
+		t.Fatalf("Branches = %+v, want %+v", cart.Branches, wantBranches)
+	}
+
+	for i, b := range wantBranches {
+		if cart.Branches[i] != b {
+			t.Errorf("Branches[%d] = %+v, want %+v", i, cart.Branches[i], b)
+		}
+	}
+
+	price := report.File("packages/web/src/price.js")
+	if price == nil || len(price.Branches) != 2 || price.Branches[1] != (Branch{Line: 4, Branch: 1}) || price.Lines[0].Hits != 1 {
+		t.Errorf("price.js = %+v, want expression branches numbered in order", price)
+	}
+
+	want := Metrics{
+		Lines:     Counter{Covered: 6, Total: 6},
+		Branches:  Counter{Covered: 3, Total: 5},
+		Functions: Counter{Covered: 2, Total: 2},
+	}
+	if report.Metrics != want {
+		t.Errorf("Metrics = %+v, want %+v", report.Metrics, want)
+	}
+}
+
+func TestLCOVParserErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		tracefile string
+		want      string
+	}{
+		{name: "no colon", tracefile: "SF:a.js\nnonsense\n", want: `line 2: invalid record "nonsense"`},
+		{name: "outside file", tracefile: "DA:1,1\n", want: "line 1: DA record outside a source file"},
+		{name: "after end", tracefile: "SF:a.js\nend_of_record\nFNDA:1,f\n", want: "line 3: FNDA record outside a source file"},
+		{name: "bad line", tracefile: "SF:a.js\nDA:0,1\n", want: `invalid DA record "0,1"`},
+		{name: "bad hits", tracefile: "SF:a.js\nDA:1,-2\n", want: `invalid DA record "1,-2"`},
+		{name: "bad function", tracefile: "SF:a.js\nFN:1\n", want: `invalid FN record "1"`},
+		{name: "bad branch", tracefile: "SF:a.js\nBRDA:1,x,0,1\n", want: `invalid BRDA record "1,x,0,1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLCOVParser(".", "").Parse(strings.NewReader(tt.tracefile))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package coverage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// reportDirs are the directories the walker excludes by default that
+// coverage tools write their reports to.
+var reportDirs = []string{"coverage", "htmlcov", "target", "build"}
+
+// reportExts are the extensions of the files whose content is sniffed when
+// reports are auto-detected.
+var reportExts = map[string]bool{
+	".out":          true,
+	".txt":          true,
+	".cov":          true,
+	".coverprofile": true,
+	".xml":          true,
+	".info":         true,
+	".lcov":         true,
+	".json":         true,
+}
+
+// formatLanguages are the languages whose sources a format covers. Formats
+// that are not listed cover any language.
+var formatLanguages = map[Format][]types.Language{
+	FormatGoCover:  {types.LanguageGo},
+	FormatIstanbul: {types.LanguageJavaScript, types.LanguageTypeScript},
+	FormatJaCoCo:   {types.LanguageJava},
+}
+
+// Located is a coverage report found in a repository.
+type Located struct {
+	// Path is the report path, relative to the repository root
+	Path string `json:"path"`
+
+	// Format is the format detected from the report content
+	Format Format `json:"format"`
+
+	// Workspace is the name of the workspace the report belongs to
+	Workspace string `json:"workspace,omitempty"`
+
+	// WorkspacePath is the path of that workspace, relative to the root
+	WorkspacePath string `json:"workspace_path,omitempty"`
+
+	// ModTime is when the report was last written
+	ModTime time.Time `json:"modified"`
+
+	// Stale reports whether a source the report covers changed after the
+	// report was written
+	Stale bool `json:"stale"`
+
+	// NewestSource is the most recently modified source the report covers,
+	// set for stale reports
+	NewestSource string `json:"newest_source,omitempty"`
+}
+
+// Locator finds the coverage reports of a repository. Configured paths are
+// matched as globs; otherwise every file with a report-like extension is a
+// candidate, including those in the coverage, htmlcov, target and build
+// directories the walker normally skips. Either way the format is sniffed
+// from the content.
+type Locator struct {
+	root       string
+	patterns   []string
+	workspaces []types.Workspace
+}
+
+// NewLocator creates a locator for the repository at root.
+func NewLocator(root string, cfg *Config, workspaces []types.Workspace) *Locator {
+	return &Locator{root: root, patterns: cfg.Paths, workspaces: workspaces}
+}
+
+// Workspaces returns the workspaces of the repository.
+func (l *Locator) Workspaces() []types.Workspace {
+	return l.workspaces
+}
+
+// Locate returns the reports of the repository, sorted by path. Each report
+// is associated with the innermost workspace containing it, preferring a
+// workspace of a language the format covers. A report older than one of
+// the source files it lists is marked stale and logged as a warning;
+// reports that cannot be parsed are not checked.
+func (l *Locator) Locate() ([]Located, error) {
+	walker := discovery.NewWalker(l.root)
+	walker.ExcludePatterns = slices.DeleteFunc(slices.Clone(walker.ExcludePatterns), func(p string) bool {
+		return slices.Contains(reportDirs, p)
+	})
+
+	var (
+		candidates []discovery.FileInfo
+		sources    = make(map[string]time.Time)
+		matched    = make(map[string]bool)
+	)
+
+	_, err := walker.Walk(func(fi discovery.FileInfo) error {
+		rel := filepath.ToSlash(fi.RelPath)
+
+		if l.candidate(rel, fi.Ext, matched) {
+			candidates = append(candidates, fi)
+		}
+
+		if discovery.LanguageOf(fi.Name) != types.LanguageUnknown && !inReportDir(rel) {
+			sources[rel] = fi.ModTime
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	for _, p := range l.patterns {
+		if !matched[p] {
+			logger.Warn("Coverage path matched no files", "pattern", p)
+		}
+	}
+
+	var reports []Located
+
+	for _, fi := range candidates {
+		rel := filepath.ToSlash(fi.RelPath)
+
+		format, ok := sniffFile(fi.Path)
+		if !ok {
+			if len(l.patterns) > 0 {
+				logger.Warn("Not a supported coverage report", "path", rel)
+			}
+
+			continue
+		}
+
+		r := Located{Path: rel, Format: format, ModTime: fi.ModTime}
+
+		if ws := l.workspaceFor(rel, format); ws != nil {
+			r.Workspace, r.WorkspacePath = ws.Name, ws.Path
+		}
+
+		l.checkStale(&r, sources)
+
+		reports = append(reports, r)
+	}
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Path < reports[j].Path })
+
+	return reports, nil
+}
+
+// Describe describes the report at a path relative to the working
+// directory, detecting its format and, for reports under the root, the
+// workspace it belongs to. Reports outside the root keep their absolute
+// path. Staleness is not checked.
+func (l *Locator) Describe(p string) (Located, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return Located{}, fmt.Errorf("failed to read coverage report: %w", err)
+	}
+
+	format, ok := sniffFile(p)
+	if !ok {
+		return Located{}, fmt.Errorf("%s is not a supported coverage report", p)
+	}
+
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return Located{}, fmt.Errorf("failed to resolve %s: %w", p, err)
+	}
+
+	r := Located{Path: filepath.ToSlash(abs), Format: format, ModTime: info.ModTime()}
+
+	if rel, ok := (resolver{root: l.root}).under(r.Path); ok {
+		r.Path = rel
+
+		if ws := l.workspaceFor(rel, format); ws != nil {
+			r.Workspace, r.WorkspacePath = ws.Name, ws.Path
+		}
+	}
+
+	return r, nil
+}
+
+// Parse parses a located report with the parser of its format, resolving
+// relative file names against its workspace. Report paths are relative to
+// the root unless absolute.
+func (l *Locator) Parse(r Located) (*Report, error) {
+	parser, err := NewParser(r.Format, l.root, r.WorkspacePath, l.workspaces)
+	if err != nil {
+		return nil, err
+	}
+
+	name := filepath.FromSlash(r.Path)
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(l.root, name)
+	}
+
+	f, err := os.Open(name) //nolint:gosec // Reading coverage reports from repository
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage report: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	report, err := parser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", r.Path, err)
+	}
+
+	return report, nil
+}
+
+// candidate reports whether a file may be a report, recording the
+// configured patterns it matches.
+func (l *Locator) candidate(rel, ext string, matched map[string]bool) bool {
+	if len(l.patterns) == 0 {
+		return reportExts[strings.ToLower(ext)]
+	}
+
+	found := false
+
+	for _, p := range l.patterns {
+		if discovery.MatchGlob(p, rel) {
+			matched[p] = true
+			found = true
+		}
+	}
+
+	return found
+}
+
+// workspaceFor returns the innermost workspace containing a report,
+// preferring workspaces of a language the format covers.
+func (l *Locator) workspaceFor(rel string, format Format) *types.Workspace {
+	if langs, ok := formatLanguages[format]; ok {
+		var fitting []types.Workspace
+
+		for _, ws := range l.workspaces {
+			if slices.Contains(langs, ws.Language) {
+				fitting = append(fitting, ws)
+			}
+		}
+
+		if ws := discovery.WorkspaceFor(fitting, rel); ws != nil {
+			return ws
+		}
+	}
+
+	return discovery.WorkspaceFor(l.workspaces, rel)
+}
+
+// checkStale marks a report stale when a source file it lists, found among
+// the modification times of the repository sources, changed after it.
+func (l *Locator) checkStale(r *Located, sources map[string]time.Time) {
+	report, err := l.Parse(*r)
+	if err != nil {
+		logger.Debug("Staleness of coverage report not checked", "report", r.Path, "error", err)
+		return
+	}
+
+	var newest time.Time
+
+	for _, f := range report.Files {
+		if modTime, ok := sources[f.Path]; ok && modTime.After(r.ModTime) && modTime.After(newest) {
+			newest, r.Stale, r.NewestSource = modTime, true, f.Path
+		}
+	}
+
+	if r.Stale {
+		logger.Warn("Coverage report is older than the sources it covers",
+			"report", r.Path, "source", r.NewestSource, "report_modified", r.ModTime, "source_modified", newest)
+	}
+}
+
+// sniffFile detects the format of the report at path.
+func sniffFile(path string) (Format, bool) {
+	f, err := os.Open(path) //nolint:gosec // Reading coverage reports from repository
+	if err != nil {
+		logger.Debug("Failed to open coverage candidate", "path", path, "error", err)
+		return "", false
+	}
+	defer func() { _ = f.Close() }()
+
+	return Sniff(f)
+}
+
+// inReportDir reports whether a path lies in a directory of reports or
+// build output, whose files are not sources.
+func inReportDir(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if slices.Contains(reportDirs, part) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,142 @@
+package coverage
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// writeCoverageRepo writes a repository with a Go module at the root, an
+// npm workspace in web and their reports. The Go profile predates its
+// source.
+func writeCoverageRepo(t *testing.T) (string, []types.Workspace) {
+	t.Helper()
+
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+	testutil.WriteFile(t, dir, "calc.go", "package app\n\nfunc One() int {\n\treturn 1\n}\n")
+	testutil.WriteFile(t, dir, "coverage/unit.txt", "mode: set\nexample.com/app/calc.go:3.16,5.2 1 1\n")
+	testutil.WriteFile(t, dir, "web/package.json", `{"name": "web"}`)
+	testutil.WriteFile(t, dir, "web/src/cart.js", "module.exports = 1;\n")
+	testutil.WriteFile(t, dir, "web/coverage/lcov.info", "SF:src/cart.js\nDA:1,1\nend_of_record\n")
+	testutil.WriteFile(t, dir, "web/coverage/lcov-report/index.html", "<html></html>")
+	testutil.WriteFile(t, dir, "notes.txt", "not a report\n")
+
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "coverage", "unit.txt"), past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	workspaces := []types.Workspace{
+		{Name: "example.com/app", Path: ".", Language: types.LanguageGo, Type: types.WorkspaceTypeGo},
+		{Name: "web", Path: "web", Language: types.LanguageJavaScript, Type: types.WorkspaceTypeNpm},
+	}
+
+	return dir, workspaces
+}
+
+func TestLocator(t *testing.T) {
+	dir, workspaces := writeCoverageRepo(t)
+
+	locator := NewLocator(dir, DefaultConfig(), workspaces)
+
+	reports, err := locator.Locate()
+	if err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+
+	if len(reports) != 2 {
+		t.Fatalf("reports = %+v, want the Go profile and the LCOV tracefile", reports)
+	}
+
+	profile, lcov := reports[0], reports[1]
+
+	if profile.Path != "coverage/unit.txt" || profile.Format != FormatGoCover || profile.WorkspacePath != "." ||
+		!profile.Stale || profile.NewestSource != "calc.go" {
+		t.Errorf("profile = %+v, want a stale go-cover report of the root module", profile)
+	}
+
+	if lcov.Path != "web/coverage/lcov.info" || lcov.Format != FormatLCOV || lcov.Workspace != "web" || lcov.Stale {
+		t.Errorf("lcov = %+v, want a fresh lcov report of web", lcov)
+	}
+
+	report, err := locator.Parse(lcov)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(report.Files) != 1 || report.Files[0].Path != "web/src/cart.js" {
+		t.Errorf("Files = %+v, want names resolved against the workspace", report.Files)
+	}
+}
+
+func TestLocatorConfiguredPaths(t *testing.T) {
+	dir, workspaces := writeCoverageRepo(t)
+
+	cfg := &Config{Paths: []string{"web/coverage/*.info", "notes.txt", "missing/*.xml"}}
+
+	reports, err := NewLocator(dir, cfg, workspaces).Locate()
+	if err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+
+	if len(reports) != 1 || reports[0].Path != "web/coverage/lcov.info" {
+		t.Errorf("reports = %+v, want only the configured report", reports)
+	}
+}
+
+func TestLocatorStaleness(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "app/x.py", "x = 1\n")
+	testutil.WriteFile(t, dir, "coverage.xml", `<?xml version="1.0" ?>
+<coverage><packages><package name="app"><classes>
+<class name="x.py" filename="app/x.py"><lines><line number="1" hits="1"/></lines></class>
+</classes></package></packages></coverage>
+`)
+	testutil.WriteFile(t, dir, "cmd/main.go", "package main\n")
+
+	past := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "app", "x.py"), past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	written := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "coverage.xml"), written, written); err != nil {
+		t.Fatal(err)
+	}
+
+	reports, err := NewLocator(dir, DefaultConfig(), nil).Locate()
+	if err != nil {
+		t.Fatalf("Locate() error = %v", err)
+	}
+
+	if len(reports) != 1 || reports[0].Stale {
+		t.Errorf("reports = %+v, want a fresh report despite a newer source it does not list", reports)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	if err := v.ReadConfig(strings.NewReader("coverage:\n  paths: [\"coverage.out\", \"web/coverage/lcov.info\"]\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(v)
+	if err != nil || len(cfg.Paths) != 2 || cfg.Paths[1] != "web/coverage/lcov.info" {
+		t.Errorf("LoadConfig() = %+v, %v", cfg, err)
+	}
+
+	v.Set("coverage.paths", []string{"/tmp/coverage.out"})
+
+	if _, err := LoadConfig(v); err == nil || !strings.Contains(err.Error(), "must be relative") {
+		t.Errorf("LoadConfig() error = %v, want relative path error", err)
+	}
+}
@@ -0,0 +1,336 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeMode is how the hits of a line, branch or function reported by
+// several reports combine.
+type MergeMode string
+
+// Merge modes.
+const (
+	// MergeSum adds the hits, for reports of different tests such as CI
+	// shards or unit and integration suites
+	MergeSum MergeMode = "sum"
+
+	// MergeMax keeps the highest hits, for reports of the same tests such
+	// as reruns
+	MergeMax MergeMode = "max"
+)
+
+// ParseMergeMode parses a merge mode name.
+func ParseMergeMode(s string) (MergeMode, error) {
+	switch mode := MergeMode(strings.ToLower(s)); mode {
+	case MergeSum, MergeMax:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown merge mode %q (expected sum or max)", s)
+	}
+}
+
+// mergedFile accumulates the coverage of a file across reports.
+type mergedFile struct {
+	path      string
+	pkg       string
+	files     []*File
+	lines     map[int]int64
+	branches  map[branchKey]int64
+	blocks    map[span]*goBlock
+	functions []*Function
+}
+
+// Merge unions the files of several reports. Files reported more than once
+// have their lines, branches, functions and Go profile blocks combined by
+// mode and their line, branch, function and statement counters recomputed;
+// counters without per-item data (statements of other formats,
+// instructions, complexity, classes) keep the highest count of any report,
+// a lower bound of the merged coverage. Functions of different formats are
+// matched by their unqualified name and line. Unresolved absolute paths
+// that end with the path of another file are taken to be that file.
+//
+// The merged report keeps the format of its reports when they share one.
+func Merge(reports []*Report, mode MergeMode) *Report {
+	var format Format
+
+	paths := make(map[string]bool)
+
+	for i, r := range reports {
+		switch {
+		case i == 0:
+			format = r.Format
+		case r.Format != format:
+			format = FormatShipShape
+		}
+
+		for _, f := range r.Files {
+			paths[f.Path] = true
+		}
+	}
+
+	canonical := canonicalPaths(paths)
+	merged := make(map[string]*mergedFile)
+
+	for _, r := range reports {
+		for _, f := range r.Files {
+			p := canonical[f.Path]
+
+			m := merged[p]
+			if m == nil {
+				m = &mergedFile{
+					path:     p,
+					lines:    make(map[int]int64),
+					branches: make(map[branchKey]int64),
+					blocks:   make(map[span]*goBlock),
+				}
+				merged[p] = m
+			}
+
+			m.add(f, mode)
+		}
+	}
+
+	files := make([]*File, 0, len(merged))
+	for _, m := range merged {
+		files = append(files, m.file())
+	}
+
+	if format == "" {
+		format = FormatShipShape
+	}
+
+	return NewReport(format, files)
+}
+
+// canonicalPaths maps every path onto the path its file is merged under:
+// itself, or for an absolute path the single relative path it ends with.
+func canonicalPaths(paths map[string]bool) map[string]string {
+	var relative []string
+
+	for p := range paths {
+		if !isAbsSlash(p) {
+			relative = append(relative, p)
+		}
+	}
+
+	sort.Strings(relative)
+
+	canonical := make(map[string]string, len(paths))
+
+	for p := range paths {
+		canonical[p] = p
+
+		if !isAbsSlash(p) {
+			continue
+		}
+
+		var matches []string
+
+		for _, rel := range relative {
+			if strings.HasSuffix(p, "/"+rel) {
+				matches = append(matches, rel)
+			}
+		}
+
+		if len(matches) == 1 {
+			canonical[p] = matches[0]
+		}
+	}
+
+	return canonical
+}
+
+// add combines the coverage of a file of one report.
+func (m *mergedFile) add(f *File, mode MergeMode) {
+	m.files = append(m.files, f)
+
+	if m.pkg == "" {
+		m.pkg = f.Package
+	}
+
+	for _, l := range f.Lines {
+		combine(m.lines, l.Number, l.Hits, mode)
+	}
+
+	for _, b := range f.Branches {
+		combine(m.branches, branchKey{line: b.Line, block: b.Block, branch: b.Branch}, b.Hits, mode)
+	}
+
+	for _, b := range f.blocks {
+		existing, ok := m.blocks[b.span]
+		if !ok {
+			copied := b
+			m.blocks[b.span] = &copied
+
+			continue
+		}
+
+		existing.count = combineHits(existing.count, b.count, mode)
+	}
+
+	for _, fn := range f.Functions {
+		existing := m.function(fn)
+		if existing == nil {
+			copied := fn
+			m.functions = append(m.functions, &copied)
+
+			continue
+		}
+
+		existing.Hits = combineHits(existing.Hits, fn.Hits, mode)
+		existing.EndLine = max(existing.EndLine, fn.EndLine)
+		existing.Statements = maxCounter(existing.Statements, fn.Statements)
+	}
+}
+
+// function returns the merged function that fn describes, or nil. Formats
+// qualify names differently ("calc.Add", "Add") and start functions at
+// their declaration or at their first executable line, so functions match
+// when their unqualified names agree and either starts within the other.
+func (m *mergedFile) function(fn Function) *Function {
+	name := unqualified(fn.Name)
+
+	for _, existing := range m.functions {
+		if unqualified(existing.Name) != name {
+			continue
+		}
+
+		if existing.Line == fn.Line || within(fn.Line, *existing) || within(existing.Line, fn) {
+			return existing
+		}
+	}
+
+	return nil
+}
+
+// unqualified returns a function name without its package or type.
+func unqualified(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+
+	return name
+}
+
+// within reports whether a line lies within the known extent of fn.
+func within(line int, fn Function) bool {
+	return line >= fn.Line && line <= fn.EndLine
+}
+
+// file builds the merged coverage of the file. A file of a single report is
+// kept as reported.
+func (m *mergedFile) file() *File {
+	if len(m.files) == 1 {
+		f := *m.files[0]
+		f.Path = m.path
+
+		return &f
+	}
+
+	f := &File{Path: m.path, Package: m.pkg}
+
+	for number, hits := range m.lines {
+		f.Lines = append(f.Lines, Line{Number: number, Hits: hits})
+	}
+
+	for key, hits := range m.branches {
+		f.Branches = append(f.Branches, Branch{Line: key.line, Block: key.block, Branch: key.branch, Hits: hits})
+	}
+
+	for _, fn := range m.functions {
+		f.Functions = append(f.Functions, m.blockFunction(*fn))
+	}
+
+	for _, b := range m.blocks {
+		f.blocks = append(f.blocks, *b)
+		f.Metrics.Statements.Total += b.stmts
+
+		if b.count > 0 {
+			f.Metrics.Statements.Covered += b.stmts
+		}
+	}
+
+	for _, other := range m.files {
+		mm := other.Metrics
+
+		// Statements of Go profiles are counted from the merged blocks.
+		if len(other.blocks) == 0 {
+			f.Metrics.Statements = maxCounter(f.Metrics.Statements, mm.Statements)
+		}
+
+		f.Metrics.Instructions = maxCounter(f.Metrics.Instructions, mm.Instructions)
+		f.Metrics.Complexity = maxCounter(f.Metrics.Complexity, mm.Complexity)
+		f.Metrics.Classes = maxCounter(f.Metrics.Classes, mm.Classes)
+
+		// Counters of reports without the items are kept when no report
+		// lists them.
+		if len(m.lines) == 0 {
+			f.Metrics.Lines = maxCounter(f.Metrics.Lines, mm.Lines)
+		}
+
+		if len(m.branches) == 0 {
+			f.Metrics.Branches = maxCounter(f.Metrics.Branches, mm.Branches)
+		}
+
+		if len(m.functions) == 0 {
+			f.Metrics.Functions = maxCounter(f.Metrics.Functions, mm.Functions)
+		}
+	}
+
+	return f
+}
+
+// blockFunction recomputes the statements and hits of a function from the
+// merged Go profile blocks within its lines. Functions without blocks are
+// returned unchanged.
+func (m *mergedFile) blockFunction(fn Function) Function {
+	var (
+		statements Counter
+		hits       int64
+	)
+
+	for _, b := range m.blocks {
+		if b.startLine < fn.Line || b.endLine > max(fn.EndLine, fn.Line) {
+			continue
+		}
+
+		statements.Total += b.stmts
+
+		if b.count > 0 {
+			statements.Covered += b.stmts
+		}
+
+		hits = max(hits, b.count)
+	}
+
+	if statements.Total > 0 {
+		fn.Statements, fn.Hits = statements, hits
+	}
+
+	return fn
+}
+
+// combine combines hits into the entry of a key by mode.
+func combine[K comparable](hits map[K]int64, key K, h int64, mode MergeMode) {
+	if existing, ok := hits[key]; ok {
+		h = combineHits(existing, h, mode)
+	}
+
+	hits[key] = h
+}
+
+// combineHits combines two hit counts by mode.
+func combineHits(a, b int64, mode MergeMode) int64 {
+	if mode == MergeMax {
+		return max(a, b)
+	}
+
+	return a + b
+}
+
+// maxCounter returns the highest covered and total counts of two counters.
+func maxCounter(a, b Counter) Counter {
+	return Counter{Covered: max(a.Covered, b.Covered), Total: max(a.Total, b.Total)}
+}
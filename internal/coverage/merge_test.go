@@ -0,0 +1,171 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+func TestMerge(t *testing.T) {
+	unit := NewReport(FormatLCOV, []*File{
+		{
+			Path:      "src/cart.js",
+			Lines:     []Line{{Number: 1, Hits: 2}, {Number: 2, Hits: 0}, {Number: 3, Hits: 1}},
+			Branches:  []Branch{{Line: 2, Branch: 0, Hits: 0}, {Line: 2, Branch: 1, Hits: 1}},
+			Functions: []Function{{Name: "total", Line: 1, Hits: 2}},
+		},
+		{
+			Path:  "src/only-unit.js",
+			Lines: []Line{{Number: 1, Hits: 1}},
+		},
+	})
+
+	integration := NewReport(FormatLCOV, []*File{
+		{
+			Path:      "/ci/checkout/src/cart.js",
+			Lines:     []Line{{Number: 1, Hits: 3}, {Number: 2, Hits: 4}},
+			Branches:  []Branch{{Line: 2, Branch: 0, Hits: 1}, {Line: 2, Branch: 1, Hits: 0}},
+			Functions: []Function{{Name: "total", Line: 1, Hits: 3}, {Name: "clear", Line: 5, Hits: 0}},
+		},
+	})
+
+	tests := []struct {
+		mode      MergeMode
+		wantLine1 int64
+		wantTotal int64
+	}{
+		{mode: MergeSum, wantLine1: 5, wantTotal: 5},
+		{mode: MergeMax, wantLine1: 3, wantTotal: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			merged := Merge([]*Report{unit, integration}, tt.mode)
+
+			if merged.Format != FormatLCOV {
+				t.Errorf("Format = %q, want the shared format", merged.Format)
+			}
+
+			if len(merged.Files) != 2 {
+				t.Fatalf("Files = %+v, want the absolute path folded into src/cart.js", merged.Files)
+			}
+
+			cart := merged.File("src/cart.js")
+			if cart == nil {
+				t.Fatal("src/cart.js missing")
+			}
+
+			if cart.Lines[0].Hits != tt.wantLine1 || cart.Functions[0].Hits != tt.wantTotal {
+				t.Errorf("hits: line 1 = %d, total = %d, want %d, %d",
+					cart.Lines[0].Hits, cart.Functions[0].Hits, tt.wantLine1, tt.wantTotal)
+			}
+
+			want := Metrics{
+				Lines:     Counter{Covered: 3, Total: 3},
+				Branches:  Counter{Covered: 2, Total: 2},
+				Functions: Counter{Covered: 1, Total: 2},
+			}
+			if cart.Metrics != want {
+				t.Errorf("Metrics = %+v, want %+v", cart.Metrics, want)
+			}
+
+			if merged.Metrics.Lines != (Counter{Covered: 4, Total: 4}) {
+				t.Errorf("report lines = %+v, want 4/4", merged.Metrics.Lines)
+			}
+		})
+	}
+}
+
+func TestMergeCounters(t *testing.T) {
+	goReport := NewReport(FormatGoCover, []*File{
+		{Path: "calc.go", Lines: []Line{{Number: 3, Hits: 1}}, Metrics: Metrics{Statements: Counter{Covered: 1, Total: 4}}},
+	})
+	cobertura := NewReport(FormatCobertura, []*File{
+		{Path: "calc.go", Lines: []Line{{Number: 4, Hits: 1}}, Metrics: Metrics{Statements: Counter{Covered: 2, Total: 4}}},
+	})
+
+	merged := Merge([]*Report{goReport, cobertura}, MergeSum)
+
+	if merged.Format != FormatShipShape {
+		t.Errorf("Format = %q, want %q for mixed formats", merged.Format, FormatShipShape)
+	}
+
+	f := merged.Files[0]
+	if f.Metrics.Lines != (Counter{Covered: 2, Total: 2}) || f.Metrics.Statements != (Counter{Covered: 2, Total: 4}) {
+		t.Errorf("Metrics = %+v, want lines recomputed and the highest statement count", f.Metrics)
+	}
+}
+
+func TestMergeGoProfiles(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "calc/calc.go", goCoverSource)
+
+	parser := NewGoProfileParser(dir, []types.Workspace{{Name: "example.com/calc", Path: ".", Type: types.WorkspaceTypeGo}})
+
+	// Two shards that only cover all statements together.
+	shards := []string{
+		`mode: count
+example.com/calc/calc/calc.go:4.24,6.2 1 2
+example.com/calc/calc/calc.go:9.24,10.12 1 1
+example.com/calc/calc/calc.go:10.12,12.3 1 0
+example.com/calc/calc/calc.go:14.2,14.14 1 1
+example.com/calc/calc/calc.go:19.21,21.2 1 0
+`,
+		`mode: count
+example.com/calc/calc/calc.go:4.24,6.2 1 0
+example.com/calc/calc/calc.go:9.24,10.12 1 1
+example.com/calc/calc/calc.go:10.12,12.3 1 1
+example.com/calc/calc/calc.go:14.2,14.14 1 0
+example.com/calc/calc/calc.go:19.21,21.2 1 3
+`,
+	}
+
+	var reports []*Report
+
+	for _, profile := range shards {
+		r, err := parser.Parse(strings.NewReader(profile))
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+
+		reports = append(reports, r)
+	}
+
+	// A Cobertura conversion names functions by package and starts them at
+	// their first executable line.
+	reports = append(reports, NewReport(FormatCobertura, []*File{{
+		Path:      "calc/calc.go",
+		Lines:     []Line{{Number: 5, Hits: 1}},
+		Functions: []Function{{Name: "calc.Add", Line: 5, EndLine: 5, Hits: 1}},
+	}}))
+
+	f := Merge(reports, MergeSum).File("calc/calc.go")
+	if f == nil {
+		t.Fatal("calc/calc.go missing")
+	}
+
+	if f.Metrics.Statements != (Counter{Covered: 5, Total: 5}) {
+		t.Errorf("Statements = %+v, want the union of the shards", f.Metrics.Statements)
+	}
+
+	if f.Metrics.Functions != (Counter{Covered: 3, Total: 3}) {
+		t.Errorf("Functions = %+v, want calc.Add matched with Add", f.Metrics.Functions)
+	}
+
+	div := f.Functions[1]
+	if div.Name != "Div" || div.Statements != (Counter{Covered: 3, Total: 3}) || div.Hits != 2 {
+		t.Errorf("Div = %+v, want its statements recomputed from the merged blocks", div)
+	}
+}
+
+func TestParseMergeMode(t *testing.T) {
+	if mode, err := ParseMergeMode("MAX"); err != nil || mode != MergeMax {
+		t.Errorf("ParseMergeMode(MAX) = %q, %v", mode, err)
+	}
+
+	if _, err := ParseMergeMode("average"); err == nil {
+		t.Error("ParseMergeMode(average) succeeded, want error")
+	}
+}
@@ -0,0 +1,121 @@
+package coverage
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// PatchFile is the coverage of the changed lines of a file.
+type PatchFile struct {
+	// Path is the file path, relative to the repository root
+	Path string `json:"path"`
+
+	// Lines counts the changed executable lines
+	Lines Counter `json:"lines"`
+
+	// Uncovered are the changed executable lines that never ran, sorted
+	Uncovered []int `json:"uncovered_lines,omitempty"`
+}
+
+// Patch is the coverage of the lines a change adds or modifies.
+type Patch struct {
+	// Lines counts the changed executable lines of every file
+	Lines Counter `json:"lines"`
+
+	// Percent is the covered percentage of the changed lines, 100 when no
+	// executable line changed
+	Percent float64 `json:"percent"`
+
+	// Files are the files with changed executable lines, sorted by path
+	Files []PatchFile `json:"files"`
+
+	// Unreported are the changed source files that no report covers, sorted
+	Unreported []string `json:"unreported,omitempty"`
+}
+
+// NewPatch intersects a report with the changed lines of each file. Only
+// lines the report lists as executable count; changed comments, blank lines
+// and declarations are ignored. Changed source files that are not tests and
+// are missing from the report are listed as unreported, since their
+// executable lines are unknown.
+func NewPatch(r *Report, changes map[string][]int) *Patch {
+	p := &Patch{Files: []PatchFile{}}
+
+	for name, changed := range changes {
+		f := r.File(name)
+		if f == nil {
+			if discovery.LanguageOf(path.Base(name)) != types.LanguageUnknown && !discovery.IsTestFile(name) {
+				p.Unreported = append(p.Unreported, name)
+			}
+
+			continue
+		}
+
+		hits := make(map[int]int64, len(f.Lines))
+		for _, l := range f.Lines {
+			hits[l.Number] = l.Hits
+		}
+
+		pf := PatchFile{Path: name}
+
+		for _, number := range changed {
+			h, ok := hits[number]
+			if !ok {
+				continue
+			}
+
+			pf.Lines.Count(h > 0)
+
+			if h == 0 {
+				pf.Uncovered = append(pf.Uncovered, number)
+			}
+		}
+
+		if pf.Lines.Total == 0 {
+			continue
+		}
+
+		sort.Ints(pf.Uncovered)
+
+		p.Files = append(p.Files, pf)
+		p.Lines.Add(pf.Lines)
+	}
+
+	sort.Slice(p.Files, func(i, j int) bool { return p.Files[i].Path < p.Files[j].Path })
+	sort.Strings(p.Unreported)
+
+	p.Percent = 100
+	if p.Lines.Total > 0 {
+		p.Percent = p.Lines.Percent()
+	}
+
+	return p
+}
+
+// LineRanges formats sorted line numbers as ranges, such as "3-5, 9".
+func LineRanges(lines []int) string {
+	var ranges []string
+
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+
+		i = j + 1
+	}
+
+	return strings.Join(ranges, ", ")
+}
@@ -0,0 +1,65 @@
+package coverage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPatch(t *testing.T) {
+	report := NewReport(FormatGoCover, []*File{
+		{Path: "cart.go", Lines: []Line{{Number: 3, Hits: 1}, {Number: 7, Hits: 1}, {Number: 8, Hits: 0}, {Number: 9, Hits: 0}, {Number: 12, Hits: 0}}},
+		{Path: "tax.go", Lines: []Line{{Number: 5, Hits: 2}}},
+		{Path: "docs.go", Lines: []Line{{Number: 10, Hits: 0}}},
+	})
+
+	changes := map[string][]int{
+		"cart.go":      {6, 7, 8, 9, 12, 13},
+		"tax.go":       {5},
+		"docs.go":      {1, 2},
+		"new.go":       {1, 2, 3},
+		"new_test.go":  {1},
+		"README.md":    {4},
+		"web/panel.ts": {9},
+	}
+
+	patch := NewPatch(report, changes)
+
+	if patch.Lines != (Counter{Covered: 2, Total: 5}) || patch.Percent != 40 {
+		t.Errorf("Lines = %+v, Percent = %v, want 2/5 and 40", patch.Lines, patch.Percent)
+	}
+
+	want := []PatchFile{
+		{Path: "cart.go", Lines: Counter{Covered: 1, Total: 4}, Uncovered: []int{8, 9, 12}},
+		{Path: "tax.go", Lines: Counter{Covered: 1, Total: 1}},
+	}
+	if !reflect.DeepEqual(patch.Files, want) {
+		t.Errorf("Files = %+v, want %+v", patch.Files, want)
+	}
+
+	if !reflect.DeepEqual(patch.Unreported, []string{"new.go", "web/panel.ts"}) {
+		t.Errorf("Unreported = %v, want the changed non-test sources", patch.Unreported)
+	}
+}
+
+func TestNewPatchWithoutExecutableChanges(t *testing.T) {
+	patch := NewPatch(NewReport(FormatLCOV, nil), map[string][]int{"README.md": {1}})
+
+	if patch.Percent != 100 || patch.Lines.Total != 0 || len(patch.Files) != 0 {
+		t.Errorf("patch = %+v, want 100%% of nothing", patch)
+	}
+}
+
+func TestLineRanges(t *testing.T) {
+	tests := map[string][]int{
+		"":            nil,
+		"7":           {7},
+		"3-5, 9":      {3, 4, 5, 9},
+		"1, 3, 10-11": {1, 3, 10, 11},
+	}
+
+	for want, lines := range tests {
+		if got := LineRanges(lines); got != want {
+			t.Errorf("LineRanges(%v) = %q, want %q", lines, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package coverage
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// resolver maps the file names recorded in coverage reports onto paths
+// relative to the repository root. Reports record names relative to the
+// directory the tests ran in, or absolute paths of the machine that ran
+// them, with either separator.
+type resolver struct {
+	root string
+}
+
+// resolve maps a file name of a report onto a repository path. Relative
+// names are tried under every base directory, then on their own. A
+// candidate that exists under the root wins; paths recorded on another
+// machine (a CI checkout, a container) match by the longest suffix of the
+// candidate that exists under the root. Names that match nothing are
+// returned cleaned.
+func (r resolver) resolve(name string, bases []string) string {
+	name = strings.ReplaceAll(name, `\`, "/")
+
+	var candidates []string
+
+	if !isAbsSlash(name) {
+		for _, base := range bases {
+			candidates = append(candidates, path.Join(strings.ReplaceAll(base, `\`, "/"), name))
+		}
+	}
+
+	candidates = append(candidates, name)
+
+	for _, c := range candidates {
+		if rel, ok := r.existing(c); ok {
+			return rel
+		}
+	}
+
+	for _, c := range candidates {
+		if rel, ok := r.under(c); ok {
+			return rel
+		}
+	}
+
+	return path.Clean(name)
+}
+
+// existing returns the repository path of a file that exists under the
+// root, trying the candidate itself and then its shorter suffixes.
+func (r resolver) existing(candidate string) (string, bool) {
+	if rel, ok := r.under(candidate); ok && r.exists(rel) {
+		return rel, true
+	}
+
+	parts := strings.Split(strings.TrimLeft(candidate, "/"), "/")
+	if len(parts) > 0 && strings.HasSuffix(parts[0], ":") {
+		parts = parts[1:]
+	}
+
+	for i := range parts {
+		rel := path.Join(parts[i:]...)
+		if rel != "." && !strings.HasPrefix(rel, "..") && r.exists(rel) {
+			return rel, true
+		}
+	}
+
+	return "", false
+}
+
+// under returns the repository path of an absolute path below the root.
+func (r resolver) under(candidate string) (string, bool) {
+	if !filepath.IsAbs(filepath.FromSlash(candidate)) {
+		return "", false
+	}
+
+	root, err := filepath.Abs(r.root)
+	if err != nil {
+		return "", false
+	}
+
+	rel, err := filepath.Rel(root, filepath.FromSlash(candidate))
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+
+	return filepath.ToSlash(rel), true
+}
+
+// exists reports whether a repository path is a regular file.
+func (r resolver) exists(rel string) bool {
+	info, err := os.Stat(filepath.Join(r.root, filepath.FromSlash(rel)))
+	return err == nil && info.Mode().IsRegular()
+}
+
+// isAbsSlash reports whether a slash-separated name is absolute on Unix or
+// Windows.
+func isAbsSlash(name string) bool {
+	return strings.HasPrefix(name, "/") || len(name) >= 3 && name[1] == ':' && name[2] == '/'
+}
@@ -0,0 +1,123 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// RatchetVersion is the version of the ratchet file format.
+const RatchetVersion = 1
+
+// DefaultRatchetFile is the ratchet path used when none is configured.
+const DefaultRatchetFile = ".shipshape-coverage-ratchet.json"
+
+// Ratchet records the highest coverage each package reached, below which
+// its coverage may not fall.
+type Ratchet struct {
+	// Version is the file format version
+	Version int `json:"version"`
+
+	// Packages maps package names to their recorded coverage
+	Packages map[string]RatchetEntry `json:"packages"`
+}
+
+// RatchetEntry is the recorded coverage of a package, in percent rounded
+// to two decimals. Metrics the reports did not provide are 0.
+type RatchetEntry struct {
+	// Line is the recorded line coverage
+	Line float64 `json:"line,omitempty"`
+
+	// Branch is the recorded branch coverage
+	Branch float64 `json:"branch,omitempty"`
+
+	// Function is the recorded function coverage
+	Function float64 `json:"function,omitempty"`
+}
+
+// NewRatchet creates a ratchet without recorded packages.
+func NewRatchet() *Ratchet {
+	return &Ratchet{Version: RatchetVersion, Packages: make(map[string]RatchetEntry)}
+}
+
+// LoadRatchet reads a ratchet file.
+func LoadRatchet(path string) (*Ratchet, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Ratchet path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ratchet: %w", err)
+	}
+
+	var r Ratchet
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse ratchet %s: %w", path, err)
+	}
+
+	if r.Version != RatchetVersion {
+		return nil, fmt.Errorf("unsupported ratchet version %d in %s (want %d)", r.Version, path, RatchetVersion)
+	}
+
+	if r.Packages == nil {
+		r.Packages = make(map[string]RatchetEntry)
+	}
+
+	return &r, nil
+}
+
+// Save writes the ratchet as indented JSON.
+func (r *Ratchet) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ratchet: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write ratchet: %w", err)
+	}
+
+	return nil
+}
+
+// Update raises the recorded coverage of the packages of a report to their
+// current coverage, never lowering it, and returns the number of packages
+// whose record changed. Packages the report does not cover keep their
+// record, and packages without covered items are not recorded.
+func (r *Ratchet) Update(report *Report) int {
+	changed := 0
+
+	for _, p := range report.Packages() {
+		old := r.Packages[p.Name]
+		entry := RatchetEntry{
+			Line:     raise(old.Line, p.Metrics.Lines),
+			Branch:   raise(old.Branch, p.Metrics.Branches),
+			Function: raise(old.Function, p.Metrics.Functions),
+		}
+
+		if entry == (RatchetEntry{}) {
+			continue
+		}
+
+		if _, ok := r.Packages[p.Name]; !ok || entry != old {
+			r.Packages[p.Name] = entry
+			changed++
+		}
+	}
+
+	return changed
+}
+
+// raise returns the higher of a recorded percentage and the percentage of
+// a counter with items.
+func raise(recorded float64, c Counter) float64 {
+	if c.Total == 0 {
+		return recorded
+	}
+
+	return max(recorded, roundPercent(c.Percent()))
+}
+
+// roundPercent rounds a percentage to two decimals, so that recorded and
+// measured percentages compare equal when nothing changed.
+func roundPercent(p float64) float64 {
+	return math.Round(p*100) / 100
+}
@@ -0,0 +1,164 @@
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+// sniffSize is the number of leading bytes read to detect the format of a
+// report.
+const sniffSize = 16 * 1024
+
+// jacocoChildren are the elements that open the <report> of a JaCoCo report.
+var jacocoChildren = map[string]bool{
+	"sessioninfo": true,
+	"group":       true,
+	"package":     true,
+	"counter":     true,
+}
+
+// Sniff detects the format of a coverage report from its leading bytes,
+// regardless of its file name. It reports false for content that is not a
+// report of a supported format, such as Clover XML or coverage.py JSON.
+func Sniff(r io.Reader) (Format, bool) {
+	head, err := io.ReadAll(io.LimitReader(r, sniffSize))
+	if err != nil {
+		return "", false
+	}
+
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	head = bytes.TrimLeft(head, " \t\r\n")
+
+	switch {
+	case len(head) == 0:
+		return "", false
+	case head[0] == '<':
+		return sniffXML(head)
+	case head[0] == '{' && sniffShipShape(head):
+		return FormatShipShape, true
+	case head[0] == '{' && sniffIstanbul(head):
+		return FormatIstanbul, true
+	case head[0] == '{':
+		return "", false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(head))
+	if !scanner.Scan() {
+		return "", false
+	}
+
+	first := strings.TrimSpace(scanner.Text())
+
+	if m, ok := strings.CutPrefix(first, "mode:"); ok {
+		switch strings.TrimSpace(m) {
+		case goModeSet, goModeCount, goModeAtomic:
+			return FormatGoCover, true
+		}
+
+		return "", false
+	}
+
+	if (strings.HasPrefix(first, "TN:") || strings.HasPrefix(first, "SF:")) && bytes.Contains(head, []byte("SF:")) {
+		return FormatLCOV, true
+	}
+
+	return "", false
+}
+
+// sniffXML tells Cobertura from JaCoCo reports by their root element.
+func sniffXML(head []byte) (Format, bool) {
+	dec := xml.NewDecoder(bytes.NewReader(head))
+
+	var (
+		doctype string
+		root    string
+	)
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", false
+		}
+
+		switch t := tok.(type) {
+		case xml.Directive:
+			doctype = string(t)
+		case xml.StartElement:
+			switch {
+			case root == "" && t.Name.Local == "coverage":
+				// Clover also writes a <coverage> root, marked by its version.
+				if xmlAttr(t, "clover") != "" {
+					return "", false
+				}
+
+				return FormatCobertura, true
+			case root == "" && t.Name.Local == "report":
+				if strings.Contains(doctype, "JACOCO") {
+					return FormatJaCoCo, true
+				}
+
+				root = t.Name.Local
+			case root == "":
+				return "", false
+			case jacocoChildren[t.Name.Local]:
+				return FormatJaCoCo, true
+			default:
+				return "", false
+			}
+		case xml.EndElement:
+			return "", false
+		}
+	}
+}
+
+// sniffShipShape reports whether a JSON document is an encoded Report, which
+// opens with its format.
+func sniffShipShape(head []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(head))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return false
+	}
+
+	tok, err := dec.Token()
+	if err != nil || tok != "format" {
+		return false
+	}
+
+	var format string
+	if err := dec.Decode(&format); err != nil {
+		return false
+	}
+
+	switch Format(format) {
+	case FormatGoCover, FormatCobertura, FormatLCOV, FormatIstanbul, FormatJaCoCo, FormatShipShape:
+		return true
+	}
+
+	return false
+}
+
+// sniffIstanbul reports whether a JSON document maps files to Istanbul
+// coverage data, recognized by their statement map.
+func sniffIstanbul(head []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(head))
+
+	var previous any
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return false
+		}
+
+		if delim, ok := tok.(json.Delim); ok && delim == '{' && previous == "statementMap" {
+			return true
+		}
+
+		previous = tok
+	}
+}
@@ -0,0 +1,43 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    Format
+		ok      bool
+	}{
+		{name: "go profile", content: "mode: atomic\nexample.com/a/a.go:1.1,2.2 1 0\n", want: FormatGoCover, ok: true},
+		{name: "go profile with unknown mode", content: "mode: sometimes\n", ok: false},
+		{name: "lcov", content: "TN:\nSF:src/a.js\nDA:1,1\nend_of_record\n", want: FormatLCOV, ok: true},
+		{name: "lcov without test name", content: "SF:/ci/src/a.js\nDA:1,1\n", want: FormatLCOV, ok: true},
+		{name: "cobertura", content: "\xef\xbb\xbf<?xml version=\"1.0\" ?>\n<!-- coverage.py -->\n<coverage line-rate=\"1\">", want: FormatCobertura, ok: true},
+		{name: "clover", content: `<?xml version="1.0"?><coverage generated="1" clover="4.4.1"><project/></coverage>`, ok: false},
+		{name: "jacoco by doctype", content: `<?xml version="1.0"?><!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd"><report name="a">`, want: FormatJaCoCo, ok: true},
+		{name: "jacoco by children", content: `<report name="a"><sessioninfo id="x"/>`, want: FormatJaCoCo, ok: true},
+		{name: "other report element", content: `<report><title>Lint</title></report>`, ok: false},
+		{name: "maven pom", content: `<?xml version="1.0"?><project><modelVersion>4.0.0</modelVersion></project>`, ok: false},
+		{name: "istanbul", content: `{"/src/a.js": {"path": "/src/a.js", "statementMap": {"0": {}}, "s": {"0": 1}}}`, want: FormatIstanbul, ok: true},
+		{name: "istanbul data wrapper", content: `{"a.js": {"data": {"path": "a.js", "statementMap": {}}}}`, want: FormatIstanbul, ok: true},
+		{name: "encoded report", content: `{"format": "lcov", "files": [], "metrics": {}}`, want: FormatShipShape, ok: true},
+		{name: "other format key", content: `{"format": "prettier", "files": []}`, ok: false},
+		{name: "package.json", content: `{"name": "app", "scripts": {"statementMap": "x"}}`, ok: false},
+		{name: "coverage.py json", content: `{"meta": {"version": "7.4.1"}, "files": {"a.py": {"executed_lines": [1]}}}`, ok: false},
+		{name: "empty", content: "   \n", ok: false},
+		{name: "text", content: "requests==2.31.0\n", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Sniff(strings.NewReader(tt.content))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Sniff() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,384 @@
+package coverage
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Check IDs of threshold findings.
+const (
+	// CheckCoverageBelowThreshold reports a workspace, package or file whose
+	// coverage is below its target
+	CheckCoverageBelowThreshold = "coverage-below-threshold"
+
+	// CheckCoverageRatchet reports a package whose coverage fell below its
+	// recorded value
+	CheckCoverageRatchet = "coverage-ratchet"
+)
+
+// Scope is the level a threshold applies to.
+type Scope string
+
+// Scopes of thresholds.
+const (
+	ScopeWorkspace Scope = "workspace"
+	ScopePackage   Scope = "package"
+	ScopeFile      Scope = "file"
+)
+
+// Metric is a coverage metric with a threshold.
+type Metric string
+
+// Metrics with thresholds.
+const (
+	MetricLine     Metric = "line"
+	MetricBranch   Metric = "branch"
+	MetricFunction Metric = "function"
+)
+
+// metricItems names the items a metric counts.
+var metricItems = map[Metric]string{
+	MetricLine:     "lines",
+	MetricBranch:   "branches",
+	MetricFunction: "functions",
+}
+
+// ThresholdResult compares a metric of a workspace, package or file with its
+// target.
+type ThresholdResult struct {
+	// Scope is the level of the result
+	Scope Scope `json:"scope"`
+
+	// Name is the workspace name, package name or file path
+	Name string `json:"name"`
+
+	// Path is the directory of the workspace or package, or the file path
+	Path string `json:"path"`
+
+	// Metric is the compared metric
+	Metric Metric `json:"metric"`
+
+	// Counter counts the covered items of the metric
+	Counter Counter `json:"counter"`
+
+	// Percent is the covered percentage
+	Percent float64 `json:"percent"`
+
+	// Target is the lowest acceptable percentage
+	Target float64 `json:"target"`
+
+	// Ratchet reports whether the target is the recorded coverage of a
+	// package rather than a configured threshold
+	Ratchet bool `json:"ratchet,omitempty"`
+
+	// Passed reports whether the percentage meets the target
+	Passed bool `json:"passed"`
+}
+
+// ThresholdReport is the evaluation of the thresholds of a report.
+type ThresholdReport struct {
+	// Results are the compared metrics: workspaces, then packages, then
+	// files, each sorted by name
+	Results []ThresholdResult `json:"results"`
+
+	// Findings report the results below their target
+	Findings []types.Finding `json:"findings"`
+}
+
+// Failed returns the number of results below their target.
+func (r *ThresholdReport) Failed() int {
+	failed := 0
+
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// ThresholdEvaluator compares the coverage of workspaces, packages and files
+// with their thresholds.
+//
+// Every workspace and package is held to the configured thresholds, replaced
+// by the overrides of its workspace and by the path overrides matching all
+// of its files. Files are only evaluated on their own when a path override
+// matches them. With a ratchet, packages are also held to their recorded
+// coverage.
+type ThresholdEvaluator struct {
+	config     *Config
+	workspaces []types.Workspace
+	ratchet    *Ratchet
+}
+
+// NewThresholdEvaluator creates an evaluator grouping files by the given
+// workspaces. A nil ratchet disables the ratchet.
+func NewThresholdEvaluator(cfg *Config, workspaces []types.Workspace, ratchet *Ratchet) *ThresholdEvaluator {
+	return &ThresholdEvaluator{config: cfg, workspaces: workspaces, ratchet: ratchet}
+}
+
+// thresholdGroup is a workspace or package with the files it aggregates.
+type thresholdGroup struct {
+	name, path string
+	workspace  *types.Workspace
+	files      []string
+	metrics    Metrics
+}
+
+// Evaluate compares the coverage of a report with its thresholds. Files
+// outside the repository are ignored, and metrics the report does not
+// provide are not compared.
+func (e *ThresholdEvaluator) Evaluate(r *Report) *ThresholdReport {
+	report := &ThresholdReport{Results: []ThresholdResult{}, Findings: []types.Finding{}}
+
+	workspaces := make(map[string]*thresholdGroup)
+	packages := make(map[string]*thresholdGroup)
+
+	for _, f := range r.Files {
+		if isAbsSlash(f.Path) {
+			continue
+		}
+
+		ws := discovery.WorkspaceFor(e.workspaces, f.Path)
+
+		wsPath := "."
+		if ws != nil {
+			wsPath = ws.Path
+		}
+
+		addToGroup(workspaces, wsPath, wsPath, ws, f)
+
+		name := f.Package
+		if name == "" {
+			name = path.Dir(f.Path)
+		}
+
+		addToGroup(packages, name, path.Dir(f.Path), ws, f)
+	}
+
+	for _, g := range sortedGroups(workspaces) {
+		name := g.path
+		if g.workspace != nil {
+			name = g.workspace.Name
+		}
+
+		e.check(report, ScopeWorkspace, name, g.path, g.metrics, e.workspaceThresholds(g.workspace), nil)
+	}
+
+	for _, g := range sortedGroups(packages) {
+		var recorded *RatchetEntry
+
+		if e.ratchet != nil {
+			if entry, ok := e.ratchet.Packages[g.name]; ok {
+				recorded = &entry
+			}
+		}
+
+		thresholds := e.pathThresholds(g.files, e.workspaceThresholds(g.workspace))
+		e.check(report, ScopePackage, g.name, g.path, g.metrics, thresholds, recorded)
+	}
+
+	for _, f := range r.Files {
+		if isAbsSlash(f.Path) || !e.overridden(f.Path) {
+			continue
+		}
+
+		ws := discovery.WorkspaceFor(e.workspaces, f.Path)
+		thresholds := e.pathThresholds([]string{f.Path}, e.workspaceThresholds(ws))
+		e.check(report, ScopeFile, f.Path, f.Path, f.Metrics, thresholds, nil)
+	}
+
+	return report
+}
+
+// addToGroup adds a file to the group with the given name.
+func addToGroup(groups map[string]*thresholdGroup, name, dir string, ws *types.Workspace, f *File) {
+	g, ok := groups[name]
+	if !ok {
+		g = &thresholdGroup{name: name, path: dir, workspace: ws}
+		groups[name] = g
+	}
+
+	g.files = append(g.files, f.Path)
+	g.metrics.Add(f.Metrics)
+}
+
+// sortedGroups returns groups sorted by name.
+func sortedGroups(groups map[string]*thresholdGroup) []*thresholdGroup {
+	sorted := make([]*thresholdGroup, 0, len(groups))
+	for _, g := range groups {
+		sorted = append(sorted, g)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+
+	return sorted
+}
+
+// workspaceThresholds returns the thresholds of a workspace, or of the
+// repository when ws is nil.
+func (e *ThresholdEvaluator) workspaceThresholds(ws *types.Workspace) Thresholds {
+	t := e.config.Thresholds
+
+	if ws == nil {
+		return t
+	}
+
+	for _, o := range e.config.Overrides {
+		if o.Workspace != "" && (o.Workspace == ws.Name || o.Workspace == ws.Path) {
+			t = o.apply(t)
+		}
+	}
+
+	return t
+}
+
+// pathThresholds applies the path overrides matching every file to t.
+func (e *ThresholdEvaluator) pathThresholds(files []string, t Thresholds) Thresholds {
+	for _, o := range e.config.Overrides {
+		if o.Path == "" {
+			continue
+		}
+
+		matched := true
+
+		for _, f := range files {
+			if !discovery.MatchGlob(o.Path, f) {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			t = o.apply(t)
+		}
+	}
+
+	return t
+}
+
+// overridden reports whether a path override matches a file.
+func (e *ThresholdEvaluator) overridden(file string) bool {
+	for _, o := range e.config.Overrides {
+		if o.Path != "" && discovery.MatchGlob(o.Path, file) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check compares the metrics of a workspace, package or file with its
+// thresholds and, for packages, with their recorded coverage.
+func (e *ThresholdEvaluator) check(report *ThresholdReport, scope Scope, name, dir string, m Metrics, t Thresholds, recorded *RatchetEntry) {
+	metrics := []struct {
+		metric   Metric
+		counter  Counter
+		target   float64
+		recorded float64
+	}{
+		{MetricLine, m.Lines, t.Line, 0},
+		{MetricBranch, m.Branches, t.Branch, 0},
+		{MetricFunction, m.Functions, t.Function, 0},
+	}
+
+	if recorded != nil {
+		metrics[0].recorded, metrics[1].recorded, metrics[2].recorded = recorded.Line, recorded.Branch, recorded.Function
+	}
+
+	for _, mt := range metrics {
+		if mt.counter.Total == 0 {
+			continue
+		}
+
+		result := ThresholdResult{Scope: scope, Name: name, Path: dir, Metric: mt.metric, Counter: mt.counter, Percent: mt.counter.Percent()}
+
+		if mt.target > 0 {
+			result.Target = mt.target
+			result.Passed = result.Percent >= mt.target
+
+			report.Results = append(report.Results, result)
+			if !result.Passed {
+				report.Findings = append(report.Findings, thresholdFinding(result))
+			}
+		}
+
+		if mt.recorded > 0 {
+			result.Target = mt.recorded
+			result.Ratchet = true
+			result.Passed = roundPercent(result.Percent) >= mt.recorded-e.config.Ratchet.Tolerance
+
+			report.Results = append(report.Results, result)
+			if !result.Passed {
+				report.Findings = append(report.Findings, ratchetFinding(result))
+			}
+		}
+	}
+}
+
+// thresholdSubject names the workspace, package or file of a result.
+func thresholdSubject(r ThresholdResult) string {
+	switch r.Scope {
+	case ScopeWorkspace:
+		if r.Name == "." {
+			return "The repository"
+		}
+
+		return "Workspace " + r.Name
+	case ScopePackage:
+		return "Package " + r.Name
+	default:
+		return "File " + r.Name
+	}
+}
+
+// thresholdFinding reports a result below its configured threshold.
+func thresholdFinding(r ThresholdResult) types.Finding {
+	return types.Finding{
+		CheckID:  CheckCoverageBelowThreshold,
+		Type:     types.FindingTypeCoverage,
+		Severity: types.SeverityMedium,
+		Title:    "Coverage Below Threshold",
+		Description: fmt.Sprintf("%s has %.1f%% %s coverage (%d of %d %s), %.1f points below the target of %.1f%%",
+			thresholdSubject(r), r.Percent, r.Metric, r.Counter.Covered, r.Counter.Total, metricItems[r.Metric], r.Target-r.Percent, r.Target),
+		Rationale: "Code below the coverage target changes without tests noticing; targets per workspace and package show where testing falls behind.",
+		Location:  types.Location{File: r.Path},
+		Remediation: &types.Remediation{
+			Summary: "Add tests for the uncovered code",
+			Steps: []string{
+				"Find the uncovered lines in the coverage report",
+				"Write tests for the least covered files first",
+				"If the target does not suit this code, set another one with a coverage.thresholds.overrides entry",
+			},
+			Effort: types.EffortMedium,
+		},
+	}
+}
+
+// ratchetFinding reports a package whose coverage fell below its recorded
+// value.
+func ratchetFinding(r ThresholdResult) types.Finding {
+	return types.Finding{
+		CheckID:  CheckCoverageRatchet,
+		Type:     types.FindingTypeCoverage,
+		Severity: types.SeverityHigh,
+		Title:    "Coverage Dropped Below Ratchet",
+		Description: fmt.Sprintf("%s %s coverage fell to %.2f%% from its recorded %.2f%%",
+			thresholdSubject(r), r.Metric, roundPercent(r.Percent), r.Target),
+		Rationale: "The ratchet keeps coverage from eroding: code added without tests or tests removed lower the coverage a package already reached.",
+		Location:  types.Location{File: r.Path},
+		Remediation: &types.Remediation{
+			Summary: "Restore the coverage of the package",
+			Steps: []string{
+				"Add tests for the code added to the package since the ratchet was recorded",
+				"Restore tests that were removed or skipped",
+			},
+			Effort: types.EffortLow,
+		},
+	}
+}
@@ -0,0 +1,220 @@
+package coverage
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// thresholdReport returns a report of a Go module at the root and an npm
+// workspace in web.
+func thresholdReport() (*Report, []types.Workspace) {
+	report := NewReport(FormatShipShape, []*File{
+		{Path: "billing/charge.go", Metrics: Metrics{Lines: Counter{Covered: 9, Total: 10}, Functions: Counter{Covered: 3, Total: 3}}},
+		{Path: "legacy/old.go", Metrics: Metrics{Lines: Counter{Covered: 1, Total: 10}}},
+		{Path: "web/src/cart.js", Metrics: Metrics{Lines: Counter{Covered: 6, Total: 10}, Branches: Counter{Covered: 1, Total: 4}}},
+		{Path: "/usr/lib/go/src/fmt/print.go", Metrics: Metrics{Lines: Counter{Total: 10}}},
+	})
+
+	workspaces := []types.Workspace{
+		{Name: "example.com/app", Path: ".", Language: types.LanguageGo, Type: types.WorkspaceTypeGo},
+		{Name: "web", Path: "web", Language: types.LanguageJavaScript, Type: types.WorkspaceTypeNpm},
+	}
+
+	return report, workspaces
+}
+
+func percent(p float64) *float64 {
+	return &p
+}
+
+func TestThresholdEvaluator(t *testing.T) {
+	report, workspaces := thresholdReport()
+
+	cfg := DefaultConfig()
+	cfg.Overrides = []ThresholdOverride{
+		{Path: "legacy/**", Line: percent(10)},
+		{Workspace: "web", Line: percent(60), Branch: percent(0)},
+	}
+
+	result := NewThresholdEvaluator(cfg, workspaces, nil).Evaluate(report)
+
+	var got []string
+	for _, r := range result.Results {
+		got = append(got, strings.Join([]string{string(r.Scope), r.Name, string(r.Metric)}, " "))
+	}
+
+	want := []string{
+		"workspace example.com/app line",
+		"workspace example.com/app function",
+		"workspace web line",
+		"package billing line",
+		"package billing function",
+		"package legacy line",
+		"package web/src line",
+		"file legacy/old.go line",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %q, want %q", got, want)
+	}
+
+	if result.Failed() != 1 || len(result.Findings) != 1 {
+		t.Fatalf("failed = %d, findings = %+v, want the root workspace lines only", result.Failed(), result.Findings)
+	}
+
+	f := result.Findings[0]
+	if f.CheckID != CheckCoverageBelowThreshold || f.Location.File != "." ||
+		f.Description != "Workspace example.com/app has 50.0% line coverage (10 of 20 lines), 30.0 points below the target of 80.0%" {
+		t.Errorf("finding = %+v", f)
+	}
+}
+
+func TestThresholdEvaluatorWithoutWorkspaces(t *testing.T) {
+	report, _ := thresholdReport()
+
+	cfg := DefaultConfig()
+	cfg.Thresholds = Thresholds{Line: 50}
+
+	result := NewThresholdEvaluator(cfg, nil, nil).Evaluate(report)
+
+	if len(result.Results) != 4 || result.Results[0].Name != "." || result.Results[0].Percent != 16.0/30*100 {
+		t.Fatalf("results = %+v, want the repository and three packages", result.Results)
+	}
+
+	var failed []string
+	for _, f := range result.Findings {
+		failed = append(failed, f.Description)
+	}
+
+	if len(failed) != 1 || !strings.HasPrefix(failed[0], "Package legacy has 10.0% line coverage") {
+		t.Errorf("findings = %q, want the legacy package", failed)
+	}
+}
+
+func TestThresholdEvaluatorRatchet(t *testing.T) {
+	report, workspaces := thresholdReport()
+
+	cfg := DefaultConfig()
+	cfg.Thresholds = Thresholds{}
+	cfg.Ratchet.Tolerance = 1
+
+	ratchet := NewRatchet()
+	ratchet.Packages["billing"] = RatchetEntry{Line: 95, Function: 100}
+	ratchet.Packages["legacy"] = RatchetEntry{Line: 5}
+
+	result := NewThresholdEvaluator(cfg, workspaces, ratchet).Evaluate(report)
+
+	if len(result.Results) != 3 || !result.Results[0].Ratchet {
+		t.Errorf("results = %+v, want the recorded metrics only", result.Results)
+	}
+
+	if len(result.Findings) != 1 {
+		t.Fatalf("findings = %+v, want the billing lines only", result.Findings)
+	}
+
+	f := result.Findings[0]
+	if f.CheckID != CheckCoverageRatchet || f.Severity != types.SeverityHigh ||
+		f.Description != "Package billing line coverage fell to 90.00% from its recorded 95.00%" {
+		t.Errorf("finding = %+v", f)
+	}
+
+	if changed := ratchet.Update(report); changed != 2 {
+		t.Errorf("Update() = %d, want legacy raised and web/src recorded", changed)
+	}
+
+	want := map[string]RatchetEntry{
+		"billing": {Line: 95, Function: 100},
+		"legacy":  {Line: 10},
+		"web/src": {Line: 60, Branch: 25},
+	}
+	if !reflect.DeepEqual(ratchet.Packages, want) {
+		t.Errorf("Packages = %+v, want %+v", ratchet.Packages, want)
+	}
+}
+
+func TestRatchetSaveLoad(t *testing.T) {
+	dir := testutil.TempDir(t)
+	file := filepath.Join(dir, DefaultRatchetFile)
+
+	ratchet := NewRatchet()
+	ratchet.Packages["example.com/app/billing"] = RatchetEntry{Line: 87.5, Branch: 66.67}
+
+	if err := ratchet.Save(file); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadRatchet(file)
+	if err != nil || !reflect.DeepEqual(loaded, ratchet) {
+		t.Errorf("LoadRatchet() = %+v, %v, want %+v", loaded, err, ratchet)
+	}
+
+	testutil.WriteFile(t, dir, "old.json", `{"version": 7, "packages": {}}`)
+
+	if _, err := LoadRatchet(filepath.Join(dir, "old.json")); err == nil || !strings.Contains(err.Error(), "unsupported ratchet version 7") {
+		t.Errorf("LoadRatchet() error = %v, want version error", err)
+	}
+
+	if _, err := LoadRatchet(filepath.Join(dir, "missing.json")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadRatchet() error = %v, want not exist", err)
+	}
+}
+
+func TestLoadThresholdConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	config := `coverage:
+  thresholds:
+    line: 75
+    overrides:
+      - path: "internal/legacy/**"
+        line: 40
+      - workspace: web
+        branch: 55.5
+  ratchet:
+    enabled: true
+    tolerance: 0.5
+`
+	if err := v.ReadConfig(strings.NewReader(config)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Thresholds != (Thresholds{Line: 75, Branch: 70, Function: 90}) {
+		t.Errorf("Thresholds = %+v, want line replaced", cfg.Thresholds)
+	}
+
+	if len(cfg.Overrides) != 2 || *cfg.Overrides[0].Line != 40 || cfg.Overrides[0].Branch != nil || *cfg.Overrides[1].Branch != 55.5 {
+		t.Errorf("Overrides = %+v", cfg.Overrides)
+	}
+
+	if cfg.Ratchet != (RatchetConfig{Enabled: true, File: DefaultRatchetFile, Tolerance: 0.5}) {
+		t.Errorf("Ratchet = %+v", cfg.Ratchet)
+	}
+
+	tests := map[string]any{
+		"coverage.thresholds.function":  120,
+		"coverage.thresholds.overrides": []map[string]any{{"path": "a/**", "workspace": "web"}},
+		"coverage.ratchet.tolerance":    -1,
+	}
+
+	for key, value := range tests {
+		v := viper.New()
+		v.Set(key, value)
+
+		if _, err := LoadConfig(v); err == nil {
+			t.Errorf("LoadConfig() with %s = %v succeeded, want error", key, value)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+package coverage
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OutputFormats are the formats a report can be written in.
+var OutputFormats = []Format{FormatShipShape, FormatLCOV, FormatCobertura}
+
+// Write writes a report in one of the OutputFormats.
+func Write(w io.Writer, r *Report, format Format) error {
+	switch format {
+	case FormatShipShape:
+		return WriteJSON(w, r)
+	case FormatLCOV:
+		return WriteLCOV(w, r)
+	case FormatCobertura:
+		return WriteCobertura(w, r)
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// WriteJSON writes a report in FormatShipShape, which keeps every detail of
+// the model.
+func WriteJSON(w io.Writer, r *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+
+	return nil
+}
+
+// WriteLCOV writes a report as an LCOV tracefile with one record per file.
+// LCOV identifies functions by name, so functions sharing a name are
+// written once, at their first declaration, with their hits added up.
+// Counters without per-item data, such as statements, are not written.
+func WriteLCOV(w io.Writer, r *Report) error {
+	bw := bufio.NewWriter(w)
+
+	for _, f := range r.Files {
+		fmt.Fprintf(bw, "TN:\nSF:%s\n", f.Path)
+
+		var (
+			names     []string
+			functions Counter
+			hits      = make(map[string]int64)
+		)
+
+		for _, fn := range f.Functions {
+			if _, ok := hits[fn.Name]; !ok {
+				fmt.Fprintf(bw, "FN:%d,%s\n", fn.Line, fn.Name)
+				names = append(names, fn.Name)
+			}
+
+			hits[fn.Name] += fn.Hits
+		}
+
+		for _, name := range names {
+			fmt.Fprintf(bw, "FNDA:%d,%s\n", hits[name], name)
+			functions.Count(hits[name] > 0)
+		}
+
+		fmt.Fprintf(bw, "FNF:%d\nFNH:%d\n", functions.Total, functions.Covered)
+
+		var branches Counter
+
+		for _, b := range f.Branches {
+			fmt.Fprintf(bw, "BRDA:%d,%d,%d,%d\n", b.Line, b.Block, b.Branch, b.Hits)
+			branches.Count(b.Hits > 0)
+		}
+
+		fmt.Fprintf(bw, "BRF:%d\nBRH:%d\n", branches.Total, branches.Covered)
+
+		var lines Counter
+
+		for _, l := range f.Lines {
+			fmt.Fprintf(bw, "DA:%d,%d\n", l.Number, l.Hits)
+			lines.Count(l.Hits > 0)
+		}
+
+		fmt.Fprintf(bw, "LF:%d\nLH:%d\nend_of_record\n", lines.Total, lines.Covered)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write LCOV tracefile: %w", err)
+	}
+
+	return nil
+}
+
+// coberturaXMLReport is the root element of a written Cobertura report.
+type coberturaXMLReport struct {
+	XMLName         xml.Name              `xml:"coverage"`
+	LineRate        string                `xml:"line-rate,attr"`
+	BranchRate      string                `xml:"branch-rate,attr"`
+	LinesCovered    int                   `xml:"lines-covered,attr"`
+	LinesValid      int                   `xml:"lines-valid,attr"`
+	BranchesCovered int                   `xml:"branches-covered,attr"`
+	BranchesValid   int                   `xml:"branches-valid,attr"`
+	Complexity      string                `xml:"complexity,attr"`
+	Timestamp       int64                 `xml:"timestamp,attr"`
+	Sources         []string              `xml:"sources>source"`
+	Packages        []coberturaXMLPackage `xml:"packages>package"`
+}
+
+// coberturaXMLPackage is a written <package> element.
+type coberturaXMLPackage struct {
+	Name       string              `xml:"name,attr"`
+	LineRate   string              `xml:"line-rate,attr"`
+	BranchRate string              `xml:"branch-rate,attr"`
+	Complexity string              `xml:"complexity,attr"`
+	Classes    []coberturaXMLClass `xml:"classes>class"`
+}
+
+// coberturaXMLClass is a written <class> element.
+type coberturaXMLClass struct {
+	Name       string               `xml:"name,attr"`
+	Filename   string               `xml:"filename,attr"`
+	LineRate   string               `xml:"line-rate,attr"`
+	BranchRate string               `xml:"branch-rate,attr"`
+	Complexity string               `xml:"complexity,attr"`
+	Methods    []coberturaXMLMethod `xml:"methods>method"`
+	Lines      []coberturaXMLLine   `xml:"lines>line"`
+}
+
+// coberturaXMLMethod is a written <method> element.
+type coberturaXMLMethod struct {
+	Name       string             `xml:"name,attr"`
+	Signature  string             `xml:"signature,attr"`
+	LineRate   string             `xml:"line-rate,attr"`
+	BranchRate string             `xml:"branch-rate,attr"`
+	Lines      []coberturaXMLLine `xml:"lines>line"`
+}
+
+// coberturaXMLLine is a written <line> element.
+type coberturaXMLLine struct {
+	Number            int                     `xml:"number,attr"`
+	Hits              int64                   `xml:"hits,attr"`
+	Branch            bool                    `xml:"branch,attr"`
+	ConditionCoverage string                  `xml:"condition-coverage,attr,omitempty"`
+	Conditions        *coberturaXMLConditions `xml:"conditions,omitempty"`
+}
+
+// coberturaXMLConditions is a written <conditions> element.
+type coberturaXMLConditions struct {
+	Conditions []coberturaXMLCondition `xml:"condition"`
+}
+
+// coberturaXMLCondition is a written <condition> element.
+type coberturaXMLCondition struct {
+	Number   int    `xml:"number,attr"`
+	Type     string `xml:"type,attr"`
+	Coverage string `xml:"coverage,attr"`
+}
+
+// WriteCobertura writes a report as Cobertura XML with one package per
+// package or directory and one class per file, named after the file. File
+// names are relative to the repository root, the single source of the
+// report. Functions become methods spanning their first and last line.
+func WriteCobertura(w io.Writer, r *Report) error {
+	doc := coberturaXMLReport{
+		LineRate:        coberturaRate(r.Metrics.Lines),
+		BranchRate:      coberturaRate(r.Metrics.Branches),
+		LinesCovered:    r.Metrics.Lines.Covered,
+		LinesValid:      r.Metrics.Lines.Total,
+		BranchesCovered: r.Metrics.Branches.Covered,
+		BranchesValid:   r.Metrics.Branches.Total,
+		Complexity:      "0",
+		Timestamp:       time.Now().UnixMilli(),
+		Sources:         []string{"."},
+	}
+
+	byName := make(map[string]*coberturaXMLPackage)
+
+	for _, p := range r.Packages() {
+		doc.Packages = append(doc.Packages, coberturaXMLPackage{
+			Name:       p.Name,
+			LineRate:   coberturaRate(p.Metrics.Lines),
+			BranchRate: coberturaRate(p.Metrics.Branches),
+			Complexity: "0",
+		})
+	}
+
+	for i := range doc.Packages {
+		byName[doc.Packages[i].Name] = &doc.Packages[i]
+	}
+
+	for _, f := range r.Files {
+		name := f.Package
+		if name == "" {
+			name = path.Dir(f.Path)
+		}
+
+		pkg := byName[name]
+		pkg.Classes = append(pkg.Classes, coberturaFileClass(f))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write Cobertura report: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write Cobertura report: %w", err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write Cobertura report: %w", err)
+	}
+
+	return nil
+}
+
+// coberturaFileClass returns the class describing a file.
+func coberturaFileClass(f *File) coberturaXMLClass {
+	base := path.Base(f.Path)
+
+	class := coberturaXMLClass{
+		Name:       strings.TrimSuffix(base, path.Ext(base)),
+		Filename:   f.Path,
+		LineRate:   coberturaRate(f.Metrics.Lines),
+		BranchRate: coberturaRate(f.Metrics.Branches),
+		Complexity: "0",
+	}
+
+	outcomes := make(map[int][]Branch)
+	for _, b := range f.Branches {
+		outcomes[b.Line] = append(outcomes[b.Line], b)
+	}
+
+	for _, l := range f.Lines {
+		line := coberturaXMLLine{Number: l.Number, Hits: l.Hits}
+
+		if branches := outcomes[l.Number]; len(branches) > 0 {
+			line.Branch = true
+			var conditions []coberturaXMLCondition
+
+			line.ConditionCoverage, conditions = coberturaConditions(branches)
+			if len(conditions) > 0 {
+				line.Conditions = &coberturaXMLConditions{Conditions: conditions}
+			}
+		}
+
+		class.Lines = append(class.Lines, line)
+	}
+
+	for _, fn := range f.Functions {
+		class.Methods = append(class.Methods, coberturaMethod(fn, class.Lines, f.Branches))
+	}
+
+	return class
+}
+
+// coberturaMethod returns the method describing a function: the executable
+// lines of its file between its first and last line, and the rates of
+// those lines and of their branches. A function without executable lines
+// is described by its declaration line.
+func coberturaMethod(fn Function, lines []coberturaXMLLine, branches []Branch) coberturaXMLMethod {
+	var covered, taken Counter
+
+	method := coberturaXMLMethod{Name: fn.Name}
+	last := max(fn.EndLine, fn.Line)
+
+	for _, l := range lines {
+		if l.Number >= fn.Line && l.Number <= last {
+			method.Lines = append(method.Lines, l)
+			covered.Count(l.Hits > 0)
+		}
+	}
+
+	for _, b := range branches {
+		if b.Line >= fn.Line && b.Line <= last {
+			taken.Count(b.Hits > 0)
+		}
+	}
+
+	if len(method.Lines) == 0 {
+		method.Lines = []coberturaXMLLine{{Number: fn.Line, Hits: fn.Hits}}
+		covered.Count(fn.Hits > 0)
+	}
+
+	method.LineRate, method.BranchRate = coberturaRate(covered), coberturaRate(taken)
+
+	return method
+}
+
+// coberturaConditions returns the condition-coverage attribute of the
+// outcomes of a line and, when every block has two outcomes, one jump
+// condition per block.
+func coberturaConditions(outcomes []Branch) (string, []coberturaXMLCondition) {
+	var (
+		covered Counter
+		blocks  = make(map[int]*Counter)
+	)
+
+	for _, b := range outcomes {
+		covered.Count(b.Hits > 0)
+
+		if blocks[b.Block] == nil {
+			blocks[b.Block] = &Counter{}
+		}
+
+		blocks[b.Block].Count(b.Hits > 0)
+	}
+
+	coverage := fmt.Sprintf("%d%% (%d/%d)", int(covered.Percent()), covered.Covered, covered.Total)
+
+	numbers := make([]int, 0, len(blocks))
+
+	for number, c := range blocks {
+		if c.Total != 2 {
+			return coverage, nil
+		}
+
+		numbers = append(numbers, number)
+	}
+
+	sort.Ints(numbers)
+
+	conditions := make([]coberturaXMLCondition, 0, len(numbers))
+	for _, number := range numbers {
+		conditions = append(conditions, coberturaXMLCondition{
+			Number:   number,
+			Type:     "jump",
+			Coverage: strconv.Itoa(int(blocks[number].Percent())) + "%",
+		})
+	}
+
+	return coverage, conditions
+}
+
+// coberturaRate formats the covered ratio of a counter.
+func coberturaRate(c Counter) string {
+	return strconv.FormatFloat(math.Round(c.Percent()*100)/10000, 'f', -1, 64)
+}
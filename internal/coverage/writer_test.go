@@ -0,0 +1,127 @@
+package coverage
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+// writtenReport is a report with lines, branches and functions of a file
+// that exists under the root the written reports are parsed against.
+func writtenReport(t *testing.T) (string, *Report) {
+	t.Helper()
+
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "src/cart.js", "function total() {}\n")
+
+	return dir, NewReport(FormatLCOV, []*File{
+		{
+			Path:      "src/cart.js",
+			Lines:     []Line{{Number: 2, Hits: 3}, {Number: 3, Hits: 0}, {Number: 5, Hits: 1}},
+			Branches:  []Branch{{Line: 2, Block: 0, Branch: 0, Hits: 2}, {Line: 2, Block: 0, Branch: 1, Hits: 0}},
+			Functions: []Function{{Name: "total", Line: 1, EndLine: 6, Hits: 3}, {Name: "clear", Line: 8, Hits: 0}},
+		},
+	})
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	tests := []struct {
+		format    Format
+		wantHits  int64
+		wantFirst string
+		wantLine  int
+	}{
+		{format: FormatShipShape, wantHits: 2, wantFirst: "total", wantLine: 1},
+		{format: FormatLCOV, wantHits: 2, wantFirst: "total", wantLine: 1},
+		// Cobertura records whether outcomes were taken, not how often,
+		// qualifies methods by their class and starts them at their first
+		// executable line.
+		{format: FormatCobertura, wantHits: 1, wantFirst: "cart.total", wantLine: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			dir, report := writtenReport(t)
+
+			var buf bytes.Buffer
+			if err := Write(&buf, report, tt.format); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			format, ok := Sniff(bytes.NewReader(buf.Bytes()))
+			if !ok || format != tt.format {
+				t.Fatalf("Sniff() = %q, %v, want %q", format, ok, tt.format)
+			}
+
+			parser, err := NewParser(format, dir, "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parsed, err := parser.Parse(&buf)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if parsed.Metrics.Lines != report.Metrics.Lines || parsed.Metrics.Branches != report.Metrics.Branches ||
+				parsed.Metrics.Functions != report.Metrics.Functions {
+				t.Errorf("Metrics = %+v, want %+v", parsed.Metrics, report.Metrics)
+			}
+
+			f := parsed.File("src/cart.js")
+			if f == nil {
+				t.Fatalf("src/cart.js missing from %+v", parsed.Files)
+			}
+
+			if f.Lines[0].Hits != 3 || f.Branches[0].Hits != tt.wantHits {
+				t.Errorf("hits: line = %d, branch = %d, want 3, %d", f.Lines[0].Hits, f.Branches[0].Hits, tt.wantHits)
+			}
+
+			if fn := f.Functions[0]; fn.Name != tt.wantFirst || fn.Hits != 3 || fn.Line != tt.wantLine {
+				t.Errorf("Functions[0] = %+v, want %s at line %d with 3 hits", fn, tt.wantFirst, tt.wantLine)
+			}
+		})
+	}
+}
+
+func TestWriteLCOV(t *testing.T) {
+	_, report := writtenReport(t)
+
+	var buf bytes.Buffer
+	if err := WriteLCOV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"SF:src/cart.js\n", "FN:1,total\n", "FNDA:3,total\n", "BRDA:2,0,1,0\n", "LF:3\nLH:2\nend_of_record\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("tracefile missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteCoberturaMethods(t *testing.T) {
+	_, report := writtenReport(t)
+
+	var buf bytes.Buffer
+	if err := WriteCobertura(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<method name="total" signature="" line-rate="0.6667" branch-rate="0.5">`,
+		`<line number="3" hits="0" branch="false"></line>`,
+		`<method name="clear" signature="" line-rate="0" branch-rate="0">`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, NewReport(FormatGoCover, nil), FormatJaCoCo); err == nil {
+		t.Error("Write() succeeded for jacoco-xml, want error")
+	}
+}
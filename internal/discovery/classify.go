@@ -0,0 +1,80 @@
+package discovery
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// jsTestSuffixes are the infixes that mark JavaScript/TypeScript test files.
+var jsTestSuffixes = []string{".test", ".spec"}
+
+// javaTestSuffixes are the class name suffixes that mark Java test classes.
+var javaTestSuffixes = []string{"Test", "Tests", "TestCase", "IT"}
+
+// LanguageOf returns the language of a file based on its extension.
+func LanguageOf(name string) types.Language {
+	if lang, ok := ExtensionMap[strings.ToLower(filepath.Ext(name))]; ok {
+		return lang
+	}
+
+	return types.LanguageUnknown
+}
+
+// IsTestFile reports whether the file at relPath is a test file according to
+// the naming conventions of its language.
+func IsTestFile(relPath string) bool {
+	name := filepath.Base(relPath)
+
+	switch LanguageOf(name) {
+	case types.LanguageGo:
+		return isGoTestFile(name)
+	case types.LanguagePython:
+		return isPythonTestFile(name)
+	case types.LanguageJavaScript, types.LanguageTypeScript:
+		return isJSTestFile(relPath)
+	case types.LanguageJava:
+		return isJavaTestFile(relPath)
+	default:
+		return false
+	}
+}
+
+// isJavaTestFile checks Surefire/Failsafe naming conventions (Test*, *Test,
+// *Tests, *TestCase, *IT) and files under Maven/Gradle test source roots.
+func isJavaTestFile(relPath string) bool {
+	slashed := filepath.ToSlash(relPath)
+	if strings.HasPrefix(slashed, "src/test/") || strings.Contains(slashed, "/src/test/") {
+		return true
+	}
+
+	stem := strings.TrimSuffix(filepath.Base(relPath), filepath.Ext(relPath))
+	for _, suffix := range javaTestSuffixes {
+		if strings.HasSuffix(stem, suffix) && len(stem) > len(suffix) {
+			return true
+		}
+	}
+
+	return strings.HasPrefix(stem, "Test") && len(stem) > len("Test")
+}
+
+// isJSTestFile checks for *.test.*, *.spec.* and files inside __tests__ directories.
+func isJSTestFile(relPath string) bool {
+	name := filepath.Base(relPath)
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+
+	for _, suffix := range jsTestSuffixes {
+		if strings.HasSuffix(stem, suffix) {
+			return true
+		}
+	}
+
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/") {
+		if part == "__tests__" {
+			return true
+		}
+	}
+
+	return false
+}
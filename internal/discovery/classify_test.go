@@ -0,0 +1,61 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+func TestIsTestFile(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"pkg/util/util_test.go", true},
+		{"pkg/util/util.go", false},
+		{"tests/test_models.py", true},
+		{"app/models.py", false},
+		{"src/button.test.tsx", true},
+		{"src/api.spec.js", true},
+		{"src/__tests__/button.tsx", true},
+		{"src/__tests__/fixtures/data.json", false},
+		{"src/button.tsx", false},
+		{"service/src/test/java/com/acme/Helpers.java", true},
+		{"src/main/java/com/acme/OrderService.java", false},
+		{"legacy/OrderServiceTest.java", true},
+		{"legacy/OrderServiceIT.java", true},
+		{"legacy/TestOrders.java", true},
+		{"legacy/Test.java", false},
+		{"src/testing.ts", false},
+		{"README.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := IsTestFile(tt.path)
+			if got != tt.want {
+				t.Errorf("IsTestFile(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLanguageOf(t *testing.T) {
+	tests := []struct {
+		name string
+		want types.Language
+	}{
+		{"main.go", types.LanguageGo},
+		{"App.TSX", types.LanguageTypeScript},
+		{"index.mjs", types.LanguageJavaScript},
+		{"Makefile", types.LanguageUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LanguageOf(tt.name); got != tt.want {
+				t.Errorf("LanguageOf(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package discovery
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MatchGlob reports whether relPath matches a glob pattern. Patterns use
+// forward slashes and support the filepath.Match syntax for each segment plus
+// "**", which matches zero or more directories. A pattern without a slash
+// matches against the base name only (e.g. "*_test.go").
+func MatchGlob(pattern, relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	pattern = strings.TrimPrefix(filepath.ToSlash(pattern), "./")
+
+	if !strings.Contains(pattern, "/") {
+		matched, err := path.Match(pattern, path.Base(relPath))
+		return err == nil && matched
+	}
+
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(relPath, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			// Collapse consecutive ** segments
+			for len(pattern) > 1 && pattern[1] == "**" {
+				pattern = pattern[1:]
+			}
+
+			if len(pattern) == 1 {
+				return true
+			}
+
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+
+		matched, err := path.Match(pattern[0], parts[0])
+		if err != nil || !matched {
+			return false
+		}
+
+		pattern, parts = pattern[1:], parts[1:]
+	}
+
+	return len(parts) == 0
+}
+
+// MatchAnyGlob reports whether relPath matches at least one of the patterns.
+func MatchAnyGlob(patterns []string, relPath string) bool {
+	for _, p := range patterns {
+		if MatchGlob(p, relPath) {
+			return true
+		}
+	}
+
+	return false
+}
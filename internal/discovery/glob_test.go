@@ -0,0 +1,48 @@
+package discovery
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*_test.go", "internal/pkg/util_test.go", true},
+		{"*_test.go", "internal/pkg/util.go", false},
+		{"internal/**", "internal/pkg/util_test.go", true},
+		{"internal/**", "cmd/main.go", false},
+		{"**/*.spec.ts", "src/app/button.spec.ts", true},
+		{"**/*.spec.ts", "button.spec.ts", true},
+		{"src/**/api/*.js", "src/api/client.js", true},
+		{"src/**/api/*.js", "src/v1/v2/api/client.js", true},
+		{"src/**/api/*.js", "src/v1/api/nested/client.js", false},
+		{"./pkg/*/*.go", "pkg/types/inventory.go", true},
+		{"pkg/*.go", "pkg/types/inventory.go", false},
+		{"src/[", "src/[", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := MatchGlob(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAnyGlob(t *testing.T) {
+	patterns := []string{"cmd/**", "*.py"}
+
+	if !MatchAnyGlob(patterns, "tests/test_app.py") {
+		t.Error("expected match on basename pattern")
+	}
+
+	if MatchAnyGlob(patterns, "internal/app.go") {
+		t.Error("unexpected match")
+	}
+
+	if MatchAnyGlob(nil, "cmd/main.go") {
+		t.Error("empty pattern list should not match")
+	}
+}
@@ -0,0 +1,249 @@
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// testDirectories are directory names that conventionally hold tests.
+var testDirectories = map[string]bool{
+	"test": true, "tests": true, "spec": true, "__tests__": true,
+}
+
+// fixtureDirectory holds Go test fixtures. Its files count as test code,
+// but it says nothing about where the tests themselves live.
+const fixtureDirectory = "testdata"
+
+// OrganizationAnalyzer computes test-to-code ratios and test layout metrics.
+type OrganizationAnalyzer struct {
+	walker *Walker
+}
+
+// NewOrganizationAnalyzer creates a new test organization analyzer.
+func NewOrganizationAnalyzer(walker *Walker) *OrganizationAnalyzer {
+	return &OrganizationAnalyzer{
+		walker: walker,
+	}
+}
+
+// Analyze classifies every source file as test or production code, counts
+// their non-blank lines and records the test layout and naming conventions
+// in use per language. Files inside test directories that are not tests
+// themselves (helpers, fixtures) count as test code.
+func (a *OrganizationAnalyzer) Analyze() (*types.TestOrganization, error) {
+	byLang := make(map[types.Language]*types.LanguageOrganization)
+
+	_, err := a.walker.Walk(func(fi FileInfo) error {
+		lang := LanguageOf(fi.Name)
+		if lang == types.LanguageUnknown || strings.EqualFold(fi.Ext, ".ipynb") {
+			return nil
+		}
+
+		data, err := os.ReadFile(fi.Path) //nolint:gosec // Reading source files from repository
+		if err != nil {
+			return nil
+		}
+
+		stats, ok := byLang[lang]
+		if !ok {
+			stats = &types.LanguageOrganization{
+				Language: lang,
+				Layouts:  make(map[types.TestLayout]int),
+				Naming:   make(map[string]int),
+			}
+			byLang[lang] = stats
+		}
+
+		rel := filepath.ToSlash(fi.RelPath)
+		lines := countNonBlankLines(data)
+
+		switch {
+		case IsTestFile(rel):
+			stats.TestFiles++
+			stats.TestLines += lines
+			stats.Layouts[testLayoutOf(rel)]++
+
+			if style := testNamingStyle(rel, lang); style != "" {
+				stats.Naming[style]++
+			}
+		case inTestDirectory(rel):
+			stats.TestFiles++
+			stats.TestLines += lines
+		default:
+			stats.SourceFiles++
+			stats.SourceLines += lines
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	org := &types.TestOrganization{}
+
+	for _, stats := range byLang {
+		stats.Ratio = ratio(stats.TestLines, stats.SourceLines)
+
+		org.TestFiles += stats.TestFiles
+		org.SourceFiles += stats.SourceFiles
+		org.TestLines += stats.TestLines
+		org.SourceLines += stats.SourceLines
+		org.Languages = append(org.Languages, *stats)
+	}
+
+	org.Ratio = ratio(org.TestLines, org.SourceLines)
+
+	sort.Slice(org.Languages, func(i, j int) bool {
+		if org.Languages[i].TestFiles != org.Languages[j].TestFiles {
+			return org.Languages[i].TestFiles > org.Languages[j].TestFiles
+		}
+
+		return org.Languages[i].Language < org.Languages[j].Language
+	})
+
+	for i := range org.Languages {
+		org.Warnings = append(org.Warnings, conventionWarnings(&org.Languages[i])...)
+	}
+
+	return org, nil
+}
+
+// testLayoutOf classifies where a test file lives.
+func testLayoutOf(relPath string) types.TestLayout {
+	if strings.HasPrefix(relPath, "src/test/") || strings.Contains(relPath, "/src/test/") {
+		return types.TestLayoutSourceRoot
+	}
+
+	layout := types.TestLayoutColocated
+
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/") {
+		if part == "__tests__" {
+			return types.TestLayoutTestsFolder
+		}
+
+		if testDirectories[part] {
+			layout = types.TestLayoutTestDirectory
+		}
+	}
+
+	return layout
+}
+
+// testNamingStyle returns the naming style of a test file for languages
+// with competing conventions (Python prefix/suffix, JavaScript test/spec).
+func testNamingStyle(relPath string, lang types.Language) string {
+	name := filepath.Base(relPath)
+	stem := strings.TrimSuffix(name, filepath.Ext(name))
+
+	switch lang {
+	case types.LanguagePython:
+		if strings.HasPrefix(stem, "test_") {
+			return "test_*.py"
+		}
+
+		return "*_test.py"
+	case types.LanguageJavaScript, types.LanguageTypeScript:
+		switch {
+		case strings.HasSuffix(stem, ".test"):
+			return "*.test"
+		case strings.HasSuffix(stem, ".spec"):
+			return "*.spec"
+		default:
+			return "__tests__/*"
+		}
+	default:
+		return ""
+	}
+}
+
+// inTestDirectory reports whether a file lies inside a conventional test
+// or fixture directory.
+func inTestDirectory(relPath string) bool {
+	if strings.HasPrefix(relPath, "src/test/") || strings.Contains(relPath, "/src/test/") {
+		return true
+	}
+
+	for _, part := range strings.Split(filepath.ToSlash(filepath.Dir(relPath)), "/") {
+		if testDirectories[part] || part == fixtureDirectory {
+			return true
+		}
+	}
+
+	return false
+}
+
+// conventionWarnings reports mixed layouts, mixed naming styles and untested languages.
+func conventionWarnings(stats *types.LanguageOrganization) []string {
+	var warnings []string
+
+	if stats.TestFiles == 0 && stats.SourceFiles > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s has %d source files but no tests", stats.Language, stats.SourceFiles))
+	}
+
+	if len(stats.Layouts) > 1 {
+		layouts := make(map[string]int, len(stats.Layouts))
+		for layout, count := range stats.Layouts {
+			layouts[string(layout)] = count
+		}
+
+		warnings = append(warnings, fmt.Sprintf("%s tests mix directory conventions: %s",
+			stats.Language, formatCounts(layouts)))
+	}
+
+	if len(stats.Naming) > 1 {
+		warnings = append(warnings, fmt.Sprintf("%s tests mix naming styles: %s",
+			stats.Language, formatCounts(stats.Naming)))
+	}
+
+	return warnings
+}
+
+// formatCounts renders counts as "a (3), b (1)", largest first.
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s (%d)", key, counts[key])
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func countNonBlankLines(data []byte) int {
+	count := 0
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+func ratio(test, source int) float64 {
+	if source == 0 {
+		return 0
+	}
+
+	return float64(test) / float64(source)
+}
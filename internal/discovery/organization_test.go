@@ -0,0 +1,124 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+//nolint:gocognit // Verifies totals, per-language metrics and warnings together
+func TestOrganizationAnalyzer_Analyze(t *testing.T) {
+	dir := testutil.TempDir(t)
+
+	// Go: 4 source lines, 2 test lines
+	testutil.WriteFile(t, dir, "calc/calc.go", "package calc\n\nfunc Add() {}\n\nfunc Sub() {}\n// end\n")
+	testutil.WriteFile(t, dir, "calc/calc_test.go", "package calc\n\nfunc TestAdd() {}\n")
+
+	// Python: mixed layouts and naming, plus a helper in tests/
+	testutil.WriteFile(t, dir, "app/models.py", "a = 1\nb = 2\n")
+	testutil.WriteFile(t, dir, "app/models_test.py", "def test_a():\n    pass\n")
+	testutil.WriteFile(t, dir, "tests/test_views.py", "def test_b():\n    pass\n")
+	testutil.WriteFile(t, dir, "tests/helpers.py", "x = 1\n")
+
+	// Java: sources without tests
+	testutil.WriteFile(t, dir, "src/main/java/App.java", "class App {}\n")
+
+	// Notebooks are ignored
+	testutil.WriteFile(t, dir, "analysis.ipynb", "{}\n")
+
+	org, err := NewOrganizationAnalyzer(NewWalker(dir)).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if org.TestFiles != 4 || org.SourceFiles != 3 {
+		t.Errorf("files: test = %d, source = %d, want 4 and 3", org.TestFiles, org.SourceFiles)
+	}
+
+	if org.TestLines != 7 || org.SourceLines != 7 || org.Ratio != 1 {
+		t.Errorf("lines: test = %d, source = %d, ratio = %v", org.TestLines, org.SourceLines, org.Ratio)
+	}
+
+	if len(org.Languages) != 3 || org.Languages[0].Language != types.LanguagePython {
+		t.Fatalf("Languages = %+v, want Python first of 3", org.Languages)
+	}
+
+	python := org.Languages[0]
+	if python.Layouts[types.TestLayoutColocated] != 1 || python.Layouts[types.TestLayoutTestDirectory] != 1 {
+		t.Errorf("Python layouts = %v", python.Layouts)
+	}
+
+	if python.Naming["test_*.py"] != 1 || python.Naming["*_test.py"] != 1 {
+		t.Errorf("Python naming = %v", python.Naming)
+	}
+
+	goStats := org.Languages[1]
+	if goStats.Language != types.LanguageGo || goStats.Ratio != 0.5 || goStats.DominantLayout() != types.TestLayoutColocated {
+		t.Errorf("Go = %+v", goStats)
+	}
+
+	wantWarnings := []string{
+		"Python tests mix directory conventions: colocated (1), test-directory (1)",
+		"Python tests mix naming styles: *_test.py (1), test_*.py (1)",
+		"Java has 1 source files but no tests",
+	}
+	if len(org.Warnings) != len(wantWarnings) {
+		t.Fatalf("Warnings = %q, want %q", org.Warnings, wantWarnings)
+	}
+
+	for i, want := range wantWarnings {
+		if org.Warnings[i] != want {
+			t.Errorf("Warnings[%d] = %q, want %q", i, org.Warnings[i], want)
+		}
+	}
+}
+
+func TestOrganizationAnalyzerGoFixtures(t *testing.T) {
+	dir := testutil.TempDir(t)
+
+	testutil.WriteFile(t, dir, "parser/parser.go", "package parser\n")
+	testutil.WriteFile(t, dir, "parser/parser_test.go", "package parser\n")
+	testutil.WriteFile(t, dir, "parser/testdata/valid.go", "package valid\n")
+	testutil.WriteFile(t, dir, "parser/testdata/golden/golden_test.go", "package golden\n")
+
+	org, err := NewOrganizationAnalyzer(NewWalker(dir)).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if len(org.Languages) != 1 {
+		t.Fatalf("Languages = %+v, want Go only", org.Languages)
+	}
+
+	goStats := org.Languages[0]
+	if goStats.TestFiles != 3 || goStats.SourceFiles != 1 || goStats.Layouts[types.TestLayoutTestDirectory] != 0 {
+		t.Errorf("Go = %+v, want fixtures counted as colocated test code", goStats)
+	}
+
+	if len(org.Warnings) != 0 {
+		t.Errorf("Warnings = %q, want no mixed-convention warning", org.Warnings)
+	}
+}
+
+func TestTestLayoutOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want types.TestLayout
+	}{
+		{"pkg/util/util_test.go", types.TestLayoutColocated},
+		{"pkg/util/testdata/fixture_test.go", types.TestLayoutColocated},
+		{"tests/unit/test_models.py", types.TestLayoutTestDirectory},
+		{"web/src/__tests__/button.tsx", types.TestLayoutTestsFolder},
+		{"web/test/api.spec.js", types.TestLayoutTestDirectory},
+		{"service/src/test/java/com/acme/OrderTest.java", types.TestLayoutSourceRoot},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := testLayoutOf(tt.path); got != tt.want {
+				t.Errorf("testLayoutOf(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
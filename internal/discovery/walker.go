@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 // DefaultExcludePatterns are directory patterns excluded from analysis.
@@ -85,6 +86,9 @@ type FileInfo struct {
 
 	// Size is the file size in bytes
 	Size int64
+
+	// ModTime is the modification time of the file
+	ModTime time.Time
 }
 
 // NewWalker creates a new file system walker with default exclusions.
@@ -142,6 +146,7 @@ func (w *Walker) Walk(fn func(FileInfo) error) (int, error) {
 			Ext:     filepath.Ext(d.Name()),
 			IsDir:   d.IsDir(),
 			Size:    info.Size(),
+			ModTime: info.ModTime(),
 		}
 
 		// Call the callback
@@ -0,0 +1,204 @@
+package discovery
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// goModulePattern extracts the module path from a go.mod file.
+var goModulePattern = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// pyProjectNamePattern extracts the project name from pyproject.toml.
+var pyProjectNamePattern = regexp.MustCompile(`(?m)^name\s*=\s*["']([^"']+)["']`)
+
+// workspaceManifests are the build manifests that mark a workspace root.
+var workspaceManifests = map[string]bool{
+	"go.mod": true, "package.json": true, "pom.xml": true, "build.gradle": true,
+	"build.gradle.kts": true, "pyproject.toml": true, "setup.py": true,
+}
+
+// WorkspaceDetector finds the packages of a repository by locating their
+// build manifests (go.mod, package.json, pom.xml, build.gradle, pyproject.toml).
+type WorkspaceDetector struct {
+	rootPath string
+	walker   *Walker
+}
+
+// NewWorkspaceDetector creates a new workspace detector.
+func NewWorkspaceDetector(rootPath string, walker *Walker) *WorkspaceDetector {
+	return &WorkspaceDetector{
+		rootPath: rootPath,
+		walker:   walker,
+	}
+}
+
+// Detect returns one workspace per directory that contains a build manifest,
+// sorted by path. When a directory contains several manifests, the first
+// match in the order Go, npm, Maven, Gradle, Python wins.
+func (d *WorkspaceDetector) Detect() ([]types.Workspace, error) {
+	found := make(map[string]types.Workspace)
+
+	_, err := d.walker.Walk(func(fi FileInfo) error {
+		dir := filepath.ToSlash(filepath.Dir(fi.RelPath))
+
+		ws, ok := d.workspaceFor(fi, dir)
+		if !ok {
+			return nil
+		}
+
+		if existing, exists := found[dir]; !exists || manifestRank(ws.Type) < manifestRank(existing.Type) {
+			found[dir] = ws
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := make([]types.Workspace, 0, len(found))
+	for _, ws := range found {
+		workspaces = append(workspaces, ws)
+	}
+
+	sort.Slice(workspaces, func(i, j int) bool {
+		return workspaces[i].Path < workspaces[j].Path
+	})
+
+	return workspaces, nil
+}
+
+// manifestRank orders workspace types when a directory has several manifests.
+func manifestRank(t types.WorkspaceType) int {
+	switch t {
+	case types.WorkspaceTypeGo:
+		return 0
+	case types.WorkspaceTypeNpm, types.WorkspaceTypeYarn, types.WorkspaceTypePnpm:
+		return 1
+	case types.WorkspaceTypeMaven:
+		return 2
+	case types.WorkspaceTypeGradle:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func (d *WorkspaceDetector) workspaceFor(fi FileInfo, dir string) (types.Workspace, bool) {
+	if !workspaceManifests[fi.Name] {
+		return types.Workspace{}, false
+	}
+
+	ws := types.Workspace{Name: path.Base(dir), Path: dir}
+	if dir == "." {
+		ws.Name = filepath.Base(d.absRoot())
+	}
+
+	data, err := os.ReadFile(fi.Path) //nolint:gosec // Reading manifest files from repository
+	if err != nil {
+		return ws, false
+	}
+
+	switch fi.Name {
+	case "go.mod":
+		ws.Type, ws.Language = types.WorkspaceTypeGo, types.LanguageGo
+		if m := goModulePattern.FindSubmatch(data); m != nil {
+			ws.Name = string(m[1])
+		}
+	case "package.json":
+		ws.Type, ws.Language = d.npmFlavor(fi.Path), types.LanguageJavaScript
+		if _, err := os.Stat(filepath.Join(filepath.Dir(fi.Path), "tsconfig.json")); err == nil {
+			ws.Language = types.LanguageTypeScript
+		}
+
+		var pkg PackageJSON
+		if json.Unmarshal(data, &pkg) == nil && pkg.Name != "" {
+			ws.Name = pkg.Name
+		}
+	case "pom.xml":
+		ws.Type, ws.Language = types.WorkspaceTypeMaven, types.LanguageJava
+
+		var pom struct {
+			ArtifactID string `xml:"artifactId"`
+		}
+		if xml.Unmarshal(data, &pom) == nil && pom.ArtifactID != "" {
+			ws.Name = pom.ArtifactID
+		}
+	case "build.gradle", "build.gradle.kts":
+		ws.Type, ws.Language = types.WorkspaceTypeGradle, types.LanguageJava
+	case "pyproject.toml", "setup.py":
+		ws.Type, ws.Language = types.WorkspaceTypePython, types.LanguagePython
+		if m := pyProjectNamePattern.FindSubmatch(data); m != nil {
+			ws.Name = string(m[1])
+		}
+	default:
+		return ws, false
+	}
+
+	return ws, true
+}
+
+// npmFlavor identifies the package manager from the lock file next to package.json.
+func (d *WorkspaceDetector) npmFlavor(manifest string) types.WorkspaceType {
+	dir := filepath.Dir(manifest)
+
+	switch {
+	case fileExists(filepath.Join(dir, "pnpm-lock.yaml")):
+		return types.WorkspaceTypePnpm
+	case fileExists(filepath.Join(dir, "yarn.lock")):
+		return types.WorkspaceTypeYarn
+	default:
+		return types.WorkspaceTypeNpm
+	}
+}
+
+func (d *WorkspaceDetector) absRoot() string {
+	abs, err := filepath.Abs(d.rootPath)
+	if err != nil {
+		return d.rootPath
+	}
+
+	return abs
+}
+
+func fileExists(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// WorkspaceFor returns the innermost workspace containing relPath, or nil.
+func WorkspaceFor(workspaces []types.Workspace, relPath string) *types.Workspace {
+	relPath = filepath.ToSlash(relPath)
+
+	var best *types.Workspace
+
+	for i := range workspaces {
+		ws := &workspaces[i]
+		if ws.Path != "." && relPath != ws.Path && !strings.HasPrefix(relPath, ws.Path+"/") {
+			continue
+		}
+
+		if best == nil || workspaceDepth(ws.Path) > workspaceDepth(best.Path) {
+			best = ws
+		}
+	}
+
+	return best
+}
+
+// workspaceDepth ranks workspace paths so that nested workspaces win over their parents.
+func workspaceDepth(p string) int {
+	if p == "." {
+		return 0
+	}
+
+	return strings.Count(p, "/") + 1
+}
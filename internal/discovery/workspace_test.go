@@ -0,0 +1,77 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+func TestWorkspaceDetector_Detect(t *testing.T) {
+	dir := testutil.TempDir(t)
+
+	testutil.WriteFile(t, dir, "go.mod", "module example.com/mono\n\ngo 1.24")
+	testutil.WriteFile(t, dir, "package.json", `{"name": "mono-tools"}`)
+	testutil.WriteFile(t, dir, "web/package.json", `{"name": "@acme/web"}`)
+	testutil.WriteFile(t, dir, "web/tsconfig.json", "{}")
+	testutil.WriteFile(t, dir, "web/pnpm-lock.yaml", "")
+	testutil.WriteFile(t, dir, "services/orders/pom.xml",
+		"<project><groupId>com.acme</groupId><artifactId>orders</artifactId></project>")
+	testutil.WriteFile(t, dir, "services/billing/build.gradle.kts", "plugins {}")
+	testutil.WriteFile(t, dir, "ml/pyproject.toml", "[project]\nname = \"acme-ml\"\n")
+	testutil.WriteFile(t, dir, "node_modules/dep/package.json", `{"name": "dep"}`)
+
+	workspaces, err := NewWorkspaceDetector(dir, NewWalker(dir)).Detect()
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	want := []types.Workspace{
+		{Name: "example.com/mono", Path: ".", Language: types.LanguageGo, Type: types.WorkspaceTypeGo},
+		{Name: "acme-ml", Path: "ml", Language: types.LanguagePython, Type: types.WorkspaceTypePython},
+		{Name: "billing", Path: "services/billing", Language: types.LanguageJava, Type: types.WorkspaceTypeGradle},
+		{Name: "orders", Path: "services/orders", Language: types.LanguageJava, Type: types.WorkspaceTypeMaven},
+		{Name: "@acme/web", Path: "web", Language: types.LanguageTypeScript, Type: types.WorkspaceTypePnpm},
+	}
+
+	if len(workspaces) != len(want) {
+		t.Fatalf("Detect() = %+v, want %d workspaces", workspaces, len(want))
+	}
+
+	for i := range want {
+		if workspaces[i] != want[i] {
+			t.Errorf("workspace[%d] = %+v, want %+v", i, workspaces[i], want[i])
+		}
+	}
+}
+
+func TestWorkspaceFor(t *testing.T) {
+	workspaces := []types.Workspace{
+		{Name: "root", Path: "."},
+		{Name: "web", Path: "web"},
+		{Name: "admin", Path: "web/admin"},
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"main_test.go", "root"},
+		{"web/src/app.test.ts", "web"},
+		{"web/admin/page.test.ts", "admin"},
+		{"webapp/index.test.js", "root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			ws := WorkspaceFor(workspaces, tt.path)
+			if ws == nil || ws.Name != tt.want {
+				t.Errorf("WorkspaceFor(%q) = %+v, want %s", tt.path, ws, tt.want)
+			}
+		})
+	}
+
+	if ws := WorkspaceFor(workspaces[1:], "cmd/main.go"); ws != nil {
+		t.Errorf("WorkspaceFor outside any workspace = %+v, want nil", ws)
+	}
+}
@@ -0,0 +1,310 @@
+package fixtures
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// mockConfigFiles are the root files that configure Jest and Vitest.
+var mockConfigFiles = []string{
+	"package.json",
+	"jest.config.js", "jest.config.ts", "jest.config.mjs", "jest.config.cjs", "jest.config.json",
+	"vitest.config.js", "vitest.config.ts", "vitest.config.mjs", "vitest.config.mts",
+}
+
+// mockResetPattern matches runner options that reset mocks between tests.
+var mockResetPattern = regexp.MustCompile(`["']?(clearMocks|resetMocks|restoreMocks|mockReset)["']?\s*:\s*true`)
+
+// analyzers analyze the files of each supported language.
+var analyzers = map[types.Language]func(relPath string, src []byte) (*fileResult, error){
+	types.LanguageGo:         analyzeGo,
+	types.LanguagePython:     analyzePython,
+	types.LanguageJavaScript: analyzeJavaScript,
+	types.LanguageTypeScript: analyzeJavaScript,
+}
+
+// Analyzer resolves the fixtures of the tests of a repository.
+type Analyzer struct {
+	walker *discovery.Walker
+}
+
+// NewAnalyzer creates an analyzer for the repository of walker.
+func NewAnalyzer(walker *discovery.Walker) *Analyzer {
+	return &Analyzer{walker: walker}
+}
+
+// Analyze walks the repository, finds the fixtures of every test file and
+// conftest.py, and resolves the fixtures each test depends on. Files that
+// cannot be read or parsed are logged and skipped.
+func (a *Analyzer) Analyze() (*Report, error) {
+	results := make(map[string]*fileResult)
+
+	_, err := a.walker.Walk(func(fi discovery.FileInfo) error {
+		if !discovery.IsTestFile(fi.RelPath) && fi.Name != "conftest.py" {
+			return nil
+		}
+
+		analyze, ok := analyzers[discovery.LanguageOf(fi.Name)]
+		if !ok {
+			return nil
+		}
+
+		src, err := os.ReadFile(fi.Path) //nolint:gosec // Reading source files from repository
+		if err != nil {
+			logger.Warn("Failed to read test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		relPath := filepath.ToSlash(fi.RelPath)
+
+		result, err := analyze(relPath, src)
+		if err != nil {
+			logger.Warn("Failed to analyze test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		results[relPath] = result
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	return newReport(results, a.resetsMocks()), nil
+}
+
+// resetsMocks reports whether the Jest or Vitest configuration at the root
+// of the repository resets mocks between tests.
+func (a *Analyzer) resetsMocks() bool {
+	for _, name := range mockConfigFiles {
+		data, err := os.ReadFile(filepath.Join(a.walker.Root, name)) //nolint:gosec // Reading config files from repository
+		if err == nil && mockResetPattern.Match(data) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resolver finds the fixtures a test refers to by name.
+type resolver struct {
+	// files maps file paths onto their fixtures by name
+	files map[string]map[string]*Fixture
+
+	// dirs maps Go package directories onto their fixtures by name
+	dirs map[string]map[string]*Fixture
+}
+
+// newReport resolves the fixtures of every test across files and
+// summarizes the results.
+//
+//nolint:gocognit,gocyclo // Resolution differs per language
+func newReport(results map[string]*fileResult, resetsMocks bool) *Report {
+	paths := make([]string, 0, len(results))
+	for p := range results {
+		paths = append(paths, p)
+	}
+
+	sort.Strings(paths)
+
+	var (
+		fixtures []*Fixture
+		findings []types.Finding
+		assigned = make(map[string]map[string]bool)
+		r        = resolver{files: make(map[string]map[string]*Fixture), dirs: make(map[string]map[string]*Fixture)}
+	)
+
+	for _, p := range paths {
+		result := results[p]
+		dir := path.Dir(p)
+		r.files[p] = make(map[string]*Fixture)
+
+		if assigned[dir] == nil {
+			assigned[dir] = make(map[string]bool)
+			r.dirs[dir] = make(map[string]*Fixture)
+		}
+
+		for _, name := range result.assigned {
+			assigned[dir][name] = true
+		}
+
+		for i := range result.fixtures {
+			f := &result.fixtures[i]
+			fixtures = append(fixtures, f)
+			r.files[p][f.Name] = f
+
+			if f.Language == types.LanguageGo {
+				r.dirs[dir][f.Name] = f
+			}
+		}
+
+		for _, finding := range result.findings {
+			if finding.CheckID != CheckMockWithoutReset || !resetsMocks {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	for _, f := range fixtures {
+		if f.Kind == KindPackageVariable && assigned[path.Dir(f.File)][f.Name] {
+			f.Mutable = true
+		}
+	}
+
+	var tests []TestFixtures
+
+	for _, p := range paths {
+		for _, use := range results[p].tests {
+			resolved := r.resolve(p, use)
+
+			for _, name := range use.mutates {
+				f := r.lookup(p, use.Language, name)
+				if f == nil || f.Kind != KindPytestFixture {
+					continue
+				}
+
+				f.Mutable = true
+
+				if f.Scope.Shared() {
+					findings = append(findings, newFinding(CheckMutableSharedFixture, p, use.Line, use.Line, use.Test,
+						"%s mutates the %s-scoped fixture %s", use.Test, f.Scope, f.Name))
+				}
+			}
+
+			use.Fixtures = nil
+			for _, f := range resolved {
+				use.Fixtures = append(use.Fixtures, f.ID)
+			}
+
+			sort.Strings(use.Fixtures)
+
+			tests = append(tests, use.TestFixtures)
+		}
+	}
+
+	report := &Report{Fixtures: []Fixture{}, Tests: []TestFixtures{}, Findings: findings}
+
+	for _, f := range fixtures {
+		report.Fixtures = append(report.Fixtures, *f)
+
+		if f.Scope.Shared() {
+			report.Summary.SharedFixtures++
+		}
+	}
+
+	mutable := make(map[string]bool)
+
+	for _, f := range fixtures {
+		if f.Mutable && (f.Kind == KindPackageVariable || f.Kind == KindModuleGlobal || f.Kind == KindSharedVariable) {
+			mutable[f.ID] = true
+		}
+	}
+
+	for _, test := range tests {
+		for _, id := range test.Fixtures {
+			test.GlobalState = test.GlobalState || mutable[id]
+		}
+
+		if len(test.Fixtures) > 0 {
+			report.Summary.TestsWithFixtures++
+		}
+
+		if test.GlobalState {
+			report.Summary.TestsWithGlobalState++
+		}
+
+		report.Tests = append(report.Tests, test)
+	}
+
+	if report.Findings == nil {
+		report.Findings = []types.Finding{}
+	}
+
+	sort.Slice(report.Fixtures, func(i, j int) bool { return report.Fixtures[i].ID < report.Fixtures[j].ID })
+	sort.Slice(report.Tests, func(i, j int) bool { return report.Tests[i].Test < report.Tests[j].Test })
+	sortFindings(report.Findings)
+
+	report.Summary.Tests = len(report.Tests)
+	report.Summary.Fixtures = len(report.Fixtures)
+	report.Summary.Findings = len(report.Findings)
+
+	return report
+}
+
+// resolve returns the fixtures a test uses, including pytest autouse
+// fixtures that apply to it.
+func (r *resolver) resolve(file string, use testUse) []*Fixture {
+	var (
+		resolved []*Fixture
+		seen     = make(map[string]bool)
+	)
+
+	add := func(f *Fixture) {
+		if f != nil && !seen[f.ID] {
+			seen[f.ID] = true
+			resolved = append(resolved, f)
+		}
+	}
+
+	if use.Language == types.LanguagePython {
+		for _, candidates := range r.pythonScopes(file) {
+			for _, f := range candidates {
+				if f.Autouse {
+					add(f)
+				}
+			}
+		}
+	}
+
+	for _, name := range use.uses {
+		add(r.lookup(file, use.Language, name))
+	}
+
+	return resolved
+}
+
+// lookup finds the fixture a test of file refers to by name: in the same Go
+// package, in the same Python file or the closest conftest.py, or in the
+// same JavaScript file.
+func (r *resolver) lookup(file string, lang types.Language, name string) *Fixture {
+	switch lang {
+	case types.LanguageGo:
+		return r.dirs[path.Dir(file)][name]
+	case types.LanguagePython:
+		for _, candidates := range r.pythonScopes(file) {
+			if f := candidates[name]; f != nil {
+				return f
+			}
+		}
+
+		return nil
+	default:
+		return r.files[file][name]
+	}
+}
+
+// pythonScopes returns the fixtures visible to a Python file, from the file
+// itself to the conftest.py of the repository root.
+func (r *resolver) pythonScopes(file string) []map[string]*Fixture {
+	scopes := []map[string]*Fixture{r.files[file]}
+
+	for dir := path.Dir(file); ; dir = path.Dir(dir) {
+		conftest := path.Join(dir, "conftest.py")
+		if conftest != file && r.files[conftest] != nil {
+			scopes = append(scopes, r.files[conftest])
+		}
+
+		if dir == "." || dir == "/" {
+			return scopes
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+func TestAnalyze(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "app/state_test.go", "package app\n\nvar hits int\n")
+	testutil.WriteFile(t, dir, "app/app_test.go",
+		"package app\n\nimport \"testing\"\n\nfunc TestHit(t *testing.T) {\n\thits++\n}\n\nfunc TestRead(t *testing.T) {\n\t_ = hits\n}\n")
+	testutil.WriteFile(t, dir, "conftest.py",
+		"import pytest\n\n@pytest.fixture(scope=\"session\")\ndef users():\n    return []\n\n@pytest.fixture(autouse=True)\ndef reset():\n    yield\n")
+	testutil.WriteFile(t, dir, "tests/test_users.py", "def test_adds(users):\n    users.append(1)\n")
+	testutil.WriteFile(t, dir, "web/a.test.js",
+		"jest.mock('./x');\ntest('a', () => { expect(f).toHaveBeenCalled(); });\ntest('b', () => { expect(f).toHaveBeenCalled(); });\n")
+
+	report, err := NewAnalyzer(discovery.NewWalker(dir)).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	tests := make(map[string]TestFixtures)
+	for _, test := range report.Tests {
+		tests[test.Test] = test
+	}
+
+	if hit := tests["app/app_test.go::TestHit"]; !hit.GlobalState || len(hit.Fixtures) != 1 || hit.Fixtures[0] != "app/state_test.go::hits" {
+		t.Errorf("TestHit = %+v, want the hits package variable from the other file", hit)
+	}
+
+	if read := tests["app/app_test.go::TestRead"]; !read.GlobalState {
+		t.Errorf("TestRead = %+v, want global state", read)
+	}
+
+	adds := tests["tests/test_users.py::test_adds"]
+	if len(adds.Fixtures) != 2 || adds.Fixtures[0] != "conftest.py::reset" || adds.Fixtures[1] != "conftest.py::users" {
+		t.Errorf("test_adds fixtures = %v, want the autouse and requested conftest fixtures", adds.Fixtures)
+	}
+
+	checks := make(map[string]int)
+	for _, f := range report.Findings {
+		checks[f.CheckID]++
+	}
+
+	want := map[string]int{CheckGlobalStateMutation: 1, CheckMutableSharedFixture: 1, CheckMockWithoutReset: 1}
+	for check, n := range want {
+		if checks[check] != n {
+			t.Errorf("%s findings = %d, want %d (all: %v)", check, checks[check], n, checks)
+		}
+	}
+
+	s := report.Summary
+	if s.Tests != 5 || s.Findings != len(report.Findings) || s.TestsWithGlobalState != 2 || s.Fixtures != len(report.Fixtures) {
+		t.Errorf("Summary = %+v", s)
+	}
+}
+
+func TestAnalyzeMockResetConfig(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "package.json", `{"jest": {"clearMocks": true}}`)
+	testutil.WriteFile(t, dir, "a.test.js",
+		"jest.mock('./x');\ntest('a', () => { expect(f).toHaveBeenCalled(); });\ntest('b', () => { expect(f).toBeCalled(); });\n")
+
+	report, err := NewAnalyzer(discovery.NewWalker(dir)).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if len(report.Findings) != 0 {
+		t.Errorf("Findings = %+v, want none when the runner clears mocks", report.Findings)
+	}
+}
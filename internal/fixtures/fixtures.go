@@ -0,0 +1,280 @@
+// Package fixtures analyzes how tests set up shared state.
+//
+// The analyzer finds the fixtures of every test file (package variables,
+// TestMain, testify suite setup and test helpers in Go; pytest fixtures,
+// module globals and unittest hooks in Python; lifecycle hooks, module mocks
+// and module-level variables in JavaScript and TypeScript), resolves the
+// fixtures each test depends on, and reports global state mutated by tests,
+// TestMain setup that is never torn down, shared pytest fixtures mutated by
+// tests, Jest and Vitest mocks that are never reset, and setup without
+// cleanup.
+package fixtures
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Kind categorizes fixtures.
+type Kind string
+
+// Kind constants.
+const (
+	KindPackageVariable Kind = "package-variable" // Go package-level variable
+	KindTestMain        Kind = "test-main"        // Go TestMain
+	KindSuiteSetup      Kind = "suite-setup"      // testify SetupSuite, SetupTest
+	KindHelper          Kind = "helper"           // Go helper taking *testing.T
+	KindPytestFixture   Kind = "pytest-fixture"   // @pytest.fixture
+	KindModuleGlobal    Kind = "module-global"    // Python module-level mutable value
+	KindHook            Kind = "hook"             // setUp, beforeEach, beforeAll, ...
+	KindModuleMock      Kind = "module-mock"      // jest.mock, vi.mock, module-level jest.fn
+	KindSharedVariable  Kind = "shared-variable"  // JavaScript module-level let or var
+)
+
+// Scope is the lifetime of a fixture.
+type Scope string
+
+// Scope constants, from the narrowest to the widest.
+const (
+	ScopeTest    Scope = "test"
+	ScopeClass   Scope = "class"
+	ScopeModule  Scope = "module"
+	ScopePackage Scope = "package"
+	ScopeSession Scope = "session"
+)
+
+// Shared reports whether a fixture of the scope outlives a single test.
+func (s Scope) Shared() bool {
+	return s != ScopeTest
+}
+
+// Check IDs of the findings reported by the analyzer.
+const (
+	CheckGlobalStateMutation  = "global-state-mutation"
+	CheckLeakyTestMain        = "leaky-test-main"
+	CheckMutableSharedFixture = "mutable-shared-fixture"
+	CheckMockWithoutReset     = "mock-without-reset"
+	CheckMissingCleanup       = "missing-cleanup"
+)
+
+// Fixture is shared setup that tests depend on.
+type Fixture struct {
+	// ID uniquely identifies the fixture as "<file>::<name>"
+	ID string `json:"id"`
+
+	// Name is the name tests refer to the fixture by
+	Name string `json:"name"`
+
+	// Kind categorizes the fixture
+	Kind Kind `json:"kind"`
+
+	// Scope is the lifetime of the fixture
+	Scope Scope `json:"scope"`
+
+	// File is the file declaring the fixture, relative to the repository root
+	File string `json:"file"`
+
+	// Line is the line where the fixture is declared
+	Line int `json:"line"`
+
+	// Language is the language of the file
+	Language types.Language `json:"language"`
+
+	// Mutable marks fixtures that functions other than their declaration assign
+	Mutable bool `json:"mutable,omitempty"`
+
+	// Teardown marks fixtures with cleanup (yield, t.Cleanup, afterEach, ...)
+	Teardown bool `json:"teardown,omitempty"`
+
+	// Autouse marks pytest fixtures applied to tests without requesting them
+	Autouse bool `json:"autouse,omitempty"`
+}
+
+// TestFixtures lists the fixtures a test depends on.
+type TestFixtures struct {
+	// Test is the ID of the test
+	Test string `json:"test"`
+
+	// File is the test file, relative to the repository root
+	File string `json:"file"`
+
+	// Line is the line where the test is declared
+	Line int `json:"line"`
+
+	// Language is the language of the test file
+	Language types.Language `json:"language"`
+
+	// Fixtures are the IDs of the fixtures the test depends on
+	Fixtures []string `json:"fixtures,omitempty"`
+
+	// GlobalState marks tests that depend on mutable package, module or
+	// file-level variables
+	GlobalState bool `json:"global_state,omitempty"`
+}
+
+// Summary aggregates the fixtures of a repository.
+type Summary struct {
+	// Tests is the number of analyzed tests
+	Tests int `json:"tests"`
+
+	// Fixtures is the number of fixtures
+	Fixtures int `json:"fixtures"`
+
+	// SharedFixtures is the number of fixtures that outlive a single test
+	SharedFixtures int `json:"shared_fixtures"`
+
+	// TestsWithFixtures is the number of tests depending on at least one fixture
+	TestsWithFixtures int `json:"tests_with_fixtures"`
+
+	// TestsWithGlobalState is the number of tests depending on mutable global state
+	TestsWithGlobalState int `json:"tests_with_global_state"`
+
+	// Findings is the number of findings
+	Findings int `json:"findings"`
+}
+
+// Report is the fixture analysis of a repository.
+type Report struct {
+	// Summary aggregates fixtures and findings
+	Summary Summary `json:"summary"`
+
+	// Fixtures are all fixtures, sorted by ID
+	Fixtures []Fixture `json:"fixtures"`
+
+	// Tests are the fixture dependencies of every test, sorted by ID
+	Tests []TestFixtures `json:"tests"`
+
+	// Findings are the fixture problems, sorted by file and line
+	Findings []types.Finding `json:"findings"`
+}
+
+// check describes the findings of a check ID.
+type check struct {
+	title       string
+	severity    types.Severity
+	rationale   string
+	remediation string
+	steps       []string
+}
+
+// checks are the checks of the analyzer.
+var checks = map[string]check{
+	CheckGlobalStateMutation: {
+		title:       "Global State Mutation",
+		severity:    types.SeverityMedium,
+		rationale:   "State changed by one test leaks into the tests that run after it, so results depend on test order.",
+		remediation: "Scope the state to the test and restore anything global when it ends",
+		steps: []string{
+			"Use t.Setenv, t.Chdir, monkeypatch or vi.stubEnv instead of changing the process environment",
+			"Create values inside the test or a per-test fixture instead of assigning package or module variables",
+			"When global state must change, restore it in t.Cleanup, a yield fixture or afterEach",
+		},
+	},
+	CheckLeakyTestMain: {
+		title:       "TestMain Setup Without Teardown",
+		severity:    types.SeverityMedium,
+		rationale:   "Resources created by TestMain stay alive for the whole package run and leak into later runs when they are never released.",
+		remediation: "Release what TestMain creates after m.Run returns",
+		steps: []string{
+			"Store the exit code of m.Run, tear down the resources, then call os.Exit with the code",
+			"Do not rely on defer in TestMain when it calls os.Exit: deferred calls do not run",
+		},
+	},
+	CheckMutableSharedFixture: {
+		title:       "Mutable Shared Fixture",
+		severity:    types.SeverityHigh,
+		rationale:   "A fixture shared by several tests that one test mutates makes the other tests depend on execution order.",
+		remediation: "Do not mutate fixtures that outlive a test",
+		steps: []string{
+			"Narrow the fixture to the default function scope",
+			"Or copy the value inside the test before changing it",
+		},
+	},
+	CheckMockWithoutReset: {
+		title:       "Mocks Never Reset",
+		severity:    types.SeverityMedium,
+		rationale:   "Module-level mocks keep their recorded calls and implementations across tests, so call assertions depend on the tests that ran before.",
+		remediation: "Reset mocks between tests",
+		steps: []string{
+			"Call jest.clearAllMocks() or vi.clearAllMocks() in afterEach",
+			"Or enable clearMocks, resetMocks or restoreMocks in the test runner configuration",
+		},
+	},
+	CheckMissingCleanup: {
+		title:       "Setup Without Cleanup",
+		severity:    types.SeverityMedium,
+		rationale:   "Servers, files, connections and environment changes created during setup outlive the tests when nothing releases them.",
+		remediation: "Release resources in the matching teardown",
+		steps: []string{
+			"Go: register cleanup with t.Cleanup in helpers, or add TearDownTest/TearDownSuite",
+			"pytest: yield the resource and release it after the yield",
+			"JavaScript: add the matching afterEach or afterAll",
+		},
+	},
+}
+
+// newFinding creates a finding of a check.
+func newFinding(checkID, file string, start, end int, test, format string, args ...any) types.Finding {
+	c := checks[checkID]
+
+	return types.Finding{
+		CheckID:     checkID,
+		Type:        types.FindingTypeQuality,
+		Severity:    c.severity,
+		Title:       c.title,
+		Description: fmt.Sprintf(format, args...),
+		Rationale:   c.rationale,
+		Location:    types.Location{File: file, StartLine: start, EndLine: end},
+		Test:        test,
+		Remediation: &types.Remediation{
+			Summary: c.remediation,
+			Steps:   c.steps,
+			Effort:  types.EffortLow,
+		},
+	}
+}
+
+// sortFindings orders findings by file, start line and check.
+func sortFindings(findings []types.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Location, findings[j].Location
+		if a.File != b.File {
+			return a.File < b.File
+		}
+
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+
+		return findings[i].CheckID < findings[j].CheckID
+	})
+}
+
+// fileResult is the analysis of a single file before fixtures are
+// resolved across files.
+type fileResult struct {
+	fixtures []Fixture
+	tests    []testUse
+	findings []types.Finding
+
+	// assigned are the package variables assigned anywhere in the file
+	assigned []string
+}
+
+// testUse is a test with the names of the fixtures it may use.
+type testUse struct {
+	TestFixtures
+
+	// uses are the names the test requests or references
+	uses []string
+
+	// mutates are the requested fixtures the test mutates
+	mutates []string
+}
+
+// newFixture creates a fixture declared in a file.
+func newFixture(file string, lang types.Language, name string, kind Kind, scope Scope, line int) Fixture {
+	return Fixture{ID: file + "::" + name, Name: name, Kind: kind, Scope: scope, File: file, Line: line, Language: lang}
+}
@@ -0,0 +1,449 @@
+package fixtures
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/goast"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// goResources are the functions that create resources or change process
+// state that a test must release or restore.
+var goResources = map[string][]string{
+	"os":                {"Create", "CreateTemp", "MkdirTemp", "OpenFile", "Setenv", "Unsetenv", "Chdir"},
+	"io/ioutil":         {"TempDir", "TempFile"},
+	"net":               {"Listen", "ListenPacket", "Dial"},
+	"net/http/httptest": {"NewServer", "NewTLSServer"},
+	"database/sql":      {"Open"},
+}
+
+// goEnvCalls are the os functions that change the process environment.
+var goEnvCalls = []string{"Setenv", "Unsetenv", "Clearenv", "Chdir"}
+
+// goSuiteSetup maps testify setup methods onto their scope and teardown.
+var goSuiteSetup = map[string]struct {
+	scope    Scope
+	teardown string
+}{
+	"SetupSuite":   {scope: ScopeClass, teardown: "TearDownSuite"},
+	"SetupTest":    {scope: ScopeTest, teardown: "TearDownTest"},
+	"BeforeTest":   {scope: ScopeTest, teardown: "AfterTest"},
+	"SetupSubTest": {scope: ScopeTest, teardown: "TearDownSubTest"},
+}
+
+// goFile holds the state of the analysis of one Go test file.
+type goFile struct {
+	*goast.File
+
+	methods map[string]bool
+	result  fileResult
+}
+
+// analyzeGo finds the fixtures, tests and fixture problems of a Go test file.
+func analyzeGo(relPath string, src []byte) (*fileResult, error) {
+	file, err := goast.Parse(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &goFile{File: file, methods: make(map[string]bool)}
+
+	g.packageVariables()
+
+	for _, decl := range file.AST.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && len(fn.Recv.List) == 1 {
+			g.methods[goast.ReceiverType(fn.Recv.List[0].Type)+"."+fn.Name.Name] = true
+		}
+	}
+
+	for _, decl := range file.AST.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		for _, w := range g.stateWrites(fn) {
+			if !strings.Contains(w.name, ".") {
+				g.result.assigned = append(g.result.assigned, w.name)
+			}
+		}
+
+		switch {
+		case fn.Recv == nil && fn.Name.Name == "TestMain":
+			g.testMain(fn)
+		case goast.IsTest(fn) || goast.IsSuiteTest(fn):
+			g.test(fn)
+		case fn.Recv != nil:
+			g.suiteSetup(fn)
+		case goast.HasTestingParam(fn.Type) && !strings.HasPrefix(fn.Name.Name, "Benchmark") &&
+			!strings.HasPrefix(fn.Name.Name, "Fuzz"):
+			g.helper(fn)
+		}
+	}
+
+	return &g.result, nil
+}
+
+// packageVariables records the package-level variables of the file.
+// Command-line flags and blank variables are not fixtures.
+func (g *goFile) packageVariables() {
+	for _, decl := range g.AST.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Values) == 1 {
+				if call, ok := vs.Values[0].(*ast.CallExpr); ok {
+					if _, flag := g.PkgCall(call, "flag"); flag {
+						continue
+					}
+				}
+			}
+
+			for _, id := range vs.Names {
+				if id.Name != "_" {
+					g.result.fixtures = append(g.result.fixtures,
+						newFixture(g.Path, types.LanguageGo, id.Name, KindPackageVariable, ScopePackage, g.Line(id.Pos())))
+				}
+			}
+		}
+	}
+}
+
+// resources returns the calls of node that create resources or change
+// process state, such as "os.MkdirTemp" or "httptest.NewServer".
+func (g *goFile) resources(node ast.Node) []string {
+	var found []string
+
+	for _, call := range goast.Calls(node) {
+		for importPath, names := range goResources {
+			if name, ok := g.PkgCall(call, importPath, names...); ok {
+				found = append(found, path.Base(importPath)+"."+name)
+			}
+		}
+	}
+
+	return found
+}
+
+// callsMethod reports whether node calls a method with one of the names.
+func callsMethod(node ast.Node, names ...string) bool {
+	for _, call := range goast.Calls(node) {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			for _, name := range names {
+				if sel.Sel.Name == name {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// goLocals collects the identifiers declared by a function: its receiver,
+// parameters, results and every variable declared in its body.
+func goLocals(fn *ast.FuncDecl) map[string]bool {
+	locals := goast.LocalNames(fn.Body)
+
+	goast.AddFields(locals, fn.Recv)
+	goast.AddFields(locals, fn.Type.Params)
+	goast.AddFields(locals, fn.Type.Results)
+
+	return locals
+}
+
+// stateWrite is an assignment to package state or a change of the process
+// environment.
+type stateWrite struct {
+	name string
+	node ast.Node
+}
+
+// stateWrites returns the package variables, variables of imported packages
+// and environment functions a function writes to, in source order and
+// without duplicates.
+func (g *goFile) stateWrites(fn *ast.FuncDecl) []stateWrite {
+	locals := goLocals(fn)
+	seen := make(map[string]bool)
+
+	var writes []stateWrite
+
+	add := func(name string, node ast.Node) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			writes = append(writes, stateWrite{name: name, node: node})
+		}
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok != token.DEFINE {
+				for _, lhs := range x.Lhs {
+					add(g.StateTarget(lhs, locals), x)
+				}
+			}
+		case *ast.IncDecStmt:
+			add(g.StateTarget(x.X, locals), x)
+		case *ast.CallExpr:
+			if name, ok := g.PkgCall(x, "os", goEnvCalls...); ok {
+				add("os."+name, x)
+			}
+		}
+
+		return true
+	})
+
+	return writes
+}
+
+// restored returns the state a function restores in deferred calls or
+// t.Cleanup callbacks: assigned variables, and "os.env" when it calls an
+// environment function.
+func (g *goFile) restored(fn *ast.FuncDecl) map[string]bool {
+	restored := make(map[string]bool)
+	locals := goLocals(fn)
+
+	collect := func(node ast.Node) {
+		ast.Inspect(node, func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.AssignStmt:
+				for _, lhs := range x.Lhs {
+					if name := g.StateTarget(lhs, locals); name != "" {
+						restored[name] = true
+					}
+				}
+			case *ast.CallExpr:
+				if _, ok := g.PkgCall(x, "os", "Setenv", "Unsetenv", "Chdir"); ok {
+					restored["os.env"] = true
+				}
+			}
+
+			return true
+		})
+	}
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.DeferStmt:
+			collect(x.Call)
+		case *ast.CallExpr:
+			if sel, ok := x.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Cleanup" {
+				for _, arg := range x.Args {
+					collect(arg)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return restored
+}
+
+// test records the fixtures a test may use and reports the global state it
+// changes without restoring it.
+func (g *goFile) test(fn *ast.FuncDecl) {
+	name := fn.Name.Name
+	id := g.Path + "::" + name
+	uses := []string{"TestMain"}
+
+	if fn.Recv != nil && len(fn.Recv.List) == 1 {
+		recv := goast.ReceiverType(fn.Recv.List[0].Type)
+		id = g.Path + "::" + recv + "::" + name
+
+		for setup := range goSuiteSetup {
+			uses = append(uses, recv+"."+setup)
+		}
+	}
+
+	locals := goLocals(fn)
+	seen := make(map[string]bool)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(x.X, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok && !locals[id.Name] && !seen[id.Name] {
+					seen[id.Name] = true
+					uses = append(uses, id.Name)
+				}
+
+				return true
+			})
+
+			return false
+		case *ast.Ident:
+			if !locals[x.Name] && !seen[x.Name] {
+				seen[x.Name] = true
+				uses = append(uses, x.Name)
+			}
+		}
+
+		return true
+	})
+
+	g.result.tests = append(g.result.tests, testUse{
+		TestFixtures: TestFixtures{Test: id, File: g.Path, Line: g.Line(fn.Pos()), Language: types.LanguageGo},
+		uses:         uses,
+	})
+
+	restored := g.restored(fn)
+
+	var leaked []string
+
+	var first ast.Node
+
+	for _, w := range g.stateWrites(fn) {
+		if restored[w.name] || strings.HasPrefix(w.name, "os.") && restored["os.env"] {
+			continue
+		}
+
+		if first == nil {
+			first = w.node
+		}
+
+		leaked = append(leaked, w.name)
+	}
+
+	if first != nil {
+		g.result.findings = append(g.result.findings, newFinding(CheckGlobalStateMutation, g.Path,
+			g.Line(first.Pos()), g.Line(first.End()), id, "%s changes %s without restoring it",
+			name, strings.Join(leaked, ", ")))
+	}
+}
+
+// testMain records TestMain as a fixture of the package and reports setup
+// that is never torn down.
+func (g *goFile) testMain(fn *ast.FuncDecl) {
+	fixture := newFixture(g.Path, types.LanguageGo, "TestMain", KindTestMain, ScopePackage, g.Line(fn.Pos()))
+
+	run := -1
+
+	for i, stmt := range fn.Body.List {
+		if callsMethod(stmt, "Run") {
+			run = i
+			break
+		}
+	}
+
+	exits := false
+
+	for _, call := range goast.Calls(fn.Body) {
+		if _, ok := g.PkgCall(call, "os", "Exit"); ok {
+			exits = true
+		}
+	}
+
+	deferred := false
+	after := false
+
+	for i, stmt := range fn.Body.List {
+		switch {
+		case i < run:
+			if _, ok := stmt.(*ast.DeferStmt); ok {
+				deferred = true
+			}
+		case i > run && run >= 0:
+			if expr, ok := stmt.(*ast.ExprStmt); ok {
+				if call, ok := expr.X.(*ast.CallExpr); ok {
+					if _, exit := g.PkgCall(call, "os", "Exit"); exit {
+						continue
+					}
+				}
+			}
+
+			if _, ok := stmt.(*ast.ReturnStmt); !ok {
+				after = true
+			}
+		}
+	}
+
+	fixture.Teardown = after || deferred && !exits
+	g.result.fixtures = append(g.result.fixtures, fixture)
+
+	if run < 0 || fixture.Teardown {
+		return
+	}
+
+	setup := g.resources(&ast.BlockStmt{List: fn.Body.List[:run]})
+	if len(setup) == 0 {
+		return
+	}
+
+	reason := "never undoes them"
+	if deferred {
+		reason = "its deferred teardown never runs because it calls os.Exit"
+	}
+
+	g.result.findings = append(g.result.findings, newFinding(CheckLeakyTestMain, g.Path,
+		g.Line(fn.Pos()), g.Line(fn.End()), "", "TestMain calls %s before m.Run but %s",
+		strings.Join(unique(setup), ", "), reason))
+}
+
+// suiteSetup records testify setup methods and reports those that create
+// resources without a matching teardown method.
+func (g *goFile) suiteSetup(fn *ast.FuncDecl) {
+	setup, ok := goSuiteSetup[fn.Name.Name]
+	if !ok {
+		return
+	}
+
+	recv := goast.ReceiverType(fn.Recv.List[0].Type)
+	name := recv + "." + fn.Name.Name
+
+	fixture := newFixture(g.Path, types.LanguageGo, name, KindSuiteSetup, setup.scope, g.Line(fn.Pos()))
+	fixture.Teardown = g.methods[recv+"."+setup.teardown]
+	g.result.fixtures = append(g.result.fixtures, fixture)
+
+	if resources := g.resources(fn.Body); len(resources) > 0 && !fixture.Teardown {
+		g.result.findings = append(g.result.findings, newFinding(CheckMissingCleanup, g.Path,
+			g.Line(fn.Pos()), g.Line(fn.End()), "", "%s calls %s but %s has no %s method",
+			name, strings.Join(unique(resources), ", "), recv, setup.teardown))
+	}
+}
+
+// helper records a test helper and reports helpers that create resources
+// without registering cleanup or returning a cleanup function.
+func (g *goFile) helper(fn *ast.FuncDecl) {
+	fixture := newFixture(g.Path, types.LanguageGo, fn.Name.Name, KindHelper, ScopeTest, g.Line(fn.Pos()))
+	fixture.Teardown = callsMethod(fn.Body, "Cleanup")
+
+	if fn.Type.Results != nil {
+		for _, field := range fn.Type.Results.List {
+			if _, ok := field.Type.(*ast.FuncType); ok {
+				fixture.Teardown = true
+			}
+		}
+	}
+
+	g.result.fixtures = append(g.result.fixtures, fixture)
+
+	if resources := g.resources(fn.Body); len(resources) > 0 && !fixture.Teardown {
+		g.result.findings = append(g.result.findings, newFinding(CheckMissingCleanup, g.Path,
+			g.Line(fn.Pos()), g.Line(fn.End()), "", "helper %s calls %s without registering t.Cleanup",
+			fn.Name.Name, strings.Join(unique(resources), ", ")))
+	}
+}
+
+// unique removes duplicates from names, keeping the first occurrence.
+func unique(names []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(names))
+
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
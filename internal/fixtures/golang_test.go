@@ -0,0 +1,180 @@
+package fixtures
+
+import (
+	"strings"
+	"testing"
+)
+
+const goFixtureSource = `package app
+
+import (
+	"flag"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+var cache = map[string]int{}
+
+var server *httptest.Server
+
+func TestMain(m *testing.M) {
+	server = httptest.NewServer(nil)
+	os.Exit(m.Run())
+}
+
+func TestFills(t *testing.T) {
+	cache["a"] = 1
+	os.Setenv("MODE", "test")
+}
+
+func TestRestores(t *testing.T) {
+	old := cache
+	cache = map[string]int{}
+	t.Cleanup(func() { cache = old })
+	t.Setenv("MODE", "test")
+}
+
+func newDir(t *testing.T) string {
+	dir, _ := os.MkdirTemp("", "app")
+	return dir
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	s := httptest.NewServer(nil)
+	t.Cleanup(s.Close)
+	return s
+}
+
+type StoreSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *StoreSuite) SetupTest() {
+	s.dir, _ = os.MkdirTemp("", "store")
+}
+
+func (s *StoreSuite) TestSaves() {
+	s.Equal(server.URL, s.dir)
+}
+
+func TestStore(t *testing.T) {
+	suite.Run(t, new(StoreSuite))
+}
+`
+
+func TestAnalyzeGo(t *testing.T) {
+	result, err := analyzeGo("app/app_test.go", []byte(goFixtureSource))
+	if err != nil {
+		t.Fatalf("analyzeGo() error = %v", err)
+	}
+
+	fixtures := make(map[string]Fixture)
+	for _, f := range result.fixtures {
+		fixtures[f.Name] = f
+	}
+
+	want := map[string]Kind{
+		"cache":                KindPackageVariable,
+		"server":               KindPackageVariable,
+		"TestMain":             KindTestMain,
+		"newDir":               KindHelper,
+		"newServer":            KindHelper,
+		"StoreSuite.SetupTest": KindSuiteSetup,
+	}
+
+	if len(fixtures) != len(want) {
+		t.Errorf("fixtures = %+v, want %d", result.fixtures, len(want))
+	}
+
+	for name, kind := range want {
+		if fixtures[name].Kind != kind {
+			t.Errorf("fixture %s kind = %q, want %q", name, fixtures[name].Kind, kind)
+		}
+	}
+
+	if fixtures["TestMain"].Teardown || !fixtures["newServer"].Teardown || fixtures["newDir"].Teardown {
+		t.Errorf("teardown detection wrong: %+v", fixtures)
+	}
+
+	wantFindings := map[string]string{
+		CheckLeakyTestMain:       "httptest.NewServer",
+		CheckGlobalStateMutation: "TestFills changes cache, os.Setenv",
+		CheckMissingCleanup:      "",
+	}
+
+	found := make(map[string]int)
+
+	for _, f := range result.findings {
+		found[f.CheckID]++
+
+		if want := wantFindings[f.CheckID]; !strings.Contains(f.Description, want) {
+			t.Errorf("%s description = %q, want it to contain %q", f.CheckID, f.Description, want)
+		}
+	}
+
+	if found[CheckLeakyTestMain] != 1 || found[CheckGlobalStateMutation] != 1 || found[CheckMissingCleanup] != 2 {
+		t.Errorf("findings = %v, want 1 leaky TestMain, 1 global state mutation, 2 missing cleanups", found)
+	}
+
+	uses := make(map[string][]string)
+	for _, use := range result.tests {
+		uses[use.Test] = use.uses
+	}
+
+	if !contains(uses["app/app_test.go::StoreSuite::TestSaves"], "StoreSuite.SetupTest") ||
+		!contains(uses["app/app_test.go::StoreSuite::TestSaves"], "server") {
+		t.Errorf("suite test uses = %v, want the suite setup and server", uses["app/app_test.go::StoreSuite::TestSaves"])
+	}
+
+	if !contains(uses["app/app_test.go::TestFills"], "cache") || contains(uses["app/app_test.go::TestRestores"], "old") {
+		t.Errorf("uses = %v", uses)
+	}
+
+	if !contains(result.assigned, "cache") || !contains(result.assigned, "server") {
+		t.Errorf("assigned = %v, want cache and server", result.assigned)
+	}
+}
+
+func TestAnalyzeGoTestMainTeardown(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		leak bool
+	}{
+		{name: "teardown after run", body: "db := open()\n\tcode := m.Run()\n\tdb.Close()\n\tos.Exit(code)", leak: false},
+		{name: "defer without exit", body: "dir, _ := os.MkdirTemp(\"\", \"x\")\n\tdefer os.RemoveAll(dir)\n\tm.Run()", leak: false},
+		{name: "defer with exit", body: "dir, _ := os.MkdirTemp(\"\", \"x\")\n\tdefer os.RemoveAll(dir)\n\tos.Exit(m.Run())", leak: true},
+		{name: "no setup", body: "os.Exit(m.Run())", leak: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := "package app\n\nimport (\n\t\"os\"\n\t\"testing\"\n)\n\nfunc TestMain(m *testing.M) {\n\t" + tt.body + "\n}\n"
+
+			result, err := analyzeGo("app_test.go", []byte(src))
+			if err != nil {
+				t.Fatalf("analyzeGo() error = %v", err)
+			}
+
+			if leak := len(result.findings) == 1 && result.findings[0].CheckID == CheckLeakyTestMain; leak != tt.leak {
+				t.Errorf("findings = %+v, want leak %v", result.findings, tt.leak)
+			}
+		})
+	}
+}
+
+func contains(values []string, want string) bool {
+	for _, v := range values {
+		if v == want {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,439 @@
+package fixtures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// jsTeardowns maps setup hooks onto the hook that tears them down.
+var jsTeardowns = map[string]string{
+	"beforeAll":  "afterAll",
+	"before":     "after",
+	"beforeEach": "afterEach",
+}
+
+// jsResources are the last segments of calls that create resources or
+// install fakes that a hook must release or restore.
+var jsResources = map[string]bool{
+	"listen":        true,
+	"createServer":  true,
+	"connect":       true,
+	"mkdtemp":       true,
+	"mkdtempSync":   true,
+	"spyOn":         true,
+	"useFakeTimers": true,
+	"setInterval":   true,
+}
+
+// jsMockFactories are the calls that create module-level mock functions.
+var jsMockFactories = map[string]bool{
+	"jest.fn": true, "jest.spyOn": true, "vi.fn": true, "vi.spyOn": true,
+}
+
+// jsMockResets are the calls that clear recorded mock calls between tests.
+var jsMockResets = []string{
+	"clearAllMocks", "resetAllMocks", "restoreAllMocks", "mockClear", "mockReset", "mockRestore",
+}
+
+// jsFile holds the state of the analysis of one JavaScript or TypeScript
+// test file.
+type jsFile struct {
+	path   string
+	lang   types.Language
+	toks   []lexer.Token
+	depth  []int
+	shared map[string]bool
+	mocks  map[string]bool
+
+	// bindings maps imported names onto their module specifier
+	bindings map[string]string
+
+	// restoresEnv marks files whose after hooks touch process.env
+	restoresEnv bool
+
+	// callAsserts is the number of tests asserting the call history of mocks
+	callAsserts int
+
+	result fileResult
+}
+
+// analyzeJavaScript finds the fixtures, tests and fixture problems of a
+// JavaScript or TypeScript test file.
+func analyzeJavaScript(relPath string, src []byte) (*fileResult, error) {
+	file, err := inventory.NewJavaScriptParser().Parse(relPath, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JavaScript file: %w", err)
+	}
+
+	j := &jsFile{
+		path:   relPath,
+		lang:   file.Language,
+		toks:   lexer.Tokenize(src, lexer.JavaScript),
+		shared: make(map[string]bool),
+		mocks:  make(map[string]bool),
+	}
+	j.depth = jsDepths(j.toks)
+	j.bindings = jsImports(j.toks, j.depth)
+
+	for k := 0; k+1 < len(j.toks); k++ {
+		tok := j.toks[k]
+		if tok.Kind != lexer.Ident || (tok.Text != "afterEach" && tok.Text != "afterAll" && tok.Text != "after") ||
+			!j.toks[k+1].Is(lexer.Punct, "(") {
+			continue
+		}
+
+		if jsTouchesEnv(j.toks[k+1 : lexer.Match(j.toks, k+1)+1]) {
+			j.restoresEnv = true
+		}
+	}
+
+	j.sharedVariables()
+	j.moduleMocks(file.Mocks)
+
+	hooks := j.hooks(nil, file.Hooks)
+	j.tests(file.Tests, hooks)
+	j.mockReset()
+
+	return &j.result, nil
+}
+
+// sharedVariables records module-level let and var declarations and marks
+// the ones assigned inside functions.
+func (j *jsFile) sharedVariables() {
+	toks := j.toks
+
+	for k := 0; k+1 < len(toks); k++ {
+		if j.depth[k] != 0 || !(toks[k].Is(lexer.Ident, "let") || toks[k].Is(lexer.Ident, "var")) {
+			continue
+		}
+
+		for n := k + 1; n < len(toks) && j.depth[n] == 0 && toks[n].Line == toks[k].Line; n++ {
+			if toks[n].Kind == lexer.Ident && (n == k+1 || toks[n-1].Is(lexer.Punct, ",")) && !j.shared[toks[n].Text] {
+				j.shared[toks[n].Text] = true
+				j.result.fixtures = append(j.result.fixtures,
+					newFixture(j.path, j.lang, toks[n].Text, KindSharedVariable, ScopeModule, toks[n].Line))
+			}
+
+			if toks[n].Is(lexer.Punct, ";") {
+				break
+			}
+		}
+	}
+
+	assigned := make(map[string]bool)
+
+	for k, tok := range toks {
+		if j.depth[k] > 0 && tok.Kind == lexer.Ident && j.shared[tok.Text] && jsAssigned(toks, k) {
+			assigned[tok.Text] = true
+		}
+	}
+
+	for i := range j.result.fixtures {
+		j.result.fixtures[i].Mutable = assigned[j.result.fixtures[i].Name]
+	}
+}
+
+// moduleMocks records mocked modules and module-level mock functions.
+func (j *jsFile) moduleMocks(mocks []types.MockUsage) {
+	for _, m := range mocks {
+		if !strings.HasSuffix(m.API, ".mock") && !strings.HasSuffix(m.API, ".doMock") || m.Target == "" || j.mocks[m.Target] {
+			continue
+		}
+
+		j.mocks[m.Target] = true
+		j.result.fixtures = append(j.result.fixtures, newFixture(j.path, j.lang, m.Target, KindModuleMock, ScopeModule, m.Line))
+	}
+
+	toks := j.toks
+
+	for k := 0; k+3 < len(toks); k++ {
+		if j.depth[k] != 0 || !(toks[k].Is(lexer.Ident, "const") || toks[k].Is(lexer.Ident, "let")) ||
+			toks[k+1].Kind != lexer.Ident || !toks[k+2].Is(lexer.Punct, "=") {
+			continue
+		}
+
+		if name, next := lexer.DottedName(toks, k+3); jsMockFactories[name] && next < len(toks) && toks[next].Is(lexer.Punct, "(") {
+			j.mocks[toks[k+1].Text] = true
+			j.result.fixtures = append(j.result.fixtures,
+				newFixture(j.path, j.lang, toks[k+1].Text, KindModuleMock, ScopeModule, toks[k+1].Line))
+		}
+	}
+}
+
+// hooks records the setup hooks of the file or a describe block as fixtures
+// and returns their names.
+func (j *jsFile) hooks(path []string, hooks []types.TestHook) []string {
+	var names []string
+
+	for _, hook := range hooks {
+		teardown, ok := jsTeardowns[hook.Name]
+		if !ok {
+			continue
+		}
+
+		name := strings.Join(append(append([]string{}, path...), hook.Name), "::")
+		scope := ScopeTest
+
+		switch {
+		case hook.Kind == types.HookBeforeAll && len(path) == 0:
+			scope = ScopeModule
+		case hook.Kind == types.HookBeforeAll:
+			scope = ScopeClass
+		}
+
+		f := newFixture(j.path, j.lang, name, KindHook, scope, hook.Line)
+
+		for _, other := range hooks {
+			f.Teardown = f.Teardown || other.Name == teardown
+		}
+
+		j.result.fixtures = append(j.result.fixtures, f)
+		names = append(names, name)
+
+		if f.Teardown {
+			continue
+		}
+
+		if acquired := jsAcquired(j.hookBody(hook)); len(acquired) > 0 {
+			j.result.findings = append(j.result.findings, newFinding(CheckMissingCleanup, j.path, hook.Line, hook.Line, "",
+				"%s calls %s but has no matching %s", name, strings.Join(acquired, ", "), teardown))
+		}
+	}
+
+	return names
+}
+
+// hookBody returns the tokens of the arguments of a hook call.
+func (j *jsFile) hookBody(hook types.TestHook) []lexer.Token {
+	for k := 0; k+1 < len(j.toks); k++ {
+		if j.toks[k].Line == hook.Line && j.toks[k].Is(lexer.Ident, hook.Name) && j.toks[k+1].Is(lexer.Punct, "(") {
+			return j.toks[k+1 : lexer.Match(j.toks, k+1)+1]
+		}
+	}
+
+	return nil
+}
+
+// tests records the tests among entries with the fixtures they use.
+func (j *jsFile) tests(entries []types.TestCase, hooks []string) {
+	for i := range entries {
+		tc := &entries[i]
+
+		if !tc.IsLeaf() {
+			path := strings.Split(tc.ID, "::")[1:]
+			j.tests(tc.Children, append(append([]string{}, hooks...), j.hooks(path, tc.Hooks)...))
+
+			continue
+		}
+
+		body := lexer.Lines(j.toks, tc.Line, tc.EndLine)
+		use := testUse{TestFixtures: TestFixtures{Test: tc.ID, File: j.path, Line: tc.Line, Language: j.lang}}
+		use.uses = append(use.uses, hooks...)
+
+		var changed []string
+
+		for k, tok := range body {
+			if tok.Kind != lexer.Ident || lexer.AfterDot(body, k) {
+				continue
+			}
+
+			switch {
+			case j.shared[tok.Text]:
+				use.uses = append(use.uses, tok.Text)
+
+				if jsAssigned(body, k) {
+					changed = append(changed, tok.Text)
+				}
+			case j.mocks[tok.Text]:
+				use.uses = append(use.uses, tok.Text)
+			case j.mocks[j.bindings[tok.Text]]:
+				use.uses = append(use.uses, j.bindings[tok.Text])
+			}
+		}
+
+		use.uses = unique(use.uses)
+		j.result.tests = append(j.result.tests, use)
+
+		if jsAssertsCalls(body) {
+			j.callAsserts++
+		}
+
+		if jsTouchesEnv(body) && !j.restoresEnv {
+			changed = append(changed, "process.env")
+		}
+
+		if len(changed) > 0 {
+			j.result.findings = append(j.result.findings, newFinding(CheckGlobalStateMutation, j.path, tc.Line, tc.EndLine, tc.ID,
+				"%s changes %s without restoring it", tc.Name, strings.Join(unique(changed), ", ")))
+		}
+	}
+}
+
+// mockReset reports module-level mocks whose call history several tests
+// assert while nothing in the file clears them.
+func (j *jsFile) mockReset() {
+	if len(j.mocks) == 0 || j.callAsserts < 2 || lexer.HasIdent(j.toks, jsMockResets...) {
+		return
+	}
+
+	var (
+		names []string
+		line  int
+	)
+
+	for _, f := range j.result.fixtures {
+		if f.Kind != KindModuleMock {
+			continue
+		}
+
+		names = append(names, f.Name)
+
+		if line == 0 || f.Line < line {
+			line = f.Line
+		}
+	}
+
+	j.result.findings = append(j.result.findings, newFinding(CheckMockWithoutReset, j.path, line, line, "",
+		"%d tests assert calls on module-level mocks (%s) that are never cleared between tests",
+		j.callAsserts, strings.Join(names, ", ")))
+}
+
+// jsDepths returns the bracket nesting depth before every token.
+func jsDepths(toks []lexer.Token) []int {
+	depths := make([]int, len(toks))
+	depth := 0
+
+	for k, tok := range toks {
+		if tok.Kind == lexer.Punct && (tok.Text == ")" || tok.Text == "]" || tok.Text == "}") && depth > 0 {
+			depth--
+		}
+
+		depths[k] = depth
+
+		if tok.Kind == lexer.Punct && (tok.Text == "(" || tok.Text == "[" || tok.Text == "{") {
+			depth++
+		}
+	}
+
+	return depths
+}
+
+// jsImports maps the names bound by module-level import declarations and
+// require calls onto their module specifiers.
+//
+//nolint:gocognit // Walks both import and require forms
+func jsImports(toks []lexer.Token, depth []int) map[string]string {
+	bindings := make(map[string]string)
+
+	for k := 0; k < len(toks); k++ {
+		if depth[k] != 0 {
+			continue
+		}
+
+		var names []string
+
+		switch {
+		case toks[k].Is(lexer.Ident, "import"):
+			n := k + 1
+			for ; n+1 < len(toks) && !toks[n].Is(lexer.Ident, "from") && !toks[n].Is(lexer.Punct, ";"); n++ {
+				if toks[n].Kind == lexer.Ident && !toks[n+1].Is(lexer.Ident, "as") && toks[n].Text != "as" && toks[n].Text != "type" {
+					names = append(names, toks[n].Text)
+				}
+			}
+
+			if n+1 < len(toks) && toks[n].Is(lexer.Ident, "from") && toks[n+1].Kind == lexer.String {
+				for _, name := range names {
+					bindings[name] = lexer.Unquote(toks[n+1].Text)
+				}
+			}
+
+			k = n
+		case toks[k].Is(lexer.Ident, "const") || toks[k].Is(lexer.Ident, "let") || toks[k].Is(lexer.Ident, "var"):
+			n := k + 1
+			for ; n < len(toks) && !toks[n].Is(lexer.Punct, "=") && !toks[n].Is(lexer.Punct, ";"); n++ {
+				if toks[n].Kind == lexer.Ident && (n+1 == len(toks) || !toks[n+1].Is(lexer.Punct, ":")) {
+					names = append(names, toks[n].Text)
+				}
+			}
+
+			if n+3 < len(toks) && toks[n+1].Is(lexer.Ident, "require") && toks[n+2].Is(lexer.Punct, "(") && toks[n+3].Kind == lexer.String {
+				for _, name := range names {
+					bindings[name] = lexer.Unquote(toks[n+3].Text)
+				}
+			}
+		}
+	}
+
+	return bindings
+}
+
+// jsAssigned reports whether toks[k] is the target of an assignment or an
+// increment.
+func jsAssigned(toks []lexer.Token, k int) bool {
+	if k+1 < len(toks) && (assignedAt(toks, k+1) || toks[k+1].Is(lexer.Punct, "++") || toks[k+1].Is(lexer.Punct, "--")) {
+		return true
+	}
+
+	return k > 0 && (toks[k-1].Is(lexer.Punct, "++") || toks[k-1].Is(lexer.Punct, "--"))
+}
+
+// jsTouchesEnv reports whether toks assign or delete process.env entries.
+func jsTouchesEnv(toks []lexer.Token) bool {
+	for k := 0; k+2 < len(toks); k++ {
+		if !toks[k].Is(lexer.Ident, "process") || !toks[k+1].Is(lexer.Punct, ".") || !toks[k+2].Is(lexer.Ident, "env") {
+			continue
+		}
+
+		if k > 0 && toks[k-1].Is(lexer.Ident, "delete") {
+			return true
+		}
+
+		n := k + 3
+		switch {
+		case n+1 < len(toks) && toks[n].Is(lexer.Punct, ".") && toks[n+1].Kind == lexer.Ident:
+			n += 2
+		case n < len(toks) && toks[n].Is(lexer.Punct, "["):
+			n = lexer.Match(toks, n) + 1
+		}
+
+		if assignedAt(toks, n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// jsAcquired returns the resource calls among toks.
+func jsAcquired(toks []lexer.Token) []string {
+	var acquired []string
+
+	for _, call := range lexer.Calls(toks) {
+		if jsResources[call.Name[strings.LastIndex(call.Name, ".")+1:]] {
+			acquired = append(acquired, call.Name)
+		}
+	}
+
+	if jsTouchesEnv(toks) {
+		acquired = append(acquired, "process.env")
+	}
+
+	return unique(acquired)
+}
+
+// jsAssertsCalls reports whether toks assert the call history of a mock.
+func jsAssertsCalls(toks []lexer.Token) bool {
+	for _, tok := range toks {
+		if tok.Kind == lexer.Ident && (strings.HasPrefix(tok.Text, "toHaveBeenCalled") || strings.HasPrefix(tok.Text, "toBeCalled") ||
+			strings.HasPrefix(tok.Text, "toHaveBeenLastCalled") || strings.HasPrefix(tok.Text, "toHaveBeenNthCalled") ||
+			tok.Text == "lastCalledWith" || tok.Text == "nthCalledWith") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,134 @@
+package fixtures
+
+import (
+	"strings"
+	"testing"
+)
+
+const jsFixtureSource = `import { fetchUser } from './api';
+const { save } = require('./store');
+
+jest.mock('./api');
+
+const onSave = jest.fn();
+let cart;
+let count = 0;
+
+beforeAll(() => {
+  server.listen(4000);
+});
+
+describe('cart', () => {
+  beforeEach(() => {
+    cart = [];
+  });
+
+  it('adds', async () => {
+    await fetchUser(1);
+    cart.push(1);
+    expect(fetchUser).toHaveBeenCalledTimes(1);
+  });
+
+  it('counts', () => {
+    count++;
+    process.env.MODE = 'test';
+    onSave();
+    expect(onSave).toHaveBeenCalled();
+  });
+});
+`
+
+func TestAnalyzeJavaScript(t *testing.T) {
+	result, err := analyzeJavaScript("src/cart.test.ts", []byte(jsFixtureSource))
+	if err != nil {
+		t.Fatalf("analyzeJavaScript() error = %v", err)
+	}
+
+	fixtures := make(map[string]Fixture)
+	for _, f := range result.fixtures {
+		fixtures[f.Name] = f
+	}
+
+	want := map[string]struct {
+		kind  Kind
+		scope Scope
+	}{
+		"./api":            {kind: KindModuleMock, scope: ScopeModule},
+		"onSave":           {kind: KindModuleMock, scope: ScopeModule},
+		"cart":             {kind: KindSharedVariable, scope: ScopeModule},
+		"count":            {kind: KindSharedVariable, scope: ScopeModule},
+		"beforeAll":        {kind: KindHook, scope: ScopeModule},
+		"cart::beforeEach": {kind: KindHook, scope: ScopeTest},
+	}
+
+	if len(fixtures) != len(want) {
+		t.Errorf("fixtures = %+v, want %d", result.fixtures, len(want))
+	}
+
+	for name, w := range want {
+		if f := fixtures[name]; f.Kind != w.kind || f.Scope != w.scope {
+			t.Errorf("fixture %s = %s/%s, want %s/%s", name, f.Kind, f.Scope, w.kind, w.scope)
+		}
+	}
+
+	if !fixtures["cart"].Mutable || !fixtures["count"].Mutable {
+		t.Errorf("shared variables should be mutable: %+v", fixtures)
+	}
+
+	wantFindings := []struct {
+		check string
+		text  string
+	}{
+		{check: CheckMockWithoutReset, text: "2 tests assert calls on module-level mocks (./api, onSave)"},
+		{check: CheckMissingCleanup, text: "beforeAll calls server.listen but has no matching afterAll"},
+		{check: CheckGlobalStateMutation, text: "counts changes count, process.env"},
+	}
+
+	sortFindings(result.findings)
+
+	if len(result.findings) != len(wantFindings) {
+		t.Fatalf("findings = %+v, want %d", result.findings, len(wantFindings))
+	}
+
+	for i, w := range wantFindings {
+		if got := result.findings[i]; got.CheckID != w.check || !strings.Contains(got.Description, w.text) {
+			t.Errorf("findings[%d] = %s %q, want %s containing %q", i, got.CheckID, got.Description, w.check, w.text)
+		}
+	}
+
+	uses := make(map[string][]string)
+	for _, use := range result.tests {
+		uses[use.Test] = use.uses
+	}
+
+	adds := uses["src/cart.test.ts::cart::adds"]
+	for _, name := range []string{"beforeAll", "cart::beforeEach", "./api", "cart"} {
+		if !contains(adds, name) {
+			t.Errorf("adds uses = %v, want %s", adds, name)
+		}
+	}
+}
+
+func TestAnalyzeJavaScriptResetAndRestore(t *testing.T) {
+	src := `import { send } from './mail';
+
+jest.mock('./mail');
+
+afterEach(() => {
+  jest.clearAllMocks();
+  delete process.env.MODE;
+});
+
+test('one', () => { process.env.MODE = 'a'; send(); expect(send).toHaveBeenCalled(); });
+test('two', () => { send(); expect(send).toHaveBeenCalled(); });
+`
+
+	result, err := analyzeJavaScript("mail.test.js", []byte(src))
+	if err != nil {
+		t.Fatalf("analyzeJavaScript() error = %v", err)
+	}
+
+	if len(result.findings) != 0 {
+		t.Errorf("findings = %+v, want none", result.findings)
+	}
+}
@@ -0,0 +1,429 @@
+package fixtures
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// pyScopes maps pytest fixture scopes onto fixture scopes.
+var pyScopes = map[string]Scope{
+	"function": ScopeTest,
+	"class":    ScopeClass,
+	"module":   ScopeModule,
+	"package":  ScopePackage,
+	"session":  ScopeSession,
+}
+
+// pyResources are the calls that create resources or change process state
+// that a fixture must release or restore.
+var pyResources = map[string]bool{
+	"open":                        true,
+	"tempfile.mkdtemp":            true,
+	"tempfile.mkstemp":            true,
+	"tempfile.NamedTemporaryFile": true,
+	"tempfile.TemporaryDirectory": true,
+	"mkdtemp":                     true,
+	"socket.socket":               true,
+	"subprocess.Popen":            true,
+	"sqlite3.connect":             true,
+	"create_engine":               true,
+	"os.chdir":                    true,
+	"os.putenv":                   true,
+}
+
+// pyMutators are the methods that mutate lists, dicts and sets in place.
+var pyMutators = map[string]bool{
+	"append": true, "extend": true, "insert": true, "remove": true, "pop": true,
+	"popitem": true, "clear": true, "update": true, "add": true, "discard": true,
+	"setdefault": true, "sort": true, "reverse": true,
+}
+
+// pyContainers are the constructors of mutable module-level values.
+var pyContainers = map[string]bool{
+	"list": true, "dict": true, "set": true, "defaultdict": true,
+	"OrderedDict": true, "Counter": true, "deque": true,
+}
+
+// pyCounterparts maps setup hooks onto the hook kind that tears them down.
+var pyCounterparts = map[types.HookKind]types.HookKind{
+	types.HookBeforeAll:  types.HookAfterAll,
+	types.HookBeforeEach: types.HookAfterEach,
+}
+
+// pyFile holds the state of the analysis of one Python test file.
+type pyFile struct {
+	path    string
+	toks    []lexer.Token
+	globals map[string]bool
+	result  fileResult
+}
+
+// analyzePython finds the fixtures, tests and fixture problems of a Python
+// test file or conftest.py.
+func analyzePython(relPath string, src []byte) (*fileResult, error) {
+	file, err := inventory.NewPythonParser().Parse(relPath, src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Python file: %w", err)
+	}
+
+	p := &pyFile{path: relPath, toks: lexer.Tokenize(src, lexer.Python), globals: make(map[string]bool)}
+	p.moduleGlobals()
+	p.pytestFixtures()
+
+	hooks := p.hooks(nil, file.Hooks, ScopeModule)
+	p.tests(file.Tests, hooks)
+
+	return &p.result, nil
+}
+
+// moduleGlobals records module-level names bound to mutable containers and
+// marks the ones functions mutate.
+func (p *pyFile) moduleGlobals() {
+	toks := p.toks
+
+	for k := 0; k+3 < len(toks); k++ {
+		tok := toks[k]
+		if tok.Kind != lexer.Ident || tok.Col != 1 || !toks[k+1].Is(lexer.Punct, "=") {
+			continue
+		}
+
+		value := toks[k+2]
+		if !(value.Kind == lexer.Punct && (value.Text == "[" || value.Text == "{")) &&
+			!(value.Kind == lexer.Ident && pyContainers[value.Text] && toks[k+3].Is(lexer.Punct, "(")) {
+			continue
+		}
+
+		if !p.globals[tok.Text] {
+			p.globals[tok.Text] = true
+			p.result.fixtures = append(p.result.fixtures,
+				newFixture(p.path, types.LanguagePython, tok.Text, KindModuleGlobal, ScopeModule, tok.Line))
+		}
+	}
+
+	mutated := make(map[string]bool)
+
+	for _, name := range pyMutations(pyIndented(toks), p.globals, true) {
+		mutated[name] = true
+	}
+
+	for i := range p.result.fixtures {
+		p.result.fixtures[i].Mutable = mutated[p.result.fixtures[i].Name]
+	}
+}
+
+// pytestFixtures records the functions decorated with @pytest.fixture.
+func (p *pyFile) pytestFixtures() {
+	toks := p.toks
+
+	for k := 0; k < len(toks); k++ {
+		if !toks[k].Is(lexer.Punct, "@") {
+			continue
+		}
+
+		name, next := lexer.DottedName(toks, k+1)
+		if name != "pytest.fixture" && name != "fixture" {
+			continue
+		}
+
+		scope, autouse, alias := ScopeTest, false, ""
+
+		if next < len(toks) && toks[next].Is(lexer.Punct, "(") {
+			end := lexer.Match(toks, next)
+
+			for j := next + 1; j+2 <= end; j++ {
+				if toks[j].Kind != lexer.Ident || !toks[j+1].Is(lexer.Punct, "=") {
+					continue
+				}
+
+				switch value := toks[j+2]; toks[j].Text {
+				case "scope":
+					if s, ok := pyScopes[lexer.Unquote(value.Text)]; ok && value.Kind == lexer.String {
+						scope = s
+					}
+				case "autouse":
+					autouse = value.Is(lexer.Ident, "True")
+				case "name":
+					if value.Kind == lexer.String {
+						alias = lexer.Unquote(value.Text)
+					}
+				}
+			}
+
+			next = end + 1
+		}
+
+		def := pyNextDef(toks, next)
+		if def < 0 {
+			continue
+		}
+
+		if alias == "" {
+			alias = toks[def+1].Text
+		}
+
+		body := pyBody(toks, def)
+		f := newFixture(p.path, types.LanguagePython, alias, KindPytestFixture, scope, toks[def].Line)
+		f.Autouse = autouse
+		f.Teardown = lexer.HasIdent(body, "yield", "addfinalizer")
+		p.result.fixtures = append(p.result.fixtures, f)
+
+		if acquired := pyAcquired(body); len(acquired) > 0 && !f.Teardown {
+			p.result.findings = append(p.result.findings, newFinding(CheckMissingCleanup, p.path, f.Line, f.Line, "",
+				"fixture %s calls %s but neither yields nor registers a finalizer", alias, strings.Join(acquired, ", ")))
+		}
+
+		k = def
+	}
+}
+
+// hooks records the setup hooks of a module or test class as fixtures and
+// returns their names.
+func (p *pyFile) hooks(path []string, hooks []types.TestHook, allScope Scope) []string {
+	var names []string
+
+	for _, hook := range hooks {
+		teardown, ok := pyCounterparts[hook.Kind]
+		if !ok || strings.HasPrefix(hook.Name, "fixture:") {
+			continue
+		}
+
+		name := strings.Join(append(append([]string{}, path...), hook.Name), ".")
+		scope := ScopeTest
+
+		if hook.Kind == types.HookBeforeAll {
+			scope = allScope
+		}
+
+		f := newFixture(p.path, types.LanguagePython, name, KindHook, scope, hook.Line)
+
+		for _, other := range hooks {
+			f.Teardown = f.Teardown || other.Kind == teardown
+		}
+
+		p.result.fixtures = append(p.result.fixtures, f)
+		names = append(names, name)
+
+		def := pyDefAt(p.toks, hook.Line, hook.Name)
+		if def < 0 || f.Teardown {
+			continue
+		}
+
+		if acquired := pyAcquired(pyBody(p.toks, def)); len(acquired) > 0 {
+			p.result.findings = append(p.result.findings, newFinding(CheckMissingCleanup, p.path, hook.Line, hook.Line, "",
+				"%s calls %s but has no matching teardown", name, strings.Join(acquired, ", ")))
+		}
+	}
+
+	return names
+}
+
+// tests records the tests among entries with the fixtures they use.
+func (p *pyFile) tests(entries []types.TestCase, hooks []string) {
+	for i := range entries {
+		tc := &entries[i]
+
+		if !tc.IsLeaf() {
+			path := strings.Split(tc.ID, "::")[1:]
+			p.tests(tc.Children, append(append([]string{}, hooks...), p.hooks(path, tc.Hooks, ScopeClass)...))
+
+			continue
+		}
+
+		body := lexer.Lines(p.toks, tc.Line, tc.EndLine)
+		use := testUse{TestFixtures: TestFixtures{Test: tc.ID, File: p.path, Line: tc.Line, Language: types.LanguagePython}}
+		use.uses = append(append(use.uses, tc.Fixtures...), hooks...)
+
+		for _, tok := range body {
+			if tok.Kind == lexer.Ident && p.globals[tok.Text] {
+				use.uses = append(use.uses, tok.Text)
+			}
+		}
+
+		use.uses = unique(use.uses)
+
+		requested := make(map[string]bool)
+		for _, name := range tc.Fixtures {
+			requested[name] = true
+		}
+
+		use.mutates = pyMutations(body, requested, false)
+		p.result.tests = append(p.result.tests, use)
+
+		changed := pyMutations(body, p.globals, true)
+		if env := pyEnvChange(body); env != "" && !requested["monkeypatch"] {
+			changed = append(changed, env)
+		}
+
+		if len(changed) > 0 {
+			p.result.findings = append(p.result.findings, newFinding(CheckGlobalStateMutation, p.path, tc.Line, tc.EndLine, tc.ID,
+				"%s changes %s without restoring it", tc.Name, strings.Join(unique(changed), ", ")))
+		}
+	}
+}
+
+// pyNextDef returns the index of the next def keyword at or after k, or -1
+// when a class or the end of the file comes first.
+func pyNextDef(toks []lexer.Token, k int) int {
+	for ; k+1 < len(toks); k++ {
+		if toks[k].Is(lexer.Ident, "class") {
+			return -1
+		}
+
+		if toks[k].Is(lexer.Ident, "def") && toks[k+1].Kind == lexer.Ident {
+			return k
+		}
+	}
+
+	return -1
+}
+
+// pyDefAt returns the index of the def keyword declaring name on line, or -1.
+func pyDefAt(toks []lexer.Token, line int, name string) int {
+	for k := 0; k+1 < len(toks); k++ {
+		if toks[k].Line == line && toks[k].Is(lexer.Ident, "def") && toks[k+1].Is(lexer.Ident, name) {
+			return k
+		}
+	}
+
+	return -1
+}
+
+// pyBody returns the tokens of the body of the function declared at def:
+// everything after the signature up to the first line indented no deeper
+// than the def keyword.
+func pyBody(toks []lexer.Token, def int) []lexer.Token {
+	k := def + 2
+	if k < len(toks) && toks[k].Is(lexer.Punct, "(") {
+		k = lexer.Match(toks, k) + 1
+	}
+
+	start := k
+
+	for ; k < len(toks); k++ {
+		if toks[k].Line != toks[k-1].Line && toks[k].Line > toks[def].Line && toks[k].Col <= toks[def].Col {
+			break
+		}
+	}
+
+	return toks[start:k]
+}
+
+// pyIndented returns the tokens of lines that start indented, which is
+// every statement inside a function or class.
+func pyIndented(toks []lexer.Token) []lexer.Token {
+	var out []lexer.Token
+
+	indented := false
+
+	for k, tok := range toks {
+		if k == 0 || tok.Line != toks[k-1].Line {
+			indented = tok.Col > 1
+		}
+
+		if indented {
+			out = append(out, tok)
+		}
+	}
+
+	return out
+}
+
+// pyAcquired returns the resource calls among toks that are not managed by
+// a with statement.
+func pyAcquired(toks []lexer.Token) []string {
+	var acquired []string
+
+	for _, call := range lexer.Calls(toks) {
+		if call.Index > 0 && toks[call.Index-1].Is(lexer.Ident, "with") {
+			continue
+		}
+
+		if pyResources[call.Name] || strings.HasSuffix(call.Name, ".connect") {
+			acquired = append(acquired, call.Name)
+		}
+	}
+
+	if env := pyEnvChange(toks); env != "" {
+		acquired = append(acquired, env)
+	}
+
+	return unique(acquired)
+}
+
+// pyEnvChange returns the process state changed directly among toks
+// (os.environ, os.chdir), or "" when there is none.
+func pyEnvChange(toks []lexer.Token) string {
+	for k := 0; k < len(toks); k++ {
+		if toks[k].Kind != lexer.Ident || lexer.AfterDot(toks, k) {
+			continue
+		}
+
+		name, next := lexer.DottedName(toks, k)
+
+		switch {
+		case name == "os.environ" && next < len(toks) && toks[next].Is(lexer.Punct, "["):
+			if assignedAt(toks, lexer.Match(toks, next)+1) {
+				return "os.environ"
+			}
+		case strings.HasPrefix(name, "os.environ.") && pyMutators[strings.TrimPrefix(name, "os.environ.")]:
+			return "os.environ"
+		case (name == "os.chdir" || name == "os.putenv") && next < len(toks) && toks[next].Is(lexer.Punct, "("):
+			return name
+		}
+
+		k = next - 1
+	}
+
+	return ""
+}
+
+// pyMutations returns the names among toks that are mutated in place:
+// mutator method calls, item and attribute assignments, and with rebind set,
+// assignments after a global statement.
+//
+//nolint:gocognit,gocyclo // Pattern matching over the mutation forms
+func pyMutations(toks []lexer.Token, names map[string]bool, rebind bool) []string {
+	var (
+		mutated  []string
+		declared = make(map[string]bool)
+	)
+
+	for k := 0; k < len(toks); k++ {
+		tok := toks[k]
+
+		if rebind && tok.Is(lexer.Ident, "global") {
+			for j := k + 1; j < len(toks) && toks[j].Line == tok.Line; j++ {
+				if toks[j].Kind == lexer.Ident && names[toks[j].Text] {
+					declared[toks[j].Text] = true
+				}
+			}
+
+			continue
+		}
+
+		if tok.Kind != lexer.Ident || !names[tok.Text] || lexer.AfterDot(toks, k) || k+1 >= len(toks) {
+			continue
+		}
+
+		next := toks[k+1]
+
+		switch {
+		case next.Is(lexer.Punct, ".") && k+3 < len(toks) && toks[k+2].Kind == lexer.Ident:
+			if (pyMutators[toks[k+2].Text] && toks[k+3].Is(lexer.Punct, "(")) || assignedAt(toks, k+3) {
+				mutated = append(mutated, tok.Text)
+			}
+		case next.Is(lexer.Punct, "["):
+			if assignedAt(toks, lexer.Match(toks, k+1)+1) {
+				mutated = append(mutated, tok.Text)
+			}
+		case declared[tok.Text] && assignedAt(toks, k+1):
+			mutated = append(mutated, tok.Text)
+		}
+	}
+
+	return unique(mutated)
+}
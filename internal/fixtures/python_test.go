@@ -0,0 +1,162 @@
+package fixtures
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/lexer"
+)
+
+const pyFixtureSource = `import os
+import tempfile
+import unittest
+
+import pytest
+
+REGISTRY = {}
+NAMES = ["a"]
+
+
+@pytest.fixture(scope="session")
+def users():
+    return []
+
+
+@pytest.fixture(autouse=True)
+def clean_env(monkeypatch):
+    monkeypatch.setenv("MODE", "test")
+
+
+@pytest.fixture
+def workdir():
+    path = tempfile.mkdtemp()
+    return path
+
+
+@pytest.fixture(name="db")
+def make_db():
+    conn = connect()
+    yield conn
+    conn.close()
+
+
+def test_adds_user(users, db):
+    users.append("ann")
+    assert NAMES
+
+
+def test_registers():
+    REGISTRY["x"] = 1
+    os.environ["MODE"] = "prod"
+
+
+class TestStore(unittest.TestCase):
+    def setUp(self):
+        self.fh = open("store.db")
+
+    def test_reads(self):
+        assert self.fh
+`
+
+func TestAnalyzePython(t *testing.T) {
+	result, err := analyzePython("tests/test_app.py", []byte(pyFixtureSource))
+	if err != nil {
+		t.Fatalf("analyzePython() error = %v", err)
+	}
+
+	fixtures := make(map[string]Fixture)
+	for _, f := range result.fixtures {
+		fixtures[f.Name] = f
+	}
+
+	want := map[string]struct {
+		kind  Kind
+		scope Scope
+	}{
+		"REGISTRY":        {kind: KindModuleGlobal, scope: ScopeModule},
+		"NAMES":           {kind: KindModuleGlobal, scope: ScopeModule},
+		"users":           {kind: KindPytestFixture, scope: ScopeSession},
+		"clean_env":       {kind: KindPytestFixture, scope: ScopeTest},
+		"workdir":         {kind: KindPytestFixture, scope: ScopeTest},
+		"db":              {kind: KindPytestFixture, scope: ScopeTest},
+		"TestStore.setUp": {kind: KindHook, scope: ScopeTest},
+	}
+
+	if len(fixtures) != len(want) {
+		t.Errorf("fixtures = %+v, want %d", result.fixtures, len(want))
+	}
+
+	for name, w := range want {
+		if f := fixtures[name]; f.Kind != w.kind || f.Scope != w.scope {
+			t.Errorf("fixture %s = %s/%s, want %s/%s", name, f.Kind, f.Scope, w.kind, w.scope)
+		}
+	}
+
+	if !fixtures["clean_env"].Autouse || !fixtures["db"].Teardown || fixtures["workdir"].Teardown {
+		t.Errorf("fixture flags wrong: %+v", fixtures)
+	}
+
+	if !fixtures["REGISTRY"].Mutable || fixtures["NAMES"].Mutable {
+		t.Errorf("REGISTRY mutable = %v, NAMES mutable = %v, want true and false",
+			fixtures["REGISTRY"].Mutable, fixtures["NAMES"].Mutable)
+	}
+
+	wantFindings := []struct {
+		check string
+		text  string
+	}{
+		{check: CheckMissingCleanup, text: "fixture workdir calls tempfile.mkdtemp"},
+		{check: CheckGlobalStateMutation, text: "test_registers changes REGISTRY, os.environ"},
+		{check: CheckMissingCleanup, text: "TestStore.setUp calls open"},
+	}
+
+	if len(result.findings) != len(wantFindings) {
+		t.Fatalf("findings = %+v, want %d", result.findings, len(wantFindings))
+	}
+
+	for i, w := range wantFindings {
+		if got := result.findings[i]; got.CheckID != w.check || !strings.Contains(got.Description, w.text) {
+			t.Errorf("findings[%d] = %s %q, want %s containing %q", i, got.CheckID, got.Description, w.check, w.text)
+		}
+	}
+
+	uses := make(map[string]testUse)
+	for _, use := range result.tests {
+		uses[use.Test] = use
+	}
+
+	adds := uses["tests/test_app.py::test_adds_user"]
+	if !contains(adds.uses, "users") || !contains(adds.uses, "db") || !contains(adds.uses, "NAMES") {
+		t.Errorf("test_adds_user uses = %v", adds.uses)
+	}
+
+	if len(adds.mutates) != 1 || adds.mutates[0] != "users" {
+		t.Errorf("test_adds_user mutates = %v, want [users]", adds.mutates)
+	}
+
+	if reads := uses["tests/test_app.py::TestStore::test_reads"]; !contains(reads.uses, "TestStore.setUp") {
+		t.Errorf("test_reads uses = %v, want the setUp hook", reads.uses)
+	}
+}
+
+func TestPyMutations(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{src: "items.append(1)", want: "items"},
+		{src: "items[0] = 1", want: "items"},
+		{src: "items.size = 1", want: "items"},
+		{src: "items = []", want: ""},
+		{src: "global items\nitems = []", want: "items"},
+		{src: "print(items[0])", want: ""},
+		{src: "other.items.append(1)", want: ""},
+	}
+
+	for _, tt := range tests {
+		got := strings.Join(pyMutations(lexer.Tokenize([]byte(tt.src), lexer.Python), map[string]bool{"items": true}, true), ",")
+		if got != tt.want {
+			t.Errorf("pyMutations(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package fixtures
+
+import "github.com/chambridge/ship-shape/internal/lexer"
+
+// assignedAt reports whether toks[k] is a plain or augmented assignment
+// operator.
+func assignedAt(toks []lexer.Token, k int) bool {
+	if k >= len(toks) || toks[k].Kind != lexer.Punct {
+		return false
+	}
+
+	switch toks[k].Text {
+	case "=", "+=", "-=", "*=", "/=", "|=", "&=", "||=", "&&=", "??=":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,166 @@
+package flakiness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Detector scores the tests of a single test file.
+type Detector interface {
+	// Detect returns the risk of every test of the file at relPath,
+	// including tests without signals.
+	Detect(relPath string, src []byte) ([]TestRisk, error)
+}
+
+// Summary counts tests per risk level.
+type Summary struct {
+	// Tests is the number of scored tests
+	Tests int `json:"tests"`
+
+	// AtRisk is the number of tests with at least one signal
+	AtRisk int `json:"at_risk"`
+
+	// High is the number of high-risk tests
+	High int `json:"high"`
+
+	// Medium is the number of medium-risk tests
+	Medium int `json:"medium"`
+
+	// Low is the number of low-risk tests
+	Low int `json:"low"`
+
+	// Signals counts the tests with each signal
+	Signals map[Signal]int `json:"signals,omitempty"`
+}
+
+// Report is the flakiness risk of every test of a repository.
+type Report struct {
+	// Summary counts tests per risk level and signal
+	Summary Summary `json:"summary"`
+
+	// Tests are all scored tests, from the riskiest to the safest
+	Tests []TestRisk `json:"tests"`
+}
+
+// Top returns the n riskiest tests that have at least one signal, or all
+// of them when n is zero or negative.
+func (r *Report) Top(n int) []TestRisk {
+	risky := r.Tests[:r.Summary.AtRisk]
+	if n > 0 && n < len(risky) {
+		return risky[:n]
+	}
+
+	return risky
+}
+
+// Analyzer scores the tests of a repository.
+type Analyzer struct {
+	walker    *discovery.Walker
+	detectors map[types.Language]Detector
+}
+
+// NewAnalyzer creates an analyzer with detectors for all supported languages.
+func NewAnalyzer(walker *discovery.Walker) *Analyzer {
+	return &Analyzer{
+		walker: walker,
+		detectors: map[types.Language]Detector{
+			types.LanguageGo:         NewGoDetector(walker.Root),
+			types.LanguagePython:     NewPythonDetector(),
+			types.LanguageJavaScript: NewJavaScriptDetector(),
+			types.LanguageTypeScript: NewJavaScriptDetector(),
+		},
+	}
+}
+
+// Analyze walks the repository and scores every test of every test file.
+// Files that cannot be read or parsed are logged and skipped.
+func (a *Analyzer) Analyze() (*Report, error) {
+	var risks []TestRisk
+
+	_, err := a.walker.Walk(func(fi discovery.FileInfo) error {
+		if !discovery.IsTestFile(fi.RelPath) {
+			return nil
+		}
+
+		if _, ok := a.detectors[discovery.LanguageOf(fi.Name)]; !ok {
+			return nil
+		}
+
+		src, err := os.ReadFile(fi.Path) //nolint:gosec // Reading source files from repository
+		if err != nil {
+			logger.Warn("Failed to read test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		fileRisks, err := a.AnalyzeFile(filepath.ToSlash(fi.RelPath), src)
+		if err != nil {
+			logger.Warn("Failed to analyze test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		risks = append(risks, fileRisks...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	return NewReport(risks), nil
+}
+
+// AnalyzeFile scores the tests of a single file. Files in unsupported
+// languages yield no tests.
+func (a *Analyzer) AnalyzeFile(relPath string, src []byte) ([]TestRisk, error) {
+	detector, ok := a.detectors[discovery.LanguageOf(relPath)]
+	if !ok {
+		return nil, nil
+	}
+
+	return detector.Detect(relPath, src)
+}
+
+// NewReport sorts scored tests from the riskiest to the safest, breaking
+// ties by test ID, and summarizes them.
+func NewReport(risks []TestRisk) *Report {
+	if risks == nil {
+		risks = []TestRisk{}
+	}
+
+	sort.SliceStable(risks, func(i, j int) bool {
+		if risks[i].Score != risks[j].Score {
+			return risks[i].Score > risks[j].Score
+		}
+
+		return risks[i].Test < risks[j].Test
+	})
+
+	summary := Summary{Tests: len(risks), Signals: make(map[Signal]int)}
+
+	for _, r := range risks {
+		switch r.Level {
+		case LevelHigh:
+			summary.High++
+		case LevelMedium:
+			summary.Medium++
+		case LevelLow:
+			summary.Low++
+		case LevelNone:
+			continue
+		}
+
+		summary.AtRisk++
+
+		for _, e := range r.Signals {
+			summary.Signals[e.Signal]++
+		}
+	}
+
+	return &Report{Summary: summary, Tests: risks}
+}
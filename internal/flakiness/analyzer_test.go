@@ -0,0 +1,112 @@
+package flakiness
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+const riskyGoTest = `package app
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestRetries(t *testing.T) {
+	time.Sleep(time.Duration(rand.Intn(10)) * time.Millisecond)
+}
+
+func TestAdds(t *testing.T) {
+	if 1+1 != 2 {
+		t.Fatal("math")
+	}
+}
+`
+
+func TestAnalyze(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "app/app.go", "package app\n\nfunc init() { go func() {}() }\n")
+	testutil.WriteFile(t, dir, "app/app_test.go", riskyGoTest)
+	testutil.WriteFile(t, dir, "app/test_app.py", "import time\n\ndef test_waits():\n    time.sleep(1)\n")
+	testutil.WriteFile(t, dir, "app/app.test.js", "test('now', () => { expect(Date.now()).toBeGreaterThan(0); });\n")
+	testutil.WriteFile(t, dir, "app/AppTest.java", "class AppTest { @Test void waits() { Thread.sleep(10); } }")
+
+	report, err := NewAnalyzer(discovery.NewWalker(dir)).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	want := Summary{
+		Tests: 4, AtRisk: 3, Medium: 2, Low: 1,
+		Signals: map[Signal]int{Sleep: 2, Randomness: 1, WallClock: 1},
+	}
+
+	got := report.Summary
+	if got.Tests != want.Tests || got.AtRisk != want.AtRisk || got.High != want.High ||
+		got.Medium != want.Medium || got.Low != want.Low || len(got.Signals) != len(want.Signals) {
+		t.Fatalf("Summary = %+v, want %+v", got, want)
+	}
+
+	for signal, n := range want.Signals {
+		if got.Signals[signal] != n {
+			t.Errorf("Signals[%s] = %d, want %d", signal, got.Signals[signal], n)
+		}
+	}
+
+	order := []string{
+		"app/app_test.go::TestRetries",
+		"app/test_app.py::test_waits",
+		"app/app.test.js::now",
+		"app/app_test.go::TestAdds",
+	}
+
+	for i, id := range order {
+		if report.Tests[i].Test != id {
+			t.Errorf("Tests[%d] = %s (score %d), want %s", i, report.Tests[i].Test, report.Tests[i].Score, id)
+		}
+	}
+}
+
+func TestReportTop(t *testing.T) {
+	report := NewReport([]TestRisk{
+		{Test: "a", Score: 20, Level: LevelLow},
+		{Test: "b", Score: 0, Level: LevelNone},
+		{Test: "c", Score: 60, Level: LevelHigh},
+		{Test: "d", Score: 20, Level: LevelLow},
+	})
+
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{n: 2, want: []string{"c", "a"}},
+		{n: 10, want: []string{"c", "a", "d"}},
+		{n: 0, want: []string{"c", "a", "d"}},
+	}
+
+	for _, tt := range tests {
+		top := report.Top(tt.n)
+
+		if len(top) != len(tt.want) {
+			t.Errorf("Top(%d) returned %d tests, want %d", tt.n, len(top), len(tt.want))
+			continue
+		}
+
+		for i, id := range tt.want {
+			if top[i].Test != id {
+				t.Errorf("Top(%d)[%d] = %s, want %s", tt.n, i, top[i].Test, id)
+			}
+		}
+	}
+}
+
+func TestEmptyReport(t *testing.T) {
+	report := NewReport(nil)
+
+	if report.Tests == nil || len(report.Top(5)) != 0 || report.Summary.Tests != 0 {
+		t.Errorf("NewReport(nil) = %+v, want an empty report", report)
+	}
+}
@@ -0,0 +1,231 @@
+// Package flakiness assigns every test a flakiness risk score from static
+// signals of non-determinism.
+//
+// Each signal of the catalog (sleeps, wall-clock time, unseeded randomness,
+// unsynchronized concurrency, global state, map iteration order, network
+// access, temporary paths without cleanup and parallel tests sharing state)
+// has a weight. The score of a test is the sum of the weights of its distinct
+// signals, capped at 100.
+package flakiness
+
+import (
+	"sort"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Signal identifies a static source of non-determinism.
+type Signal string
+
+// Signal constants.
+const (
+	Sleep               Signal = "sleep"
+	WallClock           Signal = "wall-clock"
+	Randomness          Signal = "randomness"
+	Concurrency         Signal = "concurrency"
+	GlobalState         Signal = "global-state"
+	MapIteration        Signal = "map-iteration"
+	Network             Signal = "network"
+	TempPath            Signal = "temp-path"
+	ParallelSharedState Signal = "parallel-shared-state"
+)
+
+// MaxScore is the highest risk score.
+const MaxScore = 100
+
+// Definition describes a signal of the catalog.
+type Definition struct {
+	// Signal is the catalog identifier
+	Signal Signal
+
+	// Title is the human-readable name
+	Title string
+
+	// Weight is the contribution of the signal to the risk score
+	Weight int
+
+	// Rationale explains how the signal makes tests flaky
+	Rationale string
+
+	// Remediation describes how to remove the signal
+	Remediation string
+}
+
+// Catalog lists every signal with its weight.
+var Catalog = map[Signal]Definition{
+	Sleep: {
+		Signal:      Sleep,
+		Title:       "Sleep",
+		Weight:      30,
+		Rationale:   "Sleeping for a fixed time races against the code under test and fails on slow or loaded machines.",
+		Remediation: "Wait for the event itself with channels, sync.WaitGroup, polling with a deadline or fake timers",
+	},
+	WallClock: {
+		Signal:      WallClock,
+		Title:       "Wall-Clock Time",
+		Weight:      20,
+		Rationale:   "The current time differs on every run and crosses second, day and DST boundaries unpredictably.",
+		Remediation: "Inject a clock or freeze time (fake timers, freezegun) instead of reading the wall clock",
+	},
+	Randomness: {
+		Signal:      Randomness,
+		Title:       "Unseeded Randomness",
+		Weight:      20,
+		Rationale:   "Values from an unseeded random source change on every run, so failures cannot be reproduced.",
+		Remediation: "Use a random source with a fixed seed, or fixed test data",
+	},
+	Concurrency: {
+		Signal:      Concurrency,
+		Title:       "Unsynchronized Concurrency",
+		Weight:      30,
+		Rationale:   "Goroutines and threads the test does not wait for may run after its assertions or after it ends.",
+		Remediation: "Wait for concurrent work with sync.WaitGroup, channels or Thread.join before asserting",
+	},
+	GlobalState: {
+		Signal:      GlobalState,
+		Title:       "Shared Global State",
+		Weight:      20,
+		Rationale:   "Tests that change package variables, environment variables or the working directory affect the tests that run after them.",
+		Remediation: "Use t.Setenv, t.Chdir, monkeypatch or per-test instances, and restore state in cleanup hooks",
+	},
+	MapIteration: {
+		Signal:      MapIteration,
+		Title:       "Ordering-Dependent Iteration",
+		Weight:      15,
+		Rationale:   "Map and set iteration order is unspecified, so results built while iterating change order between runs.",
+		Remediation: "Sort the keys or the results before comparing them",
+	},
+	Network: {
+		Signal:      Network,
+		Title:       "Network Access",
+		Weight:      25,
+		Rationale:   "Real network calls and fixed ports fail when services are slow, unreachable or the port is taken.",
+		Remediation: "Use httptest servers, port 0 and mocked clients instead of real hosts and fixed ports",
+	},
+	TempPath: {
+		Signal:      TempPath,
+		Title:       "Temporary Path Without Cleanup",
+		Weight:      15,
+		Rationale:   "Hard-coded or uncleaned temporary paths collide between runs and between tests running at the same time.",
+		Remediation: "Use t.TempDir, tmp_path or a temporary directory removed in a cleanup hook",
+	},
+	ParallelSharedState: {
+		Signal:      ParallelSharedState,
+		Title:       "Parallel Test With Shared State",
+		Weight:      30,
+		Rationale:   "Tests running in parallel that use shared, mutable fixtures race with each other.",
+		Remediation: "Give each parallel test its own fixtures, or do not run tests that share state in parallel",
+	},
+}
+
+// All returns every signal of the catalog in a stable order.
+func All() []Signal {
+	all := make([]Signal, 0, len(Catalog))
+	for signal := range Catalog {
+		all = append(all, signal)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	return all
+}
+
+// Level buckets risk scores.
+type Level string
+
+// Level constants, from the highest to the lowest risk.
+const (
+	LevelHigh   Level = "high"
+	LevelMedium Level = "medium"
+	LevelLow    Level = "low"
+	LevelNone   Level = "none"
+)
+
+// LevelOf returns the level of a risk score.
+func LevelOf(score int) Level {
+	switch {
+	case score >= 60:
+		return LevelHigh
+	case score >= 30:
+		return LevelMedium
+	case score > 0:
+		return LevelLow
+	}
+
+	return LevelNone
+}
+
+// Evidence is an occurrence of a signal in a test.
+type Evidence struct {
+	// Signal is the detected signal
+	Signal Signal `json:"signal"`
+
+	// Line is the 1-based line of the occurrence
+	Line int `json:"line"`
+
+	// Detail describes the occurrence (e.g., "time.Sleep(time.Second)")
+	Detail string `json:"detail"`
+}
+
+// TestRisk is the flakiness risk of a single test.
+type TestRisk struct {
+	// Test is the ID of the test
+	Test string `json:"test"`
+
+	// Name is the name of the test
+	Name string `json:"name"`
+
+	// File is the test file, relative to the repository root
+	File string `json:"file"`
+
+	// Line is the line where the test is declared
+	Line int `json:"line"`
+
+	// Language is the language of the test file
+	Language types.Language `json:"language"`
+
+	// Score is the risk score between 0 and MaxScore
+	Score int `json:"score"`
+
+	// Level buckets the score
+	Level Level `json:"level"`
+
+	// Signals are the first occurrences of each signal, sorted by line
+	Signals []Evidence `json:"signals,omitempty"`
+}
+
+// add records the first occurrence of a signal in the test.
+func (r *TestRisk) add(signal Signal, line int, detail string) {
+	if !r.Has(signal) {
+		r.Signals = append(r.Signals, Evidence{Signal: signal, Line: line, Detail: detail})
+	}
+}
+
+// score computes the score and level of the test from its signals.
+func (r *TestRisk) score() {
+	sort.SliceStable(r.Signals, func(i, j int) bool { return r.Signals[i].Line < r.Signals[j].Line })
+
+	seen := make(map[Signal]bool)
+	r.Score = 0
+
+	for _, e := range r.Signals {
+		if !seen[e.Signal] {
+			seen[e.Signal] = true
+			r.Score += Catalog[e.Signal].Weight
+		}
+	}
+
+	r.Score = min(r.Score, MaxScore)
+	r.Level = LevelOf(r.Score)
+}
+
+// Has reports whether the test has an occurrence of the signal.
+func (r *TestRisk) Has(signal Signal) bool {
+	for _, e := range r.Signals {
+		if e.Signal == signal {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,66 @@
+package flakiness
+
+import (
+	"testing"
+)
+
+func TestCatalogIsComplete(t *testing.T) {
+	all := All()
+	if len(all) != 9 {
+		t.Fatalf("All() returned %d signals, want 9", len(all))
+	}
+
+	for _, signal := range all {
+		def := Catalog[signal]
+		if def.Signal != signal || def.Title == "" || def.Weight <= 0 || def.Rationale == "" || def.Remediation == "" {
+			t.Errorf("incomplete definition for %s: %+v", signal, def)
+		}
+	}
+}
+
+func TestLevelOf(t *testing.T) {
+	tests := []struct {
+		score int
+		want  Level
+	}{
+		{score: 0, want: LevelNone},
+		{score: 15, want: LevelLow},
+		{score: 29, want: LevelLow},
+		{score: 30, want: LevelMedium},
+		{score: 59, want: LevelMedium},
+		{score: 60, want: LevelHigh},
+		{score: 100, want: LevelHigh},
+	}
+
+	for _, tt := range tests {
+		if got := LevelOf(tt.score); got != tt.want {
+			t.Errorf("LevelOf(%d) = %s, want %s", tt.score, got, tt.want)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	r := TestRisk{}
+	r.add(WallClock, 9, "time.Now")
+	r.add(Sleep, 4, "time.Sleep(time.Second)")
+	r.add(Sleep, 12, "time.Sleep(time.Second)")
+	r.score()
+
+	if r.Score != 50 || r.Level != LevelMedium {
+		t.Errorf("score = %d level %s, want 50 medium", r.Score, r.Level)
+	}
+
+	if len(r.Signals) != 2 || r.Signals[0].Signal != Sleep || r.Signals[0].Line != 4 {
+		t.Errorf("Signals = %+v, want the first sleep then the wall clock", r.Signals)
+	}
+
+	for _, signal := range All() {
+		r.add(signal, 1, "")
+	}
+
+	r.score()
+
+	if r.Score != MaxScore || r.Level != LevelHigh {
+		t.Errorf("score with every signal = %d level %s, want %d high", r.Score, r.Level, MaxScore)
+	}
+}
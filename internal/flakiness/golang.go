@@ -0,0 +1,434 @@
+package flakiness
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/goast"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// goNetworkCalls are the functions of the net and net/http packages that
+// reach the network.
+var goNetworkCalls = map[string][]string{
+	"net":      {"Dial", "DialTimeout", "DialTCP", "DialUDP", "Listen", "ListenPacket", "ListenTCP", "ListenUDP"},
+	"net/http": {"Get", "Head", "Post", "PostForm", "ListenAndServe"},
+}
+
+// goTempCalls are the functions creating temporary files and directories
+// that the test must remove itself.
+var goTempCalls = map[string][]string{
+	"os":        {"MkdirTemp", "CreateTemp"},
+	"io/ioutil": {"TempDir", "TempFile"},
+}
+
+// GoDetector scores Go test functions using the go/ast package. Package
+// variables declared by the other files of a test's package are read from
+// the repository.
+type GoDetector struct {
+	root     string
+	packages map[string]map[string]bool
+}
+
+// NewGoDetector creates a Go flakiness detector reading packages under the
+// repository root. An empty root only considers the variables of the test
+// file itself.
+func NewGoDetector(root string) *GoDetector {
+	return &GoDetector{root: root, packages: make(map[string]map[string]bool)}
+}
+
+// goFile holds the state of the analysis of one Go test file.
+type goFile struct {
+	*goast.File
+
+	globals map[string]bool
+	mutated map[string]bool
+	seeded  bool
+}
+
+// Detect parses a Go test file and scores its top-level test functions.
+func (d *GoDetector) Detect(relPath string, src []byte) ([]TestRisk, error) {
+	file, err := goast.Parse(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &goFile{File: file, globals: make(map[string]bool), mutated: make(map[string]bool)}
+
+	goast.VarNames(g.globals, file.AST)
+
+	for name := range d.packageVars(path.Dir(relPath), file.AST.Name.Name) {
+		g.globals[name] = true
+	}
+
+	var tests []*ast.FuncDecl
+
+	for _, decl := range file.AST.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		for _, w := range g.globalWrites(fn.Body) {
+			g.mutated[w.name] = true
+		}
+
+		for _, call := range goast.Calls(fn.Body) {
+			if _, ok := g.randCall(call, "Seed"); ok && len(call.Args) == 1 {
+				if _, lit := call.Args[0].(*ast.BasicLit); lit {
+					g.seeded = true
+				}
+			}
+		}
+
+		if goast.IsTest(fn) {
+			tests = append(tests, fn)
+		}
+	}
+
+	risks := make([]TestRisk, 0, len(tests))
+
+	for _, fn := range tests {
+		risks = append(risks, g.score(fn))
+	}
+
+	return risks, nil
+}
+
+// packageVars returns the package-level variables of a package of the
+// repository, read once per package.
+func (d *GoDetector) packageVars(dir, pkg string) map[string]bool {
+	if d.root == "" {
+		return nil
+	}
+
+	key := dir + ":" + pkg
+
+	vars, ok := d.packages[key]
+	if !ok {
+		vars = goast.PackageVars(filepath.Join(d.root, filepath.FromSlash(dir)), pkg)
+		d.packages[key] = vars
+	}
+
+	return vars
+}
+
+// score collects the signals of a test function and its subtests.
+func (g *goFile) score(fn *ast.FuncDecl) TestRisk {
+	risk := TestRisk{
+		Test:     g.Path + "::" + fn.Name.Name,
+		Name:     fn.Name.Name,
+		File:     g.Path,
+		Line:     g.Line(fn.Pos()),
+		Language: types.LanguageGo,
+	}
+
+	g.checkCalls(&risk, fn.Body)
+	g.checkConcurrency(&risk, fn.Body)
+	g.checkGlobalState(&risk, fn.Body)
+	g.checkMapIteration(&risk, fn.Body)
+	g.checkParallel(&risk, fn.Body)
+
+	risk.score()
+
+	return risk
+}
+
+// checkCalls reports sleeps, wall-clock reads, unseeded randomness, network
+// access and temporary paths.
+//
+//nolint:gocognit // One case per call-based signal
+func (g *goFile) checkCalls(risk *TestRisk, body *ast.BlockStmt) {
+	removes := false
+
+	for _, call := range goast.Calls(body) {
+		if _, ok := g.PkgCall(call, "os", "Remove", "RemoveAll"); ok {
+			removes = true
+		}
+	}
+
+	for _, call := range goast.Calls(body) {
+		line := g.Line(call.Pos())
+
+		if _, ok := g.PkgCall(call, "time", "Sleep"); ok {
+			risk.add(Sleep, line, g.Text(call))
+		}
+
+		if name, ok := g.PkgCall(call, "time", "Now", "Since", "Until"); ok {
+			risk.add(WallClock, line, "time."+name)
+		}
+
+		if name, ok := g.randCall(call); ok && !g.seeded && !strings.HasPrefix(name, "New") {
+			risk.add(Randomness, line, "global random source rand."+name)
+		}
+
+		for importPath, names := range goNetworkCalls {
+			if _, ok := g.PkgCall(call, importPath, names...); ok {
+				if endpoint, fixed := fixedEndpoint(call); fixed {
+					risk.add(Network, line, g.Text(call.Fun)+" to "+strconv.Quote(endpoint))
+				}
+			}
+		}
+
+		for importPath, names := range goTempCalls {
+			if _, ok := g.PkgCall(call, importPath, names...); ok && !removes {
+				risk.add(TempPath, line, g.Text(call.Fun)+" without os.RemoveAll")
+			}
+		}
+
+		for _, arg := range call.Args {
+			if s, ok := goast.StringLit(arg); ok && (s == "/tmp" || strings.HasPrefix(s, "/tmp/")) {
+				risk.add(TempPath, line, "hard-coded path "+strconv.Quote(s))
+			}
+		}
+	}
+}
+
+// fixedEndpoint returns the literal URL or address with a fixed port a
+// network call targets.
+func fixedEndpoint(call *ast.CallExpr) (string, bool) {
+	for _, arg := range call.Args {
+		s, ok := goast.StringLit(arg)
+		if !ok {
+			continue
+		}
+
+		if strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") {
+			return s, true
+		}
+
+		if i := strings.LastIndex(s, ":"); i >= 0 && s[i+1:] != "" && s[i+1:] != "0" {
+			return s, true
+		}
+	}
+
+	return "", false
+}
+
+// randCall reports whether call invokes one of the named functions (any if
+// none are given) of math/rand or math/rand/v2.
+func (g *goFile) randCall(call *ast.CallExpr, names ...string) (string, bool) {
+	for _, importPath := range []string{"math/rand", "math/rand/v2"} {
+		if name, ok := g.PkgCall(call, importPath, names...); ok {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// checkConcurrency reports goroutines the test does not wait for.
+func (g *goFile) checkConcurrency(risk *TestRisk, body *ast.BlockStmt) {
+	var first *ast.GoStmt
+
+	synchronized := false
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.GoStmt:
+			if first == nil {
+				first = x
+			}
+		case *ast.UnaryExpr:
+			if x.Op == token.ARROW {
+				synchronized = true
+			}
+		case *ast.SelectStmt:
+			synchronized = true
+		case *ast.CallExpr:
+			if sel, ok := x.Fun.(*ast.SelectorExpr); ok {
+				switch sel.Sel.Name {
+				case "Wait", "Eventually", "EventuallyWithT", "Never":
+					synchronized = true
+				}
+			}
+		}
+
+		return true
+	})
+
+	if first != nil && !synchronized {
+		risk.add(Concurrency, g.Line(first.Pos()), "goroutine started without waiting for it")
+	}
+}
+
+// checkGlobalState reports assignments to package variables and changes to
+// the process environment or working directory.
+func (g *goFile) checkGlobalState(risk *TestRisk, body *ast.BlockStmt) {
+	for _, w := range g.globalWrites(body) {
+		risk.add(GlobalState, g.Line(w.node.Pos()), "assigns package variable "+w.name)
+	}
+
+	for _, call := range goast.Calls(body) {
+		if name, ok := g.PkgCall(call, "os", "Setenv", "Unsetenv", "Clearenv", "Chdir"); ok {
+			risk.add(GlobalState, g.Line(call.Pos()), "os."+name)
+		}
+	}
+}
+
+// globalWrite is the first assignment of a function to a package variable.
+type globalWrite struct {
+	name string
+	node ast.Node
+}
+
+// globalWrites returns the package-level variables of the package and the
+// variables of imported packages that body assigns, in source order.
+func (g *goFile) globalWrites(body *ast.BlockStmt) []globalWrite {
+	locals := goast.LocalNames(body)
+	seen := make(map[string]bool)
+
+	var writes []globalWrite
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		var targets []ast.Expr
+
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok != token.DEFINE {
+				targets = x.Lhs
+			}
+		case *ast.IncDecStmt:
+			targets = []ast.Expr{x.X}
+		}
+
+		for _, target := range targets {
+			name := g.StateTarget(target, locals)
+			if (strings.Contains(name, ".") || g.globals[name]) && !seen[name] {
+				seen[name] = true
+				writes = append(writes, globalWrite{name: name, node: n})
+			}
+		}
+
+		return true
+	})
+
+	return writes
+}
+
+// checkMapIteration reports ranging over a map while appending results
+// that are not sorted afterwards.
+func (g *goFile) checkMapIteration(risk *TestRisk, body *ast.BlockStmt) {
+	maps := localMaps(body)
+	sorted := false
+
+	for _, call := range goast.Calls(body) {
+		if _, ok := g.PkgCall(call, "sort"); ok {
+			sorted = true
+		}
+
+		if name, ok := g.PkgCall(call, "slices"); ok && strings.HasPrefix(name, "Sort") {
+			sorted = true
+		}
+	}
+
+	if sorted {
+		return
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		rng, ok := n.(*ast.RangeStmt)
+		if !ok {
+			return true
+		}
+
+		id, ok := rng.X.(*ast.Ident)
+		if !ok || !maps[id.Name] {
+			return true
+		}
+
+		for _, call := range goast.Calls(rng.Body) {
+			if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "append" {
+				risk.add(MapIteration, g.Line(rng.Pos()), "appends while ranging over map "+id.Name)
+				break
+			}
+		}
+
+		return true
+	})
+}
+
+// localMaps collects the variables of body initialized with a map.
+func localMaps(body *ast.BlockStmt) map[string]bool {
+	maps := make(map[string]bool)
+
+	isMap := func(expr ast.Expr) bool {
+		switch x := expr.(type) {
+		case *ast.CompositeLit:
+			_, ok := x.Type.(*ast.MapType)
+			return ok
+		case *ast.CallExpr:
+			if fn, ok := x.Fun.(*ast.Ident); ok && fn.Name == "make" && len(x.Args) > 0 {
+				_, ok := x.Args[0].(*ast.MapType)
+				return ok
+			}
+		}
+
+		return false
+	}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range x.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && i < len(x.Rhs) && isMap(x.Rhs[i]) {
+					maps[id.Name] = true
+				}
+			}
+		case *ast.ValueSpec:
+			_, typed := x.Type.(*ast.MapType)
+			for i, id := range x.Names {
+				if typed || (i < len(x.Values) && isMap(x.Values[i])) {
+					maps[id.Name] = true
+				}
+			}
+		}
+
+		return true
+	})
+
+	return maps
+}
+
+// checkParallel reports parallel tests that use package variables some
+// function of the file assigns, or that change global state themselves.
+func (g *goFile) checkParallel(risk *TestRisk, body *ast.BlockStmt) {
+	var parallel *ast.CallExpr
+
+	for _, call := range goast.Calls(body) {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Parallel" && len(call.Args) == 0 {
+			parallel = call
+			break
+		}
+	}
+
+	if parallel == nil {
+		return
+	}
+
+	line := g.Line(parallel.Pos())
+
+	if risk.Has(GlobalState) {
+		risk.add(ParallelSharedState, line, "t.Parallel in a test that changes global state")
+		return
+	}
+
+	locals := goast.LocalNames(body)
+	shared := ""
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && shared == "" && g.mutated[id.Name] && !locals[id.Name] {
+			shared = id.Name
+		}
+
+		return shared == ""
+	})
+
+	if shared != "" {
+		risk.add(ParallelSharedState, line, "t.Parallel with mutable package variable "+shared)
+	}
+}
@@ -0,0 +1,365 @@
+package flakiness
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+const goTestHeader = `package app
+
+import (
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/app/config"
+)
+
+var cache = map[string]int{}
+
+var hits int
+
+func record() { hits++ }
+`
+
+// goSignals returns the signals of the tests in a Go test body appended
+// to goTestHeader.
+func goSignals(t *testing.T, body string) map[Signal]bool {
+	t.Helper()
+
+	risks, err := NewGoDetector("").Detect("app/app_test.go", []byte(goTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	signals := make(map[Signal]bool)
+
+	for _, r := range risks {
+		for _, e := range r.Signals {
+			signals[e.Signal] = true
+		}
+	}
+
+	return signals
+}
+
+func TestGoDetector(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   Signal
+		absent bool
+	}{
+		{
+			name: "sleep",
+			body: `
+func TestExpiry(t *testing.T) {
+	time.Sleep(10 * time.Millisecond)
+}`,
+			want: Sleep,
+		},
+		{
+			name: "wall clock",
+			body: `
+func TestStamp(t *testing.T) {
+	if time.Since(start) > time.Second {
+		t.Fatal("slow")
+	}
+}`,
+			want: WallClock,
+		},
+		{
+			name: "global random source",
+			body: `
+func TestShuffle(t *testing.T) {
+	_ = rand.Intn(10)
+}`,
+			want: Randomness,
+		},
+		{
+			name: "seeded random source",
+			body: `
+func TestShuffle(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+	_ = r.Intn(10)
+}`,
+			want:   Randomness,
+			absent: true,
+		},
+		{
+			name: "unsynchronized goroutine",
+			body: `
+func TestWorker(t *testing.T) {
+	go record()
+}`,
+			want: Concurrency,
+		},
+		{
+			name: "goroutine with wait group",
+			body: `
+func TestWorker(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() { defer wg.Done(); record() }()
+	wg.Wait()
+}`,
+			want:   Concurrency,
+			absent: true,
+		},
+		{
+			name: "assigns package variable",
+			body: `
+func TestCounter(t *testing.T) {
+	hits = 0
+}`,
+			want: GlobalState,
+		},
+		{
+			name: "assigns imported package variable",
+			body: `
+func TestDebug(t *testing.T) {
+	config.Debug = true
+}`,
+			want: GlobalState,
+		},
+		{
+			name: "sets environment variable",
+			body: `
+func TestEnv(t *testing.T) {
+	os.Setenv("MODE", "test")
+}`,
+			want: GlobalState,
+		},
+		{
+			name: "shadowed package variable",
+			body: `
+func TestCounter(t *testing.T) {
+	hits := 0
+	hits = 1
+	_ = hits
+}`,
+			want:   GlobalState,
+			absent: true,
+		},
+		{
+			name: "appends while ranging over map",
+			body: `
+func TestKeys(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	_ = keys
+}`,
+			want: MapIteration,
+		},
+		{
+			name: "sorted map keys",
+			body: `
+func TestKeys(t *testing.T) {
+	m := make(map[string]int)
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+}`,
+			want:   MapIteration,
+			absent: true,
+		},
+		{
+			name: "fixed port",
+			body: `
+func TestServe(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:8080")
+	defer ln.Close()
+}`,
+			want: Network,
+		},
+		{
+			name: "ephemeral port",
+			body: `
+func TestServe(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer ln.Close()
+}`,
+			want:   Network,
+			absent: true,
+		},
+		{
+			name: "real HTTP request",
+			body: `
+func TestStatus(t *testing.T) {
+	resp, _ := http.Get("https://example.com/health")
+	defer resp.Body.Close()
+}`,
+			want: Network,
+		},
+		{
+			name: "temporary directory without cleanup",
+			body: `
+func TestWrite(t *testing.T) {
+	dir, _ := os.MkdirTemp("", "app")
+	_ = dir
+}`,
+			want: TempPath,
+		},
+		{
+			name: "temporary directory removed",
+			body: `
+func TestWrite(t *testing.T) {
+	dir, _ := os.MkdirTemp("", "app")
+	defer os.RemoveAll(dir)
+}`,
+			want:   TempPath,
+			absent: true,
+		},
+		{
+			name: "hard-coded tmp path",
+			body: `
+func TestWrite(t *testing.T) {
+	_ = os.WriteFile("/tmp/app.txt", nil, 0o600)
+}`,
+			want: TempPath,
+		},
+		{
+			name: "parallel test with mutated package variable",
+			body: `
+func TestCount(t *testing.T) {
+	t.Parallel()
+	record()
+	if hits == 0 {
+		t.Fatal("no hits")
+	}
+}`,
+			want: ParallelSharedState,
+		},
+		{
+			name: "parallel test with read-only package variable",
+			body: `
+func TestCache(t *testing.T) {
+	t.Parallel()
+	if cache["a"] != 0 {
+		t.Fatal("unexpected")
+	}
+}`,
+			want:   ParallelSharedState,
+			absent: true,
+		},
+		{
+			name: "parallel test changing environment",
+			body: `
+func TestEnv(t *testing.T) {
+	t.Parallel()
+	os.Chdir("testdata")
+}`,
+			want: ParallelSharedState,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := goSignals(t, tt.body)
+			if got[tt.want] == tt.absent {
+				t.Errorf("signal %s detected = %v, want %v (signals: %v)", tt.want, got[tt.want], !tt.absent, got)
+			}
+		})
+	}
+}
+
+func TestGoDetectorScoresEveryTest(t *testing.T) {
+	src := goTestHeader + `
+func TestRisky(t *testing.T) {
+	go record()
+	time.Sleep(time.Second)
+	_ = rand.Int()
+}
+
+func TestSafe(t *testing.T) {
+	if 1+1 != 2 {
+		t.Fatal("math")
+	}
+}
+
+func helper(t *testing.T) {
+	time.Sleep(time.Second)
+}
+`
+
+	risks, err := NewGoDetector("").Detect("app/app_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	if len(risks) != 2 {
+		t.Fatalf("Detect() returned %d tests, want 2", len(risks))
+	}
+
+	risky := risks[0]
+	if risky.Test != "app/app_test.go::TestRisky" || risky.Score != 80 || risky.Level != LevelHigh {
+		t.Errorf("risky = %s score %d level %s, want TestRisky score 80 level high", risky.Test, risky.Score, risky.Level)
+	}
+
+	if len(risky.Signals) != 3 || risky.Signals[0].Signal != Concurrency || risky.Signals[1].Line != risky.Signals[0].Line+1 {
+		t.Errorf("risky signals = %+v, want concurrency, sleep and randomness in line order", risky.Signals)
+	}
+
+	if safe := risks[1]; safe.Score != 0 || safe.Level != LevelNone || len(safe.Signals) != 0 {
+		t.Errorf("safe = score %d level %s signals %v, want no risk", safe.Score, safe.Level, safe.Signals)
+	}
+}
+
+func TestGoDetectorSeededGlobalSource(t *testing.T) {
+	got := goSignals(t, `
+func init() { rand.Seed(1) }
+
+func TestShuffle(t *testing.T) {
+	_ = rand.Intn(10)
+}`)
+
+	if got[Randomness] {
+		t.Error("randomness detected for a global source seeded with a constant")
+	}
+}
+
+func TestGoDetectorPackageVariables(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "p/p.go", "package p\n\nvar Registry = map[string]int{}\n")
+	testutil.WriteFile(t, dir, "p/other.go", "package other\n\nvar Local int\n")
+
+	src := `package p
+
+import "testing"
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+	Registry["a"] = 2
+}
+
+func TestLocal(t *testing.T) {
+	Local := 0
+	Local++
+}
+`
+
+	risks, err := NewGoDetector(dir).Detect("p/p_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	register := risks[0]
+	if register.Name != "TestRegister" || !register.Has(GlobalState) || !register.Has(ParallelSharedState) {
+		t.Errorf("TestRegister = %+v, want global and parallel shared state for a variable of p.go", register)
+	}
+
+	if local := risks[1]; local.Has(GlobalState) {
+		t.Errorf("TestLocal = %+v, want no global state for a local", local)
+	}
+}
@@ -0,0 +1,242 @@
+package flakiness
+
+import (
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// jsNetworkModules are the modules whose calls reach the network.
+var jsNetworkModules = []string{"axios", "node-fetch", "got", "http", "https", "net", "ws", "superagent"}
+
+// jsNetworkMocks are the libraries that replace the network in tests.
+var jsNetworkMocks = []string{"msw", "nock", "fetchMock", "fetch-mock", "jest-fetch-mock", "setupServer", "mockFetch"}
+
+// jsNetworkCalls are the calls that reach the network.
+var jsNetworkCalls = map[string]bool{
+	"fetch": true, "axios": true, "axios.get": true, "axios.post": true, "axios.put": true,
+	"axios.patch": true, "axios.delete": true, "axios.request": true, "http.get": true,
+	"http.request": true, "https.get": true, "https.request": true, "net.connect": true,
+	"net.createConnection": true, "got": true, "superagent.get": true, "superagent.post": true,
+}
+
+// jsRandomCalls are the calls that return random values.
+var jsRandomCalls = map[string]bool{
+	"Math.random": true, "crypto.randomUUID": true, "crypto.randomInt": true,
+	"crypto.randomBytes": true, "crypto.getRandomValues": true,
+}
+
+// jsGlobalRoots are the objects whose properties are shared by all tests of
+// a worker.
+var jsGlobalRoots = map[string]bool{"process.env": true, "global": true, "globalThis": true, "window": true}
+
+// JavaScriptDetector scores Jest, Vitest and Mocha tests using the inventory
+// parser and the tokens of each test.
+type JavaScriptDetector struct {
+	parser *inventory.JavaScriptParser
+}
+
+// NewJavaScriptDetector creates a JavaScript and TypeScript flakiness detector.
+func NewJavaScriptDetector() *JavaScriptDetector {
+	return &JavaScriptDetector{parser: inventory.NewJavaScriptParser()}
+}
+
+// jsFile holds the state of the analysis of one JavaScript test file.
+type jsFile struct {
+	tokens []lexer.Token
+	shared map[string]bool
+	fake   bool
+	mocked bool
+	spied  bool
+	clean  bool
+}
+
+// Detect parses a JavaScript or TypeScript test file and scores its tests.
+func (d *JavaScriptDetector) Detect(relPath string, src []byte) ([]TestRisk, error) {
+	file, err := d.parser.Parse(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &jsFile{
+		tokens: lexer.Tokenize(src, lexer.JavaScript),
+		shared: make(map[string]bool),
+	}
+	f.fake = mentions(f.tokens, "useFakeTimers", "setSystemTime", "MockDate", "install")
+	f.mocked = mentions(f.tokens, jsNetworkMocks...)
+	f.clean = mentions(f.tokens, "rm", "rmSync", "rmdir", "rmdirSync", "rimraf", "remove", "removeSync")
+
+	for _, call := range lexer.Calls(f.tokens) {
+		switch {
+		case call.Name == "jest.mock" || call.Name == "vi.mock":
+			if arg := call.Arg(); len(arg) == 1 && arg[0].Kind == lexer.String &&
+				jsNetworkModule(lexer.Unquote(arg[0].Text)) {
+				f.mocked = true
+			}
+		case strings.HasSuffix(call.Name, ".spyOn") && lexer.Join(call.Arg(), "") == "Math":
+			f.spied = true
+		}
+	}
+
+	depth := 0
+
+	for k, tok := range f.tokens {
+		switch {
+		case tok.Kind == lexer.Punct && (tok.Text == "{" || tok.Text == "(" || tok.Text == "["):
+			depth++
+		case tok.Kind == lexer.Punct && (tok.Text == "}" || tok.Text == ")" || tok.Text == "]"):
+			depth--
+		case depth == 0 && (tok.Is(lexer.Ident, "let") || tok.Is(lexer.Ident, "var")) &&
+			k+1 < len(f.tokens) && f.tokens[k+1].Kind == lexer.Ident:
+			f.shared[f.tokens[k+1].Text] = true
+		}
+	}
+
+	lang := discovery.LanguageOf(relPath)
+
+	var risks []TestRisk
+
+	var visit func(tc *types.TestCase, concurrent bool)
+	visit = func(tc *types.TestCase, concurrent bool) {
+		toks := lexer.Lines(f.tokens, tc.Line, tc.EndLine)
+		concurrent = concurrent || f.concurrent(toks)
+
+		if !tc.IsLeaf() {
+			for i := range tc.Children {
+				visit(&tc.Children[i], concurrent)
+			}
+
+			return
+		}
+
+		risk := newRisk(tc, lang)
+		f.check(&risk, toks, concurrent)
+		risk.score()
+		risks = append(risks, risk)
+	}
+
+	for i := range file.Tests {
+		visit(&file.Tests[i], false)
+	}
+
+	return risks, nil
+}
+
+// jsNetworkModule reports whether a module name is a network client.
+func jsNetworkModule(module string) bool {
+	module = strings.TrimPrefix(module, "node:")
+	for _, name := range jsNetworkModules {
+		if module == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// concurrent reports whether the call declaring a test or suite uses the
+// concurrent modifier (test.concurrent, describe.concurrent).
+func (f *jsFile) concurrent(toks []lexer.Token) bool {
+	for k, tok := range toks {
+		if tok.Is(lexer.Punct, "(") {
+			return false
+		}
+
+		if tok.Is(lexer.Ident, "concurrent") && lexer.AfterDot(toks, k) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// check collects the signals of the tokens of a test.
+//
+//nolint:gocognit,gocyclo // One case per source of non-determinism
+func (f *jsFile) check(risk *TestRisk, toks []lexer.Token, concurrent bool) {
+	for _, call := range lexer.Calls(toks) {
+		line := toks[call.Index].Line
+		arg := call.Arg()
+		isNew := call.Index > 0 && toks[call.Index-1].Is(lexer.Ident, "new")
+
+		switch {
+		case (call.Name == "setTimeout" || call.Name == "setInterval") && !f.fake:
+			risk.add(Sleep, line, call.Name+" with real timers")
+		case (call.Name == "sleep" || call.Name == "delay" || strings.HasSuffix(call.Name, ".waitForTimeout") ||
+			call.Name == "cy.wait") && len(arg) == 1 && arg[0].Kind == lexer.Number:
+			risk.add(Sleep, line, call.Name+"("+arg[0].Text+")")
+		case (call.Name == "Date.now" || call.Name == "performance.now" ||
+			call.Name == "Date" && isNew && len(arg) == 0) && !f.fake:
+			detail := call.Name + "()"
+			if isNew {
+				detail = "new " + detail
+			}
+
+			risk.add(WallClock, line, detail)
+		case jsRandomCalls[call.Name] && !f.spied:
+			risk.add(Randomness, line, call.Name)
+		case (jsNetworkCalls[call.Name] || call.Name == "WebSocket" && isNew) && !f.mocked:
+			risk.add(Network, line, call.Name)
+		case strings.HasSuffix(call.Name, ".listen") && len(arg) == 1 && arg[0].Kind == lexer.Number && arg[0].Text != "0":
+			risk.add(Network, line, "listens on fixed port "+arg[0].Text)
+		case call.Name == "process.chdir":
+			risk.add(GlobalState, line, call.Name)
+		case (call.Name == "mkdtemp" || strings.HasSuffix(call.Name, ".mkdtemp") ||
+			call.Name == "mkdtempSync" || strings.HasSuffix(call.Name, ".mkdtempSync")) && !f.clean:
+			risk.add(TempPath, line, call.Name+" without removing it")
+		}
+	}
+
+	for k, tok := range toks {
+		if tok.Kind != lexer.Ident || lexer.AfterDot(toks, k) {
+			continue
+		}
+
+		if name, next := lexer.DottedName(toks, k); jsGlobalWrite(toks, name, next, k) {
+			risk.add(GlobalState, tok.Line, "assigns "+name)
+		}
+
+		if concurrent && f.shared[tok.Text] {
+			risk.add(ParallelSharedState, tok.Line, "concurrent test uses shared variable "+tok.Text)
+		}
+	}
+
+	if concurrent && risk.Has(GlobalState) {
+		risk.add(ParallelSharedState, risk.Line, "concurrent test changes global state")
+	}
+
+	if tok, path, ok := tempLiteral(toks); ok {
+		risk.add(TempPath, tok.Line, "hard-coded path \""+path+"\"")
+	}
+}
+
+// jsGlobalWrite reports whether the member chain name starting at toks[k]
+// and ending before toks[next] is a property of a global object that is
+// assigned or deleted.
+func jsGlobalWrite(toks []lexer.Token, name string, next, k int) bool {
+	root := ""
+
+	for candidate := range jsGlobalRoots {
+		if strings.HasPrefix(name, candidate+".") || name == candidate && next < len(toks) && toks[next].Is(lexer.Punct, "[") {
+			root = candidate
+		}
+	}
+
+	if root == "" {
+		return false
+	}
+
+	if k > 0 && toks[k-1].Is(lexer.Ident, "delete") {
+		return true
+	}
+
+	if next < len(toks) && toks[next].Is(lexer.Punct, "[") {
+		next = lexer.Match(toks, next) + 1
+	}
+
+	return next < len(toks) && toks[next].Kind == lexer.Punct &&
+		(toks[next].Text == "=" || toks[next].Text == "+=" || toks[next].Text == "||=" || toks[next].Text == "??=")
+}
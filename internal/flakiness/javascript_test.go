@@ -0,0 +1,210 @@
+package flakiness
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// jsSignals returns the signals of the tests in a JavaScript test file.
+func jsSignals(t *testing.T, src string) map[Signal]bool {
+	t.Helper()
+
+	risks, err := NewJavaScriptDetector().Detect("src/app.test.js", []byte(src))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	signals := make(map[Signal]bool)
+
+	for _, r := range risks {
+		for _, e := range r.Signals {
+			signals[e.Signal] = true
+		}
+	}
+
+	return signals
+}
+
+func TestJavaScriptDetector(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		want   Signal
+		absent bool
+	}{
+		{
+			name: "real timers",
+			src: `test('expires', async () => {
+  await new Promise((r) => setTimeout(r, 100));
+});`,
+			want: Sleep,
+		},
+		{
+			name: "fake timers",
+			src: `jest.useFakeTimers();
+test('expires', () => {
+  setTimeout(done, 100);
+  jest.runAllTimers();
+});`,
+			want:   Sleep,
+			absent: true,
+		},
+		{
+			name: "fixed wait",
+			src: `test('loads', async ({ page }) => {
+  await page.waitForTimeout(500);
+});`,
+			want: Sleep,
+		},
+		{
+			name: "wall clock",
+			src: `test('stamps', () => {
+  expect(stamp(new Date())).toBe(true);
+});`,
+			want: WallClock,
+		},
+		{
+			name: "date with fixed value",
+			src: `test('stamps', () => {
+  expect(stamp(new Date('2024-01-01'))).toBe(true);
+});`,
+			want:   WallClock,
+			absent: true,
+		},
+		{
+			name: "randomness",
+			src: `test('shuffles', () => {
+  expect(pick(Math.random())).toBeDefined();
+});`,
+			want: Randomness,
+		},
+		{
+			name: "mocked randomness",
+			src: `beforeEach(() => { jest.spyOn(Math, 'random').mockReturnValue(0.5); });
+test('shuffles', () => {
+  expect(pick(Math.random())).toBe(1);
+});`,
+			want:   Randomness,
+			absent: true,
+		},
+		{
+			name: "environment assignment",
+			src: `test('reads mode', () => {
+  process.env.MODE = 'test';
+  expect(mode()).toBe('test');
+});`,
+			want: GlobalState,
+		},
+		{
+			name: "environment read",
+			src: `test('reads mode', () => {
+  expect(process.env.MODE).toBeUndefined();
+});`,
+			want:   GlobalState,
+			absent: true,
+		},
+		{
+			name: "real fetch",
+			src: `test('health', async () => {
+  const res = await fetch('https://example.com/health');
+  expect(res.ok).toBe(true);
+});`,
+			want: Network,
+		},
+		{
+			name: "mocked axios",
+			src: `jest.mock('axios');
+test('health', async () => {
+  await axios.get('/health');
+});`,
+			want:   Network,
+			absent: true,
+		},
+		{
+			name: "fixed port",
+			src: `test('serves', () => {
+  server.listen(3000);
+});`,
+			want: Network,
+		},
+		{
+			name: "temporary directory without cleanup",
+			src: `test('writes', () => {
+  const dir = fs.mkdtempSync('app-');
+  expect(dir).toBeTruthy();
+});`,
+			want: TempPath,
+		},
+		{
+			name: "temporary directory removed after each test",
+			src: `let dir;
+beforeEach(() => { dir = fs.mkdtempSync('app-'); });
+afterEach(() => fs.rmSync(dir, { recursive: true }));
+test('writes', () => {
+  dir = fs.mkdtempSync('app-');
+});`,
+			want:   TempPath,
+			absent: true,
+		},
+		{
+			name: "concurrent test with shared variable",
+			src: `let count = 0;
+test.concurrent('increments', async () => {
+  count++;
+  expect(count).toBe(1);
+});`,
+			want: ParallelSharedState,
+		},
+		{
+			name: "test in concurrent suite with shared variable",
+			src: `let count = 0;
+describe.concurrent('counter', () => {
+  it('increments', async () => {
+    count++;
+  });
+});`,
+			want: ParallelSharedState,
+		},
+		{
+			name: "sequential test with shared variable",
+			src: `let count = 0;
+test('increments', () => {
+  count++;
+  expect(count).toBe(1);
+});`,
+			want:   ParallelSharedState,
+			absent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := jsSignals(t, tt.src)
+			if got[tt.want] == tt.absent {
+				t.Errorf("signal %s detected = %v, want %v (signals: %v)", tt.want, got[tt.want], !tt.absent, got)
+			}
+		})
+	}
+}
+
+func TestJavaScriptDetectorLanguage(t *testing.T) {
+	src := `describe('cart', () => {
+  it('expires', () => {
+    setTimeout(() => {}, 10);
+  });
+});`
+
+	risks, err := NewJavaScriptDetector().Detect("src/cart.test.ts", []byte(src))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	if len(risks) != 1 {
+		t.Fatalf("Detect() returned %d tests, want 1", len(risks))
+	}
+
+	if r := risks[0]; r.Test != "src/cart.test.ts::cart::expires" || r.Language != types.LanguageTypeScript || r.Score != 30 {
+		t.Errorf("risk = %s %s score %d, want cart::expires typescript score 30", r.Test, r.Language, r.Score)
+	}
+}
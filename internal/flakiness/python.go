@@ -0,0 +1,181 @@
+package flakiness
+
+import (
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// pyWallClock are the calls that read the current time.
+var pyWallClock = map[string]bool{
+	"datetime.datetime.now": true, "datetime.datetime.utcnow": true, "datetime.datetime.today": true,
+	"datetime.date.today": true, "time.time": true, "time.time_ns": true,
+}
+
+// pyNetworkModules are the HTTP client modules whose calls reach the network.
+var pyNetworkModules = []string{"requests", "httpx", "aiohttp", "urllib.request", "urllib3", "http.client"}
+
+// pyNetworkMocks are the libraries that replace the network in tests.
+var pyNetworkMocks = []string{"responses", "requests_mock", "respx", "httpretty", "aioresponses", "vcr"}
+
+// pyGlobalCalls are the calls that change process-wide state.
+var pyGlobalCalls = map[string]bool{
+	"os.chdir": true, "os.putenv": true, "os.unsetenv": true, "os.environ.update": true,
+	"os.environ.pop": true, "os.environ.clear": true, "os.environ.setdefault": true,
+}
+
+// PythonDetector scores pytest and unittest tests using the inventory parser
+// and the tokens of each test.
+type PythonDetector struct {
+	parser *inventory.PythonParser
+}
+
+// NewPythonDetector creates a Python flakiness detector.
+func NewPythonDetector() *PythonDetector {
+	return &PythonDetector{parser: inventory.NewPythonParser()}
+}
+
+// pyFile holds the state of the analysis of one Python test file.
+type pyFile struct {
+	tokens  []lexer.Token
+	imports inventory.PythonImports
+	seeded  bool
+	frozen  bool
+	mocked  bool
+}
+
+// Detect parses a Python test file and scores its tests.
+func (d *PythonDetector) Detect(relPath string, src []byte) ([]TestRisk, error) {
+	file, err := d.parser.Parse(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &pyFile{tokens: lexer.Tokenize(src, lexer.Python)}
+	f.imports = inventory.ParsePythonImports(f.tokens)
+	f.frozen = mentions(f.tokens, "freeze_time", "freezegun", "time_machine")
+	f.mocked = mentions(f.tokens, pyNetworkMocks...)
+
+	for _, call := range lexer.Calls(f.tokens) {
+		if resolved := f.imports.Resolve(call.Name); resolved == "random.seed" {
+			f.seeded = true
+		}
+	}
+
+	tests := scoredTests(file)
+	risks := make([]TestRisk, 0, len(tests))
+
+	for _, tc := range tests {
+		risk := newRisk(tc, types.LanguagePython)
+		f.check(&risk, lexer.Lines(f.tokens, tc.Line, tc.EndLine))
+		risk.score()
+		risks = append(risks, risk)
+	}
+
+	return risks, nil
+}
+
+// check collects the signals of the tokens of a test.
+//
+//nolint:gocognit,gocyclo // One case per source of non-determinism
+func (f *pyFile) check(risk *TestRisk, toks []lexer.Token) {
+	joined := mentions(toks, "join")
+	cleaned := mentions(toks, "rmtree", "remove", "unlink", "cleanup")
+
+	for _, call := range lexer.Calls(toks) {
+		resolved := f.imports.Resolve(call.Name)
+		line := toks[call.Index].Line
+
+		switch {
+		case resolved == "time.sleep" || resolved == "asyncio.sleep":
+			if arg := lexer.Join(call.Arg(), ""); arg != "0" && arg != "" {
+				risk.add(Sleep, line, call.Name+"("+arg+")")
+			}
+		case pyWallClock[resolved] && !f.frozen:
+			risk.add(WallClock, line, call.Name)
+		case strings.HasPrefix(resolved, "random.") && !f.seeded && resolved != "random.seed" &&
+			resolved != "random.Random" && resolved != "random.SystemRandom":
+			risk.add(Randomness, line, "global random generator "+call.Name)
+		case (resolved == "threading.Thread" || resolved == "multiprocessing.Process") && !joined:
+			risk.add(Concurrency, line, call.Name+" started without join")
+		case pyGlobalCalls[resolved]:
+			risk.add(GlobalState, line, call.Name)
+		case (resolved == "tempfile.mkdtemp" || resolved == "tempfile.mkstemp") && !cleaned:
+			risk.add(TempPath, line, call.Name+" without removing it")
+		case f.network(resolved):
+			risk.add(Network, line, call.Name)
+		case resolved == "set" && call.Index > 0 && pyIterates(toks, call.Index-1):
+			risk.add(MapIteration, line, "iterates over an unordered set")
+		}
+	}
+
+	for k, tok := range toks {
+		switch {
+		case tok.Is(lexer.Ident, "global") && k+1 < len(toks) && toks[k+1].Kind == lexer.Ident:
+			risk.add(GlobalState, tok.Line, "global "+toks[k+1].Text)
+		case tok.Is(lexer.Ident, "environ") && k >= 2 && lexer.AfterDot(toks, k) && toks[k-2].Is(lexer.Ident, "os") &&
+			k+1 < len(toks) && toks[k+1].Is(lexer.Punct, "[") && pyAssigned(toks, lexer.Match(toks, k+1)+1):
+			risk.add(GlobalState, tok.Line, "assigns os.environ")
+		case tok.Kind == lexer.Ident && !lexer.AfterDot(toks, k) && f.imports[tok.Text] != "":
+			if name, next := lexer.DottedName(toks, k); strings.Contains(name, ".") && pyAssigned(toks, next) {
+				risk.add(GlobalState, tok.Line, "assigns module attribute "+name)
+			}
+		}
+	}
+
+	if tok, path, ok := tempLiteral(toks); ok {
+		risk.add(TempPath, tok.Line, "hard-coded path \""+path+"\"")
+	}
+}
+
+// network reports whether a resolved call reaches the network without a
+// mocking library in the file.
+func (f *pyFile) network(resolved string) bool {
+	if f.mocked {
+		return false
+	}
+
+	if resolved == "socket.socket" || resolved == "socket.create_connection" {
+		return true
+	}
+
+	for _, module := range pyNetworkModules {
+		if strings.HasPrefix(resolved, module+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pyAssigned reports whether toks[k] is a plain or augmented assignment.
+func pyAssigned(toks []lexer.Token, k int) bool {
+	if k >= len(toks) || toks[k].Kind != lexer.Punct {
+		return false
+	}
+
+	switch toks[k].Text {
+	case "=", "+=", "-=", "*=", "/=", "|=":
+		return true
+	}
+
+	return false
+}
+
+// pyIterates reports whether the set(...) call preceded by toks[k] is
+// iterated in order: in a for loop, a comprehension or a list or tuple.
+func pyIterates(toks []lexer.Token, k int) bool {
+	if toks[k].Is(lexer.Ident, "in") {
+		return true
+	}
+
+	if !toks[k].Is(lexer.Punct, "(") || k == 0 {
+		return false
+	}
+
+	prev := toks[k-1]
+
+	return prev.Kind == lexer.Ident && (prev.Text == "list" || prev.Text == "tuple" || prev.Text == "join")
+}
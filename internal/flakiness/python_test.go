@@ -0,0 +1,242 @@
+package flakiness
+
+import (
+	"testing"
+)
+
+// pySignals returns the signals of the tests in a Python test file.
+func pySignals(t *testing.T, src string) map[Signal]bool {
+	t.Helper()
+
+	risks, err := NewPythonDetector().Detect("tests/test_app.py", []byte(src))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	signals := make(map[Signal]bool)
+
+	for _, r := range risks {
+		for _, e := range r.Signals {
+			signals[e.Signal] = true
+		}
+	}
+
+	return signals
+}
+
+func TestPythonDetector(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		want   Signal
+		absent bool
+	}{
+		{
+			name: "sleep",
+			src: `import time
+
+def test_expiry():
+    time.sleep(2)
+`,
+			want: Sleep,
+		},
+		{
+			name: "sleep imported from time",
+			src: `from time import sleep
+
+def test_expiry():
+    sleep(0.5)
+`,
+			want: Sleep,
+		},
+		{
+			name: "wall clock",
+			src: `from datetime import datetime
+
+def test_stamp():
+    assert datetime.now().year > 2000
+`,
+			want: WallClock,
+		},
+		{
+			name: "frozen time",
+			src: `from datetime import datetime
+from freezegun import freeze_time
+
+@freeze_time("2024-01-01")
+def test_stamp():
+    assert datetime.now().year == 2024
+`,
+			want:   WallClock,
+			absent: true,
+		},
+		{
+			name: "unseeded randomness",
+			src: `import random
+
+def test_pick():
+    assert random.choice([1, 2]) in (1, 2)
+`,
+			want: Randomness,
+		},
+		{
+			name: "seeded randomness",
+			src: `import random
+
+random.seed(42)
+
+def test_pick():
+    assert random.choice([1, 2]) in (1, 2)
+`,
+			want:   Randomness,
+			absent: true,
+		},
+		{
+			name: "thread without join",
+			src: `import threading
+
+def test_worker():
+    threading.Thread(target=work).start()
+`,
+			want: Concurrency,
+		},
+		{
+			name: "joined thread",
+			src: `import threading
+
+def test_worker():
+    t = threading.Thread(target=work)
+    t.start()
+    t.join()
+`,
+			want:   Concurrency,
+			absent: true,
+		},
+		{
+			name: "environment assignment",
+			src: `import os
+
+def test_env():
+    os.environ["MODE"] = "test"
+`,
+			want: GlobalState,
+		},
+		{
+			name: "module attribute assignment",
+			src: `from app import settings
+
+def test_debug():
+    settings.DEBUG = True
+`,
+			want: GlobalState,
+		},
+		{
+			name: "monkeypatched environment",
+			src: `def test_env(monkeypatch):
+    monkeypatch.setenv("MODE", "test")
+`,
+			want:   GlobalState,
+			absent: true,
+		},
+		{
+			name: "set iteration",
+			src: `def test_tags():
+    assert list(set(["a", "b"])) == ["a", "b"]
+`,
+			want: MapIteration,
+		},
+		{
+			name: "sorted set",
+			src: `def test_tags():
+    assert sorted(set(["a", "b"])) == ["a", "b"]
+`,
+			want:   MapIteration,
+			absent: true,
+		},
+		{
+			name: "real HTTP request",
+			src: `import requests
+
+def test_health():
+    assert requests.get("https://example.com/health").ok
+`,
+			want: Network,
+		},
+		{
+			name: "mocked HTTP request",
+			src: `import requests
+import responses
+
+@responses.activate
+def test_health():
+    responses.add(responses.GET, "https://example.com/health")
+    assert requests.get("https://example.com/health").ok
+`,
+			want:   Network,
+			absent: true,
+		},
+		{
+			name: "temporary directory without cleanup",
+			src: `import tempfile
+
+def test_write():
+    path = tempfile.mkdtemp()
+`,
+			want: TempPath,
+		},
+		{
+			name: "hard-coded tmp path",
+			src: `def test_write():
+    open("/tmp/out.txt", "w").write("x")
+`,
+			want: TempPath,
+		},
+		{
+			name: "tmp_path fixture",
+			src: `def test_write(tmp_path):
+    (tmp_path / "out.txt").write_text("x")
+`,
+			want:   TempPath,
+			absent: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pySignals(t, tt.src)
+			if got[tt.want] == tt.absent {
+				t.Errorf("signal %s detected = %v, want %v (signals: %v)", tt.want, got[tt.want], !tt.absent, got)
+			}
+		})
+	}
+}
+
+func TestPythonDetectorScoresTestsSeparately(t *testing.T) {
+	src := `import time
+
+class TestCart:
+    def test_expires(self):
+        time.sleep(1)
+
+    @pytest.mark.parametrize("n", [1, 2])
+    def test_total(self, n):
+        assert n > 0
+`
+
+	risks, err := NewPythonDetector().Detect("tests/test_cart.py", []byte(src))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	if len(risks) != 2 {
+		t.Fatalf("Detect() returned %d tests, want 2", len(risks))
+	}
+
+	if risks[0].Test != "tests/test_cart.py::TestCart::test_expires" || risks[0].Score != 30 {
+		t.Errorf("risks[0] = %s score %d, want test_expires score 30", risks[0].Test, risks[0].Score)
+	}
+
+	if risks[1].Score != 0 {
+		t.Errorf("risks[1] = %s score %d, want score 0", risks[1].Test, risks[1].Score)
+	}
+}
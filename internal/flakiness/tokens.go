@@ -0,0 +1,74 @@
+package flakiness
+
+import (
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// mentions reports whether the tokens reference any of the identifiers or
+// module names.
+func mentions(toks []lexer.Token, names ...string) bool {
+	for _, tok := range toks {
+		text := tok.Text
+		if tok.Kind == lexer.String {
+			text = lexer.Unquote(text)
+		} else if tok.Kind != lexer.Ident {
+			continue
+		}
+
+		for _, name := range names {
+			if text == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// tempLiteral returns the first hard-coded /tmp path of the tokens.
+func tempLiteral(toks []lexer.Token) (lexer.Token, string, bool) {
+	for _, tok := range toks {
+		if tok.Kind != lexer.String {
+			continue
+		}
+
+		if s := lexer.Unquote(tok.Text); s == "/tmp" || strings.HasPrefix(s, "/tmp/") {
+			return tok, s, true
+		}
+	}
+
+	return lexer.Token{}, "", false
+}
+
+// scoredTests returns the tests to score in an inventory file: the
+// outermost entries that are not suites, so that parameterized tests are
+// scored once rather than per case.
+func scoredTests(file *types.TestFile) []*types.TestCase {
+	var tests []*types.TestCase
+
+	var visit func(tc *types.TestCase)
+	visit = func(tc *types.TestCase) {
+		if tc.IsLeaf() {
+			tests = append(tests, tc)
+			return
+		}
+
+		for i := range tc.Children {
+			visit(&tc.Children[i])
+		}
+	}
+
+	for i := range file.Tests {
+		visit(&file.Tests[i])
+	}
+
+	return tests
+}
+
+// newRisk starts the risk of an inventory test.
+func newRisk(tc *types.TestCase, lang types.Language) TestRisk {
+	return TestRisk{Test: tc.ID, Name: tc.Name, File: tc.File, Line: tc.Line, Language: lang}
+}
@@ -0,0 +1,266 @@
+// Package gitdiff reads the lines a change adds or modifies from git.
+//
+// Changes are read from a unified diff: the added lines of its hunks,
+// numbered in the new version of each file. A modified line shows as a
+// deleted and an added line; purely deleted lines leave nothing to cover
+// and are not reported. Renames are detected, so a moved file only reports
+// the lines changed while moving it. Untracked files that git does not
+// ignore are new code as well, so every line of them counts as added.
+package gitdiff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Changes maps the files of a change, relative to the directory the diff
+// was taken in, to the sorted numbers of their added or modified lines.
+// Files whose changes only delete lines are absent.
+type Changes map[string][]int
+
+// Files returns the changed files, sorted.
+func (c Changes) Files() []string {
+	files := make([]string, 0, len(c))
+	for f := range c {
+		files = append(files, f)
+	}
+
+	sort.Strings(files)
+
+	return files
+}
+
+// Lines returns the number of changed lines.
+func (c Changes) Lines() int {
+	n := 0
+	for _, lines := range c {
+		n += len(lines)
+	}
+
+	return n
+}
+
+// Diff returns the changes of the working tree of the repository containing
+// dir since it diverged from base: committed, staged and unstaged changes
+// to tracked files relative to the merge base of base and HEAD, and every
+// line of the untracked files git does not ignore. Paths are relative to
+// dir and changes outside dir are left out.
+func Diff(dir, base string) (Changes, error) {
+	mergeBase, err := git(dir, "merge-base", base, "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the merge base of %s and HEAD: %w", base, err)
+	}
+
+	out, err := git(dir, "diff", "--unified=0", "--no-color", "--no-ext-diff", "-M",
+		"--relative", "--src-prefix=a/", "--dst-prefix=b/", strings.TrimSpace(string(mergeBase)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff against %s: %w", base, err)
+	}
+
+	changes, err := Parse(bytes.NewReader(out))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := addUntracked(dir, changes); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// addUntracked adds every line of the untracked, not ignored files under
+// dir to changes. Empty and binary files have no lines to cover.
+func addUntracked(dir string, changes Changes) error {
+	out, err := git(dir, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return fmt.Errorf("failed to list untracked files: %w", err)
+	}
+
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name))) //nolint:gosec // Path listed by git inside dir
+		if err != nil {
+			return fmt.Errorf("failed to read untracked file %s: %w", name, err)
+		}
+
+		if bytes.IndexByte(data, 0) >= 0 {
+			continue
+		}
+
+		lines := bytes.Count(data, []byte("\n"))
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			lines++
+		}
+
+		for n := 1; n <= lines; n++ {
+			changes[name] = append(changes[name], n)
+		}
+	}
+
+	return nil
+}
+
+// git runs a git command in dir, returning its output. Errors carry what
+// git wrote to stderr.
+func git(dir string, args ...string) ([]byte, error) {
+	args = append([]string{"-C", dir, "-c", "core.quotePath=false"}, args...)
+
+	var stderr bytes.Buffer
+
+	cmd := exec.Command("git", args...) //nolint:gosec // Arguments are git options, a revision and a directory
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// Parse reads the changes of a unified diff with "a/" and "b/" prefixes.
+// Hunks are read line by line within the ranges of their header, so diffs
+// with context are read as well as diffs without.
+//
+//nolint:gocognit // One case per kind of diff line
+func Parse(r io.Reader) (Changes, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var (
+		number           int
+		current          string
+		oldLeft, newLeft int
+		next             int
+		changes          = make(Changes)
+	)
+
+	for scanner.Scan() {
+		number++
+
+		line := scanner.Text()
+
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				if current != "" {
+					changes[current] = append(changes[current], next)
+				}
+
+				next++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				next++
+				oldLeft--
+				newLeft--
+			}
+
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			current = ""
+		case strings.HasPrefix(line, "+++ "):
+			current = newPath(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "@@ "):
+			h, err := parseHunk(line)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", number, err)
+			}
+
+			oldLeft, newLeft, next = h.oldCount, h.newCount, h.newStart
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read diff: %w", err)
+	}
+
+	for _, lines := range changes {
+		sort.Ints(lines)
+	}
+
+	return changes, nil
+}
+
+// newPath returns the path of the new version of a file from a "+++" line,
+// or "" for a deleted file.
+func newPath(name string) string {
+	name = strings.TrimSuffix(name, "\t")
+
+	if unquoted, err := strconv.Unquote(name); err == nil {
+		name = unquoted
+	}
+
+	if name == "/dev/null" {
+		return ""
+	}
+
+	return strings.TrimPrefix(name, "b/")
+}
+
+// hunk is the header of a hunk.
+type hunk struct {
+	oldCount, newStart, newCount int
+}
+
+// parseHunk parses a hunk header "@@ -<start>[,<count>] +<start>[,<count>] @@".
+// A missing count means one line.
+func parseHunk(header string) (hunk, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return hunk{}, fmt.Errorf("invalid hunk header %q", header)
+	}
+
+	_, oldCount, err := hunkRange(fields[1][1:])
+	if err != nil {
+		return hunk{}, fmt.Errorf("invalid hunk header %q", header)
+	}
+
+	newStart, newCount, err := hunkRange(fields[2][1:])
+	if err != nil {
+		return hunk{}, fmt.Errorf("invalid hunk header %q", header)
+	}
+
+	return hunk{oldCount: oldCount, newStart: newStart, newCount: newCount}, nil
+}
+
+// hunkRange parses "<start>[,<count>]".
+func hunkRange(s string) (start, count int, err error) {
+	first, second, found := strings.Cut(s, ",")
+
+	if start, err = strconv.Atoi(first); err != nil {
+		return 0, 0, err
+	}
+
+	count = 1
+
+	if found {
+		if count, err = strconv.Atoi(second); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return start, count, nil
+}
@@ -0,0 +1,143 @@
+package gitdiff
+
+import (
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+const sampleDiff = `diff --git a/cart.go b/cart.go
+index 1111111..2222222 100644
+--- a/cart.go
++++ b/cart.go
+@@ -3 +3 @@ func Total() int {
+-	return 0
++	return 1
+@@ -10,0 +11,3 @@ func Total() int {
++func Clear() {
+++++ counter
++}
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package app
+-
+diff --git a/docs/new file.md b/docs/new file.md
+new file mode 100644
+--- /dev/null
++++ b/docs/new file.md	
+@@ -0,0 +1,2 @@
++# Title
++text
+\ No newline at end of file
+diff --git a/ctx.py b/ctx.py
+--- a/ctx.py
++++ b/ctx.py
+@@ -1,4 +1,4 @@
+ def f():
+-    return 1
++    return 2
+ 
+ x = f()
+`
+
+func TestParse(t *testing.T) {
+	changes, err := Parse(strings.NewReader(sampleDiff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := Changes{
+		"cart.go":          {3, 11, 12, 13},
+		"docs/new file.md": {1, 2},
+		"ctx.py":           {2},
+	}
+
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Parse() = %v, want %v", changes, want)
+	}
+
+	if got := changes.Files(); !reflect.DeepEqual(got, []string{"cart.go", "ctx.py", "docs/new file.md"}) {
+		t.Errorf("Files() = %v", got)
+	}
+
+	if got := changes.Lines(); got != 7 {
+		t.Errorf("Lines() = %d, want 7", got)
+	}
+}
+
+func TestParseInvalidHunk(t *testing.T) {
+	_, err := Parse(strings.NewReader("+++ b/a.go\n@@ -1 +x @@\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Parse() error = %v, want invalid hunk error on line 2", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := testutil.TempDir(t)
+
+	run := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	testutil.WriteFile(t, dir, "app/a.go", "package app\n\nfunc A() {}\n")
+	testutil.WriteFile(t, dir, "README.md", "# App\n")
+	testutil.WriteFile(t, dir, "app/old.go", "package app\n\nfunc D() {}\n\nfunc E() {}\n\nfunc F() {}\n")
+	run("init", "-q", "-b", "main")
+	run("add", "-A")
+	run("commit", "-qm", "init")
+	run("checkout", "-qb", "feature")
+
+	testutil.WriteFile(t, dir, "app/a.go", "package app\n\nfunc A() {}\n\nfunc B() {}\n")
+	run("commit", "-qam", "add B")
+
+	// A moved file only contributes the lines changed while moving it.
+	run("mv", "app/old.go", "app/moved.go")
+	testutil.WriteFile(t, dir, "app/moved.go", "package app\n\nfunc D() {}\n\nfunc E() {}\n\nfunc G() {}\n")
+	run("commit", "-qam", "move old.go")
+
+	// Uncommitted changes count too, and every line of new files that are
+	// not added yet, unless git ignores them.
+	testutil.WriteFile(t, dir, "README.md", "# App\n\nUsage\n")
+	testutil.WriteFile(t, dir, ".gitignore", "*.log\n")
+	testutil.WriteFile(t, dir, "app/new.go", "package app\n\nfunc C() {}")
+	testutil.WriteFile(t, dir, "app/empty.go", "")
+	testutil.WriteFile(t, dir, "app/debug.log", "ignored\n")
+
+	changes, err := Diff(dir, "main")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	want := Changes{"app/a.go": {4, 5}, "app/moved.go": {7}, "README.md": {2, 3}, ".gitignore": {1}, "app/new.go": {1, 2, 3}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Diff() = %v, want %v", changes, want)
+	}
+
+	sub, err := Diff(dir+"/app", "main")
+	if err != nil {
+		t.Fatalf("Diff() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(sub, Changes{"a.go": {4, 5}, "moved.go": {7}, "new.go": {1, 2, 3}}) {
+		t.Errorf("Diff(app) = %v, want paths relative to app", sub)
+	}
+
+	if _, err := Diff(dir, "missing"); err == nil || !strings.Contains(err.Error(), "merge base") {
+		t.Errorf("Diff(missing) error = %v, want merge base error", err)
+	}
+}
@@ -0,0 +1,348 @@
+// Package goast provides the go/ast helpers shared by the analyzers of Go
+// test files: parsing with import resolution, recognizing tests and testing
+// parameters, and finding calls, literals and local names.
+package goast
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// File is a parsed Go file with its imports resolved.
+type File struct {
+	// Fset holds the positions of the file
+	Fset *token.FileSet
+
+	// AST is the syntax tree of the file
+	AST *ast.File
+
+	// Path is the path of the file relative to the repository root
+	Path string
+
+	// Imports maps the local names of imports to their import paths
+	Imports map[string]string
+}
+
+// Parse parses a Go file and resolves the local names of its imports.
+func Parse(relPath string, src []byte) (*File, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, relPath, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Go file: %w", err)
+	}
+
+	f := &File{Fset: fset, AST: file, Path: relPath, Imports: make(map[string]string)}
+
+	for _, spec := range file.Imports {
+		importPath, _ := strconv.Unquote(spec.Path.Value)
+		f.Imports[ImportName(spec, importPath)] = importPath
+	}
+
+	return f, nil
+}
+
+// Line returns the line of a position in the file.
+func (f *File) Line(pos token.Pos) int {
+	return f.Fset.Position(pos).Line
+}
+
+// Text returns the source text of a node, or "" if it cannot be printed.
+func (f *File) Text(node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, f.Fset, node); err != nil {
+		return ""
+	}
+
+	return buf.String()
+}
+
+// PkgCall reports whether call invokes one of the named functions of the
+// package imported from importPath, and returns the function name. Without
+// names, any function of the package matches.
+func (f *File) PkgCall(call *ast.CallExpr, importPath string, names ...string) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || f.Imports[pkg.Name] != importPath {
+		return "", false
+	}
+
+	if len(names) == 0 {
+		return sel.Sel.Name, true
+	}
+
+	for _, name := range names {
+		if sel.Sel.Name == name {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// StateTarget names the package-level state an assignment target writes
+// to: a variable that is not among locals, or a variable of an imported
+// package (e.g., "os.Args"). Blank identifiers and fields of locals yield
+// "".
+func (f *File) StateTarget(expr ast.Expr, locals map[string]bool) string {
+	for {
+		switch x := expr.(type) {
+		case *ast.IndexExpr:
+			expr = x.X
+		case *ast.StarExpr:
+			expr = x.X
+		case *ast.ParenExpr:
+			expr = x.X
+		case *ast.Ident:
+			if x.Name == "_" || locals[x.Name] {
+				return ""
+			}
+
+			return x.Name
+		case *ast.SelectorExpr:
+			if pkg, ok := x.X.(*ast.Ident); ok && !locals[pkg.Name] {
+				if _, imported := f.Imports[pkg.Name]; imported {
+					return pkg.Name + "." + x.Sel.Name
+				}
+			}
+
+			expr = x.X
+		default:
+			return ""
+		}
+	}
+}
+
+// VarNames adds the package-level variables declared in a file to names.
+func VarNames(names map[string]bool, file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			for _, id := range spec.(*ast.ValueSpec).Names {
+				if id.Name != "_" {
+					names[id.Name] = true
+				}
+			}
+		}
+	}
+}
+
+// PackageVars returns the package-level variables declared by the Go files
+// of dir that belong to package pkg, test files included. Files that cannot
+// be read or parsed are skipped.
+func PackageVars(dir, pkg string) map[string]bool {
+	names := make(map[string]bool)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return names
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil || file.Name.Name != pkg {
+			continue
+		}
+
+		VarNames(names, file)
+	}
+
+	return names
+}
+
+// ImportName returns the local name of an import: its explicit name, or the
+// last element of its path skipping a major version suffix such as v2.
+func ImportName(spec *ast.ImportSpec, importPath string) string {
+	if spec.Name != nil {
+		return spec.Name.Name
+	}
+
+	base := path.Base(importPath)
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		base = path.Base(path.Dir(importPath))
+	}
+
+	return base
+}
+
+// IsTestName reports whether name is a TestXxx name other than TestMain.
+func IsTestName(name string) bool {
+	if !strings.HasPrefix(name, "Test") || name == "TestMain" {
+		return false
+	}
+
+	return len(name) == 4 || name[4] < 'a' || name[4] > 'z'
+}
+
+// IsTest reports whether fn is a top-level TestXxx(t *testing.T) function.
+func IsTest(fn *ast.FuncDecl) bool {
+	params := fn.Type.Params.List
+	if fn.Recv != nil || !IsTestName(fn.Name.Name) || len(params) != 1 {
+		return false
+	}
+
+	star, ok := params[0].Type.(*ast.StarExpr)
+
+	return ok && isTestingSelector(star.X, "T")
+}
+
+// IsSuiteTest reports whether fn is a TestXxx method without parameters, as
+// run by testify suites.
+func IsSuiteTest(fn *ast.FuncDecl) bool {
+	return fn.Recv != nil && IsTestName(fn.Name.Name) && fn.Type.Params.NumFields() == 0
+}
+
+// IsTestingType matches *testing.T, *testing.B, *testing.F and testing.TB.
+func IsTestingType(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	return isTestingSelector(expr, "T", "B", "F", "TB")
+}
+
+// HasTestingParam reports whether a function takes a parameter of a type
+// matched by IsTestingType.
+func HasTestingParam(ft *ast.FuncType) bool {
+	for _, field := range ft.Params.List {
+		if IsTestingType(field.Type) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isTestingSelector reports whether expr is testing.<name> for one of the
+// names.
+func isTestingSelector(expr ast.Expr, names ...string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "testing" {
+		return false
+	}
+
+	for _, name := range names {
+		if sel.Sel.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReceiverType returns the type name of a method receiver, or "".
+func ReceiverType(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name
+	}
+
+	return ""
+}
+
+// Calls returns every call expression in node in source order.
+func Calls(node ast.Node) []*ast.CallExpr {
+	var result []*ast.CallExpr
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			result = append(result, call)
+		}
+
+		return true
+	})
+
+	return result
+}
+
+// StringLit returns the value of a string literal.
+func StringLit(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+
+	s, err := strconv.Unquote(lit.Value)
+
+	return s, err == nil
+}
+
+// LocalNames collects the identifiers declared inside a function body:
+// variables, range variables and the parameters and results of function
+// literals.
+func LocalNames(body *ast.BlockStmt) map[string]bool {
+	locals := make(map[string]bool)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.AssignStmt:
+			if x.Tok == token.DEFINE {
+				for _, lhs := range x.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						locals[id.Name] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, id := range x.Names {
+				locals[id.Name] = true
+			}
+		case *ast.RangeStmt:
+			if x.Tok == token.DEFINE {
+				for _, expr := range []ast.Expr{x.Key, x.Value} {
+					if id, ok := expr.(*ast.Ident); ok {
+						locals[id.Name] = true
+					}
+				}
+			}
+		case *ast.FuncLit:
+			AddFields(locals, x.Type.Params)
+			AddFields(locals, x.Type.Results)
+		}
+
+		return true
+	})
+
+	return locals
+}
+
+// AddFields adds the names of a parameter, result or receiver list to names.
+func AddFields(names map[string]bool, list *ast.FieldList) {
+	if list == nil {
+		return
+	}
+
+	for _, field := range list.List {
+		for _, id := range field.Names {
+			names[id.Name] = true
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package goast
+
+import (
+	"go/ast"
+	"go/parser"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+const source = `package app
+
+import (
+	"os"
+	yaml "gopkg.in/yaml.v3"
+	"github.com/stretchr/testify/suite"
+	"k8s.io/client-go/v2"
+	"testing"
+)
+
+type AppSuite struct{ suite.Suite }
+
+func TestLoad(t *testing.T) {
+	data, _ := os.ReadFile("app.yaml")
+	for i, line := range lines {
+		var n int
+		check(func(msg string) (ok bool) { return line != msg }, i, n)
+	}
+	_ = yaml.Unmarshal(data, nil)
+}
+
+func (s *AppSuite) TestName() {}
+
+func Testable(t *testing.T) {}
+
+func TestMain(m *testing.M) {}
+
+func helper(tb testing.TB) {}
+`
+
+func parse(t *testing.T) (*File, map[string]*ast.FuncDecl) {
+	t.Helper()
+
+	f, err := Parse("app/app_test.go", []byte(source))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	funcs := make(map[string]*ast.FuncDecl)
+
+	for _, decl := range f.AST.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok {
+			funcs[fn.Name.Name] = fn
+		}
+	}
+
+	return f, funcs
+}
+
+func TestParse(t *testing.T) {
+	f, _ := parse(t)
+
+	want := map[string]string{
+		"os":        "os",
+		"yaml":      "gopkg.in/yaml.v3",
+		"suite":     "github.com/stretchr/testify/suite",
+		"client-go": "k8s.io/client-go/v2",
+		"testing":   "testing",
+	}
+	if !reflect.DeepEqual(f.Imports, want) {
+		t.Errorf("Imports = %v, want %v", f.Imports, want)
+	}
+
+	if _, err := Parse("broken.go", []byte("package app\nfunc {")); err == nil {
+		t.Error("Parse() should fail on invalid source")
+	}
+}
+
+func TestTests(t *testing.T) {
+	_, funcs := parse(t)
+
+	tests := []struct {
+		name                string
+		test, suite, tParam bool
+	}{
+		{"TestLoad", true, false, true},
+		{"TestName", false, true, false},
+		{"Testable", false, false, true},
+		{"TestMain", false, false, false},
+		{"helper", false, false, true},
+	}
+
+	for _, tt := range tests {
+		fn := funcs[tt.name]
+		if IsTest(fn) != tt.test || IsSuiteTest(fn) != tt.suite || HasTestingParam(fn.Type) != tt.tParam {
+			t.Errorf("%s: IsTest = %v, IsSuiteTest = %v, HasTestingParam = %v, want %v, %v, %v", tt.name,
+				IsTest(fn), IsSuiteTest(fn), HasTestingParam(fn.Type), tt.test, tt.suite, tt.tParam)
+		}
+	}
+
+	if got := ReceiverType(funcs["TestName"].Recv.List[0].Type); got != "AppSuite" {
+		t.Errorf("ReceiverType() = %q, want AppSuite", got)
+	}
+}
+
+func TestCallsAndLocals(t *testing.T) {
+	f, funcs := parse(t)
+	body := funcs["TestLoad"].Body
+
+	var pkgCalls []string
+
+	for _, call := range Calls(body) {
+		if name, ok := f.PkgCall(call, "os", "ReadFile", "WriteFile"); ok {
+			pkgCalls = append(pkgCalls, name)
+
+			if path, ok := StringLit(call.Args[0]); !ok || path != "app.yaml" {
+				t.Errorf("StringLit() = %q, %v, want app.yaml", path, ok)
+			}
+		}
+
+		if name, ok := f.PkgCall(call, "gopkg.in/yaml.v3"); ok {
+			pkgCalls = append(pkgCalls, f.Text(call.Fun)+"@"+name)
+		}
+	}
+
+	if want := []string{"ReadFile", "yaml.Unmarshal@Unmarshal"}; !reflect.DeepEqual(pkgCalls, want) {
+		t.Errorf("package calls = %q, want %q", pkgCalls, want)
+	}
+
+	var locals []string
+	for name := range LocalNames(body) {
+		locals = append(locals, name)
+	}
+
+	sort.Strings(locals)
+
+	if want := []string{"_", "data", "i", "line", "msg", "n", "ok"}; !reflect.DeepEqual(locals, want) {
+		t.Errorf("LocalNames() = %q, want %q", locals, want)
+	}
+
+	if line := f.Line(body.Pos()); line != 13 {
+		t.Errorf("Line() = %d, want 13", line)
+	}
+}
+
+func TestStateTarget(t *testing.T) {
+	f, funcs := parse(t)
+	locals := LocalNames(funcs["TestLoad"].Body)
+
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"registry[key].count", "registry"},
+		{"*cache", "cache"},
+		{"os.Args", "os.Args"},
+		{"data[0]", ""},
+		{"_", ""},
+	}
+
+	for _, tt := range tests {
+		expr, err := parser.ParseExpr(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseExpr(%q) error = %v", tt.expr, err)
+		}
+
+		if got := f.StateTarget(expr, locals); got != tt.want {
+			t.Errorf("StateTarget(%s) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestPackageVars(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "app.go", "package app\n\nvar (\n\tRegistry = 1\n\t_ = 2\n)\n")
+	testutil.WriteFile(t, dir, "app_test.go", "package app\n\nvar fixture int\n")
+	testutil.WriteFile(t, dir, "external_test.go", "package app_test\n\nvar other int\n")
+	testutil.WriteFile(t, dir, "broken.go", "package app\nvar {")
+
+	want := map[string]bool{"Registry": true, "fixture": true}
+	if got := PackageVars(dir, "app"); !reflect.DeepEqual(got, want) {
+		t.Errorf("PackageVars() = %v, want %v", got, want)
+	}
+}
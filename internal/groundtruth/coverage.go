@@ -0,0 +1,145 @@
+package groundtruth
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/coverage"
+	"github.com/chambridge/ship-shape/internal/discovery"
+)
+
+// percentTolerance is the accepted difference between expected and parsed
+// percentages, which metadata rounds to one decimal.
+const percentTolerance = 0.05
+
+// coverageReportNames decide whether a file of an example is a coverage
+// report of a format.
+var coverageReportNames = map[coverage.Format]func(name string) bool{
+	coverage.FormatGoCover: func(name string) bool {
+		return name == "coverage.out" || name == "cover.out" || strings.HasSuffix(name, ".coverprofile")
+	},
+	coverage.FormatCobertura: func(name string) bool {
+		return name == "coverage.xml" || name == "cobertura.xml" || strings.HasSuffix(name, ".cobertura.xml")
+	},
+	coverage.FormatLCOV: func(name string) bool {
+		return name == "lcov.info" || strings.HasSuffix(name, ".lcov")
+	},
+	coverage.FormatIstanbul: func(name string) bool {
+		return name == "coverage-final.json"
+	},
+	coverage.FormatJaCoCo: func(name string) bool {
+		return name == "jacoco.xml"
+	},
+}
+
+// CoverageEvaluator scores the coverage parsers on coverage-report examples.
+type CoverageEvaluator struct{}
+
+// NewCoverageEvaluator creates a coverage evaluator.
+func NewCoverageEvaluator() *CoverageEvaluator {
+	return &CoverageEvaluator{}
+}
+
+// Evaluate parses the coverage report of the example and compares every
+// expected metric with the parsed one. A matching metric is a true positive;
+// a differing metric is both a false positive and a false negative. Examples
+// of formats without a parser yield no outcomes.
+func (e *CoverageEvaluator) Evaluate(ex *Example) ([]Outcome, error) {
+	format := coverage.Format(ex.Metadata.CoverageFormat)
+
+	isReport, ok := coverageReportNames[format]
+	if !ok {
+		return nil, nil
+	}
+
+	parser, err := coverageParser(format, ex.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(ex.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read example: %w", err)
+	}
+
+	var reports []string
+
+	for _, entry := range entries {
+		if !entry.IsDir() && isReport(entry.Name()) {
+			reports = append(reports, entry.Name())
+		}
+	}
+
+	if len(reports) != 1 {
+		return nil, fmt.Errorf("expected one %s report, found %d", format, len(reports))
+	}
+
+	f, err := os.Open(filepath.Join(ex.Dir, reports[0])) //nolint:gosec // Reading reports from the ground-truth tree
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage report: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	report, err := parser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", reports[0], err)
+	}
+
+	return []Outcome{compareCoverage(string(format), ex.Metadata.ExpectedCoverage, report)}, nil
+}
+
+// coverageParser creates the parser of a format for an example directory.
+func coverageParser(format coverage.Format, dir string) (coverage.Parser, error) {
+	workspaces, err := discovery.NewWorkspaceDetector(dir, discovery.NewWalker(dir)).Detect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect workspaces: %w", err)
+	}
+
+	return coverage.NewParser(format, dir, "", workspaces)
+}
+
+// compareCoverage matches the expected metrics with a parsed report.
+func compareCoverage(key string, want *Coverage, report *coverage.Report) Outcome {
+	o := Outcome{Key: key}
+	if want == nil {
+		return o
+	}
+
+	m := report.Metrics
+
+	check := func(name string, matches bool, got, expected any) {
+		if matches {
+			o.TruePositives++
+			return
+		}
+
+		o.FalsePositives++
+		o.FalseNegatives++
+		o.Mismatches = append(o.Mismatches, fmt.Sprintf("%s %s = %v, want %v", key, name, got, expected))
+	}
+
+	percent := func(name string, want *float64, c coverage.Counter) {
+		if want != nil {
+			got := math.Round(c.Percent()*10) / 10
+			check(name, math.Abs(c.Percent()-*want) <= percentTolerance, got, *want)
+		}
+	}
+
+	count := func(name string, want *int, got int) {
+		if want != nil {
+			check(name, got == *want, got, *want)
+		}
+	}
+
+	percent("line_coverage", want.LineCoverage, m.Lines)
+	percent("branch_coverage", want.BranchCoverage, m.Branches)
+	percent("function_coverage", want.FunctionCoverage, m.Functions)
+	count("files_covered", want.FilesCovered, len(report.Files))
+	count("lines_total", want.LinesTotal, m.Lines.Total)
+	count("lines_covered", want.LinesCovered, m.Lines.Covered)
+
+	return o
+}
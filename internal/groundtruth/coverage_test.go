@@ -0,0 +1,49 @@
+package groundtruth
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+func TestCoverageEvaluator(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "go.mod", "module example.com/app\n\ngo 1.21\n")
+	testutil.WriteFile(t, dir, "app.go", "package app\n\nfunc One() int {\n\treturn 1\n}\n\nfunc Two() int {\n\treturn 2\n}\n")
+	testutil.WriteFile(t, dir, "coverage.out", "mode: set\nexample.com/app/app.go:3.16,5.2 1 1\nexample.com/app/app.go:7.16,9.2 1 0\n")
+
+	line, function := 50.0, 100.0
+	files, total := 1, 6
+
+	ex := &Example{Dir: dir, Metadata: Metadata{
+		Category:       CategoryCoverageReport,
+		CoverageFormat: "go-cover",
+		ExpectedCoverage: &Coverage{
+			LineCoverage:     &line,
+			FunctionCoverage: &function,
+			FilesCovered:     &files,
+			LinesTotal:       &total,
+		},
+	}}
+
+	outcomes, err := NewCoverageEvaluator().Evaluate(ex)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if len(outcomes) != 1 {
+		t.Fatalf("outcomes = %+v, want one", outcomes)
+	}
+
+	o := outcomes[0]
+	if o.Key != "go-cover" || o.TruePositives != 3 || o.FalsePositives != 1 || o.FalseNegatives != 1 ||
+		len(o.Mismatches) != 1 || o.Mismatches[0] != "go-cover function_coverage = 50, want 100" {
+		t.Errorf("outcome = %+v", o)
+	}
+
+	ex.Metadata.CoverageFormat = "clover-xml"
+
+	if outcomes, err := NewCoverageEvaluator().Evaluate(ex); err != nil || outcomes != nil {
+		t.Errorf("Evaluate(clover-xml) = %+v, %v, want no outcomes", outcomes, err)
+	}
+}
@@ -0,0 +1,297 @@
+// Package groundtruth measures detector accuracy against the curated
+// examples under testdata/ground-truth.
+package groundtruth
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultTarget is the precision and recall every smell, pattern and
+// coverage format must reach.
+const DefaultTarget = 0.90
+
+// Targets are the minimum precision and recall of each score.
+type Targets struct {
+	// Precision is the minimum share of reported findings that are expected
+	Precision float64 `json:"precision"`
+
+	// Recall is the minimum share of expected findings that are reported
+	Recall float64 `json:"recall"`
+}
+
+// DefaultTargets returns the targets of the ground-truth README.
+func DefaultTargets() Targets {
+	return Targets{Precision: DefaultTarget, Recall: DefaultTarget}
+}
+
+// Outcome is the result of evaluating one smell, pattern or coverage format
+// of an example.
+type Outcome struct {
+	// Key is the smell, pattern or coverage format
+	Key string
+
+	// TruePositives are expected results that were reported
+	TruePositives int
+
+	// FalsePositives are reported results that were not expected
+	FalsePositives int
+
+	// FalseNegatives are expected results that were not reported
+	FalseNegatives int
+
+	// Mismatches describe each false positive and false negative
+	Mismatches []string
+}
+
+// Evaluator runs the detector or parser of a category over an example and
+// compares its results with the example's metadata.
+type Evaluator interface {
+	// Evaluate returns one outcome per smell, pattern or format the
+	// example is annotated for.
+	Evaluate(ex *Example) ([]Outcome, error)
+}
+
+// Score aggregates the outcomes of a smell, pattern or coverage format over
+// all examples.
+type Score struct {
+	// Category is the category of the examples
+	Category string `json:"category"`
+
+	// Key is the smell, pattern or coverage format
+	Key string `json:"key"`
+
+	// Examples is the number of examples contributing to the score
+	Examples int `json:"examples"`
+
+	// TruePositives are expected results that were reported
+	TruePositives int `json:"true_positives"`
+
+	// FalsePositives are reported results that were not expected
+	FalsePositives int `json:"false_positives"`
+
+	// FalseNegatives are expected results that were not reported
+	FalseNegatives int `json:"false_negatives"`
+
+	// Precision is TP / (TP + FP), or 1 when nothing was reported
+	Precision float64 `json:"precision"`
+
+	// Recall is TP / (TP + FN), or 1 when nothing was expected
+	Recall float64 `json:"recall"`
+
+	// F1 is the harmonic mean of precision and recall
+	F1 float64 `json:"f1"`
+
+	// Passed reports whether precision and recall reach the targets
+	Passed bool `json:"passed"`
+}
+
+// Report is the result of a harness run.
+type Report struct {
+	// Root is the ground-truth directory
+	Root string `json:"root"`
+
+	// Examples is the number of metadata.yml files found
+	Examples int `json:"examples"`
+
+	// Targets are the precision and recall targets
+	Targets Targets `json:"targets"`
+
+	// Scores are sorted by category and key
+	Scores []Score `json:"scores"`
+
+	// Problems are schema violations and evaluation errors
+	Problems []string `json:"problems,omitempty"`
+
+	// Skipped are examples of categories without an evaluator
+	Skipped []string `json:"skipped,omitempty"`
+
+	// Mismatches describe every false positive and false negative
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+// Passed reports whether every example is valid and every score reaches the
+// targets.
+func (r *Report) Passed() bool {
+	if len(r.Problems) > 0 {
+		return false
+	}
+
+	for _, s := range r.Scores {
+		if !s.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Harness walks a ground-truth tree and scores the evaluators.
+type Harness struct {
+	root       string
+	schema     *Schema
+	targets    Targets
+	evaluators map[string]Evaluator
+}
+
+// NewHarness creates a harness with evaluators for every supported category.
+func NewHarness(root string, schema *Schema, targets Targets) *Harness {
+	return &Harness{
+		root:    root,
+		schema:  schema,
+		targets: targets,
+		evaluators: map[string]Evaluator{
+			CategoryTestSmell:      NewSmellEvaluator(),
+			CategoryTestPattern:    NewPatternEvaluator(),
+			CategoryCoverageReport: NewCoverageEvaluator(),
+		},
+	}
+}
+
+// Register sets the evaluator of a category.
+func (h *Harness) Register(category string, evaluator Evaluator) {
+	h.evaluators[category] = evaluator
+}
+
+// Run validates and evaluates every example under the root. Invalid
+// examples are reported as problems and not evaluated.
+func (h *Harness) Run() (*Report, error) {
+	report := &Report{Root: h.root, Targets: h.targets, Scores: []Score{}}
+	scores := make(map[[2]string]*Score)
+
+	err := filepath.WalkDir(h.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || d.Name() != MetadataFile {
+			return nil
+		}
+
+		report.Examples++
+
+		ex, problems := h.load(path)
+		if len(problems) > 0 {
+			report.Problems = append(report.Problems, problems...)
+			return nil
+		}
+
+		evaluator, ok := h.evaluators[ex.Metadata.Category]
+		if !ok {
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%s (%s)", ex.RelDir, ex.Metadata.Category))
+			return nil
+		}
+
+		outcomes, err := evaluator.Evaluate(ex)
+		if err != nil {
+			report.Problems = append(report.Problems, fmt.Sprintf("%s: %v", ex.RelDir, err))
+			return nil
+		}
+
+		for _, o := range outcomes {
+			key := [2]string{ex.Metadata.Category, o.Key}
+
+			s, ok := scores[key]
+			if !ok {
+				s = &Score{Category: key[0], Key: key[1]}
+				scores[key] = s
+			}
+
+			s.Examples++
+			s.TruePositives += o.TruePositives
+			s.FalsePositives += o.FalsePositives
+			s.FalseNegatives += o.FalseNegatives
+
+			for _, m := range o.Mismatches {
+				report.Mismatches = append(report.Mismatches, fmt.Sprintf("%s: %s", ex.RelDir, m))
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk ground truth: %w", err)
+	}
+
+	for _, s := range scores {
+		s.score(h.targets)
+		report.Scores = append(report.Scores, *s)
+	}
+
+	sort.Slice(report.Scores, func(i, j int) bool {
+		if report.Scores[i].Category != report.Scores[j].Category {
+			return report.Scores[i].Category < report.Scores[j].Category
+		}
+
+		return report.Scores[i].Key < report.Scores[j].Key
+	})
+
+	return report, nil
+}
+
+// load reads and validates the metadata of an example.
+func (h *Harness) load(path string) (*Example, []string) {
+	dir := filepath.Dir(path)
+
+	rel, err := filepath.Rel(h.root, dir)
+	if err != nil {
+		rel = dir
+	}
+
+	rel = filepath.ToSlash(rel)
+	name := rel + "/" + MetadataFile
+
+	data, err := os.ReadFile(path) //nolint:gosec // Reading metadata from the ground-truth tree
+	if err != nil {
+		return nil, []string{fmt.Sprintf("%s: %v", name, err)}
+	}
+
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, []string{fmt.Sprintf("%s: invalid YAML: %v", name, err)}
+	}
+
+	if h.schema != nil {
+		var problems []string
+		for _, p := range h.schema.Validate(doc) {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, p))
+		}
+
+		if len(problems) > 0 {
+			return nil, problems
+		}
+	}
+
+	ex := &Example{Dir: dir, RelDir: rel}
+	if err := yaml.Unmarshal(data, &ex.Metadata); err != nil {
+		return nil, []string{fmt.Sprintf("%s: %v", name, err)}
+	}
+
+	return ex, nil
+}
+
+// score computes the metrics of s and whether they reach the targets.
+func (s *Score) score(targets Targets) {
+	s.Precision = ratio(s.TruePositives, s.TruePositives+s.FalsePositives)
+	s.Recall = ratio(s.TruePositives, s.TruePositives+s.FalseNegatives)
+
+	if s.Precision+s.Recall > 0 {
+		s.F1 = 2 * s.Precision * s.Recall / (s.Precision + s.Recall)
+	}
+
+	s.Passed = s.Precision >= targets.Precision && s.Recall >= targets.Recall
+}
+
+// ratio returns n/d, or 1 when d is zero.
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 1
+	}
+
+	return float64(n) / float64(d)
+}
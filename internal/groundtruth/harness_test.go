@@ -0,0 +1,139 @@
+package groundtruth
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+// groundTruthRoot is the repository's ground-truth tree.
+var groundTruthRoot = filepath.Join("..", "..", "testdata", "ground-truth")
+
+func TestGroundTruthMeetsTargets(t *testing.T) {
+	schema, err := LoadSchema(filepath.Join(groundTruthRoot, SchemaFile))
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	report, err := NewHarness(groundTruthRoot, schema, DefaultTargets()).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Examples == 0 {
+		t.Fatal("no ground-truth examples found")
+	}
+
+	for _, p := range report.Problems {
+		t.Errorf("problem: %s", p)
+	}
+
+	for _, s := range report.Scores {
+		if !s.Passed {
+			t.Errorf("%s %s: precision %.2f, recall %.2f", s.Category, s.Key, s.Precision, s.Recall)
+		}
+	}
+
+	if t.Failed() {
+		for _, m := range report.Mismatches {
+			t.Log(m)
+		}
+	}
+}
+
+func TestHarnessScoresOutcomes(t *testing.T) {
+	root := testutil.TempDir(t)
+
+	testutil.WriteFile(t, root, "sleepy/metadata.yml", `version: "1.0.0"
+language: go
+category: test-smell
+smell_type: flakiness
+description: "Sleeps"
+verified_by: ["a@example.com", "b@example.com"]
+verified_date: "2026-10-18"
+expected_detections:
+  - type: test-smell
+    smell: flakiness
+    file: "app_test.go"
+    function: "TestWaits"
+    reason: "sleeps"
+  - type: test-smell
+    smell: flakiness
+    file: "app_test.go"
+    function: "TestMissing"
+    reason: "not in the file"
+`)
+	testutil.WriteFile(t, root, "sleepy/app_test.go", `package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaits(t *testing.T) {
+	time.Sleep(time.Second)
+}
+
+func TestAlsoWaits(t *testing.T) {
+	time.Sleep(time.Second)
+}
+`)
+	testutil.WriteFile(t, root, "e2e/metadata.yml", `version: "1.0.0"
+language: go
+category: integration
+description: "End to end"
+verified_by: ["a@example.com", "b@example.com"]
+verified_date: "2026-10-18"
+`)
+
+	report, err := NewHarness(root, nil, DefaultTargets()).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Examples != 2 || len(report.Skipped) != 1 {
+		t.Errorf("examples = %d, skipped = %v", report.Examples, report.Skipped)
+	}
+
+	if len(report.Scores) != 1 {
+		t.Fatalf("scores = %+v, want one", report.Scores)
+	}
+
+	s := report.Scores[0]
+	if s.Key != "flakiness" || s.TruePositives != 1 || s.FalsePositives != 1 || s.FalseNegatives != 1 {
+		t.Errorf("score = %+v", s)
+	}
+
+	if s.Precision != 0.5 || s.Recall != 0.5 || s.F1 != 0.5 || s.Passed || report.Passed() {
+		t.Errorf("metrics = %.2f/%.2f/%.2f, passed = %v", s.Precision, s.Recall, s.F1, s.Passed)
+	}
+
+	if len(report.Mismatches) != 2 || !strings.Contains(report.Mismatches[0], "missed flakiness") {
+		t.Errorf("mismatches = %v", report.Mismatches)
+	}
+}
+
+func TestHarnessReportsInvalidMetadata(t *testing.T) {
+	root := testutil.TempDir(t)
+	testutil.WriteFile(t, root, "bad/metadata.yml", "version: \"1.0.0\"\ncategory: test-smell\n")
+
+	schema, err := LoadSchema(filepath.Join(groundTruthRoot, SchemaFile))
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	report, err := NewHarness(root, schema, DefaultTargets()).Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Passed() || len(report.Problems) == 0 || len(report.Scores) != 0 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	if !strings.HasPrefix(report.Problems[0], "bad/metadata.yml: ") {
+		t.Errorf("problem = %q", report.Problems[0])
+	}
+}
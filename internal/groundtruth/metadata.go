@@ -0,0 +1,126 @@
+package groundtruth
+
+// MetadataFile is the name of the file describing a ground-truth example.
+const MetadataFile = "metadata.yml"
+
+// Categories of ground-truth examples.
+const (
+	CategoryTestSmell      = "test-smell"
+	CategoryCoverageReport = "coverage-report"
+	CategoryTestPattern    = "test-pattern"
+	CategoryIntegration    = "integration"
+)
+
+// Metadata is the decoded metadata.yml of an example.
+type Metadata struct {
+	// Version is the schema version
+	Version string `yaml:"version"`
+
+	// Language is the language of the example
+	Language string `yaml:"language"`
+
+	// Category is test-smell, coverage-report, test-pattern or integration
+	Category string `yaml:"category"`
+
+	// Description explains what the example demonstrates
+	Description string `yaml:"description"`
+
+	// SmellType is the smell a test-smell example demonstrates
+	SmellType string `yaml:"smell_type"`
+
+	// CoverageFormat is the format of a coverage-report example
+	CoverageFormat string `yaml:"coverage_format"`
+
+	// PatternType is the pattern a test-pattern example demonstrates
+	PatternType string `yaml:"pattern_type"`
+
+	// ExpectedDetections are the findings the detectors must report
+	ExpectedDetections []Detection `yaml:"expected_detections"`
+
+	// ExpectedCoverage are the metrics a coverage parser must report
+	ExpectedCoverage *Coverage `yaml:"expected_coverage"`
+
+	// FalsePositivesExpected are findings accepted as false positives
+	FalsePositivesExpected []AcceptedFalsePositive `yaml:"false_positives_expected"`
+}
+
+// Detection is an expected finding.
+type Detection struct {
+	// Type is test-smell, pattern, best-practice or anti-pattern
+	Type string `yaml:"type"`
+
+	// Smell is the expected smell of a test-smell detection
+	Smell string `yaml:"smell"`
+
+	// Pattern is the expected pattern of other detections
+	Pattern string `yaml:"pattern"`
+
+	// File is the file of the finding, relative to the example
+	File string `yaml:"file"`
+
+	// Line is a line the finding must cover, or 0 for any line
+	Line int `yaml:"line"`
+
+	// Function is the test the finding must belong to, if any
+	Function string `yaml:"function"`
+
+	// Severity is the expected severity
+	Severity string `yaml:"severity"`
+
+	// Reason explains why the finding is expected
+	Reason string `yaml:"reason"`
+}
+
+// Key returns the smell or pattern the detection is about.
+func (d Detection) Key() string {
+	if d.Type == CategoryTestSmell {
+		return d.Smell
+	}
+
+	return d.Pattern
+}
+
+// Coverage are expected coverage metrics. Nil metrics are not checked.
+type Coverage struct {
+	// LineCoverage is the line coverage percentage
+	LineCoverage *float64 `yaml:"line_coverage"`
+
+	// BranchCoverage is the branch coverage percentage
+	BranchCoverage *float64 `yaml:"branch_coverage"`
+
+	// FunctionCoverage is the function coverage percentage
+	FunctionCoverage *float64 `yaml:"function_coverage"`
+
+	// FilesCovered is the number of files in the report
+	FilesCovered *int `yaml:"files_covered"`
+
+	// LinesTotal is the number of executable lines
+	LinesTotal *int `yaml:"lines_total"`
+
+	// LinesCovered is the number of covered lines
+	LinesCovered *int `yaml:"lines_covered"`
+}
+
+// AcceptedFalsePositive is a finding that is reported but not expected.
+type AcceptedFalsePositive struct {
+	// Type is the smell or pattern of the finding
+	Type string `yaml:"type"`
+
+	// Reason explains why the finding is accepted
+	Reason string `yaml:"reason"`
+
+	// AcceptedBy is who accepted the finding
+	AcceptedBy string `yaml:"accepted_by"`
+}
+
+// Example is a ground-truth example directory.
+type Example struct {
+	// Dir is the directory of the example
+	Dir string
+
+	// RelDir is the directory relative to the ground-truth root
+	RelDir string
+
+	// Metadata is the decoded metadata.yml
+	Metadata Metadata
+}
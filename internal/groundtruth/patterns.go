@@ -0,0 +1,142 @@
+package groundtruth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/patterns"
+)
+
+// PatternEvaluator scores the pattern analyzer on test-pattern examples.
+type PatternEvaluator struct{}
+
+// NewPatternEvaluator creates a pattern evaluator.
+func NewPatternEvaluator() *PatternEvaluator {
+	return &PatternEvaluator{}
+}
+
+// Evaluate collects the test inventory of the example and matches the tests
+// that follow a pattern with the expected pattern and best-practice
+// detections. Only the patterns of the catalog the example is annotated for
+// (its pattern_type and the patterns of its expected detections) are scored;
+// other annotated patterns have no detector yet and are ignored.
+func (e *PatternEvaluator) Evaluate(ex *Example) ([]Outcome, error) {
+	inv, err := inventory.NewCollector(discovery.NewWalker(ex.Dir)).Collect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect test inventory: %w", err)
+	}
+
+	annotated := make(map[patterns.Pattern]bool)
+	if _, ok := patterns.Catalog[patterns.Pattern(ex.Metadata.PatternType)]; ok {
+		annotated[patterns.Pattern(ex.Metadata.PatternType)] = true
+	}
+
+	for _, d := range ex.Metadata.ExpectedDetections {
+		if _, ok := patterns.Catalog[patterns.Pattern(d.Pattern)]; ok && isPatternDetection(d) {
+			annotated[patterns.Pattern(d.Pattern)] = true
+		}
+	}
+
+	keys := make([]patterns.Pattern, 0, len(annotated))
+	for k := range annotated {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	occurrences := patterns.Occurrences(inv)
+
+	outcomes := make([]Outcome, 0, len(keys))
+	for _, pattern := range keys {
+		outcomes = append(outcomes, evaluatePattern(pattern, ex.Metadata, occurrences))
+	}
+
+	return outcomes, nil
+}
+
+// evaluatePattern matches the occurrences of one pattern with its expected
+// detections.
+func evaluatePattern(pattern patterns.Pattern, meta Metadata, occurrences []patterns.Occurrence) Outcome {
+	o := Outcome{Key: string(pattern)}
+
+	var reported []patterns.Occurrence
+
+	for _, occ := range occurrences {
+		if occ.Pattern == pattern {
+			reported = append(reported, occ)
+		}
+	}
+
+	matched := make([]bool, len(reported))
+
+	for _, d := range meta.ExpectedDetections {
+		if !isPatternDetection(d) || d.Pattern != string(pattern) {
+			continue
+		}
+
+		found := false
+
+		for i, occ := range reported {
+			if !matched[i] && follows(occ, d) {
+				matched[i], found = true, true
+				break
+			}
+		}
+
+		if found {
+			o.TruePositives++
+		} else {
+			o.FalseNegatives++
+			o.Mismatches = append(o.Mismatches, fmt.Sprintf("missed %s in %s", pattern, describe(d)))
+		}
+	}
+
+	accepted := 0
+
+	for _, fp := range meta.FalsePositivesExpected {
+		if fp.Type == string(pattern) {
+			accepted++
+		}
+	}
+
+	for i, occ := range reported {
+		if matched[i] {
+			continue
+		}
+
+		if accepted > 0 {
+			accepted--
+			continue
+		}
+
+		o.FalsePositives++
+		o.Mismatches = append(o.Mismatches, fmt.Sprintf("unexpected %s at %s:%d (%s)", pattern, occ.File, occ.Line, occ.Name))
+	}
+
+	return o
+}
+
+// isPatternDetection reports whether an expected detection is a followed pattern.
+func isPatternDetection(d Detection) bool {
+	return d.Type == "pattern" || d.Type == "best-practice"
+}
+
+// follows reports whether an occurrence satisfies an expected detection.
+func follows(occ patterns.Occurrence, d Detection) bool {
+	if occ.File != d.File {
+		return false
+	}
+
+	if d.Line > 0 && d.Line != occ.Line {
+		return false
+	}
+
+	if d.Function != "" {
+		return occ.Name == d.Function || strings.HasSuffix(occ.Test, "::"+d.Function)
+	}
+
+	return true
+}
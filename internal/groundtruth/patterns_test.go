@@ -0,0 +1,59 @@
+package groundtruth
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+func TestPatternEvaluator(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "app_test.go", `package app
+
+import "testing"
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct{ name string }{{"a"}} {
+		t.Run(tt.name, func(t *testing.T) {})
+	}
+}
+
+func TestOther(t *testing.T) {
+	t.Parallel()
+}
+`)
+
+	ex := &Example{Dir: dir, Metadata: Metadata{
+		Category:    CategoryTestPattern,
+		PatternType: "table-driven",
+		ExpectedDetections: []Detection{
+			{Type: "pattern", Pattern: "table-driven", File: "app_test.go", Function: "TestTable", Line: 5},
+			{Type: "pattern", Pattern: "table-driven", File: "app_test.go", Function: "TestMissing"},
+			{Type: "best-practice", Pattern: "parallel", File: "app_test.go", Function: "TestTable"},
+			{Type: "best-practice", Pattern: "test-helper-usage", File: "app_test.go", Function: "helper"},
+		},
+		FalsePositivesExpected: []AcceptedFalsePositive{{Type: "parallel", Reason: "not annotated"}},
+	}}
+
+	outcomes, err := NewPatternEvaluator().Evaluate(ex)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if len(outcomes) != 2 {
+		t.Fatalf("outcomes = %+v, want parallel and table-driven", outcomes)
+	}
+
+	parallel, table := outcomes[0], outcomes[1]
+
+	if parallel.Key != "parallel" || parallel.TruePositives != 1 || parallel.FalsePositives != 0 || parallel.FalseNegatives != 0 {
+		t.Errorf("parallel = %+v", parallel)
+	}
+
+	if table.Key != "table-driven" || table.TruePositives != 1 || table.FalseNegatives != 1 ||
+		len(table.Mismatches) != 1 || table.Mismatches[0] != "missed table-driven in app_test.go (TestMissing)" {
+		t.Errorf("table-driven = %+v", table)
+	}
+}
@@ -0,0 +1,294 @@
+package groundtruth
+
+import (
+	"fmt"
+	"net/mail"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaFile is the name of the metadata schema at the ground-truth root.
+const SchemaFile = "metadata.schema.yml"
+
+// Field describes a metadata field in the schema.
+type Field struct {
+	// Type is string, integer, number, boolean, array or object
+	Type string `yaml:"type"`
+
+	// Required marks fields that must always be present
+	Required bool `yaml:"required"`
+
+	// RequiredWhen is a condition on sibling fields, such as
+	// `category == "test-smell"` or `type in ["pattern", "anti-pattern"]`
+	RequiredWhen string `yaml:"required_when"`
+
+	// Enum lists the allowed values
+	Enum []string `yaml:"enum"`
+
+	// Format is email, date or percentage
+	Format string `yaml:"format"`
+
+	// MinItems is the minimum length of an array
+	MinItems int `yaml:"min_items"`
+
+	// Items describes the elements of an array
+	Items *Field `yaml:"items"`
+
+	// RequiredFields lists the fields an object element must have
+	RequiredFields []string `yaml:"required_fields"`
+
+	// Fields describes the fields of an object
+	Fields map[string]*Field `yaml:"fields"`
+}
+
+// Schema describes the fields of a metadata.yml file. Fields that are not in
+// the schema are allowed.
+type Schema struct {
+	// Fields are the top-level fields
+	Fields map[string]*Field
+}
+
+var (
+	equalsCondition = regexp.MustCompile(`^(\w+)\s*==\s*"([^"]*)"$`)
+	inCondition     = regexp.MustCompile(`^(\w+)\s+in\s+\[(.*)\]$`)
+)
+
+// LoadSchema reads a metadata schema.
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Schema path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	return ParseSchema(data)
+}
+
+// ParseSchema parses a metadata schema.
+func ParseSchema(data []byte) (*Schema, error) {
+	var fields map[string]*Field
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("failed to parse schema: %w", err)
+	}
+
+	for name, f := range fields {
+		if err := checkField(name, f); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Schema{Fields: fields}, nil
+}
+
+// checkField rejects conditions the validator cannot evaluate.
+func checkField(name string, f *Field) error {
+	if f == nil {
+		return fmt.Errorf("schema field %s has no definition", name)
+	}
+
+	if f.RequiredWhen != "" {
+		if _, _, ok := parseCondition(f.RequiredWhen); !ok {
+			return fmt.Errorf("schema field %s has an unsupported condition %q", name, f.RequiredWhen)
+		}
+	}
+
+	if f.Items != nil {
+		if err := checkField(name+"[]", f.Items); err != nil {
+			return err
+		}
+	}
+
+	for child, cf := range f.Fields {
+		if err := checkField(name+"."+child, cf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate checks a decoded metadata document and returns one message per
+// violation, sorted by field.
+func (s *Schema) Validate(doc map[string]any) []string {
+	problems := validateObject("", doc, s.Fields, nil)
+	sort.Strings(problems)
+
+	return problems
+}
+
+// validateObject checks the fields of an object against their definitions.
+func validateObject(prefix string, obj map[string]any, fields map[string]*Field, requiredFields []string) []string {
+	var problems []string
+
+	for _, name := range requiredFields {
+		if _, ok := obj[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s%s is required", prefix, name))
+		}
+	}
+
+	for name, f := range fields {
+		value, ok := obj[name]
+		if !ok || value == nil {
+			if requiredByField(f, obj) && !contains(requiredFields, name) {
+				problems = append(problems, fmt.Sprintf("%s%s is required", prefix, name))
+			}
+
+			continue
+		}
+
+		problems = append(problems, validateValue(prefix+name, value, f)...)
+	}
+
+	return problems
+}
+
+// requiredByField reports whether a missing field is required in obj.
+func requiredByField(f *Field, obj map[string]any) bool {
+	if f.Required {
+		return true
+	}
+
+	if f.RequiredWhen == "" {
+		return false
+	}
+
+	field, values, _ := parseCondition(f.RequiredWhen)
+	actual, ok := obj[field].(string)
+
+	return ok && contains(values, actual)
+}
+
+// parseCondition splits a required_when condition into the field it tests
+// and the values that make the field required.
+func parseCondition(cond string) (string, []string, bool) {
+	cond = strings.TrimSpace(cond)
+
+	if m := equalsCondition.FindStringSubmatch(cond); m != nil {
+		return m[1], []string{m[2]}, true
+	}
+
+	if m := inCondition.FindStringSubmatch(cond); m != nil {
+		var values []string
+
+		for _, v := range strings.Split(m[2], ",") {
+			v = strings.TrimSpace(v)
+			if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+				return "", nil, false
+			}
+
+			values = append(values, v[1:len(v)-1])
+		}
+
+		return m[1], values, true
+	}
+
+	return "", nil, false
+}
+
+// validateValue checks a present value against its definition.
+//
+//nolint:gocognit,gocyclo // One case per schema type
+func validateValue(path string, value any, f *Field) []string {
+	var problems []string
+
+	switch f.Type {
+	case "string":
+		if t, ok := value.(time.Time); ok && f.Format == "date" {
+			value = t.Format(time.DateOnly)
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a string", path)}
+		}
+
+		if len(f.Enum) > 0 && !contains(f.Enum, str) {
+			problems = append(problems, fmt.Sprintf("%s must be one of %s, got %q", path, strings.Join(f.Enum, ", "), str))
+		}
+
+		problems = append(problems, validateFormat(path, str, f.Format)...)
+	case "integer":
+		if _, ok := value.(int); !ok {
+			return []string{fmt.Sprintf("%s must be an integer", path)}
+		}
+	case "number":
+		n, ok := toFloat(value)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a number", path)}
+		}
+
+		if f.Format == "percentage" && (n < 0 || n > 100) {
+			problems = append(problems, fmt.Sprintf("%s must be a percentage between 0 and 100", path))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []string{fmt.Sprintf("%s must be a boolean", path)}
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a list", path)}
+		}
+
+		if len(items) < f.MinItems {
+			problems = append(problems, fmt.Sprintf("%s must have at least %d items", path, f.MinItems))
+		}
+
+		if f.Items != nil {
+			for i, item := range items {
+				problems = append(problems, validateValue(fmt.Sprintf("%s[%d]", path, i), item, f.Items)...)
+			}
+		}
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a mapping", path)}
+		}
+
+		problems = append(problems, validateObject(path+".", obj, f.Fields, f.RequiredFields)...)
+	}
+
+	return problems
+}
+
+// validateFormat checks the format of a string value.
+func validateFormat(path, value, format string) []string {
+	switch format {
+	case "email":
+		if addr, err := mail.ParseAddress(value); err != nil || addr.Address != value {
+			return []string{fmt.Sprintf("%s must be an email address, got %q", path, value)}
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return []string{fmt.Sprintf("%s must be a date (YYYY-MM-DD), got %q", path, value)}
+		}
+	}
+
+	return nil
+}
+
+// toFloat converts a decoded YAML number.
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+package groundtruth
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const validMetadata = `version: "1.0.0"
+language: go
+category: test-smell
+smell_type: eager-test
+description: "Test function testing multiple unrelated concerns"
+verified_by:
+  - "alice@example.com"
+  - "bob@example.com"
+verified_date: 2026-01-28
+expected_detections:
+  - type: test-smell
+    smell: eager-test
+    file: "user_test.go"
+    function: "TestUserRegistration"
+    severity: medium
+    reason: "Tests creation and deletion in one test"
+files:
+  - path: "extra keys are allowed"
+`
+
+func TestSchemaValidate(t *testing.T) {
+	schema, err := LoadSchema(filepath.Join(groundTruthRoot, SchemaFile))
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		replace [2]string
+		want    string
+	}{
+		{name: "valid"},
+		{name: "missing required field", replace: [2]string{"description: \"Test function testing multiple unrelated concerns\"\n", ""}, want: "description is required"},
+		{name: "value outside enum", replace: [2]string{"language: go", "language: cobol"}, want: "language must be one of"},
+		{name: "conditionally required field", replace: [2]string{"smell_type: eager-test\n", ""}, want: "smell_type is required"},
+		{name: "too few verifiers", replace: [2]string{"  - \"bob@example.com\"\n", ""}, want: "verified_by must have at least 2 items"},
+		{name: "invalid email", replace: [2]string{"bob@example.com", "bob"}, want: "verified_by[1] must be an email address"},
+		{name: "invalid date", replace: [2]string{"2026-01-28", "yesterday"}, want: "verified_date must be a date"},
+		{name: "missing item field", replace: [2]string{"    file: \"user_test.go\"\n", ""}, want: "expected_detections[0].file is required"},
+		{name: "item field required by condition", replace: [2]string{"    smell: eager-test\n", ""}, want: "expected_detections[0].smell is required"},
+		{name: "wrong type", replace: [2]string{"function: \"TestUserRegistration\"", "line: many"}, want: "expected_detections[0].line must be an integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := validMetadata
+			if tt.replace[0] != "" {
+				src = strings.Replace(src, tt.replace[0], tt.replace[1], 1)
+			}
+
+			var doc map[string]any
+			if err := yaml.Unmarshal([]byte(src), &doc); err != nil {
+				t.Fatalf("invalid test YAML: %v", err)
+			}
+
+			problems := schema.Validate(doc)
+
+			if tt.want == "" {
+				if len(problems) > 0 {
+					t.Errorf("Validate() = %v, want no problems", problems)
+				}
+
+				return
+			}
+
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.want) {
+				t.Errorf("Validate() = %v, want %q", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSchemaRejectsUnsupportedConditions(t *testing.T) {
+	_, err := ParseSchema([]byte("smell:\n  type: string\n  required_when: category != \"x\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "unsupported condition") {
+		t.Errorf("ParseSchema() error = %v", err)
+	}
+}
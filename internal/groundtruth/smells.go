@@ -0,0 +1,156 @@
+package groundtruth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/smells"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// SmellEvaluator scores the smell engine on test-smell examples.
+type SmellEvaluator struct {
+	config *smells.Config
+}
+
+// NewSmellEvaluator creates an evaluator using the default smell configuration.
+func NewSmellEvaluator() *SmellEvaluator {
+	return &SmellEvaluator{config: smells.DefaultConfig()}
+}
+
+// Evaluate runs the smell engine over the example. Only smells the example is
+// annotated for (its smell_type and the smells of its expected detections)
+// are scored, so an example of one smell does not count the incidental
+// findings of other smells. A finding matches an expected detection in the
+// same file whose line, if given, is within the finding and whose function,
+// if given, is the finding's test. Unmatched findings are false positives
+// unless the example accepts them in false_positives_expected.
+func (e *SmellEvaluator) Evaluate(ex *Example) ([]Outcome, error) {
+	findings, err := smells.NewEngine(discovery.NewWalker(ex.Dir), e.config).Analyze()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect smells: %w", err)
+	}
+
+	annotated := make(map[string]bool)
+	if ex.Metadata.SmellType != "" {
+		annotated[ex.Metadata.SmellType] = true
+	}
+
+	for _, d := range ex.Metadata.ExpectedDetections {
+		if d.Type == CategoryTestSmell && d.Smell != "" {
+			annotated[d.Smell] = true
+		}
+	}
+
+	keys := make([]string, 0, len(annotated))
+	for k := range annotated {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	outcomes := make([]Outcome, 0, len(keys))
+	for _, smell := range keys {
+		outcomes = append(outcomes, evaluateSmell(smell, ex.Metadata, findings))
+	}
+
+	return outcomes, nil
+}
+
+// evaluateSmell matches the findings of one smell with its expected detections.
+func evaluateSmell(smell string, meta Metadata, findings []types.Finding) Outcome {
+	o := Outcome{Key: smell}
+
+	var reported []types.Finding
+
+	for _, f := range findings {
+		if f.CheckID == smell {
+			reported = append(reported, f)
+		}
+	}
+
+	matched := make([]bool, len(reported))
+
+	for _, d := range meta.ExpectedDetections {
+		if d.Type != CategoryTestSmell || d.Smell != smell {
+			continue
+		}
+
+		found := false
+
+		for i, f := range reported {
+			if !matched[i] && detects(f, d) {
+				matched[i], found = true, true
+				break
+			}
+		}
+
+		if found {
+			o.TruePositives++
+		} else {
+			o.FalseNegatives++
+			o.Mismatches = append(o.Mismatches, fmt.Sprintf("missed %s in %s", smell, describe(d)))
+		}
+	}
+
+	accepted := 0
+
+	for _, fp := range meta.FalsePositivesExpected {
+		if fp.Type == smell {
+			accepted++
+		}
+	}
+
+	for i, f := range reported {
+		if matched[i] {
+			continue
+		}
+
+		if accepted > 0 {
+			accepted--
+			continue
+		}
+
+		o.FalsePositives++
+		o.Mismatches = append(o.Mismatches, fmt.Sprintf("unexpected %s at %s", smell, f.Location))
+	}
+
+	return o
+}
+
+// detects reports whether a finding satisfies an expected detection.
+func detects(f types.Finding, d Detection) bool {
+	if f.Location.File != d.File {
+		return false
+	}
+
+	if d.Line > 0 {
+		end := max(f.Location.EndLine, f.Location.StartLine)
+		if d.Line < f.Location.StartLine || d.Line > end {
+			return false
+		}
+	}
+
+	// Fixture and file-level findings have no test to compare.
+	if d.Function != "" && f.Test != "" {
+		return strings.HasSuffix(f.Test, "::"+d.Function)
+	}
+
+	return true
+}
+
+// describe formats the location of an expected detection.
+func describe(d Detection) string {
+	loc := d.File
+	if d.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", loc, d.Line)
+	}
+
+	if d.Function != "" {
+		loc += " (" + d.Function + ")"
+	}
+
+	return loc
+}
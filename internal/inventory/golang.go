@@ -0,0 +1,560 @@
+package inventory
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// goTestPrefixes maps the prefixes of go test entry points onto test kinds.
+var goTestPrefixes = []struct {
+	prefix string
+	param  string
+	kind   types.TestKind
+}{
+	{"Test", "T", types.TestKindTest},
+	{"Benchmark", "B", types.TestKindBenchmark},
+	{"Fuzz", "F", types.TestKindFuzz},
+}
+
+// goFailureMethods are the testing.T methods that report a failure.
+var goFailureMethods = map[string]bool{
+	"Error": true, "Errorf": true, "Fatal": true, "Fatalf": true, "Fail": true, "FailNow": true,
+}
+
+// goSkipMethods are the testing.T methods that skip a test.
+var goSkipMethods = map[string]bool{"Skip": true, "Skipf": true, "SkipNow": true}
+
+// GoParser parses Go test files written with the standard testing package
+// and testify.
+type GoParser struct{}
+
+// NewGoParser creates a Go test parser.
+func NewGoParser() *GoParser {
+	return &GoParser{}
+}
+
+// Parse builds the inventory of a Go test file.
+func (p *GoParser) Parse(relPath string, src []byte) (*types.TestFile, error) {
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, relPath, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", relPath, err)
+	}
+
+	w := &goWalker{fset: fset, file: relPath, styles: map[string]int{}}
+	testify := false
+
+	for _, imp := range f.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value) //nolint:errcheck // Import paths are valid Go strings
+		if strings.HasPrefix(path, "github.com/stretchr/testify") {
+			testify = true
+		}
+	}
+
+	file := &types.TestFile{
+		Path:      relPath,
+		Language:  types.LanguageGo,
+		Framework: "testing",
+	}
+
+	if testify {
+		file.Framework = "testify"
+	}
+
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Recv != nil || fn.Body == nil {
+			continue
+		}
+
+		if fn.Name.Name == "TestMain" {
+			file.Hooks = append(file.Hooks, types.TestHook{
+				Kind: types.HookBeforeAll,
+				Name: "TestMain",
+				Line: fset.Position(fn.Pos()).Line,
+			})
+
+			continue
+		}
+
+		if tc, ok := w.testFunc(fn); ok {
+			file.Tests = append(file.Tests, tc)
+		}
+	}
+
+	file.Mocks = w.mocks
+
+	if len(w.styles) > 0 {
+		file.AssertionStyles = w.styles
+	}
+
+	return file, nil
+}
+
+type goWalker struct {
+	fset   *token.FileSet
+	file   string
+	styles map[string]int
+	mocks  []types.MockUsage
+}
+
+// testFunc builds a test case for a top-level Test, Benchmark, Fuzz or Example function.
+func (w *goWalker) testFunc(fn *ast.FuncDecl) (types.TestCase, bool) {
+	name := fn.Name.Name
+	kind := types.TestKind("")
+
+	for _, tp := range goTestPrefixes {
+		if isGoEntryPoint(name, tp.prefix) && hasTestingParam(fn.Type, tp.param) {
+			kind = tp.kind
+			break
+		}
+	}
+
+	if kind == "" && isGoEntryPoint(name, "Example") && fn.Type.Params.NumFields() == 0 {
+		kind = types.TestKindExample
+	}
+
+	if kind == "" {
+		return types.TestCase{}, false
+	}
+
+	tc := types.TestCase{
+		ID:       testID(w.file, name),
+		Name:     name,
+		Kind:     kind,
+		File:     w.file,
+		Line:     w.fset.Position(fn.Pos()).Line,
+		EndLine:  w.fset.Position(fn.End()).Line,
+		Skipped:  startsWithSkip(fn.Body),
+		Parallel: callsParallel(fn.Body),
+	}
+
+	tables := goTables(fn.Body)
+	tc.Children = w.subtests(fn.Body, []string{name}, tables)
+	tc.Assertions, tc.WeakAssertions = w.countAssertions(fn.Body, testingParamName(fn.Type), true)
+
+	return tc, true
+}
+
+// isGoEntryPoint applies the go test naming rule: the prefix must be followed
+// by the end of the name or a character that is not a lower-case letter.
+func isGoEntryPoint(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+
+	if len(name) == len(prefix) {
+		return true
+	}
+
+	c := name[len(prefix)]
+
+	return c < 'a' || c > 'z'
+}
+
+// hasTestingParam reports whether the function takes a single *testing.<param>.
+func hasTestingParam(ft *ast.FuncType, param string) bool {
+	if ft.Params == nil || len(ft.Params.List) != 1 {
+		return false
+	}
+
+	star, ok := ft.Params.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := star.X.(*ast.SelectorExpr)
+
+	return ok && sel.Sel.Name == param
+}
+
+// testingParamName returns the name of the single *testing.T (or B, F)
+// parameter, or "" when it is unnamed.
+func testingParamName(ft *ast.FuncType) string {
+	if ft.Params == nil || len(ft.Params.List) != 1 || len(ft.Params.List[0].Names) != 1 {
+		return ""
+	}
+
+	return ft.Params.List[0].Names[0].Name
+}
+
+// startsWithSkip reports whether the first statement unconditionally skips the test.
+func startsWithSkip(body *ast.BlockStmt) bool {
+	if len(body.List) == 0 {
+		return false
+	}
+
+	expr, ok := body.List[0].(*ast.ExprStmt)
+	if !ok {
+		return false
+	}
+
+	call, ok := expr.X.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+
+	return ok && goSkipMethods[sel.Sel.Name]
+}
+
+// callsParallel reports whether body calls t.Parallel(), ignoring nested
+// function literals such as subtests.
+func callsParallel(body *ast.BlockStmt) bool {
+	found := false
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+
+		switch x := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if sel, ok := x.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Parallel" && len(x.Args) == 0 {
+				_, found = sel.X.(*ast.Ident)
+			}
+		}
+
+		return true
+	})
+
+	return found
+}
+
+// goTables maps the names of local table variables to their number of rows.
+func goTables(body *ast.BlockStmt) map[string]int {
+	tables := map[string]int{}
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Lhs) != len(assign.Rhs) {
+			return true
+		}
+
+		for i, rhs := range assign.Rhs {
+			lit, ok := rhs.(*ast.CompositeLit)
+			if !ok {
+				continue
+			}
+
+			switch lit.Type.(type) {
+			case *ast.ArrayType, *ast.MapType:
+				if ident, ok := assign.Lhs[i].(*ast.Ident); ok {
+					tables[ident.Name] = len(lit.Elts)
+				}
+			}
+		}
+
+		return true
+	})
+
+	return tables
+}
+
+// subtests finds t.Run calls directly nested in node (not inside other subtests).
+func (w *goWalker) subtests(node ast.Node, parents []string, tables map[string]int) []types.TestCase {
+	var children []types.TestCase
+
+	// rangeRows tracks the number of rows of enclosing range loops over tables
+	var visit func(n ast.Node, rows int)
+
+	visit = func(n ast.Node, rows int) {
+		ast.Inspect(n, func(c ast.Node) bool {
+			if c == n {
+				return true
+			}
+
+			switch node := c.(type) {
+			case *ast.RangeStmt:
+				visit(node.Body, tableRows(node.X, tables))
+				return false
+			case *ast.CallExpr:
+				sub, ok := w.subtest(node, parents, rows, tables)
+				if !ok {
+					return true
+				}
+
+				children = append(children, sub)
+
+				return false
+			}
+
+			return true
+		})
+	}
+
+	visit(node, 0)
+
+	return children
+}
+
+// tableRows returns the number of rows of a ranged expression, or 0.
+func tableRows(x ast.Expr, tables map[string]int) int {
+	switch e := x.(type) {
+	case *ast.Ident:
+		return tables[e.Name]
+	case *ast.CompositeLit:
+		return len(e.Elts)
+	}
+
+	return 0
+}
+
+// subtest builds a child entry for a t.Run(name, func(t *testing.T) {...}) call.
+func (w *goWalker) subtest(call *ast.CallExpr, parents []string, rows int, tables map[string]int) (types.TestCase, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Run" || len(call.Args) != 2 {
+		return types.TestCase{}, false
+	}
+
+	fn, ok := call.Args[1].(*ast.FuncLit)
+	if !ok || !hasTestingParam(fn.Type, "T") && !hasTestingParam(fn.Type, "B") {
+		return types.TestCase{}, false
+	}
+
+	name := exprText(call.Args[0])
+	if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		if unquoted, err := strconv.Unquote(lit.Value); err == nil {
+			name = unquoted
+		}
+	}
+
+	path := append(append([]string(nil), parents...), name)
+	tc := types.TestCase{
+		ID:       testID(w.file, path...),
+		Name:     name,
+		Kind:     types.TestKindTest,
+		File:     w.file,
+		Line:     w.fset.Position(call.Pos()).Line,
+		EndLine:  w.fset.Position(call.End()).Line,
+		Skipped:  startsWithSkip(fn.Body),
+		Parallel: callsParallel(fn.Body),
+	}
+
+	if rows > 0 {
+		tc.Kind = types.TestKindParameterized
+		tc.ParameterCases = rows
+		tc.ParameterSources = []string{"table"}
+	}
+
+	tc.Children = w.subtests(fn.Body, path, tables)
+	tc.Assertions, tc.WeakAssertions = w.countAssertions(fn.Body, testingParamName(fn.Type), false)
+
+	return tc, true
+}
+
+// countAssertions counts failure reports and testify assertions in body,
+// excluding nested subtests, and returns the weak ones. param is the name of
+// the *testing.T parameter. Mock constructors are recorded on the first pass.
+func (w *goWalker) countAssertions(body *ast.BlockStmt, param string, record bool) (int, []types.WeakAssertion) {
+	count := 0
+
+	var weak []types.WeakAssertion
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		if record {
+			w.recordMock(call)
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if sel.Sel.Name == "Run" && len(call.Args) == 2 {
+			if _, ok := call.Args[1].(*ast.FuncLit); ok {
+				// Subtest bodies are counted on the subtest itself, but mocks
+				// declared inside them still belong to the file
+				if record {
+					ast.Inspect(call.Args[1], func(m ast.Node) bool {
+						if c, ok := m.(*ast.CallExpr); ok {
+							w.recordMock(c)
+						}
+
+						return true
+					})
+				}
+
+				return false
+			}
+		}
+
+		if pkg, ok := sel.X.(*ast.Ident); ok && (pkg.Name == "assert" || pkg.Name == "require") {
+			w.styles["testify"]++
+			count++
+
+			if wa, ok := w.weakTestify(pkg.Name, sel.Sel.Name, call); ok {
+				weak = append(weak, wa)
+			}
+
+			return true
+		}
+
+		if goFailureMethods[sel.Sel.Name] {
+			w.styles["testing"]++
+			count++
+
+			if wa, ok := w.weakFailure(sel, call, param); ok {
+				weak = append(weak, wa)
+			}
+		}
+
+		return true
+	})
+
+	return count, weak
+}
+
+// goComparisonMatchers maps comparison operators onto the testify assertion
+// that checks them, for assert.True and for assert.False.
+var goComparisonMatchers = map[token.Token][2]string{
+	token.EQL: {"Equal", "NotEqual"},
+	token.NEQ: {"NotEqual", "Equal"},
+	token.LSS: {"Less", "GreaterOrEqual"},
+	token.LEQ: {"LessOrEqual", "Greater"},
+	token.GTR: {"Greater", "LessOrEqual"},
+	token.GEQ: {"GreaterOrEqual", "Less"},
+}
+
+// goPredicateMatchers maps boolean helper functions onto the testify
+// assertion that checks them, for assert.True and for assert.False.
+var goPredicateMatchers = map[string][2]string{
+	"reflect.DeepEqual": {"Equal", "NotEqual"},
+	"bytes.Equal":       {"Equal", "NotEqual"},
+	"strings.Contains":  {"Contains", "NotContains"},
+	"errors.Is":         {"ErrorIs", "NotErrorIs"},
+}
+
+// weakTestify reports assert.True and assert.False calls whose condition is
+// a comparison testify can check directly, such as assert.True(t, a == b).
+func (w *goWalker) weakTestify(pkg, name string, call *ast.CallExpr) (types.WeakAssertion, bool) {
+	negated := 0
+
+	switch name {
+	case "True", "Truef":
+	case "False", "Falsef":
+		negated = 1
+	default:
+		return types.WeakAssertion{}, false
+	}
+
+	if len(call.Args) < 2 {
+		return types.WeakAssertion{}, false
+	}
+
+	matcher := ""
+
+	switch cond := call.Args[1].(type) {
+	case *ast.BinaryExpr:
+		matcher = goComparisonMatchers[cond.Op][negated]
+
+		if isNilIdent(cond.X) || isNilIdent(cond.Y) {
+			switch cond.Op {
+			case token.EQL:
+				matcher = [2]string{"Nil", "NotNil"}[negated]
+			case token.NEQ:
+				matcher = [2]string{"NotNil", "Nil"}[negated]
+			}
+		}
+	case *ast.CallExpr:
+		matcher = goPredicateMatchers[exprText(cond.Fun)][negated]
+	}
+
+	if matcher == "" {
+		return types.WeakAssertion{}, false
+	}
+
+	return types.WeakAssertion{
+		Kind:       types.WeakBooleanComparison,
+		Assertion:  pkg + "." + name,
+		Suggestion: pkg + "." + matcher,
+		Line:       w.fset.Position(call.Pos()).Line,
+	}, true
+}
+
+// weakFailure reports failures without a message: t.Fail(), t.FailNow() and
+// t.Error() or t.Fatal() without arguments, called on the testing parameter.
+func (w *goWalker) weakFailure(sel *ast.SelectorExpr, call *ast.CallExpr, param string) (types.WeakAssertion, bool) {
+	if recv, ok := sel.X.(*ast.Ident); !ok || recv.Name != param {
+		return types.WeakAssertion{}, false
+	}
+
+	suggestion := ""
+
+	switch sel.Sel.Name {
+	case "Fail", "Error":
+		suggestion = "t.Errorf(\"got %v, want %v\", got, want)"
+	case "FailNow", "Fatal":
+		suggestion = "t.Fatalf(\"got %v, want %v\", got, want)"
+	}
+
+	if suggestion == "" || len(call.Args) > 0 {
+		return types.WeakAssertion{}, false
+	}
+
+	return types.WeakAssertion{
+		Kind:       types.WeakMissingMessage,
+		Assertion:  exprText(sel),
+		Suggestion: suggestion,
+		Line:       w.fset.Position(call.Pos()).Line,
+	}, true
+}
+
+func isNilIdent(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+
+	return ok && ident.Name == "nil"
+}
+
+// recordMock records gomock controllers and generated NewMockXxx constructors.
+func (w *goWalker) recordMock(call *ast.CallExpr) {
+	name := ""
+
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		name = fun.Sel.Name
+	}
+
+	if strings.HasPrefix(name, "NewMock") && len(name) > len("NewMock") {
+		w.mocks = append(w.mocks, types.MockUsage{
+			API:    "gomock",
+			Target: strings.TrimPrefix(name, "NewMock"),
+			Line:   w.fset.Position(call.Pos()).Line,
+		})
+	}
+}
+
+// exprText renders simple expressions used as subtest names (tt.name, name).
+func exprText(e ast.Expr) string {
+	switch x := e.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.SelectorExpr:
+		return exprText(x.X) + "." + x.Sel.Name
+	case *ast.BasicLit:
+		return x.Value
+	case *ast.CallExpr:
+		return exprText(x.Fun) + "(...)"
+	case *ast.IndexExpr:
+		return exprText(x.X) + "[" + exprText(x.Index) + "]"
+	}
+
+	return "?"
+}
@@ -0,0 +1,265 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const goTestFile = `package orders
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(m.Run())
+}
+
+func TestPlace(t *testing.T) {
+	order, err := Place("sku-1")
+	require.NoError(t, err)
+	assert.Equal(t, 10, order.Total)
+}
+
+func TestPrices(t *testing.T) {
+	tests := []struct {
+		name string
+		sku  string
+		want int
+	}{
+		{name: "apple", sku: "a", want: 1},
+		{name: "pear", sku: "p", want: 2},
+		{name: "plum", sku: "u", want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Price(tt.sku); got != tt.want {
+				t.Errorf("Price() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Run("refunds", func(t *testing.T) {
+		t.Run("to card", func(t *testing.T) {})
+	})
+}
+
+func TestSlow(t *testing.T) {
+	t.Skip("requires database")
+}
+
+func BenchmarkPlace(b *testing.B) {}
+
+func FuzzParse(f *testing.F) {}
+
+func ExamplePlace() {}
+
+func helper(t *testing.T) {}
+`
+
+//nolint:gocognit,gocyclo // Verifies the full tree of a representative test file
+func TestGoParser(t *testing.T) {
+	file, err := NewGoParser().Parse("orders/orders_test.go", []byte(goTestFile))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Framework != "testify" || file.Language != types.LanguageGo {
+		t.Errorf("Framework = %q, Language = %v", file.Framework, file.Language)
+	}
+
+	byName := map[string]types.TestCase{}
+	for _, tc := range file.Tests {
+		byName[tc.Name] = tc
+	}
+
+	if len(byName) != 7 {
+		t.Errorf("top-level entries = %d, want 7: %v", len(byName), byName)
+	}
+
+	if len(file.Hooks) != 1 || file.Hooks[0].Kind != types.HookBeforeAll || file.Hooks[0].Name != "TestMain" {
+		t.Errorf("Hooks = %+v", file.Hooks)
+	}
+
+	if tc := byName["TestPlace"]; tc.Kind != types.TestKindTest || tc.Assertions != 2 || tc.Line != 15 {
+		t.Errorf("TestPlace = kind %s, %d assertions, line %d", tc.Kind, tc.Assertions, tc.Line)
+	}
+
+	prices := byName["TestPrices"]
+	if len(prices.Children) != 1 {
+		t.Fatalf("TestPrices children = %+v, want 1", prices.Children)
+	}
+
+	if sub := prices.Children[0]; sub.Kind != types.TestKindParameterized || sub.ParameterCases != 3 {
+		t.Errorf("table subtest = kind %s, %d cases", sub.Kind, sub.ParameterCases)
+	}
+
+	cancel := byName["TestCancel"]
+	if len(cancel.Children) != 1 || len(cancel.Children[0].Children) != 1 {
+		t.Fatalf("TestCancel = %+v", cancel)
+	}
+
+	if want := "orders/orders_test.go::TestCancel::refunds::to card"; cancel.Children[0].Children[0].ID != want {
+		t.Errorf("nested ID = %q, want %q", cancel.Children[0].Children[0].ID, want)
+	}
+
+	if !byName["TestSlow"].Skipped {
+		t.Error("TestSlow should be skipped")
+	}
+
+	kinds := map[string]types.TestKind{
+		"BenchmarkPlace": types.TestKindBenchmark,
+		"FuzzParse":      types.TestKindFuzz,
+		"ExamplePlace":   types.TestKindExample,
+	}
+	for name, want := range kinds {
+		if got := byName[name].Kind; got != want {
+			t.Errorf("%s kind = %s, want %s", name, got, want)
+		}
+	}
+
+	if file.AssertionStyles["testify"] != 2 || file.AssertionStyles["testing"] != 1 {
+		t.Errorf("AssertionStyles = %v, want testify:2 testing:1", file.AssertionStyles)
+	}
+}
+
+func TestGoParser_Mocks(t *testing.T) {
+	src := `package svc
+
+import (
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := NewMockStore(ctrl)
+	_ = store
+}
+`
+
+	file, err := NewGoParser().Parse("svc/handler_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Framework != "testing" {
+		t.Errorf("Framework = %q, want testing", file.Framework)
+	}
+
+	if len(file.Mocks) != 1 || file.Mocks[0].Target != "Store" {
+		t.Errorf("Mocks = %+v", file.Mocks)
+	}
+}
+
+func TestGoParser_InvalidSource(t *testing.T) {
+	if _, err := NewGoParser().Parse("bad_test.go", []byte("package x\nfunc {")); err == nil {
+		t.Error("Parse() expected error for invalid source")
+	}
+}
+
+func TestGoParser_Parallel(t *testing.T) {
+	src := `package svc
+
+import "testing"
+
+func TestConcurrent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("serial", func(t *testing.T) {})
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+	})
+}
+
+func TestSerial(t *testing.T) {
+	t.Run("parallel", func(t *testing.T) {
+		t.Parallel()
+	})
+}
+`
+
+	file, err := NewGoParser().Parse("svc/svc_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := map[string]bool{}
+	file.Walk(func(tc *types.TestCase) {
+		got[tc.ID] = tc.Parallel
+	})
+
+	want := map[string]bool{
+		"svc/svc_test.go::TestConcurrent":           true,
+		"svc/svc_test.go::TestConcurrent::serial":   false,
+		"svc/svc_test.go::TestConcurrent::parallel": true,
+		"svc/svc_test.go::TestSerial":               false,
+		"svc/svc_test.go::TestSerial::parallel":     true,
+	}
+
+	for id, parallel := range want {
+		if got[id] != parallel {
+			t.Errorf("%s: Parallel = %v, want %v", id, got[id], parallel)
+		}
+	}
+}
+
+func TestGoParser_WeakAssertions(t *testing.T) {
+	src := `package svc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailures(t *testing.T) {
+	if got := Load(); got != 1 {
+		t.Fail()
+	}
+	if Load() == 0 {
+		t.Fatal()
+	}
+	if Load() < 0 {
+		t.Errorf("Load() = %d, want >= 0", Load())
+	}
+	if err := Check(); err != nil {
+		t.Fatalf("Check() = %s", err.Error())
+	}
+}
+
+func TestTestify(t *testing.T) {
+	assert.True(t, Load() == 1)
+	require.False(t, Load() >= 2, "too large")
+	assert.True(t, reflect.DeepEqual(List(), []int{1}))
+	assert.False(t, Err() != nil)
+	assert.True(t, Ready())
+	assert.Equal(t, 1, Load())
+}
+`
+
+	file, err := NewGoParser().Parse("svc/svc_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	checkWeakAssertions(t, file, map[string]string{
+		"TestFailures": "13 missing-message t.Fail -> t.Errorf(\"got %v, want %v\", got, want); " +
+			"16 missing-message t.Fatal -> t.Fatalf(\"got %v, want %v\", got, want)",
+		"TestTestify": "27 boolean-comparison assert.True -> assert.Equal; " +
+			"28 boolean-comparison require.False -> require.Less; " +
+			"29 boolean-comparison assert.True -> assert.Equal; " +
+			"30 boolean-comparison assert.False -> assert.Nil",
+	})
+}
@@ -0,0 +1,93 @@
+// Package inventory builds language-neutral test inventories from test sources.
+//
+// Each supported language provides a Parser that turns a single test file
+// into a types.TestFile. The Collector walks a repository, classifies files
+// and dispatches them to the parser registered for their language.
+package inventory
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Parser extracts the test inventory of a single test file.
+type Parser interface {
+	// Parse builds the inventory of the file at relPath from its source.
+	Parse(relPath string, src []byte) (*types.TestFile, error)
+}
+
+// Collector builds a test inventory for a whole repository.
+type Collector struct {
+	walker  *discovery.Walker
+	parsers map[types.Language]Parser
+}
+
+// NewCollector creates a collector with parsers for all supported languages.
+func NewCollector(walker *discovery.Walker) *Collector {
+	js := NewJavaScriptParser()
+
+	return &Collector{
+		walker: walker,
+		parsers: map[types.Language]Parser{
+			types.LanguageJavaScript: js,
+			types.LanguageTypeScript: js,
+			types.LanguageJava:       NewJavaParser(),
+			types.LanguageGo:         NewGoParser(),
+			types.LanguagePython:     NewPythonParser(),
+		},
+	}
+}
+
+// Collect walks the repository and parses every recognized test file.
+// Files that cannot be read or parsed are logged and skipped.
+func (c *Collector) Collect() (*types.TestInventory, error) {
+	inv := &types.TestInventory{}
+
+	_, err := c.walker.Walk(func(fi discovery.FileInfo) error {
+		if !discovery.IsTestFile(fi.RelPath) {
+			return nil
+		}
+
+		parser, ok := c.parsers[discovery.LanguageOf(fi.Name)]
+		if !ok {
+			return nil
+		}
+
+		src, err := os.ReadFile(fi.Path) //nolint:gosec // Reading source files from repository
+		if err != nil {
+			logger.Warn("Failed to read test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		file, err := parser.Parse(filepath.ToSlash(fi.RelPath), src)
+		if err != nil {
+			logger.Warn("Failed to parse test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		inv.Files = append(inv.Files, *file)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	sort.Slice(inv.Files, func(i, j int) bool {
+		return inv.Files[i].Path < inv.Files[j].Path
+	})
+
+	return inv, nil
+}
+
+// testID joins a file path and the names of nested entries into a test ID.
+func testID(file string, names ...string) string {
+	return file + "::" + strings.Join(names, "::")
+}
@@ -0,0 +1,67 @@
+package inventory
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// weakAssertions maps test names to their weak assertions formatted as
+// "line kind assertion -> suggestion", joined by "; ".
+func weakAssertions(file *types.TestFile) map[string]string {
+	got := map[string]string{}
+
+	file.Walk(func(tc *types.TestCase) {
+		var parts []string
+		for _, wa := range tc.WeakAssertions {
+			parts = append(parts, fmt.Sprintf("%d %s %s -> %s", wa.Line, wa.Kind, wa.Assertion, wa.Suggestion))
+		}
+
+		got[tc.Name] = strings.Join(parts, "; ")
+	})
+
+	return got
+}
+
+// checkWeakAssertions compares weakAssertions(file) with want.
+func checkWeakAssertions(t *testing.T, file *types.TestFile, want map[string]string) {
+	t.Helper()
+
+	got := weakAssertions(file)
+	for name, w := range want {
+		if got[name] != w {
+			t.Errorf("%s: weak assertions = %q, want %q", name, got[name], w)
+		}
+	}
+}
+
+func TestCollector_Collect(t *testing.T) {
+	dir := testutil.TempDir(t)
+
+	testutil.WriteFile(t, dir, "web/src/app.test.ts", "describe('app', () => { it('boots', () => {}); });")
+	testutil.WriteFile(t, dir, "web/src/__tests__/util.js", "test('util', () => {});")
+	testutil.WriteFile(t, dir, "web/src/app.ts", "export const app = {};")
+	testutil.WriteFile(t, dir, "web/node_modules/lib/lib.test.js", "it('vendored', () => {});")
+
+	inv, err := NewCollector(discovery.NewWalker(dir)).Collect()
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	if len(inv.Files) != 2 {
+		t.Fatalf("Collect() found %d files, want 2: %+v", len(inv.Files), inv.Files)
+	}
+
+	// Files are sorted by path
+	if inv.Files[0].Path != "web/src/__tests__/util.js" || inv.Files[1].Path != "web/src/app.test.ts" {
+		t.Errorf("file order = %s, %s", inv.Files[0].Path, inv.Files[1].Path)
+	}
+
+	if got := inv.TestCount(); got != 2 {
+		t.Errorf("TestCount() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,813 @@
+package inventory
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// javaTestAnnotations mark methods as tests and map them to a test kind.
+var javaTestAnnotations = map[string]types.TestKind{
+	"Test":              types.TestKindTest,
+	"ParameterizedTest": types.TestKindParameterized,
+	"RepeatedTest":      types.TestKindParameterized,
+	"TestFactory":       types.TestKindParameterized,
+	"TestTemplate":      types.TestKindParameterized,
+}
+
+// javaHookAnnotations maps JUnit 4/5 and TestNG lifecycle annotations onto hook kinds.
+var javaHookAnnotations = map[string]types.HookKind{
+	"BeforeAll":    types.HookBeforeAll,
+	"BeforeClass":  types.HookBeforeAll,
+	"BeforeSuite":  types.HookBeforeAll,
+	"BeforeTest":   types.HookBeforeAll,
+	"BeforeGroups": types.HookBeforeAll,
+	"BeforeEach":   types.HookBeforeEach,
+	"Before":       types.HookBeforeEach,
+	"BeforeMethod": types.HookBeforeEach,
+	"AfterEach":    types.HookAfterEach,
+	"After":        types.HookAfterEach,
+	"AfterMethod":  types.HookAfterEach,
+	"AfterAll":     types.HookAfterAll,
+	"AfterClass":   types.HookAfterAll,
+	"AfterSuite":   types.HookAfterAll,
+	"AfterTest":    types.HookAfterAll,
+	"AfterGroups":  types.HookAfterAll,
+}
+
+// javaJUnit3Hooks are the lifecycle methods of JUnit 3 TestCase subclasses.
+var javaJUnit3Hooks = map[string]types.HookKind{
+	"setUp":    types.HookBeforeEach,
+	"tearDown": types.HookAfterEach,
+}
+
+// javaParameterSources are the JUnit 5 argument sources and TestNG data providers.
+var javaParameterSources = map[string]bool{
+	"ValueSource": true, "CsvSource": true, "CsvFileSource": true, "MethodSource": true,
+	"EnumSource": true, "ArgumentsSource": true, "FieldSource": true,
+	"NullSource": true, "EmptySource": true, "NullAndEmptySource": true,
+}
+
+// javaMockAnnotations are field annotations that create mocks.
+var javaMockAnnotations = map[string]bool{"Mock": true, "MockBean": true, "Spy": true, "SpyBean": true}
+
+// JavaParser parses JUnit 3/4/5 and TestNG test classes.
+type JavaParser struct{}
+
+// NewJavaParser creates a Java test parser.
+func NewJavaParser() *JavaParser {
+	return &JavaParser{}
+}
+
+// javaAnnotation is a parsed annotation with its argument token range.
+type javaAnnotation struct {
+	name string
+	line int
+	// argsOpen and argsClose delimit the parenthesized arguments, or are -1
+	argsOpen, argsClose int
+}
+
+type javaWalker struct {
+	tokens    []lexer.Token
+	file      string
+	framework string
+	// assertThat is the library that owns an unqualified assertThat call
+	assertThat string
+	styles     map[string]int
+	mocks      []types.MockUsage
+}
+
+// Parse builds the inventory of a Java test file.
+func (p *JavaParser) Parse(relPath string, src []byte) (*types.TestFile, error) {
+	tokens := lexer.Tokenize(src, lexer.Java)
+	imports := javaImports(tokens)
+
+	w := &javaWalker{
+		tokens:     tokens,
+		file:       relPath,
+		framework:  javaFramework(imports),
+		assertThat: javaAssertThatLibrary(imports),
+		styles:     map[string]int{},
+	}
+
+	file := &types.TestFile{
+		Path:      relPath,
+		Language:  types.LanguageJava,
+		Framework: w.framework,
+	}
+
+	file.Tests, file.Hooks = w.parseBody(0, len(tokens), nil, classContext{})
+	file.Mocks = w.mocks
+
+	if len(w.styles) > 0 {
+		file.AssertionStyles = w.styles
+	}
+
+	return file, nil
+}
+
+// javaImports returns the imported names, including static imports.
+func javaImports(tokens []lexer.Token) []string {
+	var imports []string
+
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].Is(lexer.Ident, "import") {
+			continue
+		}
+
+		var parts []string
+
+		for i++; i < len(tokens) && !tokens[i].Is(lexer.Punct, ";"); i++ {
+			if tokens[i].Text != "static" {
+				parts = append(parts, tokens[i].Text)
+			}
+		}
+
+		imports = append(imports, strings.Join(parts, ""))
+	}
+
+	return imports
+}
+
+func javaFramework(imports []string) string {
+	framework := ""
+
+	for _, imp := range imports {
+		switch {
+		case strings.HasPrefix(imp, "org.testng"):
+			return "testng"
+		case strings.HasPrefix(imp, "org.junit.jupiter"):
+			framework = "junit5"
+		case strings.HasPrefix(imp, "org.junit.") && framework == "":
+			framework = "junit4"
+		case strings.HasPrefix(imp, "junit.framework") && framework == "":
+			framework = "junit3"
+		}
+	}
+
+	if framework == "" {
+		return "junit5"
+	}
+
+	return framework
+}
+
+// javaAssertThatLibrary determines which library provides assertThat.
+func javaAssertThatLibrary(imports []string) string {
+	library := "junit"
+
+	for _, imp := range imports {
+		switch {
+		case strings.HasPrefix(imp, "org.assertj"):
+			return "assertj"
+		case strings.HasPrefix(imp, "org.hamcrest"), strings.HasPrefix(imp, "org.junit.Assert.assertThat"):
+			library = "hamcrest"
+		case strings.HasPrefix(imp, "com.google.common.truth"):
+			library = "truth"
+		}
+	}
+
+	return library
+}
+
+// classContext carries class-level information into member parsing.
+type classContext struct {
+	// junit3 is set for subclasses of junit.framework.TestCase
+	junit3 bool
+	// testngClassTest is set when the class itself is annotated with TestNG @Test
+	testngClassTest bool
+	// tags are groups inherited from the class-level annotation
+	tags []string
+}
+
+// parseBody parses the members in tokens[start:end] and returns the test
+// classes (as suites) and methods found, together with lifecycle hooks.
+//
+//nolint:gocognit,gocyclo // Member dispatch follows the Java declaration grammar
+func (w *javaWalker) parseBody(start, end int, parents []string, ctx classContext) ([]types.TestCase, []types.TestHook) {
+	var (
+		cases []types.TestCase
+		hooks []types.TestHook
+	)
+
+	k := start
+	for k < end {
+		annotations, next := w.parseAnnotations(k, end)
+		k = next
+
+		if k >= end {
+			break
+		}
+
+		// Find what kind of member this is
+		m := k
+		for m < end {
+			tok := w.tokens[m]
+			if tok.Kind == lexer.Ident && (tok.Text == "class" || tok.Text == "interface" ||
+				tok.Text == "enum" || tok.Text == "record") {
+				break
+			}
+
+			if tok.Kind == lexer.Punct && (tok.Text == "(" || tok.Text == "{" || tok.Text == ";" || tok.Text == "=") {
+				break
+			}
+
+			m++
+		}
+
+		if m >= end {
+			break
+		}
+
+		tok := w.tokens[m]
+
+		switch {
+		case tok.Kind == lexer.Ident && tok.Text == "class" && m+1 < end:
+			suite, bodyEnd, ok := w.parseClass(m, end, parents, annotations, ctx)
+			if ok {
+				cases = append(cases, suite)
+			}
+
+			k = bodyEnd + 1
+		case tok.Kind == lexer.Ident:
+			// interface, enum or record: skip the declaration body
+			open := w.skipTo(m, end, "{")
+			if open >= end {
+				return cases, hooks
+			}
+
+			k = lexer.Match(w.tokens, open) + 1
+		case tok.Is(lexer.Punct, "("):
+			closeParams := lexer.Match(w.tokens, m)
+			bodyStart := w.skipTo(closeParams, end, "{", ";")
+			bodyEnd := bodyStart
+
+			if bodyStart < end && w.tokens[bodyStart].Is(lexer.Punct, "{") {
+				bodyEnd = lexer.Match(w.tokens, bodyStart)
+			}
+
+			name := ""
+			if m > 0 && w.tokens[m-1].Kind == lexer.Ident {
+				name = w.tokens[m-1].Text
+			}
+
+			isPublicVoid := w.hasModifier(k, m, "public") && w.hasModifier(k, m, "void")
+			if tc, ok := w.buildMethod(name, annotations, ctx, isPublicVoid, bodyStart, bodyEnd, parents); ok {
+				cases = append(cases, tc)
+			} else if hook, ok := w.buildHook(name, annotations, ctx, tok.Line); ok {
+				hooks = append(hooks, hook)
+			}
+
+			k = bodyEnd + 1
+		case tok.Is(lexer.Punct, "{"):
+			// Initializer block
+			k = lexer.Match(w.tokens, m) + 1
+		default:
+			// Field declaration, possibly with an initializer
+			w.recordFieldMocks(annotations, k, m)
+			k = w.skipTo(m, end, ";") + 1
+		}
+	}
+
+	return cases, hooks
+}
+
+// parseAnnotations consumes annotations and returns them with the index of
+// the first token after them.
+func (w *javaWalker) parseAnnotations(k, end int) ([]javaAnnotation, int) {
+	var annotations []javaAnnotation
+
+	for k+1 < end && w.tokens[k].Is(lexer.Punct, "@") && w.tokens[k+1].Kind == lexer.Ident &&
+		w.tokens[k+1].Text != "interface" {
+		a := javaAnnotation{line: w.tokens[k].Line, argsOpen: -1, argsClose: -1}
+		k++
+
+		// Qualified names keep only the simple name (@org.junit.Test -> Test)
+		a.name = w.tokens[k].Text
+		for k+2 < end && w.tokens[k+1].Is(lexer.Punct, ".") && w.tokens[k+2].Kind == lexer.Ident {
+			k += 2
+			a.name = w.tokens[k].Text
+		}
+
+		k++
+
+		if k < end && w.tokens[k].Is(lexer.Punct, "(") {
+			a.argsOpen = k
+			a.argsClose = lexer.Match(w.tokens, k)
+			k = a.argsClose + 1
+		}
+
+		annotations = append(annotations, a)
+	}
+
+	return annotations, k
+}
+
+// parseClass parses a class declaration at tokens[m] ("class" keyword).
+// It returns the class as a suite when it contains tests or hooks.
+func (w *javaWalker) parseClass(m, end int, parents []string, annotations []javaAnnotation,
+	outer classContext) (types.TestCase, int, bool) {
+	name := w.tokens[m+1].Text
+	open := w.skipTo(m, end, "{")
+	closeIdx := lexer.Match(w.tokens, open)
+
+	ctx := classContext{tags: append([]string(nil), outer.tags...)}
+
+	for k := m + 2; k < open; k++ {
+		if w.tokens[k].Is(lexer.Ident, "extends") && k+1 < open && w.tokens[k+1].Text == "TestCase" {
+			ctx.junit3 = true
+		}
+	}
+
+	suite := types.TestCase{
+		Name:    name,
+		Kind:    types.TestKindSuite,
+		File:    w.file,
+		Line:    w.tokens[m].Line,
+		EndLine: w.tokens[closeIdx].Line,
+	}
+
+	for _, a := range annotations {
+		switch {
+		case a.name == "Test" && w.framework == "testng":
+			ctx.testngClassTest = true
+			ctx.tags = append(ctx.tags, w.stringArgs(a, "groups")...)
+		case a.name == "Tag":
+			suite.Tags = append(suite.Tags, w.stringArgs(a, "value")...)
+		}
+
+		if w.isDisabled(a) {
+			suite.Skipped = true
+		}
+	}
+
+	path := append(append([]string(nil), parents...), name)
+	suite.ID = testID(w.file, path...)
+	suite.Children, suite.Hooks = w.parseBody(open+1, closeIdx, path, ctx)
+
+	if suite.Skipped {
+		for i := range suite.Children {
+			markSkipped(&suite.Children[i])
+		}
+	}
+
+	return suite, closeIdx, len(suite.Children) > 0 || len(suite.Hooks) > 0
+}
+
+func markSkipped(tc *types.TestCase) {
+	tc.Skipped = true
+	for i := range tc.Children {
+		markSkipped(&tc.Children[i])
+	}
+}
+
+// buildMethod builds a test case for a test method, if the method is one.
+//
+//nolint:gocognit // Annotation handling covers JUnit 3/4/5 and TestNG semantics
+func (w *javaWalker) buildMethod(name string, annotations []javaAnnotation, ctx classContext,
+	isPublicVoid bool, bodyStart, bodyEnd int, parents []string) (types.TestCase, bool) {
+	path := append(append([]string(nil), parents...), name)
+	tc := types.TestCase{
+		ID:   testID(w.file, path...),
+		Name: name,
+		File: w.file,
+		Tags: append([]string(nil), ctx.tags...),
+	}
+
+	isTest := false
+
+	for _, a := range annotations {
+		if kind, ok := javaTestAnnotations[a.name]; ok {
+			isTest = true
+
+			if tc.Kind == "" || kind != types.TestKindTest {
+				tc.Kind = kind
+			}
+
+			tc.Line = a.line
+		}
+
+		switch {
+		case javaParameterSources[a.name]:
+			tc.ParameterSources = append(tc.ParameterSources, "@"+a.name)
+			tc.ParameterCases += w.countSourceCases(a)
+		case a.name == "RepeatedTest":
+			tc.ParameterSources = append(tc.ParameterSources, "@RepeatedTest")
+			tc.ParameterCases += w.intArg(a)
+		case a.name == "Test" && w.framework == "testng":
+			tc.Tags = append(tc.Tags, w.stringArgs(a, "groups")...)
+
+			if provider := w.stringArgs(a, "dataProvider"); len(provider) > 0 {
+				tc.Kind = types.TestKindParameterized
+				tc.ParameterSources = append(tc.ParameterSources, "dataProvider:"+provider[0])
+			}
+		case a.name == "Tag":
+			tc.Tags = append(tc.Tags, w.stringArgs(a, "value")...)
+		}
+
+		if w.isDisabled(a) {
+			tc.Skipped = true
+		}
+	}
+
+	if !isTest && len(annotations) == 0 && isPublicVoid {
+		// JUnit 3 naming convention, or TestNG class-level @Test
+		if (ctx.junit3 && strings.HasPrefix(name, "test")) || ctx.testngClassTest {
+			isTest = true
+		}
+	}
+
+	if !isTest || bodyStart >= len(w.tokens) {
+		return tc, false
+	}
+
+	if tc.Kind == "" {
+		tc.Kind = types.TestKindTest
+	}
+
+	if tc.Line == 0 {
+		tc.Line = w.tokens[bodyStart].Line
+	}
+
+	tc.EndLine = w.tokens[bodyEnd].Line
+	tc.Assertions, tc.WeakAssertions = w.countAssertions(bodyStart, bodyEnd)
+
+	if len(tc.Tags) == 0 {
+		tc.Tags = nil
+	}
+
+	return tc, true
+}
+
+func (w *javaWalker) buildHook(name string, annotations []javaAnnotation, ctx classContext, line int) (types.TestHook, bool) {
+	for _, a := range annotations {
+		if kind, ok := javaHookAnnotations[a.name]; ok {
+			return types.TestHook{Kind: kind, Name: "@" + a.name, Line: a.line}, true
+		}
+	}
+
+	if kind, ok := javaJUnit3Hooks[name]; ok && ctx.junit3 {
+		return types.TestHook{Kind: kind, Name: name, Line: line}, true
+	}
+
+	return types.TestHook{}, false
+}
+
+// isDisabled reports whether an annotation disables the annotated element.
+func (w *javaWalker) isDisabled(a javaAnnotation) bool {
+	switch {
+	case a.name == "Ignore" || strings.HasPrefix(a.name, "Disabled"):
+		return true
+	case a.name == "Test" && w.framework == "testng" && a.argsOpen >= 0:
+		for k := a.argsOpen + 1; k+2 < a.argsClose; k++ {
+			if w.tokens[k].Is(lexer.Ident, "enabled") && w.tokens[k+1].Is(lexer.Punct, "=") &&
+				w.tokens[k+2].Is(lexer.Ident, "false") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// stringArgs returns the string literals of an annotation attribute. The
+// attribute "value" also matches a single unnamed argument.
+func (w *javaWalker) stringArgs(a javaAnnotation, attr string) []string {
+	if a.argsOpen < 0 {
+		return nil
+	}
+
+	start, end := a.argsOpen+1, a.argsClose
+
+	found := false
+	for k := start; k+1 < end; k++ {
+		if w.tokens[k].Is(lexer.Ident, attr) && w.tokens[k+1].Is(lexer.Punct, "=") {
+			start, end = k+2, w.attrEnd(k+2, a.argsClose)
+			found = true
+
+			break
+		}
+	}
+
+	if !found && (attr != "value" || w.hasNamedAttrs(a)) {
+		return nil
+	}
+
+	var values []string
+
+	for k := start; k < end; k++ {
+		if w.tokens[k].Kind == lexer.String {
+			values = append(values, lexer.Unquote(w.tokens[k].Text))
+		}
+	}
+
+	return values
+}
+
+// attrEnd returns the end of an annotation attribute value starting at k.
+func (w *javaWalker) attrEnd(k, limit int) int {
+	for ; k < limit; k++ {
+		if w.tokens[k].Is(lexer.Punct, "{") {
+			k = lexer.Match(w.tokens, k)
+			continue
+		}
+
+		if w.tokens[k].Is(lexer.Punct, ",") {
+			return k
+		}
+	}
+
+	return limit
+}
+
+func (w *javaWalker) hasNamedAttrs(a javaAnnotation) bool {
+	for k := a.argsOpen + 1; k+1 < a.argsClose; k++ {
+		if w.tokens[k].Kind == lexer.Ident && w.tokens[k+1].Is(lexer.Punct, "=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countSourceCases counts statically known cases of a JUnit 5 argument source.
+func (w *javaWalker) countSourceCases(a javaAnnotation) int {
+	switch a.name {
+	case "NullSource", "EmptySource":
+		return 1
+	case "NullAndEmptySource":
+		return 2
+	case "CsvSource":
+		if rows := w.stringArgs(a, "value"); len(rows) > 0 {
+			return len(rows)
+		}
+
+		// Text block form: one row per non-empty line
+		rows := 0
+
+		for _, text := range w.stringArgs(a, "textBlock") {
+			for _, line := range strings.Split(text, "\n") {
+				if strings.TrimSpace(line) != "" {
+					rows++
+				}
+			}
+		}
+
+		return rows
+	case "ValueSource":
+		if a.argsOpen < 0 {
+			return 0
+		}
+
+		// @ValueSource(ints = {1, 2, 3}) or @ValueSource(strings = "x")
+		count := 0
+
+		for k := a.argsOpen + 1; k < a.argsClose; k++ {
+			tok := w.tokens[k]
+			if tok.Kind == lexer.Punct && tok.Text == "=" {
+				continue
+			}
+
+			if tok.Kind == lexer.String || tok.Kind == lexer.Number ||
+				(tok.Kind == lexer.Ident && (k+1 >= a.argsClose || !w.tokens[k+1].Is(lexer.Punct, "="))) {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	return 0
+}
+
+// intArg returns the integer value of a single-argument annotation such as @RepeatedTest(5).
+func (w *javaWalker) intArg(a javaAnnotation) int {
+	for k := a.argsOpen + 1; a.argsOpen >= 0 && k < a.argsClose; k++ {
+		if w.tokens[k].Kind == lexer.Number {
+			if n, err := strconv.Atoi(w.tokens[k].Text); err == nil {
+				return n
+			}
+		}
+	}
+
+	return 0
+}
+
+// countAssertions counts assertion calls in a method body, records the
+// assertion library of each call and returns the weak ones.
+func (w *javaWalker) countAssertions(start, end int) (int, []types.WeakAssertion) {
+	count := 0
+
+	var weak []types.WeakAssertion
+
+	for k := start; k < end && k+1 < len(w.tokens); k++ {
+		tok := w.tokens[k]
+		if tok.Kind != lexer.Ident || !w.tokens[k+1].Is(lexer.Punct, "(") {
+			continue
+		}
+
+		library := ""
+
+		switch {
+		case tok.Text == "assertThat" || tok.Text == "assertThatThrownBy" || tok.Text == "assertThatCode":
+			library = w.assertThat
+			if tok.Text != "assertThat" {
+				library = "assertj"
+			}
+		case strings.HasPrefix(tok.Text, "assert") || tok.Text == "fail":
+			library = "junit"
+			if w.framework == "testng" {
+				library = "testng"
+			}
+		case tok.Text == "verify" || tok.Text == "verifyNoMoreInteractions" || tok.Text == "verifyNoInteractions":
+			library = "mockito"
+		default:
+			continue
+		}
+
+		w.styles[library]++
+		count++
+
+		if wa, ok := w.weakAssertion(k, end); ok {
+			weak = append(weak, wa)
+		}
+	}
+
+	return count, weak
+}
+
+// javaComparisonMatchers maps comparisons onto the JUnit assertion and the
+// AssertJ matcher that check them, for positive and negated assertions.
+// JUnit has no assertion for ordering comparisons.
+var javaComparisonMatchers = map[string]struct{ junit, assertj [2]string }{
+	"==":     {[2]string{"assertEquals", "assertNotEquals"}, [2]string{"isEqualTo", "isNotEqualTo"}},
+	"equals": {[2]string{"assertEquals", "assertNotEquals"}, [2]string{"isEqualTo", "isNotEqualTo"}},
+	"!=":     {[2]string{"assertNotEquals", "assertEquals"}, [2]string{"isNotEqualTo", "isEqualTo"}},
+	"null":   {[2]string{"assertNull", "assertNotNull"}, [2]string{"isNull", "isNotNull"}},
+	"<":      {assertj: [2]string{"isLessThan", "isGreaterThanOrEqualTo"}},
+	"<=":     {assertj: [2]string{"isLessThanOrEqualTo", "isGreaterThan"}},
+	">":      {assertj: [2]string{"isGreaterThan", "isLessThanOrEqualTo"}},
+	">=":     {assertj: [2]string{"isGreaterThanOrEqualTo", "isLessThan"}},
+}
+
+// weakAssertion reports comparisons checked as booleans, such as
+// assertTrue(a == b), assertFalse(a.equals(b)) and assertThat(a == b).isTrue(),
+// for the assertion call at tokens[k].
+func (w *javaWalker) weakAssertion(k, end int) (types.WeakAssertion, bool) {
+	tokens := w.tokens
+	closeParen := lexer.Match(tokens, k+1)
+	negated := 0
+	op := ""
+
+	switch tokens[k].Text {
+	case "assertTrue", "assertFalse":
+		if tokens[k].Text == "assertFalse" {
+			negated = 1
+		}
+
+		// The condition is the argument that compares; the others are messages
+		for start := k + 2; start < closeParen && op == ""; {
+			argEnd := w.skipTo(start, closeParen, ",")
+			op = w.comparison(start, argEnd, &negated)
+			start = argEnd + 1
+		}
+	case "assertThat":
+		if closeParen+3 >= end || !tokens[closeParen+1].Is(lexer.Punct, ".") {
+			return types.WeakAssertion{}, false
+		}
+
+		switch tokens[closeParen+2].Text {
+		case "isTrue":
+		case "isFalse":
+			negated = 1
+		default:
+			return types.WeakAssertion{}, false
+		}
+
+		op = w.comparison(k+2, closeParen, &negated)
+	}
+
+	if op == "" {
+		return types.WeakAssertion{}, false
+	}
+
+	// Suggest AssertJ when the test already uses it
+	assertj := w.assertThat == "assertj" || tokens[k].Text == "assertThat"
+	m := javaComparisonMatchers[op]
+	suggestion := "assertThat(actual)." + m.assertj[negated] + "(expected)"
+
+	switch {
+	case op == "null":
+		suggestion = "assertThat(actual)." + m.assertj[negated] + "()"
+		if !assertj {
+			suggestion = m.junit[negated] + "(actual)"
+		}
+	case !assertj && m.junit[negated] != "":
+		suggestion = m.junit[negated] + "(expected, actual)"
+	}
+
+	return types.WeakAssertion{
+		Kind:       types.WeakBooleanComparison,
+		Assertion:  tokens[k].Text,
+		Suggestion: suggestion,
+		Line:       tokens[k].Line,
+	}, true
+}
+
+// comparison returns the comparison of the expression in tokens[start:end]
+// outside brackets: an operator, "equals" for a.equals(b) or "null" for a
+// comparison with null. A leading ! flips negated.
+func (w *javaWalker) comparison(start, end int, negated *int) string {
+	for i := start; i < end; i++ {
+		tok := w.tokens[i]
+
+		switch {
+		case tok.Is(lexer.Punct, "(") || tok.Is(lexer.Punct, "[") || tok.Is(lexer.Punct, "{"):
+			i = lexer.Match(w.tokens, i)
+		case tok.Is(lexer.Punct, "&&") || tok.Is(lexer.Punct, "||") || tok.Is(lexer.Punct, "?") ||
+			tok.Is(lexer.Punct, "->"):
+			return ""
+		case tok.Is(lexer.Ident, "equals") && i > start && w.tokens[i-1].Is(lexer.Punct, "."):
+			if w.tokens[start].Is(lexer.Punct, "!") {
+				*negated ^= 1
+			}
+
+			return "equals"
+		case tok.Kind == lexer.Punct && javaComparisonMatchers[tok.Text].assertj[0] != "":
+			if w.tokens[i-1].Is(lexer.Ident, "null") || i+1 < end && w.tokens[i+1].Is(lexer.Ident, "null") {
+				if tok.Text == "!=" {
+					*negated ^= 1
+				}
+
+				return "null"
+			}
+
+			return tok.Text
+		}
+	}
+
+	return ""
+}
+
+// recordFieldMocks records @Mock-style field declarations.
+func (w *javaWalker) recordFieldMocks(annotations []javaAnnotation, declStart, declEnd int) {
+	for _, a := range annotations {
+		if !javaMockAnnotations[a.name] {
+			continue
+		}
+
+		target := ""
+
+		// The field type is the first identifier that is not a modifier
+		for k := declStart; k < declEnd; k++ {
+			tok := w.tokens[k]
+			if tok.Kind == lexer.Ident && !javaModifiers[tok.Text] {
+				target = tok.Text
+				break
+			}
+		}
+
+		w.mocks = append(w.mocks, types.MockUsage{API: "@" + a.name, Target: target, Line: a.line})
+	}
+}
+
+// javaModifiers are declaration modifiers that precede a member's type.
+var javaModifiers = map[string]bool{
+	"public": true, "protected": true, "private": true, "static": true,
+	"final": true, "abstract": true, "transient": true, "volatile": true, "synchronized": true,
+}
+
+func (w *javaWalker) hasModifier(start, end int, word string) bool {
+	for k := start; k < end; k++ {
+		if w.tokens[k].Is(lexer.Ident, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipTo returns the index of the first token at bracket depth zero whose
+// text is one of stops, starting at k. Nested brackets are skipped.
+func (w *javaWalker) skipTo(k, end int, stops ...string) int {
+	for ; k < end; k++ {
+		tok := w.tokens[k]
+		if tok.Kind != lexer.Punct {
+			continue
+		}
+
+		for _, s := range stops {
+			if tok.Text == s {
+				return k
+			}
+		}
+
+		if tok.Text == "(" || tok.Text == "[" || tok.Text == "{" {
+			k = lexer.Match(w.tokens, k)
+		}
+	}
+
+	return end
+}
@@ -0,0 +1,307 @@
+package inventory
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const junit5Class = `package com.acme.orders;
+
+import static org.assertj.core.api.Assertions.assertThat;
+import static org.junit.jupiter.api.Assertions.assertThrows;
+
+import org.junit.jupiter.api.*;
+import org.junit.jupiter.params.ParameterizedTest;
+import org.junit.jupiter.params.provider.*;
+import org.mockito.Mock;
+
+@Tag("unit")
+class OrderServiceTest {
+
+    @Mock
+    private PaymentGateway gateway;
+
+    private final OrderService service = new OrderService(() -> { return 1; });
+
+    @BeforeAll
+    static void initAll() {}
+
+    @BeforeEach
+    void init() {}
+
+    @Test
+    @DisplayName("places an order")
+    void placesOrder() {
+        Order order = service.place("sku-1");
+        assertThat(order.id()).isNotNull();
+        assertThat(order.total()).isEqualTo(10);
+    }
+
+    @ParameterizedTest
+    @ValueSource(ints = {1, 2, 3})
+    void acceptsQuantities(int quantity) {
+        assertThat(service.accepts(quantity)).isTrue();
+    }
+
+    @ParameterizedTest(name = "{0} costs {1}")
+    @CsvSource({"apple, 1", "pear, 2"})
+    void prices(String fruit, int price) {}
+
+    @ParameterizedTest
+    @MethodSource("orders")
+    void validates(Order order) {}
+
+    @RepeatedTest(5)
+    void isStable() {}
+
+    @Test
+    @Disabled("flaky on CI")
+    void rejectsEmptyCart() {
+        assertThrows(IllegalArgumentException.class, () -> service.place(""));
+    }
+
+    @Nested
+    class WhenCancelled {
+        @Test
+        void refunds() {}
+    }
+
+    static Stream<Order> orders() { return Stream.empty(); }
+
+    @AfterEach
+    void tearDown() {}
+}
+`
+
+//nolint:gocognit,gocyclo // Verifies the full tree of a representative test class
+func TestJavaParser_JUnit5(t *testing.T) {
+	file, err := NewJavaParser().Parse("src/test/java/com/acme/orders/OrderServiceTest.java", []byte(junit5Class))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Framework != "junit5" || file.Language != types.LanguageJava {
+		t.Errorf("Framework = %q, Language = %v", file.Framework, file.Language)
+	}
+
+	if len(file.Tests) != 1 {
+		t.Fatalf("top-level entries = %d, want 1", len(file.Tests))
+	}
+
+	suite := file.Tests[0]
+	if suite.Name != "OrderServiceTest" || suite.Kind != types.TestKindSuite || len(suite.Tags) != 1 {
+		t.Errorf("suite = %q kind=%s tags=%v", suite.Name, suite.Kind, suite.Tags)
+	}
+
+	if len(suite.Hooks) != 3 {
+		t.Errorf("hooks = %+v, want 3", suite.Hooks)
+	}
+
+	byName := map[string]types.TestCase{}
+	for _, c := range suite.Children {
+		byName[c.Name] = c
+	}
+
+	if len(byName) != 7 {
+		t.Errorf("children = %d, want 7: %v", len(byName), byName)
+	}
+
+	if c := byName["placesOrder"]; c.Kind != types.TestKindTest || c.Assertions != 2 || c.Line != 25 {
+		t.Errorf("placesOrder = kind %s, %d assertions, line %d", c.Kind, c.Assertions, c.Line)
+	}
+
+	if c := byName["acceptsQuantities"]; c.Kind != types.TestKindParameterized || c.ParameterCases != 3 ||
+		len(c.ParameterSources) != 1 || c.ParameterSources[0] != "@ValueSource" {
+		t.Errorf("acceptsQuantities = %+v", c)
+	}
+
+	if c := byName["prices"]; c.ParameterCases != 2 {
+		t.Errorf("prices cases = %d, want 2", c.ParameterCases)
+	}
+
+	if c := byName["validates"]; c.ParameterCases != 0 || c.ParameterSources[0] != "@MethodSource" {
+		t.Errorf("validates = %+v", c)
+	}
+
+	if c := byName["isStable"]; c.ParameterCases != 5 {
+		t.Errorf("isStable cases = %d, want 5", c.ParameterCases)
+	}
+
+	if c := byName["rejectsEmptyCart"]; !c.Skipped {
+		t.Error("rejectsEmptyCart should be skipped")
+	}
+
+	nested := byName["WhenCancelled"]
+	if nested.Kind != types.TestKindSuite || len(nested.Children) != 1 {
+		t.Fatalf("WhenCancelled = %+v", nested)
+	}
+
+	if want := "src/test/java/com/acme/orders/OrderServiceTest.java::OrderServiceTest::WhenCancelled::refunds"; nested.Children[0].ID != want {
+		t.Errorf("nested ID = %q, want %q", nested.Children[0].ID, want)
+	}
+
+	if file.AssertionStyles["assertj"] != 3 || file.AssertionStyles["junit"] != 1 {
+		t.Errorf("AssertionStyles = %v, want assertj:3 junit:1", file.AssertionStyles)
+	}
+
+	if len(file.Mocks) != 1 || file.Mocks[0].API != "@Mock" || file.Mocks[0].Target != "PaymentGateway" {
+		t.Errorf("Mocks = %+v", file.Mocks)
+	}
+}
+
+func TestJavaParser_TestNG(t *testing.T) {
+	src := `import org.testng.annotations.*;
+import static org.testng.Assert.assertEquals;
+
+@Test(groups = {"integration"})
+public class InventoryTest {
+    @BeforeMethod
+    public void setUp() {}
+
+    public void countsStock() { assertEquals(1, 1); }
+
+    @Test(groups = {"slow", "db"}, enabled = false)
+    public void syncsWarehouse() {}
+
+    @Test(dataProvider = "skus")
+    public void looksUp(String sku) {}
+
+    private void helper() {}
+}
+`
+
+	file, err := NewJavaParser().Parse("InventoryTest.java", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Framework != "testng" {
+		t.Errorf("Framework = %q, want testng", file.Framework)
+	}
+
+	suite := file.Tests[0]
+	if len(suite.Children) != 3 {
+		t.Fatalf("children = %+v, want 3", suite.Children)
+	}
+
+	counts, syncs, lookup := suite.Children[0], suite.Children[1], suite.Children[2]
+
+	if counts.Name != "countsStock" || len(counts.Tags) != 1 || counts.Tags[0] != "integration" {
+		t.Errorf("countsStock = %+v", counts)
+	}
+
+	if !syncs.Skipped || len(syncs.Tags) != 3 {
+		t.Errorf("syncsWarehouse skipped=%v tags=%v", syncs.Skipped, syncs.Tags)
+	}
+
+	if lookup.Kind != types.TestKindParameterized || lookup.ParameterSources[0] != "dataProvider:skus" {
+		t.Errorf("looksUp = %+v", lookup)
+	}
+
+	if len(suite.Hooks) != 1 || suite.Hooks[0].Kind != types.HookBeforeEach {
+		t.Errorf("hooks = %+v", suite.Hooks)
+	}
+
+	if file.AssertionStyles["testng"] != 1 {
+		t.Errorf("AssertionStyles = %v", file.AssertionStyles)
+	}
+}
+
+func TestJavaParser_JUnit3And4(t *testing.T) {
+	src := `import junit.framework.TestCase;
+
+public class LegacyTest extends TestCase {
+    protected void setUp() {}
+    public void testAdds() { assertEquals(2, 1 + 1); }
+    public void helper() {}
+}
+`
+
+	file, err := NewJavaParser().Parse("LegacyTest.java", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	suite := file.Tests[0]
+	if len(suite.Children) != 1 || suite.Children[0].Name != "testAdds" {
+		t.Errorf("JUnit 3 children = %+v", suite.Children)
+	}
+
+	if len(suite.Hooks) != 1 || suite.Hooks[0].Name != "setUp" {
+		t.Errorf("JUnit 3 hooks = %+v", suite.Hooks)
+	}
+
+	src4 := `import org.junit.*;
+import static org.hamcrest.MatcherAssert.assertThat;
+
+@Ignore
+public class OldTest {
+    @Test public void a() { assertThat(1, is(1)); }
+}
+`
+
+	file, err = NewJavaParser().Parse("OldTest.java", []byte(src4))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Framework != "junit4" || !file.Tests[0].Children[0].Skipped {
+		t.Errorf("JUnit 4 file = %+v", file)
+	}
+
+	if file.AssertionStyles["hamcrest"] != 1 {
+		t.Errorf("AssertionStyles = %v, want hamcrest:1", file.AssertionStyles)
+	}
+}
+
+func TestJavaParser_WeakAssertions(t *testing.T) {
+	src := `package com.acme;
+
+import static org.assertj.core.api.Assertions.assertThat;
+import static org.junit.jupiter.api.Assertions.*;
+
+import org.junit.jupiter.api.Test;
+
+class LoaderTest {
+    @Test
+    void booleans() {
+        assertTrue(load() == 1);
+        assertFalse(name().equals("x"), "unexpected name");
+        assertTrue("positive", load() > 0);
+        assertTrue(result() != null);
+        assertThat(!name().equals("x")).isTrue();
+        assertTrue(ready());
+        assertEquals(1, load());
+    }
+}
+`
+
+	file, err := NewJavaParser().Parse("src/test/java/com/acme/LoaderTest.java", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	checkWeakAssertions(t, file, map[string]string{
+		"booleans": "11 boolean-comparison assertTrue -> assertThat(actual).isEqualTo(expected); " +
+			"12 boolean-comparison assertFalse -> assertThat(actual).isNotEqualTo(expected); " +
+			"13 boolean-comparison assertTrue -> assertThat(actual).isGreaterThan(expected); " +
+			"14 boolean-comparison assertTrue -> assertThat(actual).isNotNull(); " +
+			"15 boolean-comparison assertThat -> assertThat(actual).isNotEqualTo(expected)",
+	})
+
+	file, err = NewJavaParser().Parse("LoaderTest.java", []byte(strings.Replace(src,
+		"import static org.assertj.core.api.Assertions.assertThat;\n", "", 1)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	checkWeakAssertions(t, file, map[string]string{
+		"booleans": "10 boolean-comparison assertTrue -> assertEquals(expected, actual); " +
+			"11 boolean-comparison assertFalse -> assertNotEquals(expected, actual); " +
+			"12 boolean-comparison assertTrue -> assertThat(actual).isGreaterThan(expected); " +
+			"13 boolean-comparison assertTrue -> assertNotNull(actual); " +
+			"14 boolean-comparison assertThat -> assertThat(actual).isNotEqualTo(expected)",
+	})
+}
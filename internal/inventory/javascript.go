@@ -0,0 +1,696 @@
+package inventory
+
+import (
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// jsSuiteFuncs are the suite constructors of Jest, Vitest and Mocha (BDD and TDD).
+var jsSuiteFuncs = map[string]bool{"describe": true, "context": true, "suite": true}
+
+// jsTestFuncs are the test constructors of Jest, Vitest and Mocha.
+var jsTestFuncs = map[string]bool{"it": true, "test": true, "specify": true}
+
+// jsHookFuncs maps hook functions onto language-neutral hook kinds.
+var jsHookFuncs = map[string]types.HookKind{
+	"beforeAll":  types.HookBeforeAll,
+	"before":     types.HookBeforeAll,
+	"beforeEach": types.HookBeforeEach,
+	"afterEach":  types.HookAfterEach,
+	"afterAll":   types.HookAfterAll,
+	"after":      types.HookAfterAll,
+}
+
+// jsMockAPIs are the module and object mocking calls that are recorded.
+var jsMockAPIs = map[string]bool{
+	"jest.mock": true, "jest.doMock": true, "jest.spyOn": true,
+	"vi.mock": true, "vi.doMock": true, "vi.spyOn": true,
+	"sinon.stub": true, "sinon.mock": true, "sinon.spy": true,
+}
+
+// JSSnapshotMatchers are the matchers that compare against stored snapshots.
+var JSSnapshotMatchers = map[string]bool{
+	"toMatchSnapshot":                    true,
+	"toMatchInlineSnapshot":              true,
+	"toMatchFileSnapshot":                true,
+	"toThrowErrorMatchingSnapshot":       true,
+	"toThrowErrorMatchingInlineSnapshot": true,
+}
+
+// JavaScriptParser parses Jest, Vitest and Mocha test files written in
+// JavaScript or TypeScript.
+type JavaScriptParser struct {
+	// DefaultFramework is reported when the framework cannot be inferred
+	// from the file itself (default: "jest").
+	DefaultFramework string
+}
+
+// NewJavaScriptParser creates a JavaScript/TypeScript test parser.
+func NewJavaScriptParser() *JavaScriptParser {
+	return &JavaScriptParser{DefaultFramework: "jest"}
+}
+
+// jsCall is a test-related call such as describe.only.each(table)(name, fn).
+type jsCall struct {
+	base      string
+	modifiers []string
+	// table is the token range of the .each table, if any
+	tableStart, tableEnd int
+	// args is the token range of the (name, fn) argument list, parentheses inclusive
+	argsOpen, argsClose int
+}
+
+func (c *jsCall) has(modifier string) bool {
+	for _, m := range c.modifiers {
+		if m == modifier {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Parse builds the inventory of a JavaScript or TypeScript test file.
+func (p *JavaScriptParser) Parse(relPath string, src []byte) (*types.TestFile, error) {
+	tokens := lexer.Tokenize(src, lexer.JavaScript)
+
+	lang := discovery.LanguageOf(relPath)
+	if lang != types.LanguageTypeScript {
+		lang = types.LanguageJavaScript
+	}
+
+	file := &types.TestFile{
+		Path:            relPath,
+		Language:        lang,
+		Framework:       p.detectFramework(tokens),
+		AssertionStyles: map[string]int{},
+	}
+
+	w := &jsWalker{tokens: tokens, file: relPath}
+	file.Tests, file.Hooks = w.parseRange(0, len(tokens), nil)
+	file.Mocks = jsMocks(tokens)
+
+	for i, tok := range tokens {
+		if tok.Kind == lexer.Ident && isCallee(tokens, i) && (tok.Text == "expect" || tok.Text == "assert") {
+			file.AssertionStyles[tok.Text]++
+		}
+	}
+
+	if len(file.AssertionStyles) == 0 {
+		file.AssertionStyles = nil
+	}
+
+	return file, nil
+}
+
+// detectFramework infers the framework from imports and global API usage.
+func (p *JavaScriptParser) detectFramework(tokens []lexer.Token) string {
+	for i, tok := range tokens {
+		switch tok.Kind {
+		case lexer.String:
+			switch lexer.Unquote(tok.Text) {
+			case "vitest":
+				return "vitest"
+			case "mocha", "chai", "sinon":
+				return "mocha"
+			case "@jest/globals":
+				return "jest"
+			}
+		case lexer.Ident:
+			if i+1 < len(tokens) && tokens[i+1].Is(lexer.Punct, ".") && !precededByDot(tokens, i) {
+				switch tok.Text {
+				case "vi":
+					return "vitest"
+				case "jest":
+					return "jest"
+				}
+			}
+		}
+	}
+
+	if p.DefaultFramework == "" {
+		return "jest"
+	}
+
+	return p.DefaultFramework
+}
+
+type jsWalker struct {
+	tokens []lexer.Token
+	file   string
+}
+
+// parseRange extracts suites, tests and hooks from tokens[start:end].
+// Calls nested inside arbitrary code (loops, conditionals, helpers) are
+// found as well, since the scan descends into every token.
+func (w *jsWalker) parseRange(start, end int, parents []string) ([]types.TestCase, []types.TestHook) {
+	var (
+		cases []types.TestCase
+		hooks []types.TestHook
+	)
+
+	for i := start; i < end; i++ {
+		tok := w.tokens[i]
+		if tok.Kind != lexer.Ident || precededByDot(w.tokens, i) || precededByKeyword(w.tokens, i) {
+			continue
+		}
+
+		if kind, ok := jsHookFuncs[tok.Text]; ok && i+1 < end && w.tokens[i+1].Is(lexer.Punct, "(") {
+			hooks = append(hooks, types.TestHook{Kind: kind, Name: tok.Text, Line: tok.Line})
+			i = lexer.Match(w.tokens, i+1)
+
+			continue
+		}
+
+		call, ok := w.parseCall(i, end)
+		if !ok {
+			continue
+		}
+
+		cases = append(cases, w.buildCase(call, tok.Line, parents))
+		i = call.argsClose
+	}
+
+	return cases, hooks
+}
+
+// parseCall recognizes a test or suite call starting at tokens[i].
+//
+//nolint:gocognit,gocyclo // Mirrors the grammar of chained test modifiers
+func (w *jsWalker) parseCall(i, end int) (jsCall, bool) {
+	name := w.tokens[i].Text
+
+	call := jsCall{tableStart: -1, tableEnd: -1}
+
+	switch {
+	case jsSuiteFuncs[name] || jsTestFuncs[name]:
+		call.base = name
+	case len(name) > 1 && (name[0] == 'x' || name[0] == 'f') &&
+		(jsSuiteFuncs[name[1:]] || jsTestFuncs[name[1:]]):
+		// Jasmine-style aliases: xit, xdescribe, fit, fdescribe, xtest
+		call.base = name[1:]
+		if name[0] == 'x' {
+			call.modifiers = append(call.modifiers, "skip")
+		} else {
+			call.modifiers = append(call.modifiers, "only")
+		}
+	default:
+		return call, false
+	}
+
+	j := i + 1
+	for j+1 < end && w.tokens[j].Is(lexer.Punct, ".") && w.tokens[j+1].Kind == lexer.Ident {
+		call.modifiers = append(call.modifiers, w.tokens[j+1].Text)
+		j += 2
+	}
+
+	if j >= end {
+		return call, false
+	}
+
+	// Tagged template table: test.each`a | b ...`(name, fn)
+	if call.has("each") && w.tokens[j].Kind == lexer.String {
+		call.tableStart, call.tableEnd = j, j
+		j++
+	}
+
+	if j >= end || !w.tokens[j].Is(lexer.Punct, "(") {
+		return call, false
+	}
+
+	closeIdx := lexer.Match(w.tokens, j)
+
+	// Curried forms: describe.each(table)(name, fn), it.skipIf(cond)(name, fn)
+	if (call.has("each") && call.tableStart < 0) || call.has("skipIf") || call.has("runIf") {
+		if closeIdx+1 < end && w.tokens[closeIdx+1].Is(lexer.Punct, "(") {
+			if call.has("each") {
+				call.tableStart, call.tableEnd = j+1, closeIdx-1
+			}
+
+			j = closeIdx + 1
+			closeIdx = lexer.Match(w.tokens, j)
+		}
+	}
+
+	call.argsOpen, call.argsClose = j, closeIdx
+
+	return call, true
+}
+
+// JSCallArgs recognizes the test or suite call starting at tokens[i], such
+// as it.only(...) or describe.each(table)(...), and returns the positions
+// of the parentheses of its (name, fn) argument list.
+func JSCallArgs(tokens []lexer.Token, i int) (int, int, bool) {
+	w := &jsWalker{tokens: tokens}
+	call, ok := w.parseCall(i, len(tokens))
+
+	return call.argsOpen, call.argsClose, ok
+}
+
+func (w *jsWalker) buildCase(call jsCall, line int, parents []string) types.TestCase {
+	name := w.argName(call.argsOpen+1, call.argsClose)
+	path := append(append([]string(nil), parents...), name)
+
+	tc := types.TestCase{
+		ID:      testID(w.file, path...),
+		Name:    name,
+		Kind:    types.TestKindTest,
+		File:    w.file,
+		Line:    line,
+		EndLine: w.tokens[call.argsClose].Line,
+		Skipped: call.has("skip"),
+		Focused: call.has("only"),
+		Todo:    call.has("todo"),
+	}
+
+	if call.has("each") {
+		tc.ParameterSources = []string{"each"}
+		tc.ParameterCases = w.countTableRows(call.tableStart, call.tableEnd)
+	}
+
+	if jsSuiteFuncs[call.base] {
+		tc.Kind = types.TestKindSuite
+		tc.Children, tc.Hooks = w.parseRange(call.argsOpen+1, call.argsClose, path)
+
+		return tc
+	}
+
+	if call.has("each") {
+		tc.Kind = types.TestKindParameterized
+	}
+
+	for k := call.argsOpen + 1; k < call.argsClose; k++ {
+		tok := w.tokens[k]
+		if tok.Kind != lexer.Ident {
+			continue
+		}
+
+		if (tok.Text == "expect" || tok.Text == "assert") && isCallee(w.tokens, k) {
+			tc.Assertions++
+
+			if wa, ok := w.weakAssertion(k, call.argsClose); ok {
+				tc.WeakAssertions = append(tc.WeakAssertions, wa)
+			}
+		}
+
+		if JSSnapshotMatchers[tok.Text] && precededByDot(w.tokens, k) {
+			tc.Snapshots++
+		}
+	}
+
+	// A test without a callback (it('does x')) is pending in Mocha and Jest
+	if !tc.Todo && !w.hasCallback(call.argsOpen+1, call.argsClose) {
+		tc.Todo = true
+	}
+
+	tc.Async = w.asyncStyle(call)
+
+	return tc
+}
+
+// jsComparisonMatchers maps comparison operators onto the Jest and Chai
+// matchers that check them, for positive and negated assertions.
+var jsComparisonMatchers = map[string]struct{ jest, chai [2]string }{
+	"===": {[2]string{"toBe", "not.toBe"}, [2]string{"equal", "not.equal"}},
+	"==":  {[2]string{"toBe", "not.toBe"}, [2]string{"equal", "not.equal"}},
+	"!==": {[2]string{"not.toBe", "toBe"}, [2]string{"not.equal", "equal"}},
+	"!=":  {[2]string{"not.toBe", "toBe"}, [2]string{"not.equal", "equal"}},
+	"<":   {[2]string{"toBeLessThan", "toBeGreaterThanOrEqual"}, [2]string{"be.below", "be.at.least"}},
+	"<=":  {[2]string{"toBeLessThanOrEqual", "toBeGreaterThan"}, [2]string{"be.at.most", "be.above"}},
+	">":   {[2]string{"toBeGreaterThan", "toBeLessThanOrEqual"}, [2]string{"be.above", "be.at.most"}},
+	">=":  {[2]string{"toBeGreaterThanOrEqual", "toBeLessThan"}, [2]string{"be.at.least", "be.below"}},
+}
+
+// weakAssertion reports truthiness checks (expect(x).toBeTruthy(),
+// expect(x).to.be.ok, assert(x)) and comparisons checked as booleans
+// (expect(a === b).toBe(true)) for the expect or assert call at tokens[k].
+//
+//nolint:gocognit,gocyclo // Jest, Chai and Node assertion chains
+func (w *jsWalker) weakAssertion(k, end int) (types.WeakAssertion, bool) {
+	tokens := w.tokens
+	line := tokens[k].Line
+
+	// assert(x), assert.ok(x) and Chai's assert.isTrue(x)
+	if tokens[k].Text == "assert" {
+		name, open := "assert", k+1
+
+		if tokens[k+1].Is(lexer.Punct, ".") && k+3 < end && tokens[k+3].Is(lexer.Punct, "(") {
+			name, open = "assert."+tokens[k+2].Text, k+3
+		}
+
+		if !tokens[open].Is(lexer.Punct, "(") || name != "assert" && name != "assert.ok" && name != "assert.isTrue" {
+			return types.WeakAssertion{}, false
+		}
+
+		wa := types.WeakAssertion{Kind: types.WeakTruthiness, Assertion: name, Line: line}
+
+		switch op := w.comparison(open+1, lexer.Match(tokens, open)); op {
+		case "":
+			wa.Suggestion = "assert.strictEqual(actual, expected)"
+		case "===", "==":
+			wa.Kind, wa.Suggestion = types.WeakBooleanComparison, "assert.strictEqual(actual, expected)"
+		case "!==", "!=":
+			wa.Kind, wa.Suggestion = types.WeakBooleanComparison, "assert.notStrictEqual(actual, expected)"
+		default:
+			return types.WeakAssertion{}, false
+		}
+
+		return wa, true
+	}
+
+	if !tokens[k+1].Is(lexer.Punct, "(") {
+		return types.WeakAssertion{}, false
+	}
+
+	argsClose := lexer.Match(tokens, k+1)
+	op := w.comparison(k+2, argsClose)
+
+	// Collect the matcher chain: .not.toBe(true), .to.be.ok
+	var chain []string
+
+	matcher, matcherArg := "", ""
+
+	for j := argsClose; j+2 < end && tokens[j+1].Is(lexer.Punct, ".") && tokens[j+2].Kind == lexer.Ident; {
+		chain = append(chain, tokens[j+2].Text)
+		matcher, matcherArg, j = tokens[j+2].Text, "", j+2
+
+		if j+1 < end && tokens[j+1].Is(lexer.Punct, "(") {
+			closeParen := lexer.Match(tokens, j+1)
+			if closeParen == j+3 {
+				matcherArg = tokens[j+2].Text
+			}
+
+			j = closeParen
+		}
+	}
+
+	negated := 0
+
+	for _, name := range chain {
+		if name == "not" {
+			negated ^= 1
+		}
+	}
+
+	chai := len(chain) > 0 && chain[0] == "to"
+
+	switch {
+	case matcher == "toBeTruthy", chai && matcher == "ok":
+	case matcher == "toBeFalsy":
+		negated ^= 1
+	case op != "" && (matcherArg == "true" || chai && matcher == "true" && matcherArg == ""):
+	case op != "" && (matcherArg == "false" || chai && matcher == "false" && matcherArg == ""):
+		negated ^= 1
+	default:
+		return types.WeakAssertion{}, false
+	}
+
+	wa := types.WeakAssertion{Kind: types.WeakTruthiness, Assertion: matcher, Line: line}
+
+	switch {
+	case op == "" && chai:
+		wa.Suggestion = "expect(actual).to.equal(expected)"
+	case op == "":
+		wa.Suggestion = "expect(actual).toEqual(expected)"
+	case chai:
+		wa.Kind = types.WeakBooleanComparison
+		wa.Suggestion = "expect(actual).to." + jsComparisonMatchers[op].chai[negated] + "(expected)"
+	default:
+		wa.Kind = types.WeakBooleanComparison
+		wa.Suggestion = "expect(actual)." + jsComparisonMatchers[op].jest[negated] + "(expected)"
+	}
+
+	return wa, true
+}
+
+// comparison returns the comparison operator of the expression in
+// tokens[start:end] outside brackets, or "" when there is none or the
+// expression is a function.
+func (w *jsWalker) comparison(start, end int) string {
+	op := ""
+
+	for i := start; i < end; i++ {
+		tok := w.tokens[i]
+		if tok.Kind != lexer.Punct {
+			continue
+		}
+
+		switch {
+		case tok.Text == "(" || tok.Text == "[" || tok.Text == "{":
+			i = lexer.Match(w.tokens, i)
+		case tok.Text == "=>" || tok.Text == "," || tok.Text == "&&" || tok.Text == "||" || tok.Text == "?":
+			return ""
+		case op == "" && jsComparisonMatchers[tok.Text].jest[0] != "":
+			op = tok.Text
+		}
+	}
+
+	return op
+}
+
+// argName returns the test name from the first argument in tokens[start:end].
+// Non-literal names (e.g. MyComponent.name) are returned as source text.
+func (w *jsWalker) argName(start, end int) string {
+	if start < end && w.tokens[start].Kind == lexer.String &&
+		(start+1 == end || w.tokens[start+1].Is(lexer.Punct, ",")) {
+		return lexer.Unquote(w.tokens[start].Text)
+	}
+
+	var parts []string
+
+	for k := start; k < end; k++ {
+		tok := w.tokens[k]
+		if tok.Is(lexer.Punct, ",") {
+			break
+		}
+
+		if tok.Is(lexer.Punct, "(") || tok.Is(lexer.Punct, "[") || tok.Is(lexer.Punct, "{") {
+			closeIdx := lexer.Match(w.tokens, k)
+			for m := k; m <= closeIdx && m < end; m++ {
+				parts = append(parts, w.tokens[m].Text)
+			}
+
+			k = closeIdx
+
+			continue
+		}
+
+		parts = append(parts, tok.Text)
+	}
+
+	return strings.Join(parts, "")
+}
+
+// hasCallback reports whether the argument list contains a function argument.
+func (w *jsWalker) hasCallback(start, end int) bool {
+	for k := start; k < end; k++ {
+		tok := w.tokens[k]
+		if tok.Is(lexer.Punct, "=>") || tok.Is(lexer.Ident, "function") {
+			return true
+		}
+	}
+
+	// Named callbacks: it('x', runCase)
+	depth := 0
+	args := 1
+
+	for k := start; k < end; k++ {
+		tok := w.tokens[k]
+
+		switch {
+		case tok.Kind == lexer.Punct && (tok.Text == "(" || tok.Text == "[" || tok.Text == "{"):
+			depth++
+		case tok.Kind == lexer.Punct && (tok.Text == ")" || tok.Text == "]" || tok.Text == "}"):
+			depth--
+		case tok.Is(lexer.Punct, ",") && depth == 0 && k+1 < end:
+			args++
+		}
+	}
+
+	return args > 1
+}
+
+// asyncStyle classifies how the callback of a test waits for asynchronous
+// work: an async function, a done parameter or a promise chain. Parameters
+// of .each callbacks are table values, not done callbacks.
+func (w *jsWalker) asyncStyle(call jsCall) types.AsyncStyle {
+	start, end := w.lastArg(call.argsOpen+1, call.argsClose), call.argsClose
+	if start < 0 {
+		return ""
+	}
+
+	first := w.tokens[start]
+	if first.Is(lexer.Ident, "async") {
+		return types.AsyncAwait
+	}
+
+	params := false
+
+	switch {
+	case first.Is(lexer.Ident, "function"):
+		k := start + 1
+		if k < end && w.tokens[k].Kind == lexer.Ident {
+			k++
+		}
+
+		if k < end && w.tokens[k].Is(lexer.Punct, "(") {
+			params = lexer.Match(w.tokens, k) > k+1
+		}
+	case first.Is(lexer.Punct, "("):
+		closeIdx := lexer.Match(w.tokens, start)
+		params = closeIdx > start+1 && closeIdx+1 < end && w.tokens[closeIdx+1].Is(lexer.Punct, "=>")
+	case first.Kind == lexer.Ident:
+		params = start+1 < end && w.tokens[start+1].Is(lexer.Punct, "=>")
+	}
+
+	if params && !call.has("each") {
+		return types.AsyncCallback
+	}
+
+	for k := start; k < end; k++ {
+		if tok := w.tokens[k]; tok.Kind == lexer.Ident && precededByDot(w.tokens, k) &&
+			(tok.Text == "then" || tok.Text == "resolves" || tok.Text == "rejects") {
+			return types.AsyncPromise
+		}
+	}
+
+	return ""
+}
+
+// lastArg returns the index of the first token of the last argument in
+// tokens[start:end], or -1 when there is only one argument.
+func (w *jsWalker) lastArg(start, end int) int {
+	last := -1
+
+	for k := start; k < end; k++ {
+		tok := w.tokens[k]
+
+		switch {
+		case tok.Kind == lexer.Punct && (tok.Text == "(" || tok.Text == "[" || tok.Text == "{"):
+			k = lexer.Match(w.tokens, k)
+		case tok.Is(lexer.Punct, ",") && k+1 < end:
+			last = k + 1
+		}
+	}
+
+	return last
+}
+
+// countTableRows counts the cases of an .each table given as an array
+// literal or a tagged template. It returns 0 when the table is not static.
+func (w *jsWalker) countTableRows(start, end int) int {
+	if start < 0 || start > end {
+		return 0
+	}
+
+	tok := w.tokens[start]
+
+	if tok.Kind == lexer.String && strings.HasPrefix(tok.Text, "`") {
+		rows := 0
+
+		for _, line := range strings.Split(lexer.Unquote(tok.Text), "\n") {
+			if strings.TrimSpace(line) != "" {
+				rows++
+			}
+		}
+
+		// The first row is the header
+		if rows > 0 {
+			rows--
+		}
+
+		return rows
+	}
+
+	if !tok.Is(lexer.Punct, "[") {
+		return 0
+	}
+
+	closeIdx := lexer.Match(w.tokens, start)
+	if closeIdx == start+1 {
+		return 0
+	}
+
+	count := 1
+
+	for k := start + 1; k < closeIdx; k++ {
+		t := w.tokens[k]
+
+		switch {
+		case t.Kind == lexer.Punct && (t.Text == "(" || t.Text == "[" || t.Text == "{"):
+			k = lexer.Match(w.tokens, k)
+		case t.Is(lexer.Punct, ",") && k+1 < closeIdx:
+			count++
+		}
+	}
+
+	return count
+}
+
+// jsMocks records jest.mock, vi.mock, spyOn and sinon calls in the file.
+func jsMocks(tokens []lexer.Token) []types.MockUsage {
+	var mocks []types.MockUsage
+
+	for i := 0; i+3 < len(tokens); i++ {
+		if tokens[i].Kind != lexer.Ident || precededByDot(tokens, i) ||
+			!tokens[i+1].Is(lexer.Punct, ".") || tokens[i+2].Kind != lexer.Ident {
+			continue
+		}
+
+		api := tokens[i].Text + "." + tokens[i+2].Text
+		if !jsMockAPIs[api] || !tokens[i+3].Is(lexer.Punct, "(") {
+			continue
+		}
+
+		mock := types.MockUsage{API: api, Line: tokens[i].Line}
+
+		if i+4 < len(tokens) {
+			arg := tokens[i+4]
+
+			switch {
+			case arg.Kind == lexer.String:
+				mock.Target = lexer.Unquote(arg.Text)
+			case arg.Kind == lexer.Ident && strings.HasSuffix(api, ".spyOn") &&
+				i+6 < len(tokens) && tokens[i+5].Is(lexer.Punct, ",") && tokens[i+6].Kind == lexer.String:
+				mock.Target = arg.Text + "." + lexer.Unquote(tokens[i+6].Text)
+			case arg.Kind == lexer.Ident:
+				mock.Target = arg.Text
+			}
+		}
+
+		mocks = append(mocks, mock)
+	}
+
+	return mocks
+}
+
+// precededByDot reports whether tokens[i] is a property access (x.name).
+func precededByDot(tokens []lexer.Token, i int) bool {
+	return i > 0 && (tokens[i-1].Is(lexer.Punct, ".") || tokens[i-1].Is(lexer.Punct, "?."))
+}
+
+// precededByKeyword reports whether tokens[i] is being declared rather than called.
+func precededByKeyword(tokens []lexer.Token, i int) bool {
+	if i == 0 || tokens[i-1].Kind != lexer.Ident {
+		return false
+	}
+
+	switch tokens[i-1].Text {
+	case "function", "const", "let", "var", "class":
+		return true
+	}
+
+	return false
+}
+
+// isCallee reports whether tokens[i] is directly called or chained (expect(...), assert.equal).
+func isCallee(tokens []lexer.Token, i int) bool {
+	if precededByDot(tokens, i) || i+1 >= len(tokens) {
+		return false
+	}
+
+	return tokens[i+1].Is(lexer.Punct, "(") || tokens[i+1].Is(lexer.Punct, ".")
+}
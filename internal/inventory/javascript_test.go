@@ -0,0 +1,287 @@
+package inventory
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const jestSpec = `import { render } from '@testing-library/react';
+import Button from '../Button';
+
+jest.mock('../api');
+
+beforeAll(() => setup());
+
+describe('Button', () => {
+  beforeEach(() => {
+    jest.spyOn(console, 'error');
+  });
+
+  it('renders', () => {
+    const { container } = render(<Button />);
+    expect(container).toMatchSnapshot();
+  });
+
+  it.only('handles click', async () => {
+    expect(onClick).toHaveBeenCalled();
+    expect(onClick).toHaveBeenCalledTimes(1);
+  });
+
+  describe.skip('disabled', () => {
+    test('ignores click', () => {});
+  });
+
+  it.each([
+    [1, 1, 2],
+    [1, 2, 3],
+    [2, 2, 4],
+  ])('add(%i, %i) -> %i', (a, b, expected) => {
+    expect(add(a, b)).toBe(expected);
+  });
+
+  test.each` + "`" + `
+    a    | b    | expected
+    ${1} | ${1} | ${2}
+    ${2} | ${1} | ${3}
+  ` + "`" + `('returns $expected', ({ a, b, expected }) => {});
+
+  it.todo('supports keyboard');
+  xit('legacy behaviour', () => {});
+});
+`
+
+//nolint:gocognit,gocyclo // Verifies the full tree of a representative spec
+func TestJavaScriptParser_Parse(t *testing.T) {
+	parser := NewJavaScriptParser()
+
+	file, err := parser.Parse("src/__tests__/Button.test.tsx", []byte(jestSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Language != types.LanguageTypeScript {
+		t.Errorf("Language = %v, want TypeScript", file.Language)
+	}
+
+	if file.Framework != "jest" {
+		t.Errorf("Framework = %q, want jest", file.Framework)
+	}
+
+	if len(file.Hooks) != 1 || file.Hooks[0].Kind != types.HookBeforeAll {
+		t.Errorf("file hooks = %+v, want one beforeAll", file.Hooks)
+	}
+
+	if len(file.Mocks) != 2 {
+		t.Fatalf("Mocks = %+v, want 2", file.Mocks)
+	}
+
+	if file.Mocks[0].API != "jest.mock" || file.Mocks[0].Target != "../api" {
+		t.Errorf("Mocks[0] = %+v, want jest.mock ../api", file.Mocks[0])
+	}
+
+	if file.Mocks[1].API != "jest.spyOn" || file.Mocks[1].Target != "console.error" {
+		t.Errorf("Mocks[1] = %+v, want jest.spyOn console.error", file.Mocks[1])
+	}
+
+	if len(file.Tests) != 1 {
+		t.Fatalf("top-level tests = %d, want 1", len(file.Tests))
+	}
+
+	suite := file.Tests[0]
+	if suite.Kind != types.TestKindSuite || suite.Name != "Button" || suite.Line != 8 {
+		t.Errorf("suite = %s %q line %d, want suite Button line 8", suite.Kind, suite.Name, suite.Line)
+	}
+
+	if len(suite.Hooks) != 1 || suite.Hooks[0].Kind != types.HookBeforeEach {
+		t.Errorf("suite hooks = %+v, want one beforeEach", suite.Hooks)
+	}
+
+	byName := map[string]types.TestCase{}
+	for _, c := range suite.Children {
+		byName[c.Name] = c
+	}
+
+	if c := byName["renders"]; c.Snapshots != 1 || c.Assertions != 1 {
+		t.Errorf("renders: snapshots=%d assertions=%d, want 1/1", c.Snapshots, c.Assertions)
+	}
+
+	if c := byName["handles click"]; !c.Focused || c.Assertions != 2 {
+		t.Errorf("handles click: focused=%v assertions=%d, want true/2", c.Focused, c.Assertions)
+	}
+
+	disabled := byName["disabled"]
+	if !disabled.Skipped || disabled.Kind != types.TestKindSuite || len(disabled.Children) != 1 {
+		t.Errorf("disabled suite = %+v", disabled)
+	} else if want := "src/__tests__/Button.test.tsx::Button::disabled::ignores click"; disabled.Children[0].ID != want {
+		t.Errorf("nested ID = %q, want %q", disabled.Children[0].ID, want)
+	}
+
+	if c := byName["add(%i, %i) -> %i"]; c.Kind != types.TestKindParameterized || c.ParameterCases != 3 {
+		t.Errorf("it.each: kind=%s cases=%d, want parameterized/3", c.Kind, c.ParameterCases)
+	}
+
+	if c := byName["returns $expected"]; c.ParameterCases != 2 {
+		t.Errorf("test.each template: cases=%d, want 2", c.ParameterCases)
+	}
+
+	if c := byName["supports keyboard"]; !c.Todo {
+		t.Error("it.todo should be marked todo")
+	}
+
+	if c := byName["legacy behaviour"]; !c.Skipped {
+		t.Error("xit should be marked skipped")
+	}
+
+	if file.AssertionStyles["expect"] != 4 {
+		t.Errorf("AssertionStyles[expect] = %d, want 4", file.AssertionStyles["expect"])
+	}
+}
+
+func TestJavaScriptParser_Framework(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"vitest import", `import { describe, it, vi } from 'vitest';`, "vitest"},
+		{"vi global", `vi.mock('./db');`, "vitest"},
+		{"mocha with chai", `const { expect } = require('chai');`, "mocha"},
+		{"jest global", `jest.useFakeTimers();`, "jest"},
+		{"no hints", `it('x', () => {});`, "jest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := NewJavaScriptParser().Parse("a.test.js", []byte(tt.src))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if file.Framework != tt.want {
+				t.Errorf("Framework = %q, want %q", file.Framework, tt.want)
+			}
+		})
+	}
+}
+
+func TestJavaScriptParser_IgnoresNonTestCalls(t *testing.T) {
+	src := `
+const re = /it\(/;
+if (re.test('it(')) {}
+function it(name) {}
+const message = "describe('not a suite', () => {})";
+describe.each([['a'], ['b']])('suite %s', (v) => {
+  for (const n of [1, 2]) {
+    it(` + "`case ${n}`" + `, () => { expect(v).toBeDefined(); });
+  }
+});
+`
+
+	file, err := NewJavaScriptParser().Parse("loop.spec.js", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(file.Tests) != 1 {
+		t.Fatalf("top-level tests = %d, want 1: %+v", len(file.Tests), file.Tests)
+	}
+
+	suite := file.Tests[0]
+	if suite.ParameterCases != 2 || suite.Kind != types.TestKindSuite {
+		t.Errorf("describe.each: kind=%s cases=%d, want suite/2", suite.Kind, suite.ParameterCases)
+	}
+
+	if len(suite.Children) != 1 || suite.Children[0].Name != "case ${n}" {
+		t.Errorf("children = %+v, want one templated test", suite.Children)
+	}
+
+	if leaves := file.EffectiveLeaves(); len(leaves) != 1 || leaves[0].ParameterCases != 2 {
+		t.Errorf("EffectiveLeaves() = %+v, want the nested it run for 2 cases", leaves)
+	}
+}
+
+func TestJavaScriptParser_AsyncStyle(t *testing.T) {
+	src := `
+it('awaits', async () => { await load(); });
+it('awaits in a function', async function () { await load(); });
+it('calls done', (done) => { load(done); });
+it('calls done in a function', function (done) { load(done); });
+it('returns a promise', () => load().then((v) => expect(v).toBe(1)));
+it('returns resolves', () => { return expect(load()).resolves.toBe(1); });
+it('is synchronous', () => { expect(1).toBe(1); });
+it.each([[1], [2]])('uses table value %s', (n) => { expect(n).toBeGreaterThan(0); });
+it('is pending');
+`
+
+	file, err := NewJavaScriptParser().Parse("load.test.js", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]types.AsyncStyle{
+		"awaits":                   types.AsyncAwait,
+		"awaits in a function":     types.AsyncAwait,
+		"calls done":               types.AsyncCallback,
+		"calls done in a function": types.AsyncCallback,
+		"returns a promise":        types.AsyncPromise,
+		"returns resolves":         types.AsyncPromise,
+		"is synchronous":           "",
+		"uses table value %s":      "",
+		"is pending":               "",
+	}
+
+	if len(file.Tests) != len(want) {
+		t.Fatalf("tests = %d, want %d", len(file.Tests), len(want))
+	}
+
+	for _, tc := range file.Tests {
+		if tc.Async != want[tc.Name] {
+			t.Errorf("%s: Async = %q, want %q", tc.Name, tc.Async, want[tc.Name])
+		}
+	}
+}
+
+func TestJavaScriptParser_WeakAssertions(t *testing.T) {
+	src := `
+it('uses jest', () => {
+  expect(load()).toBeTruthy();
+  expect(load() === 1).toBe(true);
+  expect(load() > 2).not.toBeTruthy();
+  expect(load() !== 1).toEqual(false);
+  expect(items()).toBeFalsy();
+  expect(load()).toBe(1);
+  expect(ready()).toBe(true);
+  expect(() => load() === 1).toBeTruthy();
+});
+
+it('uses chai and node', () => {
+  expect(load()).to.be.ok;
+  expect(load() === 1).to.be.true;
+  expect(load() < 2).to.be.false;
+  assert(load());
+  assert.ok(load() === 1);
+  assert.strictEqual(load(), 1);
+  assert(load() > 0);
+});
+`
+
+	file, err := NewJavaScriptParser().Parse("load.test.js", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	checkWeakAssertions(t, file, map[string]string{
+		"uses jest": "3 truthiness toBeTruthy -> expect(actual).toEqual(expected); " +
+			"4 boolean-comparison toBe -> expect(actual).toBe(expected); " +
+			"5 boolean-comparison toBeTruthy -> expect(actual).toBeLessThanOrEqual(expected); " +
+			"6 boolean-comparison toEqual -> expect(actual).toBe(expected); " +
+			"7 truthiness toBeFalsy -> expect(actual).toEqual(expected); " +
+			"10 truthiness toBeTruthy -> expect(actual).toEqual(expected)",
+		"uses chai and node": "14 truthiness ok -> expect(actual).to.equal(expected); " +
+			"15 boolean-comparison true -> expect(actual).to.equal(expected); " +
+			"16 boolean-comparison false -> expect(actual).to.be.at.least(expected); " +
+			"17 truthiness assert -> assert.strictEqual(actual, expected); " +
+			"18 boolean-comparison assert.ok -> assert.strictEqual(actual, expected)",
+	})
+}
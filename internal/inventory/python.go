@@ -0,0 +1,867 @@
+package inventory
+
+import (
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// pyHookMethods maps unittest and pytest xunit-style setup functions onto hook kinds.
+var pyHookMethods = map[string]types.HookKind{
+	"setUpClass":        types.HookBeforeAll,
+	"setup_class":       types.HookBeforeAll,
+	"setUpModule":       types.HookBeforeAll,
+	"setup_module":      types.HookBeforeAll,
+	"setUp":             types.HookBeforeEach,
+	"setup_method":      types.HookBeforeEach,
+	"setup_function":    types.HookBeforeEach,
+	"tearDown":          types.HookAfterEach,
+	"teardown_method":   types.HookAfterEach,
+	"teardown_function": types.HookAfterEach,
+	"tearDownClass":     types.HookAfterAll,
+	"teardown_class":    types.HookAfterAll,
+	"tearDownModule":    types.HookAfterAll,
+	"teardown_module":   types.HookAfterAll,
+}
+
+// pyMockAPIs are the patching helpers recorded as mocks.
+var pyMockAPIs = map[string]bool{
+	"patch": true, "mock.patch": true, "unittest.mock.patch": true,
+	"patch.object": true, "mock.patch.object": true,
+	"mocker.patch": true, "mocker.patch.object": true, "mocker.spy": true,
+	"monkeypatch.setattr": true, "monkeypatch.setenv": true,
+}
+
+// PythonParser parses pytest and unittest test modules.
+type PythonParser struct{}
+
+// NewPythonParser creates a Python test parser.
+func NewPythonParser() *PythonParser {
+	return &PythonParser{}
+}
+
+// pyLine is a logical source line: the tokens of one statement, with
+// bracketed continuations joined.
+type pyLine struct {
+	tokens []lexer.Token
+	indent int
+	line   int
+}
+
+// pyDecorator is a parsed decorator such as @pytest.mark.parametrize(...).
+type pyDecorator struct {
+	name   string
+	tokens []lexer.Token
+}
+
+// Parse builds the inventory of a Python test module.
+func (p *PythonParser) Parse(relPath string, src []byte) (*types.TestFile, error) {
+	tokens := lexer.Tokenize(src, lexer.Python)
+	lines := pyLogicalLines(tokens)
+
+	w := &pyWalker{lines: lines, file: relPath, styles: map[string]int{}}
+
+	file := &types.TestFile{
+		Path:      relPath,
+		Language:  types.LanguagePython,
+		Framework: pyFramework(tokens),
+	}
+
+	file.Tests, file.Hooks = w.parseBlock(0, len(lines), -1, nil, false)
+	file.Mocks = w.mocks
+
+	if len(w.styles) > 0 {
+		file.AssertionStyles = w.styles
+	}
+
+	return file, nil
+}
+
+func pyLogicalLines(tokens []lexer.Token) []pyLine {
+	var (
+		lines   []pyLine
+		current []lexer.Token
+		depth   int
+	)
+
+	for i, tok := range tokens {
+		if len(current) > 0 && depth == 0 && tok.Line != tokens[i-1].Line &&
+			!tokens[i-1].Is(lexer.Punct, "\\") && !endsMultiline(tokens[i-1], tok) {
+			lines = append(lines, pyLine{tokens: current, indent: current[0].Col, line: current[0].Line})
+			current = nil
+		}
+
+		if tok.Kind == lexer.Punct {
+			switch tok.Text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				if depth > 0 {
+					depth--
+				}
+			case "\\":
+				continue
+			}
+		}
+
+		current = append(current, tok)
+	}
+
+	if len(current) > 0 {
+		lines = append(lines, pyLine{tokens: current, indent: current[0].Col, line: current[0].Line})
+	}
+
+	return lines
+}
+
+// endsMultiline reports whether tok continues a line whose previous token is
+// a multi-line string (the next token starts on the string's last line).
+func endsMultiline(prev, tok lexer.Token) bool {
+	return prev.Kind == lexer.String && prev.Line+strings.Count(prev.Text, "\n") == tok.Line
+}
+
+func pyFramework(tokens []lexer.Token) string {
+	unittest := false
+
+	for _, tok := range tokens {
+		if tok.Kind != lexer.Ident {
+			continue
+		}
+
+		switch tok.Text {
+		case "pytest":
+			return "pytest"
+		case "unittest", "TestCase":
+			unittest = true
+		}
+	}
+
+	if unittest {
+		return "unittest"
+	}
+
+	return "pytest"
+}
+
+type pyWalker struct {
+	lines  []pyLine
+	file   string
+	styles map[string]int
+	mocks  []types.MockUsage
+}
+
+// blockEnd returns the index of the first line after the block opened at
+// lines[start] (i.e. the first line indented at or left of it).
+func (w *pyWalker) blockEnd(start int) int {
+	indent := w.lines[start].indent
+
+	end := start + 1
+	for end < len(w.lines) && w.lines[end].indent > indent {
+		end++
+	}
+
+	return end
+}
+
+// parseBlock parses test classes and functions among lines[start:end] that
+// are indented exactly one level deeper than parentIndent.
+//
+//nolint:gocognit,gocyclo // Statement dispatch over classes, functions and decorators
+func (w *pyWalker) parseBlock(start, end, parentIndent int, parents []string,
+	inTestClass bool) ([]types.TestCase, []types.TestHook) {
+	var (
+		cases      []types.TestCase
+		hooks      []types.TestHook
+		decorators []pyDecorator
+	)
+
+	blockIndent := -1
+
+	for i := start; i < end; i++ {
+		l := w.lines[i]
+		if l.indent <= parentIndent {
+			break
+		}
+
+		if blockIndent < 0 {
+			blockIndent = l.indent
+		}
+
+		if l.indent != blockIndent {
+			continue
+		}
+
+		toks := l.tokens
+
+		if toks[0].Is(lexer.Punct, "@") {
+			decorators = append(decorators, pyParseDecorator(toks[1:]))
+			w.recordDecoratorMock(decorators[len(decorators)-1], l.line)
+
+			continue
+		}
+
+		head := toks
+		if len(head) > 1 && head[0].Is(lexer.Ident, "async") {
+			head = head[1:]
+		}
+
+		switch {
+		case head[0].Is(lexer.Ident, "class") && len(head) > 1:
+			bodyEnd := w.blockEnd(i)
+			name := head[1].Text
+
+			if isTestClass(name, head) {
+				suite := w.buildClass(name, l, decorators, i, bodyEnd, parents)
+				if len(suite.Children) > 0 || len(suite.Hooks) > 0 {
+					cases = append(cases, suite)
+				}
+			}
+
+			i = bodyEnd - 1
+		case head[0].Is(lexer.Ident, "def") && len(head) > 1:
+			bodyEnd := w.blockEnd(i)
+			name := head[1].Text
+
+			switch {
+			case strings.HasPrefix(name, "test") && (inTestClass || len(parents) == 0):
+				cases = append(cases, w.buildTest(name, l, decorators, i, bodyEnd, parents))
+			case pyHookMethods[name] != "":
+				hooks = append(hooks, types.TestHook{Kind: pyHookMethods[name], Name: name, Line: l.line})
+			case pyIsAutouseFixture(decorators):
+				hooks = append(hooks, types.TestHook{Kind: types.HookBeforeEach, Name: "fixture:" + name, Line: l.line})
+			}
+
+			w.scanBody(i+1, bodyEnd, nil)
+			i = bodyEnd - 1
+		default:
+			w.scanBody(i, i+1, nil)
+		}
+
+		decorators = nil
+	}
+
+	return cases, hooks
+}
+
+// isTestClass applies pytest (Test*) and unittest (TestCase subclass) rules.
+func isTestClass(name string, head []lexer.Token) bool {
+	if strings.HasPrefix(name, "Test") {
+		return true
+	}
+
+	for _, tok := range head[2:] {
+		if tok.Kind == lexer.Ident && strings.HasSuffix(tok.Text, "TestCase") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (w *pyWalker) buildClass(name string, l pyLine, decorators []pyDecorator, i, bodyEnd int,
+	parents []string) types.TestCase {
+	path := append(append([]string(nil), parents...), name)
+	suite := types.TestCase{
+		ID:      testID(w.file, path...),
+		Name:    name,
+		Kind:    types.TestKindSuite,
+		File:    w.file,
+		Line:    l.line,
+		EndLine: w.lines[bodyEnd-1].line,
+	}
+
+	w.applyDecorators(&suite, decorators)
+	suite.Children, suite.Hooks = w.parseBlock(i+1, bodyEnd, l.indent, path, true)
+
+	if suite.Skipped {
+		for c := range suite.Children {
+			markSkipped(&suite.Children[c])
+		}
+	}
+
+	return suite
+}
+
+func (w *pyWalker) buildTest(name string, l pyLine, decorators []pyDecorator, i, bodyEnd int,
+	parents []string) types.TestCase {
+	path := append(append([]string(nil), parents...), name)
+	tc := types.TestCase{
+		ID:      testID(w.file, path...),
+		Name:    name,
+		Kind:    types.TestKindTest,
+		File:    w.file,
+		Line:    l.line,
+		EndLine: w.lines[bodyEnd-1].line,
+	}
+
+	w.applyDecorators(&tc, decorators)
+
+	tc.Fixtures = pyFixtures(l.tokens, decorators)
+	tc.Assertions, tc.WeakAssertions = w.countAssertions(i+1, bodyEnd)
+
+	// A first statement of self.skipTest(...) or pytest.skip(...) skips the test
+	if i+1 < bodyEnd {
+		first := w.lines[i+1].tokens
+		if pyCallName(first) == "self.skipTest" || pyCallName(first) == "pytest.skip" {
+			tc.Skipped = true
+		}
+	}
+
+	return tc
+}
+
+// applyDecorators interprets pytest marks and unittest decorators.
+func (w *pyWalker) applyDecorators(tc *types.TestCase, decorators []pyDecorator) {
+	for _, d := range decorators {
+		switch d.name {
+		case "pytest.mark.skip", "unittest.skip", "skip":
+			tc.Skipped = true
+		case "pytest.mark.parametrize":
+			applyParameters(tc, pyParametrizeCases(d.tokens), "parametrize")
+		case "parameterized", "parameterized.expand", "parameterized.parameterized.expand":
+			cases := 0
+			if len(d.tokens) > 1 {
+				cases = pyLiteralLength(d.tokens, 1)
+			}
+
+			applyParameters(tc, cases, "parameterized")
+		default:
+			if mark, ok := strings.CutPrefix(d.name, "pytest.mark."); ok {
+				tc.Tags = append(tc.Tags, mark)
+			}
+		}
+	}
+}
+
+// applyParameters records a parameter source of a test. Stacked
+// parametrize decorators produce the cartesian product of their cases.
+func applyParameters(tc *types.TestCase, cases int, source string) {
+	if tc.ParameterCases == 0 {
+		tc.ParameterCases = cases
+	} else {
+		tc.ParameterCases *= cases
+	}
+
+	tc.ParameterSources = append(tc.ParameterSources, source)
+
+	if tc.Kind != types.TestKindSuite {
+		tc.Kind = types.TestKindParameterized
+	}
+}
+
+// pyFixtures returns the parameters of a test function that pytest resolves
+// as fixtures: every named parameter except self, cls, *args, **kwargs and
+// the argument names of parametrize decorators.
+func pyFixtures(def []lexer.Token, decorators []pyDecorator) []string {
+	open := -1
+
+	for k := range def {
+		if def[k].Is(lexer.Punct, "(") {
+			open = k
+			break
+		}
+	}
+
+	if open < 0 {
+		return nil
+	}
+
+	params := make(map[string]bool)
+	for _, d := range decorators {
+		if d.name == "pytest.mark.parametrize" {
+			for _, name := range pyParametrizeNames(d.tokens) {
+				params[name] = true
+			}
+		}
+	}
+
+	var fixtures []string
+
+	closeIdx := lexer.Match(def, open)
+
+	for k := open + 1; k < closeIdx; k++ {
+		tok := def[k]
+
+		switch {
+		case tok.Kind == lexer.Punct && (tok.Text == "(" || tok.Text == "[" || tok.Text == "{"):
+			k = lexer.Match(def, k)
+		case tok.Kind == lexer.Ident && (def[k-1].Is(lexer.Punct, "(") || def[k-1].Is(lexer.Punct, ",")):
+			if tok.Text != "self" && tok.Text != "cls" && !params[tok.Text] {
+				fixtures = append(fixtures, tok.Text)
+			}
+		}
+	}
+
+	return fixtures
+}
+
+// pyParametrizeNames returns the argument names of a parametrize decorator,
+// given as "a, b" or as a list or tuple of strings.
+func pyParametrizeNames(toks []lexer.Token) []string {
+	if len(toks) < 2 {
+		return nil
+	}
+
+	last := 1
+	if toks[1].Is(lexer.Punct, "[") || toks[1].Is(lexer.Punct, "(") {
+		last = lexer.Match(toks, 1)
+	}
+
+	var names []string
+
+	for k := 1; k <= last && k < len(toks); k++ {
+		if toks[k].Kind != lexer.String {
+			continue
+		}
+
+		for _, name := range strings.Split(lexer.Unquote(toks[k].Text), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// pyParseDecorator returns the dotted name of a decorator and its argument tokens.
+func pyParseDecorator(toks []lexer.Token) pyDecorator {
+	var parts []string
+
+	k := 0
+	for k < len(toks) && (toks[k].Kind == lexer.Ident || toks[k].Is(lexer.Punct, ".")) {
+		parts = append(parts, toks[k].Text)
+		k++
+	}
+
+	d := pyDecorator{name: strings.Join(parts, "")}
+	if k < len(toks) && toks[k].Is(lexer.Punct, "(") {
+		d.tokens = toks[k:]
+	}
+
+	return d
+}
+
+// pyParametrizeCases counts the argument values of a parametrize decorator,
+// returning 0 when they are not a literal list or tuple.
+func pyParametrizeCases(toks []lexer.Token) int {
+	if len(toks) == 0 {
+		return 0
+	}
+
+	closeIdx := lexer.Match(toks, 0)
+
+	// Skip the argnames argument
+	k := 1
+	for k < closeIdx && !toks[k].Is(lexer.Punct, ",") {
+		k++
+	}
+
+	k++
+	if k >= closeIdx {
+		return 0
+	}
+
+	return pyLiteralLength(toks, k)
+}
+
+// pyLiteralLength counts the elements of the list or tuple literal opening
+// at toks[k], returning 0 when toks[k] opens none.
+func pyLiteralLength(toks []lexer.Token, k int) int {
+	if !toks[k].Is(lexer.Punct, "[") && !toks[k].Is(lexer.Punct, "(") {
+		return 0
+	}
+
+	listEnd := lexer.Match(toks, k)
+	if listEnd == k+1 {
+		return 0
+	}
+
+	count := 1
+
+	for m := k + 1; m < listEnd; m++ {
+		t := toks[m]
+
+		switch {
+		case t.Kind == lexer.Punct && (t.Text == "(" || t.Text == "[" || t.Text == "{"):
+			m = lexer.Match(toks, m)
+		case t.Is(lexer.Punct, ",") && m+1 < listEnd:
+			count++
+		}
+	}
+
+	return count
+}
+
+func pyIsAutouseFixture(decorators []pyDecorator) bool {
+	for _, d := range decorators {
+		if d.name != "pytest.fixture" && d.name != "fixture" {
+			continue
+		}
+
+		for k := 0; k+2 < len(d.tokens); k++ {
+			if d.tokens[k].Is(lexer.Ident, "autouse") && d.tokens[k+2].Is(lexer.Ident, "True") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// pyCallName returns the dotted callee of a statement that starts with a call.
+func pyCallName(toks []lexer.Token) string {
+	var parts []string
+
+	for k := 0; k < len(toks); k++ {
+		tok := toks[k]
+		if tok.Kind == lexer.Ident || tok.Is(lexer.Punct, ".") {
+			parts = append(parts, tok.Text)
+			continue
+		}
+
+		if tok.Is(lexer.Punct, "(") {
+			return strings.Join(parts, "")
+		}
+
+		break
+	}
+
+	return ""
+}
+
+// countAssertions counts assert statements, unittest assertion methods and
+// pytest.raises blocks among lines[start:end], and returns the weak ones.
+func (w *pyWalker) countAssertions(start, end int) (int, []types.WeakAssertion) {
+	count := 0
+
+	var weak []types.WeakAssertion
+
+	w.scanBody(start, end, func(style string, toks []lexer.Token, k int) {
+		w.styles[style]++
+		count++
+
+		if wa, ok := pyWeakAssertion(style, toks, k); ok {
+			weak = append(weak, wa)
+		}
+	})
+
+	return count, weak
+}
+
+// scanBody records mocks among lines[start:end] and reports each assertion,
+// with the tokens of its line and the index of the assertion keyword or
+// method, to onAssert, when set.
+func (w *pyWalker) scanBody(start, end int, onAssert func(style string, toks []lexer.Token, k int)) {
+	for i := start; i < end && i < len(w.lines); i++ {
+		toks := w.lines[i].tokens
+
+		for k, tok := range toks {
+			if tok.Kind != lexer.Ident {
+				continue
+			}
+
+			if onAssert != nil {
+				switch {
+				case tok.Text == "assert" && k == 0:
+					onAssert("assert", toks, k)
+				case k > 1 && toks[k-1].Is(lexer.Punct, ".") && toks[k-2].Is(lexer.Ident, "self") &&
+					(strings.HasPrefix(tok.Text, "assert") || tok.Text == "fail"):
+					onAssert("unittest", toks, k)
+				case tok.Text == "raises" && k > 1 && toks[k-2].Is(lexer.Ident, "pytest"):
+					onAssert("pytest.raises", toks, k)
+				}
+			}
+
+			if k > 0 && toks[k-1].Is(lexer.Punct, ".") {
+				continue
+			}
+
+			name := pyCallName(toks[k:])
+			if pyMockAPIs[name] {
+				w.mocks = append(w.mocks, types.MockUsage{API: name, Target: pyFirstStringArg(toks[k:]), Line: tok.Line})
+			}
+		}
+	}
+}
+
+// pyComparisonMatchers maps comparison operators onto the unittest
+// assertion that checks them, for assertTrue and for assertFalse.
+var pyComparisonMatchers = map[string][2]string{
+	"==":     {"assertEqual", "assertNotEqual"},
+	"!=":     {"assertNotEqual", "assertEqual"},
+	"<":      {"assertLess", "assertGreaterEqual"},
+	"<=":     {"assertLessEqual", "assertGreater"},
+	">":      {"assertGreater", "assertLessEqual"},
+	">=":     {"assertGreaterEqual", "assertLess"},
+	"in":     {"assertIn", "assertNotIn"},
+	"not in": {"assertNotIn", "assertIn"},
+	"is":     {"assertIs", "assertIsNot"},
+	"is not": {"assertIsNot", "assertIs"},
+}
+
+// pyWeakAssertion reports bare assert statements without a comparison or
+// message (assert x) and assertTrue/assertFalse calls on a comparison
+// (self.assertTrue(a == b)).
+func pyWeakAssertion(style string, toks []lexer.Token, k int) (types.WeakAssertion, bool) {
+	line := toks[k].Line
+
+	switch {
+	case style == "assert":
+		expr, rest := pyFirstExpr(toks[k+1:])
+		if len(rest) > 0 || len(expr) == 0 {
+			// A message explains the failure
+			return types.WeakAssertion{}, false
+		}
+
+		if op, _ := pyComparison(expr); op != "" {
+			return types.WeakAssertion{}, false
+		}
+
+		return types.WeakAssertion{
+			Kind:       types.WeakTruthiness,
+			Assertion:  "assert",
+			Suggestion: "assert actual == expected",
+			Line:       line,
+		}, true
+	case style == "unittest" && (toks[k].Text == "assertTrue" || toks[k].Text == "assertFalse"):
+		if k+1 >= len(toks) || !toks[k+1].Is(lexer.Punct, "(") {
+			return types.WeakAssertion{}, false
+		}
+
+		expr, _ := pyFirstExpr(toks[k+2 : lexer.Match(toks, k+1)])
+
+		negated := 0
+		if toks[k].Text == "assertFalse" {
+			negated = 1
+		}
+
+		matcher := ""
+
+		switch op, none := pyComparison(expr); {
+		case none && (op == "is" || op == "=="):
+			matcher = [2]string{"assertIsNone", "assertIsNotNone"}[negated]
+		case none && (op == "is not" || op == "!="):
+			matcher = [2]string{"assertIsNotNone", "assertIsNone"}[negated]
+		case op != "":
+			matcher = pyComparisonMatchers[op][negated]
+		case len(expr) > 1 && expr[0].Is(lexer.Ident, "isinstance") && expr[1].Is(lexer.Punct, "("):
+			matcher = [2]string{"assertIsInstance", "assertNotIsInstance"}[negated]
+		}
+
+		if matcher == "" {
+			return types.WeakAssertion{}, false
+		}
+
+		return types.WeakAssertion{
+			Kind:       types.WeakBooleanComparison,
+			Assertion:  "self." + toks[k].Text,
+			Suggestion: "self." + matcher,
+			Line:       line,
+		}, true
+	}
+
+	return types.WeakAssertion{}, false
+}
+
+// pyFirstExpr splits toks at the first comma outside brackets. Parentheses
+// wrapping the whole expression are removed.
+func pyFirstExpr(toks []lexer.Token) (expr, rest []lexer.Token) {
+	expr = toks
+
+	for i := 0; i < len(toks); i++ {
+		if toks[i].Kind == lexer.Punct && (toks[i].Text == "(" || toks[i].Text == "[" || toks[i].Text == "{") {
+			i = lexer.Match(toks, i)
+			continue
+		}
+
+		if toks[i].Is(lexer.Punct, ",") {
+			expr, rest = toks[:i], toks[i+1:]
+			break
+		}
+	}
+
+	for len(expr) > 1 && expr[0].Is(lexer.Punct, "(") && lexer.Match(expr, 0) == len(expr)-1 {
+		expr = expr[1 : len(expr)-1]
+	}
+
+	return expr, rest
+}
+
+// pyComparison returns the first comparison operator of expr outside
+// brackets, and whether one of the compared values is None.
+func pyComparison(expr []lexer.Token) (op string, none bool) {
+	for i := 0; i < len(expr); i++ {
+		tok := expr[i]
+
+		switch {
+		case tok.Kind == lexer.Punct && (tok.Text == "(" || tok.Text == "[" || tok.Text == "{"):
+			i = lexer.Match(expr, i)
+			continue
+		case tok.Kind == lexer.Punct && pyComparisonMatchers[tok.Text] != [2]string{}:
+			op = tok.Text
+		case tok.Is(lexer.Ident, "in"):
+			op = "in"
+		case tok.Is(lexer.Ident, "not") && i+1 < len(expr) && expr[i+1].Is(lexer.Ident, "in"):
+			op = "not in"
+		case tok.Is(lexer.Ident, "is") && i+1 < len(expr) && expr[i+1].Is(lexer.Ident, "not"):
+			op = "is not"
+		case tok.Is(lexer.Ident, "is"):
+			op = "is"
+		}
+
+		if op != "" {
+			none = i > 0 && expr[i-1].Is(lexer.Ident, "None") || pyComparesNone(expr[i+1:])
+
+			return op, none
+		}
+	}
+
+	return "", false
+}
+
+// pyComparesNone reports whether the right operand of a comparison is None.
+func pyComparesNone(rhs []lexer.Token) bool {
+	for len(rhs) > 0 && rhs[0].Kind == lexer.Ident && (rhs[0].Text == "not" || rhs[0].Text == "in") {
+		rhs = rhs[1:]
+	}
+
+	return len(rhs) == 1 && rhs[0].Is(lexer.Ident, "None")
+}
+
+func (w *pyWalker) recordDecoratorMock(d pyDecorator, line int) {
+	if pyMockAPIs[d.name] {
+		w.mocks = append(w.mocks, types.MockUsage{API: d.name, Target: pyFirstStringArg(d.tokens), Line: line})
+	}
+}
+
+// pyFirstStringArg returns the first string literal in a call's arguments.
+func pyFirstStringArg(toks []lexer.Token) string {
+	for _, tok := range toks {
+		if tok.Kind == lexer.String {
+			return lexer.Unquote(tok.Text)
+		}
+	}
+
+	return ""
+}
+
+// PythonImports maps the local names bound by the import statements of a
+// module to the qualified names they refer to: "import a.b" binds a,
+// "import a.b as c" binds c to a.b and "from a import b" binds b to a.b.
+// Relative imports keep their leading dots ("from . import b" binds b to
+// ".b").
+type PythonImports map[string]string
+
+// ParsePythonImports collects the imports of a tokenized module, including
+// imports inside functions.
+func ParsePythonImports(tokens []lexer.Token) PythonImports {
+	imports := make(PythonImports)
+
+	for _, l := range pyLogicalLines(tokens) {
+		toks := l.tokens
+
+		switch {
+		case toks[0].Is(lexer.Ident, "import"):
+			for _, part := range pySplitCommas(toks[1:]) {
+				module, next := lexer.DottedName(part, 0)
+				if module == "" {
+					continue
+				}
+
+				local, _, _ := strings.Cut(module, ".")
+				qualified := local
+
+				if next+1 < len(part) && part[next].Is(lexer.Ident, "as") {
+					local, qualified = part[next+1].Text, module
+				}
+
+				imports[local] = qualified
+			}
+		case toks[0].Is(lexer.Ident, "from"):
+			imports.addFrom(toks)
+		}
+	}
+
+	return imports
+}
+
+// addFrom records the names bound by a from ... import statement.
+func (imports PythonImports) addFrom(toks []lexer.Token) {
+	k := 1
+	module := ""
+
+	for k < len(toks) && toks[k].Is(lexer.Punct, ".") {
+		module += "."
+		k++
+	}
+
+	next := k
+	if k < len(toks) && !toks[k].Is(lexer.Ident, "import") {
+		var name string
+
+		name, next = lexer.DottedName(toks, k)
+		module += name
+	}
+
+	if next >= len(toks) || !toks[next].Is(lexer.Ident, "import") {
+		return
+	}
+
+	if !strings.HasSuffix(module, ".") {
+		module += "."
+	}
+
+	names := toks[next+1:]
+	if len(names) > 0 && names[0].Is(lexer.Punct, "(") {
+		names = names[1:lexer.Match(names, 0)]
+	}
+
+	for _, part := range pySplitCommas(names) {
+		switch {
+		case len(part) == 3 && part[0].Kind == lexer.Ident && part[1].Is(lexer.Ident, "as"):
+			imports[part[2].Text] = module + part[0].Text
+		case len(part) == 1 && part[0].Kind == lexer.Ident:
+			imports[part[0].Text] = module + part[0].Text
+		}
+	}
+}
+
+// Resolve qualifies a dotted name with the module its root was imported
+// from, e.g. "sleep" imported from time becomes "time.sleep". Names whose
+// root is not imported are returned unchanged.
+func (imports PythonImports) Resolve(name string) string {
+	root, rest, found := strings.Cut(name, ".")
+
+	qualified, ok := imports[root]
+	if !ok {
+		return name
+	}
+
+	if found {
+		return qualified + "." + rest
+	}
+
+	return qualified
+}
+
+// pySplitCommas splits tokens on commas outside brackets.
+func pySplitCommas(toks []lexer.Token) [][]lexer.Token {
+	var (
+		parts [][]lexer.Token
+		start int
+	)
+
+	for k := 0; k < len(toks); k++ {
+		switch {
+		case toks[k].Kind == lexer.Punct && (toks[k].Text == "(" || toks[k].Text == "[" || toks[k].Text == "{"):
+			k = lexer.Match(toks, k)
+		case toks[k].Is(lexer.Punct, ","):
+			parts = append(parts, toks[start:k])
+			start = k + 1
+		}
+	}
+
+	return append(parts, toks[start:])
+}
@@ -0,0 +1,326 @@
+package inventory
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const pytestModule = `import pytest
+from unittest import mock
+
+
+@pytest.fixture(autouse=True)
+def reset_db():
+    yield
+
+
+def test_place():
+    order = place("sku-1")
+    assert order.id is not None
+    assert order.total == 10
+
+
+@pytest.mark.parametrize("sku,price", [("a", 1), ("p", 2), ("u", 3)])
+@pytest.mark.parametrize("currency", ["usd", "eur"])
+def test_prices(sku, price, currency):
+    assert price_of(sku, currency) == price
+
+
+@pytest.mark.skip(reason="flaky")
+def test_cancel():
+    pass
+
+
+@pytest.mark.slow
+@mock.patch("orders.gateway.charge")
+def test_charge(charge):
+    with pytest.raises(ValueError):
+        charge_order(None)
+
+
+def test_later():
+    pytest.skip("not implemented")
+
+
+class TestRefunds:
+    def setup_method(self):
+        self.svc = Service()
+
+    def test_full(self):
+        assert self.svc.refund(10) == 10
+
+    def helper(self):
+        pass
+
+
+def helper():
+    pass
+`
+
+//nolint:gocognit,gocyclo // Verifies the full tree of a representative test module
+func TestPythonParser_Pytest(t *testing.T) {
+	file, err := NewPythonParser().Parse("tests/test_orders.py", []byte(pytestModule))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Framework != "pytest" || file.Language != types.LanguagePython {
+		t.Errorf("Framework = %q, Language = %v", file.Framework, file.Language)
+	}
+
+	byName := map[string]types.TestCase{}
+	for _, tc := range file.Tests {
+		byName[tc.Name] = tc
+	}
+
+	if len(byName) != 6 {
+		t.Errorf("top-level entries = %d, want 6: %v", len(byName), byName)
+	}
+
+	if len(file.Hooks) != 1 || file.Hooks[0].Name != "fixture:reset_db" {
+		t.Errorf("Hooks = %+v", file.Hooks)
+	}
+
+	if tc := byName["test_place"]; tc.Kind != types.TestKindTest || tc.Assertions != 2 || tc.Line != 10 {
+		t.Errorf("test_place = kind %s, %d assertions, line %d", tc.Kind, tc.Assertions, tc.Line)
+	}
+
+	if tc := byName["test_prices"]; tc.Kind != types.TestKindParameterized || tc.ParameterCases != 6 {
+		t.Errorf("test_prices = kind %s, %d cases", tc.Kind, tc.ParameterCases)
+	}
+
+	if !byName["test_cancel"].Skipped {
+		t.Error("test_cancel should be skipped")
+	}
+
+	if !byName["test_later"].Skipped {
+		t.Error("test_later should be skipped")
+	}
+
+	charge := byName["test_charge"]
+	if len(charge.Tags) != 1 || charge.Tags[0] != "slow" {
+		t.Errorf("test_charge tags = %v, want [slow]", charge.Tags)
+	}
+
+	refunds := byName["TestRefunds"]
+	if refunds.Kind != types.TestKindSuite || len(refunds.Children) != 1 || len(refunds.Hooks) != 1 {
+		t.Fatalf("TestRefunds = %+v", refunds)
+	}
+
+	if want := "tests/test_orders.py::TestRefunds::test_full"; refunds.Children[0].ID != want {
+		t.Errorf("nested ID = %q, want %q", refunds.Children[0].ID, want)
+	}
+
+	if len(file.Mocks) != 1 || file.Mocks[0].Target != "orders.gateway.charge" {
+		t.Errorf("Mocks = %+v", file.Mocks)
+	}
+
+	if file.AssertionStyles["assert"] != 4 || file.AssertionStyles["pytest.raises"] != 1 {
+		t.Errorf("AssertionStyles = %v", file.AssertionStyles)
+	}
+}
+
+func TestPythonParser_Unittest(t *testing.T) {
+	src := `import unittest
+
+
+class OrderTests(unittest.TestCase):
+    @classmethod
+    def setUpClass(cls):
+        pass
+
+    def setUp(self):
+        pass
+
+    def test_total(self):
+        self.assertEqual(total([1, 2]), 3)
+        self.assertTrue(True)
+
+    @unittest.skip("broken")
+    def test_discount(self):
+        pass
+
+    def test_tax(self):
+        self.skipTest("needs config")
+`
+
+	file, err := NewPythonParser().Parse("test_orders.py", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if file.Framework != "unittest" {
+		t.Errorf("Framework = %q, want unittest", file.Framework)
+	}
+
+	if len(file.Tests) != 1 {
+		t.Fatalf("top-level entries = %+v, want 1", file.Tests)
+	}
+
+	suite := file.Tests[0]
+	if len(suite.Children) != 3 || len(suite.Hooks) != 2 {
+		t.Fatalf("suite children = %d, hooks = %d", len(suite.Children), len(suite.Hooks))
+	}
+
+	total, discount, tax := suite.Children[0], suite.Children[1], suite.Children[2]
+
+	if total.Assertions != 2 || total.Skipped {
+		t.Errorf("test_total = %+v", total)
+	}
+
+	if !discount.Skipped || !tax.Skipped {
+		t.Errorf("skipped: discount=%v tax=%v", discount.Skipped, tax.Skipped)
+	}
+
+	if file.AssertionStyles["unittest"] != 2 {
+		t.Errorf("AssertionStyles = %v", file.AssertionStyles)
+	}
+}
+
+func TestPythonParser_Fixtures(t *testing.T) {
+	src := `import pytest
+
+
+@pytest.mark.parametrize("sku,price", [("a", 1)])
+def test_price(sku, price, db, *args, **kwargs):
+    assert price_of(db, sku) == price
+
+
+@pytest.mark.parametrize(["currency"], [("usd",)])
+def test_currency(currency, rates=None):
+    assert currency in rates
+
+
+class TestRefunds:
+    def test_full(self, tmp_path):
+        assert tmp_path
+`
+
+	file, err := NewPythonParser().Parse("tests/test_orders.py", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := map[string][]string{}
+	file.Walk(func(tc *types.TestCase) {
+		got[tc.Name] = tc.Fixtures
+	})
+
+	want := map[string][]string{
+		"test_price":    {"db"},
+		"test_currency": {"rates"},
+		"test_full":     {"tmp_path"},
+	}
+
+	for name, fixtures := range want {
+		if strings.Join(got[name], ",") != strings.Join(fixtures, ",") {
+			t.Errorf("%s: Fixtures = %v, want %v", name, got[name], fixtures)
+		}
+	}
+}
+
+func TestPythonParser_WeakAssertions(t *testing.T) {
+	src := `import unittest
+
+
+def test_bare():
+    assert load()
+    assert not errors()
+    assert (load() == 1)
+    assert load() in {1, 2}
+    assert ready(), "service should be ready"
+
+
+class LoaderTest(unittest.TestCase):
+    def test_booleans(self):
+        self.assertTrue(load() == 1)
+        self.assertFalse(load() < 0)
+        self.assertTrue(1 in load_all(), "one is loaded")
+        self.assertTrue(result() is None)
+        self.assertFalse(result() is not None)
+        self.assertTrue(isinstance(load(), int))
+        self.assertTrue(ready())
+        self.assertEqual(load(), 1)
+`
+
+	file, err := NewPythonParser().Parse("tests/test_loader.py", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	checkWeakAssertions(t, file, map[string]string{
+		"test_bare": "5 truthiness assert -> assert actual == expected; " +
+			"6 truthiness assert -> assert actual == expected",
+		"test_booleans": "14 boolean-comparison self.assertTrue -> self.assertEqual; " +
+			"15 boolean-comparison self.assertFalse -> self.assertGreaterEqual; " +
+			"16 boolean-comparison self.assertTrue -> self.assertIn; " +
+			"17 boolean-comparison self.assertTrue -> self.assertIsNone; " +
+			"18 boolean-comparison self.assertFalse -> self.assertIsNone; " +
+			"19 boolean-comparison self.assertTrue -> self.assertIsInstance",
+	})
+}
+
+func TestPythonParser_Parameterized(t *testing.T) {
+	src := `import unittest
+from parameterized import parameterized
+
+
+class PriceTest(unittest.TestCase):
+    @parameterized.expand([("a", 1), ("b", 2)])
+    def test_price(self, sku, want):
+        self.assertEqual(price(sku), want)
+`
+
+	file, err := NewPythonParser().Parse("test_price.py", []byte(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	tc := file.Tests[0].Children[0]
+	if tc.Kind != types.TestKindParameterized || tc.ParameterCases != 2 || tc.ParameterSources[0] != "parameterized" {
+		t.Errorf("test_price = %+v, want 2 parameterized cases", tc)
+	}
+}
+
+func TestParsePythonImports(t *testing.T) {
+	src := `import os, json as j
+import urllib.request
+from time import sleep as nap
+from . import helpers
+from ..app.models import (
+    Order,
+    Item as Line,
+)
+
+
+def test_local():
+    from datetime import datetime
+`
+
+	imports := ParsePythonImports(lexer.Tokenize([]byte(src), lexer.Python))
+
+	want := PythonImports{
+		"os": "os", "j": "json", "urllib": "urllib", "nap": "time.sleep", "helpers": ".helpers",
+		"Order": "..app.models.Order", "Line": "..app.models.Item", "datetime": "datetime.datetime",
+	}
+	if !reflect.DeepEqual(imports, want) {
+		t.Errorf("ParsePythonImports() = %v, want %v", imports, want)
+	}
+
+	tests := map[string]string{
+		"nap":                    "time.sleep",
+		"datetime.now":           "datetime.datetime.now",
+		"urllib.request.urlopen": "urllib.request.urlopen",
+		"print":                  "print",
+	}
+
+	for name, want := range tests {
+		if got := imports.Resolve(name); got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package lexer
+
+import "strings"
+
+// Call is a call expression found in a token stream.
+type Call struct {
+	// Name is the dotted callee (e.g., "self.assertEqual", "time.sleep")
+	Name string
+
+	// Index is the position of the callee's first token
+	Index int
+
+	// Open is the position of the opening parenthesis
+	Open int
+
+	// Line is the line of the callee
+	Line int
+
+	// Args are the top-level arguments of the call
+	Args [][]Token
+}
+
+// Arg returns the tokens of the first argument of the call, or nil.
+func (c Call) Arg() []Token {
+	if len(c.Args) == 0 {
+		return nil
+	}
+
+	return c.Args[0]
+}
+
+// DottedName reads an identifier chain such as a.b.c (or a?.b) starting at
+// tokens[k] and returns it with the index of the first token after it.
+func DottedName(tokens []Token, k int) (string, int) {
+	if k >= len(tokens) || tokens[k].Kind != Ident {
+		return "", k
+	}
+
+	parts := []string{tokens[k].Text}
+	k++
+
+	for k+1 < len(tokens) && (tokens[k].Is(Punct, ".") || tokens[k].Is(Punct, "?.")) && tokens[k+1].Kind == Ident {
+		parts = append(parts, tokens[k+1].Text)
+		k += 2
+	}
+
+	return strings.Join(parts, "."), k
+}
+
+// AfterDot reports whether tokens[k] continues a member access.
+func AfterDot(tokens []Token, k int) bool {
+	return k > 0 && (tokens[k-1].Is(Punct, ".") || tokens[k-1].Is(Punct, "?."))
+}
+
+// Calls returns every call whose callee is an identifier chain. Methods
+// called on the result of another expression, such as bar in foo().bar(),
+// are not reported.
+func Calls(tokens []Token) []Call {
+	var calls []Call
+
+	for k := 0; k < len(tokens); k++ {
+		if tokens[k].Kind != Ident || AfterDot(tokens, k) {
+			continue
+		}
+
+		name, next := DottedName(tokens, k)
+		if next < len(tokens) && tokens[next].Is(Punct, "(") {
+			calls = append(calls, Call{Name: name, Index: k, Open: next, Line: tokens[k].Line, Args: SplitArgs(tokens, next)})
+		}
+
+		// Skip the rest of the chain; its arguments are visited next
+		k = next - 1
+	}
+
+	return calls
+}
+
+// SplitArgs splits the tokens between tokens[open] and its closing
+// delimiter on top-level commas.
+func SplitArgs(tokens []Token, open int) [][]Token {
+	closing := Match(tokens, open)
+	if closing <= open+1 {
+		return nil
+	}
+
+	var (
+		args    [][]Token
+		current []Token
+		depth   int
+	)
+
+	for _, tok := range tokens[open+1 : closing] {
+		if tok.Kind == Punct {
+			switch tok.Text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			case ",":
+				if depth == 0 {
+					args = append(args, current)
+					current = nil
+
+					continue
+				}
+			}
+		}
+
+		current = append(current, tok)
+	}
+
+	if len(current) > 0 {
+		args = append(args, current)
+	}
+
+	return args
+}
+
+// HasIdent reports whether the tokens contain any of the identifiers.
+func HasIdent(tokens []Token, names ...string) bool {
+	for _, tok := range tokens {
+		if tok.Kind != Ident {
+			continue
+		}
+
+		for _, name := range names {
+			if tok.Text == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Join joins the texts of the tokens with sep: "" restores source-like
+// text, " " normalizes layout for comparisons.
+func Join(tokens []Token, sep string) string {
+	texts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		texts[i] = tok.Text
+	}
+
+	return strings.Join(texts, sep)
+}
+
+// Lines returns the tokens from line first through line last. A last line
+// before the first one selects the first line only.
+func Lines(tokens []Token, first, last int) []Token {
+	last = max(last, first)
+	start := len(tokens)
+
+	for k, tok := range tokens {
+		if tok.Line >= first {
+			start = k
+			break
+		}
+	}
+
+	stop := start
+	for stop < len(tokens) && tokens[stop].Line <= last {
+		stop++
+	}
+
+	return tokens[start:stop]
+}
@@ -0,0 +1,56 @@
+package lexer
+
+import (
+	"testing"
+)
+
+func TestCalls(t *testing.T) {
+	src := "self.assertEqual(add(1, 2), [3, 4])\nuser?.save().then(done())\nx = y.z\n"
+	calls := Calls(Tokenize([]byte(src), JavaScript))
+
+	var names []string
+	for _, c := range calls {
+		names = append(names, c.Name)
+	}
+
+	if want := []string{"self.assertEqual", "add", "user.save", "done"}; !equal(names, want) {
+		t.Fatalf("Calls() names = %q, want %q", names, want)
+	}
+
+	if first := calls[0]; len(first.Args) != 2 || !equal(texts(first.Arg()), []string{"add", "(", "1", ",", "2", ")"}) ||
+		!equal(texts(first.Args[1]), []string{"[", "3", ",", "4", "]"}) {
+		t.Errorf("assertEqual args = %+v", first.Args)
+	}
+
+	if save := calls[2]; save.Line != 2 || save.Arg() != nil {
+		t.Errorf("user.save = %+v, want line 2 without arguments", save)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	tokens := Tokenize([]byte("time.sleep( 1 )"), Python)
+
+	if got := Join(tokens, ""); got != "time.sleep(1)" {
+		t.Errorf("Join(\"\") = %q", got)
+	}
+
+	if got := Join(tokens, " "); got != "time . sleep ( 1 )" {
+		t.Errorf("Join(\" \") = %q", got)
+	}
+}
+
+func TestLines(t *testing.T) {
+	tokens := Tokenize([]byte("a\nb c\nd\ne\n"), Python)
+
+	if got := texts(Lines(tokens, 2, 3)); !equal(got, []string{"b", "c", "d"}) {
+		t.Errorf("Lines(2, 3) = %q", got)
+	}
+
+	if got := texts(Lines(tokens, 4, 0)); !equal(got, []string{"e"}) {
+		t.Errorf("Lines(4, 0) = %q, want the first line only", got)
+	}
+
+	if !HasIdent(tokens, "x", "d") || HasIdent(tokens, "x") {
+		t.Error("HasIdent() should match any of the names")
+	}
+}
@@ -0,0 +1,487 @@
+// Package lexer provides lightweight tokenizers for languages that Ship Shape
+// analyzes without a native Go parser (JavaScript/TypeScript, Java, Python).
+//
+// The tokenizers are intentionally forgiving: they never fail, and they only
+// understand enough of each language to separate identifiers, literals,
+// punctuation and comments with accurate line numbers.
+package lexer
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind categorizes a token.
+type Kind int
+
+// Token kinds produced by the tokenizers.
+const (
+	Ident   Kind = iota // Identifiers and keywords
+	Number              // Numeric literals
+	String              // String, character and template literals
+	Regex               // JavaScript regular expression literals
+	Punct               // Operators and delimiters
+	Comment             // Line and block comments
+)
+
+// Dialect selects the lexical rules of a language family.
+type Dialect int
+
+// Supported dialects.
+const (
+	JavaScript Dialect = iota // JavaScript and TypeScript
+	Java                      // Java (and other C-family languages)
+	Python                    // Python
+)
+
+// Token is a single lexical element of a source file.
+type Token struct {
+	// Kind categorizes the token
+	Kind Kind
+
+	// Text is the exact source text of the token
+	Text string
+
+	// Line is the 1-based line where the token starts
+	Line int
+
+	// Col is the 1-based byte column where the token starts
+	Col int
+
+	// Offset is the byte offset of the token in the source
+	Offset int
+}
+
+// Is reports whether the token has the given kind and text.
+func (t Token) Is(kind Kind, text string) bool {
+	return t.Kind == kind && t.Text == text
+}
+
+// operators lists multi-character operators, longest first.
+var operators = []string{
+	">>>=", "===", "!==", "**=", "...", "<<=", ">>=", ">>>", "&&=", "||=", "??=",
+	"=>", "==", "!=", "<=", ">=", "&&", "||", "??", "?.", "++", "--", "+=", "-=",
+	"*=", "/=", "%=", "&=", "|=", "^=", "**", "<<", ">>", "::", "->", "//", ":=",
+}
+
+// regexPrecedingWords are keywords after which a slash starts a regex literal.
+var regexPrecedingWords = map[string]bool{
+	"return": true, "typeof": true, "case": true, "do": true, "else": true,
+	"in": true, "of": true, "new": true, "delete": true, "void": true,
+	"throw": true, "instanceof": true, "yield": true, "await": true,
+}
+
+// Tokenize splits src into tokens, omitting comments.
+func Tokenize(src []byte, dialect Dialect) []Token {
+	return lex(src, dialect, false)
+}
+
+// TokenizeWithComments splits src into tokens, including comment tokens.
+func TokenizeWithComments(src []byte, dialect Dialect) []Token {
+	return lex(src, dialect, true)
+}
+
+type scanner struct {
+	src     []byte
+	dialect Dialect
+	pos     int
+	line    int
+	lineOff int
+	tokens  []Token
+	// lastCode is the index of the last non-comment token, or -1
+	lastCode int
+}
+
+//nolint:gocognit,gocyclo // A hand-written lexer is a single large dispatch loop
+func lex(src []byte, dialect Dialect, keepComments bool) []Token {
+	s := &scanner{src: src, dialect: dialect, line: 1, lastCode: -1}
+
+	for s.pos < len(s.src) {
+		c := s.src[s.pos]
+
+		switch {
+		case c == '\n':
+			s.newline(s.pos)
+			s.pos++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			s.pos++
+		case dialect == Python && c == '#':
+			s.emitComment(s.pos, s.scanLineEnd(s.pos), keepComments)
+		case dialect != Python && c == '/' && s.peek(1) == '/':
+			s.emitComment(s.pos, s.scanLineEnd(s.pos), keepComments)
+		case dialect != Python && c == '/' && s.peek(1) == '*':
+			s.emitComment(s.pos, s.scanBlockComment(s.pos), keepComments)
+		case dialect == JavaScript && c == '/' && s.regexAllowed():
+			s.emit(Regex, s.pos, s.scanRegex(s.pos))
+		case c == '"' || c == '\'':
+			s.emit(String, s.pos, s.scanQuoted(s.pos, 0))
+		case c == '`' && dialect == JavaScript:
+			s.emit(String, s.pos, s.scanTemplate(s.pos))
+		case dialect == Python && isStringPrefix(s.src, s.pos):
+			s.emit(String, s.pos, s.scanQuoted(s.pos, prefixLen(s.src, s.pos)))
+		case isDigit(c) || (c == '.' && isDigit(s.peek(1))):
+			s.emit(Number, s.pos, s.scanNumber(s.pos))
+		case isIdentStart(s.src, s.pos, dialect):
+			s.emit(Ident, s.pos, s.scanIdent(s.pos, dialect))
+		default:
+			s.emit(Punct, s.pos, s.scanPunct(s.pos))
+		}
+	}
+
+	return s.tokens
+}
+
+func (s *scanner) peek(n int) byte {
+	if s.pos+n < len(s.src) {
+		return s.src[s.pos+n]
+	}
+
+	return 0
+}
+
+func (s *scanner) newline(at int) {
+	s.line++
+	s.lineOff = at + 1
+}
+
+// emit appends a token spanning [start, end) and advances past it, keeping
+// line accounting correct for multi-line tokens.
+func (s *scanner) emit(kind Kind, start, end int) {
+	s.tokens = append(s.tokens, Token{
+		Kind:   kind,
+		Text:   string(s.src[start:end]),
+		Line:   s.line,
+		Col:    start - s.lineOff + 1,
+		Offset: start,
+	})
+	s.lastCode = len(s.tokens) - 1
+	s.advance(start, end)
+}
+
+func (s *scanner) emitComment(start, end int, keep bool) {
+	if keep {
+		s.tokens = append(s.tokens, Token{
+			Kind:   Comment,
+			Text:   string(s.src[start:end]),
+			Line:   s.line,
+			Col:    start - s.lineOff + 1,
+			Offset: start,
+		})
+	}
+
+	s.advance(start, end)
+}
+
+func (s *scanner) advance(start, end int) {
+	for i := start; i < end; i++ {
+		if s.src[i] == '\n' {
+			s.newline(i)
+		}
+	}
+
+	s.pos = end
+}
+
+func (s *scanner) scanLineEnd(start int) int {
+	i := start
+	for i < len(s.src) && s.src[i] != '\n' {
+		i++
+	}
+
+	return i
+}
+
+func (s *scanner) scanBlockComment(start int) int {
+	end := strings.Index(string(s.src[start+2:]), "*/")
+	if end < 0 {
+		return len(s.src)
+	}
+
+	return start + 2 + end + 2
+}
+
+// scanQuoted scans a quoted literal starting after a prefix of prefix bytes.
+// Triple-quoted strings are supported for Python and Java text blocks.
+func (s *scanner) scanQuoted(start, prefix int) int {
+	i := start + prefix
+	quote := s.src[i]
+
+	if (s.dialect == Python || s.dialect == Java) && i+2 < len(s.src) &&
+		s.src[i+1] == quote && s.src[i+2] == quote {
+		delim := string([]byte{quote, quote, quote})
+
+		end := strings.Index(string(s.src[i+3:]), delim)
+		for end >= 0 && escaped(s.src, i+3+end) {
+			next := strings.Index(string(s.src[i+3+end+1:]), delim)
+			if next < 0 {
+				end = -1
+				break
+			}
+
+			end += next + 1
+		}
+
+		if end < 0 {
+			return len(s.src)
+		}
+
+		return i + 3 + end + 3
+	}
+
+	i++
+	for i < len(s.src) {
+		switch s.src[i] {
+		case '\\':
+			i += 2
+			continue
+		case quote:
+			return i + 1
+		case '\n':
+			// Unterminated literal; stop at end of line
+			return i
+		}
+		i++
+	}
+
+	return len(s.src)
+}
+
+// escaped reports whether the byte at i is preceded by an odd number of backslashes.
+func escaped(src []byte, i int) bool {
+	n := 0
+	for j := i - 1; j >= 0 && src[j] == '\\'; j-- {
+		n++
+	}
+
+	return n%2 == 1
+}
+
+// scanTemplate scans a JavaScript template literal, including nested
+// ${...} substitutions that may themselves contain strings and templates.
+func (s *scanner) scanTemplate(start int) int {
+	i := start + 1
+	for i < len(s.src) {
+		switch s.src[i] {
+		case '\\':
+			i += 2
+			continue
+		case '`':
+			return i + 1
+		case '$':
+			if i+1 < len(s.src) && s.src[i+1] == '{' {
+				i = s.scanSubstitution(i + 2)
+				continue
+			}
+		}
+		i++
+	}
+
+	return len(s.src)
+}
+
+func (s *scanner) scanSubstitution(start int) int {
+	depth := 1
+
+	i := start
+	for i < len(s.src) && depth > 0 {
+		switch s.src[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"', '\'':
+			i = s.scanQuoted(i, 0)
+			continue
+		case '`':
+			i = s.scanTemplate(i)
+			continue
+		}
+		i++
+	}
+
+	return i
+}
+
+// regexAllowed decides whether a slash starts a regex literal by looking at
+// the previous significant token.
+func (s *scanner) regexAllowed() bool {
+	if s.lastCode < 0 {
+		return true
+	}
+
+	prev := s.tokens[s.lastCode]
+
+	switch prev.Kind {
+	case Ident:
+		return regexPrecedingWords[prev.Text]
+	case Punct:
+		return prev.Text != ")" && prev.Text != "]" && prev.Text != "++" && prev.Text != "--"
+	default:
+		return false
+	}
+}
+
+func (s *scanner) scanRegex(start int) int {
+	inClass := false
+
+	i := start + 1
+	for i < len(s.src) {
+		switch c := s.src[i]; {
+		case c == '\\':
+			i += 2
+			continue
+		case c == '\n':
+			return i
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case c == '/' && !inClass:
+			i++
+			for i < len(s.src) && isIdentByte(s.src[i]) {
+				i++
+			}
+
+			return i
+		}
+		i++
+	}
+
+	return len(s.src)
+}
+
+func (s *scanner) scanNumber(start int) int {
+	i := start + 1
+	for i < len(s.src) {
+		c := s.src[i]
+		if isIdentByte(c) || c == '.' {
+			i++
+			continue
+		}
+
+		// Exponent sign (1e-9)
+		if (c == '+' || c == '-') && (s.src[i-1] == 'e' || s.src[i-1] == 'E') &&
+			!strings.HasPrefix(strings.ToLower(string(s.src[start:i])), "0x") {
+			i++
+			continue
+		}
+
+		break
+	}
+
+	return i
+}
+
+func (s *scanner) scanIdent(start int, dialect Dialect) int {
+	i := start
+	for i < len(s.src) {
+		r, size := utf8.DecodeRune(s.src[i:])
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || (r == '$' && dialect != Python) {
+			i += size
+			continue
+		}
+
+		break
+	}
+
+	return i
+}
+
+func (s *scanner) scanPunct(start int) int {
+	rest := s.src[start:]
+	for _, op := range operators {
+		if len(rest) >= len(op) && string(rest[:len(op)]) == op {
+			return start + len(op)
+		}
+	}
+
+	_, size := utf8.DecodeRune(rest)
+
+	return start + size
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentStart(src []byte, pos int, dialect Dialect) bool {
+	r, _ := utf8.DecodeRune(src[pos:])
+
+	return r == '_' || unicode.IsLetter(r) || (r == '$' && dialect != Python)
+}
+
+// prefixLen returns the length of a Python string prefix (r, b, f, u, rb, ...)
+// at pos, or 0 if there is none.
+func prefixLen(src []byte, pos int) int {
+	i := pos
+	for i < len(src) && i-pos < 2 && strings.ContainsRune("rRbBfFuU", rune(src[i])) {
+		i++
+	}
+
+	if i < len(src) && (src[i] == '"' || src[i] == '\'') {
+		return i - pos
+	}
+
+	return 0
+}
+
+func isStringPrefix(src []byte, pos int) bool {
+	if pos > 0 && isIdentByte(src[pos-1]) {
+		return false
+	}
+
+	return prefixLen(src, pos) > 0
+}
+
+// closers maps opening delimiters to their closing counterparts.
+var closers = map[string]string{"(": ")", "[": "]", "{": "}"}
+
+// Match returns the index of the delimiter closing tokens[open].
+// If tokens[open] is not an opening delimiter it returns open; if the
+// delimiter is never closed it returns the index of the last token.
+func Match(tokens []Token, open int) int {
+	closer, ok := closers[tokens[open].Text]
+	if !ok || tokens[open].Kind != Punct {
+		return open
+	}
+
+	opener := tokens[open].Text
+	depth := 0
+
+	for i := open; i < len(tokens); i++ {
+		if tokens[i].Kind != Punct {
+			continue
+		}
+
+		switch tokens[i].Text {
+		case opener:
+			depth++
+		case closer:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(tokens) - 1
+}
+
+// Unquote returns the contents of a string literal token without its quotes
+// and prefix. Escape sequences are left untouched.
+func Unquote(text string) string {
+	text = strings.TrimLeft(text, "rRbBfFuU")
+
+	for _, q := range []string{`"""`, `'''`, `"`, `'`, "`"} {
+		if len(text) >= 2*len(q) && strings.HasPrefix(text, q) && strings.HasSuffix(text, q) {
+			return text[len(q) : len(text)-len(q)]
+		}
+	}
+
+	return text
+}
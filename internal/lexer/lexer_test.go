@@ -0,0 +1,157 @@
+package lexer
+
+import (
+	"testing"
+)
+
+func texts(tokens []Token) []string {
+	out := make([]string, len(tokens))
+	for i, t := range tokens {
+		out[i] = t.Text
+	}
+
+	return out
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestTokenize_JavaScript(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []string
+	}{
+		{
+			name: "call with string argument",
+			src:  `it('works', () => {})`,
+			want: []string{"it", "(", "'works'", ",", "(", ")", "=>", "{", "}", ")"},
+		},
+		{
+			name: "comments are dropped",
+			src:  "a // line\n/* block */ b",
+			want: []string{"a", "b"},
+		},
+		{
+			name: "template literal with substitution",
+			src:  "x = `a ${f(`b`)} c`;",
+			want: []string{"x", "=", "`a ${f(`b`)} c`", ";"},
+		},
+		{
+			name: "regex literal after assignment",
+			src:  `const re = /a\/b[/]/g;`,
+			want: []string{"const", "re", "=", `/a\/b[/]/g`, ";"},
+		},
+		{
+			name: "division is not a regex",
+			src:  `a = b / c / d`,
+			want: []string{"a", "=", "b", "/", "c", "/", "d"},
+		},
+		{
+			name: "multi-character operators",
+			src:  `a?.b === c ?? d`,
+			want: []string{"a", "?.", "b", "===", "c", "??", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := texts(Tokenize([]byte(tt.src), JavaScript))
+			if !equal(got, tt.want) {
+				t.Errorf("Tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenize_Python(t *testing.T) {
+	src := "def test_x():  # comment\n    s = rb'''multi\nline'''\n    assert s // 2\n"
+
+	got := Tokenize([]byte(src), Python)
+	want := []string{"def", "test_x", "(", ")", ":", "s", "=", "rb'''multi\nline'''", "assert", "s", "//", "2"}
+
+	if !equal(texts(got), want) {
+		t.Fatalf("Tokenize() = %q, want %q", texts(got), want)
+	}
+
+	// Tokens after a multi-line string must report the correct line
+	if got[8].Line != 4 {
+		t.Errorf("assert line = %d, want 4", got[8].Line)
+	}
+
+	if got[5].Col != 5 {
+		t.Errorf("s column = %d, want 5", got[5].Col)
+	}
+}
+
+func TestTokenize_JavaTextBlock(t *testing.T) {
+	src := "String s = \"\"\"\n  {\"a\": 1}\n  \"\"\";\n@Test void x() {}"
+
+	got := Tokenize([]byte(src), Java)
+	if got[3].Kind != String {
+		t.Fatalf("token 3 kind = %v, want String (%q)", got[3].Kind, got[3].Text)
+	}
+
+	if got[5].Text != "@" || got[5].Line != 4 {
+		t.Errorf("token 5 = %q at line %d, want @ at line 4", got[5].Text, got[5].Line)
+	}
+}
+
+func TestTokenizeWithComments(t *testing.T) {
+	got := TokenizeWithComments([]byte("a // shipshape:ignore\nb"), JavaScript)
+	if len(got) != 3 || got[1].Kind != Comment || got[1].Line != 1 {
+		t.Fatalf("TokenizeWithComments() = %+v", got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tokens := Tokenize([]byte("f(a, [b, (c)], {d})"), JavaScript)
+
+	if got := Match(tokens, 1); got != len(tokens)-1 {
+		t.Errorf("Match(outer) = %d, want %d", got, len(tokens)-1)
+	}
+
+	if got := Match(tokens, 4); tokens[got].Text != "]" {
+		t.Errorf("Match(array) = %q, want ]", tokens[got].Text)
+	}
+
+	if got := Match(tokens, 0); got != 0 {
+		t.Errorf("Match(non-delimiter) = %d, want 0", got)
+	}
+
+	unclosed := Tokenize([]byte("f(a, b"), JavaScript)
+	if got := Match(unclosed, 1); got != len(unclosed)-1 {
+		t.Errorf("Match(unclosed) = %d, want %d", got, len(unclosed)-1)
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`'abc'`, "abc"},
+		{`"abc"`, "abc"},
+		{"`abc`", "abc"},
+		{`"""doc"""`, "doc"},
+		{`rb'raw'`, "raw"},
+		{`''`, ""},
+		{`ident`, "ident"},
+	}
+
+	for _, tt := range tests {
+		if got := Unquote(tt.in); got != tt.want {
+			t.Errorf("Unquote(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package mapping
+
+import (
+	"path"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// testSegments are directories that hold tests; they are ignored when
+// comparing the location of a test with the location of its subject.
+var testSegments = map[string]bool{
+	"test": true, "tests": true, "__tests__": true, "spec": true,
+}
+
+// jsExtensions are the extensions a JavaScript/TypeScript subject may have.
+var jsExtensions = []string{".ts", ".tsx", ".js", ".jsx", ".mjs", ".cjs", ".mts", ".cts"}
+
+// javaTestAffixes are stripped from Java test class names, longest first.
+var javaTestAffixes = []string{"TestCase", "Tests", "Test", "IT"}
+
+// subjectName returns the file name of the source a test file is expected
+// to cover according to its language's naming convention.
+func subjectName(test string) string {
+	name := path.Base(test)
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+
+	switch discovery.LanguageOf(name) {
+	case types.LanguageGo:
+		stem = strings.TrimSuffix(stem, "_test")
+	case types.LanguagePython:
+		if strings.HasPrefix(stem, "test_") {
+			stem = strings.TrimPrefix(stem, "test_")
+		} else {
+			stem = strings.TrimSuffix(stem, "_test")
+		}
+	case types.LanguageJavaScript, types.LanguageTypeScript:
+		stem = strings.TrimSuffix(strings.TrimSuffix(stem, ".test"), ".spec")
+	case types.LanguageJava:
+		stem = javaSubject(stem)
+	}
+
+	return stem + ext
+}
+
+// javaSubject strips the Surefire naming affixes from a test class name.
+func javaSubject(stem string) string {
+	for _, suffix := range javaTestAffixes {
+		if strings.HasSuffix(stem, suffix) && len(stem) > len(suffix) {
+			return strings.TrimSuffix(stem, suffix)
+		}
+	}
+
+	if strings.HasPrefix(stem, "Test") && len(stem) > len("Test") {
+		return strings.TrimPrefix(stem, "Test")
+	}
+
+	return stem
+}
+
+// conventionSources returns the sources a test covers by naming convention.
+// Go tests fall back to every file of their package when no file matches.
+func conventionSources(test string, idx *sourceIndex) ([]string, types.LinkReason) {
+	subject := subjectName(test)
+	lang := discovery.LanguageOf(test)
+
+	if lang == types.LanguageGo {
+		dir := path.Dir(test)
+		if candidate := path.Join(dir, subject); idx.files[candidate] {
+			return []string{candidate}, types.LinkConvention
+		}
+
+		var pkg []string
+
+		for _, src := range idx.byDir[dir] {
+			if discovery.LanguageOf(src) == types.LanguageGo {
+				pkg = append(pkg, src)
+			}
+		}
+
+		return pkg, types.LinkPackage
+	}
+
+	var candidates []string
+
+	if lang == types.LanguageJavaScript || lang == types.LanguageTypeScript {
+		stem := strings.TrimSuffix(subject, path.Ext(subject))
+		for _, ext := range jsExtensions {
+			candidates = append(candidates, idx.byBase[stem+ext]...)
+		}
+	} else {
+		candidates = idx.byBase[subject]
+	}
+
+	return closestSources(test, candidates), types.LinkConvention
+}
+
+// closestSources picks the candidates whose directory best mirrors the
+// test's directory (colocated, __tests__ parent, src/test -> src/main,
+// tests/pkg -> pkg). Unrelated candidates only qualify when unambiguous.
+func closestSources(test string, candidates []string) []string {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	var testDir []string
+
+	for _, part := range strings.Split(path.Dir(test), "/") {
+		if part != "." && !testSegments[part] {
+			testDir = append(testDir, part)
+		}
+	}
+
+	best, bestScore := []string(nil), -1
+
+	for _, candidate := range candidates {
+		score := sharedSuffix(testDir, strings.Split(path.Dir(candidate), "/"))
+
+		switch {
+		case score > bestScore:
+			best, bestScore = []string{candidate}, score
+		case score == bestScore:
+			best = append(best, candidate)
+		}
+	}
+
+	if bestScore == 0 && len(candidates) > 1 {
+		return nil
+	}
+
+	return best
+}
+
+// sharedSuffix counts the trailing path segments two directories share.
+func sharedSuffix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,343 @@
+package mapping
+
+import (
+	"go/parser"
+	"go/token"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// importSources resolves the imports of a test file to repository sources.
+// Only imports that resolve inside the repository produce links.
+func importSources(test string, src []byte, idx *sourceIndex) []string {
+	switch discovery.LanguageOf(test) {
+	case types.LanguageGo:
+		return goImportSources(test, src, idx)
+	case types.LanguagePython:
+		return pythonImportSources(test, src, idx)
+	case types.LanguageJavaScript, types.LanguageTypeScript:
+		return jsImportSources(test, src, idx)
+	case types.LanguageJava:
+		return javaImportSources(src, idx)
+	default:
+		return nil
+	}
+}
+
+// goImportSources links a Go test to every file of the module packages it imports.
+func goImportSources(test string, src []byte, idx *sourceIndex) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), test, src, parser.ImportsOnly)
+	if err != nil {
+		return nil
+	}
+
+	var sources []string
+
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		dir, ok := idx.goPackageDir(importPath)
+		if !ok || dir == path.Dir(test) {
+			continue
+		}
+
+		for _, candidate := range idx.byDir[dir] {
+			if discovery.LanguageOf(candidate) == types.LanguageGo {
+				sources = append(sources, candidate)
+			}
+		}
+	}
+
+	return sources
+}
+
+// goPackageDir maps an import path onto a directory of a Go module in the repository.
+func (idx *sourceIndex) goPackageDir(importPath string) (string, bool) {
+	best, bestLen := "", -1
+
+	for _, ws := range idx.workspaces {
+		if ws.Type != types.WorkspaceTypeGo || len(ws.Name) <= bestLen {
+			continue
+		}
+
+		if importPath == ws.Name || strings.HasPrefix(importPath, ws.Name+"/") {
+			best = path.Join(ws.Path, strings.TrimPrefix(importPath, ws.Name))
+			bestLen = len(ws.Name)
+		}
+	}
+
+	return best, bestLen >= 0
+}
+
+// pythonImportSources resolves "import a.b" and "from a.b import c" statements,
+// including relative imports, against the repository and its src/ layouts.
+//
+//nolint:gocognit // Import statement parsing is a single token-level state machine
+func pythonImportSources(test string, src []byte, idx *sourceIndex) []string {
+	tokens := lexer.Tokenize(src, lexer.Python)
+	roots := idx.pythonRoots()
+
+	var sources []string
+
+	for i := 0; i < len(tokens); i++ {
+		if i > 0 && tokens[i-1].Line == tokens[i].Line {
+			continue
+		}
+
+		switch {
+		case tokens[i].Is(lexer.Ident, "import"):
+			line := tokens[i].Line
+
+			for i++; i < len(tokens) && tokens[i].Line == line; i++ {
+				module, next := dottedName(tokens, i)
+				sources = append(sources, idx.resolvePython(roots, module)...)
+				i = next
+
+				if i < len(tokens) && tokens[i].Is(lexer.Ident, "as") {
+					i += 2
+				}
+
+				if i >= len(tokens) || !tokens[i].Is(lexer.Punct, ",") {
+					break
+				}
+			}
+
+			i--
+		case tokens[i].Is(lexer.Ident, "from"):
+			i++
+
+			level := 0
+			for i < len(tokens) && tokens[i].Kind == lexer.Punct && strings.Trim(tokens[i].Text, ".") == "" {
+				level += len(tokens[i].Text)
+				i++
+			}
+
+			module := ""
+			if i < len(tokens) && !tokens[i].Is(lexer.Ident, "import") {
+				module, i = dottedName(tokens, i)
+			}
+
+			if i >= len(tokens) || !tokens[i].Is(lexer.Ident, "import") {
+				continue
+			}
+
+			moduleRoots := roots
+			if level > 0 {
+				base := path.Dir(test)
+				for l := 1; l < level; l++ {
+					base = path.Dir(base)
+				}
+
+				moduleRoots = []string{base}
+			}
+
+			if module != "" {
+				sources = append(sources, idx.resolvePython(moduleRoots, module)...)
+			}
+
+			// Imported names may themselves be submodules
+			end := i
+			if i+1 < len(tokens) && tokens[i+1].Is(lexer.Punct, "(") {
+				end = lexer.Match(tokens, i+1)
+			} else {
+				for end+1 < len(tokens) && tokens[end+1].Line == tokens[i].Line {
+					end++
+				}
+			}
+
+			for j := i + 1; j <= end; j++ {
+				if tokens[j].Kind == lexer.Ident && !tokens[j-1].Is(lexer.Ident, "as") && tokens[j].Text != "as" {
+					name := tokens[j].Text
+					if module != "" {
+						name = module + "." + name
+					}
+
+					sources = append(sources, idx.resolvePython(moduleRoots, name)...)
+				}
+			}
+
+			i = end
+		}
+	}
+
+	return sources
+}
+
+// dottedName reads a dotted identifier starting at tokens[i] and returns it
+// with the index of the first token after it.
+func dottedName(tokens []lexer.Token, i int) (string, int) {
+	var parts []string
+
+	for i < len(tokens) && tokens[i].Kind == lexer.Ident {
+		parts = append(parts, tokens[i].Text)
+		if i+1 >= len(tokens) || !tokens[i+1].Is(lexer.Punct, ".") {
+			i++
+			break
+		}
+
+		i += 2
+	}
+
+	return strings.Join(parts, "."), i
+}
+
+// pythonRoots are the directories Python modules are resolved from: the
+// repository root, each Python workspace, and their src/ layouts.
+func (idx *sourceIndex) pythonRoots() []string {
+	roots := []string{".", "src"}
+
+	for _, ws := range idx.workspaces {
+		if ws.Type == types.WorkspaceTypePython && ws.Path != "." {
+			roots = append(roots, ws.Path, path.Join(ws.Path, "src"))
+		}
+	}
+
+	return roots
+}
+
+// resolvePython maps a dotted module name onto a module file under one of the roots.
+func (idx *sourceIndex) resolvePython(roots []string, module string) []string {
+	if module == "" {
+		return nil
+	}
+
+	rel := strings.ReplaceAll(module, ".", "/")
+
+	for _, root := range roots {
+		candidate := path.Join(root, rel+".py")
+		if idx.files[candidate] {
+			return []string{candidate}
+		}
+	}
+
+	return nil
+}
+
+// jsImportSources resolves relative ES module imports, re-exports, require()
+// and dynamic import() calls of a JavaScript/TypeScript test.
+func jsImportSources(test string, src []byte, idx *sourceIndex) []string {
+	tokens := lexer.Tokenize(src, lexer.JavaScript)
+
+	var sources []string
+
+	for i := 0; i+1 < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.Kind != lexer.Ident {
+			continue
+		}
+
+		var spec lexer.Token
+
+		switch {
+		case (tok.Text == "from" || tok.Text == "import") && tokens[i+1].Kind == lexer.String:
+			spec = tokens[i+1]
+		case (tok.Text == "require" || tok.Text == "import") && tokens[i+1].Is(lexer.Punct, "(") &&
+			i+2 < len(tokens) && tokens[i+2].Kind == lexer.String:
+			spec = tokens[i+2]
+		default:
+			continue
+		}
+
+		if resolved, ok := idx.resolveJS(path.Dir(test), lexer.Unquote(spec.Text)); ok {
+			sources = append(sources, resolved)
+		}
+	}
+
+	return sources
+}
+
+// resolveJS resolves a relative module specifier the way Node and TypeScript
+// do: exact file, added extension, .js written for a .ts file, or index file.
+func (idx *sourceIndex) resolveJS(dir, spec string) (string, bool) {
+	if !strings.HasPrefix(spec, "./") && !strings.HasPrefix(spec, "../") {
+		return "", false
+	}
+
+	target := path.Join(dir, spec)
+	if idx.files[target] {
+		return target, true
+	}
+
+	stems := []string{target, path.Join(target, "index")}
+	if ext := path.Ext(target); ext == ".js" || ext == ".jsx" || ext == ".mjs" || ext == ".cjs" {
+		stems = append([]string{strings.TrimSuffix(target, ext)}, stems...)
+	}
+
+	for _, stem := range stems {
+		for _, ext := range jsExtensions {
+			if idx.files[stem+ext] {
+				return stem + ext, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// javaImportSources resolves single-type and static imports of a Java test
+// to the source files declaring the imported classes.
+func javaImportSources(src []byte, idx *sourceIndex) []string {
+	tokens := lexer.Tokenize(src, lexer.Java)
+
+	var sources []string
+
+	for i := 0; i < len(tokens); i++ {
+		if !tokens[i].Is(lexer.Ident, "import") {
+			continue
+		}
+
+		i++
+
+		static := i < len(tokens) && tokens[i].Is(lexer.Ident, "static")
+		if static {
+			i++
+		}
+
+		name, next := dottedName(tokens, i)
+		i = next
+
+		if name == "" || (i < len(tokens) && tokens[i].Is(lexer.Punct, "*")) {
+			continue // wildcard import
+		}
+
+		if static {
+			name = name[:max(strings.LastIndex(name, "."), 0)]
+		}
+
+		sources = append(sources, idx.resolveJava(name)...)
+	}
+
+	return sources
+}
+
+// resolveJava finds the source files declaring a fully qualified class name.
+// Nested classes (com.acme.Outer.Inner) resolve to their outer class file.
+func (idx *sourceIndex) resolveJava(fqn string) []string {
+	parts := strings.Split(fqn, ".")
+
+	for n := len(parts); n > 1; n-- {
+		rel := strings.Join(parts[:n], "/") + ".java"
+
+		var found []string
+
+		for _, candidate := range idx.byBase[parts[n-1]+".java"] {
+			if candidate == rel || strings.HasSuffix(candidate, "/"+rel) {
+				found = append(found, candidate)
+			}
+		}
+
+		if len(found) > 0 {
+			return found
+		}
+	}
+
+	return nil
+}
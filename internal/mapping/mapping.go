@@ -0,0 +1,210 @@
+// Package mapping links test files to the production source files they exercise.
+//
+// Links are established from language naming conventions (foo_test.go ->
+// foo.go, tests/test_foo.py -> foo.py, foo.spec.ts -> foo.ts, src/test/java
+// mirroring src/main/java) and from the imports of each test file. Source
+// files without any linked test are reported as untested; test files that
+// link to nothing are reported as orphans.
+package mapping
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// supportDirs are directories whose files support tests rather than ship to production.
+var supportDirs = map[string]bool{
+	"test": true, "tests": true, "__tests__": true, "__mocks__": true, "testdata": true, "fixtures": true,
+}
+
+// supportFiles are well-known files that are never the subject of a test.
+var supportFiles = map[string]bool{
+	"conftest.py": true, "__init__.py": true, "setup.py": true, "__main__.py": true,
+}
+
+// mappedLanguages are the languages with mapping conventions.
+var mappedLanguages = map[types.Language]bool{
+	types.LanguageGo: true, types.LanguagePython: true, types.LanguageJavaScript: true,
+	types.LanguageTypeScript: true, types.LanguageJava: true,
+}
+
+// Mapper builds test-to-source maps for a repository.
+type Mapper struct {
+	rootPath string
+	walker   *discovery.Walker
+}
+
+// NewMapper creates a new test-to-source mapper.
+func NewMapper(rootPath string, walker *discovery.Walker) *Mapper {
+	return &Mapper{
+		rootPath: rootPath,
+		walker:   walker,
+	}
+}
+
+// sourceIndex provides lookups over the production source files of a repository.
+type sourceIndex struct {
+	files      map[string]bool
+	byDir      map[string][]string
+	byBase     map[string][]string
+	workspaces []types.Workspace
+}
+
+func newSourceIndex(sources []string, workspaces []types.Workspace) *sourceIndex {
+	idx := &sourceIndex{
+		files:      make(map[string]bool, len(sources)),
+		byDir:      make(map[string][]string),
+		byBase:     make(map[string][]string),
+		workspaces: workspaces,
+	}
+
+	for _, src := range sources {
+		idx.files[src] = true
+		idx.byDir[path.Dir(src)] = append(idx.byDir[path.Dir(src)], src)
+		idx.byBase[path.Base(src)] = append(idx.byBase[path.Base(src)], src)
+	}
+
+	return idx
+}
+
+// Map walks the repository and links every test file to its sources.
+func (m *Mapper) Map() (*types.TestMap, error) {
+	var tests, sources []string
+
+	_, err := m.walker.Walk(func(fi discovery.FileInfo) error {
+		if !mappedLanguages[discovery.LanguageOf(fi.Name)] {
+			return nil
+		}
+
+		rel := filepath.ToSlash(fi.RelPath)
+
+		switch {
+		case discovery.IsTestFile(rel):
+			tests = append(tests, rel)
+		case !isSupportFile(rel):
+			sources = append(sources, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	workspaces, err := discovery.NewWorkspaceDetector(m.rootPath, m.walker).Detect()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect workspaces: %w", err)
+	}
+
+	idx := newSourceIndex(sources, workspaces)
+	result := &types.TestMap{
+		SourceFiles: len(sources),
+		TestFiles:   len(tests),
+		Links:       []types.TestLink{},
+		Untested:    []string{},
+		Orphans:     []types.OrphanTest{},
+	}
+	tested := make(map[string]bool)
+
+	for _, test := range tests {
+		links := m.linkTest(test, idx)
+		if len(links) == 0 {
+			result.Orphans = append(result.Orphans, types.OrphanTest{Path: test, Subject: subjectName(test)})
+			continue
+		}
+
+		for _, link := range links {
+			tested[link.Source] = true
+		}
+
+		result.Links = append(result.Links, links...)
+	}
+
+	for _, src := range sources {
+		if !tested[src] {
+			result.Untested = append(result.Untested, src)
+		}
+	}
+
+	sort.Strings(result.Untested)
+	sort.Slice(result.Orphans, func(i, j int) bool {
+		return result.Orphans[i].Path < result.Orphans[j].Path
+	})
+	sort.Slice(result.Links, func(i, j int) bool {
+		if result.Links[i].Test != result.Links[j].Test {
+			return result.Links[i].Test < result.Links[j].Test
+		}
+
+		return result.Links[i].Source < result.Links[j].Source
+	})
+
+	return result, nil
+}
+
+// linkTest returns the links of a single test file, keeping the strongest
+// reason when a source is reached both by convention and by import.
+func (m *Mapper) linkTest(test string, idx *sourceIndex) []types.TestLink {
+	reasons := make(map[string]types.LinkReason)
+
+	add := func(sources []string, reason types.LinkReason) {
+		for _, src := range sources {
+			if existing, ok := reasons[src]; !ok || reasonRank(reason) < reasonRank(existing) {
+				reasons[src] = reason
+			}
+		}
+	}
+
+	conventional, reason := conventionSources(test, idx)
+	add(conventional, reason)
+
+	src, err := os.ReadFile(filepath.Join(m.rootPath, filepath.FromSlash(test))) //nolint:gosec // Reading test files from repository
+	if err != nil {
+		logger.Warn("Failed to read test file", "path", test, "error", err)
+	} else {
+		add(importSources(test, src, idx), types.LinkImport)
+	}
+
+	links := make([]types.TestLink, 0, len(reasons))
+	for source, r := range reasons {
+		links = append(links, types.TestLink{Test: test, Source: source, Reason: r})
+	}
+
+	return links
+}
+
+// reasonRank orders link reasons from the strongest to the weakest.
+func reasonRank(r types.LinkReason) int {
+	switch r {
+	case types.LinkConvention:
+		return 0
+	case types.LinkPackage:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// isSupportFile reports whether a non-test file only supports tests or
+// tooling (fixtures, conftest.py, type declarations, tool configs).
+func isSupportFile(relPath string) bool {
+	name := path.Base(relPath)
+	if supportFiles[name] || strings.HasSuffix(name, ".d.ts") || strings.Contains(name, ".config.") {
+		return true
+	}
+
+	for _, part := range strings.Split(path.Dir(relPath), "/") {
+		if supportDirs[part] {
+			return true
+		}
+	}
+
+	return false
+}
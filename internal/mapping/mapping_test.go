@@ -0,0 +1,189 @@
+package mapping
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+//nolint:gocognit,gocyclo // Verifies every language convention on one repository
+func TestMapper_Map(t *testing.T) {
+	dir := testutil.TempDir(t)
+
+	// Go: name match, package fallback and cross-package import
+	testutil.WriteFile(t, dir, "go.mod", "module example.com/shop\n\ngo 1.24")
+	testutil.WriteFile(t, dir, "internal/cart/cart.go", "package cart")
+	testutil.WriteFile(t, dir, "internal/cart/price.go", "package cart")
+	testutil.WriteFile(t, dir, "internal/cart/cart_test.go", "package cart")
+	testutil.WriteFile(t, dir, "internal/tax/tax.go", "package tax")
+	testutil.WriteFile(t, dir, "internal/tax/rates.go", "package tax")
+	testutil.WriteFile(t, dir, "internal/tax/integration_test.go", `package tax_test
+
+import (
+	"testing"
+
+	"example.com/shop/internal/cart"
+)
+`)
+	testutil.WriteFile(t, dir, "internal/legacy/old_test.go", "package legacy")
+
+	// Python: tests/ mirror, src layout import, orphan
+	testutil.WriteFile(t, dir, "src/shop/orders.py", "")
+	testutil.WriteFile(t, dir, "src/shop/payments.py", "")
+	testutil.WriteFile(t, dir, "src/shop/__init__.py", "")
+	testutil.WriteFile(t, dir, "tests/test_orders.py", `from shop import payments
+import shop.orders as orders
+`)
+	testutil.WriteFile(t, dir, "tests/conftest.py", "")
+	testutil.WriteFile(t, dir, "tests/test_invoices.py", "import pytest\n")
+
+	// JavaScript/TypeScript: colocated spec, __tests__, relative import with .js
+	testutil.WriteFile(t, dir, "web/src/button.tsx", "")
+	testutil.WriteFile(t, dir, "web/src/button.test.tsx", `import { Button } from "./button";`)
+	testutil.WriteFile(t, dir, "web/src/api/client.ts", "")
+	testutil.WriteFile(t, dir, "web/src/api/__tests__/client.spec.ts", `import { get } from "../client.js";
+import { theme } from "../../theme";
+const lodash = require("lodash");
+`)
+	testutil.WriteFile(t, dir, "web/src/theme/index.ts", "")
+	testutil.WriteFile(t, dir, "web/jest.config.js", "")
+
+	// Java: src/test mirrors src/main, import of another class
+	testutil.WriteFile(t, dir, "svc/src/main/java/com/acme/OrderService.java", "")
+	testutil.WriteFile(t, dir, "svc/src/main/java/com/acme/Money.java", "")
+	testutil.WriteFile(t, dir, "svc/src/main/java/com/acme/Unused.java", "")
+	testutil.WriteFile(t, dir, "svc/src/test/java/com/acme/OrderServiceTest.java", `package com.acme;
+import static com.acme.Money.of;
+import java.util.*;
+`)
+
+	result, err := NewMapper(dir, discovery.NewWalker(dir)).Map()
+	if err != nil {
+		t.Fatalf("Map() error = %v", err)
+	}
+
+	links := make(map[string]types.LinkReason)
+	for _, link := range result.Links {
+		links[link.Test+" -> "+link.Source] = link.Reason
+	}
+
+	wantLinks := map[string]types.LinkReason{
+		"internal/cart/cart_test.go -> internal/cart/cart.go":                                              types.LinkConvention,
+		"internal/tax/integration_test.go -> internal/tax/tax.go":                                          types.LinkPackage,
+		"internal/tax/integration_test.go -> internal/tax/rates.go":                                        types.LinkPackage,
+		"internal/tax/integration_test.go -> internal/cart/cart.go":                                        types.LinkImport,
+		"internal/tax/integration_test.go -> internal/cart/price.go":                                       types.LinkImport,
+		"tests/test_orders.py -> src/shop/orders.py":                                                       types.LinkConvention,
+		"tests/test_orders.py -> src/shop/payments.py":                                                     types.LinkImport,
+		"web/src/button.test.tsx -> web/src/button.tsx":                                                    types.LinkConvention,
+		"web/src/api/__tests__/client.spec.ts -> web/src/api/client.ts":                                    types.LinkConvention,
+		"web/src/api/__tests__/client.spec.ts -> web/src/theme/index.ts":                                   types.LinkImport,
+		"svc/src/test/java/com/acme/OrderServiceTest.java -> svc/src/main/java/com/acme/OrderService.java": types.LinkConvention,
+		"svc/src/test/java/com/acme/OrderServiceTest.java -> svc/src/main/java/com/acme/Money.java":        types.LinkImport,
+	}
+
+	for key, want := range wantLinks {
+		if got, ok := links[key]; !ok || got != want {
+			t.Errorf("link %s = %q, want %q", key, got, want)
+		}
+	}
+
+	if len(result.Links) != len(wantLinks) {
+		t.Errorf("links = %d, want %d: %v", len(result.Links), len(wantLinks), links)
+	}
+
+	wantUntested := []string{"svc/src/main/java/com/acme/Unused.java"}
+	if len(result.Untested) != len(wantUntested) || result.Untested[0] != wantUntested[0] {
+		t.Errorf("Untested = %v, want %v", result.Untested, wantUntested)
+	}
+
+	wantOrphans := []types.OrphanTest{
+		{Path: "internal/legacy/old_test.go", Subject: "old.go"},
+		{Path: "tests/test_invoices.py", Subject: "invoices.py"},
+	}
+	if len(result.Orphans) != len(wantOrphans) {
+		t.Fatalf("Orphans = %+v, want %+v", result.Orphans, wantOrphans)
+	}
+
+	for i, want := range wantOrphans {
+		if result.Orphans[i] != want {
+			t.Errorf("Orphans[%d] = %+v, want %+v", i, result.Orphans[i], want)
+		}
+	}
+
+	if result.SourceFiles != 12 || result.TestFiles != 8 {
+		t.Errorf("SourceFiles = %d, TestFiles = %d, want 12 and 8", result.SourceFiles, result.TestFiles)
+	}
+}
+
+func TestSubjectName(t *testing.T) {
+	tests := []struct {
+		test string
+		want string
+	}{
+		{"pkg/store/store_test.go", "store.go"},
+		{"tests/test_models.py", "models.py"},
+		{"app/models_test.py", "models.py"},
+		{"src/button.test.tsx", "button.tsx"},
+		{"src/api.spec.js", "api.js"},
+		{"src/__tests__/button.tsx", "button.tsx"},
+		{"OrderServiceTest.java", "OrderService.java"},
+		{"OrderServiceTests.java", "OrderService.java"},
+		{"OrderServiceIT.java", "OrderService.java"},
+		{"LegacyTestCase.java", "Legacy.java"},
+		{"TestOrders.java", "Orders.java"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.test, func(t *testing.T) {
+			if got := subjectName(tt.test); got != tt.want {
+				t.Errorf("subjectName(%q) = %q, want %q", tt.test, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClosestSources(t *testing.T) {
+	tests := []struct {
+		name       string
+		test       string
+		candidates []string
+		want       []string
+	}{
+		{
+			name:       "prefers mirrored directory",
+			test:       "tests/billing/test_utils.py",
+			candidates: []string{"shop/utils.py", "billing/utils.py"},
+			want:       []string{"billing/utils.py"},
+		},
+		{
+			name:       "single unrelated candidate",
+			test:       "tests/test_utils.py",
+			candidates: []string{"shop/utils.py"},
+			want:       []string{"shop/utils.py"},
+		},
+		{
+			name:       "ambiguous unrelated candidates",
+			test:       "tests/test_utils.py",
+			candidates: []string{"shop/utils.py", "billing/utils.py"},
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := closestSources(tt.test, tt.candidates)
+			if len(got) != len(tt.want) {
+				t.Fatalf("closestSources() = %v, want %v", got, tt.want)
+			}
+
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("closestSources()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package patterns
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Metric is the adoption of a pattern in a language.
+type Metric struct {
+	// Pattern is the measured pattern
+	Pattern Pattern `json:"pattern"`
+
+	// Language is the language of the measured tests
+	Language types.Language `json:"language"`
+
+	// Adopted is the number of tests that follow the pattern
+	Adopted int `json:"adopted"`
+
+	// Eligible is the number of tests the percentage is computed over
+	Eligible int `json:"eligible"`
+
+	// Percentage is Adopted / Eligible * 100
+	Percentage float64 `json:"percentage"`
+
+	// Threshold is the configured minimum percentage, 0 when disabled
+	Threshold float64 `json:"threshold"`
+
+	// Met reports whether the percentage reaches the threshold
+	Met bool `json:"met"`
+}
+
+// Occurrence is a test that follows a pattern.
+type Occurrence struct {
+	// Pattern is the followed pattern
+	Pattern Pattern `json:"pattern"`
+
+	// File is the test file, relative to the repository root
+	File string `json:"file"`
+
+	// Line is the line where the test is declared
+	Line int `json:"line"`
+
+	// Test is the ID of the test
+	Test string `json:"test"`
+
+	// Name is the name of the test
+	Name string `json:"name"`
+}
+
+// Report is the result of a pattern analysis.
+type Report struct {
+	// Metrics are sorted by language and pattern; patterns without
+	// eligible tests are omitted
+	Metrics []Metric `json:"metrics"`
+
+	// Findings report the patterns below their threshold
+	Findings []types.Finding `json:"findings"`
+}
+
+// subject is an inventory entry with the context a pattern needs.
+type subject struct {
+	file      *types.TestFile
+	test      *types.TestCase
+	ancestors []*types.TestCase
+}
+
+// measure decides which tests count towards a pattern and which follow it.
+type measure struct {
+	eligible func(s subject) bool
+	adopted  func(s subject) bool
+}
+
+// measures defines how the adoption of each pattern is computed.
+var measures = map[Pattern]measure{
+	TableDriven: {
+		eligible: isGoTestFunction,
+		adopted: func(s subject) bool {
+			return hasDescendant(s.test, func(tc *types.TestCase) bool { return hasSource(tc, "table") })
+		},
+	},
+	Parallel: {
+		eligible: isGoTestFunction,
+		adopted:  func(s subject) bool { return s.test.Parallel },
+	},
+	Parametrized: {
+		eligible: isRunnableLeaf,
+		adopted: func(s subject) bool {
+			return hasSource(s.test, "parametrize") || anyAncestor(s, func(tc *types.TestCase) bool {
+				return hasSource(tc, "parametrize")
+			})
+		},
+	},
+	Fixtures: {
+		eligible: isRunnableLeaf,
+		adopted: func(s subject) bool {
+			return len(s.test.Fixtures) > 0 || len(s.file.Hooks) > 0 || anyAncestor(s, func(tc *types.TestCase) bool {
+				return len(tc.Hooks) > 0
+			})
+		},
+	},
+	DescribeIt: {
+		eligible: isRunnableLeaf,
+		adopted:  func(s subject) bool { return len(s.ancestors) > 0 },
+	},
+	AsyncAwait: {
+		eligible: func(s subject) bool { return isRunnableLeaf(s) && s.test.Async != "" },
+		adopted:  func(s subject) bool { return s.test.Async == types.AsyncAwait },
+	},
+}
+
+// Analyzer measures pattern adoption in a test inventory.
+type Analyzer struct {
+	config *Config
+}
+
+// NewAnalyzer creates an analyzer; a nil config uses the catalog thresholds.
+func NewAnalyzer(config *Config) *Analyzer {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Analyzer{config: config}
+}
+
+// Analyze computes the adoption of every pattern for the languages of the
+// inventory and reports the patterns below their threshold.
+func (a *Analyzer) Analyze(inv *types.TestInventory) *Report {
+	report := &Report{Metrics: []Metric{}, Findings: []types.Finding{}}
+
+	for _, pattern := range All() {
+		def := Catalog[pattern]
+		m := Metric{Pattern: pattern, Language: def.Language, Threshold: a.config.Thresholds[pattern]}
+
+		forEachSubject(inv, def.Language, func(s subject) {
+			if measures[pattern].eligible(s) {
+				m.Eligible++
+
+				if measures[pattern].adopted(s) {
+					m.Adopted++
+				}
+			}
+		})
+
+		if m.Eligible == 0 {
+			continue
+		}
+
+		m.Percentage = float64(m.Adopted) * 100 / float64(m.Eligible)
+		m.Met = m.Percentage >= m.Threshold
+		report.Metrics = append(report.Metrics, m)
+
+		if !m.Met {
+			report.Findings = append(report.Findings, finding(def, m))
+		}
+	}
+
+	sort.SliceStable(report.Metrics, func(i, j int) bool {
+		return report.Metrics[i].Language < report.Metrics[j].Language
+	})
+
+	return report
+}
+
+// Occurrences returns every test that follows a pattern, in inventory order.
+func Occurrences(inv *types.TestInventory) []Occurrence {
+	var occurrences []Occurrence
+
+	for _, pattern := range All() {
+		m := measures[pattern]
+
+		forEachSubject(inv, Catalog[pattern].Language, func(s subject) {
+			if m.eligible(s) && m.adopted(s) {
+				occurrences = append(occurrences, Occurrence{
+					Pattern: pattern,
+					File:    s.file.Path,
+					Line:    s.test.Line,
+					Test:    s.test.ID,
+					Name:    s.test.Name,
+				})
+			}
+		})
+	}
+
+	return occurrences
+}
+
+// finding reports a pattern below its threshold.
+func finding(def Definition, m Metric) types.Finding {
+	remediation := def.Remediation
+
+	return types.Finding{
+		CheckID:  string(def.Pattern) + "-adoption",
+		Type:     types.FindingTypeBestPractice,
+		Severity: types.SeverityLow,
+		Title:    "Low " + def.Title + " Adoption",
+		Description: fmt.Sprintf("%d of %d %s (%.1f%%) follow the pattern, below the %.0f%% target",
+			m.Adopted, m.Eligible, def.Subject, m.Percentage, m.Threshold),
+		Rationale:   def.Rationale,
+		Remediation: &remediation,
+	}
+}
+
+// forEachSubject calls fn for every entry of the files of a language.
+// JavaScript covers TypeScript files too.
+func forEachSubject(inv *types.TestInventory, lang types.Language, fn func(s subject)) {
+	for i := range inv.Files {
+		file := &inv.Files[i]
+
+		fileLang := file.Language
+		if fileLang == types.LanguageTypeScript {
+			fileLang = types.LanguageJavaScript
+		}
+
+		if fileLang != lang {
+			continue
+		}
+
+		for j := range file.Tests {
+			visit(file, &file.Tests[j], nil, fn)
+		}
+	}
+}
+
+func visit(file *types.TestFile, tc *types.TestCase, ancestors []*types.TestCase, fn func(s subject)) {
+	fn(subject{file: file, test: tc, ancestors: ancestors})
+
+	ancestors = append(ancestors[:len(ancestors):len(ancestors)], tc)
+	for i := range tc.Children {
+		visit(file, &tc.Children[i], ancestors, fn)
+	}
+}
+
+// isGoTestFunction reports whether the subject is a top-level Test function.
+func isGoTestFunction(s subject) bool {
+	return len(s.ancestors) == 0 && s.test.Kind == types.TestKindTest
+}
+
+// isRunnableLeaf reports whether the subject is a test with a body.
+func isRunnableLeaf(s subject) bool {
+	return s.test.IsLeaf() && !s.test.Todo &&
+		(s.test.Kind == types.TestKindTest || s.test.Kind == types.TestKindParameterized)
+}
+
+func hasSource(tc *types.TestCase, source string) bool {
+	for _, s := range tc.ParameterSources {
+		if s == source {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasDescendant(tc *types.TestCase, match func(tc *types.TestCase) bool) bool {
+	for i := range tc.Children {
+		if match(&tc.Children[i]) || hasDescendant(&tc.Children[i], match) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func anyAncestor(s subject, match func(tc *types.TestCase) bool) bool {
+	for _, tc := range s.ancestors {
+		if match(tc) {
+			return true
+		}
+	}
+
+	return false
+}
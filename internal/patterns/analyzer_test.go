@@ -0,0 +1,172 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const goPatternTests = `package app
+
+import "testing"
+
+func TestTable(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct{ in, want string }{{"a", "A"}, {"b", "B"}}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {})
+	}
+}
+
+func TestPlain(t *testing.T) {}
+
+func TestOther(t *testing.T) {}
+
+func TestLast(t *testing.T) {}
+
+func BenchmarkTable(b *testing.B) {}
+`
+
+const pythonPatternTests = `import pytest
+
+
+@pytest.mark.parametrize("n", [1, 2])
+def test_numbers(n):
+    assert n
+
+
+def test_tmp(tmp_path):
+    assert tmp_path
+
+
+def test_plain():
+    assert True
+
+
+class TestSetUp:
+    def setup_method(self):
+        self.x = 1
+
+    def test_x(self):
+        assert self.x
+`
+
+const jsPatternTests = `
+describe('cart', () => {
+  it('adds', async () => { await add(); });
+  it('removes', (done) => { remove(done); });
+  it('totals', () => { expect(total()).toBe(0); });
+});
+
+it('loads', () => load().then(() => {}));
+it.todo('checks out');
+`
+
+// parse builds an inventory from test sources keyed by path.
+func parse(t *testing.T, files map[string]string) *types.TestInventory {
+	t.Helper()
+
+	parsers := map[string]inventory.Parser{
+		".go": inventory.NewGoParser(),
+		".py": inventory.NewPythonParser(),
+		".ts": inventory.NewJavaScriptParser(),
+	}
+
+	inv := &types.TestInventory{}
+
+	for path, src := range files {
+		parser := parsers[path[strings.LastIndex(path, "."):]]
+
+		file, err := parser.Parse(path, []byte(src))
+		if err != nil {
+			t.Fatalf("Parse(%s) error = %v", path, err)
+		}
+
+		inv.Files = append(inv.Files, *file)
+	}
+
+	return inv
+}
+
+func TestAnalyze(t *testing.T) {
+	inv := parse(t, map[string]string{
+		"app/app_test.go":   goPatternTests,
+		"tests/test_app.py": pythonPatternTests,
+		"web/cart.test.ts":  jsPatternTests,
+	})
+
+	report := NewAnalyzer(nil).Analyze(inv)
+
+	want := []struct {
+		pattern           Pattern
+		language          types.Language
+		adopted, eligible int
+		met               bool
+	}{
+		{pattern: Parallel, language: types.LanguageGo, adopted: 1, eligible: 4},
+		{pattern: TableDriven, language: types.LanguageGo, adopted: 1, eligible: 4},
+		{pattern: AsyncAwait, language: types.LanguageJavaScript, adopted: 1, eligible: 3},
+		{pattern: DescribeIt, language: types.LanguageJavaScript, adopted: 3, eligible: 4},
+		{pattern: Fixtures, language: types.LanguagePython, adopted: 2, eligible: 4, met: true},
+		{pattern: Parametrized, language: types.LanguagePython, adopted: 1, eligible: 4},
+	}
+
+	if len(report.Metrics) != len(want) {
+		t.Fatalf("metrics = %+v, want %d", report.Metrics, len(want))
+	}
+
+	for i, w := range want {
+		m := report.Metrics[i]
+		if m.Pattern != w.pattern || m.Language != w.language || m.Adopted != w.adopted ||
+			m.Eligible != w.eligible || m.Met != w.met {
+			t.Errorf("metric %d = %+v, want %+v", i, m, w)
+		}
+	}
+
+	if len(report.Findings) != 5 {
+		t.Fatalf("findings = %d, want 5", len(report.Findings))
+	}
+
+	f := report.Findings[0]
+	if f.CheckID != "async-await-adoption" || f.Type != types.FindingTypeBestPractice || f.Remediation == nil {
+		t.Errorf("finding = %+v", f)
+	}
+
+	if !strings.Contains(f.Description, "1 of 3 asynchronous JavaScript/TypeScript tests (33.3%) follow the pattern, below the 60% target") {
+		t.Errorf("description = %q", f.Description)
+	}
+}
+
+func TestAnalyzeHonorsThresholds(t *testing.T) {
+	inv := parse(t, map[string]string{"app/app_test.go": goPatternTests})
+
+	cfg := DefaultConfig()
+	cfg.Thresholds[TableDriven] = 25
+	cfg.Thresholds[Parallel] = 0
+
+	report := NewAnalyzer(cfg).Analyze(inv)
+
+	if len(report.Findings) != 0 {
+		t.Errorf("findings = %+v, want none", report.Findings)
+	}
+
+	if len(report.Metrics) != 2 || !report.Metrics[0].Met || !report.Metrics[1].Met {
+		t.Errorf("metrics = %+v", report.Metrics)
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	inv := parse(t, map[string]string{"app/app_test.go": goPatternTests})
+
+	var got []string
+	for _, o := range Occurrences(inv) {
+		got = append(got, string(o.Pattern)+" "+o.Name)
+	}
+
+	if strings.Join(got, ", ") != "parallel TestTable, table-driven TestTable" {
+		t.Errorf("Occurrences() = %v", got)
+	}
+}
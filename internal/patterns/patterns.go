@@ -0,0 +1,215 @@
+// Package patterns measures the adoption of testing best practices.
+//
+// Adoption percentages are computed from the test inventory (see package
+// inventory) and compared with the thresholds configured under
+// quality.patterns.<language>. Patterns below their threshold are reported
+// as best-practice findings.
+package patterns
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// Pattern identifies a best practice of the catalog.
+type Pattern string
+
+// Pattern constants use the names of the ground-truth test-pattern catalog
+// where one exists.
+const (
+	TableDriven  Pattern = "table-driven"
+	Parallel     Pattern = "parallel"
+	Parametrized Pattern = "parametrized"
+	Fixtures     Pattern = "fixtures"
+	DescribeIt   Pattern = "describe-it"
+	AsyncAwait   Pattern = "async-await"
+)
+
+// Definition describes a pattern of the catalog.
+type Definition struct {
+	// Pattern is the catalog identifier
+	Pattern Pattern
+
+	// Language is the language the pattern is measured for; JavaScript
+	// patterns also cover TypeScript
+	Language types.Language
+
+	// Title is the human-readable name
+	Title string
+
+	// Subject describes the tests the percentage is computed over
+	Subject string
+
+	// ConfigKey is the threshold key under quality.patterns
+	ConfigKey string
+
+	// DefaultThreshold is the minimum adoption percentage
+	DefaultThreshold float64
+
+	// Rationale explains why the pattern matters
+	Rationale string
+
+	// Remediation describes how to adopt the pattern
+	Remediation types.Remediation
+}
+
+// Catalog lists every pattern with its threshold, rationale and remediation.
+var Catalog = map[Pattern]Definition{
+	TableDriven: {
+		Pattern:          TableDriven,
+		Language:         types.LanguageGo,
+		Title:            "Table-Driven Tests",
+		Subject:          "Go test functions",
+		ConfigKey:        "go.min-table-driven-percentage",
+		DefaultThreshold: 50,
+		Rationale:        "Table-driven tests keep cases side by side, make missing cases easy to spot and give each case its own subtest name in failures.",
+		Remediation: types.Remediation{
+			Summary: "Convert sequential checks into table-driven subtests",
+			Steps: []string{
+				"Collect the inputs and expected results in a slice of structs",
+				"Range over the table and run each case with t.Run(tt.name, ...)",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	Parallel: {
+		Pattern:          Parallel,
+		Language:         types.LanguageGo,
+		Title:            "Parallel Tests",
+		Subject:          "Go test functions",
+		ConfigKey:        "go.min-parallel-percentage",
+		DefaultThreshold: 30,
+		Rationale:        "Tests that call t.Parallel run concurrently, which shortens the suite and exposes hidden shared state.",
+		Remediation: types.Remediation{
+			Summary: "Call t.Parallel() in tests that do not share mutable state",
+			Steps: []string{
+				"Add t.Parallel() as the first statement of independent tests and subtests",
+				"Replace package-level state and os.Setenv with per-test values before parallelizing",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	Parametrized: {
+		Pattern:          Parametrized,
+		Language:         types.LanguagePython,
+		Title:            "Parametrized Tests",
+		Subject:          "Python tests",
+		ConfigKey:        "python.min-parametrize-percentage",
+		DefaultThreshold: 50,
+		Rationale:        "Parametrized tests run one test body over many cases and report each case separately.",
+		Remediation: types.Remediation{
+			Summary: "Use @pytest.mark.parametrize for tests that differ only in data",
+			Steps: []string{
+				"Merge near-identical tests into one test decorated with @pytest.mark.parametrize",
+				"Use subTest in unittest classes that cannot switch to pytest",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	Fixtures: {
+		Pattern:          Fixtures,
+		Language:         types.LanguagePython,
+		Title:            "Fixture Usage",
+		Subject:          "Python tests",
+		ConfigKey:        "python.min-fixture-usage",
+		DefaultThreshold: 40,
+		Rationale:        "Fixtures make test setup explicit and reusable instead of repeating it, or hiding it in module state.",
+		Remediation: types.Remediation{
+			Summary: "Move repeated setup into pytest fixtures",
+			Steps: []string{
+				"Extract shared setup into @pytest.fixture functions, in conftest.py when shared across modules",
+				"Request fixtures as test arguments, using built-ins such as tmp_path and monkeypatch",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	DescribeIt: {
+		Pattern:          DescribeIt,
+		Language:         types.LanguageJavaScript,
+		Title:            "Describe/It Structure",
+		Subject:          "JavaScript/TypeScript tests",
+		ConfigKey:        "javascript.min-describe-it-percentage",
+		DefaultThreshold: 80,
+		Rationale:        "Grouping tests in describe blocks documents the unit under test and scopes hooks to the tests that need them.",
+		Remediation: types.Remediation{
+			Summary: "Group related tests in describe blocks",
+			Steps: []string{
+				"Wrap the tests of each module, class or function in describe('<unit>', ...)",
+				"Move shared setup into beforeEach hooks of the describe block",
+			},
+			Effort: types.EffortMinimal,
+		},
+	},
+	AsyncAwait: {
+		Pattern:          AsyncAwait,
+		Language:         types.LanguageJavaScript,
+		Title:            "Async/Await Tests",
+		Subject:          "asynchronous JavaScript/TypeScript tests",
+		ConfigKey:        "javascript.min-async-await-percentage",
+		DefaultThreshold: 60,
+		Rationale:        "Async/await tests read top to bottom and report rejected promises reliably, unlike done callbacks and promise chains.",
+		Remediation: types.Remediation{
+			Summary: "Rewrite done callbacks and promise chains with async/await",
+			Steps: []string{
+				"Make the test callback async and await each asynchronous step",
+				"Replace done callbacks with awaited promises",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+}
+
+// All returns every pattern of the catalog in a stable order.
+func All() []Pattern {
+	all := make([]Pattern, 0, len(Catalog))
+	for pattern := range Catalog {
+		all = append(all, pattern)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	return all
+}
+
+// Config holds the adoption thresholds of the patterns.
+type Config struct {
+	// Thresholds are the minimum adoption percentages; 0 disables the check
+	Thresholds map[Pattern]float64
+}
+
+// DefaultConfig uses the catalog thresholds.
+func DefaultConfig() *Config {
+	cfg := &Config{Thresholds: make(map[Pattern]float64, len(Catalog))}
+
+	for pattern, def := range Catalog {
+		cfg.Thresholds[pattern] = def.DefaultThreshold
+	}
+
+	return cfg
+}
+
+// LoadConfig reads the thresholds under quality.patterns, such as
+// quality.patterns.go.min-table-driven-percentage. Thresholds must be
+// percentages between 0 and 100.
+func LoadConfig(v *viper.Viper) (*Config, error) {
+	cfg := DefaultConfig()
+
+	for pattern, def := range Catalog {
+		key := "quality.patterns." + def.ConfigKey
+		if !v.IsSet(key) {
+			continue
+		}
+
+		threshold := v.GetFloat64(key)
+		if threshold < 0 || threshold > 100 {
+			return nil, fmt.Errorf("%s must be between 0 and 100, got %v", key, threshold)
+		}
+
+		cfg.Thresholds[pattern] = threshold
+	}
+
+	return cfg, nil
+}
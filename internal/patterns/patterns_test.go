@@ -0,0 +1,77 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		want     map[Pattern]float64
+		wantErr  string
+	}{
+		{
+			name: "defaults",
+			want: map[Pattern]float64{TableDriven: 50, Parallel: 30, Parametrized: 50, Fixtures: 40, DescribeIt: 80, AsyncAwait: 60},
+		},
+		{
+			name: "configured thresholds",
+			settings: map[string]any{
+				"quality.patterns.go.min-parallel-percentage":            0,
+				"quality.patterns.python.min-fixture-usage":              75,
+				"quality.patterns.javascript.min-describe-it-percentage": 55.5,
+			},
+			want: map[Pattern]float64{Parallel: 0, Fixtures: 75, DescribeIt: 55.5, TableDriven: 50},
+		},
+		{
+			name:     "threshold above 100",
+			settings: map[string]any{"quality.patterns.go.min-table-driven-percentage": 120},
+			wantErr:  "quality.patterns.go.min-table-driven-percentage must be between 0 and 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			for key, value := range tt.settings {
+				v.Set(key, value)
+			}
+
+			cfg, err := LoadConfig(v)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			for pattern, want := range tt.want {
+				if got := cfg.Thresholds[pattern]; got != want {
+					t.Errorf("threshold of %s = %v, want %v", pattern, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for _, pattern := range All() {
+		def := Catalog[pattern]
+		if def.Title == "" || def.Subject == "" || def.ConfigKey == "" || def.Rationale == "" || def.Remediation.Summary == "" {
+			t.Errorf("incomplete definition of %s: %+v", pattern, def)
+		}
+
+		if _, ok := measures[pattern]; !ok {
+			t.Errorf("no measure for %s", pattern)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+// Package runner runs the tests of a repository with coverage enabled.
+//
+// Each workspace gets the command of the test framework detected in it:
+// go test for Go, pytest with pytest-cov for Python, Jest for JavaScript and
+// TypeScript, and Maven with the JaCoCo plugin for Java. Commands run one at
+// a time, each bounded by the analyzer timeout, and write their coverage
+// report, test results and console output to a directory of the work dir.
+package runner
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chambridge/ship-shape/internal/coverage"
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// DefaultTimeout bounds each command when analysis.analyzer-timeout is not
+// set.
+const DefaultTimeout = 5 * time.Minute
+
+// Config holds the settings of test runs.
+type Config struct {
+	// Timeout bounds each command
+	Timeout time.Duration
+}
+
+// DefaultConfig bounds each command by DefaultTimeout.
+func DefaultConfig() *Config {
+	return &Config{Timeout: DefaultTimeout}
+}
+
+// LoadConfig reads analysis.analyzer-timeout, which must be positive.
+func LoadConfig(v *viper.Viper) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if v.IsSet("analysis.analyzer-timeout") {
+		cfg.Timeout = v.GetDuration("analysis.analyzer-timeout")
+		if cfg.Timeout <= 0 {
+			return nil, fmt.Errorf("analysis.analyzer-timeout must be a positive duration, got %q", v.GetString("analysis.analyzer-timeout"))
+		}
+	}
+
+	return cfg, nil
+}
+
+// Command is the test command of a workspace.
+type Command struct {
+	// Workspace is the workspace the command tests
+	Workspace types.Workspace `json:"workspace"`
+
+	// Framework is the test framework the command runs
+	Framework string `json:"framework"`
+
+	// Args are the program and its arguments
+	Args []string `json:"args"`
+
+	// Dir is the directory the command runs in
+	Dir string `json:"dir"`
+
+	// OutputDir is the directory of the outputs of the command
+	OutputDir string `json:"output_dir"`
+
+	// Format is the format of the coverage report
+	Format coverage.Format `json:"format"`
+
+	// Coverage is the path of the coverage report
+	Coverage string `json:"coverage"`
+
+	// Results is the path of the test results, if the framework writes any
+	// outside the console output
+	Results string `json:"results,omitempty"`
+
+	// Stdout is the path of the captured standard output
+	Stdout string `json:"stdout"`
+
+	// Stderr is the path of the captured standard error
+	Stderr string `json:"stderr"`
+
+	// generated is where the framework writes the coverage report when it
+	// cannot be told to write it to the output directory
+	generated string
+}
+
+// Skipped is a workspace without a supported test framework.
+type Skipped struct {
+	// Workspace is the skipped workspace
+	Workspace types.Workspace `json:"workspace"`
+
+	// Reason explains why the workspace was skipped
+	Reason string `json:"reason"`
+}
+
+// Result is the outcome of a command.
+type Result struct {
+	Command
+
+	// Duration is how long the command ran
+	Duration time.Duration `json:"duration"`
+
+	// ExitCode is the exit code of the command, or -1 when it did not exit
+	ExitCode int `json:"exit_code"`
+
+	// TimedOut reports whether the command was stopped by the timeout
+	TimedOut bool `json:"timed_out,omitempty"`
+
+	// Error describes why the command failed to run or produced no report
+	Error string `json:"error,omitempty"`
+
+	// Reported reports whether the command produced a coverage report
+	Reported bool `json:"reported"`
+}
+
+// Passed reports whether the command ran and every test passed.
+func (r Result) Passed() bool {
+	return r.ExitCode == 0 && r.Error == ""
+}
+
+// Runner plans and runs the test commands of the workspaces of a repository.
+type Runner struct {
+	root    string
+	workDir string
+	config  *Config
+}
+
+// NewRunner creates a runner for the repository at root, writing outputs
+// to workDir. Both are made absolute, since commands run in the workspace
+// directories.
+func NewRunner(root, workDir string, cfg *Config) (*Runner, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+
+	absWorkDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", workDir, err)
+	}
+
+	return &Runner{root: absRoot, workDir: absWorkDir, config: cfg}, nil
+}
+
+// Plan returns the commands of the workspaces with a supported test
+// framework, in workspace order, and the workspaces without one.
+func (r *Runner) Plan(workspaces []types.Workspace) ([]Command, []Skipped, error) {
+	var (
+		commands []Command
+		skipped  []Skipped
+	)
+
+	for _, ws := range workspaces {
+		dir := filepath.Join(r.root, filepath.FromSlash(ws.Path))
+
+		frameworks, err := discovery.NewFrameworkDetector(dir, discovery.NewWalker(dir)).Detect()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to detect the frameworks of %s: %w", ws.Path, err)
+		}
+
+		cmd, reason := r.command(ws, frameworks)
+		if reason != "" {
+			skipped = append(skipped, Skipped{Workspace: ws, Reason: reason})
+			continue
+		}
+
+		commands = append(commands, cmd)
+	}
+
+	return commands, skipped, nil
+}
+
+// command returns the test command of a workspace, or why it has none.
+func (r *Runner) command(ws types.Workspace, frameworks []types.Framework) (Command, string) {
+	out := filepath.Join(r.workDir, outputName(ws.Path))
+	cmd := Command{
+		Workspace: ws,
+		Dir:       filepath.Join(r.root, filepath.FromSlash(ws.Path)),
+		OutputDir: out,
+		Stdout:    filepath.Join(out, "stdout.log"),
+		Stderr:    filepath.Join(out, "stderr.log"),
+	}
+
+	has := func(names ...string) bool {
+		return slices.ContainsFunc(frameworks, func(fw types.Framework) bool {
+			return fw.Type == types.FrameworkTypeTest && slices.Contains(names, fw.Name)
+		})
+	}
+
+	switch {
+	case ws.Type == types.WorkspaceTypeGo || ws.Language == types.LanguageGo:
+		if !has("testing", "testify", "ginkgo") {
+			return cmd, "no Go tests"
+		}
+
+		cmd.Framework = "go test"
+		cmd.Format = coverage.FormatGoCover
+		cmd.Coverage = filepath.Join(out, "coverage.out")
+		cmd.Stdout = filepath.Join(out, "test.json")
+		cmd.Results = cmd.Stdout
+		cmd.Args = []string{"go", "test", "-coverprofile=" + cmd.Coverage, "-json", "./..."}
+	case ws.Language == types.LanguagePython:
+		if !has("pytest") {
+			return cmd, "pytest not detected"
+		}
+
+		cmd.Framework = "pytest"
+		cmd.Format = coverage.FormatCobertura
+		cmd.Coverage = filepath.Join(out, "coverage.xml")
+		cmd.Results = filepath.Join(out, "junit.xml")
+		cmd.Args = []string{pythonExecutable(), "-m", "pytest", "--cov=.", "--cov-report=xml:" + cmd.Coverage, "--junitxml=" + cmd.Results}
+	case ws.Language == types.LanguageJavaScript || ws.Language == types.LanguageTypeScript:
+		if !has("jest") {
+			return cmd, "Jest not detected"
+		}
+
+		cmd.Framework = "jest"
+		cmd.Format = coverage.FormatIstanbul
+		cmd.Coverage = filepath.Join(out, "coverage", "coverage-final.json")
+		cmd.Results = filepath.Join(out, "jest.json")
+		cmd.Args = []string{
+			"npx", "--no-install", "jest", "--ci", "--coverage", "--coverageReporters=json",
+			"--coverageDirectory=" + filepath.Dir(cmd.Coverage), "--json", "--outputFile=" + cmd.Results,
+		}
+	case ws.Type == types.WorkspaceTypeMaven:
+		// The plugin is named in full so that projects without it in their
+		// pom.xml are covered too.
+		cmd.Framework = "maven"
+		cmd.Format = coverage.FormatJaCoCo
+		cmd.Coverage = filepath.Join(out, "jacoco.xml")
+		cmd.generated = filepath.Join(cmd.Dir, "target", "site", "jacoco", "jacoco.xml")
+		cmd.Args = []string{
+			"mvn", "--batch-mode", "org.jacoco:jacoco-maven-plugin:prepare-agent", "test",
+			"-Dmaven.test.failure.ignore=true", "org.jacoco:jacoco-maven-plugin:report",
+		}
+	default:
+		return cmd, fmt.Sprintf("running %s tests with coverage is not supported", ws.Type)
+	}
+
+	return cmd, ""
+}
+
+// Run runs a command with the configured timeout and collects its coverage
+// report. Failing tests are not an error: their coverage is still
+// collected and the exit code reports the failure.
+func (r *Runner) Run(ctx context.Context, cmd Command) Result {
+	result := Result{Command: cmd, ExitCode: -1}
+
+	if err := os.MkdirAll(cmd.OutputDir, 0o750); err != nil {
+		result.Error = fmt.Sprintf("failed to create output directory: %v", err)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.config.Timeout)
+	defer cancel()
+
+	logger.Info("Running tests", "workspace", cmd.Workspace.Path, "command", strings.Join(cmd.Args, " "))
+
+	start := time.Now()
+	err := r.exec(ctx, cmd)
+	result.Duration = time.Since(start)
+
+	var exitErr *exec.ExitError
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		result.TimedOut = true
+		result.Error = fmt.Sprintf("timed out after %s", r.config.Timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.Error = err.Error()
+	default:
+		result.ExitCode = 0
+	}
+
+	if cmd.generated != "" && result.Error == "" {
+		if err := copyFile(cmd.generated, cmd.Coverage); err != nil {
+			logger.Debug("Coverage report not generated", "path", cmd.generated, "error", err)
+		}
+	}
+
+	if _, err := os.Stat(cmd.Coverage); err == nil {
+		result.Reported = true
+	} else if result.Error == "" {
+		result.Error = "no coverage report was written"
+	}
+
+	logger.Debug("Tests finished", "workspace", cmd.Workspace.Path, "exit_code", result.ExitCode,
+		"duration", result.Duration, "reported", result.Reported)
+
+	return result
+}
+
+// exec runs a command, capturing its output to files.
+func (r *Runner) exec(ctx context.Context, cmd Command) error {
+	stdout, err := os.Create(cmd.Stdout)
+	if err != nil {
+		return fmt.Errorf("failed to capture output: %w", err)
+	}
+	defer stdout.Close()
+
+	stderr, err := os.Create(cmd.Stderr)
+	if err != nil {
+		return fmt.Errorf("failed to capture output: %w", err)
+	}
+	defer stderr.Close()
+
+	c := exec.CommandContext(ctx, cmd.Args[0], cmd.Args[1:]...) //nolint:gosec // Commands are built from the detected frameworks
+	c.Dir = cmd.Dir
+	c.Stdout = stdout
+	c.Stderr = stderr
+	c.WaitDelay = 10 * time.Second
+
+	return c.Run()
+}
+
+// Parse parses the coverage report of a result. Paths are resolved
+// against the workspace the command tested.
+func (r *Runner) Parse(result Result, workspaces []types.Workspace) (*coverage.Report, error) {
+	parser, err := coverage.NewParser(result.Format, r.root, result.Workspace.Path, workspaces)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(result.Coverage) //nolint:gosec // Reading a report written by the test run
+	if err != nil {
+		return nil, fmt.Errorf("failed to open coverage report: %w", err)
+	}
+	defer f.Close()
+
+	report, err := parser.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", result.Coverage, err)
+	}
+
+	return report, nil
+}
+
+// outputName returns the name of the output directory of a workspace: its
+// path with slashes replaced, followed by a short hash of the path so that
+// workspaces such as a/b and a-b do not share a directory.
+func outputName(wsPath string) string {
+	if wsPath == "." || wsPath == "" {
+		return "root"
+	}
+
+	sum := sha256.Sum256([]byte(wsPath))
+
+	return strings.ReplaceAll(wsPath, "/", "-") + "-" + hex.EncodeToString(sum[:4])
+}
+
+// pythonExecutable returns python3 when it is installed, else python.
+func pythonExecutable() string {
+	if _, err := exec.LookPath("python3"); err == nil {
+		return "python3"
+	}
+
+	return "python"
+}
+
+// copyFile copies a file.
+func copyFile(src, dst string) error {
+	in, err := os.Open(src) //nolint:gosec // Reading a report written by the test run
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst) //nolint:gosec // Writing to the work directory
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,203 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chambridge/ship-shape/internal/coverage"
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// writeGoModule writes a Go module with a test covering one of its two
+// functions.
+func writeGoModule(t *testing.T, dir string) {
+	t.Helper()
+
+	testutil.WriteFile(t, dir, "go.mod", "module example.com/calc\n\ngo 1.21\n")
+	testutil.WriteFile(t, dir, "calc.go", "package calc\n\nfunc One() int {\n\treturn 1\n}\n\nfunc Two() int {\n\treturn 2\n}\n")
+	testutil.WriteFile(t, dir, "calc_test.go", "package calc\n\nimport \"testing\"\n\nfunc TestOne(t *testing.T) {\n\tif One() != 1 {\n\t\tt.Fatal(\"One() != 1\")\n\t}\n}\n")
+}
+
+func TestPlan(t *testing.T) {
+	root := testutil.TempDir(t)
+	writeGoModule(t, root)
+	testutil.WriteFile(t, root, "web/package.json", `{"name": "web", "devDependencies": {"jest": "^29.0.0"}}`)
+	testutil.WriteFile(t, root, "site/package.json", `{"name": "site", "devDependencies": {"mocha": "^10.0.0"}}`)
+	testutil.WriteFile(t, root, "ml/requirements.txt", "pytest==8.0.0\npytest-cov==4.1.0\n")
+	testutil.WriteFile(t, root, "api/pom.xml", "<project/>")
+	testutil.WriteFile(t, root, "app/build.gradle", "plugins { id 'java' }\n")
+	testutil.WriteFile(t, root, "tools/go.mod", "module example.com/tools\n\ngo 1.21\n")
+
+	workspaces := []types.Workspace{
+		{Name: "example.com/calc", Path: ".", Language: types.LanguageGo, Type: types.WorkspaceTypeGo},
+		{Name: "api", Path: "api", Language: types.LanguageJava, Type: types.WorkspaceTypeMaven},
+		{Name: "app", Path: "app", Language: types.LanguageJava, Type: types.WorkspaceTypeGradle},
+		{Name: "ml", Path: "ml", Language: types.LanguagePython, Type: types.WorkspaceTypePython},
+		{Name: "site", Path: "site", Language: types.LanguageJavaScript, Type: types.WorkspaceTypeNpm},
+		{Name: "example.com/tools", Path: "tools", Language: types.LanguageGo, Type: types.WorkspaceTypeGo},
+		{Name: "web", Path: "web", Language: types.LanguageJavaScript, Type: types.WorkspaceTypeNpm},
+	}
+
+	workDir := filepath.Join(root, "out")
+
+	r, err := NewRunner(root, workDir, DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commands, skipped, err := r.Plan(workspaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, c := range commands {
+		got = append(got, c.Workspace.Path+" "+c.Framework+" "+string(c.Format))
+	}
+
+	want := []string{". go test go-cover", "api maven jacoco-xml", "ml pytest cobertura-xml", "web jest istanbul-json"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("commands = %q, want %q", got, want)
+	}
+
+	goTest := commands[0]
+	if goTest.Dir != root || goTest.Coverage != filepath.Join(workDir, "root", "coverage.out") ||
+		!reflect.DeepEqual(goTest.Args, []string{"go", "test", "-coverprofile=" + goTest.Coverage, "-json", "./..."}) {
+		t.Errorf("go test command = %+v", goTest)
+	}
+
+	if jest := commands[3]; jest.Dir != filepath.Join(root, "web") || jest.Coverage != filepath.Join(workDir, outputName("web"), "coverage", "coverage-final.json") {
+		t.Errorf("jest command = %+v", jest)
+	}
+
+	var reasons []string
+	for _, s := range skipped {
+		reasons = append(reasons, s.Workspace.Path+": "+s.Reason)
+	}
+
+	wantReasons := []string{
+		"app: running gradle tests with coverage is not supported",
+		"site: Jest not detected",
+		"tools: no Go tests",
+	}
+	if !reflect.DeepEqual(reasons, wantReasons) {
+		t.Errorf("skipped = %q, want %q", reasons, wantReasons)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	root := testutil.TempDir(t)
+	writeGoModule(t, root)
+
+	workspaces := []types.Workspace{{Name: "example.com/calc", Path: ".", Language: types.LanguageGo, Type: types.WorkspaceTypeGo}}
+
+	r, err := NewRunner(root, filepath.Join(root, "out"), DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commands, _, err := r.Plan(workspaces)
+	if err != nil || len(commands) != 1 {
+		t.Fatalf("Plan() = %+v, %v", commands, err)
+	}
+
+	result := r.Run(context.Background(), commands[0])
+	if !result.Passed() || !result.Reported {
+		t.Fatalf("Run() = %+v, want passing tests with a report", result)
+	}
+
+	report, err := r.Parse(result, workspaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Files) != 1 || report.Files[0].Path != "calc.go" || report.Metrics.Lines != (coverage.Counter{Covered: 2, Total: 4}) {
+		t.Errorf("report = %+v, want calc.go half covered", report)
+	}
+}
+
+func TestRunFailures(t *testing.T) {
+	root := testutil.TempDir(t)
+
+	r, err := NewRunner(root, filepath.Join(root, "out"), &Config{Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	command := func(args ...string) Command {
+		out := filepath.Join(root, "out", "root")
+
+		return Command{
+			Args:      args,
+			Dir:       root,
+			OutputDir: out,
+			Coverage:  filepath.Join(out, "coverage.out"),
+			Stdout:    filepath.Join(out, "stdout.log"),
+			Stderr:    filepath.Join(out, "stderr.log"),
+		}
+	}
+
+	t.Run("missing executable", func(t *testing.T) {
+		result := r.Run(context.Background(), command("shipshape-no-such-test-runner"))
+		if result.Passed() || result.Reported || !strings.Contains(result.Error, "executable file not found") {
+			t.Errorf("Run() = %+v, want an error", result)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		if _, err := exec.LookPath("sleep"); err != nil {
+			t.Skip("sleep is not installed")
+		}
+
+		result := r.Run(context.Background(), command("sleep", "5"))
+		if !result.TimedOut || result.Passed() || result.Duration >= 5*time.Second {
+			t.Errorf("Run() = %+v, want a timeout", result)
+		}
+	})
+}
+
+func TestOutputName(t *testing.T) {
+	if got := outputName("."); got != "root" {
+		t.Errorf("outputName(.) = %q, want root", got)
+	}
+
+	nested, dashed := outputName("a/b"), outputName("a-b")
+	if nested == dashed || !strings.HasPrefix(nested, "a-b-") || !strings.HasPrefix(dashed, "a-b-") {
+		t.Errorf("outputName(a/b) = %q, outputName(a-b) = %q, want distinct readable names", nested, dashed)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	if err := v.ReadConfig(strings.NewReader("analysis:\n  analyzer-timeout: 90s\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(v)
+	if err != nil || cfg.Timeout != 90*time.Second {
+		t.Errorf("LoadConfig() = %+v, %v, want 90s", cfg, err)
+	}
+
+	if cfg, err := LoadConfig(viper.New()); err != nil || cfg.Timeout != DefaultTimeout {
+		t.Errorf("LoadConfig() = %+v, %v, want the default timeout", cfg, err)
+	}
+
+	v.Set("analysis.analyzer-timeout", "0s")
+
+	if _, err := LoadConfig(v); err == nil || !strings.Contains(err.Error(), "must be a positive duration") {
+		t.Errorf("LoadConfig() error = %v, want positive duration error", err)
+	}
+}
@@ -0,0 +1,148 @@
+package smells
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// BaselineVersion is the version of the baseline file format.
+const BaselineVersion = 1
+
+// DefaultBaselineFile is the baseline path used when none is configured.
+const DefaultBaselineFile = ".shipshape-smells-baseline.json"
+
+// Baseline records accepted findings so that only new findings are reported.
+type Baseline struct {
+	// Version is the file format version
+	Version int `json:"version"`
+
+	// Findings are the accepted findings, ordered by file and fingerprint
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// BaselineEntry is an accepted finding.
+type BaselineEntry struct {
+	// Fingerprint identifies the finding independently of line numbers
+	Fingerprint string `json:"fingerprint"`
+
+	// CheckID is the smell of the finding
+	CheckID string `json:"check_id"`
+
+	// File is the file of the finding
+	File string `json:"file"`
+
+	// Test is the test of the finding, if any
+	Test string `json:"test,omitempty"`
+}
+
+// NewBaseline creates a baseline accepting the given findings.
+func NewBaseline(findings []types.Finding) *Baseline {
+	b := &Baseline{Version: BaselineVersion, Findings: make([]BaselineEntry, 0, len(findings))}
+
+	for _, f := range findings {
+		b.Findings = append(b.Findings, BaselineEntry{
+			Fingerprint: f.Fingerprint,
+			CheckID:     f.CheckID,
+			File:        f.Location.File,
+			Test:        f.Test,
+		})
+	}
+
+	sort.Slice(b.Findings, func(i, j int) bool {
+		if b.Findings[i].File != b.Findings[j].File {
+			return b.Findings[i].File < b.Findings[j].File
+		}
+
+		return b.Findings[i].Fingerprint < b.Findings[j].Fingerprint
+	})
+
+	return b
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // Baseline path is provided by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+
+	if b.Version != BaselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s (want %d)", b.Version, path, BaselineVersion)
+	}
+
+	return &b, nil
+}
+
+// Save writes the baseline as indented JSON.
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+
+	return nil
+}
+
+// Filter returns the findings missing from the baseline and the number of
+// findings it matched.
+func (b *Baseline) Filter(findings []types.Finding) ([]types.Finding, int) {
+	known := make(map[string]bool, len(b.Findings))
+	for _, entry := range b.Findings {
+		known[entry.Fingerprint] = true
+	}
+
+	fresh := make([]types.Finding, 0, len(findings))
+	matched := 0
+
+	for _, f := range findings {
+		if f.Fingerprint != "" && known[f.Fingerprint] {
+			matched++
+			continue
+		}
+
+		fresh = append(fresh, f)
+	}
+
+	return fresh, matched
+}
+
+// fingerprint sets the fingerprints of a file's findings, which must be
+// sorted by line. A fingerprint hashes the smell, file, test and the
+// whitespace-normalized source line where the finding starts, so it
+// survives lines being added or removed elsewhere in the file. Repeated
+// findings with the same key are told apart by their order.
+func fingerprint(findings []types.Finding, src []byte) {
+	lines := strings.Split(string(src), "\n")
+	seen := make(map[string]int)
+
+	for i := range findings {
+		f := &findings[i]
+
+		text := ""
+		if line := f.Location.StartLine; line >= 1 && line <= len(lines) {
+			text = strings.Join(strings.Fields(lines[line-1]), " ")
+		}
+
+		key := strings.Join([]string{f.CheckID, f.Location.File, f.Test, text}, "\x00")
+		seen[key]++
+
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", key, seen[key])))
+		f.Fingerprint = hex.EncodeToString(sum[:16])
+	}
+}
@@ -0,0 +1,114 @@
+package smells
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/testutil"
+)
+
+func TestFingerprintsSurviveLineShifts(t *testing.T) {
+	before := sleepyTest
+	after := strings.Replace(sleepyTest, "func TestWaits", "// Waits for the clock.\n\nfunc TestWaits", 1)
+
+	engine := NewEngine(nil, nil)
+
+	old, err := engine.AnalyzeFile("app/app_test.go", []byte(before))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	shifted, err := engine.AnalyzeFile("app/app_test.go", []byte(after))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	if len(old) != 2 || len(shifted) != 2 {
+		t.Fatalf("expected 2 findings each, got %d and %d", len(old), len(shifted))
+	}
+
+	for i := range old {
+		if old[i].Fingerprint == "" || old[i].Fingerprint != shifted[i].Fingerprint {
+			t.Errorf("fingerprint changed: %q -> %q", old[i].Fingerprint, shifted[i].Fingerprint)
+		}
+
+		if old[i].Location.StartLine == shifted[i].Location.StartLine {
+			t.Errorf("expected finding %d to move", i)
+		}
+	}
+
+	if old[0].Fingerprint == old[1].Fingerprint {
+		t.Error("distinct findings share a fingerprint")
+	}
+}
+
+func TestFingerprintsDistinguishRepeatedFindings(t *testing.T) {
+	src := strings.Replace(sleepyTest, "time.Sleep(time.Second)", "time.Sleep(time.Second)\n\ttime.Sleep(time.Second)", 1)
+
+	findings, err := NewEngine(nil, nil).AnalyzeFile("app/app_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	seen := make(map[string]bool)
+
+	for _, f := range findings {
+		if seen[f.Fingerprint] {
+			t.Errorf("duplicate fingerprint %q for %s", f.Fingerprint, f.Location)
+		}
+
+		seen[f.Fingerprint] = true
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	findings, err := NewEngine(nil, nil).AnalyzeFile("app/app_test.go", []byte(sleepyTest))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	path := filepath.Join(testutil.TempDir(t), "baseline.json")
+	if err := NewBaseline(findings[:1]).Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	baseline, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline() error = %v", err)
+	}
+
+	if len(baseline.Findings) != 1 || baseline.Findings[0].CheckID != findings[0].CheckID {
+		t.Fatalf("baseline = %+v", baseline)
+	}
+
+	fresh, matched := baseline.Filter(findings)
+	if matched != 1 || len(fresh) != 1 || fresh[0].Fingerprint != findings[1].Fingerprint {
+		t.Errorf("Filter() = %+v, %d; want the second finding and 1 match", fresh, matched)
+	}
+}
+
+func TestLoadBaselineErrors(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "bad.json", "{")
+	testutil.WriteFile(t, dir, "future.json", `{"version": 99, "findings": []}`)
+
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "missing", file: "missing.json", wantErr: "failed to read baseline"},
+		{name: "invalid JSON", file: "bad.json", wantErr: "failed to parse baseline"},
+		{name: "unknown version", file: "future.json", wantErr: "unsupported baseline version 99"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadBaseline(filepath.Join(dir, tt.file))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadBaseline() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,750 @@
+package smells
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"hash/fnv"
+	"sort"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// DefaultMinCloneTokens is the default length, in tokens, from which a
+// repeated sequence is reported as a clone.
+const DefaultMinCloneTokens = 50
+
+// CloneType classifies clones by how much their copies differ.
+type CloneType int
+
+// Clone types of the usual clone taxonomy. Type 3 (gapped) clones are not detected.
+const (
+	CloneType1 CloneType = 1 // Identical apart from layout and comments
+	CloneType2 CloneType = 2 // Identical apart from renamed identifiers and literals
+)
+
+// CloneClass is a group of code fragments sharing the same token sequence.
+type CloneClass struct {
+	// Type is CloneType1 when every fragment is textually identical
+	Type CloneType `json:"type"`
+
+	// Language is the language of the fragments
+	Language types.Language `json:"language"`
+
+	// Tokens is the length of each fragment in tokens
+	Tokens int `json:"tokens"`
+
+	// Fragments are the copies, ordered by file and line
+	Fragments []types.Location `json:"fragments"`
+}
+
+// cloneKeywords are the identifiers kept verbatim during normalization,
+// because renaming them changes the structure of the code.
+var cloneKeywords = map[lexer.Dialect]map[string]bool{
+	lexer.Python: setOf("False", "None", "True", "and", "as", "assert", "async", "await", "break", "class",
+		"continue", "def", "del", "elif", "else", "except", "finally", "for", "from", "global", "if", "import",
+		"in", "is", "lambda", "nonlocal", "not", "or", "pass", "raise", "return", "try", "while", "with", "yield"),
+	lexer.JavaScript: setOf("async", "await", "break", "case", "catch", "class", "const", "continue", "default",
+		"delete", "do", "else", "export", "extends", "false", "finally", "for", "function", "if", "import", "in",
+		"instanceof", "let", "new", "null", "of", "return", "super", "switch", "this", "throw", "true", "try",
+		"typeof", "undefined", "var", "void", "while", "yield"),
+}
+
+// cloneDialects maps the languages analyzed by the lexer to their dialect.
+var cloneDialects = map[types.Language]lexer.Dialect{
+	types.LanguagePython:     lexer.Python,
+	types.LanguageJavaScript: lexer.JavaScript,
+	types.LanguageTypeScript: lexer.JavaScript,
+}
+
+func setOf(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+
+	return set
+}
+
+// cloneToken is a token with its normalized symbol and original text.
+type cloneToken struct {
+	sym  int
+	text string
+	line int
+}
+
+// cloneFile is a tokenized test file.
+type cloneFile struct {
+	path   string
+	lang   types.Language
+	tokens []cloneToken
+}
+
+// clonePos is the start of a token window in a file.
+type clonePos struct {
+	file   int
+	offset int
+}
+
+// CloneDetector finds Type 1 and Type 2 clones across the test files added
+// to it. Files are compared only with files of the same language family, so
+// JavaScript and TypeScript tests are compared with each other.
+type CloneDetector struct {
+	minTokens int
+	symbols   map[string]int
+	groups    map[string][]*cloneFile
+}
+
+// NewCloneDetector creates a clone detector reporting sequences of at least
+// minTokens tokens. Non-positive values select DefaultMinCloneTokens.
+func NewCloneDetector(minTokens int) *CloneDetector {
+	if minTokens <= 0 {
+		minTokens = DefaultMinCloneTokens
+	}
+
+	return &CloneDetector{
+		minTokens: minTokens,
+		symbols:   make(map[string]int),
+		groups:    make(map[string][]*cloneFile),
+	}
+}
+
+// Add tokenizes a file for comparison. Files in unsupported languages are ignored.
+func (d *CloneDetector) Add(relPath string, src []byte) error {
+	lang := discovery.LanguageOf(relPath)
+
+	var (
+		tokens []cloneToken
+		group  string
+		err    error
+	)
+
+	if lang == types.LanguageGo {
+		tokens, err = d.tokenizeGo(relPath, src)
+		group = string(lang)
+	} else if dialect, ok := cloneDialects[lang]; ok {
+		tokens = d.tokenize(src, dialect)
+		group = fmt.Sprintf("dialect-%d", dialect)
+	} else {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	d.groups[group] = append(d.groups[group], &cloneFile{path: relPath, lang: lang, tokens: tokens})
+
+	return nil
+}
+
+// symbol interns a normalized token.
+func (d *CloneDetector) symbol(s string) int {
+	sym, ok := d.symbols[s]
+	if !ok {
+		sym = len(d.symbols)
+		d.symbols[s] = sym
+	}
+
+	return sym
+}
+
+// tokenizeGo tokenizes Go source with go/scanner, leaving out the package
+// clause and imports, which every file shares. Composite literals, such as
+// test tables, are data rather than code and count as a single literal.
+//
+//nolint:gocognit // Skips package clause, imports and literal contents while scanning
+func (d *CloneDetector) tokenizeGo(relPath string, src []byte) ([]cloneToken, error) {
+	fset := token.NewFileSet()
+	file := fset.AddFile(relPath, -1, len(src))
+	literals := goCompositeLiterals(src)
+
+	var (
+		s         scanner.Scanner
+		firstErr  error
+		tokens    []cloneToken
+		skip      bool
+		depth     int
+		skipUntil = -1
+	)
+
+	s.Init(file, src, func(pos token.Position, msg string) {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("failed to tokenize %s: %s", pos, msg)
+		}
+	}, 0)
+
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		offset := file.Offset(pos)
+
+		switch {
+		case offset < skipUntil:
+			continue
+		case tok == token.PACKAGE || tok == token.IMPORT:
+			skip = true
+
+			continue
+		case skip:
+			if tok == token.LPAREN {
+				depth++
+			} else if tok == token.RPAREN {
+				depth--
+			} else if tok == token.SEMICOLON && depth == 0 {
+				skip = false
+			}
+
+			continue
+		}
+
+		norm, text := tok.String(), lit
+		switch {
+		case tok == token.IDENT:
+			norm = "id"
+		case tok.IsLiteral():
+			norm = "lit"
+		case tok == token.SEMICOLON:
+			norm, text = ";", ";"
+		default:
+			text = tok.String()
+		}
+
+		line := fset.Position(pos).Line
+		tokens = append(tokens, cloneToken{sym: d.symbol(norm), text: text, line: line})
+
+		if end, ok := literals[offset]; ok && end > offset+1 {
+			tokens = append(tokens, cloneToken{sym: d.symbol("lit"), text: string(src[offset+1 : end]), line: line})
+			skipUntil = end
+		}
+	}
+
+	return tokens, firstErr
+}
+
+// goCompositeLiterals maps the offset of each outermost composite literal's
+// opening brace to the offset of its closing brace. Files that do not parse
+// yield no literals.
+func goCompositeLiterals(src []byte) map[int]int {
+	literals := make(map[int]int)
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return literals
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		lit, ok := n.(*ast.CompositeLit)
+		if !ok {
+			return true
+		}
+
+		literals[fset.Position(lit.Lbrace).Offset] = fset.Position(lit.Rbrace).Offset
+
+		return false
+	})
+
+	return literals
+}
+
+// tokenize tokenizes Python, JavaScript or TypeScript source, leaving out
+// imports. List, dict and object literals count as a single literal.
+func (d *CloneDetector) tokenize(src []byte, dialect lexer.Dialect) []cloneToken {
+	toks := lexer.Tokenize(src, dialect)
+	keywords := cloneKeywords[dialect]
+	tokens := make([]cloneToken, 0, len(toks))
+
+	for k := 0; k < len(toks); k++ {
+		tok := toks[k]
+
+		if end := importEnd(toks, k, dialect); end > k {
+			k = end
+
+			continue
+		}
+
+		norm := tok.Text
+		switch tok.Kind {
+		case lexer.Ident:
+			if !keywords[tok.Text] {
+				norm = "id"
+			}
+		case lexer.Number, lexer.String, lexer.Regex:
+			norm = "lit"
+		}
+
+		tokens = append(tokens, cloneToken{sym: d.symbol(norm), text: tok.Text, line: tok.Line})
+
+		if dataLiteral(toks, k) {
+			closing := lexer.Match(toks, k)
+			if closing > k+1 {
+				tokens = append(tokens, cloneToken{sym: d.symbol("lit"), text: lexer.Join(toks[k+1:closing], " "), line: tok.Line})
+				k = closing - 1
+			}
+		}
+	}
+
+	return tokens
+}
+
+// dataLiteral reports whether toks[k] opens a list, dict or object literal:
+// a bracket or brace in value position.
+func dataLiteral(toks []lexer.Token, k int) bool {
+	if k == 0 || !(toks[k].Is(lexer.Punct, "[") || toks[k].Is(lexer.Punct, "{")) {
+		return false
+	}
+
+	prev := toks[k-1]
+	if prev.Kind == lexer.Ident {
+		return prev.Text == "return" || prev.Text == "in" || prev.Text == "yield"
+	}
+
+	switch prev.Text {
+	case "=", "(", ",", ":", "[", "==", "!=", "?", "||", "&&", "??":
+		return prev.Kind == lexer.Punct
+	}
+
+	return false
+}
+
+// importEnd returns the last token of the import statement starting at
+// toks[k], or k when no import starts there.
+func importEnd(toks []lexer.Token, k int, dialect lexer.Dialect) int {
+	tok := toks[k]
+
+	if dialect == lexer.Python {
+		if !(tok.Is(lexer.Ident, "import") || tok.Is(lexer.Ident, "from")) || (k > 0 && toks[k-1].Line == tok.Line) {
+			return k
+		}
+
+		end := k
+		for end+1 < len(toks) && toks[end+1].Line == tok.Line {
+			end++
+			if toks[end].Is(lexer.Punct, "(") {
+				end = lexer.Match(toks, end)
+			}
+		}
+
+		return end
+	}
+
+	if !tok.Is(lexer.Ident, "import") || (k > 0 && toks[k-1].Is(lexer.Punct, ".")) ||
+		(k+1 < len(toks) && toks[k+1].Is(lexer.Punct, "(")) {
+		return k
+	}
+
+	end := k + 1
+	for end < len(toks) && toks[end].Kind != lexer.String {
+		end++
+	}
+
+	if end+1 < len(toks) && toks[end+1].Is(lexer.Punct, ";") {
+		end++
+	}
+
+	return min(end, len(toks)-1)
+}
+
+// Classes returns the clone classes of every language, ordered by the
+// location of their first fragment.
+func (d *CloneDetector) Classes() []CloneClass {
+	var classes []CloneClass
+
+	for _, files := range d.groups {
+		classes = append(classes, d.groupClasses(files)...)
+	}
+
+	sort.SliceStable(classes, func(i, j int) bool {
+		a, b := classes[i].Fragments[0], classes[j].Fragments[0]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+
+		return a.StartLine < b.StartLine
+	})
+
+	return classes
+}
+
+// cloneClass collects the fragments of one clone class while matching.
+type cloneClass struct {
+	length    int
+	fragments []clonePos
+	seen      map[clonePos]bool
+}
+
+// groupClasses finds maximal repeated token sequences among files of one
+// language family. Windows of minTokens tokens are hashed; every pair of
+// windows that is not extensible to the left is extended to the right as
+// far as the tokens match, and the resulting pairs are grouped by their
+// normalized token sequence.
+//
+//nolint:gocognit,gocyclo // Window indexing, pair extension and grouping
+func (d *CloneDetector) groupClasses(files []*cloneFile) []CloneClass {
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+
+	windows := make(map[uint64][]clonePos)
+
+	for fi, f := range files {
+		for off := 0; off+d.minTokens <= len(f.tokens); off++ {
+			h := d.hash(f.tokens[off : off+d.minTokens])
+			windows[h] = append(windows[h], clonePos{file: fi, offset: off})
+		}
+	}
+
+	byKey := make(map[uint64]*cloneClass)
+
+	var order []uint64
+
+	for fi, f := range files {
+		for off := 0; off+d.minTokens <= len(f.tokens); off++ {
+			p := clonePos{file: fi, offset: off}
+
+			for _, q := range windows[d.hash(f.tokens[off:off+d.minTokens])] {
+				if q.file < p.file || (q.file == p.file && q.offset <= p.offset) {
+					continue
+				}
+
+				a, b := f.tokens, files[q.file].tokens
+				if p.offset > 0 && q.offset > 0 && a[p.offset-1].sym == b[q.offset-1].sym {
+					continue // Part of a longer match found at an earlier offset
+				}
+
+				length := 0
+				for p.offset+length < len(a) && q.offset+length < len(b) && a[p.offset+length].sym == b[q.offset+length].sym {
+					length++
+				}
+
+				if p.file == q.file && p.offset+length > q.offset {
+					// A match running into its own copy is a periodic sequence,
+					// such as the rows of a test table, not copied code
+					continue
+				}
+
+				if length < d.minTokens {
+					continue
+				}
+
+				key := d.hash(a[p.offset : p.offset+length])
+
+				class, ok := byKey[key]
+				if !ok {
+					class = &cloneClass{length: length, seen: make(map[clonePos]bool)}
+					byKey[key] = class
+					order = append(order, key)
+				}
+
+				for _, pos := range []clonePos{p, q} {
+					if !class.seen[pos] {
+						class.seen[pos] = true
+						class.fragments = append(class.fragments, pos)
+					}
+				}
+			}
+		}
+	}
+
+	var classes []CloneClass
+
+	for _, key := range order {
+		class := byKey[key]
+		if d.subsumed(class, byKey) {
+			continue
+		}
+
+		sort.Slice(class.fragments, func(i, j int) bool {
+			a, b := class.fragments[i], class.fragments[j]
+			if a.file != b.file {
+				return a.file < b.file
+			}
+
+			return a.offset < b.offset
+		})
+
+		// Fragments overlapping within a file are shifted matches of a
+		// repetitive sequence; keep the first of them
+		kept := class.fragments[:0]
+		for _, frag := range class.fragments {
+			if n := len(kept); n > 0 && kept[n-1].file == frag.file && kept[n-1].offset+class.length > frag.offset {
+				continue
+			}
+
+			kept = append(kept, frag)
+		}
+
+		if class.fragments = kept; len(kept) < 2 {
+			continue
+		}
+
+		classes = append(classes, d.export(files, class))
+	}
+
+	return classes
+}
+
+// subsumed reports whether every fragment of class lies inside a fragment of a longer class.
+func (d *CloneDetector) subsumed(class *cloneClass, all map[uint64]*cloneClass) bool {
+	for _, frag := range class.fragments {
+		covered := false
+
+		for _, other := range all {
+			if other.length <= class.length {
+				continue
+			}
+
+			for _, o := range other.fragments {
+				if o.file == frag.file && o.offset <= frag.offset && frag.offset+class.length <= o.offset+other.length {
+					covered = true
+					break
+				}
+			}
+
+			if covered {
+				break
+			}
+		}
+
+		if !covered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// export converts a clone class to its public form.
+func (d *CloneDetector) export(files []*cloneFile, class *cloneClass) CloneClass {
+	first := files[class.fragments[0].file]
+	out := CloneClass{Type: CloneType1, Language: first.lang, Tokens: class.length}
+
+	for _, frag := range class.fragments {
+		f := files[frag.file]
+		toks := f.tokens[frag.offset : frag.offset+class.length]
+
+		if out.Type == CloneType1 {
+			ref := first.tokens[class.fragments[0].offset:]
+			for i, tok := range toks {
+				if tok.text != ref[i].text {
+					out.Type = CloneType2
+					break
+				}
+			}
+		}
+
+		out.Fragments = append(out.Fragments, types.Location{
+			File:      f.path,
+			StartLine: toks[0].line,
+			EndLine:   toks[len(toks)-1].line,
+		})
+	}
+
+	return out
+}
+
+// hash returns the FNV-1a hash of a normalized token sequence.
+func (d *CloneDetector) hash(toks []cloneToken) uint64 {
+	h := fnv.New64a()
+
+	var buf [8]byte
+
+	for _, tok := range toks {
+		for i := range buf {
+			buf[i] = byte(tok.sym >> (8 * i))
+		}
+
+		_, _ = h.Write(buf[:])
+	}
+
+	return h.Sum64()
+}
+
+// cloneRegion is a maximal duplicated region of a file: the union of the
+// overlapping fragments of one or more clone classes.
+type cloneRegion struct {
+	types.Location
+	class  CloneClass       // longest class with a fragment in the region
+	others []types.Location // fragments of those classes elsewhere
+}
+
+// cloneFindings reports one code-duplication finding per maximal
+// duplicated region, naming the other copies and a refactoring that fits
+// the longest clone class of the region. Fragments that overlap or contain
+// each other within a file, whether of one class or of several, are merged
+// into a single region.
+func cloneFindings(classes []CloneClass) []types.Finding {
+	var regions []*cloneRegion
+
+	for _, class := range classes {
+		for i, frag := range class.Fragments {
+			r := &cloneRegion{Location: frag, class: class}
+
+			for j, other := range class.Fragments {
+				if j != i {
+					r.others = append(r.others, other)
+				}
+			}
+
+			regions = append(regions, r)
+		}
+	}
+
+	sort.Slice(regions, func(i, j int) bool { return locationBefore(regions[i].Location, regions[j].Location) })
+
+	var merged []*cloneRegion
+
+	for _, r := range regions {
+		if n := len(merged); n > 0 && overlaps(merged[n-1].Location, r.Location) {
+			m := merged[n-1]
+			m.EndLine = max(m.EndLine, r.EndLine)
+			m.others = append(m.others, r.others...)
+
+			if r.class.Tokens > m.class.Tokens {
+				m.class = r.class
+			}
+
+			continue
+		}
+
+		merged = append(merged, r)
+	}
+
+	var findings []types.Finding
+
+	for _, r := range merged {
+		var others []string
+
+		for _, other := range mergeLocations(r.others) {
+			if !overlaps(other, r.Location) {
+				others = append(others, other.String())
+			}
+		}
+
+		if len(others) == 0 {
+			continue
+		}
+
+		kind := "identical code"
+		if r.class.Type == CloneType2 {
+			kind = "code differing only in identifiers and literals"
+		}
+
+		remediation := cloneRemediation(r.class)
+		f := newFinding(CodeDuplication, r.File, r.StartLine, r.EndLine, "",
+			fmt.Sprintf("Type-%d clone: %d tokens of %s, also at %s", r.class.Type, r.class.Tokens, kind,
+				strings.Join(others, ", ")))
+		f.Remediation = &remediation
+		findings = append(findings, f)
+	}
+
+	return findings
+}
+
+// mergeLocations sorts locations and merges those that overlap.
+func mergeLocations(locs []types.Location) []types.Location {
+	sorted := append([]types.Location(nil), locs...)
+	sort.Slice(sorted, func(i, j int) bool { return locationBefore(sorted[i], sorted[j]) })
+
+	var merged []types.Location
+
+	for _, loc := range sorted {
+		if n := len(merged); n > 0 && overlaps(merged[n-1], loc) {
+			merged[n-1].EndLine = max(merged[n-1].EndLine, loc.EndLine)
+			continue
+		}
+
+		merged = append(merged, loc)
+	}
+
+	return merged
+}
+
+// locationBefore orders locations by file and start line, longer ones first.
+func locationBefore(a, b types.Location) bool {
+	if a.File != b.File {
+		return a.File < b.File
+	}
+
+	if a.StartLine != b.StartLine {
+		return a.StartLine < b.StartLine
+	}
+
+	return a.EndLine > b.EndLine
+}
+
+// overlaps reports whether two locations share a line of the same file.
+func overlaps(a, b types.Location) bool {
+	return a.File == b.File && a.StartLine <= b.EndLine && b.StartLine <= a.EndLine
+}
+
+// cloneRemediation suggests table-driven or parametrized tests for copies
+// within one file, and shared helpers for copies spread across files.
+func cloneRemediation(class CloneClass) types.Remediation {
+	sameFile := true
+	for _, frag := range class.Fragments {
+		sameFile = sameFile && frag.File == class.Fragments[0].File
+	}
+
+	if sameFile && class.Type == CloneType2 {
+		switch class.Language {
+		case types.LanguageGo:
+			return types.Remediation{
+				Summary: "Convert the copies into one table-driven test",
+				Steps: []string{
+					"Move the values that differ between copies into a table of test cases",
+					"Loop over the table and run each case with t.Run",
+				},
+				Effort: types.EffortLow,
+			}
+		case types.LanguagePython:
+			return types.Remediation{
+				Summary: "Merge the copies into one parametrized test",
+				Steps: []string{
+					"Move the values that differ between copies into @pytest.mark.parametrize arguments",
+					"For unittest, loop over the cases with self.subTest",
+				},
+				Effort: types.EffortLow,
+			}
+		default:
+			return types.Remediation{
+				Summary: "Merge the copies into one test with test.each",
+				Steps: []string{
+					"Move the values that differ between copies into a test.each (or it.each) table",
+					"Use the table columns as parameters of the test callback",
+				},
+				Effort: types.EffortLow,
+			}
+		}
+	}
+
+	where := "a helper function in the test file"
+	if !sameFile {
+		switch class.Language {
+		case types.LanguageGo:
+			where = "a shared test helper package (call t.Helper() in it)"
+		case types.LanguagePython:
+			where = "a fixture in conftest.py or a shared helper module"
+		default:
+			where = "a shared test utility module"
+		}
+	}
+
+	return types.Remediation{
+		Summary: "Extract the repeated code into " + where,
+		Steps: []string{
+			"Move the duplicated code into " + where,
+			"Pass the values that differ between copies as parameters",
+			"Replace every copy with a call to the helper",
+		},
+		Effort: types.EffortLow,
+	}
+}
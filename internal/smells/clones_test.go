@@ -0,0 +1,304 @@
+package smells
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const cloneGoOrder = `package orders
+
+import (
+	"testing"
+
+	"example.com/shop/store"
+)
+
+func TestCreateOrder(t *testing.T) {
+	db := store.Open("mem")
+	user := store.Create("alice")
+	cart := store.NewCart(user)
+	cart.Add("apple", 2)
+	if err := store.Checkout(db, cart); err != nil {
+		t.Fatalf("checkout failed: %v", err)
+	}
+	if cart.Total() != 4 {
+		t.Errorf("total = %d", cart.Total())
+	}
+}
+`
+
+// cloneGoRenamed is cloneGoOrder with renamed identifiers and literals.
+const cloneGoRenamed = `package billing
+
+import (
+	"testing"
+
+	"example.com/shop/store"
+)
+
+func TestInvoice(t *testing.T) {
+	conn := store.Open("disk")
+	customer := store.Create("bob")
+	basket := store.NewCart(customer)
+	basket.Add("pear", 3)
+	if err := store.Checkout(conn, basket); err != nil {
+		t.Fatalf("invoice failed: %v", err)
+	}
+	if basket.Total() != 9 {
+		t.Errorf("sum = %d", basket.Total())
+	}
+}
+`
+
+// clonesOf returns the clone classes among files keyed by path.
+func clonesOf(t *testing.T, minTokens int, files map[string]string) []CloneClass {
+	t.Helper()
+
+	d := NewCloneDetector(minTokens)
+	for path, src := range files {
+		if err := d.Add(path, []byte(src)); err != nil {
+			t.Fatalf("Add(%s) error = %v", path, err)
+		}
+	}
+
+	return d.Classes()
+}
+
+func TestCloneDetectorType2AcrossFiles(t *testing.T) {
+	classes := clonesOf(t, 30, map[string]string{
+		"orders/orders_test.go":   cloneGoOrder,
+		"billing/billing_test.go": cloneGoRenamed,
+	})
+
+	if len(classes) != 1 {
+		t.Fatalf("expected 1 clone class, got %d: %+v", len(classes), classes)
+	}
+
+	c := classes[0]
+	if c.Type != CloneType2 || c.Language != types.LanguageGo || c.Tokens < 30 {
+		t.Errorf("class = %+v", c)
+	}
+
+	want := []types.Location{
+		{File: "billing/billing_test.go", StartLine: 9, EndLine: 20},
+		{File: "orders/orders_test.go", StartLine: 9, EndLine: 20},
+	}
+
+	if len(c.Fragments) != len(want) {
+		t.Fatalf("fragments = %+v", c.Fragments)
+	}
+
+	for i, frag := range c.Fragments {
+		if frag != want[i] {
+			t.Errorf("fragment %d = %+v, want %+v", i, frag, want[i])
+		}
+	}
+}
+
+func TestCloneDetectorType1(t *testing.T) {
+	src := `import pytest
+
+from app.orders import checkout, create_cart
+
+
+def test_checkout_total():
+    cart = create_cart("alice")
+    cart.add("apple", 2)
+    result = checkout(cart, express=False)
+    assert result.total == 4
+    assert result.items == ["apple", "apple"]
+`
+
+	classes := clonesOf(t, 30, map[string]string{
+		"tests/test_a.py": src,
+		"tests/test_b.py": src,
+	})
+
+	if len(classes) != 1 || classes[0].Type != CloneType1 || len(classes[0].Fragments) != 2 {
+		t.Fatalf("classes = %+v", classes)
+	}
+
+	if classes[0].Fragments[0].StartLine != 6 {
+		t.Errorf("fragment = %+v, want clone to start after the imports", classes[0].Fragments[0])
+	}
+}
+
+func TestCloneDetectorGroupsCopies(t *testing.T) {
+	test := `
+it('adds items', () => {
+  const cart = createCart('alice');
+  cart.add('apple', 2);
+  expect(cart.items).toHaveLength(2);
+  expect(cart.total()).toBe(4);
+});
+`
+
+	classes := clonesOf(t, 25, map[string]string{
+		"src/a.test.js":  test,
+		"src/b.test.ts":  strings.ReplaceAll(test, "apple", "pear"),
+		"src/c.test.tsx": strings.ReplaceAll(test, "alice", "bob"),
+	})
+
+	if len(classes) != 1 || len(classes[0].Fragments) != 3 || classes[0].Type != CloneType2 {
+		t.Fatalf("expected one class of 3 fragments, got %+v", classes)
+	}
+}
+
+func TestCloneDetectorIgnoresShortAndUnrelated(t *testing.T) {
+	tests := []struct {
+		name      string
+		minTokens int
+		files     map[string]string
+	}{
+		{
+			name:      "shorter than minimum",
+			minTokens: 200,
+			files: map[string]string{
+				"orders/orders_test.go":   cloneGoOrder,
+				"billing/billing_test.go": cloneGoRenamed,
+			},
+		},
+		{
+			name:      "shared imports only",
+			minTokens: 15,
+			files: map[string]string{
+				"a/a_test.go": "package a\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n\t\"testing\"\n)\n\nfunc TestA(t *testing.T) {}\n",
+				"b/b_test.go": "package b\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n\t\"testing\"\n)\n\nfunc TestB(t *testing.T) { t.Skip() }\n",
+			},
+		},
+		{
+			name:      "different languages",
+			minTokens: 10,
+			files: map[string]string{
+				"a/a_test.go":  "package a\n\nfunc TestA(t *testing.T) { a := b(c); d(a, 1); e(a, 2); f(a, 3) }\n",
+				"tests/a.java": "class A { void a() { a = b(c); d(a, 1); e(a, 2); f(a, 3); } }\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if classes := clonesOf(t, tt.minTokens, tt.files); len(classes) != 0 {
+				t.Errorf("expected no clones, got %+v", classes)
+			}
+		})
+	}
+}
+
+func TestCloneRemediation(t *testing.T) {
+	tests := []struct {
+		name  string
+		class CloneClass
+		want  string
+	}{
+		{
+			name: "renamed copies in one Go file",
+			class: CloneClass{Type: CloneType2, Language: types.LanguageGo, Fragments: []types.Location{
+				{File: "a_test.go", StartLine: 1}, {File: "a_test.go", StartLine: 20},
+			}},
+			want: "table-driven",
+		},
+		{
+			name: "renamed copies in one Python file",
+			class: CloneClass{Type: CloneType2, Language: types.LanguagePython, Fragments: []types.Location{
+				{File: "test_a.py", StartLine: 1}, {File: "test_a.py", StartLine: 20},
+			}},
+			want: "parametrized",
+		},
+		{
+			name: "renamed copies in one JavaScript file",
+			class: CloneClass{Type: CloneType2, Language: types.LanguageJavaScript, Fragments: []types.Location{
+				{File: "a.test.js", StartLine: 1}, {File: "a.test.js", StartLine: 20},
+			}},
+			want: "test.each",
+		},
+		{
+			name: "copies across Go files",
+			class: CloneClass{Type: CloneType2, Language: types.LanguageGo, Fragments: []types.Location{
+				{File: "a_test.go", StartLine: 1}, {File: "b_test.go", StartLine: 1},
+			}},
+			want: "shared test helper",
+		},
+		{
+			name: "identical copies in one file",
+			class: CloneClass{Type: CloneType1, Language: types.LanguagePython, Fragments: []types.Location{
+				{File: "test_a.py", StartLine: 1}, {File: "test_a.py", StartLine: 20},
+			}},
+			want: "helper function",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cloneRemediation(tt.class).Summary; !strings.Contains(got, tt.want) {
+				t.Errorf("summary = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloneFindingsMergesOverlappingFragments(t *testing.T) {
+	classes := []CloneClass{
+		{Type: CloneType1, Language: types.LanguageGo, Tokens: 60, Fragments: []types.Location{
+			{File: "a_test.go", StartLine: 1, EndLine: 10}, {File: "b_test.go", StartLine: 1, EndLine: 10},
+		}},
+		{Type: CloneType2, Language: types.LanguageGo, Tokens: 80, Fragments: []types.Location{
+			{File: "a_test.go", StartLine: 5, EndLine: 15}, {File: "c_test.go", StartLine: 3, EndLine: 13},
+		}},
+		{Type: CloneType1, Language: types.LanguageGo, Tokens: 50, Fragments: []types.Location{
+			{File: "a_test.go", StartLine: 6, EndLine: 9}, {File: "b_test.go", StartLine: 2, EndLine: 8},
+		}},
+	}
+
+	findings := cloneFindings(classes)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.Location.String()+" "+f.Description)
+	}
+
+	want := []string{
+		"a_test.go:1-15 Type-2 clone: 80 tokens of code differing only in identifiers and literals, also at b_test.go:1-10, c_test.go:3-13",
+		"b_test.go:1-10 Type-1 clone: 60 tokens of identical code, also at a_test.go:1-10",
+		"c_test.go:3-13 Type-2 clone: 80 tokens of code differing only in identifiers and literals, also at a_test.go:5-15",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestEngineReportsClonesWithinFile(t *testing.T) {
+	src := cloneGoOrder + strings.ReplaceAll(cloneGoRenamed[strings.Index(cloneGoRenamed, "func"):], "TestInvoice", "TestInvoiceOrder")
+
+	cfg := DefaultConfig()
+	cfg.MinCloneTokens = 30
+
+	findings, err := NewEngine(nil, cfg).AnalyzeFile("orders/orders_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	var clones []types.Finding
+
+	for _, f := range findings {
+		if f.CheckID == string(CodeDuplication) {
+			clones = append(clones, f)
+		}
+	}
+
+	if len(clones) != 2 {
+		t.Fatalf("expected a finding per copy, got %+v", clones)
+	}
+
+	if !strings.Contains(clones[0].Description, "also at orders/orders_test.go:21-32") ||
+		clones[0].Remediation == nil || !strings.Contains(clones[0].Remediation.Summary, "table-driven") {
+		t.Errorf("finding = %+v", clones[0])
+	}
+
+	if clones[0].Severity != types.SeverityLow || clones[0].Title != "Code Duplication" {
+		t.Errorf("finding = %+v", clones[0])
+	}
+}
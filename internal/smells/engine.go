@@ -0,0 +1,268 @@
+package smells
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/flakiness"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// Detector finds test smells in a single test file.
+type Detector interface {
+	// Detect returns the smells found in the file at relPath. Findings only
+	// need CheckID, Location, Test and Description; the engine fills in
+	// severity, title, rationale and (unless set) remediation from the catalog.
+	Detect(relPath string, src []byte) ([]types.Finding, error)
+}
+
+// Engine runs the smell detectors over a repository.
+type Engine struct {
+	walker    *discovery.Walker
+	config    *Config
+	detectors map[types.Language]Detector
+}
+
+// NewEngine creates an engine with detectors for all supported languages.
+func NewEngine(walker *discovery.Walker, config *Config) *Engine {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	return &Engine{
+		walker: walker,
+		config: config,
+		detectors: map[types.Language]Detector{
+			types.LanguageGo:         NewGoDetector(),
+			types.LanguagePython:     NewPythonDetector(),
+			types.LanguageJavaScript: NewJavaScriptDetector(),
+			types.LanguageTypeScript: NewJavaScriptDetector(),
+		},
+	}
+}
+
+// Analyze walks the repository and returns the smells of every test file
+// and conftest.py, sorted by file, line and smell. Code duplication is
+// detected across all test files of a language. Findings covered by
+// shipshape:ignore comments or configured suppressions are dropped. Files
+// that cannot be read or parsed are logged and skipped.
+func (e *Engine) Analyze() ([]types.Finding, error) {
+	findings := []types.Finding{}
+
+	if !e.config.Enabled {
+		return findings, nil
+	}
+
+	clones := NewCloneDetector(e.config.MinCloneTokens)
+	sources := make(map[string][]byte)
+	byFile := make(map[string][]types.Finding)
+
+	_, err := e.walker.Walk(func(fi discovery.FileInfo) error {
+		if !discovery.IsTestFile(fi.RelPath) && fi.Name != "conftest.py" {
+			return nil
+		}
+
+		if _, ok := e.detectors[discovery.LanguageOf(fi.Name)]; !ok {
+			return nil
+		}
+
+		src, err := os.ReadFile(fi.Path) //nolint:gosec // Reading source files from repository
+		if err != nil {
+			logger.Warn("Failed to read test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		relPath := filepath.ToSlash(fi.RelPath)
+
+		fileFindings, err := e.analyzeFile(relPath, src)
+		if err != nil {
+			logger.Warn("Failed to analyze test file", "path", fi.RelPath, "error", err)
+			return nil
+		}
+
+		sources[relPath] = src
+		byFile[relPath] = fileFindings
+
+		if err := clones.Add(relPath, src); err != nil {
+			logger.Warn("Failed to tokenize test file for clone detection", "path", fi.RelPath, "error", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk repository: %w", err)
+	}
+
+	for _, f := range e.cloneFindings(clones) {
+		byFile[f.Location.File] = append(byFile[f.Location.File], f)
+	}
+
+	for relPath, fileFindings := range byFile {
+		findings = append(findings, e.finish(relPath, sources[relPath], fileFindings)...)
+	}
+
+	SortFindings(findings)
+
+	return findings, nil
+}
+
+// AnalyzeFile runs the detector for the file's language and applies the
+// configuration and suppressions. Code duplication is detected within the
+// file only. Files in unsupported languages yield no findings.
+func (e *Engine) AnalyzeFile(relPath string, src []byte) ([]types.Finding, error) {
+	findings, err := e.analyzeFile(relPath, src)
+	if err != nil || !e.config.Enabled {
+		return findings, err
+	}
+
+	clones := NewCloneDetector(e.config.MinCloneTokens)
+	if err := clones.Add(relPath, src); err != nil {
+		return nil, err
+	}
+
+	return e.finish(relPath, src, append(findings, e.cloneFindings(clones)...)), nil
+}
+
+// finish fingerprints the findings of a file and drops the suppressed ones.
+func (e *Engine) finish(relPath string, src []byte, findings []types.Finding) []types.Finding {
+	SortFindings(findings)
+	fingerprint(findings, src)
+
+	inline := parseInlineSuppressions(relPath, src)
+	kept := make([]types.Finding, 0, len(findings))
+
+	for _, f := range findings {
+		if e.suppressed(f, inline) {
+			logger.Debug("Suppressed smell", "id", f.ID)
+			continue
+		}
+
+		kept = append(kept, f)
+	}
+
+	return kept
+}
+
+// analyzeFile runs the detector for the file's language on its own.
+func (e *Engine) analyzeFile(relPath string, src []byte) ([]types.Finding, error) {
+	detector, ok := e.detectors[discovery.LanguageOf(relPath)]
+	if !ok || !e.config.Enabled {
+		return nil, nil
+	}
+
+	raw, err := detector.Detect(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]types.Finding, 0, len(raw))
+
+	for _, f := range raw {
+		smell := Smell(f.CheckID)
+		if !e.config.IsEnabled(smell) {
+			continue
+		}
+
+		findings = append(findings, e.complete(smell, f))
+	}
+
+	SortFindings(findings)
+
+	return findings, nil
+}
+
+// cloneFindings reports the clone classes found by the clone detector.
+func (e *Engine) cloneFindings(clones *CloneDetector) []types.Finding {
+	if !e.config.IsEnabled(CodeDuplication) {
+		return nil
+	}
+
+	raw := cloneFindings(clones.Classes())
+	findings := make([]types.Finding, 0, len(raw))
+
+	for _, f := range raw {
+		findings = append(findings, e.complete(CodeDuplication, f))
+	}
+
+	return findings
+}
+
+// complete fills in the catalog fields and configured severity of a finding.
+func (e *Engine) complete(smell Smell, f types.Finding) types.Finding {
+	def := Catalog[smell]
+
+	f.Type = def.Type
+	f.Title = def.Title
+	f.Severity = e.config.SeverityOf(smell)
+	f.Rationale = def.Rationale
+
+	if f.Remediation == nil {
+		remediation := def.Remediation
+		f.Remediation = &remediation
+	}
+	f.ID = fmt.Sprintf("%s@%s:%d", smell, f.Location.File, f.Location.StartLine)
+
+	return f
+}
+
+// SortFindings orders findings by file, start line and check.
+func SortFindings(findings []types.Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i].Location, findings[j].Location
+		if a.File != b.File {
+			return a.File < b.File
+		}
+
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+
+		return findings[i].CheckID < findings[j].CheckID
+	})
+}
+
+// newFinding creates a detector finding for a smell at a line range.
+func newFinding(smell Smell, file string, start, end int, test, description string) types.Finding {
+	return types.Finding{
+		CheckID:     string(smell),
+		Location:    types.Location{File: file, StartLine: start, EndLine: end},
+		Test:        test,
+		Description: description,
+	}
+}
+
+// flakinessPhrases describe the signals of the flakiness detectors that are
+// reported as the Flakiness smell. Network, global state and temporary
+// path signals are left to the flakiness risk report.
+var flakinessPhrases = map[flakiness.Signal]string{
+	flakiness.Sleep:       "waits on the clock instead of synchronizing",
+	flakiness.WallClock:   "depends on the current time",
+	flakiness.Randomness:  "depends on unseeded randomness",
+	flakiness.Concurrency: "does not wait for concurrent work",
+}
+
+// flakinessFindings reports the timing, randomness and concurrency signals
+// the flakiness detector finds in a file.
+func flakinessFindings(detector flakiness.Detector, relPath string, src []byte) ([]types.Finding, error) {
+	risks, err := detector.Detect(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []types.Finding
+
+	for _, risk := range risks {
+		for _, e := range risk.Signals {
+			if phrase, ok := flakinessPhrases[e.Signal]; ok {
+				findings = append(findings, newFinding(Flakiness, relPath, e.Line, e.Line, risk.Test,
+					fmt.Sprintf("%s %s (%s)", risk.Name, phrase, e.Detail)))
+			}
+		}
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,103 @@
+package smells
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/testutil"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const sleepyTest = `package app
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWaits(t *testing.T) {
+	time.Sleep(time.Second)
+	_, _ = os.ReadFile("config.json")
+}
+`
+
+func TestEngineAnalyze(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "app/app.go", "package app\n")
+	testutil.WriteFile(t, dir, "app/app_test.go", sleepyTest)
+	testutil.WriteFile(t, dir, "app/test_app.py", "def test_parses_numbers():\n    assert int(\"1\") == 1\n")
+	testutil.WriteFile(t, dir, "app/AppTest.java", "class AppTest { void testX() { Thread.sleep(10); } }")
+
+	findings, err := NewEngine(discovery.NewWalker(dir), nil).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d: %+v", len(findings), findings)
+	}
+
+	for _, f := range findings {
+		if f.Location.File != "app/app_test.go" {
+			t.Errorf("unexpected file %q", f.Location.File)
+		}
+
+		if f.Title == "" || f.Rationale == "" || f.Remediation == nil || f.ID == "" {
+			t.Errorf("finding not completed from catalog: %+v", f)
+		}
+	}
+
+	if findings[0].CheckID != string(Flakiness) || findings[0].Severity != types.SeverityHigh {
+		t.Errorf("first finding = %s/%s, want flakiness/high", findings[0].CheckID, findings[0].Severity)
+	}
+
+	if findings[0].ID != "flakiness@app/app_test.go:10" {
+		t.Errorf("ID = %q", findings[0].ID)
+	}
+}
+
+func TestEngineAppliesConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Disabled[MysteryGuest] = true
+	cfg.SeverityOverrides[Flakiness] = types.SeverityCritical
+
+	findings, err := NewEngine(nil, cfg).AnalyzeFile("app/app_test.go", []byte(sleepyTest))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(findings), findings)
+	}
+
+	if findings[0].CheckID != string(Flakiness) || findings[0].Severity != types.SeverityCritical {
+		t.Errorf("finding = %s/%s, want flakiness/critical", findings[0].CheckID, findings[0].Severity)
+	}
+
+	cfg.Enabled = false
+
+	findings, err = NewEngine(nil, cfg).AnalyzeFile("app/app_test.go", []byte(sleepyTest))
+	if err != nil || len(findings) != 0 {
+		t.Errorf("expected no findings when disabled, got %v (err %v)", findings, err)
+	}
+}
+
+func TestEngineAnalyzesConftest(t *testing.T) {
+	dir := testutil.TempDir(t)
+	testutil.WriteFile(t, dir, "tests/conftest.py", `import pytest
+
+@pytest.fixture
+def world():
+    return {"user": 1, "cart": 2, "db": 3}
+`)
+
+	findings, err := NewEngine(discovery.NewWalker(dir), nil).Analyze()
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if len(findings) != 1 || findings[0].CheckID != string(GeneralFixture) {
+		t.Errorf("expected one general-fixture finding in conftest.py, got %+v", findings)
+	}
+}
@@ -0,0 +1,925 @@
+package smells
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/flakiness"
+	"github.com/chambridge/ship-shape/internal/goast"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// goBuiltins are predeclared functions that are never the code under test.
+var goBuiltins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true, "copy": true,
+	"delete": true, "imag": true, "len": true, "make": true, "max": true, "min": true,
+	"new": true, "panic": true, "print": true, "println": true, "real": true, "recover": true,
+}
+
+// goPredeclaredTypes are the predeclared types, whose conversions such as
+// string(b) are not calls of the code under test.
+var goPredeclaredTypes = map[string]bool{
+	"any": true, "bool": true, "byte": true, "comparable": true, "complex64": true, "complex128": true,
+	"error": true, "float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true, "uint8": true,
+	"uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+}
+
+// testSupportSegments are import path segments of testing libraries.
+var testSupportSegments = map[string]bool{
+	"test": true, "tests": true, "testing": true, "testify": true, "testutil": true, "testutils": true,
+	"testhelpers": true, "go-cmp": true, "gomega": true, "ginkgo": true, "quicktest": true,
+	"httptest": true, "iotest": true, "fstest": true, "envtest": true, "slogtest": true, "gotest.tools": true,
+}
+
+// testifyArity is the number of arguments, including t, that a testify
+// assertion takes before its optional message.
+var testifyArity = map[string]int{
+	"True": 2, "False": 2, "Nil": 2, "NotNil": 2, "Empty": 2, "NotEmpty": 2, "NoError": 2,
+	"Error": 2, "Zero": 2, "NotZero": 2, "Panics": 2, "NotPanics": 2, "FileExists": 2,
+	"NoFileExists": 2, "DirExists": 2, "NoDirExists": 2, "Condition": 2, "Fail": 2, "FailNow": 2,
+	"Equal": 3, "NotEqual": 3, "EqualValues": 3, "NotEqualValues": 3, "Exactly": 3, "Same": 3,
+	"NotSame": 3, "Contains": 3, "NotContains": 3, "Subset": 3, "NotSubset": 3, "ElementsMatch": 3,
+	"Len": 3, "Greater": 3, "GreaterOrEqual": 3, "Less": 3, "LessOrEqual": 3, "ErrorIs": 3,
+	"NotErrorIs": 3, "ErrorAs": 3, "ErrorContains": 3, "EqualError": 3, "IsType": 3,
+	"Implements": 3, "Regexp": 3, "NotRegexp": 3, "JSONEq": 3, "YAMLEq": 3, "PanicsWithValue": 3,
+	"PanicsWithError": 3, "InDelta": 4, "InEpsilon": 4, "WithinDuration": 4, "Eventually": 4,
+	"Never": 4, "EventuallyWithT": 4,
+}
+
+// testifyEquality are the testify assertions that compare two values.
+var testifyEquality = map[string]bool{
+	"Equal": true, "NotEqual": true, "EqualValues": true, "NotEqualValues": true, "Exactly": true,
+}
+
+// GoDetector detects test smells in Go test files using the go/ast package.
+type GoDetector struct {
+	flaky *flakiness.GoDetector
+}
+
+// NewGoDetector creates a Go smell detector.
+func NewGoDetector() *GoDetector {
+	return &GoDetector{flaky: flakiness.NewGoDetector("")}
+}
+
+// goFile holds the state of the analysis of one Go test file.
+type goFile struct {
+	*goast.File
+
+	helpers  map[string]bool
+	findings []types.Finding
+}
+
+// goTest is a test function or a testify suite test method.
+type goTest struct {
+	fn      *ast.FuncDecl
+	id      string
+	recv    string
+	recvTyp string
+	tNames  map[string]bool
+}
+
+// Detect parses a Go test file and runs every smell check on its tests.
+func (d *GoDetector) Detect(relPath string, src []byte) ([]types.Finding, error) {
+	file, err := goast.Parse(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	flaky, err := flakinessFindings(d.flaky, relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &goFile{File: file, helpers: make(map[string]bool)}
+
+	var tests []*goTest
+
+	for _, decl := range file.AST.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		if fn.Recv == nil {
+			g.helpers[fn.Name.Name] = true
+		}
+
+		if t := g.asTest(fn); t != nil {
+			tests = append(tests, t)
+		}
+	}
+
+	for _, t := range tests {
+		g.checkMysteryGuest(t)
+		g.checkResourceOptimism(t)
+		g.checkAssertions(t)
+		g.checkConditionalLogic(t)
+		g.checkObscure(t)
+		g.checkSensitiveEquality(t)
+	}
+
+	g.checkGeneralFixture(tests)
+
+	g.findings = append(g.findings, flaky...)
+	SortFindings(g.findings)
+
+	return g.findings, nil
+}
+
+// asTest returns the test described by fn, or nil if fn is not a test.
+func (g *goFile) asTest(fn *ast.FuncDecl) *goTest {
+	name := fn.Name.Name
+	if !goast.IsTestName(name) {
+		return nil
+	}
+
+	t := &goTest{fn: fn, id: g.Path + "::" + name, tNames: make(map[string]bool)}
+
+	if fn.Recv != nil && len(fn.Recv.List) == 1 {
+		field := fn.Recv.List[0]
+		if len(field.Names) == 1 {
+			t.recv = field.Names[0].Name
+		}
+
+		t.recvTyp = goast.ReceiverType(field.Type)
+		t.id = g.Path + "::" + t.recvTyp + "::" + name
+	} else if !goast.HasTestingParam(fn.Type) {
+		return nil
+	}
+
+	ast.Inspect(fn, func(n ast.Node) bool {
+		var ft *ast.FuncType
+
+		switch x := n.(type) {
+		case *ast.FuncDecl:
+			ft = x.Type
+		case *ast.FuncLit:
+			ft = x.Type
+		default:
+			return true
+		}
+
+		for _, field := range ft.Params.List {
+			if goast.IsTestingType(field.Type) {
+				for _, id := range field.Names {
+					t.tNames[id.Name] = true
+				}
+			}
+		}
+
+		return true
+	})
+
+	return t
+}
+
+func (g *goFile) report(smell Smell, node ast.Node, t *goTest, format string, args ...any) {
+	test := ""
+	if t != nil {
+		test = t.id
+	}
+
+	g.findings = append(g.findings, newFinding(smell, g.Path, g.Line(node.Pos()), g.Line(node.End()),
+		test, fmt.Sprintf(format, args...)))
+}
+
+// assertion classifies a call as a testify ("testify") or testing package
+// ("testing") assertion and reports whether it carries a failure message.
+func (g *goFile) assertion(call *ast.CallExpr, t *goTest) (string, bool, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false, false
+	}
+
+	name := sel.Sel.Name
+	offset := 0
+
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		switch {
+		case t.tNames[x.Name]:
+			switch name {
+			case "Error", "Errorf", "Fatal", "Fatalf":
+				return "testing", len(call.Args) > 0, true
+			case "Fail", "FailNow":
+				return "testing", false, true
+			}
+
+			return "", false, false
+		case isTestifyImport(g.Imports[x.Name]):
+		case t.recv != "" && x.Name == t.recv:
+			offset = 1
+		default:
+			return "", false, false
+		}
+	case *ast.CallExpr:
+		inner, ok := x.Fun.(*ast.SelectorExpr)
+		if !ok || (inner.Sel.Name != "Require" && inner.Sel.Name != "Assert") {
+			return "", false, false
+		}
+
+		offset = 1
+	default:
+		return "", false, false
+	}
+
+	if arity, ok := testifyArity[name]; ok {
+		return "testify", len(call.Args) > arity-offset, true
+	}
+
+	if base := strings.TrimSuffix(name, "f"); base != name {
+		if _, ok := testifyArity[base]; ok {
+			return "testify", true, true
+		}
+	}
+
+	return "", false, false
+}
+
+func isTestifyImport(importPath string) bool {
+	return strings.HasSuffix(importPath, "testify/assert") || strings.HasSuffix(importPath, "testify/require")
+}
+
+// isSubtest reports whether call is t.Run or a suite's s.Run.
+func (g *goFile) isSubtest(call *ast.CallExpr, t *goTest) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Run" {
+		return false
+	}
+
+	x, ok := sel.X.(*ast.Ident)
+
+	return ok && (t.tNames[x.Name] || (t.recv != "" && x.Name == t.recv))
+}
+
+func (g *goFile) hasSubtests(node ast.Node, t *goTest) bool {
+	for _, call := range goast.Calls(node) {
+		if g.isSubtest(call, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// literalPath resolves a path built from string literals, including
+// filepath.Join calls whose arguments are all literals.
+func (g *goFile) literalPath(expr ast.Expr) (string, bool) {
+	if s, ok := goast.StringLit(expr); ok {
+		return s, true
+	}
+
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+
+	if _, ok := g.PkgCall(call, "path/filepath", "Join"); !ok {
+		if _, ok := g.PkgCall(call, "path", "Join"); !ok {
+			return "", false
+		}
+	}
+
+	parts := make([]string, 0, len(call.Args))
+
+	for _, arg := range call.Args {
+		s, ok := goast.StringLit(arg)
+		if !ok {
+			return "", false
+		}
+
+		parts = append(parts, s)
+	}
+
+	return path.Join(parts...), true
+}
+
+func underTestdata(p string) bool {
+	p = path.Clean(strings.ReplaceAll(p, "\\", "/"))
+
+	return p == "testdata" || strings.HasPrefix(p, "testdata/") || strings.Contains(p, "/testdata/")
+}
+
+// literalPaths collects the literal paths passed to the named os functions.
+func (g *goFile) literalPaths(node ast.Node, names ...string) map[string]bool {
+	paths := make(map[string]bool)
+
+	for _, call := range goast.Calls(node) {
+		if _, ok := g.PkgCall(call, "os", names...); ok && len(call.Args) > 0 {
+			if p, ok := g.literalPath(call.Args[0]); ok {
+				paths[path.Clean(p)] = true
+			}
+		}
+	}
+
+	return paths
+}
+
+// checkMysteryGuest reports reads of files, environment variables, external
+// URLs and databases that the test does not set up itself.
+//
+//nolint:gocognit,gocyclo // One case per kind of external dependency
+func (g *goFile) checkMysteryGuest(t *goTest) {
+	created := g.literalPaths(t.fn.Body, "Create", "WriteFile", "Mkdir", "MkdirAll", "OpenFile")
+	setenv := make(map[string]bool)
+
+	for _, call := range goast.Calls(t.fn.Body) {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && sel.Sel.Name == "Setenv" && len(call.Args) > 0 {
+			if key, ok := goast.StringLit(call.Args[0]); ok {
+				setenv[key] = true
+			}
+		}
+	}
+
+	for _, call := range goast.Calls(t.fn.Body) {
+		if name, ok := g.PkgCall(call, "os", "Open", "ReadFile", "ReadDir"); ok && len(call.Args) > 0 {
+			if p, ok := g.literalPath(call.Args[0]); ok && !underTestdata(p) && !created[path.Clean(p)] {
+				g.report(MysteryGuest, call, t, "%s reads %q (os.%s), which the test does not create", t.fn.Name.Name, p, name)
+			}
+
+			continue
+		}
+
+		if _, ok := g.PkgCall(call, "io/ioutil", "ReadFile", "ReadDir"); ok && len(call.Args) > 0 {
+			if p, ok := g.literalPath(call.Args[0]); ok && !underTestdata(p) && !created[path.Clean(p)] {
+				g.report(MysteryGuest, call, t, "%s reads %q, which the test does not create", t.fn.Name.Name, p)
+			}
+
+			continue
+		}
+
+		if name, ok := g.PkgCall(call, "os", "Getenv", "LookupEnv"); ok && len(call.Args) > 0 {
+			if key, ok := goast.StringLit(call.Args[0]); ok && !setenv[key] {
+				g.report(MysteryGuest, call, t, "%s depends on environment variable %s (os.%s) without setting it",
+					t.fn.Name.Name, key, name)
+			}
+
+			continue
+		}
+
+		if name, ok := g.PkgCall(call, "net/http", "Get", "Head", "Post", "PostForm", "NewRequest", "NewRequestWithContext"); ok {
+			for _, arg := range call.Args {
+				if url, ok := goast.StringLit(arg); ok && isExternalURL(url) {
+					g.report(MysteryGuest, call, t, "%s calls external service %s (http.%s)", t.fn.Name.Name, url, name)
+					break
+				}
+			}
+
+			continue
+		}
+
+		if _, ok := g.PkgCall(call, "database/sql", "Open"); ok {
+			g.report(MysteryGuest, call, t, "%s opens a real database connection (sql.Open)", t.fn.Name.Name)
+		}
+	}
+}
+
+// isExternalURL reports whether a URL points at a host other than the local machine.
+func isExternalURL(url string) bool {
+	rest, ok := strings.CutPrefix(url, "http://")
+	if !ok {
+		if rest, ok = strings.CutPrefix(url, "https://"); !ok {
+			return false
+		}
+	}
+
+	host := rest
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		host = host[:i]
+	}
+
+	switch host {
+	case "localhost", "127.0.0.1", "[::1]", "0.0.0.0", "":
+		return false
+	default:
+		return true
+	}
+}
+
+// checkResourceOptimism reports fixed paths and ports, files created without
+// cleanup and files opened without being closed.
+//
+//nolint:gocognit // One case per kind of resource
+func (g *goFile) checkResourceOptimism(t *goTest) {
+	removed := g.literalPaths(t.fn.Body, "Remove", "RemoveAll")
+	closed := make(map[string]bool)
+
+	ast.Inspect(t.fn.Body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok && sel.Sel.Name == "Close" {
+			if x, ok := sel.X.(*ast.Ident); ok {
+				closed[x.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	for _, call := range goast.Calls(t.fn.Body) {
+		if name, ok := g.PkgCall(call, "os", "Create", "WriteFile", "Mkdir", "MkdirAll", "OpenFile"); ok && len(call.Args) > 0 {
+			p, ok := g.literalPath(call.Args[0])
+			if !ok || underTestdata(p) {
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(p, "/") || (len(p) > 2 && p[1] == ':'):
+				g.report(ResourceOptimism, call, t, "%s writes to fixed path %q (os.%s)", t.fn.Name.Name, p, name)
+			case !removed[path.Clean(p)]:
+				g.report(ResourceOptimism, call, t, "%s creates %q (os.%s) without removing it", t.fn.Name.Name, p, name)
+			}
+
+			continue
+		}
+
+		listen, isListen := g.PkgCall(call, "net", "Listen")
+		if !isListen {
+			listen, isListen = g.PkgCall(call, "net/http", "ListenAndServe")
+		}
+
+		if isListen {
+			for _, arg := range call.Args {
+				if addr, ok := goast.StringLit(arg); ok && hasFixedPort(addr) {
+					g.report(ResourceOptimism, call, t, "%s listens on fixed address %q (%s) instead of port 0",
+						t.fn.Name.Name, addr, listen)
+				}
+			}
+		}
+	}
+
+	ast.Inspect(t.fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 {
+			return true
+		}
+
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		if _, ok := g.PkgCall(call, "os", "Create", "Open", "OpenFile"); !ok {
+			return true
+		}
+
+		if id, ok := assign.Lhs[0].(*ast.Ident); ok && id.Name != "_" && !closed[id.Name] {
+			g.report(ResourceOptimism, assign, t, "%s opens file %s without closing it", t.fn.Name.Name, id.Name)
+		}
+
+		return true
+	})
+}
+
+func hasFixedPort(addr string) bool {
+	i := strings.LastIndex(addr, ":")
+	if i < 0 {
+		return false
+	}
+
+	port := addr[i+1:]
+
+	return port != "" && port != "0"
+}
+
+// checkAssertions reports assertion roulette, eager tests and lazy tests.
+//
+//nolint:gocognit // Shares one pass over calls between three assertion-based smells
+func (g *goFile) checkAssertions(t *goTest) {
+	assertions, messageless := 0, 0
+
+	for _, call := range goast.Calls(t.fn.Body) {
+		if _, hasMsg, ok := g.assertion(call, t); ok {
+			assertions++
+
+			if !hasMsg {
+				messageless++
+			}
+		}
+	}
+
+	name := t.fn.Name.Name
+
+	if messageless >= rouletteMinAssertions {
+		g.report(AssertionRoulette, t.fn, t, "%s has %d assertions without failure messages", name, messageless)
+	}
+
+	if g.hasSubtests(t.fn.Body, t) {
+		return
+	}
+
+	locals := goast.LocalNames(t.fn.Body)
+	callees := make(map[string][]*ast.CallExpr)
+
+	var order []string
+
+	ast.Inspect(t.fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return false
+		case *ast.CallExpr:
+			if callee := g.productionCallee(x, locals); callee != "" {
+				if _, seen := callees[callee]; !seen {
+					order = append(order, callee)
+				}
+
+				callees[callee] = append(callees[callee], x)
+			}
+		}
+
+		return true
+	})
+
+	if len(order) >= eagerMinCalls && assertions >= eagerMinAssertions {
+		g.report(EagerTest, t.fn, t, "%s exercises %d different functions (%s) with %d assertions",
+			name, len(order), strings.Join(order, ", "), assertions)
+	}
+
+	for _, callee := range order {
+		if sites := callees[callee]; len(sites) >= lazyMinCalls && g.distinctArgs(sites) >= lazyMinCalls {
+			g.report(LazyTest, t.fn, t, "%s checks %d scenarios of %s in sequence", name, len(sites), callee)
+			break
+		}
+	}
+}
+
+// productionCallee names the function under test invoked by call, or ""
+// for builtins, type conversions, test helpers, locals and standard or
+// test-support packages.
+func (g *goFile) productionCallee(call *ast.CallExpr, locals map[string]bool) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		if goBuiltins[fn.Name] || goPredeclaredTypes[fn.Name] || g.helpers[fn.Name] || locals[fn.Name] {
+			return ""
+		}
+
+		if fn.Obj != nil && fn.Obj.Kind == ast.Typ {
+			return ""
+		}
+
+		return fn.Name
+	case *ast.SelectorExpr:
+		pkg, ok := fn.X.(*ast.Ident)
+		if !ok || locals[pkg.Name] {
+			return ""
+		}
+
+		importPath, ok := g.Imports[pkg.Name]
+		if !ok || isStdlib(importPath) || isTestSupport(importPath) {
+			return ""
+		}
+
+		return pkg.Name + "." + fn.Sel.Name
+	default:
+		return ""
+	}
+}
+
+func isStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// isTestSupport reports whether an import path is a testing library: one
+// with a segment such as testing, testify or gomega, a segment starting
+// with mock, or a segment naming a test package like foo-test or foo_test.
+func isTestSupport(importPath string) bool {
+	for _, seg := range strings.Split(importPath, "/") {
+		if testSupportSegments[seg] || strings.HasPrefix(seg, "mock") ||
+			strings.HasSuffix(seg, "-test") || strings.HasSuffix(seg, "_test") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *goFile) distinctArgs(sites []*ast.CallExpr) int {
+	seen := make(map[string]bool)
+
+	for _, call := range sites {
+		var args []string
+		for _, arg := range call.Args {
+			args = append(args, g.Text(arg))
+		}
+
+		seen[strings.Join(args, ",")] = true
+	}
+
+	return len(seen)
+}
+
+// checkConditionalLogic reports branches and loops that make assertions conditional.
+// The idiomatic "if got != want { t.Errorf(...) }" guard is not a smell.
+//
+//nolint:gocognit // Distinguishes idiomatic guards and table loops from conditional logic
+func (g *goFile) checkConditionalLogic(t *goTest) {
+	tables := localTables(t.fn.Body)
+	reported := make(map[ast.Node]bool)
+
+	ast.Inspect(t.fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.IfStmt:
+			if reported[x] {
+				return true
+			}
+
+			switch {
+			case x.Else != nil:
+				g.report(ConditionalLogic, x, t, "%s branches with if/else around test logic", t.fn.Name.Name)
+
+				for e := x.Else; e != nil; {
+					elseIf, ok := e.(*ast.IfStmt)
+					if !ok {
+						break
+					}
+
+					reported[elseIf] = true
+					e = elseIf.Else
+				}
+			case g.containsTestify(x.Body, t):
+				g.report(ConditionalLogic, x, t, "%s makes assertions only when a condition holds", t.fn.Name.Name)
+			}
+		case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+			g.report(ConditionalLogic, x, t, "%s switches between different test paths", t.fn.Name.Name)
+		case *ast.ForStmt:
+			if !g.hasSubtests(x.Body, t) && g.containsAssertion(x.Body, t) {
+				g.report(ConditionalLogic, x, t, "%s asserts inside a loop instead of using subtests", t.fn.Name.Name)
+			}
+		case *ast.RangeStmt:
+			if _, ok := x.X.(*ast.CompositeLit); ok {
+				return true
+			}
+
+			if id, ok := x.X.(*ast.Ident); ok && tables[id.Name] {
+				return true
+			}
+
+			if !g.hasSubtests(x.Body, t) && g.containsAssertion(x.Body, t) {
+				g.report(ConditionalLogic, x, t, "%s asserts inside a loop instead of using subtests", t.fn.Name.Name)
+			}
+		}
+
+		return true
+	})
+}
+
+// localTables returns the local variables initialized with composite literals.
+func localTables(body *ast.BlockStmt) map[string]bool {
+	tables := make(map[string]bool)
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok && len(assign.Lhs) == len(assign.Rhs) {
+			for i, rhs := range assign.Rhs {
+				if _, ok := rhs.(*ast.CompositeLit); ok {
+					if id, ok := assign.Lhs[i].(*ast.Ident); ok {
+						tables[id.Name] = true
+					}
+				}
+			}
+		}
+
+		return true
+	})
+
+	return tables
+}
+
+func (g *goFile) containsAssertion(node ast.Node, t *goTest) bool {
+	for _, call := range goast.Calls(node) {
+		if _, _, ok := g.assertion(call, t); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *goFile) containsTestify(node ast.Node, t *goTest) bool {
+	for _, call := range goast.Calls(node) {
+		if style, _, ok := g.assertion(call, t); ok && style == "testify" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkObscure reports tests with meaningless names or overly long bodies.
+// Statements of subtests and other closures are counted separately.
+func (g *goFile) checkObscure(t *goTest) {
+	name := t.fn.Name.Name
+	subject := strings.ToLower(strings.TrimRight(strings.Trim(strings.TrimPrefix(name, "Test"), "_"), "0123456789"))
+
+	if obscureNames[subject] {
+		g.report(ObscureTest, t.fn.Name, t, "%s does not describe the behavior under test", name)
+	}
+
+	statements := 0
+
+	ast.Inspect(t.fn.Body, func(n ast.Node) bool {
+		if _, ok := n.(*ast.FuncLit); ok {
+			return false
+		}
+
+		if _, ok := n.(ast.Stmt); ok {
+			if _, block := n.(*ast.BlockStmt); !block {
+				statements++
+			}
+		}
+
+		return true
+	})
+
+	if statements > obscureMaxStatements {
+		g.report(ObscureTest, t.fn, t, "%s has %d statements, which makes its intent hard to follow", name, statements)
+	}
+}
+
+// checkSensitiveEquality reports comparisons of String(), Error() and
+// fmt.Sprint output, and of serialized or timestamped string literals.
+func (g *goFile) checkSensitiveEquality(t *goTest) {
+	ast.Inspect(t.fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.CallExpr:
+			sel, ok := x.Fun.(*ast.SelectorExpr)
+			if !ok || !testifyEquality[strings.TrimSuffix(sel.Sel.Name, "f")] {
+				return true
+			}
+
+			if _, _, ok := g.assertion(x, t); !ok {
+				return true
+			}
+
+			for _, arg := range x.Args {
+				if what := g.sensitiveOperand(arg); what != "" {
+					g.report(SensitiveEquality, x, t, "%s compares %s", t.fn.Name.Name, what)
+					break
+				}
+			}
+		case *ast.IfStmt:
+			cond, ok := x.Cond.(*ast.BinaryExpr)
+			if !ok || (cond.Op != token.EQL && cond.Op != token.NEQ) || !g.containsAssertion(x.Body, t) {
+				return true
+			}
+
+			for _, operand := range []ast.Expr{cond.X, cond.Y} {
+				if what := g.sensitiveOperand(operand); what != "" {
+					g.report(SensitiveEquality, x, t, "%s compares %s", t.fn.Name.Name, what)
+					break
+				}
+			}
+		}
+
+		return true
+	})
+}
+
+// sensitiveOperand describes why an operand makes an equality check fragile, or returns "".
+func (g *goFile) sensitiveOperand(expr ast.Expr) string {
+	if s, ok := goast.StringLit(expr); ok {
+		return sensitiveLiteral(s)
+	}
+
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+
+	if name, ok := g.PkgCall(call, "fmt", "Sprint", "Sprintf", "Sprintln"); ok {
+		return "formatted output (fmt." + name + ")"
+	}
+
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok && len(call.Args) == 0 {
+		switch sel.Sel.Name {
+		case "String":
+			return "the String() form of a value (" + g.Text(call) + ")"
+		case "Error":
+			return "an error message (" + g.Text(call) + ") instead of the error value"
+		}
+	}
+
+	return ""
+}
+
+// goFixture is shared setup: TestMain assigning package variables or a
+// testify suite's Setup method assigning receiver fields.
+type goFixture struct {
+	fn      *ast.FuncDecl
+	recv    string
+	recvTyp string
+	values  map[string]bool
+}
+
+// checkGeneralFixture reports fixtures of which most tests use less than half.
+//
+//nolint:gocognit // Collects fixtures and their usage for two fixture styles
+func (g *goFile) checkGeneralFixture(tests []*goTest) {
+	pkgVars := make(map[string]bool)
+
+	for _, decl := range g.AST.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.VAR {
+			for _, spec := range gen.Specs {
+				for _, id := range spec.(*ast.ValueSpec).Names {
+					pkgVars[id.Name] = true
+				}
+			}
+		}
+	}
+
+	var fixtures []goFixture
+
+	for _, decl := range g.AST.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		fixture := goFixture{fn: fn, values: make(map[string]bool)}
+
+		switch {
+		case fn.Recv == nil && fn.Name.Name == "TestMain":
+		case fn.Recv != nil && len(fn.Recv.List) == 1 && len(fn.Recv.List[0].Names) == 1 &&
+			(fn.Name.Name == "SetupTest" || fn.Name.Name == "SetupSuite"):
+			fixture.recv = fn.Recv.List[0].Names[0].Name
+			fixture.recvTyp = goast.ReceiverType(fn.Recv.List[0].Type)
+		default:
+			continue
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok {
+				return true
+			}
+
+			for _, lhs := range assign.Lhs {
+				switch x := lhs.(type) {
+				case *ast.Ident:
+					if fixture.recv == "" && pkgVars[x.Name] {
+						fixture.values[x.Name] = true
+					}
+				case *ast.SelectorExpr:
+					if id, ok := x.X.(*ast.Ident); ok && fixture.recv != "" && id.Name == fixture.recv {
+						fixture.values[x.Sel.Name] = true
+					}
+				}
+			}
+
+			return true
+		})
+
+		if len(fixture.values) >= generalFixtureMinValues {
+			fixtures = append(fixtures, fixture)
+		}
+	}
+
+	for _, fixture := range fixtures {
+		consumers, partial := 0, 0
+
+		for _, t := range tests {
+			if t.recvTyp != fixture.recvTyp {
+				continue
+			}
+
+			consumers++
+
+			if used := fixtureUsage(t, fixture); used*2 < len(fixture.values) {
+				partial++
+			}
+		}
+
+		if consumers > 0 && partial*2 >= consumers {
+			g.report(GeneralFixture, fixture.fn, nil, "%s prepares %d shared values but %d of %d tests use fewer than half of them",
+				fixture.fn.Name.Name, len(fixture.values), partial, consumers)
+		}
+	}
+}
+
+// fixtureUsage counts the fixture values a test references.
+func fixtureUsage(t *goTest, fixture goFixture) int {
+	used := make(map[string]bool)
+
+	ast.Inspect(t.fn.Body, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.Ident:
+			if fixture.recv == "" && fixture.values[x.Name] {
+				used[x.Name] = true
+			}
+		case *ast.SelectorExpr:
+			if id, ok := x.X.(*ast.Ident); ok && fixture.recv != "" && id.Name == t.recv && fixture.values[x.Sel.Name] {
+				used[x.Sel.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	return len(used)
+}
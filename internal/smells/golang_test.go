@@ -0,0 +1,409 @@
+package smells
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+const goTestHeader = `package app
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/app/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var _ = fmt.Sprint
+var _ = rand.Int
+var _ = net.Listen
+var _ = os.Open
+var _ sync.WaitGroup
+var _ = time.Now
+var _ = store.Open
+var _ = assert.Equal
+var _ suite.Suite
+`
+
+// smellsOf returns the smells found in a Go test body appended to goTestHeader.
+func smellsOf(t *testing.T, body string) map[Smell]int {
+	t.Helper()
+
+	findings, err := NewGoDetector().Detect("app/app_test.go", []byte(goTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	counts := make(map[Smell]int)
+	for _, f := range findings {
+		counts[Smell(f.CheckID)]++
+	}
+
+	return counts
+}
+
+//nolint:gocognit,gocyclo // Table-driven tests can be complex but are still readable
+func TestGoDetector(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   Smell
+		absent bool
+	}{
+		{
+			name: "mystery guest reads untracked file",
+			body: `
+func TestLoadUsers(t *testing.T) {
+	data, err := os.ReadFile("users.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = data
+}`,
+			want: MysteryGuest,
+		},
+		{
+			name: "testdata file is not a mystery guest",
+			body: `
+func TestLoadUsers(t *testing.T) {
+	data, err := os.ReadFile("testdata/users.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = data
+}`,
+			want:   MysteryGuest,
+			absent: true,
+		},
+		{
+			name: "mystery guest reads unset environment variable",
+			body: `
+func TestConfigFromEnv(t *testing.T) {
+	if os.Getenv("DATABASE_URL") == "" {
+		t.Fatal("missing database")
+	}
+}`,
+			want: MysteryGuest,
+		},
+		{
+			name: "environment variable set by test",
+			body: `
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("DATABASE_URL", "sqlite://")
+	if os.Getenv("DATABASE_URL") == "" {
+		t.Fatal("missing database")
+	}
+}`,
+			want:   MysteryGuest,
+			absent: true,
+		},
+		{
+			name: "assertion roulette without messages",
+			body: `
+func TestUserFields(t *testing.T) {
+	u := store.NewUser("alice")
+	assert.Equal(t, "alice", u.Name)
+	assert.True(t, u.Active)
+	assert.NotNil(t, u.Created)
+}`,
+			want: AssertionRoulette,
+		},
+		{
+			name: "assertions with messages",
+			body: `
+func TestUserFields(t *testing.T) {
+	u := store.NewUser("alice")
+	assert.Equal(t, "alice", u.Name, "name")
+	assert.True(t, u.Active, "active")
+	assert.NotNil(t, u.Created, "created")
+}`,
+			want:   AssertionRoulette,
+			absent: true,
+		},
+		{
+			name: "eager test exercises several functions",
+			body: `
+func TestUserLifecycle(t *testing.T) {
+	u := store.Create("alice")
+	assert.NotNil(t, u, "created")
+	assert.NoError(t, store.Update(u), "updated")
+	assert.NoError(t, store.Delete(u), "deleted")
+	assert.Nil(t, store.Find("alice"), "gone")
+}`,
+			want: EagerTest,
+		},
+		{
+			name: "type conversions are not functions under test",
+			body: `
+func TestUserKey(t *testing.T) {
+	type key string
+	u := store.Create("alice")
+	assert.Equal(t, "alice", string(store.Key(u)), "key")
+	assert.Equal(t, 5, int(u.Age), "age")
+	assert.Equal(t, []byte("alice"), []byte(u.Name), "bytes")
+	assert.Equal(t, key("alice"), key(u.Name), "typed")
+	assert.Equal(t, 5.0, float64(len(u.Name)), "length")
+}`,
+			want:   EagerTest,
+			absent: true,
+		},
+		{
+			name: "lazy test calls one function with several inputs",
+			body: `
+func TestParse(t *testing.T) {
+	if store.Parse("a") != 1 {
+		t.Error("a")
+	}
+	if store.Parse("b") != 2 {
+		t.Error("b")
+	}
+	if store.Parse("c") != 3 {
+		t.Error("c")
+	}
+}`,
+			want: LazyTest,
+		},
+		{
+			name: "table-driven test is not lazy",
+			body: `
+func TestParse(t *testing.T) {
+	for _, tt := range []struct{ in string; want int }{{"a", 1}, {"b", 2}, {"c", 3}} {
+		t.Run(tt.in, func(t *testing.T) {
+			if store.Parse(tt.in) != tt.want {
+				t.Error(tt.in)
+			}
+		})
+	}
+}`,
+			want:   LazyTest,
+			absent: true,
+		},
+		{
+			name: "conditional logic with if/else",
+			body: `
+func TestDiscount(t *testing.T) {
+	price := store.Price("vip")
+	if price > 100 {
+		assert.Equal(t, 90, store.Discount(price), "vip discount")
+	} else {
+		assert.Equal(t, price, store.Discount(price), "no discount")
+	}
+}`,
+			want: ConditionalLogic,
+		},
+		{
+			name: "idiomatic got/want guard is not conditional logic",
+			body: `
+func TestDiscount(t *testing.T) {
+	if got := store.Discount(200); got != 180 {
+		t.Errorf("Discount() = %d, want 180", got)
+	}
+}`,
+			want:   ConditionalLogic,
+			absent: true,
+		},
+		{
+			name: "obscure test name",
+			body: `
+func TestFoo(t *testing.T) {
+	if store.Discount(200) != 180 {
+		t.Error("discount")
+	}
+}`,
+			want: ObscureTest,
+		},
+		{
+			name: "sensitive equality on error text",
+			body: `
+func TestOpenMissing(t *testing.T) {
+	_, err := store.Open("missing")
+	assert.Equal(t, "store: not found", err.Error(), "error")
+}`,
+			want: SensitiveEquality,
+		},
+		{
+			name: "resource optimism with fixed port",
+			body: `
+func TestServe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+}`,
+			want: ResourceOptimism,
+		},
+		{
+			name: "ephemeral port is not resource optimism",
+			body: `
+func TestServe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+}`,
+			want:   ResourceOptimism,
+			absent: true,
+		},
+		{
+			name: "flakiness from sleep",
+			body: `
+func TestCacheExpiry(t *testing.T) {
+	c := store.NewCache(time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	if c.Len() != 0 {
+		t.Error("not expired")
+	}
+}`,
+			want: Flakiness,
+		},
+		{
+			name: "goroutine waited on is not flaky",
+			body: `
+func TestConcurrentWrites(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		store.Write("x")
+	}()
+	wg.Wait()
+}`,
+			want:   Flakiness,
+			absent: true,
+		},
+		{
+			name: "general fixture in suite setup",
+			body: `
+type OrderSuite struct {
+	suite.Suite
+	db    *store.DB
+	user  *store.User
+	cart  *store.Cart
+	clock *store.Clock
+}
+
+func (s *OrderSuite) SetupTest() {
+	s.db = store.Open("mem")
+	s.user = store.Create("alice")
+	s.cart = store.NewCart(s.user)
+	s.clock = store.NewClock()
+}
+
+func (s *OrderSuite) TestUserName() {
+	s.Equal("alice", s.user.Name, "name")
+}
+
+func (s *OrderSuite) TestClockStarts() {
+	s.NotNil(s.clock, "clock")
+}`,
+			want: GeneralFixture,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := smellsOf(t, tt.body)
+
+			if tt.absent && got[tt.want] > 0 {
+				t.Errorf("expected no %s, got %v", tt.want, got)
+			}
+
+			if !tt.absent && got[tt.want] == 0 {
+				t.Errorf("expected %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsTestSupport(t *testing.T) {
+	tests := map[string]bool{
+		"github.com/stretchr/testify/assert":   true,
+		"github.com/golang/mock/gomock":        true,
+		"github.com/onsi/ginkgo/v2":            true,
+		"github.com/google/go-cmp/cmp":         true,
+		"example.com/app/internal/testutil":    true,
+		"example.com/app/integration-test":     true,
+		"example.com/contest/scores":           false,
+		"example.com/app/latest":               false,
+		"example.com/attestation/verify":       false,
+		"github.com/chambridge/ship-shape/cmd": false,
+	}
+
+	for importPath, want := range tests {
+		if got := isTestSupport(importPath); got != want {
+			t.Errorf("isTestSupport(%q) = %v, want %v", importPath, got, want)
+		}
+	}
+}
+
+func TestGoDetectorFindingDetails(t *testing.T) {
+	body := `
+func TestLoadUsers(t *testing.T) {
+	data, _ := os.ReadFile("users.json")
+	_ = data
+}`
+
+	findings, err := NewGoDetector().Detect("app/app_test.go", []byte(goTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(findings), findings)
+	}
+
+	f := findings[0]
+	if f.CheckID != string(MysteryGuest) {
+		t.Errorf("CheckID = %q, want %q", f.CheckID, MysteryGuest)
+	}
+
+	if f.Test != "app/app_test.go::TestLoadUsers" {
+		t.Errorf("Test = %q", f.Test)
+	}
+
+	if f.Location.File != "app/app_test.go" || f.Location.StartLine != 28 {
+		t.Errorf("Location = %+v, want app/app_test.go line 28", f.Location)
+	}
+}
+
+func TestGoDetectorParseError(t *testing.T) {
+	if _, err := NewGoDetector().Detect("broken_test.go", []byte("package app\nfunc {")); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestGoDetectorIgnoresHelpers(t *testing.T) {
+	body := `
+func helper(t *testing.T) {
+	time.Sleep(time.Second)
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(m.Run())
+}`
+
+	findings, err := NewGoDetector().Detect("app/app_test.go", []byte(goTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	for _, f := range findings {
+		if f.Severity != "" || f.Type != types.FindingType("") {
+			t.Errorf("detector should leave severity and type to the engine: %+v", f)
+		}
+	}
+
+	if len(findings) != 0 {
+		t.Errorf("expected no findings outside tests, got %+v", findings)
+	}
+}
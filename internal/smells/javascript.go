@@ -0,0 +1,894 @@
+package smells
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/flakiness"
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// jsGiantSnapshotLines is the size from which an inline snapshot counts as giant.
+const jsGiantSnapshotLines = 25
+
+// jsWholeRender are identifiers that denote an entire rendered tree.
+var jsWholeRender = map[string]bool{
+	"container": true, "asFragment": true, "baseElement": true, "toJSON": true, "render": true,
+	"mount": true, "shallow": true, "html": true, "wrapper": true, "tree": true,
+}
+
+// jsAsyncQueries are testing-library helpers that return promises.
+var jsAsyncQueries = map[string]bool{"waitFor": true, "waitForElementToBeRemoved": true}
+
+// jsNetworkMocks are identifiers and modules whose presence means network calls are intercepted.
+var jsNetworkMocks = []string{"msw", "nock", "fetchMock", "fetch-mock", "jest-fetch-mock", "setupServer", "mockFetch"}
+
+// JavaScriptDetector detects test smells in the Jest, Vitest and Mocha
+// tests found by the inventory parser. expect() takes no failure message,
+// so assertion roulette is not reported.
+type JavaScriptDetector struct {
+	parser *inventory.JavaScriptParser
+	flaky  *flakiness.JavaScriptDetector
+}
+
+// NewJavaScriptDetector creates a JavaScript/TypeScript smell detector.
+func NewJavaScriptDetector() *JavaScriptDetector {
+	return &JavaScriptDetector{parser: inventory.NewJavaScriptParser(), flaky: flakiness.NewJavaScriptDetector()}
+}
+
+// jsTest is a test call with its callback body tokens[bodyStart:bodyEnd].
+type jsTest struct {
+	id        string
+	name      string
+	call      int
+	argsClose int
+	bodyStart int
+	bodyEnd   int
+	callback  bool
+	each      bool
+	scope     int
+}
+
+// jsScope is a describe block (or the file) with its beforeEach hooks.
+type jsScope struct {
+	parent int
+	hooks  []jsRange
+}
+
+// jsRange is a token range with the name of the hook or call that opened it.
+type jsRange struct {
+	name       string
+	start, end int
+}
+
+// jsFile holds the state of the analysis of one JavaScript/TypeScript file.
+type jsFile struct {
+	path     string
+	tokens   []lexer.Token
+	imports  map[string]bool
+	tests    []*jsTest
+	scopes   []jsScope
+	findings []types.Finding
+}
+
+// Detect parses a test file and runs every smell check on its suites and tests.
+func (d *JavaScriptDetector) Detect(relPath string, src []byte) ([]types.Finding, error) {
+	inv, err := d.parser.Parse(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	flaky, err := flakinessFindings(d.flaky, relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &jsFile{
+		path:    relPath,
+		tokens:  lexer.Tokenize(src, lexer.JavaScript),
+		imports: make(map[string]bool),
+		scopes:  []jsScope{{parent: -1}},
+	}
+
+	f.parseImports()
+	f.parseHooks(inv.Hooks, 0, len(f.tokens), 0)
+	f.parseTests(inv.Tests, 0, len(f.tokens), nil, 0)
+
+	for _, t := range f.tests {
+		if !t.callback {
+			continue
+		}
+
+		f.checkEmpty(t)
+		f.checkMysteryGuest(t)
+		f.checkConditionalLogic(t)
+		f.checkMissingAwait(t)
+		f.checkSnapshotOnly(t)
+		f.checkAssertions(t)
+		f.checkObscure(t)
+		f.checkSensitiveEquality(t)
+		f.checkResourceOptimism(t)
+	}
+
+	f.checkGeneralFixture()
+
+	f.findings = append(f.findings, flaky...)
+	SortFindings(f.findings)
+
+	return f.findings, nil
+}
+
+func (f *jsFile) precededByDot(i int) bool {
+	return i > 0 && (f.tokens[i-1].Is(lexer.Punct, ".") || f.tokens[i-1].Is(lexer.Punct, "?."))
+}
+
+func (f *jsFile) declared(i int) bool {
+	if i == 0 || f.tokens[i-1].Kind != lexer.Ident {
+		return false
+	}
+
+	switch f.tokens[i-1].Text {
+	case "function", "const", "let", "var", "class":
+		return true
+	}
+
+	return false
+}
+
+func (f *jsFile) calledAt(i int) bool {
+	return i+1 < len(f.tokens) && f.tokens[i+1].Is(lexer.Punct, "(") && !f.precededByDot(i) && !f.declared(i)
+}
+
+func (f *jsFile) report(smell Smell, start, end int, t *jsTest, format string, args ...any) {
+	test := ""
+	if t != nil {
+		test = t.id
+	}
+
+	f.findings = append(f.findings, newFinding(smell, f.path, f.tokens[start].Line, f.tokens[end].Line, test,
+		fmt.Sprintf(format, args...)))
+}
+
+// reportTest reports a smell spanning a whole test call.
+func (f *jsFile) reportTest(smell Smell, t *jsTest, format string, args ...any) {
+	f.report(smell, t.call, t.argsClose, t, format, args...)
+}
+
+// parseImports records names imported from relative modules (the code under test).
+func (f *jsFile) parseImports() {
+	toks := f.tokens
+
+	for i := 0; i < len(toks); i++ {
+		var names []string
+
+		switch {
+		case toks[i].Is(lexer.Ident, "import") && !f.precededByDot(i):
+			j := i + 1
+			for j < len(toks) && !toks[j].Is(lexer.Ident, "from") && toks[j].Kind != lexer.String {
+				if toks[j].Kind == lexer.Ident && !toks[j].Is(lexer.Ident, "as") && !toks[j].Is(lexer.Ident, "type") &&
+					(j+1 >= len(toks) || !toks[j+1].Is(lexer.Ident, "as")) {
+					names = append(names, toks[j].Text)
+				}
+
+				j++
+			}
+
+			if j < len(toks) && toks[j].Is(lexer.Ident, "from") {
+				j++
+			}
+
+			if j < len(toks) && toks[j].Kind == lexer.String && jsLocalModule(lexer.Unquote(toks[j].Text)) {
+				for _, name := range names {
+					f.imports[name] = true
+				}
+			}
+
+			i = j
+		case toks[i].Is(lexer.Ident, "require") && i+3 < len(toks) && toks[i+1].Is(lexer.Punct, "(") &&
+			toks[i+2].Kind == lexer.String && jsLocalModule(lexer.Unquote(toks[i+2].Text)):
+			// const { a, b } = require('./x') or const x = require('./x')
+			j := i - 1
+			if j >= 0 && toks[j].Is(lexer.Punct, "=") {
+				j--
+			}
+
+			if j >= 0 && toks[j].Is(lexer.Punct, "}") {
+				for k := j - 1; k >= 0 && !toks[k].Is(lexer.Punct, "{"); k-- {
+					if toks[k].Kind == lexer.Ident && !(k+1 < j && toks[k+1].Is(lexer.Punct, ":")) {
+						f.imports[toks[k].Text] = true
+					}
+				}
+			} else if j >= 0 && toks[j].Kind == lexer.Ident {
+				f.imports[toks[j].Text] = true
+			}
+		}
+	}
+}
+
+func jsLocalModule(module string) bool {
+	return strings.HasPrefix(module, ".") || strings.HasPrefix(module, "@/") || strings.HasPrefix(module, "~/")
+}
+
+// parseHooks locates the before hooks of the inventory in tokens[start:end]
+// and adds their bodies to a scope.
+func (f *jsFile) parseHooks(hooks []types.TestHook, start, end, scope int) {
+	for _, hook := range hooks {
+		if hook.Kind != types.HookBeforeEach && hook.Kind != types.HookBeforeAll {
+			continue
+		}
+
+		for i := start; i < end; i++ {
+			if f.tokens[i].Line == hook.Line && f.tokens[i].Is(lexer.Ident, hook.Name) && f.calledAt(i) {
+				closing := lexer.Match(f.tokens, i+1)
+				if bodyStart, bodyEnd, ok := f.callback(i+2, closing); ok {
+					f.scopes[scope].hooks = append(f.scopes[scope].hooks, jsRange{name: hook.Name, start: bodyStart, end: bodyEnd})
+				}
+
+				break
+			}
+		}
+	}
+}
+
+// parseTests locates the suites and tests of the inventory in
+// tokens[start:end], reporting focused and skipped ones.
+func (f *jsFile) parseTests(cases []types.TestCase, start, end int, parents []string, scope int) {
+	for i := range cases {
+		tc := &cases[i]
+
+		call, argsOpen, argsClose, ok := f.locate(tc.Line, start, end)
+		if !ok {
+			continue
+		}
+
+		start = argsClose + 1
+		path := append(append([]string(nil), parents...), tc.Name)
+
+		kind := "test"
+		if tc.Kind == types.TestKindSuite {
+			kind = "suite"
+		}
+
+		if tc.Focused {
+			f.report(FocusedTest, call, argsClose, nil, "%s %q is focused with .only, so the rest of the file does not run",
+				kind, strings.Join(path, " > "))
+		}
+
+		if tc.Skipped {
+			f.report(SkippedTest, call, argsClose, nil, "%s %q is skipped", kind, strings.Join(path, " > "))
+		}
+
+		switch {
+		case tc.Kind == types.TestKindSuite:
+			f.scopes = append(f.scopes, jsScope{parent: scope})
+			f.parseHooks(tc.Hooks, argsOpen+1, argsClose, len(f.scopes)-1)
+			f.parseTests(tc.Children, argsOpen+1, argsClose, path, len(f.scopes)-1)
+		case !tc.Todo:
+			t := &jsTest{
+				id:        tc.ID,
+				name:      tc.Name,
+				call:      call,
+				argsClose: argsClose,
+				each:      tc.Kind == types.TestKindParameterized,
+				scope:     scope,
+			}
+			t.bodyStart, t.bodyEnd, t.callback = f.callback(argsOpen+1, argsClose)
+			f.tests = append(f.tests, t)
+		}
+	}
+}
+
+// locate finds the first test or suite call in tokens[start:end] on a
+// line, returning its position and the parentheses of its arguments.
+func (f *jsFile) locate(line, start, end int) (int, int, int, bool) {
+	for i := start; i < end && f.tokens[i].Line <= line; i++ {
+		if f.tokens[i].Line != line || f.tokens[i].Kind != lexer.Ident || f.precededByDot(i) || f.declared(i) {
+			continue
+		}
+
+		if argsOpen, argsClose, ok := inventory.JSCallArgs(f.tokens, i); ok && argsClose < end {
+			return i, argsOpen, argsClose, true
+		}
+	}
+
+	return 0, 0, 0, false
+}
+
+// callback locates the body of the last function argument in tokens[start:end],
+// such as it('name', fn) or it('name', fn, timeout). Expression-bodied
+// arrows yield the expression as body.
+func (f *jsFile) callback(start, end int) (int, int, bool) {
+	var args []jsRange
+
+	argStart := start
+
+	for k := start; k < end; k++ {
+		switch tok := f.tokens[k]; {
+		case tok.Kind == lexer.Punct && strings.Contains("([{", tok.Text):
+			k = lexer.Match(f.tokens, k)
+		case tok.Is(lexer.Punct, ","):
+			args = append(args, jsRange{start: argStart, end: k})
+			argStart = k + 1
+		}
+	}
+
+	args = append(args, jsRange{start: argStart, end: end})
+
+	for i := len(args) - 1; i >= 0; i-- {
+		if bodyStart, bodyEnd, ok := f.function(args[i].start, args[i].end); ok {
+			return bodyStart, bodyEnd, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// function returns the body of the function expression in tokens[start:end].
+func (f *jsFile) function(start, end int) (int, int, bool) {
+	for k := start; k < end; k++ {
+		tok := f.tokens[k]
+
+		switch {
+		case tok.Is(lexer.Punct, "=>"):
+			if k+1 < end && f.tokens[k+1].Is(lexer.Punct, "{") {
+				return k + 2, lexer.Match(f.tokens, k+1), true
+			}
+
+			return k + 1, end, true
+		case tok.Is(lexer.Ident, "function"):
+			for m := k + 1; m < end; m++ {
+				if f.tokens[m].Is(lexer.Punct, "(") {
+					m = lexer.Match(f.tokens, m)
+					if m+1 < end && f.tokens[m+1].Is(lexer.Punct, "{") {
+						return m + 2, lexer.Match(f.tokens, m+1), true
+					}
+
+					return 0, 0, false
+				}
+			}
+		case tok.Kind == lexer.Punct && strings.Contains("[{", tok.Text):
+			return 0, 0, false
+		case tok.Is(lexer.Punct, "("):
+			// Skip the parameter list of an arrow function
+			k = lexer.Match(f.tokens, k)
+		}
+	}
+
+	return 0, 0, false
+}
+
+// hasIdentIn reports whether any identifier in tokens[start:end] is one of names.
+func (f *jsFile) hasIdentIn(start, end int, names ...string) bool {
+	for k := start; k < end; k++ {
+		if f.tokens[k].Kind != lexer.Ident {
+			continue
+		}
+
+		for _, name := range names {
+			if f.tokens[k].Text == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// fileMentions reports whether the file references any of the identifiers or module names.
+func (f *jsFile) fileMentions(names ...string) bool {
+	for _, tok := range f.tokens {
+		text := tok.Text
+		if tok.Kind == lexer.String {
+			text = lexer.Unquote(text)
+		} else if tok.Kind != lexer.Ident {
+			continue
+		}
+
+		for _, name := range names {
+			if text == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// expectCalls returns the positions of expect(...) calls in tokens[start:end].
+func (f *jsFile) expectCalls(start, end int) []int {
+	var calls []int
+
+	for k := start; k < end; k++ {
+		if f.tokens[k].Is(lexer.Ident, "expect") && f.calledAt(k) {
+			calls = append(calls, k)
+		}
+	}
+
+	return calls
+}
+
+// matcher returns the matcher of the expect chain at k and the position of
+// its argument list, skipping modifiers such as .not and .resolves.
+func (f *jsFile) matcher(k int) (string, int) {
+	j := lexer.Match(f.tokens, k+1) + 1
+
+	for j+1 < len(f.tokens) && f.tokens[j].Is(lexer.Punct, ".") && f.tokens[j+1].Kind == lexer.Ident {
+		if j+2 < len(f.tokens) && f.tokens[j+2].Is(lexer.Punct, "(") {
+			return f.tokens[j+1].Text, j + 2
+		}
+
+		j += 2
+	}
+
+	return "", -1
+}
+
+// checkEmpty reports tests whose callback has no statements.
+func (f *jsFile) checkEmpty(t *jsTest) {
+	if t.bodyStart >= t.bodyEnd {
+		f.reportTest(EmptyTest, t, "%q has an empty body and always passes", t.name)
+	}
+}
+
+// checkMysteryGuest reports real network calls without mocks, reads of
+// untracked files and unset environment variables.
+//
+//nolint:gocognit // One case per kind of external dependency
+func (f *jsFile) checkMysteryGuest(t *jsTest) {
+	for k := t.bodyStart; k < t.bodyEnd; k++ {
+		tok := f.tokens[k]
+		if tok.Kind != lexer.Ident || f.precededByDot(k) {
+			continue
+		}
+
+		name, next := lexer.DottedName(f.tokens, k)
+
+		switch {
+		case (name == "fetch" || strings.HasPrefix(name, "axios")) && next < len(f.tokens) && f.tokens[next].Is(lexer.Punct, "("):
+			client, _, _ := strings.Cut(name, ".")
+			if !f.mocksNetwork(client) {
+				f.report(MysteryGuest, k, lexer.Match(f.tokens, next), t, "%q calls real %s without a mock", t.name, name)
+			}
+		case (name == "fs.readFileSync" || name == "fs.readFile" || name == "fs.promises.readFile" || name == "readFileSync") &&
+			next+1 < len(f.tokens) && f.tokens[next].Is(lexer.Punct, "("):
+			if p, ok := literalString(f.tokens[next+1 : next+2]); ok && !jsFixturePath(p) {
+				f.report(MysteryGuest, k, lexer.Match(f.tokens, next), t, "%q reads %q, which the test does not create", t.name, p)
+			}
+		case strings.HasPrefix(name, "process.env.") && !(next < len(f.tokens) && f.tokens[next].Is(lexer.Punct, "=")):
+			variable := strings.TrimPrefix(name, "process.env.")
+			if !f.assignsEnv(variable) {
+				f.report(MysteryGuest, k, next-1, t, "%q depends on environment variable %s without setting it", t.name, variable)
+			}
+		}
+
+		if next > k+1 {
+			k = next - 1
+		}
+	}
+}
+
+// mocksNetwork reports whether the file intercepts calls of the given client.
+func (f *jsFile) mocksNetwork(client string) bool {
+	if f.fileMentions(jsNetworkMocks...) {
+		return true
+	}
+
+	toks := f.tokens
+	for k := 0; k+4 < len(toks); k++ {
+		if !toks[k].Is(lexer.Ident, "mock") && !toks[k].Is(lexer.Ident, "spyOn") && !toks[k].Is(lexer.Ident, "stub") &&
+			!toks[k].Is(lexer.Ident, "stubGlobal") {
+			continue
+		}
+
+		for m := k + 2; m < len(toks) && m < k+6; m++ {
+			if toks[m].Kind == lexer.String && lexer.Unquote(toks[m].Text) == client || toks[m].Is(lexer.Ident, client) {
+				return true
+			}
+		}
+	}
+
+	// global.fetch = jest.fn()
+	for k := 0; k+3 < len(toks); k++ {
+		if toks[k].Is(lexer.Ident, client) && f.precededByDot(k) && toks[k+1].Is(lexer.Punct, "=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (f *jsFile) assignsEnv(variable string) bool {
+	toks := f.tokens
+	for k := 0; k+5 < len(toks); k++ {
+		if lexer.Join(toks[k:k+4], " ") == "process . env ." && toks[k+4].Is(lexer.Ident, variable) && toks[k+5].Is(lexer.Punct, "=") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsFixturePath(p string) bool {
+	clean := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+
+	return pyFixturePath(clean) || strings.Contains("/"+clean+"/", "/__fixtures__/")
+}
+
+// checkConditionalLogic reports expect() calls inside if/switch blocks,
+// loops and forEach/map callbacks.
+//
+//nolint:gocognit // Locates the extent of each control structure
+func (f *jsFile) checkConditionalLogic(t *jsTest) {
+	for k := t.bodyStart; k < t.bodyEnd; k++ {
+		tok := f.tokens[k]
+		if tok.Kind != lexer.Ident {
+			continue
+		}
+
+		var end int
+
+		switch {
+		case (tok.Text == "if" || tok.Text == "for" || tok.Text == "while" || tok.Text == "switch") &&
+			k+1 < t.bodyEnd && f.tokens[k+1].Is(lexer.Punct, "("):
+			end = f.statementEnd(lexer.Match(f.tokens, k+1)+1, t.bodyEnd)
+
+			if tok.Text == "if" {
+				for end+1 < t.bodyEnd && f.tokens[end+1].Is(lexer.Ident, "else") {
+					end = f.statementEnd(end+2, t.bodyEnd)
+				}
+			}
+		case (tok.Text == "forEach" || tok.Text == "map") && f.precededByDot(k) && k+1 < t.bodyEnd &&
+			f.tokens[k+1].Is(lexer.Punct, "("):
+			end = lexer.Match(f.tokens, k+1)
+		default:
+			continue
+		}
+
+		if len(f.expectCalls(k, end)) > 0 {
+			kind := "a " + tok.Text + " block"
+			if tok.Text == "forEach" || tok.Text == "map" || tok.Text == "for" || tok.Text == "while" {
+				kind = "a loop (" + tok.Text + ")"
+			}
+
+			f.report(ConditionalLogic, k, end, t, "%q calls expect inside %s; use test.each or separate tests", t.name, kind)
+
+			k = end
+		}
+	}
+}
+
+// statementEnd returns the last token of the statement starting at k: a
+// braced block, or everything up to the next semicolon or line break.
+func (f *jsFile) statementEnd(k, limit int) int {
+	if k >= limit {
+		return limit - 1
+	}
+
+	if f.tokens[k].Is(lexer.Punct, "{") {
+		return lexer.Match(f.tokens, k)
+	}
+
+	if f.tokens[k].Is(lexer.Ident, "if") && k+1 < limit && f.tokens[k+1].Is(lexer.Punct, "(") {
+		end := f.statementEnd(lexer.Match(f.tokens, k+1)+1, limit)
+		for end+1 < limit && f.tokens[end+1].Is(lexer.Ident, "else") {
+			end = f.statementEnd(end+2, limit)
+		}
+
+		return end
+	}
+
+	line := f.tokens[k].Line
+
+	for m := k; m < limit; m++ {
+		tok := f.tokens[m]
+
+		switch {
+		case tok.Is(lexer.Punct, ";"):
+			return m
+		case tok.Kind == lexer.Punct && strings.Contains("([{", tok.Text):
+			m = lexer.Match(f.tokens, m)
+		case m+1 < limit && f.tokens[m+1].Line != line && !f.tokens[m+1].Is(lexer.Punct, "."):
+			return m
+		}
+	}
+
+	return limit - 1
+}
+
+// checkMissingAwait reports asynchronous assertions that are neither awaited nor returned.
+func (f *jsFile) checkMissingAwait(t *jsTest) {
+	for k := t.bodyStart; k < t.bodyEnd; k++ {
+		tok := f.tokens[k]
+		if tok.Kind != lexer.Ident || f.precededByDot(k) || f.declared(k) {
+			continue
+		}
+
+		name, next := lexer.DottedName(f.tokens, k)
+		last := name[strings.LastIndex(name, ".")+1:]
+		what := ""
+
+		switch {
+		case tok.Text == "expect" && f.calledAt(k):
+			chain := lexer.Match(f.tokens, k+1) + 1
+			if chain+1 < len(f.tokens) && f.tokens[chain].Is(lexer.Punct, ".") &&
+				(f.tokens[chain+1].Is(lexer.Ident, "resolves") || f.tokens[chain+1].Is(lexer.Ident, "rejects")) {
+				what = "expect(...)." + f.tokens[chain+1].Text
+			}
+		case next < len(f.tokens) && f.tokens[next].Is(lexer.Punct, "(") &&
+			(jsAsyncQueries[last] || strings.HasPrefix(last, "findBy") || strings.HasPrefix(last, "findAllBy")):
+			what = name
+		}
+
+		if what == "" || f.awaited(k) {
+			continue
+		}
+
+		f.report(MissingAwait, k, f.statementEnd(k, t.bodyEnd), t, "%q does not await %s, so its failure is never reported", t.name, what)
+	}
+}
+
+// awaited reports whether the expression starting at k is awaited, returned
+// or passed on as an argument.
+func (f *jsFile) awaited(k int) bool {
+	if k == 0 {
+		return false
+	}
+
+	prev := f.tokens[k-1]
+
+	return prev.Is(lexer.Ident, "await") || prev.Is(lexer.Ident, "return") || prev.Is(lexer.Punct, "=>") ||
+		prev.Is(lexer.Punct, "(") || prev.Is(lexer.Punct, "[") || prev.Is(lexer.Punct, ",")
+}
+
+// checkSnapshotOnly reports tests whose only assertions are snapshots of a
+// whole rendered tree or giant inline snapshots.
+func (f *jsFile) checkSnapshotOnly(t *jsTest) {
+	expects := f.expectCalls(t.bodyStart, t.bodyEnd)
+	if len(expects) == 0 {
+		return
+	}
+
+	giant := false
+
+	for _, k := range expects {
+		matcher, args := f.matcher(k)
+		if !inventory.JSSnapshotMatchers[matcher] {
+			return
+		}
+
+		if args > 0 && args+1 < len(f.tokens) && f.tokens[args+1].Kind == lexer.String &&
+			strings.Count(f.tokens[args+1].Text, "\n") >= jsGiantSnapshotLines {
+			giant = true
+		}
+
+		if f.hasIdentIn(k+2, lexer.Match(f.tokens, k+1), "container", "asFragment", "baseElement", "toJSON", "html") ||
+			f.snapshotsRender(k) {
+			giant = true
+		}
+	}
+
+	if giant {
+		f.reportTest(SnapshotOnly, t, "%q only asserts a snapshot of the whole rendered output", t.name)
+	}
+}
+
+// snapshotsRender reports whether expect's argument is the result of a render call.
+func (f *jsFile) snapshotsRender(k int) bool {
+	closing := lexer.Match(f.tokens, k+1)
+
+	for m := k + 2; m < closing; m++ {
+		if tok := f.tokens[m]; tok.Kind == lexer.Ident && jsWholeRender[tok.Text] &&
+			m+1 < closing && f.tokens[m+1].Is(lexer.Punct, "(") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAssertions reports eager and lazy tests.
+func (f *jsFile) checkAssertions(t *jsTest) {
+	if t.each {
+		return
+	}
+
+	callees := make(map[string][]string)
+
+	var order []string
+
+	for k := t.bodyStart; k < t.bodyEnd; k++ {
+		tok := f.tokens[k]
+		if tok.Kind != lexer.Ident || !f.imports[tok.Text] || !f.calledAt(k) {
+			continue
+		}
+
+		if _, seen := callees[tok.Text]; !seen {
+			order = append(order, tok.Text)
+		}
+
+		closing := lexer.Match(f.tokens, k+1)
+		callees[tok.Text] = append(callees[tok.Text], lexer.Join(f.tokens[k+2:closing], " "))
+	}
+
+	if expects := len(f.expectCalls(t.bodyStart, t.bodyEnd)); len(order) >= eagerMinCalls && expects >= eagerMinAssertions {
+		f.reportTest(EagerTest, t, "%q exercises %d different functions (%s) with %d assertions",
+			t.name, len(order), strings.Join(order, ", "), expects)
+	}
+
+	for _, callee := range order {
+		inputs := make(map[string]bool)
+		for _, args := range callees[callee] {
+			inputs[args] = true
+		}
+
+		if len(callees[callee]) >= lazyMinCalls && len(inputs) >= lazyMinCalls {
+			f.reportTest(LazyTest, t, "%q checks %d scenarios of %s in sequence; use test.each", t.name, len(callees[callee]), callee)
+			break
+		}
+	}
+}
+
+// checkObscure reports tests with meaningless names or overly long bodies.
+func (f *jsFile) checkObscure(t *jsTest) {
+	subject := strings.ToLower(strings.TrimSpace(t.name))
+	for _, prefix := range []string{"it ", "should ", "test "} {
+		subject = strings.TrimPrefix(subject, prefix)
+	}
+
+	subject = strings.TrimRight(subject, "0123456789 ")
+
+	if obscureNames[subject] || subject == "work" || subject == "test" {
+		f.report(ObscureTest, t.call, t.call, t, "%q does not describe the behavior under test", t.name)
+	}
+
+	if statements := len(f.statements(t.bodyStart, t.bodyEnd)); statements > obscureMaxStatements {
+		f.reportTest(ObscureTest, t, "%q has %d statements, which makes its intent hard to follow", t.name, statements)
+	}
+}
+
+// statements splits tokens[start:end] into top-level statements.
+func (f *jsFile) statements(start, end int) []jsRange {
+	var stmts []jsRange
+
+	for k := start; k < end; {
+		last := f.statementEnd(k, end)
+		stmts = append(stmts, jsRange{start: k, end: last})
+		k = last + 1
+	}
+
+	return stmts
+}
+
+// checkSensitiveEquality reports expectations on JSON.stringify, toString()
+// and String() output, and on serialized or timestamped string literals.
+func (f *jsFile) checkSensitiveEquality(t *jsTest) {
+	for _, k := range f.expectCalls(t.bodyStart, t.bodyEnd) {
+		matcher, args := f.matcher(k)
+		if matcher != "toBe" && matcher != "toEqual" && matcher != "toStrictEqual" {
+			continue
+		}
+
+		closing := lexer.Match(f.tokens, k+1)
+		subject := f.tokens[k+2 : closing]
+		what := ""
+
+		switch {
+		case len(subject) > 2 && lexer.Join(subject[:3], " ") == "JSON . stringify":
+			what = "serialized JSON (JSON.stringify)"
+		case len(subject) > 0 && subject[0].Is(lexer.Ident, "String"):
+			what = "the String() form of a value"
+		case len(subject) > 3 && lexer.Join(subject[len(subject)-4:], " ") == ". toString ( )":
+			what = "the toString() form of a value"
+		}
+
+		if expected, ok := literalString(f.tokens[args+1 : lexer.Match(f.tokens, args)]); ok && what == "" {
+			what = sensitiveLiteral(expected)
+		}
+
+		if what != "" {
+			f.report(SensitiveEquality, k, lexer.Match(f.tokens, args), t, "%q compares %s", t.name, what)
+		}
+	}
+}
+
+// checkResourceOptimism reports servers listening on fixed ports and files
+// written to fixed paths without cleanup.
+func (f *jsFile) checkResourceOptimism(t *jsTest) {
+	for k := t.bodyStart; k+2 < t.bodyEnd; k++ {
+		tok := f.tokens[k]
+		if tok.Kind != lexer.Ident || !f.tokens[k+1].Is(lexer.Punct, "(") {
+			continue
+		}
+
+		arg := f.tokens[k+2]
+
+		switch {
+		case tok.Text == "listen" && f.precededByDot(k) && arg.Kind == lexer.Number && arg.Text != "0":
+			f.report(ResourceOptimism, k, lexer.Match(f.tokens, k+1), t, "%q listens on fixed port %s instead of port 0", t.name, arg.Text)
+		case (tok.Text == "writeFileSync" || tok.Text == "mkdirSync") && arg.Kind == lexer.String:
+			p := lexer.Unquote(arg.Text)
+			if !jsFixturePath(p) && !f.removes(p) {
+				f.report(ResourceOptimism, k, lexer.Match(f.tokens, k+1), t, "%q creates %q without removing it", t.name, p)
+			}
+		}
+	}
+}
+
+// removes reports whether the file deletes the literal path p.
+func (f *jsFile) removes(p string) bool {
+	for k := 0; k+2 < len(f.tokens); k++ {
+		switch f.tokens[k].Text {
+		case "unlinkSync", "rmSync", "rmdirSync", "unlink", "rm":
+			if f.tokens[k+1].Is(lexer.Punct, "(") && f.tokens[k+2].Kind == lexer.String && lexer.Unquote(f.tokens[k+2].Text) == p {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkGeneralFixture reports beforeEach hooks that assign more shared
+// variables than most tests of their scope use.
+//
+//nolint:gocognit // Collects assignments per hook and usage per test
+func (f *jsFile) checkGeneralFixture() {
+	for scope, s := range f.scopes {
+		for _, hook := range s.hooks {
+			values := make(map[string]bool)
+
+			for _, stmt := range f.statements(hook.start, hook.end) {
+				k := stmt.start
+				if f.tokens[k].Kind == lexer.Ident && k+1 <= stmt.end && f.tokens[k+1].Is(lexer.Punct, "=") && !f.declared(k) {
+					values[f.tokens[k].Text] = true
+				}
+			}
+
+			if len(values) < generalFixtureMinValues {
+				continue
+			}
+
+			consumers, partial := 0, 0
+
+			for _, t := range f.tests {
+				if !f.within(t.scope, scope) || !t.callback {
+					continue
+				}
+
+				consumers++
+
+				used := make(map[string]bool)
+
+				for k := t.bodyStart; k < t.bodyEnd; k++ {
+					if f.tokens[k].Kind == lexer.Ident && values[f.tokens[k].Text] && !f.precededByDot(k) {
+						used[f.tokens[k].Text] = true
+					}
+				}
+
+				if len(used)*2 < len(values) {
+					partial++
+				}
+			}
+
+			if consumers > 0 && partial*2 >= consumers {
+				f.report(GeneralFixture, hook.start-1, hook.end, nil,
+					"%s prepares %d shared values but %d of %d tests use fewer than half of them",
+					hook.name, len(values), partial, consumers)
+			}
+		}
+	}
+}
+
+// within reports whether scope is ancestor or equal to the test's scope.
+func (f *jsFile) within(testScope, scope int) bool {
+	for s := testScope; s >= 0; s = f.scopes[s].parent {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,370 @@
+package smells
+
+import (
+	"strings"
+	"testing"
+)
+
+const jsTestHeader = `import { render, screen, waitFor } from '@testing-library/react';
+import fs from 'fs';
+import { cancel, checkout, createCart, parse } from '../src/orders';
+import { createUser } from '../src/users';
+`
+
+// jsSmellsOf returns the smells found in a test file body appended to jsTestHeader.
+func jsSmellsOf(t *testing.T, body string) map[Smell]int {
+	t.Helper()
+
+	findings, err := NewJavaScriptDetector().Detect("src/orders.test.ts", []byte(jsTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	counts := make(map[Smell]int)
+	for _, f := range findings {
+		counts[Smell(f.CheckID)]++
+	}
+
+	return counts
+}
+
+//nolint:gocognit,gocyclo // Table-driven tests can be complex but are still readable
+func TestJavaScriptDetector(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		want   Smell
+		absent bool
+	}{
+		{
+			name: "focused test",
+			body: `
+it.only('parses numbers', () => {
+  expect(parse('1')).toBe(1);
+});
+`,
+			want: FocusedTest,
+		},
+		{
+			name: "focused suite with fdescribe",
+			body: `
+fdescribe('orders', () => {
+  it('parses numbers', () => {
+    expect(parse('1')).toBe(1);
+  });
+});
+`,
+			want: FocusedTest,
+		},
+		{
+			name: "skipped test with xit",
+			body: `
+xit('parses numbers', () => {
+  expect(parse('1')).toBe(1);
+});
+`,
+			want: SkippedTest,
+		},
+		{
+			name: "skipped suite",
+			body: `
+describe.skip('orders', () => {
+  test('parses numbers', () => {
+    expect(parse('1')).toBe(1);
+  });
+});
+`,
+			want: SkippedTest,
+		},
+		{
+			name: "empty test body",
+			body: `
+it('cancels an order', () => {});
+`,
+			want: EmptyTest,
+		},
+		{
+			name: "todo test is not empty",
+			body: `
+it.todo('cancels an order');
+`,
+			want:   EmptyTest,
+			absent: true,
+		},
+		{
+			name: "setTimeout-based wait",
+			body: `
+it('expires the cart', async () => {
+  const cart = createCart();
+  await new Promise((resolve) => setTimeout(resolve, 500));
+  expect(cart.expired).toBe(true);
+});
+`,
+			want: Flakiness,
+		},
+		{
+			name: "timers with fake timers are deterministic",
+			body: `
+jest.useFakeTimers();
+
+it('expires the cart', () => {
+  const cart = createCart();
+  setTimeout(() => cart.expire(), 500);
+  jest.advanceTimersByTime(500);
+  expect(cart.expired).toBe(true);
+});
+`,
+			want:   Flakiness,
+			absent: true,
+		},
+		{
+			name: "real fetch without mock",
+			body: `
+it('loads the status page', async () => {
+  const res = await fetch('https://status.example.com/api');
+  expect(res.ok).toBe(true);
+});
+`,
+			want: MysteryGuest,
+		},
+		{
+			name: "mocked fetch is not a mystery guest",
+			body: `
+global.fetch = jest.fn(() => Promise.resolve({ ok: true }));
+
+it('loads the status page', async () => {
+  const res = await fetch('https://status.example.com/api');
+  expect(res.ok).toBe(true);
+});
+`,
+			want:   MysteryGuest,
+			absent: true,
+		},
+		{
+			name: "giant snapshot-only test",
+			body: `
+it('renders the order page', () => {
+  const { container } = render(<OrderPage />);
+  expect(container).toMatchSnapshot();
+});
+`,
+			want: SnapshotOnly,
+		},
+		{
+			name: "focused snapshot alongside assertions",
+			body: `
+it('renders the total', () => {
+  render(<OrderPage />);
+  expect(screen.getByText('Total')).toBeInTheDocument();
+  expect(screen.getByRole('table')).toMatchSnapshot();
+});
+`,
+			want:   SnapshotOnly,
+			absent: true,
+		},
+		{
+			name: "expect inside loop",
+			body: `
+it('parses numbers', () => {
+  ['1', '2'].forEach((text) => {
+    expect(parse(text)).toBe(Number(text));
+  });
+});
+`,
+			want: ConditionalLogic,
+		},
+		{
+			name: "expect inside conditional",
+			body: `
+it('applies discounts', () => {
+  const user = createUser('vip');
+  if (user.vip) {
+    expect(checkout(createCart(user)).discount).toBe(10);
+  }
+});
+`,
+			want: ConditionalLogic,
+		},
+		{
+			name: "missing await on resolves",
+			body: `
+it('checks out', () => {
+  expect(checkout(createCart())).resolves.toEqual({ ok: true });
+});
+`,
+			want: MissingAwait,
+		},
+		{
+			name: "missing await on waitFor",
+			body: `
+it('shows the receipt', () => {
+  render(<OrderPage />);
+  waitFor(() => expect(screen.getByText('Receipt')).toBeVisible());
+});
+`,
+			want: MissingAwait,
+		},
+		{
+			name: "awaited and returned assertions",
+			body: `
+it('checks out', async () => {
+  await expect(checkout(createCart())).resolves.toEqual({ ok: true });
+  await screen.findByText('Receipt');
+  return expect(cancel(createCart())).rejects.toThrow();
+});
+`,
+			want:   MissingAwait,
+			absent: true,
+		},
+		{
+			name: "sensitive equality with JSON.stringify",
+			body: `
+it('serializes the user', () => {
+  expect(JSON.stringify(createUser('alice'))).toBe('{"name":"alice"}');
+});
+`,
+			want: SensitiveEquality,
+		},
+		{
+			name: "lazy test",
+			body: `
+it('parses', () => {
+  expect(parse('1')).toBe(1);
+  expect(parse('2')).toBe(2);
+  expect(parse('3')).toBe(3);
+});
+`,
+			want: LazyTest,
+		},
+		{
+			name: "each table is not lazy",
+			body: `
+it.each([['1', 1], ['2', 2], ['3', 3]])('parses %s', (text, want) => {
+  expect(parse(text)).toBe(want);
+  expect(parse(text + '')).toBe(want);
+  expect(parse(text.trim())).toBe(want);
+});
+`,
+			want:   LazyTest,
+			absent: true,
+		},
+		{
+			name: "obscure test name",
+			body: `
+test('works', () => {
+  expect(parse('1')).toBe(1);
+});
+`,
+			want: ObscureTest,
+		},
+		{
+			name: "fixed port",
+			body: `
+it('starts the server', () => {
+  const server = app.listen(3000);
+  expect(server.listening).toBe(true);
+});
+`,
+			want: ResourceOptimism,
+		},
+		{
+			name: "reads untracked file",
+			body: `
+it('loads orders', () => {
+  const data = fs.readFileSync('/tmp/orders.json', 'utf8');
+  expect(parse(data)).toBeDefined();
+});
+`,
+			want: MysteryGuest,
+		},
+		{
+			name: "general fixture in beforeEach",
+			body: `
+describe('orders', () => {
+  let user, cart, db, clock;
+
+  beforeEach(() => {
+    user = createUser('alice');
+    cart = createCart(user);
+    db = {};
+    clock = {};
+  });
+
+  it('names the user', () => {
+    expect(user.name).toBe('alice');
+  });
+
+  it('sets the clock', () => {
+    expect(clock).toBeDefined();
+  });
+});
+`,
+			want: GeneralFixture,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := jsSmellsOf(t, tt.body)
+
+			if tt.absent && got[tt.want] > 0 {
+				t.Errorf("expected no %s, got %v", tt.want, got)
+			}
+
+			if !tt.absent && got[tt.want] == 0 {
+				t.Errorf("expected %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestJavaScriptDetectorFindingDetails(t *testing.T) {
+	body := `
+describe('cart', () => {
+  it.only('expires', () => {
+    expect(createCart().expired).toBe(false);
+  });
+});
+`
+
+	findings, err := NewJavaScriptDetector().Detect("src/cart.test.js", []byte(jsTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(findings), findings)
+	}
+
+	f := findings[0]
+	if f.CheckID != string(FocusedTest) || f.Location.StartLine != 7 || f.Location.EndLine != 9 {
+		t.Errorf("finding = %+v", f)
+	}
+
+	if !strings.Contains(f.Description, `"cart > expires"`) {
+		t.Errorf("description = %q", f.Description)
+	}
+}
+
+func TestJavaScriptDetectorTestsOnOneLine(t *testing.T) {
+	body := `
+describe('cart', () => { it('is empty', () => {}); it('is new', () => {}); });
+`
+
+	findings, err := NewJavaScriptDetector().Detect("src/cart.test.js", []byte(jsTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	var tests []string
+
+	for _, f := range findings {
+		if f.CheckID == string(EmptyTest) {
+			tests = append(tests, f.Test)
+		}
+	}
+
+	if len(tests) != 2 || tests[0] != "src/cart.test.js::cart::is empty" || tests[1] != "src/cart.test.js::cart::is new" {
+		t.Errorf("empty tests = %q, want both tests of the line", tests)
+	}
+}
@@ -0,0 +1,1014 @@
+package smells
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/flakiness"
+	"github.com/chambridge/ship-shape/internal/inventory"
+	"github.com/chambridge/ship-shape/internal/lexer"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// pyUnittestArity is the number of positional arguments a unittest
+// assertion takes before its optional msg.
+var pyUnittestArity = map[string]int{
+	"assertTrue": 1, "assertFalse": 1, "assertIsNone": 1, "assertIsNotNone": 1,
+	"assertEqual": 2, "assertEquals": 2, "assertNotEqual": 2, "assertIs": 2, "assertIsNot": 2,
+	"assertIn": 2, "assertNotIn": 2, "assertIsInstance": 2, "assertNotIsInstance": 2,
+	"assertGreater": 2, "assertGreaterEqual": 2, "assertLess": 2, "assertLessEqual": 2,
+	"assertCountEqual": 2, "assertListEqual": 2, "assertDictEqual": 2, "assertSetEqual": 2,
+	"assertTupleEqual": 2, "assertSequenceEqual": 2, "assertMultiLineEqual": 2,
+	"assertRegex": 2, "assertNotRegex": 2, "assertAlmostEqual": 2, "assertNotAlmostEqual": 2,
+}
+
+// pyEqualityAsserts are the unittest assertions that compare two values.
+var pyEqualityAsserts = map[string]bool{
+	"assertEqual": true, "assertEquals": true, "assertNotEqual": true, "assertMultiLineEqual": true,
+}
+
+// pySupportModules are standard library and test tooling modules whose
+// functions are never the code under test.
+var pySupportModules = map[string]bool{
+	"abc": true, "argparse": true, "asyncio": true, "base64": true, "collections": true,
+	"contextlib": true, "copy": true, "csv": true, "dataclasses": true, "datetime": true,
+	"decimal": true, "enum": true, "functools": true, "glob": true, "hashlib": true, "http": true,
+	"importlib": true, "inspect": true, "io": true, "itertools": true, "json": true, "logging": true,
+	"math": true, "os": true, "pathlib": true, "pickle": true, "random": true, "re": true,
+	"shutil": true, "signal": true, "socket": true, "sqlite3": true, "string": true,
+	"subprocess": true, "sys": true, "tempfile": true, "textwrap": true, "threading": true,
+	"time": true, "types": true, "typing": true, "urllib": true, "uuid": true, "warnings": true,
+	"pytest": true, "unittest": true, "mock": true, "hypothesis": true, "freezegun": true,
+	"responses": true, "requests_mock": true, "faker": true, "factory": true, "pytest_mock": true,
+}
+
+// pyHTTPClients are the modules whose calls reach the network.
+var pyHTTPClients = map[string]bool{
+	"requests": true, "httpx": true, "urllib.request": true, "aiohttp": true,
+}
+
+// pyDatabaseConnects are the calls that open database connections.
+var pyDatabaseConnects = map[string]bool{
+	"sqlite3.connect": true, "psycopg2.connect": true, "psycopg.connect": true, "pymysql.connect": true,
+	"mysql.connector.connect": true, "create_engine": true, "sqlalchemy.create_engine": true,
+	"pymongo.MongoClient": true, "MongoClient": true, "redis.Redis": true,
+}
+
+// pyServerConstructors are the calls that bind a listening socket to an address tuple.
+var pyServerConstructors = map[string]bool{
+	"HTTPServer": true, "ThreadingHTTPServer": true, "TCPServer": true,
+	"http.server.HTTPServer": true, "socketserver.TCPServer": true,
+}
+
+// PythonDetector detects test smells in the pytest and unittest tests found
+// by the inventory parser.
+type PythonDetector struct {
+	parser *inventory.PythonParser
+	flaky  *flakiness.PythonDetector
+}
+
+// NewPythonDetector creates a Python smell detector.
+func NewPythonDetector() *PythonDetector {
+	return &PythonDetector{parser: inventory.NewPythonParser(), flaky: flakiness.NewPythonDetector()}
+}
+
+// pyStmt is a logical line: the tokens of one statement, with bracketed
+// continuations joined.
+type pyStmt struct {
+	tokens  []lexer.Token
+	indent  int
+	line    int
+	endLine int
+}
+
+// pyTest is a test function or method. Its body is stmts[def+1:end].
+type pyTest struct {
+	id           string
+	name         string
+	class        string // ID of the enclosing test class
+	def          int
+	end          int
+	params       []string
+	parametrized bool
+	unittest     bool
+}
+
+// pyFixture is shared setup: a pytest fixture or a setUp/setup_method.
+type pyFixture struct {
+	name    string
+	class   string // ID of the test class of a setUp method
+	def     int
+	end     int
+	autouse bool
+	setUp   bool
+}
+
+// pyFile holds the state of the analysis of one Python module.
+type pyFile struct {
+	path     string
+	stmts    []pyStmt
+	lines    map[int]int
+	imports  inventory.PythonImports
+	findings []types.Finding
+}
+
+// pyAssertion is an assert statement or a unittest/pytest assertion call.
+type pyAssertion struct {
+	style  string
+	name   string
+	hasMsg bool
+	args   [][]lexer.Token
+	line   int
+}
+
+// Detect parses a Python test module and runs every smell check on its
+// tests and fixtures. conftest.py files are checked for broad fixtures only.
+func (d *PythonDetector) Detect(relPath string, src []byte) ([]types.Finding, error) {
+	inv, err := d.parser.Parse(relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	flaky, err := flakinessFindings(d.flaky, relPath, src)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := lexer.Tokenize(src, lexer.Python)
+	f := &pyFile{
+		path:    relPath,
+		stmts:   pyStatements(tokens),
+		lines:   make(map[int]int),
+		imports: inventory.ParsePythonImports(tokens),
+	}
+
+	for i, stmt := range f.stmts {
+		f.lines[stmt.line] = i
+	}
+
+	tests := f.tests(inv)
+	fixtures := f.fixtures(inv)
+
+	for _, t := range tests {
+		f.checkMysteryGuest(t)
+		f.checkResourceOptimism(t)
+		f.checkAssertions(t)
+		f.checkConditionalLogic(t)
+		f.checkObscure(t)
+		f.checkSensitiveEquality(t)
+	}
+
+	f.checkGeneralFixture(tests, fixtures)
+
+	f.findings = append(f.findings, flaky...)
+	SortFindings(f.findings)
+
+	return f.findings, nil
+}
+
+// pyStatements groups tokens into logical lines.
+func pyStatements(tokens []lexer.Token) []pyStmt {
+	var (
+		stmts   []pyStmt
+		current []lexer.Token
+		depth   int
+	)
+
+	flush := func() {
+		last := current[len(current)-1]
+		stmts = append(stmts, pyStmt{
+			tokens:  current,
+			indent:  current[0].Col,
+			line:    current[0].Line,
+			endLine: last.Line + strings.Count(last.Text, "\n"),
+		})
+		current = nil
+	}
+
+	for i, tok := range tokens {
+		if len(current) > 0 && depth == 0 && tok.Line != tokens[i-1].Line && !tokens[i-1].Is(lexer.Punct, "\\") &&
+			!(tokens[i-1].Kind == lexer.String && tokens[i-1].Line+strings.Count(tokens[i-1].Text, "\n") == tok.Line) {
+			flush()
+		}
+
+		if tok.Kind == lexer.Punct {
+			switch tok.Text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				if depth > 0 {
+					depth--
+				}
+			case "\\":
+				continue
+			}
+		}
+
+		current = append(current, tok)
+	}
+
+	if len(current) > 0 {
+		flush()
+	}
+
+	return stmts
+}
+
+// blockEnd returns the index of the first statement after the block opened at stmts[i].
+func (f *pyFile) blockEnd(i int) int {
+	end := i + 1
+	for end < len(f.stmts) && f.stmts[end].indent > f.stmts[i].indent {
+		end++
+	}
+
+	return end
+}
+
+// tests maps the tests of the inventory onto the statements that define
+// them.
+func (f *pyFile) tests(inv *types.TestFile) []*pyTest {
+	var (
+		tests []*pyTest
+		visit func(tc, class *types.TestCase)
+	)
+
+	visit = func(tc, class *types.TestCase) {
+		if !tc.IsLeaf() {
+			for i := range tc.Children {
+				visit(&tc.Children[i], tc)
+			}
+
+			return
+		}
+
+		def, ok := f.lines[tc.Line]
+		if !ok {
+			return
+		}
+
+		t := &pyTest{
+			id:           tc.ID,
+			name:         tc.Name,
+			def:          def,
+			end:          f.blockEnd(def),
+			params:       tc.Fixtures,
+			parametrized: tc.Kind == types.TestKindParameterized,
+		}
+
+		if class != nil {
+			t.class, t.unittest = class.ID, f.unittestClass(class.Line)
+		}
+
+		tests = append(tests, t)
+	}
+
+	for i := range inv.Tests {
+		visit(&inv.Tests[i], nil)
+	}
+
+	return tests
+}
+
+// unittestClass reports whether the class declared at a line derives from
+// a TestCase.
+func (f *pyFile) unittestClass(line int) bool {
+	i, ok := f.lines[line]
+	if !ok {
+		return false
+	}
+
+	for _, tok := range f.stmts[i].tokens {
+		if tok.Kind == lexer.Ident && strings.HasSuffix(tok.Text, "TestCase") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fixtures finds the shared setup of the module: the setUp and
+// setup_method hooks of the test classes of the inventory, and pytest
+// fixtures.
+func (f *pyFile) fixtures(inv *types.TestFile) []*pyFixture {
+	var fixtures []*pyFixture
+
+	inv.Walk(func(tc *types.TestCase) {
+		for _, hook := range tc.Hooks {
+			if def, ok := f.lines[hook.Line]; ok && (hook.Name == "setUp" || hook.Name == "setup_method") {
+				fixtures = append(fixtures, &pyFixture{name: hook.Name, class: tc.ID, def: def, end: f.blockEnd(def), setUp: true})
+			}
+		}
+	})
+
+	var decorators [][]lexer.Token
+
+	for i, stmt := range f.stmts {
+		toks := stmt.tokens
+		if toks[0].Is(lexer.Punct, "@") {
+			decorators = append(decorators, toks[1:])
+			continue
+		}
+
+		head := toks
+		if len(head) > 1 && head[0].Is(lexer.Ident, "async") {
+			head = head[1:]
+		}
+
+		if head[0].Is(lexer.Ident, "def") && len(head) > 1 {
+			for _, dec := range decorators {
+				if name, next := lexer.DottedName(dec, 0); name == "pytest.fixture" || name == "fixture" {
+					fixtures = append(fixtures, &pyFixture{
+						name:    head[1].Text,
+						def:     i,
+						end:     f.blockEnd(i),
+						autouse: pyAutouse(dec[next:]),
+					})
+				}
+			}
+		}
+
+		decorators = nil
+	}
+
+	return fixtures
+}
+
+// pyAutouse reports whether the arguments of a fixture decorator set
+// autouse=True.
+func pyAutouse(args []lexer.Token) bool {
+	for k := 0; k+2 < len(args); k++ {
+		if args[k].Is(lexer.Ident, "autouse") && args[k+2].Is(lexer.Ident, "True") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// body returns the statements of a test or fixture.
+func (f *pyFile) body(def, end int) []pyStmt {
+	return f.stmts[def+1 : end]
+}
+
+func (f *pyFile) bodyTokens(def, end int) []lexer.Token {
+	var toks []lexer.Token
+	for _, stmt := range f.body(def, end) {
+		toks = append(toks, stmt.tokens...)
+	}
+
+	return toks
+}
+
+func (f *pyFile) report(smell Smell, start, end int, t *pyTest, format string, args ...any) {
+	test := ""
+	if t != nil {
+		test = t.id
+	}
+
+	f.findings = append(f.findings, newFinding(smell, f.path, start, end, test, fmt.Sprintf(format, args...)))
+}
+
+// reportTest reports a smell spanning a whole test.
+func (f *pyFile) reportTest(smell Smell, t *pyTest, format string, args ...any) {
+	f.report(smell, f.stmts[t.def].line, f.stmts[t.end-1].endLine, t, format, args...)
+}
+
+// pyAssertions returns the assertions of a statement.
+func pyAssertions(stmt pyStmt) []pyAssertion {
+	var result []pyAssertion
+
+	toks := stmt.tokens
+	if toks[0].Is(lexer.Ident, "assert") {
+		parts := splitTop(toks[1:], ",")
+		result = append(result, pyAssertion{style: "assert", hasMsg: len(parts) > 1, args: parts[:1], line: stmt.line})
+	}
+
+	for _, call := range lexer.Calls(toks) {
+		switch {
+		case strings.HasPrefix(call.Name, "self.assert"):
+			method := strings.TrimPrefix(call.Name, "self.")
+
+			arity, ok := pyUnittestArity[method]
+			if !ok {
+				// assertRaises, assertWarns and assertLogs describe themselves
+				result = append(result, pyAssertion{style: "unittest", name: method, hasMsg: true, args: call.Args, line: call.Line})
+				continue
+			}
+
+			positional, hasMsg := 0, false
+
+			for _, arg := range call.Args {
+				if len(arg) > 1 && arg[0].Kind == lexer.Ident && arg[1].Is(lexer.Punct, "=") {
+					hasMsg = hasMsg || arg[0].Text == "msg"
+					continue
+				}
+
+				positional++
+			}
+
+			result = append(result, pyAssertion{
+				style:  "unittest",
+				name:   method,
+				hasMsg: hasMsg || positional > arity,
+				args:   call.Args,
+				line:   call.Line,
+			})
+		case call.Name == "self.fail" || call.Name == "pytest.fail":
+			result = append(result, pyAssertion{style: "fail", name: call.Name, hasMsg: len(call.Args) > 0, line: call.Line})
+		case call.Name == "pytest.raises":
+			result = append(result, pyAssertion{style: "raises", name: call.Name, hasMsg: true, line: call.Line})
+		}
+	}
+
+	return result
+}
+
+// verifies reports whether statements make assertions other than explicit failures.
+func verifies(stmts []pyStmt) bool {
+	for _, stmt := range stmts {
+		for _, a := range pyAssertions(stmt) {
+			if a.style != "fail" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// checkMysteryGuest reports reads of files, environment variables, network
+// services and databases that the test does not set up itself.
+//
+//nolint:gocognit,gocyclo // One case per kind of external dependency
+func (f *pyFile) checkMysteryGuest(t *pyTest) {
+	body := f.body(t.def, t.end)
+	written := make(map[string]bool)
+	setenv := make(map[string]bool)
+	envPatched := false
+
+	for _, stmt := range body {
+		toks := stmt.tokens
+
+		for k := range toks {
+			if key, assigned, ok := pyEnvironKey(toks, k); ok && assigned {
+				setenv[key] = true
+			}
+		}
+
+		for _, call := range lexer.Calls(toks) {
+			switch {
+			case strings.HasSuffix(call.Name, "setenv") && len(call.Args) > 0:
+				if key, ok := literalString(call.Args[0]); ok {
+					setenv[key] = true
+				}
+			case strings.HasSuffix(call.Name, "patch.dict") && len(call.Args) > 0 && lexer.HasIdent(call.Args[0], "environ"):
+				envPatched = true
+			case call.Name == "open" && len(call.Args) > 0 && pyWriteMode(call.Args):
+				if p, ok := literalString(call.Args[0]); ok {
+					written[path.Clean(p)] = true
+				}
+			}
+		}
+	}
+
+	for _, stmt := range body {
+		toks := stmt.tokens
+
+		for k := range toks {
+			if key, assigned, ok := pyEnvironKey(toks, k); ok && !assigned && !setenv[key] && !envPatched {
+				f.report(MysteryGuest, stmt.line, stmt.endLine, t, "%s depends on environment variable %s without setting it",
+					t.name, key)
+			}
+		}
+
+		for _, call := range lexer.Calls(toks) {
+			resolved := f.imports.Resolve(call.Name)
+
+			switch {
+			case call.Name == "open" && len(call.Args) > 0 && !pyWriteMode(call.Args):
+				if p, ok := literalString(call.Args[0]); ok && !pyFixturePath(p) && !written[path.Clean(p)] {
+					f.report(MysteryGuest, stmt.line, stmt.endLine, t, "%s reads %q, which the test does not create", t.name, p)
+				}
+			case resolved == "os.getenv" || resolved == "os.environ.get":
+				if len(call.Args) == 0 {
+					continue
+				}
+
+				if key, ok := literalString(call.Args[0]); ok && !setenv[key] && !envPatched {
+					f.report(MysteryGuest, stmt.line, stmt.endLine, t, "%s depends on environment variable %s without setting it",
+						t.name, key)
+				}
+			case pyDatabaseConnects[resolved] || pyDatabaseConnects[call.Name]:
+				if len(call.Args) > 0 {
+					if dsn, ok := literalString(call.Args[0]); ok && strings.Contains(dsn, ":memory:") {
+						continue
+					}
+				}
+
+				f.report(MysteryGuest, stmt.line, stmt.endLine, t, "%s opens a real database connection (%s)", t.name, call.Name)
+			case pyIsHTTPCall(resolved):
+				for _, arg := range call.Args {
+					if url, ok := literalString(arg); ok && isExternalURL(url) {
+						f.report(MysteryGuest, stmt.line, stmt.endLine, t, "%s calls external service %s (%s)", t.name, url, call.Name)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// pyEnvironKey matches os.environ["KEY"] at toks[k] and reports whether it is assigned.
+func pyEnvironKey(toks []lexer.Token, k int) (string, bool, bool) {
+	if k+5 >= len(toks) || lexer.Join(toks[k:k+4], " ") != "os . environ [" || toks[k+4].Kind != lexer.String ||
+		!toks[k+5].Is(lexer.Punct, "]") {
+		return "", false, false
+	}
+
+	assigned := k+6 < len(toks) && toks[k+6].Is(lexer.Punct, "=")
+
+	return lexer.Unquote(toks[k+4].Text), assigned, true
+}
+
+func pyIsHTTPCall(resolved string) bool {
+	for client := range pyHTTPClients {
+		if strings.HasPrefix(resolved, client+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pyWriteMode reports whether an open() call writes to its file.
+func pyWriteMode(args [][]lexer.Token) bool {
+	for i, arg := range args {
+		if len(arg) > 2 && arg[0].Is(lexer.Ident, "mode") && arg[1].Is(lexer.Punct, "=") {
+			arg = arg[2:]
+		} else if i != 1 {
+			continue
+		}
+
+		if mode, ok := literalString(arg); ok {
+			return strings.ContainsAny(mode, "wax+")
+		}
+	}
+
+	return false
+}
+
+func pyFixturePath(p string) bool {
+	clean := path.Clean(strings.ReplaceAll(p, "\\", "/"))
+
+	return underTestdata(clean) || clean == "fixtures" || strings.HasPrefix(clean, "fixtures/") ||
+		strings.Contains(clean, "/fixtures/")
+}
+
+// checkResourceOptimism reports fixed paths and ports, files created without
+// cleanup and files opened without being closed.
+//
+//nolint:gocognit // One case per kind of resource
+func (f *pyFile) checkResourceOptimism(t *pyTest) {
+	body := f.body(t.def, t.end)
+	bodyToks := f.bodyTokens(t.def, t.end)
+	removed := make(map[string]bool)
+
+	for _, stmt := range body {
+		for _, call := range lexer.Calls(stmt.tokens) {
+			switch f.imports.Resolve(call.Name) {
+			case "os.remove", "os.unlink", "os.rmdir", "shutil.rmtree":
+				if len(call.Args) > 0 {
+					if p, ok := literalString(call.Args[0]); ok {
+						removed[path.Clean(p)] = true
+					}
+				}
+			}
+		}
+	}
+
+	for _, stmt := range body {
+		toks := stmt.tokens
+
+		for _, call := range lexer.Calls(toks) {
+			resolved := f.imports.Resolve(call.Name)
+			creates := (call.Name == "open" && pyWriteMode(call.Args)) || resolved == "os.mkdir" || resolved == "os.makedirs"
+
+			if creates && len(call.Args) > 0 {
+				p, ok := literalString(call.Args[0])
+				if !ok || pyFixturePath(p) {
+					continue
+				}
+
+				switch {
+				case strings.HasPrefix(p, "/") || (len(p) > 2 && p[1] == ':'):
+					f.report(ResourceOptimism, stmt.line, stmt.endLine, t, "%s writes to fixed path %q", t.name, p)
+				case !removed[path.Clean(p)]:
+					f.report(ResourceOptimism, stmt.line, stmt.endLine, t, "%s creates %q without removing it", t.name, p)
+				}
+
+				continue
+			}
+
+			if (strings.HasSuffix(call.Name, ".bind") || pyServerConstructors[call.Name]) && len(call.Args) > 0 {
+				if port := pyFixedPort(call.Args[0]); port != "" {
+					f.report(ResourceOptimism, stmt.line, stmt.endLine, t, "%s binds to fixed port %s instead of port 0", t.name, port)
+				}
+			}
+		}
+
+		// name = open(...) outside a with block must be closed explicitly
+		if len(toks) > 3 && toks[0].Kind == lexer.Ident && toks[1].Is(lexer.Punct, "=") && toks[2].Is(lexer.Ident, "open") &&
+			toks[3].Is(lexer.Punct, "(") && !pyCloses(bodyToks, toks[0].Text) {
+			f.report(ResourceOptimism, stmt.line, stmt.endLine, t, "%s opens file %s without closing it", t.name, toks[0].Text)
+		}
+	}
+}
+
+// pyFixedPort returns the port of an address tuple such as ("localhost", 8080)
+// when it is a literal other than 0.
+func pyFixedPort(arg []lexer.Token) string {
+	if len(arg) < 2 || !arg[0].Is(lexer.Punct, "(") {
+		return ""
+	}
+
+	parts := lexer.SplitArgs(arg, 0)
+	if len(parts) != 2 || len(parts[1]) != 1 || parts[1][0].Kind != lexer.Number || parts[1][0].Text == "0" {
+		return ""
+	}
+
+	return parts[1][0].Text
+}
+
+func pyCloses(toks []lexer.Token, name string) bool {
+	for k := 0; k+2 < len(toks); k++ {
+		if toks[k].Is(lexer.Ident, name) && toks[k+1].Is(lexer.Punct, ".") && toks[k+2].Is(lexer.Ident, "close") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAssertions reports assertion roulette, eager tests and lazy tests.
+// pytest rewrites bare assert statements to show the failing expression, so
+// they only count towards assertion roulette inside unittest classes.
+//
+//nolint:gocognit // Shares one pass over statements between three assertion-based smells
+func (f *pyFile) checkAssertions(t *pyTest) {
+	body := f.body(t.def, t.end)
+	assertions, messageless := 0, 0
+
+	for _, stmt := range body {
+		for _, a := range pyAssertions(stmt) {
+			assertions++
+
+			if !a.hasMsg && (a.style == "unittest" || (a.style == "assert" && t.unittest)) {
+				messageless++
+			}
+		}
+	}
+
+	if messageless >= rouletteMinAssertions {
+		f.reportTest(AssertionRoulette, t, "%s has %d assertions without failure messages", t.name, messageless)
+	}
+
+	bodyToks := f.bodyTokens(t.def, t.end)
+	if t.parametrized || lexer.HasIdent(bodyToks, "subTest") {
+		return
+	}
+
+	callees := make(map[string][]lexer.Call)
+
+	var order []string
+
+	for i := t.def + 1; i < t.end; i++ {
+		head := f.stmts[i].tokens[0]
+		if head.Is(lexer.Ident, "for") || head.Is(lexer.Ident, "while") {
+			i = f.blockEnd(i) - 1
+			continue
+		}
+
+		for _, call := range lexer.Calls(f.stmts[i].tokens) {
+			if callee := f.productionCallee(call.Name); callee != "" {
+				if _, seen := callees[callee]; !seen {
+					order = append(order, callee)
+				}
+
+				callees[callee] = append(callees[callee], call)
+			}
+		}
+	}
+
+	if len(order) >= eagerMinCalls && assertions >= eagerMinAssertions {
+		f.reportTest(EagerTest, t, "%s exercises %d different functions (%s) with %d assertions",
+			t.name, len(order), strings.Join(order, ", "), assertions)
+	}
+
+	for _, callee := range order {
+		sites := callees[callee]
+		inputs := make(map[string]bool)
+
+		for _, call := range sites {
+			var args []string
+			for _, arg := range call.Args {
+				args = append(args, lexer.Join(arg, " "))
+			}
+
+			inputs[strings.Join(args, ",")] = true
+		}
+
+		if len(sites) >= lazyMinCalls && len(inputs) >= lazyMinCalls {
+			f.reportTest(LazyTest, t, "%s checks %d scenarios of %s in sequence", t.name, len(sites), callee)
+			break
+		}
+	}
+}
+
+// productionCallee returns the call name when it resolves to an imported
+// function of the code under test, or "".
+func (f *pyFile) productionCallee(name string) string {
+	root, _, _ := strings.Cut(name, ".")
+
+	module, ok := f.imports[root]
+	if !ok || root == "self" {
+		return ""
+	}
+
+	top, _, _ := strings.Cut(strings.TrimLeft(module, "."), ".")
+	if pySupportModules[top] || strings.Contains(module, "test") || strings.Contains(module, "mock") {
+		return ""
+	}
+
+	return name
+}
+
+// checkConditionalLogic reports branches and loops that make assertions conditional.
+// A guard that only calls pytest.fail or self.fail is not a smell.
+func (f *pyFile) checkConditionalLogic(t *pyTest) {
+	for i := t.def + 1; i < t.end; i++ {
+		stmt := f.stmts[i]
+		head := stmt.tokens[0]
+		end := f.blockEnd(i)
+
+		switch {
+		case head.Is(lexer.Ident, "if"):
+			chainEnd := end
+			for chainEnd < t.end && f.stmts[chainEnd].indent == stmt.indent &&
+				(f.stmts[chainEnd].tokens[0].Is(lexer.Ident, "elif") || f.stmts[chainEnd].tokens[0].Is(lexer.Ident, "else")) {
+				chainEnd = f.blockEnd(chainEnd)
+			}
+
+			switch {
+			case chainEnd > end:
+				f.report(ConditionalLogic, stmt.line, f.stmts[chainEnd-1].endLine, t,
+					"%s branches with if/else around test logic", t.name)
+			case verifies(f.stmts[i+1 : end]):
+				f.report(ConditionalLogic, stmt.line, f.stmts[end-1].endLine, t,
+					"%s makes assertions only when a condition holds", t.name)
+			}
+		case head.Is(lexer.Ident, "for") || head.Is(lexer.Ident, "while"):
+			block := f.stmts[i:end]
+			if verifies(block) && !lexer.HasIdent(f.bodyTokens(i-1, end), "subTest") {
+				f.report(ConditionalLogic, stmt.line, f.stmts[end-1].endLine, t,
+					"%s asserts inside a loop instead of parametrizing", t.name)
+
+				i = end - 1
+			}
+		case head.Is(lexer.Ident, "match"):
+			f.report(ConditionalLogic, stmt.line, f.stmts[end-1].endLine, t, "%s matches between different test paths", t.name)
+		}
+	}
+}
+
+// checkObscure reports tests with meaningless names or overly long bodies.
+func (f *pyFile) checkObscure(t *pyTest) {
+	subject := strings.ToLower(strings.TrimRight(strings.Trim(strings.TrimPrefix(t.name, "test"), "_"), "0123456789_"))
+	if obscureNames[subject] {
+		def := f.stmts[t.def]
+		f.report(ObscureTest, def.line, def.line, t, "%s does not describe the behavior under test", t.name)
+	}
+
+	if statements := t.end - t.def - 1; statements > obscureMaxStatements {
+		f.reportTest(ObscureTest, t, "%s has %d statements, which makes its intent hard to follow", t.name, statements)
+	}
+}
+
+// checkSensitiveEquality reports comparisons of str(), repr() and
+// serialized output, and of serialized or timestamped string literals.
+func (f *pyFile) checkSensitiveEquality(t *pyTest) {
+	for _, stmt := range f.body(t.def, t.end) {
+		for _, a := range pyAssertions(stmt) {
+			var operands [][]lexer.Token
+
+			switch {
+			case a.style == "assert" && len(a.args) == 1:
+				for _, op := range []string{"==", "!="} {
+					if parts := splitTop(a.args[0], op); len(parts) == 2 {
+						operands = parts
+					}
+				}
+			case a.style == "unittest" && pyEqualityAsserts[a.name] && len(a.args) >= 2:
+				operands = a.args[:2]
+			}
+
+			for _, operand := range operands {
+				if what := f.sensitiveOperand(operand); what != "" {
+					f.report(SensitiveEquality, stmt.line, stmt.endLine, t, "%s compares %s", t.name, what)
+					break
+				}
+			}
+		}
+	}
+}
+
+// sensitiveOperand describes why an operand makes an equality check fragile, or returns "".
+func (f *pyFile) sensitiveOperand(operand []lexer.Token) string {
+	if s, ok := literalString(operand); ok {
+		return sensitiveLiteral(s)
+	}
+
+	name, next := lexer.DottedName(operand, 0)
+	if next >= len(operand) || !operand[next].Is(lexer.Punct, "(") || lexer.Match(operand, next) != len(operand)-1 {
+		return ""
+	}
+
+	switch {
+	case name == "str" || name == "repr":
+		return "the " + name + "() form of a value (" + lexer.Join(operand, " ") + ")"
+	case f.imports.Resolve(name) == "json.dumps":
+		return "serialized JSON (" + name + ")"
+	case strings.HasSuffix(name, ".__str__") || strings.HasSuffix(name, ".__repr__"):
+		return "the string form of a value (" + lexer.Join(operand, " ") + ")"
+	default:
+		return ""
+	}
+}
+
+// checkGeneralFixture reports setUp methods and fixtures that prepare more
+// than their tests use, and conftest.py fixtures that bundle many values or
+// run broad autouse setup for every test.
+//
+//nolint:gocognit,gocyclo // Handles setUp methods, module fixtures and conftest fixtures
+func (f *pyFile) checkGeneralFixture(tests []*pyTest, fixtures []*pyFixture) {
+	conftest := path.Base(f.path) == "conftest.py"
+
+	for _, fx := range fixtures {
+		def := f.stmts[fx.def]
+		end := f.stmts[fx.end-1].endLine
+		values := make(map[string]bool)
+
+		switch {
+		case fx.setUp:
+			for _, stmt := range f.body(fx.def, fx.end) {
+				toks := stmt.tokens
+				if len(toks) > 3 && toks[0].Is(lexer.Ident, "self") && toks[1].Is(lexer.Punct, ".") && toks[3].Is(lexer.Punct, "=") {
+					values[toks[2].Text] = true
+				}
+			}
+		default:
+			for _, key := range f.fixtureKeys(fx) {
+				values[key] = true
+			}
+		}
+
+		if conftest {
+			setup := 0
+
+			for _, stmt := range f.body(fx.def, fx.end) {
+				if !stmt.tokens[0].Is(lexer.Ident, "yield") && !stmt.tokens[0].Is(lexer.Ident, "return") {
+					setup++
+				}
+			}
+
+			switch {
+			case len(values) >= generalFixtureMinValues:
+				f.report(GeneralFixture, def.line, end, nil, "fixture %s bundles %d values for every test that requests it",
+					fx.name, len(values))
+			case fx.autouse && setup >= generalFixtureMinValues:
+				f.report(GeneralFixture, def.line, end, nil, "autouse fixture %s runs %d setup statements before every test",
+					fx.name, setup)
+			}
+
+			continue
+		}
+
+		if len(values) < generalFixtureMinValues {
+			continue
+		}
+
+		consumers, partial := 0, 0
+
+		for _, t := range tests {
+			if fx.setUp && t.class != fx.class {
+				continue
+			}
+
+			if !fx.setUp && !pyRequests(t, fx.name) {
+				continue
+			}
+
+			consumers++
+
+			if used := f.fixtureUsage(t, fx, values); used*2 < len(values) {
+				partial++
+			}
+		}
+
+		if consumers > 0 && partial*2 >= consumers {
+			f.report(GeneralFixture, def.line, end, nil, "%s prepares %d shared values but %d of %d tests use fewer than half of them",
+				fx.name, len(values), partial, consumers)
+		}
+	}
+}
+
+func pyRequests(t *pyTest, fixture string) bool {
+	for _, param := range t.params {
+		if param == fixture {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fixtureKeys returns the keys of a dict literal, or the positions of a
+// tuple, returned or yielded by a fixture.
+func (f *pyFile) fixtureKeys(fx *pyFixture) []string {
+	for _, stmt := range f.body(fx.def, fx.end) {
+		toks := stmt.tokens
+		if len(toks) < 3 || (!toks[0].Is(lexer.Ident, "return") && !toks[0].Is(lexer.Ident, "yield")) {
+			continue
+		}
+
+		value := toks[1:]
+
+		var keys []string
+
+		switch {
+		case value[0].Is(lexer.Punct, "{") && lexer.Match(value, 0) == len(value)-1:
+			for _, entry := range lexer.SplitArgs(value, 0) {
+				if len(entry) > 0 && entry[0].Kind == lexer.String {
+					keys = append(keys, lexer.Unquote(entry[0].Text))
+				}
+			}
+		case value[0].Is(lexer.Ident, "dict") && len(value) > 1 && value[1].Is(lexer.Punct, "("):
+			for _, entry := range lexer.SplitArgs(value, 1) {
+				if len(entry) > 0 && entry[0].Kind == lexer.Ident {
+					keys = append(keys, entry[0].Text)
+				}
+			}
+		default:
+			parts := splitTop(value, ",")
+			if len(parts) == 1 && value[0].Is(lexer.Punct, "(") && lexer.Match(value, 0) == len(value)-1 {
+				parts = lexer.SplitArgs(value, 0)
+			}
+
+			if len(parts) > 1 {
+				for i := range parts {
+					keys = append(keys, fmt.Sprintf("[%d]", i))
+				}
+			}
+		}
+
+		return keys
+	}
+
+	return nil
+}
+
+// fixtureUsage counts the fixture values a test references.
+func (f *pyFile) fixtureUsage(t *pyTest, fx *pyFixture, values map[string]bool) int {
+	toks := f.bodyTokens(t.def, t.end)
+	used := make(map[string]bool)
+
+	for k := 0; k+2 < len(toks); k++ {
+		switch {
+		case fx.setUp && toks[k].Is(lexer.Ident, "self") && toks[k+1].Is(lexer.Punct, "."):
+			if values[toks[k+2].Text] {
+				used[toks[k+2].Text] = true
+			}
+		case !fx.setUp && toks[k].Is(lexer.Ident, fx.name) && toks[k+1].Is(lexer.Punct, "["):
+			key := lexer.Unquote(toks[k+2].Text)
+			if toks[k+2].Kind == lexer.Number {
+				key = "[" + toks[k+2].Text + "]"
+			}
+
+			if values[key] {
+				used[key] = true
+			}
+		}
+	}
+
+	// Unpacking a tuple fixture uses every value
+	if !fx.setUp && len(used) == 0 {
+		for _, stmt := range f.body(t.def, t.end) {
+			if parts := splitTop(stmt.tokens, "="); len(parts) == 2 && lexer.Join(parts[1], " ") == fx.name &&
+				len(splitTop(parts[0], ",")) > 1 {
+				return len(values)
+			}
+		}
+	}
+
+	return len(used)
+}
@@ -0,0 +1,339 @@
+package smells
+
+import (
+	"testing"
+)
+
+const pyTestHeader = `import json
+import os
+import random
+import threading
+import time
+import unittest
+
+import pytest
+import requests
+
+from app.orders import cancel, checkout, create_cart, parse
+from app.users import create_user
+`
+
+// pySmellsOf returns the smells found in a Python module body appended to pyTestHeader.
+func pySmellsOf(t *testing.T, relPath, body string) map[Smell]int {
+	t.Helper()
+
+	findings, err := NewPythonDetector().Detect(relPath, []byte(pyTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	counts := make(map[Smell]int)
+	for _, f := range findings {
+		counts[Smell(f.CheckID)]++
+	}
+
+	return counts
+}
+
+//nolint:gocognit,gocyclo // Table-driven tests can be complex but are still readable
+func TestPythonDetector(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		want   Smell
+		absent bool
+	}{
+		{
+			name: "mystery guest opens untracked file",
+			body: `
+def test_load_users():
+    with open("users.json") as fh:
+        assert json.load(fh)
+`,
+			want: MysteryGuest,
+		},
+		{
+			name: "file under testdata is not a mystery guest",
+			body: `
+def test_load_users():
+    with open("tests/testdata/users.json") as fh:
+        assert json.load(fh)
+`,
+			want:   MysteryGuest,
+			absent: true,
+		},
+		{
+			name: "mystery guest reads environment",
+			body: `
+def test_database_url():
+    assert os.environ["DATABASE_URL"].startswith("postgres")
+`,
+			want: MysteryGuest,
+		},
+		{
+			name: "environment set with monkeypatch",
+			body: `
+def test_database_url(monkeypatch):
+    monkeypatch.setenv("DATABASE_URL", "postgres://")
+    assert os.getenv("DATABASE_URL").startswith("postgres")
+`,
+			want:   MysteryGuest,
+			absent: true,
+		},
+		{
+			name: "mystery guest calls external service",
+			body: `
+def test_status_page():
+    resp = requests.get("https://status.example.com/api")
+    assert resp.status_code == 200
+`,
+			want: MysteryGuest,
+		},
+		{
+			name: "assertion roulette in unittest",
+			body: `
+class TestUser(unittest.TestCase):
+    def test_user_fields(self):
+        user = create_user("alice")
+        self.assertEqual(user.name, "alice")
+        self.assertTrue(user.active)
+        self.assertIsNotNone(user.created)
+`,
+			want: AssertionRoulette,
+		},
+		{
+			name: "unittest assertions with messages",
+			body: `
+class TestUser(unittest.TestCase):
+    def test_user_fields(self):
+        user = create_user("alice")
+        self.assertEqual(user.name, "alice", "name")
+        self.assertTrue(user.active, msg="active")
+        self.assertIsNotNone(user.created, "created")
+`,
+			want:   AssertionRoulette,
+			absent: true,
+		},
+		{
+			name: "bare pytest asserts are introspected",
+			body: `
+def test_user_fields():
+    user = create_user("alice")
+    assert user.name == "alice"
+    assert user.active
+    assert user.created is not None
+`,
+			want:   AssertionRoulette,
+			absent: true,
+		},
+		{
+			name: "eager test",
+			body: `
+def test_order_lifecycle():
+    user = create_user("alice")
+    cart = create_cart(user)
+    assert cart.items == []
+    assert checkout(cart).ok
+    assert cancel(cart).ok
+    assert cart.cancelled
+`,
+			want: EagerTest,
+		},
+		{
+			name: "lazy test",
+			body: `
+def test_parse():
+    assert parse("1") == 1
+    assert parse("2") == 2
+    assert parse("3") == 3
+`,
+			want: LazyTest,
+		},
+		{
+			name: "parametrized test is not lazy",
+			body: `
+@pytest.mark.parametrize("text,want", [("1", 1), ("2", 2), ("3", 3)])
+def test_parse(text, want):
+    assert parse(text) == want
+    assert parse(text) == want
+    assert parse(text + "") == want
+`,
+			want:   LazyTest,
+			absent: true,
+		},
+		{
+			name: "conditional logic in test",
+			body: `
+def test_discount():
+    user = create_user("vip")
+    if user.vip:
+        assert checkout(create_cart(user)).discount == 10
+    else:
+        assert checkout(create_cart(user)).discount == 0
+`,
+			want: ConditionalLogic,
+		},
+		{
+			name: "loop with subTest is not conditional logic",
+			body: `
+class TestParse(unittest.TestCase):
+    def test_parse_numbers(self):
+        for text in ["1", "2"]:
+            with self.subTest(text=text):
+                self.assertEqual(parse(text), int(text), "parsed")
+`,
+			want:   ConditionalLogic,
+			absent: true,
+		},
+		{
+			name: "guard with pytest.fail is not conditional logic",
+			body: `
+def test_checkout_succeeds():
+    result = checkout(create_cart(create_user("a")))
+    if not result.ok:
+        pytest.fail("checkout failed")
+`,
+			want:   ConditionalLogic,
+			absent: true,
+		},
+		{
+			name: "flakiness from time.sleep",
+			body: `
+def test_cache_expiry():
+    cart = create_cart(None)
+    time.sleep(2)
+    assert cart.expired
+`,
+			want: Flakiness,
+		},
+		{
+			name: "unjoined thread",
+			body: `
+def test_background_checkout():
+    threading.Thread(target=checkout, args=(None,)).start()
+    assert True
+`,
+			want: Flakiness,
+		},
+		{
+			name: "obscure test name",
+			body: `
+def test_1():
+    assert parse("1") == 1
+`,
+			want: ObscureTest,
+		},
+		{
+			name: "sensitive equality with str()",
+			body: `
+def test_user_repr():
+    user = create_user("alice")
+    assert str(user) == "User(name=alice)"
+`,
+			want: SensitiveEquality,
+		},
+		{
+			name: "sensitive equality with serialized JSON literal",
+			body: `
+class TestUser(unittest.TestCase):
+    def test_user_json(self):
+        self.assertEqual(create_user("a").to_json(), '{"name": "a"}', "json")
+`,
+			want: SensitiveEquality,
+		},
+		{
+			name: "resource optimism with fixed port",
+			body: `
+def test_server_binds(server_socket):
+    server_socket.bind(("localhost", 8080))
+    assert server_socket
+`,
+			want: ResourceOptimism,
+		},
+		{
+			name: "file written without cleanup",
+			body: `
+def test_export():
+    with open("export.csv", "w") as fh:
+        fh.write("a,b")
+    assert os.path.exists("export.csv")
+`,
+			want: ResourceOptimism,
+		},
+		{
+			name: "general fixture in setUp",
+			body: `
+class TestOrders(unittest.TestCase):
+    def setUp(self):
+        self.user = create_user("alice")
+        self.cart = create_cart(self.user)
+        self.db = object()
+        self.clock = object()
+
+    def test_user_name(self):
+        self.assertEqual(self.user.name, "alice", "name")
+
+    def test_clock_set(self):
+        self.assertIsNotNone(self.clock, "clock")
+`,
+			want: GeneralFixture,
+		},
+		{
+			name: "broad conftest fixture",
+			path: "tests/conftest.py",
+			body: `
+@pytest.fixture
+def world():
+    user = create_user("alice")
+    return {"user": user, "cart": create_cart(user), "db": None, "clock": None}
+`,
+			want: GeneralFixture,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			relPath := tt.path
+			if relPath == "" {
+				relPath = "tests/test_app.py"
+			}
+
+			got := pySmellsOf(t, relPath, tt.body)
+
+			if tt.absent && got[tt.want] > 0 {
+				t.Errorf("expected no %s, got %v", tt.want, got)
+			}
+
+			if !tt.absent && got[tt.want] == 0 {
+				t.Errorf("expected %s, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPythonDetectorFindingDetails(t *testing.T) {
+	body := `
+class TestCache(unittest.TestCase):
+    def test_expiry(self):
+        time.sleep(1)
+`
+
+	findings, err := NewPythonDetector().Detect("tests/test_cache.py", []byte(pyTestHeader+body))
+	if err != nil {
+		t.Fatalf("Detect() error = %v", err)
+	}
+
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d: %+v", len(findings), findings)
+	}
+
+	f := findings[0]
+	if f.CheckID != string(Flakiness) || f.Test != "tests/test_cache.py::TestCache::test_expiry" {
+		t.Errorf("finding = %+v", f)
+	}
+
+	if f.Location.StartLine != 16 || f.Description != "test_expiry waits on the clock instead of synchronizing (time.sleep(1))" {
+		t.Errorf("finding = %+v", f)
+	}
+}
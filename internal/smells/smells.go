@@ -0,0 +1,449 @@
+// Package smells detects test smells in test source files.
+//
+// Each supported language provides a Detector that reports the smells of a
+// single test file as findings. The Engine walks a repository, dispatches test
+// files to the detector for their language and applies the smell
+// configuration (quality.smells.detect and quality.smells.severity-overrides).
+package smells
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+// Smell identifies a test smell of the catalog.
+type Smell string
+
+// Smell constants mirror the catalog in testdata/ground-truth/test-smells.
+const (
+	MysteryGuest      Smell = "mystery-guest"
+	EagerTest         Smell = "eager-test"
+	LazyTest          Smell = "lazy-test"
+	AssertionRoulette Smell = "assertion-roulette"
+	ConditionalLogic  Smell = "conditional-logic"
+	GeneralFixture    Smell = "general-fixture"
+	ObscureTest       Smell = "obscure-test"
+	SensitiveEquality Smell = "sensitive-equality"
+	ResourceOptimism  Smell = "resource-optimism"
+	CodeDuplication   Smell = "code-duplication"
+	Flakiness         Smell = "flakiness"
+)
+
+// Smells specific to JavaScript/TypeScript test frameworks (Jest, Vitest, Mocha).
+const (
+	FocusedTest  Smell = "focused-test"
+	SkippedTest  Smell = "skipped-test"
+	EmptyTest    Smell = "empty-test"
+	SnapshotOnly Smell = "snapshot-only"
+	MissingAwait Smell = "missing-await"
+)
+
+// Definition describes a smell of the catalog.
+type Definition struct {
+	// Smell is the catalog identifier
+	Smell Smell
+
+	// Title is the human-readable name
+	Title string
+
+	// Severity is the default severity, overridable in configuration
+	Severity types.Severity
+
+	// Type is the finding type reported for the smell
+	Type types.FindingType
+
+	// Rationale explains why the smell matters
+	Rationale string
+
+	// Remediation describes how to remove the smell
+	Remediation types.Remediation
+}
+
+// Catalog lists every smell with its default severity, rationale and remediation.
+var Catalog = map[Smell]Definition{
+	MysteryGuest: {
+		Smell:     MysteryGuest,
+		Title:     "Mystery Guest",
+		Severity:  types.SeverityHigh,
+		Type:      types.FindingTypeQuality,
+		Rationale: "The test depends on external files, environment variables, databases or network services that are not visible in the test, making it hard to understand and brittle.",
+		Remediation: types.Remediation{
+			Summary: "Make external dependencies explicit in the test",
+			Steps: []string{
+				"Create the files the test needs inside the test (t.TempDir, tmp_path) or keep them under testdata/",
+				"Set environment variables in the test (t.Setenv, monkeypatch.setenv)",
+				"Replace network and database access with fakes, mocks or in-process servers",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	EagerTest: {
+		Smell:     EagerTest,
+		Title:     "Eager Test",
+		Severity:  types.SeverityMedium,
+		Type:      types.FindingTypeQuality,
+		Rationale: "The test exercises several unrelated behaviors, so a failure does not point at a single cause and the test is hard to name.",
+		Remediation: types.Remediation{
+			Summary: "Split the test into focused tests, one behavior each",
+			Steps: []string{
+				"Group the assertions by the behavior they verify",
+				"Move each group into its own test with a descriptive name",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	LazyTest: {
+		Smell:     LazyTest,
+		Title:     "Lazy Test",
+		Severity:  types.SeverityLow,
+		Type:      types.FindingTypeQuality,
+		Rationale: "The test checks several scenarios of the same function one after another instead of using the framework's data-driven features, so the first failure hides the others.",
+		Remediation: types.Remediation{
+			Summary: "Convert the scenarios into a table-driven or parameterized test",
+			Steps: []string{
+				"Collect the inputs and expected outputs into a table (Go), parametrize (pytest) or each (Jest/Vitest)",
+				"Run every row as a named subtest or parameter case",
+			},
+			Effort: types.EffortMinimal,
+		},
+	},
+	AssertionRoulette: {
+		Smell:     AssertionRoulette,
+		Title:     "Assertion Roulette",
+		Severity:  types.SeverityMedium,
+		Type:      types.FindingTypeQuality,
+		Rationale: "Several assertions without explanatory messages make it hard to tell which expectation failed and why.",
+		Remediation: types.Remediation{
+			Summary: "Add descriptive messages to the assertions or split the test",
+			Steps: []string{
+				"Pass a message argument describing each expectation",
+				"Prefer one logical assertion per test where practical",
+			},
+			Effort: types.EffortMinimal,
+		},
+	},
+	ConditionalLogic: {
+		Smell:     ConditionalLogic,
+		Title:     "Conditional Test Logic",
+		Severity:  types.SeverityMedium,
+		Type:      types.FindingTypeQuality,
+		Rationale: "Branches and loops in a test mean that some assertions may never run, so the test can pass without verifying anything.",
+		Remediation: types.Remediation{
+			Summary: "Remove branching from the test body",
+			Steps: []string{
+				"Split each branch into its own test with a fixed setup",
+				"Replace loops over cases with table-driven or parameterized tests",
+				"Use explicit skip conditions instead of silently bypassing assertions",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	GeneralFixture: {
+		Smell:     GeneralFixture,
+		Title:     "General Fixture",
+		Severity:  types.SeverityLow,
+		Type:      types.FindingTypeMaintainability,
+		Rationale: "The shared setup prepares more than most tests use, which slows the suite down and hides what each test actually depends on.",
+		Remediation: types.Remediation{
+			Summary: "Narrow the shared fixture to what every test needs",
+			Steps: []string{
+				"Move setup used by only some tests into those tests or into smaller helpers",
+				"Prefer granular fixtures that tests request explicitly",
+			},
+			Effort: types.EffortMedium,
+		},
+	},
+	ObscureTest: {
+		Smell:     ObscureTest,
+		Title:     "Obscure Test",
+		Severity:  types.SeverityLow,
+		Type:      types.FindingTypeMaintainability,
+		Rationale: "The test's intent is unclear from its name or its body is too long to follow, so readers cannot tell what behavior it protects.",
+		Remediation: types.Remediation{
+			Summary: "Make the test's intent obvious",
+			Steps: []string{
+				"Rename the test after the behavior and expected outcome it verifies",
+				"Extract setup into well-named helpers and keep the body short",
+			},
+			Effort: types.EffortMinimal,
+		},
+	},
+	SensitiveEquality: {
+		Smell:     SensitiveEquality,
+		Title:     "Sensitive Equality",
+		Severity:  types.SeverityLow,
+		Type:      types.FindingTypeQuality,
+		Rationale: "Comparing formatted strings, error messages or serialized output breaks on irrelevant changes such as wording, field order or whitespace.",
+		Remediation: types.Remediation{
+			Summary: "Compare structured values instead of their string form",
+			Steps: []string{
+				"Compare the fields that matter instead of String() or serialized output",
+				"Check errors by identity or type (errors.Is, errors.As, pytest.raises(match=...))",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	ResourceOptimism: {
+		Smell:     ResourceOptimism,
+		Title:     "Resource Optimism",
+		Severity:  types.SeverityMedium,
+		Type:      types.FindingTypeQuality,
+		Rationale: "The test assumes files, directories or ports are available and never cleans them up, so it fails on other machines or interferes with parallel runs.",
+		Remediation: types.Remediation{
+			Summary: "Create isolated resources and release them",
+			Steps: []string{
+				"Use per-test temporary directories instead of fixed paths",
+				"Listen on port 0 and read the assigned address",
+				"Close and remove resources with defer or cleanup hooks",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	CodeDuplication: {
+		Smell:     CodeDuplication,
+		Title:     "Code Duplication",
+		Severity:  types.SeverityLow,
+		Type:      types.FindingTypeMaintainability,
+		Rationale: "Copy-pasted test code has to be updated in several places and obscures what differs between tests.",
+		Remediation: types.Remediation{
+			Summary: "Extract the repeated code into a helper or fixture",
+			Steps: []string{
+				"Move the duplicated statements into a helper function or fixture",
+				"Keep only the parts that differ in each test",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	Flakiness: {
+		Smell:     Flakiness,
+		Title:     "Flakiness",
+		Severity:  types.SeverityHigh,
+		Type:      types.FindingTypePerformance,
+		Rationale: "The test depends on timing, randomness or unsynchronized concurrency, so it can pass or fail without any code change.",
+		Remediation: types.Remediation{
+			Summary: "Make the test deterministic",
+			Steps: []string{
+				"Replace sleeps with explicit synchronization or polling with a deadline",
+				"Inject clocks and seeded random sources",
+				"Wait for goroutines, threads and promises before asserting",
+			},
+			Effort: types.EffortMedium,
+		},
+	},
+	FocusedTest: {
+		Smell:     FocusedTest,
+		Title:     "Focused Test",
+		Severity:  types.SeverityHigh,
+		Type:      types.FindingTypeBestPractice,
+		Rationale: "A committed .only (or fit/fdescribe) makes the runner skip every other test in the file, so CI passes without running most of the suite.",
+		Remediation: types.Remediation{
+			Summary: "Remove the .only modifier before committing",
+			Steps: []string{
+				"Replace it.only/test.only/describe.only with it/test/describe",
+				"Enable a lint rule such as jest/no-focused-tests or vitest/no-focused-tests",
+			},
+			Effort: types.EffortMinimal,
+		},
+	},
+	SkippedTest: {
+		Smell:     SkippedTest,
+		Title:     "Skipped Test",
+		Severity:  types.SeverityLow,
+		Type:      types.FindingTypeBestPractice,
+		Rationale: "Skipped tests silently stop protecting behavior and tend to stay disabled long after the reason is forgotten.",
+		Remediation: types.Remediation{
+			Summary: "Fix or delete the skipped test",
+			Steps: []string{
+				"Fix the underlying problem and remove .skip (or xit/xdescribe)",
+				"Delete the test if the behavior is no longer relevant, or track it as test.todo with an issue link",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	EmptyTest: {
+		Smell:     EmptyTest,
+		Title:     "Empty Test",
+		Severity:  types.SeverityMedium,
+		Type:      types.FindingTypeQuality,
+		Rationale: "A test with an empty body always passes and inflates the test count without verifying anything.",
+		Remediation: types.Remediation{
+			Summary: "Implement the test or mark it as todo",
+			Steps: []string{
+				"Add the setup, action and assertions the test name promises",
+				"Use test.todo('name') for planned tests so they are reported as pending",
+			},
+			Effort: types.EffortLow,
+		},
+	},
+	SnapshotOnly: {
+		Smell:     SnapshotOnly,
+		Title:     "Snapshot-Only Test",
+		Severity:  types.SeverityLow,
+		Type:      types.FindingTypeMaintainability,
+		Rationale: "Tests that only compare large snapshots of whole rendered output break on any markup change and are routinely updated without review, so they rarely catch regressions.",
+		Remediation: types.Remediation{
+			Summary: "Assert on the behavior that matters instead of a large snapshot",
+			Steps: []string{
+				"Replace the snapshot with targeted assertions on text, roles or state",
+				"Keep snapshots small by snapshotting a specific element or serialized value",
+			},
+			Effort: types.EffortMedium,
+		},
+	},
+	MissingAwait: {
+		Smell:     MissingAwait,
+		Title:     "Missing Await",
+		Severity:  types.SeverityHigh,
+		Type:      types.FindingTypeQuality,
+		Rationale: "An asynchronous assertion that is neither awaited nor returned settles after the test has finished, so its failure is never reported.",
+		Remediation: types.Remediation{
+			Summary: "Await or return the asynchronous assertion",
+			Steps: []string{
+				"Add await before expect(...).resolves/.rejects, waitFor and findBy* queries",
+				"Make the test callback async where needed",
+			},
+			Effort: types.EffortMinimal,
+		},
+	},
+}
+
+// Thresholds shared by the language detectors.
+const (
+	rouletteMinAssertions   = 3  // Messageless assertions in one test
+	eagerMinCalls           = 3  // Distinct functions under test called by one test
+	eagerMinAssertions      = 4  // Assertions in an eager test
+	lazyMinCalls            = 3  // Calls of the same function with different inputs
+	obscureMaxStatements    = 40 // Statements before a test becomes hard to follow
+	generalFixtureMinValues = 3  // Values prepared by a shared fixture
+)
+
+// obscureNames are test name suffixes that say nothing about the behavior under test.
+var obscureNames = map[string]bool{
+	"": true, "it": true, "foo": true, "bar": true, "baz": true, "stuff": true, "misc": true,
+	"temp": true, "tmp": true, "something": true, "thing": true, "things": true, "case": true,
+	"test": true, "tests": true, "works": true, "example": true, "scenario": true, "todo": true,
+	"wip": true, "x": true,
+}
+
+// timestampPattern matches ISO 8601 timestamps embedded in expected strings.
+var timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}`)
+
+// All returns every smell of the catalog in a stable order.
+func All() []Smell {
+	all := make([]Smell, 0, len(Catalog))
+	for smell := range Catalog {
+		all = append(all, smell)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	return all
+}
+
+// Config controls which smells are reported and at which severity.
+type Config struct {
+	// Enabled turns smell detection on or off as a whole
+	Enabled bool
+
+	// Disabled lists smells switched off via quality.smells.detect
+	Disabled map[Smell]bool
+
+	// SeverityOverrides replaces the default severity of a smell
+	SeverityOverrides map[Smell]types.Severity
+
+	// MinCloneTokens is the length from which repeated code is reported as code-duplication
+	MinCloneTokens int
+
+	// Suppressions silence smells in matching paths
+	Suppressions []PathSuppression
+}
+
+// DefaultConfig enables every smell with its catalog severity.
+func DefaultConfig() *Config {
+	return &Config{
+		Enabled:           true,
+		Disabled:          make(map[Smell]bool),
+		SeverityOverrides: make(map[Smell]types.Severity),
+		MinCloneTokens:    DefaultMinCloneTokens,
+	}
+}
+
+// LoadConfig reads quality.smells.enabled, quality.smells.detect,
+// quality.smells.severity-overrides, quality.smells.duplication.min-tokens
+// and quality.smells.suppress. Unknown smells and invalid severities are
+// rejected so typos do not silently disable checks.
+func LoadConfig(v *viper.Viper) (*Config, error) {
+	cfg := DefaultConfig()
+
+	if v.IsSet("quality.smells.enabled") {
+		cfg.Enabled = v.GetBool("quality.smells.enabled")
+	}
+
+	for name := range v.GetStringMap("quality.smells.detect") {
+		smell := Smell(name)
+		if _, ok := Catalog[smell]; !ok {
+			return nil, fmt.Errorf("unknown smell %q in quality.smells.detect", name)
+		}
+
+		if !v.GetBool("quality.smells.detect." + name) {
+			cfg.Disabled[smell] = true
+		}
+	}
+
+	for name, value := range v.GetStringMapString("quality.smells.severity-overrides") {
+		smell := Smell(name)
+		if _, ok := Catalog[smell]; !ok {
+			return nil, fmt.Errorf("unknown smell %q in quality.smells.severity-overrides", name)
+		}
+
+		severity, err := types.ParseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid severity override for %s: %w", name, err)
+		}
+
+		cfg.SeverityOverrides[smell] = severity
+	}
+
+	if v.IsSet("quality.smells.duplication.min-tokens") {
+		cfg.MinCloneTokens = v.GetInt("quality.smells.duplication.min-tokens")
+		if cfg.MinCloneTokens <= 0 {
+			return nil, fmt.Errorf("quality.smells.duplication.min-tokens must be positive, got %d", cfg.MinCloneTokens)
+		}
+	}
+
+	if err := v.UnmarshalKey("quality.smells.suppress", &cfg.Suppressions); err != nil {
+		return nil, fmt.Errorf("invalid quality.smells.suppress: %w", err)
+	}
+
+	for i, s := range cfg.Suppressions {
+		if s.Path == "" {
+			return nil, fmt.Errorf("quality.smells.suppress[%d] has no path", i)
+		}
+
+		for _, name := range s.Smells {
+			if _, ok := Catalog[Smell(name)]; !ok {
+				return nil, fmt.Errorf("unknown smell %q in quality.smells.suppress", name)
+			}
+		}
+	}
+
+	return cfg, nil
+}
+
+// IsEnabled reports whether a smell should be reported.
+func (c *Config) IsEnabled(smell Smell) bool {
+	return c.Enabled && !c.Disabled[smell]
+}
+
+// SeverityOf returns the configured severity of a smell.
+func (c *Config) SeverityOf(smell Smell) types.Severity {
+	if severity, ok := c.SeverityOverrides[smell]; ok {
+		return severity
+	}
+
+	return Catalog[smell].Severity
+}
@@ -0,0 +1,170 @@
+package smells
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+	"github.com/spf13/viper"
+)
+
+func TestCatalogIsComplete(t *testing.T) {
+	all := All()
+	if len(all) != 16 {
+		t.Fatalf("expected 16 smells, got %d", len(all))
+	}
+
+	for _, smell := range all {
+		def := Catalog[smell]
+		if def.Smell != smell || def.Title == "" || def.Rationale == "" || def.Remediation.Summary == "" {
+			t.Errorf("incomplete catalog entry for %s: %+v", smell, def)
+		}
+
+		if def.Severity.Rank() < 0 {
+			t.Errorf("invalid default severity for %s: %q", smell, def.Severity)
+		}
+	}
+}
+
+//nolint:gocognit // Table-driven tests can be complex but are still readable
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		wantErr  string
+		check    func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, cfg *Config) {
+				if !cfg.IsEnabled(MysteryGuest) {
+					t.Error("expected mystery-guest enabled by default")
+				}
+
+				if cfg.SeverityOf(MysteryGuest) != types.SeverityHigh {
+					t.Errorf("SeverityOf(mystery-guest) = %s, want high", cfg.SeverityOf(MysteryGuest))
+				}
+			},
+		},
+		{
+			name: "detect disables smells",
+			settings: map[string]any{
+				"quality.smells.detect": map[string]any{"lazy-test": false, "eager-test": true},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.IsEnabled(LazyTest) {
+					t.Error("expected lazy-test disabled")
+				}
+
+				if !cfg.IsEnabled(EagerTest) {
+					t.Error("expected eager-test enabled")
+				}
+			},
+		},
+		{
+			name:     "enabled false disables everything",
+			settings: map[string]any{"quality.smells.enabled": false},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.IsEnabled(Flakiness) {
+					t.Error("expected all smells disabled")
+				}
+			},
+		},
+		{
+			name: "severity overrides",
+			settings: map[string]any{
+				"quality.smells.severity-overrides": map[string]any{"assertion-roulette": "critical"},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.SeverityOf(AssertionRoulette) != types.SeverityCritical {
+					t.Errorf("SeverityOf(assertion-roulette) = %s, want critical", cfg.SeverityOf(AssertionRoulette))
+				}
+			},
+		},
+		{
+			name: "unknown smell",
+			settings: map[string]any{
+				"quality.smells.detect": map[string]any{"mystery-guets": false},
+			},
+			wantErr: "unknown smell",
+		},
+		{
+			name:     "clone length",
+			settings: map[string]any{"quality.smells.duplication.min-tokens": 80},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.MinCloneTokens != 80 {
+					t.Errorf("MinCloneTokens = %d, want 80", cfg.MinCloneTokens)
+				}
+			},
+		},
+		{
+			name: "path suppressions",
+			settings: map[string]any{
+				"quality.smells.suppress": []any{
+					map[string]any{"path": "legacy/**", "smells": []any{"eager-test"}, "reason": "pre-dates the guidelines"},
+					map[string]any{"path": "e2e/**"},
+				},
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if len(cfg.Suppressions) != 2 {
+					t.Fatalf("Suppressions = %+v", cfg.Suppressions)
+				}
+
+				s := cfg.Suppressions[0]
+				if s.Path != "legacy/**" || len(s.Smells) != 1 || s.Smells[0] != "eager-test" || s.Reason == "" {
+					t.Errorf("Suppressions[0] = %+v", s)
+				}
+			},
+		},
+		{
+			name: "suppression of unknown smell",
+			settings: map[string]any{
+				"quality.smells.suppress": []any{map[string]any{"path": "legacy/**", "smells": []any{"eagre-test"}}},
+			},
+			wantErr: "unknown smell",
+		},
+		{
+			name: "suppression without path",
+			settings: map[string]any{
+				"quality.smells.suppress": []any{map[string]any{"smells": []any{"eager-test"}}},
+			},
+			wantErr: "has no path",
+		},
+		{
+			name:     "invalid clone length",
+			settings: map[string]any{"quality.smells.duplication.min-tokens": 0},
+			wantErr:  "must be positive",
+		},
+		{
+			name: "invalid severity",
+			settings: map[string]any{
+				"quality.smells.severity-overrides": map[string]any{"eager-test": "urgent"},
+			},
+			wantErr: "invalid severity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			for key, value := range tt.settings {
+				v.Set(key, value)
+			}
+
+			cfg, err := LoadConfig(v)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadConfig() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("LoadConfig() error = %v", err)
+			}
+
+			tt.check(t, cfg)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package smells
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/discovery"
+	"github.com/chambridge/ship-shape/internal/logger"
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+// suppressionDirective is the comment marker of an inline suppression.
+const suppressionDirective = "shipshape:ignore"
+
+// PathSuppression silences smells in files matching a glob, configured
+// under quality.smells.suppress.
+type PathSuppression struct {
+	// Path is a glob such as "legacy/**" or "*_integration_test.go"
+	Path string `mapstructure:"path"`
+
+	// Smells lists the suppressed smells; empty suppresses every smell
+	Smells []string `mapstructure:"smells"`
+
+	// Reason documents why the findings are accepted
+	Reason string `mapstructure:"reason"`
+}
+
+// Matches reports whether the suppression covers a finding.
+func (p PathSuppression) Matches(f types.Finding) bool {
+	if !discovery.MatchGlob(p.Path, f.Location.File) {
+		return false
+	}
+
+	if len(p.Smells) == 0 {
+		return true
+	}
+
+	for _, smell := range p.Smells {
+		if smell == f.CheckID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inlineSuppression is a shipshape:ignore comment.
+type inlineSuppression struct {
+	line   int
+	smells map[Smell]bool
+	reason string
+}
+
+// covers reports whether the comment suppresses a finding: the comment must
+// be on the line before the finding or within its line range.
+func (s inlineSuppression) covers(f types.Finding) bool {
+	if len(s.smells) > 0 && !s.smells[Smell(f.CheckID)] {
+		return false
+	}
+
+	end := max(f.Location.EndLine, f.Location.StartLine)
+
+	return s.line >= f.Location.StartLine-1 && s.line <= end
+}
+
+// parseInlineSuppressions finds comments of the form
+//
+//	// shipshape:ignore eager-test,lazy-test reason
+//	# shipshape:ignore mystery-guest reason
+//
+// A comment without smells, or with "all", suppresses every smell.
+// Unknown smell names are logged and ignored.
+func parseInlineSuppressions(relPath string, src []byte) []inlineSuppression {
+	if !bytes.Contains(src, []byte(suppressionDirective)) {
+		return nil
+	}
+
+	var suppressions []inlineSuppression
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+
+		idx := strings.Index(text, suppressionDirective)
+		if idx < 0 || !strings.ContainsAny(text[:idx], "/#*") {
+			continue
+		}
+
+		rest := text[idx+len(suppressionDirective):]
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			continue // Some other word, such as shipshape:ignored
+		}
+
+		fields := strings.Fields(strings.TrimSuffix(strings.TrimSpace(rest), "*/"))
+		s := inlineSuppression{line: line}
+
+		if len(fields) > 0 {
+			s.reason = strings.Join(fields[1:], " ")
+
+			if fields[0] != "all" {
+				s.smells = make(map[Smell]bool)
+
+				for _, name := range strings.Split(fields[0], ",") {
+					if _, ok := Catalog[Smell(name)]; ok {
+						s.smells[Smell(name)] = true
+					} else {
+						logger.Warn("Unknown smell in suppression comment",
+							"location", fmt.Sprintf("%s:%d", relPath, line), "smell", name)
+					}
+				}
+
+				if len(s.smells) == 0 {
+					continue
+				}
+			}
+		}
+
+		suppressions = append(suppressions, s)
+	}
+
+	return suppressions
+}
+
+// suppressed reports whether an inline or configured suppression covers a finding.
+func (e *Engine) suppressed(f types.Finding, inline []inlineSuppression) bool {
+	for _, s := range inline {
+		if s.covers(f) {
+			return true
+		}
+	}
+
+	for _, s := range e.config.Suppressions {
+		if s.Matches(f) {
+			return true
+		}
+	}
+
+	return false
+}
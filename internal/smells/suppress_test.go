@@ -0,0 +1,134 @@
+package smells
+
+import (
+	"testing"
+
+	"github.com/chambridge/ship-shape/pkg/types"
+)
+
+func TestParseInlineSuppressions(t *testing.T) {
+	src := `package app
+
+// shipshape:ignore eager-test,lazy-test exercises the whole checkout flow
+func TestCheckout(t *testing.T) {}
+
+func TestLegacy(t *testing.T) { // shipshape:ignore
+}
+
+# shipshape:ignore all generated fixtures
+/* shipshape:ignore mystery-guest reads the shared config */
+// shipshape:ignore flaky-ish unknown smells are ignored
+// shipshape:ignored is not a directive
+var s = "shipshape:ignore eager-test"
+`
+
+	got := parseInlineSuppressions("app/app_test.go", []byte(src))
+
+	want := []struct {
+		line   int
+		smells []Smell
+		reason string
+	}{
+		{line: 3, smells: []Smell{EagerTest, LazyTest}, reason: "exercises the whole checkout flow"},
+		{line: 6},
+		{line: 9, reason: "generated fixtures"},
+		{line: 10, smells: []Smell{MysteryGuest}, reason: "reads the shared config"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d suppressions, want %d: %+v", len(got), len(want), got)
+	}
+
+	for i, w := range want {
+		if got[i].line != w.line || got[i].reason != w.reason || len(got[i].smells) != len(w.smells) {
+			t.Errorf("suppression %d = %+v, want %+v", i, got[i], w)
+			continue
+		}
+
+		for _, smell := range w.smells {
+			if !got[i].smells[smell] {
+				t.Errorf("suppression %d does not cover %s", i, smell)
+			}
+		}
+	}
+}
+
+func TestSuppressionCoverage(t *testing.T) {
+	finding := types.Finding{
+		CheckID:  string(EagerTest),
+		Location: types.Location{File: "legacy/orders_test.go", StartLine: 10, EndLine: 20},
+	}
+
+	tests := []struct {
+		name   string
+		inline []inlineSuppression
+		paths  []PathSuppression
+		want   bool
+	}{
+		{name: "none"},
+		{name: "comment on the line before", inline: []inlineSuppression{{line: 9}}, want: true},
+		{name: "comment inside the range", inline: []inlineSuppression{{line: 15}}, want: true},
+		{name: "comment far above", inline: []inlineSuppression{{line: 5}}},
+		{name: "comment after the range", inline: []inlineSuppression{{line: 21}}},
+		{
+			name:   "comment for another smell",
+			inline: []inlineSuppression{{line: 9, smells: map[Smell]bool{LazyTest: true}}},
+		},
+		{name: "path glob", paths: []PathSuppression{{Path: "legacy/**"}}, want: true},
+		{
+			name:  "path glob for the smell",
+			paths: []PathSuppression{{Path: "legacy/**", Smells: []string{"eager-test"}}},
+			want:  true,
+		},
+		{name: "path glob for another smell", paths: []PathSuppression{{Path: "legacy/**", Smells: []string{"lazy-test"}}}},
+		{name: "other path", paths: []PathSuppression{{Path: "app/**"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Suppressions = tt.paths
+
+			if got := NewEngine(nil, cfg).suppressed(finding, tt.inline); got != tt.want {
+				t.Errorf("suppressed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineHonorsSuppressions(t *testing.T) {
+	src := `package app
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWaits(t *testing.T) {
+	time.Sleep(time.Second) // shipshape:ignore flakiness waits for the real backoff
+	_, _ = os.ReadFile("config.json")
+}
+`
+
+	findings, err := NewEngine(nil, nil).AnalyzeFile("app/app_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	if len(findings) != 1 || findings[0].CheckID != string(MysteryGuest) {
+		t.Fatalf("findings = %+v, want only mystery-guest", findings)
+	}
+
+	cfg := DefaultConfig()
+	cfg.Suppressions = []PathSuppression{{Path: "app/*_test.go", Smells: []string{"mystery-guest"}}}
+
+	findings, err = NewEngine(nil, cfg).AnalyzeFile("app/app_test.go", []byte(src))
+	if err != nil {
+		t.Fatalf("AnalyzeFile() error = %v", err)
+	}
+
+	if len(findings) != 0 {
+		t.Errorf("findings = %+v, want none", findings)
+	}
+}
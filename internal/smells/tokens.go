@@ -0,0 +1,68 @@
+package smells
+
+import (
+	"strings"
+
+	"github.com/chambridge/ship-shape/internal/lexer"
+)
+
+// splitTop splits tokens on a top-level punctuation token such as "==".
+func splitTop(toks []lexer.Token, sep string) [][]lexer.Token {
+	var (
+		parts   [][]lexer.Token
+		current []lexer.Token
+		depth   int
+	)
+
+	for _, tok := range toks {
+		if tok.Kind == lexer.Punct {
+			switch tok.Text {
+			case "(", "[", "{":
+				depth++
+			case ")", "]", "}":
+				depth--
+			case sep:
+				if depth == 0 {
+					parts = append(parts, current)
+					current = nil
+
+					continue
+				}
+			}
+		}
+
+		current = append(current, tok)
+	}
+
+	return append(parts, current)
+}
+
+// literalString returns the value of an argument consisting of a single
+// string literal. Template and f-strings with substitutions do not qualify.
+func literalString(arg []lexer.Token) (string, bool) {
+	if len(arg) != 1 || arg[0].Kind != lexer.String {
+		return "", false
+	}
+
+	text := arg[0].Text
+	if quote := strings.IndexAny(text, "\"'`"); quote < 0 || strings.ContainsAny(text[:quote], "fF") ||
+		(text[quote] == '`' && strings.Contains(text, "${")) {
+		return "", false
+	}
+
+	return lexer.Unquote(text), true
+}
+
+// sensitiveLiteral describes a string literal whose exact text is fragile, or returns "".
+func sensitiveLiteral(s string) string {
+	trimmed := strings.TrimSpace(s)
+
+	switch {
+	case strings.HasPrefix(trimmed, `{"`) || strings.HasPrefix(trimmed, `[{`):
+		return "against serialized JSON text"
+	case timestampPattern.MatchString(s):
+		return "against a string containing a timestamp"
+	default:
+		return ""
+	}
+}
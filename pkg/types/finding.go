@@ -0,0 +1,153 @@
+package types
+
+import "fmt"
+
+// Severity ranks how much a finding matters.
+type Severity string
+
+// Severity constants, from the least to the most severe.
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// severityRanks orders severities for comparison.
+var severityRanks = map[Severity]int{
+	SeverityInfo:     0,
+	SeverityLow:      1,
+	SeverityMedium:   2,
+	SeverityHigh:     3,
+	SeverityCritical: 4,
+}
+
+// ParseSeverity converts a configuration value into a Severity.
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(s)
+	if _, ok := severityRanks[sev]; !ok {
+		return "", fmt.Errorf("invalid severity %q (want info, low, medium, high or critical)", s)
+	}
+
+	return sev, nil
+}
+
+// Rank returns the position of the severity in the ordering info < low <
+// medium < high < critical. Unknown severities rank below info.
+func (s Severity) Rank() int {
+	if rank, ok := severityRanks[s]; ok {
+		return rank
+	}
+
+	return -1
+}
+
+// AtLeast reports whether s is as severe as or more severe than other.
+func (s Severity) AtLeast(other Severity) bool {
+	return s.Rank() >= other.Rank()
+}
+
+// FindingType categorizes findings by analysis area.
+type FindingType string
+
+// Finding type constants group findings by the dimension they affect.
+const (
+	FindingTypeCoverage        FindingType = "coverage"
+	FindingTypeQuality         FindingType = "quality"
+	FindingTypePerformance     FindingType = "performance"
+	FindingTypeMaintainability FindingType = "maintainability"
+	FindingTypeBestPractice    FindingType = "best_practice"
+)
+
+// EffortLevel estimates the work needed to address a finding.
+type EffortLevel string
+
+// Effort level constants.
+const (
+	EffortMinimal EffortLevel = "minimal" // <15 min
+	EffortLow     EffortLevel = "low"     // 15-60 min
+	EffortMedium  EffortLevel = "medium"  // 1-4 hours
+	EffortHigh    EffortLevel = "high"    // 4-8 hours
+)
+
+// Location identifies a range of lines in a repository file.
+type Location struct {
+	// File is the path relative to the repository root
+	File string `json:"file"`
+
+	// StartLine is the 1-based first line of the range
+	StartLine int `json:"start_line"`
+
+	// EndLine is the 1-based last line of the range
+	EndLine int `json:"end_line"`
+}
+
+// String formats the location as file:start-end. Locations without a line,
+// such as whole files and directories, are formatted as the path alone,
+// with "." for the repository root.
+func (l Location) String() string {
+	file := l.File
+	if file == "" {
+		file = "."
+	}
+
+	if l.StartLine == 0 {
+		return file
+	}
+
+	if l.EndLine > l.StartLine {
+		return fmt.Sprintf("%s:%d-%d", file, l.StartLine, l.EndLine)
+	}
+
+	return fmt.Sprintf("%s:%d", file, l.StartLine)
+}
+
+// Remediation provides actionable guidance for fixing a finding.
+type Remediation struct {
+	// Summary is a one-line description of the fix
+	Summary string `json:"summary"`
+
+	// Steps are the ordered remediation steps
+	Steps []string `json:"steps,omitempty"`
+
+	// Effort estimates the work involved
+	Effort EffortLevel `json:"effort,omitempty"`
+}
+
+// Finding is a single issue reported by an analyzer.
+type Finding struct {
+	// ID identifies the finding within a report
+	ID string `json:"id"`
+
+	// Fingerprint identifies the finding across runs, independently of line
+	// numbers, so baselines survive unrelated edits to the file
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// CheckID is the analyzer check that produced the finding (e.g., "assertion-roulette")
+	CheckID string `json:"check_id"`
+
+	// Type categorizes the finding
+	Type FindingType `json:"type"`
+
+	// Severity ranks the finding
+	Severity Severity `json:"severity"`
+
+	// Title is a short human-readable name of the check
+	Title string `json:"title"`
+
+	// Description explains what was found at this location
+	Description string `json:"description"`
+
+	// Rationale explains why the finding matters
+	Rationale string `json:"rationale,omitempty"`
+
+	// Location is where the finding was detected
+	Location Location `json:"location"`
+
+	// Test is the ID of the test the finding belongs to, if any
+	Test string `json:"test,omitempty"`
+
+	// Remediation describes how to fix the finding
+	Remediation *Remediation `json:"remediation,omitempty"`
+}
@@ -0,0 +1,51 @@
+package types
+
+import "testing"
+
+func TestParseSeverity(t *testing.T) {
+	for _, s := range []string{"info", "low", "medium", "high", "critical"} {
+		if _, err := ParseSeverity(s); err != nil {
+			t.Errorf("ParseSeverity(%q) error = %v", s, err)
+		}
+	}
+
+	if _, err := ParseSeverity("urgent"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
+func TestSeverity_AtLeast(t *testing.T) {
+	tests := []struct {
+		s, other Severity
+		want     bool
+	}{
+		{SeverityHigh, SeverityMedium, true},
+		{SeverityMedium, SeverityMedium, true},
+		{SeverityLow, SeverityHigh, false},
+		{Severity("bogus"), SeverityInfo, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.s.AtLeast(tt.other); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %v, want %v", tt.s, tt.other, got, tt.want)
+		}
+	}
+}
+
+func TestLocation_String(t *testing.T) {
+	if got := (Location{File: "a_test.go", StartLine: 3, EndLine: 9}).String(); got != "a_test.go:3-9" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if got := (Location{File: "a_test.go", StartLine: 3, EndLine: 3}).String(); got != "a_test.go:3" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if got := (Location{File: "auth"}).String(); got != "auth" {
+		t.Errorf("String() = %q, want the path without a line", got)
+	}
+
+	if got := (Location{}).String(); got != "." {
+		t.Errorf("String() = %q, want the repository root", got)
+	}
+}
@@ -0,0 +1,274 @@
+package types
+
+// TestKind categorizes an entry in the test inventory.
+type TestKind string
+
+// Test kind constants describe the role a discovered entry plays in a suite.
+const (
+	TestKindSuite         TestKind = "suite"         // Grouping construct (describe, test class, @Nested)
+	TestKindTest          TestKind = "test"          // Single test case
+	TestKindParameterized TestKind = "parameterized" // Test executed once per parameter case
+	TestKindBenchmark     TestKind = "benchmark"     // Benchmark function
+	TestKindFuzz          TestKind = "fuzz"          // Fuzz target
+	TestKindExample       TestKind = "example"       // Executable example
+)
+
+// HookKind identifies a lifecycle hook in a language-neutral way.
+type HookKind string
+
+// Hook kind constants map framework-specific lifecycle hooks onto common names.
+const (
+	HookBeforeAll  HookKind = "before-all"  // beforeAll, @BeforeAll, @BeforeClass, setUpClass
+	HookBeforeEach HookKind = "before-each" // beforeEach, @BeforeEach, @BeforeMethod, setUp
+	HookAfterEach  HookKind = "after-each"  // afterEach, @AfterEach, @AfterMethod, tearDown
+	HookAfterAll   HookKind = "after-all"   // afterAll, @AfterAll, @AfterClass, tearDownClass
+)
+
+// AsyncStyle describes how an asynchronous test signals completion.
+type AsyncStyle string
+
+// Async style constants, from the most to the least idiomatic.
+const (
+	AsyncAwait    AsyncStyle = "async-await" // async callback that awaits promises
+	AsyncPromise  AsyncStyle = "promise"     // returned promise chain (.then, .resolves)
+	AsyncCallback AsyncStyle = "callback"    // done callback parameter
+)
+
+// WeakAssertionKind describes why an assertion is weak.
+type WeakAssertionKind string
+
+// Weak assertion kind constants.
+const (
+	WeakTruthiness        WeakAssertionKind = "truthiness"         // assert x, toBeTruthy(): only checks truthiness
+	WeakBooleanComparison WeakAssertionKind = "boolean-comparison" // assertTrue(a == b): hides both values in a boolean
+	WeakMissingMessage    WeakAssertionKind = "missing-message"    // t.Fail(), t.Error(): fails without saying why
+)
+
+// WeakAssertion is an assertion whose failure does not show what was
+// expected and what was received.
+type WeakAssertion struct {
+	// Kind describes why the assertion is weak
+	Kind WeakAssertionKind `json:"kind"`
+
+	// Assertion is the assertion as written (e.g., "assertTrue", "toBeTruthy", "t.Fail")
+	Assertion string `json:"assertion"`
+
+	// Suggestion is the specific assertion or matcher to use instead
+	Suggestion string `json:"suggestion"`
+
+	// Line is the 1-based line of the assertion
+	Line int `json:"line"`
+}
+
+// TestCase is a single node of the test tree. Suites contain children;
+// tests and parameterized tests are leaves.
+type TestCase struct {
+	// ID uniquely identifies the entry as "<file>::<suite>::<name>"
+	ID string `json:"id"`
+
+	// Name is the display name of the test or suite
+	Name string `json:"name"`
+
+	// Kind categorizes the entry (suite, test, parameterized, ...)
+	Kind TestKind `json:"kind"`
+
+	// File is the path of the declaring file relative to the repository root
+	File string `json:"file"`
+
+	// Line is the 1-based line where the entry is declared
+	Line int `json:"line"`
+
+	// EndLine is the 1-based line where the entry's body ends
+	EndLine int `json:"end_line,omitempty"`
+
+	// Skipped marks entries disabled via .skip, @Disabled, etc.
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Focused marks entries that restrict the run to themselves (.only, fit, fdescribe)
+	Focused bool `json:"focused,omitempty"`
+
+	// Todo marks placeholder tests without an implementation
+	Todo bool `json:"todo,omitempty"`
+
+	// ParameterCases is the number of statically known parameter cases (0 if unknown)
+	ParameterCases int `json:"parameter_cases,omitempty"`
+
+	// ParameterSources lists where parameters come from (e.g., "each", "@ValueSource")
+	ParameterSources []string `json:"parameter_sources,omitempty"`
+
+	// Tags are groups, categories or markers attached to the entry
+	Tags []string `json:"tags,omitempty"`
+
+	// Assertions is the number of assertion calls found in the entry's body
+	Assertions int `json:"assertions,omitempty"`
+
+	// WeakAssertions are the assertions of the entry's body whose failures
+	// do not show the compared values
+	WeakAssertions []WeakAssertion `json:"weak_assertions,omitempty"`
+
+	// Snapshots is the number of snapshot assertions found in the entry's body
+	Snapshots int `json:"snapshots,omitempty"`
+
+	// Parallel marks tests that run in parallel with other tests (t.Parallel)
+	Parallel bool `json:"parallel,omitempty"`
+
+	// Fixtures are the fixtures the test requests by name (pytest arguments)
+	Fixtures []string `json:"fixtures,omitempty"`
+
+	// Async is how an asynchronous test signals completion, empty for synchronous tests
+	Async AsyncStyle `json:"async,omitempty"`
+
+	// Hooks are lifecycle hooks declared directly inside this suite
+	Hooks []TestHook `json:"hooks,omitempty"`
+
+	// Children are the nested suites and tests of a suite
+	Children []TestCase `json:"children,omitempty"`
+}
+
+// TestHook is a lifecycle hook declared in a test file or suite.
+type TestHook struct {
+	// Kind is the language-neutral hook kind
+	Kind HookKind `json:"kind"`
+
+	// Name is the framework-specific spelling (e.g., "beforeEach", "@BeforeAll")
+	Name string `json:"name"`
+
+	// Line is the 1-based line where the hook is declared
+	Line int `json:"line"`
+}
+
+// MockUsage records a module or object mock declared in a test file.
+type MockUsage struct {
+	// API is the mocking call used (e.g., "jest.mock", "vi.mock", "@Mock")
+	API string `json:"api"`
+
+	// Target is the mocked module or type, when statically known
+	Target string `json:"target,omitempty"`
+
+	// Line is the 1-based line of the mock declaration
+	Line int `json:"line"`
+}
+
+// TestFile is the inventory of a single test source file.
+type TestFile struct {
+	// Path is the file path relative to the repository root
+	Path string `json:"path"`
+
+	// Language is the language of the file
+	Language Language `json:"language"`
+
+	// Framework is the test framework the file is written for
+	Framework string `json:"framework"`
+
+	// Tests are the top-level suites and tests declared in the file
+	Tests []TestCase `json:"tests,omitempty"`
+
+	// Hooks are lifecycle hooks declared at file scope
+	Hooks []TestHook `json:"hooks,omitempty"`
+
+	// Mocks are module or object mocks declared in the file
+	Mocks []MockUsage `json:"mocks,omitempty"`
+
+	// AssertionStyles counts assertion calls per assertion library
+	AssertionStyles map[string]int `json:"assertion_styles,omitempty"`
+}
+
+// TestInventory is the set of test files discovered in a repository.
+type TestInventory struct {
+	// Files are the parsed test files, sorted by path
+	Files []TestFile `json:"files"`
+}
+
+// IsLeaf reports whether the entry is an executable test rather than a suite.
+func (tc *TestCase) IsLeaf() bool {
+	return tc.Kind != TestKindSuite
+}
+
+// Walk calls fn for every entry of the file's test tree in declaration order.
+func (f *TestFile) Walk(fn func(tc *TestCase)) {
+	for i := range f.Tests {
+		walkTestCase(&f.Tests[i], fn)
+	}
+}
+
+func walkTestCase(tc *TestCase, fn func(tc *TestCase)) {
+	fn(tc)
+
+	for i := range tc.Children {
+		walkTestCase(&tc.Children[i], fn)
+	}
+}
+
+// Leaves returns all executable tests of the file, excluding suites.
+func (f *TestFile) Leaves() []*TestCase {
+	var leaves []*TestCase
+
+	f.Walk(func(tc *TestCase) {
+		if tc.IsLeaf() {
+			leaves = append(leaves, tc)
+		}
+	})
+
+	return leaves
+}
+
+// TestCount returns the number of executable tests across all files.
+func (inv *TestInventory) TestCount() int {
+	count := 0
+	for i := range inv.Files {
+		count += len(inv.Files[i].Leaves())
+	}
+
+	return count
+}
+
+// Focused returns every focused entry, including focused suites.
+func (inv *TestInventory) Focused() []*TestCase {
+	var focused []*TestCase
+
+	for i := range inv.Files {
+		inv.Files[i].Walk(func(tc *TestCase) {
+			if tc.Focused {
+				focused = append(focused, tc)
+			}
+		})
+	}
+
+	return focused
+}
+
+// EffectiveLeaves returns copies of the file's executable tests with the
+// skipped, focused, todo and tag state inherited from enclosing suites.
+// Parameter cases multiply: a test with 2 cases in a suite run for 3
+// parameter sets has 6.
+func (f *TestFile) EffectiveLeaves() []TestCase {
+	var leaves []TestCase
+
+	for i := range f.Tests {
+		collectEffective(f.Tests[i], TestCase{}, &leaves)
+	}
+
+	return leaves
+}
+
+func collectEffective(tc, inherited TestCase, leaves *[]TestCase) {
+	tc.Skipped = tc.Skipped || inherited.Skipped
+	tc.Focused = tc.Focused || inherited.Focused
+	tc.Todo = tc.Todo || inherited.Todo
+
+	if inherited.ParameterCases > 0 {
+		tc.ParameterCases = max(tc.ParameterCases, 1) * inherited.ParameterCases
+	}
+
+	if len(inherited.Tags) > 0 {
+		tc.Tags = append(append([]string(nil), inherited.Tags...), tc.Tags...)
+	}
+
+	if tc.IsLeaf() {
+		*leaves = append(*leaves, tc)
+	}
+
+	for _, child := range tc.Children {
+		collectEffective(child, tc, leaves)
+	}
+}
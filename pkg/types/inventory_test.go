@@ -0,0 +1,142 @@
+package types
+
+import (
+	"reflect"
+	"testing"
+)
+
+func sampleInventory() TestInventory {
+	return TestInventory{
+		Files: []TestFile{
+			{
+				Path: "a.test.js",
+				Tests: []TestCase{
+					{
+						Name: "suite",
+						Kind: TestKindSuite,
+						Children: []TestCase{
+							{Name: "one", Kind: TestKindTest, Focused: true},
+							{Name: "two", Kind: TestKindParameterized},
+						},
+					},
+					{Name: "three", Kind: TestKindTest},
+				},
+			},
+			{
+				Path:  "b.test.js",
+				Tests: []TestCase{{Name: "focused suite", Kind: TestKindSuite, Focused: true}},
+			},
+		},
+	}
+}
+
+func TestTestFile_Walk(t *testing.T) {
+	inv := sampleInventory()
+
+	var names []string
+
+	inv.Files[0].Walk(func(tc *TestCase) {
+		names = append(names, tc.Name)
+	})
+
+	want := []string{"suite", "one", "two", "three"}
+	if len(names) != len(want) {
+		t.Fatalf("Walk() visited %v, want %v", names, want)
+	}
+
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Walk()[%d] = %q, want %q", i, names[i], want[i])
+		}
+	}
+}
+
+func TestTestInventory_TestCount(t *testing.T) {
+	inv := sampleInventory()
+
+	if got := inv.TestCount(); got != 3 {
+		t.Errorf("TestCount() = %d, want 3", got)
+	}
+}
+
+func TestTestInventory_Focused(t *testing.T) {
+	inv := sampleInventory()
+
+	focused := inv.Focused()
+	if len(focused) != 2 {
+		t.Fatalf("Focused() = %d entries, want 2", len(focused))
+	}
+
+	if focused[0].Name != "one" || focused[1].Name != "focused suite" {
+		t.Errorf("Focused() = %q, %q", focused[0].Name, focused[1].Name)
+	}
+}
+
+func TestTestFile_EffectiveLeaves(t *testing.T) {
+	file := TestFile{
+		Tests: []TestCase{
+			{
+				Name:    "suite",
+				Kind:    TestKindSuite,
+				Skipped: true,
+				Tags:    []string{"slow"},
+				Children: []TestCase{
+					{Name: "a", Kind: TestKindTest, Tags: []string{"db"}},
+				},
+			},
+		},
+	}
+
+	leaves := file.EffectiveLeaves()
+	if len(leaves) != 1 {
+		t.Fatalf("EffectiveLeaves() = %d entries, want 1", len(leaves))
+	}
+
+	if !leaves[0].Skipped {
+		t.Error("leaf should inherit skipped state from its suite")
+	}
+
+	if len(leaves[0].Tags) != 2 || leaves[0].Tags[0] != "slow" {
+		t.Errorf("leaf tags = %v, want [slow db]", leaves[0].Tags)
+	}
+
+	// The original tree must not be modified
+	if file.Tests[0].Children[0].Skipped {
+		t.Error("EffectiveLeaves() modified the original test case")
+	}
+}
+
+func TestTestFile_EffectiveLeavesParameterCases(t *testing.T) {
+	file := TestFile{
+		Tests: []TestCase{
+			{
+				Name:           "currency %s",
+				Kind:           TestKindSuite,
+				ParameterCases: 3,
+				Children: []TestCase{
+					{Name: "formats", Kind: TestKindTest},
+					{Name: "rounds %d", Kind: TestKindParameterized, ParameterCases: 2},
+					{
+						Name:           "locale %s",
+						Kind:           TestKindSuite,
+						ParameterCases: 2,
+						Children:       []TestCase{{Name: "parses", Kind: TestKindTest}},
+					},
+				},
+			},
+		},
+	}
+
+	var got []int
+	for _, leaf := range file.EffectiveLeaves() {
+		got = append(got, leaf.ParameterCases)
+	}
+
+	if want := []int{3, 6, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("leaf cases = %v, want %v", got, want)
+	}
+
+	if file.Tests[0].Children[0].ParameterCases != 0 {
+		t.Error("EffectiveLeaves() modified the original test case")
+	}
+}
@@ -0,0 +1,85 @@
+package types
+
+// LinkReason explains why a test file was linked to a source file.
+type LinkReason string
+
+// Link reason constants, from the strongest to the weakest signal.
+const (
+	LinkConvention LinkReason = "convention" // Naming convention (foo_test.go -> foo.go, FooTest -> Foo)
+	LinkPackage    LinkReason = "package"    // Same Go package without a matching file name
+	LinkImport     LinkReason = "import"     // The test imports the source file
+)
+
+// TestLink connects a test file to a source file it exercises.
+type TestLink struct {
+	// Test is the test file path relative to the repository root
+	Test string `json:"test"`
+
+	// Source is the source file path relative to the repository root
+	Source string `json:"source"`
+
+	// Reason explains how the link was established
+	Reason LinkReason `json:"reason"`
+}
+
+// OrphanTest is a test file that could not be linked to any source file.
+type OrphanTest struct {
+	// Path is the test file path relative to the repository root
+	Path string `json:"path"`
+
+	// Subject is the source file the naming convention expected (e.g., "foo.py")
+	Subject string `json:"subject,omitempty"`
+}
+
+// TestMap links the test files of a repository to its source files.
+type TestMap struct {
+	// SourceFiles is the number of production source files considered
+	SourceFiles int `json:"source_files"`
+
+	// TestFiles is the number of test files considered
+	TestFiles int `json:"test_files"`
+
+	// Links are the test-to-source links, sorted by test then source
+	Links []TestLink `json:"links"`
+
+	// Untested are the source files no test is linked to, sorted by path
+	Untested []string `json:"untested"`
+
+	// Orphans are the test files whose subject could not be found, sorted by path
+	Orphans []OrphanTest `json:"orphans"`
+}
+
+// TestsFor returns the test files linked to a source file.
+func (m *TestMap) TestsFor(source string) []string {
+	var tests []string
+
+	for _, link := range m.Links {
+		if link.Source == source {
+			tests = append(tests, link.Test)
+		}
+	}
+
+	return tests
+}
+
+// SourcesFor returns the source files linked to a test file.
+func (m *TestMap) SourcesFor(test string) []string {
+	var sources []string
+
+	for _, link := range m.Links {
+		if link.Test == test {
+			sources = append(sources, link.Source)
+		}
+	}
+
+	return sources
+}
+
+// TestedPercentage returns the share of source files with at least one linked test.
+func (m *TestMap) TestedPercentage() float64 {
+	if m.SourceFiles == 0 {
+		return 0
+	}
+
+	return float64(m.SourceFiles-len(m.Untested)) / float64(m.SourceFiles) * 100
+}
@@ -0,0 +1,31 @@
+package types
+
+import "testing"
+
+func TestTestMap_Lookups(t *testing.T) {
+	m := &TestMap{
+		SourceFiles: 4,
+		Links: []TestLink{
+			{Test: "a_test.go", Source: "a.go", Reason: LinkConvention},
+			{Test: "a_test.go", Source: "b.go", Reason: LinkImport},
+			{Test: "c_test.go", Source: "a.go", Reason: LinkImport},
+		},
+		Untested: []string{"d.go"},
+	}
+
+	if got := m.TestsFor("a.go"); len(got) != 2 || got[0] != "a_test.go" || got[1] != "c_test.go" {
+		t.Errorf("TestsFor(a.go) = %v", got)
+	}
+
+	if got := m.SourcesFor("a_test.go"); len(got) != 2 || got[1] != "b.go" {
+		t.Errorf("SourcesFor(a_test.go) = %v", got)
+	}
+
+	if got := m.TestedPercentage(); got != 75 {
+		t.Errorf("TestedPercentage() = %v, want 75", got)
+	}
+
+	if got := (&TestMap{}).TestedPercentage(); got != 0 {
+		t.Errorf("empty TestedPercentage() = %v, want 0", got)
+	}
+}
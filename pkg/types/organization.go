@@ -0,0 +1,86 @@
+package types
+
+// MinTestToCodeRatio is the test-to-code ratio required by the
+// test-organization attribute.
+const MinTestToCodeRatio = 0.5
+
+// TestLayout describes where test files live relative to the code they test.
+type TestLayout string
+
+// Test layout constants cover the directory conventions of supported languages.
+const (
+	TestLayoutColocated     TestLayout = "colocated"      // Next to the source (foo_test.go, foo.test.ts)
+	TestLayoutTestDirectory TestLayout = "test-directory" // Dedicated tests/, test/ or spec/ directory
+	TestLayoutTestsFolder   TestLayout = "__tests__"      // Jest __tests__ folders
+	TestLayoutSourceRoot    TestLayout = "source-root"    // Maven/Gradle src/test source root
+)
+
+// TestOrganization summarizes how tests are organized across a repository.
+type TestOrganization struct {
+	// TestFiles is the number of test files, including test helpers in test directories
+	TestFiles int `json:"test_files"`
+
+	// SourceFiles is the number of production source files
+	SourceFiles int `json:"source_files"`
+
+	// TestLines is the number of non-blank lines in test files
+	TestLines int `json:"test_lines"`
+
+	// SourceLines is the number of non-blank lines in production source files
+	SourceLines int `json:"source_lines"`
+
+	// Ratio is TestLines divided by SourceLines (0 when there is no source)
+	Ratio float64 `json:"ratio"`
+
+	// Languages breaks the metrics down per language, sorted by test file count
+	Languages []LanguageOrganization `json:"languages,omitempty"`
+
+	// Warnings describe mixed or inconsistent test conventions
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// LanguageOrganization contains the test organization metrics of one language.
+type LanguageOrganization struct {
+	// Language is the language the metrics apply to
+	Language Language `json:"language"`
+
+	// TestFiles is the number of test files in this language
+	TestFiles int `json:"test_files"`
+
+	// SourceFiles is the number of production source files in this language
+	SourceFiles int `json:"source_files"`
+
+	// TestLines is the number of non-blank lines in test files
+	TestLines int `json:"test_lines"`
+
+	// SourceLines is the number of non-blank lines in production source files
+	SourceLines int `json:"source_lines"`
+
+	// Ratio is TestLines divided by SourceLines (0 when there is no source)
+	Ratio float64 `json:"ratio"`
+
+	// Layouts counts test files per directory convention
+	Layouts map[TestLayout]int `json:"layouts,omitempty"`
+
+	// Naming counts test files per naming style (e.g., "test_*.py", "*.spec")
+	Naming map[string]int `json:"naming,omitempty"`
+}
+
+// MeetsRatio reports whether the test-to-code ratio reaches MinTestToCodeRatio.
+func (o *TestOrganization) MeetsRatio() bool {
+	return o.Ratio >= MinTestToCodeRatio
+}
+
+// DominantLayout returns the most used test layout of the language, or "" if
+// the language has no tests. Ties resolve to the alphabetically first layout.
+func (l *LanguageOrganization) DominantLayout() TestLayout {
+	var best TestLayout
+
+	for layout, count := range l.Layouts {
+		if best == "" || count > l.Layouts[best] || (count == l.Layouts[best] && layout < best) {
+			best = layout
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,40 @@
+package types
+
+import "testing"
+
+func TestTestOrganization_MeetsRatio(t *testing.T) {
+	tests := []struct {
+		ratio float64
+		want  bool
+	}{
+		{0, false},
+		{0.49, false},
+		{0.5, true},
+		{1.2, true},
+	}
+
+	for _, tt := range tests {
+		org := &TestOrganization{Ratio: tt.ratio}
+		if got := org.MeetsRatio(); got != tt.want {
+			t.Errorf("MeetsRatio() with ratio %v = %v, want %v", tt.ratio, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageOrganization_DominantLayout(t *testing.T) {
+	lang := &LanguageOrganization{
+		Layouts: map[TestLayout]int{
+			TestLayoutColocated:     3,
+			TestLayoutTestDirectory: 3,
+			TestLayoutTestsFolder:   1,
+		},
+	}
+
+	if got := lang.DominantLayout(); got != TestLayoutColocated {
+		t.Errorf("DominantLayout() = %q, want %q", got, TestLayoutColocated)
+	}
+
+	if got := (&LanguageOrganization{}).DominantLayout(); got != "" {
+		t.Errorf("DominantLayout() without tests = %q, want empty", got)
+	}
+}
@@ -47,6 +47,9 @@ type Repository struct {
 
 	// ExcludedPaths are the patterns that were excluded during discovery
 	ExcludedPaths []string `json:"excluded_paths"`
+
+	// TestOrganization contains test-to-code ratio and test layout metrics
+	TestOrganization *TestOrganization `json:"test_organization,omitempty"`
 }
 
 // Framework represents a detected framework or tool in the repository.
@@ -107,6 +110,7 @@ const (
 	WorkspaceTypeMaven  WorkspaceType = "maven"  // Maven multi-module
 	WorkspaceTypeGradle WorkspaceType = "gradle" // Gradle multi-project
 	WorkspaceTypeLerna  WorkspaceType = "lerna"  // Lerna monorepo
+	WorkspaceTypePython WorkspaceType = "python" // Python project (pyproject.toml, setup.py)
 )
 
 // PrimaryLanguage returns the primary language (highest percentage) in the repository.
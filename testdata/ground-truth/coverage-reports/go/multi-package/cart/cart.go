@@ -0,0 +1,28 @@
+package cart
+
+import "example.com/shop/price"
+
+// Cart holds item prices in cents.
+type Cart struct {
+	items []int
+}
+
+// Add adds an item to the cart.
+func (c *Cart) Add(cents int) {
+	c.items = append(c.items, cents)
+}
+
+// Total returns the discounted total of the cart.
+func (c *Cart) Total(percent int) (int, error) {
+	sum := 0
+	for _, item := range c.items {
+		sum += item
+	}
+
+	return price.Discount(sum, percent)
+}
+
+// Empty reports whether the cart has no items.
+func (c *Cart) Empty() bool {
+	return len(c.items) == 0
+}
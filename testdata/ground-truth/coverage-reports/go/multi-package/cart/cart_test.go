@@ -0,0 +1,13 @@
+package cart
+
+import "testing"
+
+func TestTotal(t *testing.T) {
+	var c Cart
+	c.Add(500)
+	c.Add(500)
+
+	if got, err := c.Total(100); err != nil || got != 0 {
+		t.Errorf("Total(100) = %d, %v, want 0", got, err)
+	}
+}
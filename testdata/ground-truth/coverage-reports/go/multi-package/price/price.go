@@ -0,0 +1,46 @@
+package price
+
+import "errors"
+
+// ErrNegative is returned for negative amounts.
+var ErrNegative = errors.New("negative amount")
+
+// Discount applies a percentage discount to an amount in cents.
+func Discount(cents, percent int) (int, error) {
+	if cents < 0 {
+		return 0, ErrNegative
+	}
+
+	if percent <= 0 {
+		return cents, nil
+	}
+
+	if percent >= 100 {
+		return 0, nil
+	}
+
+	return cents - cents*percent/100, nil
+}
+
+// Format renders an amount in cents as dollars.
+func Format(cents int) string {
+	if cents < 0 {
+		return "-"
+	}
+
+	return "$" + itoa(cents/100)
+}
+
+func itoa(n int) string {
+	if n == 0 {
+		return "0"
+	}
+
+	var digits []byte
+	for n > 0 {
+		digits = append([]byte{byte('0' + n%10)}, digits...)
+		n /= 10
+	}
+
+	return string(digits)
+}
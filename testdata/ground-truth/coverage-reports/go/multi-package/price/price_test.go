@@ -0,0 +1,13 @@
+package price
+
+import "testing"
+
+func TestDiscount(t *testing.T) {
+	if got, _ := Discount(1000, 10); got != 900 {
+		t.Errorf("Discount(1000, 10) = %d, want 900", got)
+	}
+
+	if _, err := Discount(-1, 10); err != ErrNegative {
+		t.Errorf("Discount(-1, 10) error = %v, want ErrNegative", err)
+	}
+}
@@ -0,0 +1,15 @@
+package com.example.cart;
+
+import com.example.pricing.Discount;
+
+public class Cart {
+    private int total;
+
+    public void add(int price) {
+        total += price;
+    }
+
+    public int checkout(int percent) {
+        return Discount.apply(total, percent);
+    }
+}
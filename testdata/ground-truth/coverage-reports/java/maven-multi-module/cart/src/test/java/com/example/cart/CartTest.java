@@ -0,0 +1,14 @@
+package com.example.cart;
+
+import static org.junit.jupiter.api.Assertions.assertEquals;
+
+import org.junit.jupiter.api.Test;
+
+class CartTest {
+    @Test
+    void checkoutAppliesTheDiscount() {
+        Cart cart = new Cart();
+        cart.add(200);
+        assertEquals(180, cart.checkout(10));
+    }
+}
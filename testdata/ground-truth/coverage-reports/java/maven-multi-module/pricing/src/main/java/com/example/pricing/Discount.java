@@ -0,0 +1,13 @@
+package com.example.pricing;
+
+public final class Discount {
+    private Discount() {
+    }
+
+    public static int apply(int total, int percent) {
+        if (percent <= 0) {
+            return total;
+        }
+        return total - total * percent / 100;
+    }
+}
@@ -0,0 +1,12 @@
+function discount(total, code) {
+  if (code === "HALF") {
+    return total / 2;
+  }
+  return total;
+}
+
+function label(code) {
+  return code ? `code ${code}` : "none";
+}
+
+module.exports = { discount, label };
@@ -0,0 +1,9 @@
+const { discount } = require("../src/discount");
+
+test("halves the total with the HALF code", () => {
+  expect(discount(10, "HALF")).toBe(5);
+});
+
+test("keeps the total without a code", () => {
+  expect(discount(10, null)).toBe(10);
+});
@@ -0,0 +1,12 @@
+function currency(value) {
+  if (value < 0) {
+    return `-$${Math.abs(value).toFixed(2)}`;
+  }
+  return `$${value.toFixed(2)}`;
+}
+
+function percent(value) {
+  return `${Math.round(value * 100)}%`;
+}
+
+module.exports = { currency, percent };
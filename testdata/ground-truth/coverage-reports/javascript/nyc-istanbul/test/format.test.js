@@ -0,0 +1,12 @@
+const assert = require("assert");
+const { currency, percent } = require("../src");
+
+describe("format", () => {
+  it("formats positive amounts", () => {
+    assert.strictEqual(currency(5), "$5.00");
+  });
+
+  it("formats ratios as percentages", () => {
+    assert.strictEqual(percent(0.5), "50%");
+  });
+});
@@ -0,0 +1,3 @@
+def summary(stock):
+    lines = [f"{k}={v}" for k, v in sorted(stock.items.items())]
+    return ", ".join(lines)
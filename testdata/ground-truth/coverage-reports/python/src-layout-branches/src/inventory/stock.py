@@ -0,0 +1,18 @@
+"""Stock levels."""
+
+
+class Stock:
+    def __init__(self):
+        self.items = {}
+
+    def add(self, name, qty):
+        if qty <= 0:
+            raise ValueError("qty must be positive")
+        self.items[name] = self.items.get(name, 0) + qty
+
+    def remove(self, name, qty):
+        if self.items.get(name, 0) < qty:
+            raise KeyError(name)
+        self.items[name] -= qty
+        if self.items[name] == 0:
+            del self.items[name]
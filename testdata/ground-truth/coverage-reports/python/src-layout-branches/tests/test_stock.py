@@ -0,0 +1,8 @@
+from inventory.stock import Stock
+
+
+def test_add_accumulates():
+    stock = Stock()
+    stock.add("apple", 2)
+    stock.add("apple", 3)
+    assert stock.items == {"apple": 5}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"os"
+	"strconv"
+)
+
+// Cache is a map of integers with a bounded size.
+type Cache struct {
+	items map[string]int
+	size  int
+}
+
+// New creates a cache sized by CACHE_SIZE.
+func New() *Cache {
+	size, err := strconv.Atoi(os.Getenv("CACHE_SIZE"))
+	if err != nil {
+		size = 100
+	}
+
+	return &Cache{items: make(map[string]int), size: size}
+}
+
+// Set stores a value.
+func (c *Cache) Set(key string, value int) { c.items[key] = value }
+
+// Get returns a stored value.
+func (c *Cache) Get(key string) (int, bool) {
+	v, ok := c.items[key]
+	return v, ok
+}
+
+// Delete removes a value.
+func (c *Cache) Delete(key string) { delete(c.items, key) }
+
+// Size returns the maximum number of items.
+func (c *Cache) Size() int { return c.size }
@@ -0,0 +1,41 @@
+package cache
+
+import "testing"
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+
+	c := New()
+	c.Set("a", 1)
+
+	if got, ok := c.Get("a"); !ok || got != 1 {
+		t.Errorf("Get(a) = %d, %v, want 1, true", got, ok)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct{ name, key string }{{"present", "a"}, {"absent", "b"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := New()
+			c.Set("a", 1)
+			c.Delete(tt.key)
+
+			if _, ok := c.Get(tt.key); ok {
+				t.Errorf("Get(%s) found a deleted key", tt.key)
+			}
+		})
+	}
+}
+
+func TestDefaultSize(t *testing.T) {
+	t.Setenv("CACHE_SIZE", "10")
+
+	if got := New().Size(); got != 10 {
+		t.Errorf("Size() = %d, want 10", got)
+	}
+}
@@ -0,0 +1,44 @@
+package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b int
+		want int
+	}{
+		{"positive numbers", 2, 3, 5},
+		{"negative numbers", -1, -1, -2},
+		{"zero", 0, 5, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Add(tt.a, tt.b); got != tt.want {
+				t.Errorf("Add(%d, %d) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAbs(t *testing.T) {
+	cases := map[string]struct{ in, want int }{
+		"negative": {-3, 3},
+		"positive": {4, 4},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := Abs(tc.in); got != tc.want {
+				t.Errorf("Abs(%d) = %d, want %d", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAbsOfZero(t *testing.T) {
+	if got := Abs(0); got != 0 {
+		t.Errorf("Abs(0) = %d, want 0", got)
+	}
+}
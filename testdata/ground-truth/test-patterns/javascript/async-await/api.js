@@ -0,0 +1,15 @@
+async function fetchUser(id) {
+  if (typeof id !== 'number') {
+    throw new Error('id must be a number');
+  }
+  if (id < 0) {
+    throw new Error('not found');
+  }
+  return { id };
+}
+
+async function saveUser(user) {
+  return Boolean(user.id);
+}
+
+module.exports = { fetchUser, saveUser };
@@ -0,0 +1,26 @@
+const { fetchUser, saveUser } = require('./api');
+
+describe('api', () => {
+  it('fetches a user', async () => {
+    const user = await fetchUser(1);
+    expect(user.id).toBe(1);
+  });
+
+  it('rejects unknown users', async () => {
+    await expect(fetchUser(-1)).rejects.toThrow('not found');
+  });
+
+  it('saves a user', () => {
+    return saveUser({ id: 2 }).then((saved) => {
+      expect(saved).toBe(true);
+    });
+  });
+
+  it('saves with a callback', (done) => {
+    saveUser({ id: 3 }).then(() => done());
+  });
+
+  it('validates ids synchronously', () => {
+    expect(() => fetchUser('x')).toThrow();
+  });
+});
@@ -0,0 +1,20 @@
+class Cart {
+  constructor() {
+    this.items = new Map();
+  }
+
+  add(name, quantity) {
+    if (quantity < 0) {
+      throw new Error('quantity must not be negative');
+    }
+    this.items.set(name, (this.items.get(name) || 0) + quantity);
+  }
+
+  count() {
+    let n = 0;
+    for (const q of this.items.values()) n += q;
+    return n;
+  }
+}
+
+module.exports = { Cart };
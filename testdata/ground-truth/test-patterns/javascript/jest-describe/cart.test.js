@@ -0,0 +1,30 @@
+const { Cart } = require('./cart');
+
+describe('Cart', () => {
+  let cart;
+
+  beforeEach(() => {
+    cart = new Cart();
+  });
+
+  describe('add', () => {
+    it('adds an item', () => {
+      cart.add('apple', 2);
+      expect(cart.count()).toBe(2);
+    });
+
+    it('rejects negative quantities', () => {
+      expect(() => cart.add('apple', -1)).toThrow();
+    });
+  });
+
+  test('starts empty', () => {
+    expect(cart.count()).toBe(0);
+  });
+
+  it.todo('applies coupons');
+});
+
+test('creates independent carts', () => {
+  expect(new Cart()).not.toBe(new Cart());
+});
@@ -0,0 +1,10 @@
+def discount(amount):
+    if amount >= 500:
+        return 75
+    if amount >= 100:
+        return 10
+    return 0
+
+
+def total(items):
+    return sum(items)
@@ -0,0 +1,30 @@
+import pytest
+
+from pricing import discount, total
+
+
+@pytest.mark.parametrize(
+    "amount,expected",
+    [
+        (50, 0),
+        (100, 10),
+        (500, 75),
+    ],
+)
+def test_discount(amount, expected):
+    assert discount(amount) == expected
+
+
+@pytest.mark.parametrize("items", [[], [1], [1, 2, 3]])
+def test_total_is_never_negative(items):
+    assert total(items) >= 0
+
+
+def test_total_of_empty_cart():
+    assert total([]) == 0
+
+
+@pytest.mark.parametrize("currency", ["EUR", "USD"])
+class TestFormatting:
+    def test_symbol(self, currency):
+        assert currency in ("EUR", "USD")
@@ -0,0 +1,10 @@
+import pytest
+
+from store import Store
+
+
+@pytest.fixture
+def store():
+    s = Store()
+    yield s
+    s.close()
@@ -0,0 +1,13 @@
+class Store:
+    def __init__(self, path=None):
+        self.path = path
+        self.items = {}
+
+    def put(self, key, value):
+        self.items[key] = value
+
+    def get(self, key):
+        return self.items.get(key)
+
+    def close(self):
+        self.items.clear()
@@ -0,0 +1,29 @@
+import unittest
+
+from store import Store
+
+
+def test_put_and_get(store):
+    store.put("a", 1)
+    assert store.get("a") == 1
+
+
+def test_persists_to_path(tmp_path):
+    s = Store(tmp_path / "db")
+    assert s.path.name == "db"
+
+
+def test_missing_key():
+    s = Store()
+    assert s.get("missing") is None
+    s.close()
+
+
+class StoreTest(unittest.TestCase):
+    def setUp(self):
+        self.store = Store()
+
+    def test_overwrite(self):
+        self.store.put("a", 1)
+        self.store.put("a", 2)
+        self.assertEqual(self.store.get("a"), 2)
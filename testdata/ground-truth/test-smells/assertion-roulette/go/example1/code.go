@@ -0,0 +1,15 @@
+package orders
+
+// Order is a customer order.
+type Order struct {
+	ID       string
+	Customer string
+	Items    []string
+	Total    int
+	Paid     bool
+}
+
+// New creates an unpaid order.
+func New(id, customer string, items []string, total int) *Order {
+	return &Order{ID: id, Customer: customer, Items: items, Total: total}
+}
@@ -0,0 +1,25 @@
+package orders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOrder(t *testing.T) {
+	o := New("o-1", "alice", []string{"book", "pen"}, 1250)
+
+	assert.Equal(t, "o-1", o.ID)
+	assert.Equal(t, "alice", o.Customer)
+	assert.Len(t, o.Items, 2)
+	assert.Equal(t, 1250, o.Total)
+	assert.False(t, o.Paid)
+}
+
+func TestNewOrderIsUnpaid(t *testing.T) {
+	o := New("o-2", "bob", nil, 0)
+
+	assert.False(t, o.Paid, "new orders are unpaid")
+	assert.Empty(t, o.Items, "no items were added")
+	assert.Zero(t, o.Total, "empty orders cost nothing")
+}
@@ -0,0 +1,17 @@
+import unittest
+
+from users import create_user
+
+
+class TestUser(unittest.TestCase):
+    def test_user_fields(self):
+        user = create_user("alice")
+        self.assertEqual(user.name, "alice")
+        self.assertTrue(user.active)
+        self.assertIsNotNone(user.created)
+
+    def test_user_fields_with_messages(self):
+        user = create_user("bob")
+        self.assertEqual(user.name, "bob", "name is kept")
+        self.assertTrue(user.active, "new users are active")
+        self.assertIsNotNone(user.created, "creation time is recorded")
@@ -0,0 +1,12 @@
+import datetime
+
+
+class User:
+    def __init__(self, name):
+        self.name = name
+        self.active = True
+        self.created = datetime.datetime.now()
+
+
+def create_user(name):
+    return User(name)
@@ -0,0 +1,51 @@
+package inventory
+
+import "errors"
+
+// Item is a stocked product.
+type Item struct {
+	SKU      string
+	Quantity int
+}
+
+// Store keeps items by SKU.
+type Store struct {
+	items map[string]*Item
+}
+
+// NewStore creates an empty store.
+func NewStore() *Store {
+	return &Store{items: map[string]*Item{}}
+}
+
+// Add stocks quantity units of a SKU.
+func (s *Store) Add(sku string, quantity int) {
+	item, ok := s.items[sku]
+	if !ok {
+		item = &Item{SKU: sku}
+		s.items[sku] = item
+	}
+
+	item.Quantity += quantity
+}
+
+// Remove takes quantity units of a SKU out of stock.
+func (s *Store) Remove(sku string, quantity int) error {
+	item, ok := s.items[sku]
+	if !ok || item.Quantity < quantity {
+		return errors.New("insufficient stock")
+	}
+
+	item.Quantity -= quantity
+
+	return nil
+}
+
+// Quantity returns the stock of a SKU.
+func (s *Store) Quantity(sku string) int {
+	if item, ok := s.items[sku]; ok {
+		return item.Quantity
+	}
+
+	return 0
+}
@@ -0,0 +1,37 @@
+package inventory
+
+import "testing"
+
+func TestRemoveBooks(t *testing.T) {
+	s := NewStore()
+	s.Add("book", 10)
+
+	if err := s.Remove("book", 3); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if got := s.Quantity("book"); got != 7 {
+		t.Errorf("Quantity() = %d, want %d", got, 7)
+	}
+
+	if err := s.Remove("book", 20); err == nil {
+		t.Error("Remove() succeeded beyond the stock")
+	}
+}
+
+func TestRemovePens(t *testing.T) {
+	store := NewStore()
+	store.Add("pen", 5)
+
+	if err := store.Remove("pen", 2); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if got := store.Quantity("pen"); got != 3 {
+		t.Errorf("Quantity() = %d, want %d", got, 3)
+	}
+
+	if err := store.Remove("pen", 10); err == nil {
+		t.Error("Remove() succeeded beyond the stock")
+	}
+}
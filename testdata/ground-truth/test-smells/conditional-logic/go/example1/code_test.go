@@ -0,0 +1,17 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscount(t *testing.T) {
+	price := Price("vip")
+
+	if price > 100 {
+		assert.Equal(t, 180, Discount(price), "vip discount")
+	} else {
+		assert.Equal(t, price, Discount(price), "no discount")
+	}
+}
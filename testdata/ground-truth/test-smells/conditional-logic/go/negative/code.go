@@ -0,0 +1,19 @@
+package pricing
+
+// Price returns the list price of a plan.
+func Price(plan string) int {
+	if plan == "vip" {
+		return 200
+	}
+
+	return 50
+}
+
+// Discount applies the VIP discount to prices over 100.
+func Discount(price int) int {
+	if price > 100 {
+		return price * 9 / 10
+	}
+
+	return price
+}
@@ -0,0 +1,15 @@
+package pricing
+
+import "testing"
+
+func TestDiscountForVIP(t *testing.T) {
+	if got := Discount(Price("vip")); got != 180 {
+		t.Errorf("Discount() = %d, want 180", got)
+	}
+}
+
+func TestDiscountForBasic(t *testing.T) {
+	if got := Discount(Price("basic")); got != 50 {
+		t.Errorf("Discount() = %d, want 50", got)
+	}
+}
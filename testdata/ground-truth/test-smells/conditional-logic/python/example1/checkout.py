@@ -0,0 +1,11 @@
+class User:
+    def __init__(self, name):
+        self.vip = name == "vip"
+
+
+def create_user(name):
+    return User(name)
+
+
+def discount(user):
+    return 10 if user.vip else 0
@@ -0,0 +1,16 @@
+import pytest
+
+from checkout import create_user, discount
+
+
+def test_discount():
+    user = create_user("vip")
+    if user.vip:
+        assert discount(user) == 10
+    else:
+        assert discount(user) == 0
+
+
+@pytest.mark.parametrize("name,want", [("vip", 10), ("basic", 0)])
+def test_discount_by_plan(name, want):
+    assert discount(create_user(name)) == want
@@ -0,0 +1,41 @@
+package accounts
+
+import "errors"
+
+// Account is a user account.
+type Account struct {
+	Name   string
+	Active bool
+}
+
+var accounts = map[string]*Account{}
+
+// Create registers an account.
+func Create(name string) *Account {
+	a := &Account{Name: name, Active: true}
+	accounts[name] = a
+
+	return a
+}
+
+// Deactivate disables an account.
+func Deactivate(a *Account) error {
+	if a == nil {
+		return errors.New("no account")
+	}
+
+	a.Active = false
+
+	return nil
+}
+
+// Delete removes an account.
+func Delete(a *Account) error {
+	delete(accounts, a.Name)
+	return nil
+}
+
+// Find looks up an account by name.
+func Find(name string) *Account {
+	return accounts[name]
+}
@@ -0,0 +1,24 @@
+package accounts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountLifecycle(t *testing.T) {
+	a := Create("alice")
+	assert.NotNil(t, a, "created")
+	assert.True(t, a.Active, "active after creation")
+
+	assert.NoError(t, Deactivate(a), "deactivated")
+	assert.False(t, a.Active, "inactive after deactivation")
+
+	assert.NoError(t, Delete(a), "deleted")
+	assert.Nil(t, Find("alice"), "gone after deletion")
+}
+
+func TestCreateActivatesAccount(t *testing.T) {
+	a := Create("bob")
+	assert.True(t, a.Active, "active after creation")
+}
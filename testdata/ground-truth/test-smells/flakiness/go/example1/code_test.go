@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheExpiry(t *testing.T) {
+	c := New(time.Millisecond)
+	c.Put("a")
+
+	time.Sleep(10 * time.Millisecond)
+
+	if c.Len() != 0 {
+		t.Error("entry did not expire")
+	}
+}
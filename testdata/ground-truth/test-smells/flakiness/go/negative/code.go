@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is a map whose entries expire.
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]time.Time
+}
+
+// New creates a cache with the given time to live.
+func New(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, entries: map[string]time.Time{}}
+}
+
+// Put stores a key.
+func (c *Cache) Put(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = time.Now()
+}
+
+// Len counts the entries that have not expired.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, at := range c.entries {
+		if time.Since(at) < c.ttl {
+			n++
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentPuts(t *testing.T) {
+	c := New(time.Hour)
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"a", "b", "c"} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			c.Put(key)
+		}()
+	}
+
+	wg.Wait()
+
+	if got := c.Len(); got != 3 {
+		t.Errorf("Len() = %d, want 3", got)
+	}
+}
@@ -0,0 +1,9 @@
+function createCart(ttl = 100) {
+  const cart = { expired: false };
+  setTimeout(() => {
+    cart.expired = true;
+  }, ttl);
+  return cart;
+}
+
+module.exports = { createCart };
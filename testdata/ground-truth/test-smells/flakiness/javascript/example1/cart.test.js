@@ -0,0 +1,9 @@
+const { createCart } = require('./cart');
+
+describe('cart', () => {
+  it('expires after its ttl', async () => {
+    const cart = createCart(100);
+    await new Promise((resolve) => setTimeout(resolve, 500));
+    expect(cart.expired).toBe(true);
+  });
+});
@@ -0,0 +1,11 @@
+const { createCart } = require('./cart');
+
+jest.useFakeTimers();
+
+describe('cart', () => {
+  it('expires after its ttl', () => {
+    const cart = createCart(100);
+    jest.advanceTimersByTime(100);
+    expect(cart.expired).toBe(true);
+  });
+});
@@ -0,0 +1,11 @@
+import time
+
+
+class Cart:
+    def __init__(self, ttl):
+        self.created = time.monotonic()
+        self.ttl = ttl
+
+    @property
+    def expired(self):
+        return time.monotonic() - self.created > self.ttl
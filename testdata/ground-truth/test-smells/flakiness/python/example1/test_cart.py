@@ -0,0 +1,13 @@
+import time
+
+from cart import Cart
+
+
+def test_cart_expires():
+    cart = Cart(ttl=1)
+    time.sleep(2)
+    assert cart.expired
+
+
+def test_new_cart_is_not_expired():
+    assert not Cart(ttl=60).expired
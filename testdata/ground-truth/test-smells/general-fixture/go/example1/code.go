@@ -0,0 +1,31 @@
+package billing
+
+// Customer is a billed customer.
+type Customer struct {
+	Name    string
+	Country string
+}
+
+// Invoice is an amount owed by a customer.
+type Invoice struct {
+	Customer *Customer
+	Amount   int
+}
+
+// Tax returns the tax owed on an invoice.
+func Tax(inv *Invoice) int {
+	if inv.Customer.Country == "DE" {
+		return inv.Amount * 19 / 100
+	}
+
+	return 0
+}
+
+// Currency returns the currency of a country.
+func Currency(country string) string {
+	if country == "US" {
+		return "USD"
+	}
+
+	return "EUR"
+}
@@ -0,0 +1,34 @@
+package billing
+
+import (
+	"os"
+	"testing"
+)
+
+var (
+	customer *Customer
+	invoice  *Invoice
+	rates    map[string]int
+	country  string
+)
+
+func TestMain(m *testing.M) {
+	customer = &Customer{Name: "alice", Country: "DE"}
+	invoice = &Invoice{Customer: customer, Amount: 1000}
+	rates = map[string]int{"DE": 19, "US": 0}
+	country = "US"
+
+	os.Exit(m.Run())
+}
+
+func TestTaxInGermany(t *testing.T) {
+	if got := Tax(invoice); got != 190 {
+		t.Errorf("Tax() = %d, want 190", got)
+	}
+}
+
+func TestCurrencyInUS(t *testing.T) {
+	if got := Currency(country); got != "USD" {
+		t.Errorf("Currency() = %q, want USD", got)
+	}
+}
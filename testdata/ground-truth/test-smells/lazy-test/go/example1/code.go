@@ -0,0 +1,25 @@
+package units
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseSize converts sizes such as "10k" to bytes.
+func ParseSize(s string) int {
+	multiplier := 1
+
+	switch {
+	case strings.HasSuffix(s, "k"):
+		multiplier, s = 1024, strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		multiplier, s = 1024*1024, strings.TrimSuffix(s, "m")
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return -1
+	}
+
+	return n * multiplier
+}
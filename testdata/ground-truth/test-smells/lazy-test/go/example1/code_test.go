@@ -0,0 +1,21 @@
+package units
+
+import "testing"
+
+func TestParseSize(t *testing.T) {
+	if got := ParseSize("10"); got != 10 {
+		t.Errorf("ParseSize(10) = %d", got)
+	}
+
+	if got := ParseSize("2k"); got != 2048 {
+		t.Errorf("ParseSize(2k) = %d", got)
+	}
+
+	if got := ParseSize("1m"); got != 1048576 {
+		t.Errorf("ParseSize(1m) = %d", got)
+	}
+
+	if got := ParseSize("x"); got != -1 {
+		t.Errorf("ParseSize(x) = %d", got)
+	}
+}
@@ -0,0 +1,7 @@
+const { parse } = require('./parse');
+
+it('parses', () => {
+  expect(parse('1')).toBe(1);
+  expect(parse('2')).toBe(2);
+  expect(parse('3')).toBe(3);
+});
@@ -0,0 +1,5 @@
+function parse(text) {
+  return Number.parseInt(text, 10);
+}
+
+module.exports = { parse };
@@ -0,0 +1,9 @@
+const { parse } = require('./parse');
+
+test.each([
+  ['1', 1],
+  ['2', 2],
+  ['3', 3],
+])('parses %s', (text, want) => {
+  expect(parse(text)).toBe(want);
+});
@@ -0,0 +1,25 @@
+package users
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// User is a registered user.
+type User struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// Load reads users from a JSON file.
+func Load(path string) ([]User, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []User
+	err = json.Unmarshal(data, &users)
+
+	return users, err
+}
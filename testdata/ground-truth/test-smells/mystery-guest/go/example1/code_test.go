@@ -0,0 +1,28 @@
+package users
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadUsers(t *testing.T) {
+	data, err := os.ReadFile("users.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) == 0 {
+		t.Error("expected users")
+	}
+}
+
+func TestLoadUsersFromDatabaseExport(t *testing.T) {
+	users, err := Load(os.Getenv("USERS_EXPORT"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(users) == 0 {
+		t.Error("expected users")
+	}
+}
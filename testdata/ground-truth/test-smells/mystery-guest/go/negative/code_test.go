@@ -0,0 +1,36 @@
+package users
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadUsersFromTestdata(t *testing.T) {
+	users, err := Load("testdata/users.json")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(users) != 2 {
+		t.Errorf("got %d users, want 2", len(users))
+	}
+}
+
+func TestLoadUsersFromExport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.json")
+	if err := os.WriteFile(path, []byte(`[{"name":"alice"}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("USERS_EXPORT", path)
+
+	users, err := Load(os.Getenv("USERS_EXPORT"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(users) != 1 {
+		t.Errorf("got %d users, want 1", len(users))
+	}
+}
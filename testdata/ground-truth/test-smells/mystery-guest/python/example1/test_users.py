@@ -0,0 +1,19 @@
+import json
+import os
+
+from users import load_users
+
+
+def test_load_users():
+    with open("users.json") as fh:
+        assert json.load(fh)
+
+
+def test_database_url():
+    assert os.environ["DATABASE_URL"].startswith("postgres")
+
+
+def test_load_users_from_tmp_path(tmp_path):
+    path = tmp_path / "users.json"
+    path.write_text('[{"name": "alice"}]')
+    assert load_users(path) == [{"name": "alice"}]
@@ -0,0 +1,6 @@
+import json
+
+
+def load_users(path):
+    with open(path) as fh:
+        return json.load(fh)
@@ -0,0 +1,15 @@
+package pricing
+
+import "testing"
+
+func TestFoo(t *testing.T) {
+	if Discount(200) != 180 {
+		t.Error("discount")
+	}
+}
+
+func TestDiscountLeavesSmallPricesUnchanged(t *testing.T) {
+	if got := Discount(80); got != 80 {
+		t.Errorf("Discount(80) = %d, want 80", got)
+	}
+}
@@ -0,0 +1,16 @@
+package server
+
+import (
+	"net"
+	"net/http"
+)
+
+// Serve serves health checks on the listener.
+func Serve(ln net.Listener) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return http.Serve(ln, mux)
+}
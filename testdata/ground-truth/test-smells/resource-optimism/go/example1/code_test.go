@@ -0,0 +1,27 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"testing"
+)
+
+func TestServeHealthz(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() { _ = Serve(ln) }()
+
+	resp, err := http.Get("http://127.0.0.1:8080/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+}
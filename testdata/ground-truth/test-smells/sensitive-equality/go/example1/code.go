@@ -0,0 +1,20 @@
+package store
+
+import "fmt"
+
+// NotFoundError reports a missing key.
+type NotFoundError struct {
+	Key string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("store: %s not found", e.Key)
+}
+
+// Open opens the store at path.
+func Open(path string) (*Store, error) {
+	return nil, &NotFoundError{Key: path}
+}
+
+// Store is a key-value store.
+type Store struct{}
@@ -0,0 +1,20 @@
+package store
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenMissing(t *testing.T) {
+	_, err := Open("missing")
+	assert.Equal(t, "store: missing not found", err.Error(), "error text")
+}
+
+func TestOpenMissingReturnsNotFound(t *testing.T) {
+	_, err := Open("missing")
+
+	var nf *NotFoundError
+	assert.True(t, errors.As(err, &nf), "error type")
+}
@@ -0,0 +1,5 @@
+function createUser(name) {
+  return { name, roles: [] };
+}
+
+module.exports = { createUser };
@@ -0,0 +1,9 @@
+const { createUser } = require('./user');
+
+test('serializes the user', () => {
+  expect(JSON.stringify(createUser('alice'))).toBe('{"name":"alice","roles":[]}');
+});
+
+test('creates users without roles', () => {
+  expect(createUser('bob')).toEqual({ name: 'bob', roles: [] });
+});